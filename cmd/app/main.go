@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/app"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/judge"
 
 	"github.com/spf13/cobra"
 
@@ -19,6 +20,11 @@ var rootCmd = &cobra.Command{
 }
 
 func main() {
+	// The judge re-executes this binary to run solutions, see judge.SandboxHelperCommand.
+	if len(os.Args) > 1 && os.Args[1] == judge.SandboxHelperCommand {
+		os.Exit(judge.RunSandboxHelper(os.Args[2:]))
+	}
+
 	err := rootCmd.Execute()
 	if err != nil {
 		os.Exit(1)
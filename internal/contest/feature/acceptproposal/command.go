@@ -0,0 +1,8 @@
+package acceptproposal
+
+import "github.com/google/uuid"
+
+type Command struct {
+	ContestID uuid.UUID `param:"contest_id" validate:"required"`
+	ProblemID uuid.UUID `param:"problem_id" validate:"required"`
+}
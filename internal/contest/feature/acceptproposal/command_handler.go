@@ -0,0 +1,71 @@
+package acceptproposal
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/assignproblem"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+var ErrProposalNotFound = errors.New("proposal not found")
+
+type Repository interface {
+	IsOpenProposal(ctx context.Context, contestID uuid.UUID, problemID uuid.UUID) (bool, error)
+}
+
+// CommandHandler accepts a problem proposed for a contest by assigning it,
+// so the contest's limits and lifecycle are checked exactly as they are for
+// problems assigned directly.
+type CommandHandler struct {
+	repo          Repository
+	assignHandler *assignproblem.CommandHandler
+	validator     *validator.Validate
+	authProvider  contract.AuthProvider
+}
+
+func NewCommandHandler(
+	repo Repository,
+	assignHandler *assignproblem.CommandHandler,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+) *CommandHandler {
+	return &CommandHandler{
+		repo:          repo,
+		assignHandler: assignHandler,
+		validator:     validator,
+		authProvider:  authProvider,
+	}
+}
+
+func (h *CommandHandler) Handle(ctx context.Context, command *Command) error {
+	if command == nil {
+		return errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(command); err != nil {
+		return errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	if can, err := h.authProvider.Can(ctx, constant.PermissionContestAssignProblemAny); err != nil {
+		return errors.WrapIf(err, "failed to check permission")
+	} else if !can {
+		return customerror.NewNoPermissionError(constant.PermissionContestAssignProblemAny)
+	}
+
+	if ok, err := h.repo.IsOpenProposal(ctx, command.ContestID, command.ProblemID); err != nil {
+		return errors.WrapIf(err, "failed to check proposal")
+	} else if !ok {
+		return errors.WithStack(ErrProposalNotFound)
+	}
+
+	return h.assignHandler.Handle(ctx, &assignproblem.Command{
+		ContestID: command.ContestID,
+		ProblemID: command.ProblemID,
+	})
+}
@@ -0,0 +1,39 @@
+package acceptproposal
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) IsOpenProposal(ctx context.Context, contestID uuid.UUID, problemID uuid.UUID) (bool, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var count int64
+	if err := db.WithContext(ctx).
+		Model(&database.Problem{}).
+		Where("problem_id = ? AND target_contest_id = ?", problemID, contestID).
+		Where(
+			"NOT EXISTS (SELECT 1 FROM contest_problems cp WHERE cp.problem_id = problems.problem_id AND cp.contest_id = ?)",
+			contestID,
+		).
+		Count(&count).Error; err != nil {
+		return false, errors.WrapIf(err, "failed to check if problem is proposed for the contest")
+	}
+
+	return count > 0, nil
+}
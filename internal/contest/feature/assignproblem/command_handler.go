@@ -2,7 +2,10 @@ package assignproblem
 
 import (
 	"context"
+	"time"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
@@ -14,16 +17,25 @@ import (
 )
 
 var (
-	ErrProblemNotFound = errors.New("problem not found")
-	ErrContestNotFound = errors.New("contest not found")
-	ErrTooManyProblems = errors.New("too many problems")
+	ErrProblemNotFound        = errors.New("problem not found")
+	ErrContestNotFound        = errors.New("contest not found")
+	ErrProblemAlreadyAssigned = errors.New("problem is already assigned to the contest")
+	ErrTooManyProblems        = errors.New("too many problems")
+	ErrExceedsLimits          = errors.New("problem limits exceed contest maximums")
 )
 
 type Repository interface {
-	DoesContestExist(ctx context.Context, contestID uuid.UUID) (bool, error)
+	// LockContest serializes the changes to the contest's problem set and
+	// reports whether the contest exists.
+	LockContest(ctx context.Context, contestID uuid.UUID) (bool, error)
+	GetContestLifecycle(ctx context.Context, contestID uuid.UUID) (constant.ContestPhase, time.Time, error)
 	DoesProblemExist(ctx context.Context, problemID uuid.UUID) (bool, error)
-	AssignProblemToContest(ctx context.Context, problemID uuid.UUID, contestID uuid.UUID) error
-	IsContestAlmostMaxedOut(ctx context.Context, contestID uuid.UUID) (bool, error)
+	IsProblemAssigned(ctx context.Context, problemID uuid.UUID, contestID uuid.UUID) (bool, error)
+	// AssignProblemToContest appends the problem to the end of the contest's
+	// problem set.
+	AssignProblemToContest(ctx context.Context, problemID uuid.UUID, contestID uuid.UUID, assignedAt time.Time) error
+	IsContestFull(ctx context.Context, contestID uuid.UUID) (bool, error)
+	ExceedsContestLimits(ctx context.Context, problemID uuid.UUID, contestID uuid.UUID) (bool, error)
 }
 
 type CommandHandler struct {
@@ -31,6 +43,7 @@ type CommandHandler struct {
 	validator    *validator.Validate
 	authProvider contract.AuthProvider
 	uowFactory   contract.UnitOfWorkFactory
+	broadcaster  contract.MessageBroadcaster
 	l            logger.Logger
 }
 
@@ -39,6 +52,7 @@ func NewCommandHandler(
 	validator *validator.Validate,
 	authProvider contract.AuthProvider,
 	uowFactory contract.UnitOfWorkFactory,
+	broadcaster contract.MessageBroadcaster,
 	l logger.Logger,
 ) *CommandHandler {
 	return &CommandHandler{
@@ -46,6 +60,7 @@ func NewCommandHandler(
 		validator:    validator,
 		authProvider: authProvider,
 		uowFactory:   uowFactory,
+		broadcaster:  broadcaster,
 		l:            l,
 	}
 }
@@ -59,37 +74,73 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) error {
 		return errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
 	}
 
-	_, err := h.authProvider.MustGetUser(ctx)
+	user, err := h.authProvider.MustGetUser(ctx)
 	if err != nil {
 		return errors.WrapIf(err, "failed to get user from auth provider")
 	}
 
-	// TODO: check if the current user has permissions or not
+	if can, err := h.authProvider.CanInContest(ctx, command.ContestID, constant.PermissionContestAssignProblemAny); err != nil {
+		return errors.WrapIf(err, "failed to check permission")
+	} else if !can {
+		return customerror.NewNoPermissionError(constant.PermissionContestAssignProblemAny)
+	}
 
 	uow := h.uowFactory.New()
 	return uowhelper.Do(ctx, uow, h.l, func(ctx context.Context) error {
-		if ok, err := h.repo.DoesContestExist(ctx, command.ContestID); err != nil {
-			return errors.WrapIf(err, "failed to check if contest exists")
+		if ok, err := h.repo.LockContest(ctx, command.ContestID); err != nil {
+			return errors.WrapIf(err, "failed to lock contest")
 		} else if !ok {
 			return errors.WithStack(ErrContestNotFound)
 		}
 
+		if phase, deadline, err := h.repo.GetContestLifecycle(ctx, command.ContestID); err != nil {
+			return errors.WrapIf(err, "failed to get contest lifecycle")
+		} else if contest.IsProblemSetLocked(phase, deadline, time.Now()) {
+			return errors.WithStack(contest.ErrProblemSetLocked)
+		}
+
 		if ok, err := h.repo.DoesProblemExist(ctx, command.ProblemID); err != nil {
 			return errors.WrapIf(err, "failed to check if problem exists")
 		} else if !ok {
 			return errors.WithStack(ErrProblemNotFound)
 		}
 
-		if notOk, err := h.repo.IsContestAlmostMaxedOut(ctx, command.ContestID); err != nil {
+		if assigned, err := h.repo.IsProblemAssigned(ctx, command.ProblemID, command.ContestID); err != nil {
+			return errors.WrapIf(err, "failed to check if problem is assigned")
+		} else if assigned {
+			return errors.WithStack(ErrProblemAlreadyAssigned)
+		}
+
+		if full, err := h.repo.IsContestFull(ctx, command.ContestID); err != nil {
 			return errors.WrapIf(err, "failed to get contest problem count")
-		} else if notOk {
+		} else if full {
 			return errors.WithStack(ErrTooManyProblems)
 		}
 
-		if err := h.repo.AssignProblemToContest(ctx, command.ProblemID, command.ContestID); err != nil {
+		if exceeds, err := h.repo.ExceedsContestLimits(ctx, command.ProblemID, command.ContestID); err != nil {
+			return errors.WrapIf(err, "failed to check contest limits")
+		} else if exceeds {
+			return errors.WithStack(ErrExceedsLimits)
+		}
+
+		timestamp := time.Now()
+
+		if err := h.repo.AssignProblemToContest(ctx, command.ProblemID, command.ContestID, timestamp); err != nil {
 			return errors.WrapIf(err, "failed to assign problem to contest")
 		}
 
+		details, err := h.authProvider.MustGetUserDetails(ctx, user.UserID)
+		if err != nil {
+			return errors.WrapIf(err, "failed to get user details")
+		}
+
+		if err := h.broadcaster.BroadcastProblemAssignedMessage(ctx, command.ContestID, command.ProblemID, contract.MessageUser{
+			UserID:   user.UserID,
+			Username: details.Username,
+		}, timestamp); err != nil {
+			return errors.WrapIf(err, "failed to broadcast problem assigned message")
+		}
+
 		return nil
 	})
 }
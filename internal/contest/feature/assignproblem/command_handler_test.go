@@ -0,0 +1,123 @@
+package assignproblem
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/contracttest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger/defaultlogger"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+type fakeRepository struct {
+	assigned bool
+}
+
+func (r *fakeRepository) LockContest(context.Context, uuid.UUID) (bool, error) { return true, nil }
+
+func (r *fakeRepository) GetContestLifecycle(context.Context, uuid.UUID) (constant.ContestPhase, time.Time, error) {
+	return constant.ContestPhaseProposal, time.Time{}, nil
+}
+
+func (r *fakeRepository) DoesProblemExist(context.Context, uuid.UUID) (bool, error) { return true, nil }
+
+func (r *fakeRepository) IsProblemAssigned(context.Context, uuid.UUID, uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeRepository) AssignProblemToContest(context.Context, uuid.UUID, uuid.UUID, time.Time) error {
+	r.assigned = true
+	return nil
+}
+
+func (r *fakeRepository) IsContestFull(context.Context, uuid.UUID) (bool, error) { return false, nil }
+
+func (r *fakeRepository) ExceedsContestLimits(context.Context, uuid.UUID, uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+type fakeUnitOfWork struct{}
+
+func (u fakeUnitOfWork) Begin(ctx context.Context) (context.Context, error) { return ctx, nil }
+
+func (u fakeUnitOfWork) Commit() error { return nil }
+
+func (u fakeUnitOfWork) Rollback() error { return nil }
+
+func (u fakeUnitOfWork) New() contract.UnitOfWork { return u }
+
+type fakeBroadcaster struct {
+	contract.MessageBroadcaster
+}
+
+func (fakeBroadcaster) BroadcastProblemAssignedMessage(
+	context.Context,
+	uuid.UUID,
+	uuid.UUID,
+	contract.MessageUser,
+	time.Time,
+) error {
+	return nil
+}
+
+func TestHandleChecksPermission(t *testing.T) {
+	contestID := uuid.New()
+
+	tests := []struct {
+		name               string
+		permissions        []string
+		contestPermissions map[uuid.UUID][]string
+		wantAllowed        bool
+	}{
+		{name: "no permission", wantAllowed: false},
+		{name: "global permission", permissions: []string{constant.PermissionContestAssignProblemAny}, wantAllowed: true},
+		{
+			name:               "coordinator of the contest",
+			contestPermissions: map[uuid.UUID][]string{contestID: {constant.PermissionContestAssignProblemAny}},
+			wantAllowed:        true,
+		},
+		{
+			name:               "coordinator of another contest",
+			contestPermissions: map[uuid.UUID][]string{uuid.New(): {constant.PermissionContestAssignProblemAny}},
+			wantAllowed:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			authProvider := contracttest.NewAuthProvider(userID, &contract.AuthUserDetails{Permissions: tt.permissions})
+			for id, permissions := range tt.contestPermissions {
+				authProvider.GrantInContest(userID, id, permissions...)
+			}
+
+			repo := &fakeRepository{}
+			handler := NewCommandHandler(
+				repo,
+				validator.New(),
+				authProvider,
+				fakeUnitOfWork{},
+				fakeBroadcaster{},
+				defaultlogger.GetLogger(),
+			)
+
+			err := handler.Handle(context.Background(), &Command{ContestID: contestID, ProblemID: uuid.New()})
+			if tt.wantAllowed && err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			} else if !tt.wantAllowed && !errors.Is(err, customerror.ErrBaseNoPermission) {
+				t.Fatalf("Handle() error = %v, want no permission", err)
+			}
+
+			if repo.assigned != tt.wantAllowed {
+				t.Errorf("assigned = %v, want %v", repo.assigned, tt.wantAllowed)
+			}
+		})
+	}
+}
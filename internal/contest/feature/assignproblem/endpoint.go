@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/httperror"
 
 	"emperror.dev/errors"
@@ -38,12 +39,22 @@ func (e *Endpoint) handle() echo.HandlerFunc {
 		}
 
 		err := e.handler.Handle(ctx.Request().Context(), command)
-		if errors.Is(err, ErrProblemNotFound) {
+		if errors.Is(err, customerror.ErrBaseNoPermission) ||
+			errors.Is(err, customerror.ErrCommandNil) ||
+			errors.Is(err, customerror.ErrValidationFailed) {
+			return err
+		} else if errors.Is(err, ErrProblemNotFound) {
 			return httperror.New(http.StatusUnprocessableEntity, "The problem does not exist")
 		} else if errors.Is(err, ErrContestNotFound) {
 			return httperror.New(http.StatusNotFound, "The contest does not exist")
+		} else if errors.Is(err, contest.ErrProblemSetLocked) {
+			return httperror.New(http.StatusConflict, "The contest is frozen or past its deadline")
+		} else if errors.Is(err, ErrProblemAlreadyAssigned) {
+			return httperror.New(http.StatusConflict, "The problem is already assigned to this contest")
 		} else if errors.Is(err, ErrTooManyProblems) {
 			return httperror.New(http.StatusUnprocessableEntity, "The contest already has enough problems")
+		} else if errors.Is(err, ErrExceedsLimits) {
+			return httperror.New(http.StatusUnprocessableEntity, "The problem's time or memory limit exceeds the maximum allowed by the contest")
 		} else if err != nil {
 			return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
 		}
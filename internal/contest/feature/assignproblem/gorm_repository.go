@@ -2,12 +2,16 @@ package assignproblem
 
 import (
 	"context"
+	"time"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
 
 	"emperror.dev/errors"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type GormRepository struct {
@@ -18,18 +22,39 @@ func NewGormRepository(db *gorm.DB) *GormRepository {
 	return &GormRepository{db: db}
 }
 
-func (r *GormRepository) DoesContestExist(ctx context.Context, contestID uuid.UUID) (bool, error) {
+// LockContest locks the row of the contest until the unit of work ends, so
+// that changes to its problem set run one at a time, and reports whether the
+// contest exists.
+func (r *GormRepository) LockContest(ctx context.Context, contestID uuid.UUID) (bool, error) {
 	db := database.GetDBFromContext(ctx, r.db)
 
-	var count int64
+	var contestIDs []uuid.UUID
 	if err := db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
 		Model(&database.Contest{}).
 		Where("contest_id = ?", contestID).
-		Count(&count).Error; err != nil {
-		return false, errors.WrapIf(err, "failed to check if contest exists")
+		Pluck("contest_id", &contestIDs).Error; err != nil {
+		return false, errors.WrapIf(err, "failed to lock contest")
 	}
 
-	return count > 0, nil
+	return len(contestIDs) > 0, nil
+}
+
+func (r *GormRepository) GetContestLifecycle(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (constant.ContestPhase, time.Time, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var contest database.Contest
+	if err := db.WithContext(ctx).
+		Select("phase", "deadline_datetime").
+		Where("contest_id = ?", contestID).
+		First(&contest).Error; err != nil {
+		return "", time.Time{}, errors.WrapIf(err, "failed to get contest lifecycle")
+	}
+
+	return constant.FromStringToContestPhase(contest.Phase), contest.DeadlineDatetime, nil
 }
 
 func (r *GormRepository) DoesProblemExist(ctx context.Context, problemID uuid.UUID) (bool, error) {
@@ -46,37 +71,106 @@ func (r *GormRepository) DoesProblemExist(ctx context.Context, problemID uuid.UU
 	return count > 0, nil
 }
 
-func (r *GormRepository) IsContestAlmostMaxedOut(ctx context.Context, contestID uuid.UUID) (bool, error) {
+func (r *GormRepository) IsProblemAssigned(
+	ctx context.Context,
+	problemID uuid.UUID,
+	contestID uuid.UUID,
+) (bool, error) {
 	db := database.GetDBFromContext(ctx, r.db)
 
-	type resultModel struct {
-		ProblemCount    uint64 `gorm:"problem_count"`
-		MaxProblemCount uint64 `gorm:"max_problem_count"`
+	var count int64
+	if err := db.WithContext(ctx).
+		Model(&database.ContestProblem{}).
+		Where("problem_id = ? AND contest_id = ?", problemID, contestID).
+		Count(&count).Error; err != nil {
+		return false, errors.WrapIf(err, "failed to check if problem is assigned")
 	}
 
-	var data resultModel
+	return count > 0, nil
+}
+
+func (r *GormRepository) IsContestFull(ctx context.Context, contestID uuid.UUID) (bool, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var contest database.Contest
 	if err := db.WithContext(ctx).
-		Table("contests c").
-		Select("COUNT(p.*) AS problem_count, c.max_problem_count AS max_problem_count").
-		Joins("LEFT JOIN problems p ON p.assigned_contest_id = c.contest_id").
+		Select("max_problem_count").
 		Where("contest_id = ?", contestID).
-		Group("c.contest_id").
-		Find(&data).Error; err != nil {
+		First(&contest).Error; err != nil {
 		return false, errors.WrapIf(err, "failed to get contest data")
 	}
 
-	return data.ProblemCount+1 >= data.MaxProblemCount, nil
+	var count int64
+	if err := db.WithContext(ctx).
+		Model(&database.ContestProblem{}).
+		Where("contest_id = ?", contestID).
+		Count(&count).Error; err != nil {
+		return false, errors.WrapIf(err, "failed to count contest problems")
+	}
+
+	return uint(count) >= contest.MaxProblemCount, nil
 }
 
-func (r *GormRepository) AssignProblemToContest(ctx context.Context, problemID uuid.UUID, contestID uuid.UUID) error {
+func (r *GormRepository) ExceedsContestLimits(ctx context.Context, problemID uuid.UUID, contestID uuid.UUID) (bool, error) {
 	db := database.GetDBFromContext(ctx, r.db)
 
-	problem := database.Problem{ProblemID: problemID}
+	var c database.Contest
+	if err := db.WithContext(ctx).
+		Select("max_time_limit_ms", "max_memory_limit_mb").
+		Where("contest_id = ?", contestID).
+		First(&c).Error; err != nil {
+		return false, errors.WrapIf(err, "failed to get contest limits")
+	}
+
+	limits := contest.Limits{MaxTimeLimitMs: c.MaxTimeLimitMs, MaxMemoryLimitMb: c.MaxMemoryLimitMb}
+	if limits == (contest.Limits{}) {
+		return false, nil
+	}
+
+	var version database.ProblemVersion
+	if err := db.WithContext(ctx).
+		Select("time_limit_ms", "memory_limit_mb").
+		Where("problem_id = ?", problemID).
+		Order("created_at DESC").
+		First(&version).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+
+		return false, errors.WrapIf(err, "failed to get latest problem version limits")
+	}
+
+	return !limits.Allows(version.TimeLimitMs, version.MemoryLimitMb), nil
+}
+
+func (r *GormRepository) AssignProblemToContest(
+	ctx context.Context,
+	problemID uuid.UUID,
+	contestID uuid.UUID,
+	assignedAt time.Time,
+) error {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var lastPosition uint
+	if err := db.WithContext(ctx).
+		Model(&database.ContestProblem{}).
+		Select("COALESCE(MAX(position), 0)").
+		Where("contest_id = ?", contestID).
+		Scan(&lastPosition).Error; err != nil {
+		return errors.WrapIf(err, "failed to get last problem position")
+	}
 
+	position := lastPosition + 1
 	if err := db.WithContext(ctx).
-		Model(&problem).
-		Update("assigned_contest_id", contestID).Error; err != nil {
-		return errors.WrapIf(err, "failed to set assigned contest id")
+		Create(&database.ContestProblem{
+			ContestID: contestID,
+			ProblemID: problemID,
+			Position:  position,
+			Label:     contest.Label(position),
+			CreatedAt: assignedAt,
+			UpdatedAt: assignedAt,
+		}).Error; err != nil {
+		return errors.WrapIf(err, "failed to create contest problem")
 	}
 
 	return nil
@@ -0,0 +1,18 @@
+package clonecontest
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Command clones a contest. Fields left out are copied from the source
+// contest, whose deadline usually has to be moved for the clone.
+type Command struct {
+	ContestID        uuid.UUID  `param:"contest_id" validate:"required"`
+	Title            *string    `json:"title"       validate:"omitempty,min=1"`
+	DeadlineDatetime *time.Time `json:"deadline_datetime"`
+	StartDatetime    *time.Time `json:"start_datetime"`
+	EndDatetime      *time.Time `json:"end_datetime"`
+	IncludeProblems  bool       `json:"include_problems"`
+}
@@ -0,0 +1,173 @@
+package clonecontest
+
+import (
+	"context"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/createcontest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+var ErrContestNotFound = errors.New("contest not found")
+
+// AssignedProblem is a problem assigned to the source contest, as of its latest
+// version.
+type AssignedProblem struct {
+	contest.ProposedProblem
+	ProblemID uuid.UUID
+	HasTarget bool
+}
+
+type Repository interface {
+	GetContest(ctx context.Context, contestID uuid.UUID) (*createcontest.Contest, error)
+	CreateContest(ctx context.Context, contest createcontest.Contest) error
+	// GetAssignedProblems returns the problems in their contest order.
+	GetAssignedProblems(ctx context.Context, contestID uuid.UUID) ([]AssignedProblem, error)
+	// SetTargetContest sets the target contest of the problems that have none.
+	SetTargetContest(ctx context.Context, problemIDs []uuid.UUID, contestID uuid.UUID, updatedAt time.Time) error
+}
+
+type CommandHandler struct {
+	repo         Repository
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+	uowFactory   contract.UnitOfWorkFactory
+	l            logger.Logger
+}
+
+func NewCommandHandler(
+	repo Repository,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+	uowFactory contract.UnitOfWorkFactory,
+	l logger.Logger,
+) *CommandHandler {
+	return &CommandHandler{
+		repo:         repo,
+		validator:    validator,
+		authProvider: authProvider,
+		uowFactory:   uowFactory,
+		l:            l,
+	}
+}
+
+// Handle creates a contest with the title, description, limits and schedule
+// of another one. The clone starts over in the proposal phase. With
+// IncludeProblems, the problems assigned to the source are proposed for the
+// clone; they stay assigned to the source. Problems that are already proposed
+// for a contest, or that could not be proposed for the clone by retargeting
+// them, are left out.
+func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Response, error) {
+	if command == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(command); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	if can, err := h.authProvider.Can(ctx, constant.PermissionContestCreate); err != nil {
+		return nil, errors.WrapIf(err, "failed to check permission")
+	} else if !can {
+		return nil, customerror.NewNoPermissionError(constant.PermissionContestCreate)
+	}
+
+	if can, err := h.authProvider.CanInContest(
+		ctx,
+		command.ContestID,
+		constant.PermissionContestReadDetailsAny,
+	); err != nil {
+		return nil, errors.WrapIf(err, "failed to check permission")
+	} else if !can {
+		return nil, customerror.NewNoPermissionError(constant.PermissionContestReadDetailsAny)
+	}
+
+	uow := h.uowFactory.New()
+	return uowhelper.DoWithResult(ctx, uow, h.l, func(ctx context.Context) (*Response, error) {
+		source, err := h.repo.GetContest(ctx, command.ContestID)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to get contest")
+		} else if source == nil {
+			return nil, errors.WithStack(ErrContestNotFound)
+		}
+
+		title := source.Title
+		if command.Title != nil {
+			title = *command.Title
+		}
+
+		deadline := source.DeadlineDatetime
+		if command.DeadlineDatetime != nil {
+			deadline = *command.DeadlineDatetime
+		}
+
+		start, end := source.StartDatetime, source.EndDatetime
+		if command.StartDatetime != nil {
+			start = command.StartDatetime
+		}
+
+		if command.EndDatetime != nil {
+			end = command.EndDatetime
+		}
+
+		clone, err := createcontest.NewContest(
+			title,
+			source.Description,
+			source.MinProblemCount,
+			source.MaxProblemCount,
+			source.MaxTimeLimitMs,
+			source.MaxMemoryLimitMb,
+			deadline,
+			start,
+			end,
+		)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to create contest")
+		}
+
+		if err := h.repo.CreateContest(ctx, clone); err != nil {
+			return nil, errors.WrapIf(err, "failed to create contest in repository")
+		}
+
+		proposedProblemIDs := make([]uuid.UUID, 0)
+		if command.IncludeProblems {
+			problems, err := h.repo.GetAssignedProblems(ctx, command.ContestID)
+			if err != nil {
+				return nil, errors.WrapIf(err, "failed to get assigned problems")
+			}
+
+			target := contest.ProposalTarget{
+				Phase:            clone.Phase,
+				DeadlineDatetime: clone.DeadlineDatetime,
+				Limits: contest.Limits{
+					MaxTimeLimitMs:   clone.MaxTimeLimitMs,
+					MaxMemoryLimitMb: clone.MaxMemoryLimitMb,
+				},
+			}
+
+			for _, p := range problems {
+				if !p.HasTarget && contest.CheckProposal(p.ProposedProblem, target, clone.CreatedAt) == nil {
+					proposedProblemIDs = append(proposedProblemIDs, p.ProblemID)
+				}
+			}
+
+			if err := h.repo.SetTargetContest(ctx, proposedProblemIDs, clone.ContestID, clone.CreatedAt); err != nil {
+				return nil, errors.WrapIf(err, "failed to propose problems for the clone")
+			}
+		}
+
+		return &Response{
+			ContestID:          clone.ContestID,
+			ProposedProblemIDs: proposedProblemIDs,
+		}, nil
+	})
+}
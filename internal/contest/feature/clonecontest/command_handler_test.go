@@ -0,0 +1,189 @@
+package clonecontest
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/createcontest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/contracttest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger/defaultlogger"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+type fakeRepository struct {
+	source   createcontest.Contest
+	problems []AssignedProblem
+	created  []createcontest.Contest
+	targeted []uuid.UUID
+}
+
+func (r *fakeRepository) GetContest(context.Context, uuid.UUID) (*createcontest.Contest, error) {
+	return &r.source, nil
+}
+
+func (r *fakeRepository) CreateContest(_ context.Context, contest createcontest.Contest) error {
+	r.created = append(r.created, contest)
+	return nil
+}
+
+func (r *fakeRepository) GetAssignedProblems(context.Context, uuid.UUID) ([]AssignedProblem, error) {
+	return r.problems, nil
+}
+
+func (r *fakeRepository) SetTargetContest(_ context.Context, problemIDs []uuid.UUID, _ uuid.UUID, _ time.Time) error {
+	r.targeted = append(r.targeted, problemIDs...)
+	return nil
+}
+
+type fakeUnitOfWork struct{}
+
+func (u fakeUnitOfWork) Begin(ctx context.Context) (context.Context, error) { return ctx, nil }
+
+func (u fakeUnitOfWork) Commit() error { return nil }
+
+func (u fakeUnitOfWork) Rollback() error { return nil }
+
+func (u fakeUnitOfWork) New() contract.UnitOfWork { return u }
+
+func newTestRepository() *fakeRepository {
+	start := time.Now().Add(2 * time.Hour)
+	end := start.Add(5 * time.Hour)
+
+	return &fakeRepository{
+		source: createcontest.Contest{
+			ContestID:        uuid.New(),
+			Title:            "Contest",
+			Description:      "The first edition",
+			MinProblemCount:  1,
+			MaxProblemCount:  4,
+			MaxTimeLimitMs:   2000,
+			MaxMemoryLimitMb: 256,
+			DeadlineDatetime: time.Now().Add(time.Hour),
+			Phase:            constant.ContestPhaseArchived,
+			StartDatetime:    &start,
+			EndDatetime:      &end,
+		},
+	}
+}
+
+func newTestHandler(repo Repository, permissions ...string) *CommandHandler {
+	return NewCommandHandler(
+		repo,
+		validator.New(),
+		contracttest.NewAuthProvider(uuid.New(), &contract.AuthUserDetails{Permissions: permissions}),
+		fakeUnitOfWork{},
+		defaultlogger.GetLogger(),
+	)
+}
+
+func TestHandleCopiesContest(t *testing.T) {
+	repo := newTestRepository()
+	handler := newTestHandler(repo, constant.PermissionContestCreate, constant.PermissionContestReadDetailsAny)
+
+	title := "Contest 2"
+	response, err := handler.Handle(context.Background(), &Command{ContestID: repo.source.ContestID, Title: &title})
+	if err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	if len(repo.created) != 1 {
+		t.Fatalf("created %d contests, want 1", len(repo.created))
+	}
+
+	clone := repo.created[0]
+	if clone.ContestID != response.ContestID || clone.ContestID == repo.source.ContestID {
+		t.Errorf("clone ID = %s, want a new ID returned in the response", clone.ContestID)
+	}
+
+	want := repo.source
+	want.ContestID, want.Title, want.Phase, want.CreatedAt = clone.ContestID, title, constant.ContestPhaseProposal, clone.CreatedAt
+	if clone != want {
+		t.Errorf("clone = %+v, want %+v", clone, want)
+	}
+}
+
+func TestHandleProposesEligibleProblems(t *testing.T) {
+	problem := func(status constant.ProblemStatus, timeLimitMs uint, hasTarget bool) AssignedProblem {
+		return AssignedProblem{
+			ProposedProblem: contest.ProposedProblem{Status: status, TimeLimitMs: timeLimitMs, MemoryLimitMb: 256},
+			ProblemID:       uuid.New(),
+			HasTarget:       hasTarget,
+		}
+	}
+
+	eligible := problem(constant.ProblemStatusCompleted, 1000, false)
+	problems := []AssignedProblem{
+		eligible,
+		problem(constant.ProblemStatusCompleted, 1000, true),
+		problem(constant.ProblemStatusAwaitingFinalCheck, 1000, false),
+		problem(constant.ProblemStatusCompleted, 5000, false),
+	}
+
+	tests := []struct {
+		name            string
+		includeProblems bool
+		want            []uuid.UUID
+	}{
+		{name: "with problems", includeProblems: true, want: []uuid.UUID{eligible.ProblemID}},
+		{name: "without problems", includeProblems: false, want: []uuid.UUID{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepository()
+			repo.problems = problems
+			handler := newTestHandler(repo, constant.PermissionContestCreate, constant.PermissionContestReadDetailsAny)
+
+			response, err := handler.Handle(context.Background(), &Command{
+				ContestID:       repo.source.ContestID,
+				IncludeProblems: tt.includeProblems,
+			})
+			if err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			}
+
+			if !slices.Equal(response.ProposedProblemIDs, tt.want) {
+				t.Errorf("ProposedProblemIDs = %v, want %v", response.ProposedProblemIDs, tt.want)
+			}
+
+			if !slices.Equal(repo.targeted, tt.want) {
+				t.Errorf("targeted = %v, want %v", repo.targeted, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleChecksPermissions(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []string
+	}{
+		{name: "cannot create contests", permissions: []string{constant.PermissionContestReadDetailsAny}},
+		{name: "cannot read the source", permissions: []string{constant.PermissionContestCreate}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepository()
+			handler := newTestHandler(repo, tt.permissions...)
+
+			_, err := handler.Handle(context.Background(), &Command{ContestID: repo.source.ContestID})
+			if !errors.Is(err, customerror.ErrBaseNoPermission) {
+				t.Errorf("Handle() error = %v, want no permission", err)
+			}
+
+			if len(repo.created) != 0 {
+				t.Errorf("created %d contests, want none", len(repo.created))
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package clonecontest
+
+import (
+	"context"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/createcontest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) GetContest(ctx context.Context, contestID uuid.UUID) (*createcontest.Contest, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var contest database.Contest
+	if err := db.WithContext(ctx).
+		Where("contest_id = ?", contestID).
+		First(&contest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, errors.WrapIf(err, "failed to get contest")
+	}
+
+	return &createcontest.Contest{
+		ContestID:        contest.ContestID,
+		Title:            contest.Title,
+		Description:      contest.Description,
+		MinProblemCount:  contest.MinProblemCount,
+		MaxProblemCount:  contest.MaxProblemCount,
+		MaxTimeLimitMs:   contest.MaxTimeLimitMs,
+		MaxMemoryLimitMb: contest.MaxMemoryLimitMb,
+		DeadlineDatetime: contest.DeadlineDatetime,
+		Phase:            constant.FromStringToContestPhase(contest.Phase),
+		StartDatetime:    contest.StartDatetime,
+		EndDatetime:      contest.EndDatetime,
+		CreatedAt:        contest.CreatedAt,
+	}, nil
+}
+
+func (r *GormRepository) CreateContest(ctx context.Context, contest createcontest.Contest) error {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	contestModel := database.Contest{
+		ContestID:        contest.ContestID,
+		Title:            contest.Title,
+		Description:      contest.Description,
+		MinProblemCount:  contest.MinProblemCount,
+		MaxProblemCount:  contest.MaxProblemCount,
+		MaxTimeLimitMs:   contest.MaxTimeLimitMs,
+		MaxMemoryLimitMb: contest.MaxMemoryLimitMb,
+		DeadlineDatetime: contest.DeadlineDatetime,
+		Phase:            string(contest.Phase),
+		StartDatetime:    contest.StartDatetime,
+		EndDatetime:      contest.EndDatetime,
+		CreatedAt:        contest.CreatedAt,
+		UpdatedAt:        contest.CreatedAt,
+	}
+
+	if err := db.WithContext(ctx).Create(&contestModel).Error; err != nil {
+		return errors.WrapIf(err, "failed to create contest")
+	}
+
+	return nil
+}
+
+func (r *GormRepository) GetAssignedProblems(ctx context.Context, contestID uuid.UUID) ([]AssignedProblem, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var contestProblems []database.ContestProblem
+	if err := db.WithContext(ctx).
+		Preload("Problem").
+		Where("contest_id = ?", contestID).
+		Order("position ASC").
+		Find(&contestProblems).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get assigned problems")
+	}
+
+	problems := make([]AssignedProblem, 0, len(contestProblems))
+	for _, cp := range contestProblems {
+		var version database.ProblemVersion
+		if err := db.WithContext(ctx).
+			Select("time_limit_ms", "memory_limit_mb").
+			Where("problem_id = ?", cp.ProblemID).
+			Order("created_at DESC").
+			First(&version).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WrapIf(err, "failed to get latest problem version limits")
+		}
+
+		problems = append(problems, AssignedProblem{
+			ProposedProblem: contest.ProposedProblem{
+				Status:        constant.FromStringToProblemStatus(cp.Problem.Status),
+				TimeLimitMs:   version.TimeLimitMs,
+				MemoryLimitMb: version.MemoryLimitMb,
+			},
+			ProblemID: cp.ProblemID,
+			HasTarget: cp.Problem.TargetContestID.Valid,
+		})
+	}
+
+	return problems, nil
+}
+
+func (r *GormRepository) SetTargetContest(
+	ctx context.Context,
+	problemIDs []uuid.UUID,
+	contestID uuid.UUID,
+	updatedAt time.Time,
+) error {
+	if len(problemIDs) == 0 {
+		return nil
+	}
+
+	db := database.GetDBFromContext(ctx, r.db)
+
+	if err := db.WithContext(ctx).
+		Model(&database.Problem{}).
+		Where("problem_id IN ? AND target_contest_id IS NULL", problemIDs).
+		Updates(map[string]any{
+			"target_contest_id": contestID,
+			"updated_at":        updatedAt,
+		}).Error; err != nil {
+		return errors.WrapIf(err, "failed to update target contest")
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package clonecontest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestSetTargetContestKeepsExistingTargets(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{DisableForeignKeyConstraintWhenMigrating: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every connection to an in-memory database gets a database of its own.
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(&database.Problem{}); err != nil {
+		t.Fatal(err)
+	}
+
+	otherContestID := uuid.New()
+	untargeted := database.Problem{ProblemID: uuid.New(), ProblemDraftID: uuid.New()}
+	targeted := database.Problem{
+		ProblemID:       uuid.New(),
+		ProblemDraftID:  uuid.New(),
+		TargetContestID: uuid.NullUUID{UUID: otherContestID, Valid: true},
+	}
+	if err := db.Create(&[]database.Problem{untargeted, targeted}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	cloneID := uuid.New()
+	if err := NewGormRepository(db).SetTargetContest(
+		context.Background(),
+		[]uuid.UUID{untargeted.ProblemID, targeted.ProblemID},
+		cloneID,
+		time.Now(),
+	); err != nil {
+		t.Fatalf("SetTargetContest() error = %v", err)
+	}
+
+	want := map[uuid.UUID]uuid.UUID{untargeted.ProblemID: cloneID, targeted.ProblemID: otherContestID}
+	for problemID, contestID := range want {
+		var p database.Problem
+		if err := db.Where("problem_id = ?", problemID).First(&p).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		if p.TargetContestID.UUID != contestID {
+			t.Errorf("target of %s = %s, want %s", problemID, p.TargetContestID.UUID, contestID)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package clonecontest
+
+import "github.com/google/uuid"
+
+type Response struct {
+	ContestID          uuid.UUID   `json:"contest_id"`
+	ProposedProblemIDs []uuid.UUID `json:"proposed_problem_ids"`
+}
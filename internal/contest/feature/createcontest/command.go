@@ -3,9 +3,13 @@ package createcontest
 import "time"
 
 type Command struct {
-	Title            string    `json:"title"             validate:"required"`
-	Description      string    `json:"description"       validate:"required"`
-	MinProblemCount  uint      `json:"min_problem_count" validate:"required,gte=1"`
-	MaxProblemCount  uint      `json:"max_problem_count" validate:"required,gte=1"`
-	DeadlineDatetime time.Time `json:"deadline_datetime" validate:"required"`
+	Title            string     `json:"title"               validate:"required"`
+	Description      string     `json:"description"         validate:"required"`
+	MinProblemCount  uint       `json:"min_problem_count"   validate:"required,gte=1"`
+	MaxProblemCount  uint       `json:"max_problem_count"   validate:"required,gte=1"`
+	MaxTimeLimitMs   uint       `json:"max_time_limit_ms"   validate:"omitempty,min=100,max=60000"`
+	MaxMemoryLimitMb uint       `json:"max_memory_limit_mb" validate:"omitempty,min=16,max=4096"`
+	DeadlineDatetime time.Time  `json:"deadline_datetime"   validate:"required"`
+	StartDatetime    *time.Time `json:"start_datetime"`
+	EndDatetime      *time.Time `json:"end_datetime"`
 }
@@ -53,7 +53,11 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Respons
 		command.Description,
 		command.MinProblemCount,
 		command.MaxProblemCount,
+		command.MaxTimeLimitMs,
+		command.MaxMemoryLimitMb,
 		command.DeadlineDatetime,
+		command.StartDatetime,
+		command.EndDatetime,
 	)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to create contest")
@@ -3,6 +3,8 @@ package createcontest
 import (
 	"time"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
 	"emperror.dev/errors"
 	"github.com/google/uuid"
 )
@@ -11,22 +13,30 @@ var (
 	ErrProblemCountRangeFlipped = errors.New("minProblemCount cannot be greater than maxProblemCount")
 	ErrInvalidProblemCount      = errors.New("problem count must be greater than 1")
 	ErrInvalidDeadlineDatetime  = errors.New("deadline datetime must be in the future")
+	ErrInvalidSchedule          = errors.New("contest must start before it ends")
 )
 
 type Contest struct {
-	ContestID        uuid.UUID `json:"contest_id"`
-	Title            string    `json:"title"`
-	Description      string    `json:"description"`
-	MinProblemCount  uint      `json:"min_problem_count"`
-	MaxProblemCount  uint      `json:"max_problem_count"`
-	DeadlineDatetime time.Time `json:"deadline_datetime"`
-	CreatedAt        time.Time `json:"created_at"`
+	ContestID        uuid.UUID             `json:"contest_id"`
+	Title            string                `json:"title"`
+	Description      string                `json:"description"`
+	MinProblemCount  uint                  `json:"min_problem_count"`
+	MaxProblemCount  uint                  `json:"max_problem_count"`
+	MaxTimeLimitMs   uint                  `json:"max_time_limit_ms"`
+	MaxMemoryLimitMb uint                  `json:"max_memory_limit_mb"`
+	DeadlineDatetime time.Time             `json:"deadline_datetime"`
+	Phase            constant.ContestPhase `json:"phase"`
+	StartDatetime    *time.Time            `json:"start_datetime"`
+	EndDatetime      *time.Time            `json:"end_datetime"`
+	CreatedAt        time.Time             `json:"created_at"`
 }
 
 func NewContest(
 	title, description string,
 	minProblemCount, maxProblemCount uint,
+	maxTimeLimitMs, maxMemoryLimitMb uint,
 	deadlineDatetime time.Time,
+	startDatetime, endDatetime *time.Time,
 ) (Contest, error) {
 	contestID, err := uuid.NewV7()
 	if err != nil {
@@ -46,13 +56,22 @@ func NewContest(
 		return Contest{}, errors.WithStack(ErrInvalidDeadlineDatetime)
 	}
 
+	if startDatetime != nil && endDatetime != nil && !startDatetime.Before(*endDatetime) {
+		return Contest{}, errors.WithStack(ErrInvalidSchedule)
+	}
+
 	return Contest{
 		ContestID:        contestID,
 		Title:            title,
 		Description:      description,
 		MinProblemCount:  minProblemCount,
 		MaxProblemCount:  maxProblemCount,
+		MaxTimeLimitMs:   maxTimeLimitMs,
+		MaxMemoryLimitMb: maxMemoryLimitMb,
 		DeadlineDatetime: deadlineDatetime,
+		Phase:            constant.ContestPhaseProposal,
+		StartDatetime:    startDatetime,
+		EndDatetime:      endDatetime,
 		CreatedAt:        now,
 	}, nil
 }
@@ -45,6 +45,8 @@ func (e *Endpoint) handle() echo.HandlerFunc {
 			return httperror.New(http.StatusUnprocessableEntity, "Problem count range flipped. Max should be greater than min")
 		} else if errors.Is(err, ErrInvalidDeadlineDatetime) {
 			return httperror.New(http.StatusUnprocessableEntity, "Deadline must be in the future")
+		} else if errors.Is(err, ErrInvalidSchedule) {
+			return httperror.New(http.StatusUnprocessableEntity, "Contest must start before it ends")
 		} else if err != nil {
 			return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
 		}
@@ -28,8 +28,14 @@ func (r *GormRepository) CreateContest(ctx context.Context, contest Contest) err
 		Description:      contest.Description,
 		MinProblemCount:  contest.MinProblemCount,
 		MaxProblemCount:  contest.MaxProblemCount,
+		MaxTimeLimitMs:   contest.MaxTimeLimitMs,
+		MaxMemoryLimitMb: contest.MaxMemoryLimitMb,
 		DeadlineDatetime: contest.DeadlineDatetime,
+		Phase:            string(contest.Phase),
+		StartDatetime:    contest.StartDatetime,
+		EndDatetime:      contest.EndDatetime,
 		CreatedAt:        contest.CreatedAt,
+		UpdatedAt:        contest.CreatedAt,
 	}
 
 	if err := db.WithContext(ctx).Create(&contestModel).Error; err != nil {
@@ -3,6 +3,7 @@ package deletecontest
 import (
 	"context"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
 
 	"emperror.dev/errors"
@@ -11,18 +12,25 @@ import (
 )
 
 type Repository interface {
-	DeleteContest(ctx context.Context, contestID uuid.UUID) error
+	// DeleteContest returns the users who were members of the contest.
+	DeleteContest(ctx context.Context, contestID uuid.UUID) ([]uuid.UUID, error)
 }
 
 type CommandHandler struct {
 	repo      Repository
 	validator *validator.Validate
+	revoker   contract.RoomAccessRevoker
 }
 
-func NewCommandHandler(repo Repository, validator *validator.Validate) *CommandHandler {
+func NewCommandHandler(
+	repo Repository,
+	validator *validator.Validate,
+	revoker contract.RoomAccessRevoker,
+) *CommandHandler {
 	return &CommandHandler{
 		repo:      repo,
 		validator: validator,
+		revoker:   revoker,
 	}
 }
 
@@ -35,9 +43,14 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) error {
 		return errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
 	}
 
-	if err := h.repo.DeleteContest(ctx, command.ContestID); err != nil {
+	memberIDs, err := h.repo.DeleteContest(ctx, command.ContestID)
+	if err != nil {
 		return errors.WrapIf(err, "failed to delete contest in repository")
 	}
 
+	for _, memberID := range memberIDs {
+		h.revoker.RecheckUserRooms(memberID)
+	}
+
 	return nil
 }
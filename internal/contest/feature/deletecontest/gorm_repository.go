@@ -20,12 +20,38 @@ func NewGormRepository(db *gorm.DB) *GormRepository {
 	}
 }
 
-func (r *GormRepository) DeleteContest(ctx context.Context, contestID uuid.UUID) error {
+func (r *GormRepository) DeleteContest(ctx context.Context, contestID uuid.UUID) ([]uuid.UUID, error) {
 	db := database.GetDBFromContext(ctx, r.db)
 
-	contest := &database.Contest{ContestID: contestID}
-	if err := db.WithContext(ctx).Delete(&contest).Error; err != nil {
-		return errors.WrapIf(err, "failed to delete contest")
+	var memberIDs []uuid.UUID
+
+	// The contest is only soft-deleted, but its problems are released so
+	// that they can be assigned to another contest, and its staff lose the
+	// access their roles gave them.
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&database.ContestMembership{}).
+			Where("contest_id = ?", contestID).
+			Pluck("user_id", &memberIDs).Error; err != nil {
+			return errors.WrapIf(err, "failed to get contest members")
+		}
+
+		if err := tx.Where("contest_id = ?", contestID).Delete(&database.ContestProblem{}).Error; err != nil {
+			return errors.WrapIf(err, "failed to release contest problems")
+		}
+
+		if err := tx.Where("contest_id = ?", contestID).Delete(&database.ContestMembership{}).Error; err != nil {
+			return errors.WrapIf(err, "failed to delete contest memberships")
+		}
+
+		contest := &database.Contest{ContestID: contestID}
+		if err := tx.Delete(&contest).Error; err != nil {
+			return errors.WrapIf(err, "failed to delete contest")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
 	}
-	return nil
+
+	return memberIDs, nil
 }
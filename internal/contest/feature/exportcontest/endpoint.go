@@ -0,0 +1,73 @@
+package exportcontest
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/httperror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/problemexport"
+
+	"emperror.dev/errors"
+	"github.com/labstack/echo/v4"
+)
+
+type Endpoint struct {
+	*contest.EndpointParams
+	handler *QueryHandler
+}
+
+func NewEndpoint(params *contest.EndpointParams, handler *QueryHandler) *Endpoint {
+	return &Endpoint{
+		EndpointParams: params,
+		handler:        handler,
+	}
+}
+
+func (e *Endpoint) MapEndpoint() {
+	e.ContestsGroup.GET("/:contest_id/export", e.handle())
+}
+
+func (e *Endpoint) handle() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		query := &Query{}
+		if err := ctx.Bind(query); err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid request format")
+		}
+
+		if err := ctx.Validate(query); err != nil {
+			return err
+		}
+
+		response, err := e.handler.Handle(ctx.Request().Context(), query)
+		var notReadyErr *NotReadyError
+		if errors.Is(err, customerror.ErrValidationFailed) {
+			return httperror.New(http.StatusBadRequest, err.Error())
+		} else if errors.Is(err, ErrContestNotFound) {
+			return httperror.New(http.StatusNotFound, "The contest does not exist")
+		} else if errors.Is(err, ErrNoProblems) {
+			return httperror.New(http.StatusUnprocessableEntity, "The contest has no assigned problems")
+		} else if errors.As(err, &notReadyErr) {
+			return httperror.New(http.StatusConflict, "Some problems are not ready for export").
+				WithDetails(notReadyErr.Problems)
+		} else if err != nil {
+			return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
+		}
+
+		archive, err := problemexport.Stage(ctx.Request().Context(), response.Write)
+		if err != nil {
+			return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
+		}
+		defer archive.Close()
+
+		header := ctx.Response().Header()
+		header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{
+			"filename": response.FileName,
+		}))
+		header.Set(echo.HeaderContentLength, strconv.FormatInt(archive.Size, 10))
+
+		return ctx.Stream(http.StatusOK, "application/zip", archive)
+	}
+}
@@ -0,0 +1,120 @@
+package exportcontest
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/problemexport"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) GetContestTitle(ctx context.Context, contestID uuid.UUID) (string, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var contest database.Contest
+	if err := db.WithContext(ctx).
+		Select("title").
+		Where("contest_id = ?", contestID).
+		First(&contest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", errors.WithStack(ErrContestNotFound)
+		}
+
+		return "", errors.WrapIf(err, "failed to get contest")
+	}
+
+	return contest.Title, nil
+}
+
+func (r *GormRepository) GetAssignedProblems(ctx context.Context, contestID uuid.UUID) ([]AssignedProblem, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var contestProblems []database.ContestProblem
+	if err := db.WithContext(ctx).
+		Preload("Problem", func(db *gorm.DB) *gorm.DB {
+			return db.Select("problem_id", "status")
+		}).
+		Where("contest_id = ?", contestID).
+		Order("position ASC").
+		Find(&contestProblems).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get assigned problems")
+	}
+
+	result := make([]AssignedProblem, len(contestProblems))
+	for i, cp := range contestProblems {
+		result[i] = AssignedProblem{
+			ProblemID:     cp.ProblemID,
+			Status:        constant.FromStringToProblemStatus(cp.Problem.Status),
+			Label:         cp.Label,
+			DisplayTitle:  cp.DisplayTitle,
+			TimeLimitMs:   cp.TimeLimitMs,
+			MemoryLimitMb: cp.MemoryLimitMb,
+		}
+	}
+
+	return result, nil
+}
+
+func (r *GormRepository) GetLatestApprovedVersions(
+	ctx context.Context,
+	problemIDs []uuid.UUID,
+) (map[uuid.UUID]problemexport.Problem, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var approved []database.ProblemVersion
+	if err := db.WithContext(ctx).
+		Select("problem_versions.problem_version_id", "problem_versions.problem_id").
+		Joins("JOIN problem_reviews ON problem_reviews.version_id = problem_versions.problem_version_id").
+		Where("problem_versions.problem_id IN ? AND problem_reviews.decision = ?", problemIDs, "approve").
+		Order("problem_versions.created_at DESC").
+		Find(&approved).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get approved versions")
+	}
+
+	latest := make(map[uuid.UUID]uuid.UUID)
+	versionIDs := make([]uuid.UUID, 0, len(problemIDs))
+	for _, v := range approved {
+		if _, ok := latest[v.ProblemID]; !ok {
+			latest[v.ProblemID] = v.ProblemVersionID
+			versionIDs = append(versionIDs, v.ProblemVersionID)
+		}
+	}
+
+	result := make(map[uuid.UUID]problemexport.Problem, len(versionIDs))
+	if len(versionIDs) == 0 {
+		return result, nil
+	}
+
+	var versions []database.ProblemVersion
+	if err := db.WithContext(ctx).
+		Where("problem_version_id IN ?", versionIDs).
+		Preload("Details").
+		Preload("Examples").
+		Preload("Testcases", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Preload("Solutions").
+		Find(&versions).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get problem versions")
+	}
+
+	for _, v := range versions {
+		result[v.ProblemID] = problemexport.FromGormProblemVersion(v)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,8 @@
+package exportcontest
+
+import "github.com/google/uuid"
+
+type Query struct {
+	ContestID uuid.UUID `param:"contest_id" validate:"required"`
+	Format    string    `query:"format"     validate:"required,oneof=domjudge kattis"`
+}
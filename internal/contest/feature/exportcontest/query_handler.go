@@ -0,0 +1,200 @@
+package exportcontest
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/judge"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/problemexport"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrContestNotFound  = errors.New("contest not found")
+	ErrNoProblems       = errors.New("contest has no assigned problems")
+	ErrProblemsNotReady = errors.New("some problems are not ready for export")
+)
+
+type AssignedProblem struct {
+	ProblemID     uuid.UUID
+	Status        constant.ProblemStatus
+	Label         string
+	DisplayTitle  string // Empty means the problem's own title
+	TimeLimitMs   uint   // Zero means the problem's own limit
+	MemoryLimitMb uint   // Zero means the problem's own limit
+}
+
+// ProblemNotReady is a problem that keeps the contest from being exported.
+type ProblemNotReady struct {
+	ProblemID uuid.UUID              `json:"problem_id"`
+	Status    constant.ProblemStatus `json:"status"`
+	Reason    string                 `json:"reason"`
+}
+
+type NotReadyError struct {
+	Problems []ProblemNotReady
+}
+
+func (e *NotReadyError) Error() string {
+	return fmt.Sprintf("%d problems are not ready for export", len(e.Problems))
+}
+
+func (e *NotReadyError) Is(target error) bool {
+	return target == ErrProblemsNotReady
+}
+
+type Repository interface {
+	GetContestTitle(ctx context.Context, contestID uuid.UUID) (string, error)
+	// GetAssignedProblems returns the problems in their contest order.
+	GetAssignedProblems(ctx context.Context, contestID uuid.UUID) ([]AssignedProblem, error)
+	// GetLatestApprovedVersions leaves out problems without an approved
+	// version.
+	GetLatestApprovedVersions(
+		ctx context.Context,
+		problemIDs []uuid.UUID,
+	) (map[uuid.UUID]problemexport.Problem, error)
+}
+
+type QueryHandler struct {
+	repo         Repository
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+	blobStore    contract.BlobStore
+	judgeOpts    *judge.Options
+}
+
+func NewQueryHandler(
+	repo Repository,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+	blobStore contract.BlobStore,
+	judgeOpts *judge.Options,
+) *QueryHandler {
+	return &QueryHandler{
+		repo:         repo,
+		validator:    validator,
+		authProvider: authProvider,
+		blobStore:    blobStore,
+		judgeOpts:    judgeOpts,
+	}
+}
+
+func (h *QueryHandler) Handle(ctx context.Context, query *Query) (*Response, error) {
+	if query == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(query); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	format, err := problemexport.ParseFormat(query.Format)
+	if err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	can, err := h.authProvider.Can(ctx, constant.PermissionContestExportAny)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to check permission for exporting contests")
+	}
+
+	if !can {
+		return nil, customerror.NewNoPermissionError(constant.PermissionContestExportAny)
+	}
+
+	title, err := h.repo.GetContestTitle(ctx, query.ContestID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get contest")
+	}
+
+	assigned, err := h.repo.GetAssignedProblems(ctx, query.ContestID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get assigned problems")
+	} else if len(assigned) == 0 {
+		return nil, errors.WithStack(ErrNoProblems)
+	}
+
+	problemIDs := make([]uuid.UUID, len(assigned))
+	for i, p := range assigned {
+		problemIDs[i] = p.ProblemID
+	}
+
+	versions, err := h.repo.GetLatestApprovedVersions(ctx, problemIDs)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get latest approved versions")
+	}
+
+	contest := problemexport.Contest{
+		ContestID: query.ContestID,
+		Title:     title,
+		Problems:  make([]problemexport.ContestProblem, 0, len(assigned)),
+	}
+	notReady := make([]ProblemNotReady, 0)
+
+	for _, p := range assigned {
+		version, ok := versions[p.ProblemID]
+
+		var reason string
+		var unsupportedErr *problemexport.UnsupportedError
+		if p.Status != constant.ProblemStatusCompleted {
+			reason = "the problem is not completed"
+		} else if !ok {
+			reason = "the problem has no approved version"
+		} else if err := problemexport.Check(version); errors.As(err, &unsupportedErr) {
+			reason = unsupportedErr.Reason
+		}
+
+		if reason != "" {
+			notReady = append(notReady, ProblemNotReady{ProblemID: p.ProblemID, Status: p.Status, Reason: reason})
+			continue
+		}
+
+		contest.Problems = append(contest.Problems, problemexport.ContestProblem{
+			Label:   p.Label,
+			Problem: applyOverrides(version, p),
+		})
+	}
+
+	if len(notReady) > 0 {
+		return nil, errors.WithStack(&NotReadyError{Problems: notReady})
+	}
+
+	writer := problemexport.NewWriter(format, h.blobStore, h.judgeOpts.TestlibDir)
+	return &Response{
+		FileName: fmt.Sprintf("contest-%s-%s.zip", query.ContestID, format),
+		Write: func(ctx context.Context, w io.Writer) error {
+			return writer.WriteContest(ctx, w, contest)
+		},
+	}, nil
+}
+
+// applyOverrides returns the problem with the title and limits set for it in
+// the contest.
+func applyOverrides(problem problemexport.Problem, assigned AssignedProblem) problemexport.Problem {
+	if assigned.DisplayTitle != "" {
+		statements := make([]problemexport.Statement, len(problem.Statements))
+		for i, statement := range problem.Statements {
+			statement.Title = assigned.DisplayTitle
+			statements[i] = statement
+		}
+
+		problem.Statements = statements
+	}
+
+	if assigned.TimeLimitMs > 0 {
+		problem.TimeLimitMs = assigned.TimeLimitMs
+	}
+
+	if assigned.MemoryLimitMb > 0 {
+		problem.MemoryLimitMb = assigned.MemoryLimitMb
+	}
+
+	return problem
+}
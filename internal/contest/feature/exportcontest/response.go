@@ -0,0 +1,13 @@
+package exportcontest
+
+import (
+	"context"
+	"io"
+)
+
+// Response streams the archive with Write, so that testcases are copied from
+// the blob store as the archive is sent.
+type Response struct {
+	FileName string
+	Write    func(ctx context.Context, w io.Writer) error
+}
@@ -0,0 +1,54 @@
+package getcontestreadiness
+
+import (
+	"net/http"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/httperror"
+
+	"emperror.dev/errors"
+	"github.com/labstack/echo/v4"
+)
+
+type Endpoint struct {
+	*contest.EndpointParams
+	handler *QueryHandler
+}
+
+func NewEndpoint(params *contest.EndpointParams, handler *QueryHandler) *Endpoint {
+	return &Endpoint{
+		EndpointParams: params,
+		handler:        handler,
+	}
+}
+
+func (e *Endpoint) MapEndpoint() {
+	e.ContestsGroup.GET("/:contest_id/readiness", e.handle())
+}
+
+func (e *Endpoint) handle() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		query := &Query{}
+		if err := ctx.Bind(query); err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid request format")
+		}
+
+		if err := ctx.Validate(query); err != nil {
+			return err
+		}
+
+		response, err := e.handler.Handle(ctx.Request().Context(), query)
+		if errors.Is(err, customerror.ErrBaseNoPermission) ||
+			errors.Is(err, customerror.ErrCommandNil) ||
+			errors.Is(err, customerror.ErrValidationFailed) {
+			return err
+		} else if errors.Is(err, ErrContestNotFound) {
+			return httperror.New(http.StatusNotFound, "The contest does not exist")
+		} else if err != nil {
+			return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
+		}
+
+		return ctx.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,164 @@
+package getcontestreadiness
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) GetContest(ctx context.Context, contestID uuid.UUID) (*Contest, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var contest database.Contest
+	if err := db.WithContext(ctx).
+		Select("contest_id", "phase", "min_problem_count", "max_problem_count").
+		Where("contest_id = ?", contestID).
+		First(&contest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, errors.WrapIf(err, "failed to get contest")
+	}
+
+	return &Contest{
+		ContestID:       contest.ContestID,
+		Phase:           constant.FromStringToContestPhase(contest.Phase),
+		MinProblemCount: contest.MinProblemCount,
+		MaxProblemCount: contest.MaxProblemCount,
+	}, nil
+}
+
+func (r *GormRepository) GetAssignedProblems(ctx context.Context, contestID uuid.UUID) ([]AssignedProblem, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var contestProblems []database.ContestProblem
+	if err := db.WithContext(ctx).
+		Preload("Problem", func(db *gorm.DB) *gorm.DB {
+			return db.Select("problem_id", "status")
+		}).
+		Preload("Problem.Testers", func(db *gorm.DB) *gorm.DB {
+			return db.Select("user_id", "username")
+		}).
+		Where("contest_id = ?", contestID).
+		Order("position ASC").
+		Find(&contestProblems).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get assigned problems")
+	}
+
+	if len(contestProblems) == 0 {
+		return []AssignedProblem{}, nil
+	}
+
+	problemIDs := make([]uuid.UUID, 0, len(contestProblems))
+	for _, cp := range contestProblems {
+		problemIDs = append(problemIDs, cp.ProblemID)
+	}
+
+	var versions []database.ProblemVersion
+	if err := db.WithContext(ctx).
+		Select("problem_version_id", "problem_id", "problem_difficulty_id", "created_at").
+		Preload("Details", func(db *gorm.DB) *gorm.DB {
+			return db.Select("detail_id", "problem_version_id", "language", "title", "statement")
+		}).
+		Preload("TestResults", func(db *gorm.DB) *gorm.DB {
+			return db.Select("problem_test_result_id", "version_id", "tester_id")
+		}).
+		Where("problem_id IN ?", problemIDs).
+		Order("created_at DESC").
+		Find(&versions).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get problem versions")
+	}
+
+	// Versions are ordered newest first, so the first one seen for a problem
+	// is its latest.
+	latestVersions := make(map[uuid.UUID]database.ProblemVersion, len(contestProblems))
+	for _, v := range versions {
+		if _, ok := latestVersions[v.ProblemID]; !ok {
+			latestVersions[v.ProblemID] = v
+		}
+	}
+
+	problems := make([]AssignedProblem, 0, len(contestProblems))
+	for _, cp := range contestProblems {
+		version := latestVersions[cp.ProblemID]
+
+		languages := make([]string, 0, len(version.Details))
+		for _, d := range version.Details {
+			if d.Title != "" && d.Statement != "" {
+				languages = append(languages, d.Language)
+			}
+		}
+
+		reported := make(map[uuid.UUID]struct{}, len(version.TestResults))
+		for _, result := range version.TestResults {
+			reported[result.TesterID] = struct{}{}
+		}
+
+		outstanding := make([]Tester, 0)
+		for _, tester := range cp.Problem.Testers {
+			if _, ok := reported[tester.UserID]; !ok {
+				outstanding = append(outstanding, Tester{
+					UserID:   tester.UserID,
+					Username: tester.Username,
+				})
+			}
+		}
+
+		problems = append(problems, AssignedProblem{
+			ProblemID:           cp.ProblemID,
+			Label:               cp.Label,
+			Status:              constant.FromStringToProblemStatus(cp.Problem.Status),
+			ProblemDifficultyID: version.ProblemDifficultyID,
+			Languages:           languages,
+			OutstandingTesters:  outstanding,
+		})
+	}
+
+	return problems, nil
+}
+
+func (r *GormRepository) GetDifficulties(ctx context.Context) ([]Difficulty, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var difficulties []database.ProblemDifficulty
+	if err := db.WithContext(ctx).
+		Preload("DisplayNames").
+		Order("problem_difficulty_id ASC").
+		Find(&difficulties).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get problem difficulties")
+	}
+
+	result := make([]Difficulty, 0, len(difficulties))
+	for _, d := range difficulties {
+		displayNames := make([]DisplayName, 0, len(d.DisplayNames))
+		for _, n := range d.DisplayNames {
+			displayNames = append(displayNames, DisplayName{
+				Language:    n.Language,
+				DisplayName: n.DisplayName,
+			})
+		}
+
+		result = append(result, Difficulty{
+			ProblemDifficultyID: d.ProblemDifficultyID,
+			DisplayNames:        displayNames,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,7 @@
+package getcontestreadiness
+
+import "github.com/google/uuid"
+
+type Query struct {
+	ContestID uuid.UUID `param:"contest_id" validate:"required"`
+}
@@ -0,0 +1,188 @@
+package getcontestreadiness
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+var ErrContestNotFound = errors.New("contest not found")
+
+type Contest struct {
+	ContestID       uuid.UUID
+	Phase           constant.ContestPhase
+	MinProblemCount uint
+	MaxProblemCount uint
+}
+
+// AssignedProblem is an assigned problem as of its latest version.
+type AssignedProblem struct {
+	ProblemID           uuid.UUID
+	Label               string
+	Status              constant.ProblemStatus
+	ProblemDifficultyID uuid.UUID
+	// Languages the problem has a title and statement in.
+	Languages          []string
+	OutstandingTesters []Tester
+}
+
+type Difficulty struct {
+	ProblemDifficultyID uuid.UUID
+	DisplayNames        []DisplayName
+}
+
+type Repository interface {
+	GetContest(ctx context.Context, contestID uuid.UUID) (*Contest, error)
+	// GetAssignedProblems returns the problems in their contest order.
+	GetAssignedProblems(ctx context.Context, contestID uuid.UUID) ([]AssignedProblem, error)
+	GetDifficulties(ctx context.Context) ([]Difficulty, error)
+}
+
+type QueryHandler struct {
+	repo         Repository
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+}
+
+func NewQueryHandler(
+	repo Repository,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+) *QueryHandler {
+	return &QueryHandler{
+		repo:         repo,
+		validator:    validator,
+		authProvider: authProvider,
+	}
+}
+
+func (h *QueryHandler) Handle(ctx context.Context, query *Query) (*Response, error) {
+	if query == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(query); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	if can, err := h.authProvider.Can(ctx, constant.PermissionContestReadDetailsAny); err != nil {
+		return nil, errors.WrapIf(err, "failed to check permission")
+	} else if !can {
+		return nil, customerror.NewNoPermissionError(constant.PermissionContestReadDetailsAny)
+	}
+
+	contest, err := h.repo.GetContest(ctx, query.ContestID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get contest")
+	} else if contest == nil {
+		return nil, errors.WithStack(ErrContestNotFound)
+	}
+
+	problems, err := h.repo.GetAssignedProblems(ctx, query.ContestID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get assigned problems")
+	}
+
+	difficulties, err := h.repo.GetDifficulties(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get problem difficulties")
+	}
+
+	return &Response{
+		Readiness: buildReadiness(*contest, problems, difficulties),
+	}, nil
+}
+
+// buildReadiness aggregates the assigned problems of the contest. Blockers are
+// what keeps the contest from being frozen and exported: a problem count
+// outside the contest's range, problems that are not completed and missing
+// translations.
+func buildReadiness(contest Contest, problems []AssignedProblem, difficulties []Difficulty) Readiness {
+	readiness := Readiness{
+		ContestID:           contest.ContestID,
+		Phase:               contest.Phase,
+		ProblemCount:        uint(len(problems)),
+		MinProblemCount:     contest.MinProblemCount,
+		MaxProblemCount:     contest.MaxProblemCount,
+		Statuses:            make(map[constant.ProblemStatus]uint),
+		Difficulties:        make([]DifficultyCount, 0, len(difficulties)),
+		MissingTranslations: make([]MissingTranslation, 0),
+		OutstandingTesters:  make([]OutstandingTesters, 0),
+		Blockers:            make([]Blocker, 0),
+	}
+
+	if readiness.ProblemCount < contest.MinProblemCount {
+		readiness.Blockers = append(readiness.Blockers, Blocker{
+			Code:    BlockerNotEnoughProblems,
+			Message: fmt.Sprintf("The contest has %d of at least %d problems", readiness.ProblemCount, contest.MinProblemCount),
+		})
+	} else if readiness.ProblemCount > contest.MaxProblemCount {
+		readiness.Blockers = append(readiness.Blockers, Blocker{
+			Code:    BlockerTooManyProblems,
+			Message: fmt.Sprintf("The contest has %d of at most %d problems", readiness.ProblemCount, contest.MaxProblemCount),
+		})
+	}
+
+	difficultyCounts := make(map[uuid.UUID]uint)
+	for _, p := range problems {
+		readiness.Statuses[p.Status]++
+		difficultyCounts[p.ProblemDifficultyID]++
+
+		if p.Status != constant.ProblemStatusCompleted {
+			readiness.Blockers = append(readiness.Blockers, Blocker{
+				Code:      BlockerProblemNotCompleted,
+				Message:   fmt.Sprintf("Problem %s is %s", p.Label, p.Status),
+				ProblemID: &p.ProblemID,
+			})
+
+			if len(p.OutstandingTesters) > 0 {
+				readiness.OutstandingTesters = append(readiness.OutstandingTesters, OutstandingTesters{
+					ProblemID: p.ProblemID,
+					Label:     p.Label,
+					Testers:   p.OutstandingTesters,
+				})
+			}
+		}
+
+		missing := make([]string, 0)
+		for _, language := range constant.Languages {
+			if !slices.Contains(p.Languages, language) {
+				missing = append(missing, language)
+			}
+		}
+
+		if len(missing) > 0 {
+			readiness.MissingTranslations = append(readiness.MissingTranslations, MissingTranslation{
+				ProblemID: p.ProblemID,
+				Label:     p.Label,
+				Languages: missing,
+			})
+
+			readiness.Blockers = append(readiness.Blockers, Blocker{
+				Code:      BlockerMissingTranslation,
+				Message:   fmt.Sprintf("Problem %s is missing %s", p.Label, strings.Join(missing, ", ")),
+				ProblemID: &p.ProblemID,
+			})
+		}
+	}
+
+	for _, d := range difficulties {
+		readiness.Difficulties = append(readiness.Difficulties, DifficultyCount{
+			ProblemDifficultyID: d.ProblemDifficultyID,
+			DisplayNames:        d.DisplayNames,
+			Count:               difficultyCounts[d.ProblemDifficultyID],
+		})
+	}
+
+	readiness.IsReady = len(readiness.Blockers) == 0
+	return readiness
+}
@@ -0,0 +1,162 @@
+package getcontestreadiness
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
+	"github.com/google/uuid"
+)
+
+func TestBuildReadiness(t *testing.T) {
+	easy := uuid.New()
+	hard := uuid.New()
+	difficulties := []Difficulty{{ProblemDifficultyID: easy}, {ProblemDifficultyID: hard}}
+	tester := Tester{UserID: uuid.New(), Username: "tester"}
+
+	completed := func(label string, difficultyID uuid.UUID) AssignedProblem {
+		return AssignedProblem{
+			ProblemID:           uuid.New(),
+			Label:               label,
+			Status:              constant.ProblemStatusCompleted,
+			ProblemDifficultyID: difficultyID,
+			Languages:           constant.Languages,
+		}
+	}
+
+	tests := []struct {
+		name                   string
+		contest                Contest
+		problems               []AssignedProblem
+		wantBlockers           []string
+		wantMissing            int
+		wantOutstandingTesters int
+	}{
+		{
+			name:     "ready",
+			contest:  Contest{MinProblemCount: 1, MaxProblemCount: 2},
+			problems: []AssignedProblem{completed("A", easy), completed("B", hard)},
+		},
+		{
+			name:         "not enough problems",
+			contest:      Contest{MinProblemCount: 2, MaxProblemCount: 3},
+			problems:     []AssignedProblem{completed("A", easy)},
+			wantBlockers: []string{BlockerNotEnoughProblems},
+		},
+		{
+			name:         "too many problems",
+			contest:      Contest{MinProblemCount: 0, MaxProblemCount: 1},
+			problems:     []AssignedProblem{completed("A", easy), completed("B", easy)},
+			wantBlockers: []string{BlockerTooManyProblems},
+		},
+		{
+			name:    "problem not completed",
+			contest: Contest{MinProblemCount: 1, MaxProblemCount: 1},
+			problems: []AssignedProblem{{
+				ProblemID:           uuid.New(),
+				Label:               "A",
+				Status:              constant.ProblemStatusPendingTesting,
+				ProblemDifficultyID: easy,
+				Languages:           constant.Languages,
+				OutstandingTesters:  []Tester{tester},
+			}},
+			wantBlockers:           []string{BlockerProblemNotCompleted},
+			wantOutstandingTesters: 1,
+		},
+		{
+			name:    "completed problem with testers left",
+			contest: Contest{MinProblemCount: 1, MaxProblemCount: 1},
+			problems: []AssignedProblem{func() AssignedProblem {
+				p := completed("A", easy)
+				p.OutstandingTesters = []Tester{tester}
+				return p
+			}()},
+		},
+		{
+			name:    "missing translation",
+			contest: Contest{MinProblemCount: 1, MaxProblemCount: 1},
+			problems: []AssignedProblem{func() AssignedProblem {
+				p := completed("A", easy)
+				p.Languages = []string{constant.LanguageEnUS}
+				return p
+			}()},
+			wantBlockers: []string{BlockerMissingTranslation},
+			wantMissing:  1,
+		},
+		{
+			name:         "empty contest",
+			contest:      Contest{MinProblemCount: 1, MaxProblemCount: 1},
+			wantBlockers: []string{BlockerNotEnoughProblems},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readiness := buildReadiness(tt.contest, tt.problems, difficulties)
+
+			blockers := make([]string, 0, len(readiness.Blockers))
+			for _, blocker := range readiness.Blockers {
+				blockers = append(blockers, blocker.Code)
+			}
+
+			if !slices.Equal(blockers, tt.wantBlockers) {
+				t.Errorf("blockers = %v, want %v", blockers, tt.wantBlockers)
+			}
+
+			if readiness.IsReady != (len(tt.wantBlockers) == 0) {
+				t.Errorf("IsReady = %v, want %v", readiness.IsReady, len(tt.wantBlockers) == 0)
+			}
+
+			if len(readiness.MissingTranslations) != tt.wantMissing {
+				t.Errorf("len(MissingTranslations) = %d, want %d", len(readiness.MissingTranslations), tt.wantMissing)
+			}
+
+			if len(readiness.OutstandingTesters) != tt.wantOutstandingTesters {
+				t.Errorf("len(OutstandingTesters) = %d, want %d", len(readiness.OutstandingTesters), tt.wantOutstandingTesters)
+			}
+
+			if readiness.ProblemCount != uint(len(tt.problems)) {
+				t.Errorf("ProblemCount = %d, want %d", readiness.ProblemCount, len(tt.problems))
+			}
+		})
+	}
+}
+
+func TestBuildReadinessCounts(t *testing.T) {
+	easy := uuid.New()
+	hard := uuid.New()
+	unused := uuid.New()
+	difficulties := []Difficulty{{ProblemDifficultyID: easy}, {ProblemDifficultyID: hard}, {ProblemDifficultyID: unused}}
+
+	problems := []AssignedProblem{
+		{Label: "A", Status: constant.ProblemStatusCompleted, ProblemDifficultyID: easy},
+		{Label: "B", Status: constant.ProblemStatusCompleted, ProblemDifficultyID: easy},
+		{Label: "C", Status: constant.ProblemStatusPendingReview, ProblemDifficultyID: hard},
+	}
+
+	readiness := buildReadiness(Contest{MaxProblemCount: 3}, problems, difficulties)
+
+	wantStatuses := map[constant.ProblemStatus]uint{
+		constant.ProblemStatusCompleted:     2,
+		constant.ProblemStatusPendingReview: 1,
+	}
+	if len(readiness.Statuses) != len(wantStatuses) {
+		t.Errorf("Statuses = %v, want %v", readiness.Statuses, wantStatuses)
+	}
+	for status, want := range wantStatuses {
+		if got := readiness.Statuses[status]; got != want {
+			t.Errorf("Statuses[%s] = %d, want %d", status, got, want)
+		}
+	}
+
+	wantDifficulties := []uint{2, 1, 0}
+	if len(readiness.Difficulties) != len(wantDifficulties) {
+		t.Fatalf("len(Difficulties) = %d, want %d", len(readiness.Difficulties), len(wantDifficulties))
+	}
+	for i, want := range wantDifficulties {
+		if got := readiness.Difficulties[i]; got.ProblemDifficultyID != difficulties[i].ProblemDifficultyID || got.Count != want {
+			t.Errorf("Difficulties[%d] = %+v, want %d of %s", i, got, want, difficulties[i].ProblemDifficultyID)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package getcontestreadiness
+
+import (
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
+	"github.com/google/uuid"
+)
+
+const (
+	BlockerNotEnoughProblems   = "not_enough_problems"
+	BlockerTooManyProblems     = "too_many_problems"
+	BlockerProblemNotCompleted = "problem_not_completed"
+	BlockerMissingTranslation  = "missing_translation"
+)
+
+type Readiness struct {
+	ContestID           uuid.UUID                       `json:"contest_id"`
+	Phase               constant.ContestPhase           `json:"phase"`
+	IsReady             bool                            `json:"is_ready"`
+	ProblemCount        uint                            `json:"problem_count"`
+	MinProblemCount     uint                            `json:"min_problem_count"`
+	MaxProblemCount     uint                            `json:"max_problem_count"`
+	Statuses            map[constant.ProblemStatus]uint `json:"statuses"`
+	Difficulties        []DifficultyCount               `json:"difficulties"`
+	MissingTranslations []MissingTranslation            `json:"missing_translations"`
+	OutstandingTesters  []OutstandingTesters            `json:"outstanding_testers"`
+	Blockers            []Blocker                       `json:"blockers"`
+}
+
+type DifficultyCount struct {
+	ProblemDifficultyID uuid.UUID     `json:"problem_difficulty_id"`
+	DisplayNames        []DisplayName `json:"display_names"`
+	Count               uint          `json:"count"`
+}
+
+type DisplayName struct {
+	Language    string `json:"language"`
+	DisplayName string `json:"display_name"`
+}
+
+type MissingTranslation struct {
+	ProblemID uuid.UUID `json:"problem_id"`
+	Label     string    `json:"label"`
+	Languages []string  `json:"languages"`
+}
+
+// OutstandingTesters are the assigned testers of a problem that have not
+// reported on its latest version yet.
+type OutstandingTesters struct {
+	ProblemID uuid.UUID `json:"problem_id"`
+	Label     string    `json:"label"`
+	Testers   []Tester  `json:"testers"`
+}
+
+type Tester struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+}
+
+type Blocker struct {
+	Code      string     `json:"code"`
+	Message   string     `json:"message"`
+	ProblemID *uuid.UUID `json:"problem_id,omitempty"`
+}
+
+type Response struct {
+	Readiness Readiness `json:"readiness"`
+}
@@ -2,33 +2,14 @@ package listassignedproblems
 
 import (
 	"context"
-	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
 
 	"emperror.dev/errors"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
-type assignedProblemRecord struct {
-	ProblemID           uuid.UUID     `gorm:"column:problem_id"`
-	CreatedAt           time.Time     `gorm:"column:created_at"`
-	UpdatedAt           time.Time     `gorm:"column:updated_at"`
-	LatestVersionID     uuid.NullUUID `gorm:"column:latest_version_id"`
-	ProblemDifficultyID uuid.NullUUID `gorm:"column:problem_difficulty_id"`
-}
-
-type problemVersionDetailRecord struct {
-	ProblemVersionID uuid.UUID `gorm:"column:problem_version_id"`
-	Language         string    `gorm:"column:language"`
-	Title            string    `gorm:"column:title"`
-}
-
-type problemDifficultyDisplayRecord struct {
-	ProblemDifficultyID uuid.UUID `gorm:"column:problem_difficulty_id"`
-	Language            string    `gorm:"column:language"`
-	DisplayName         string    `gorm:"column:display_name"`
-}
-
 type GormRepository struct {
 	db *gorm.DB
 }
@@ -40,122 +21,114 @@ func NewGormRepository(db *gorm.DB) *GormRepository {
 }
 
 func (g *GormRepository) GetAssignedProblems(ctx context.Context, contestID uuid.UUID) ([]Problem, error) {
-	var records []assignedProblemRecord
-	query := g.db.WithContext(ctx).
-		Table("problems p").
-		Joins(`
-			LEFT JOIN LATERAL (
-				SELECT
-					pv.problem_version_id,
-					pv.problem_difficulty_id,
-					pv.created_at
-				FROM problem_versions pv
-				WHERE pv.problem_id = p.problem_id
-				ORDER BY pv.created_at DESC
-				LIMIT 1
-			) latest ON TRUE
-		`).
-		Select(`
-			p.problem_id,
-			p.created_at,
-			p.updated_at,
-			latest.problem_version_id AS latest_version_id,
-			latest.problem_difficulty_id AS problem_difficulty_id
-		`).
-		Where("p.assigned_contest_id = ?", contestID).
-		Order("p.created_at ASC")
-
-	if err := query.Scan(&records).Error; err != nil {
+	db := database.GetDBFromContext(ctx, g.db)
+
+	var contestProblems []database.ContestProblem
+	if err := db.WithContext(ctx).
+		Preload("Problem", func(db *gorm.DB) *gorm.DB {
+			return db.Select("problem_id", "created_at", "updated_at")
+		}).
+		Where("contest_id = ?", contestID).
+		Order("position ASC").
+		Find(&contestProblems).Error; err != nil {
 		return nil, errors.Wrap(err, "failed to get assigned problems")
 	}
 
-	if len(records) == 0 {
+	if len(contestProblems) == 0 {
 		return []Problem{}, nil
 	}
 
-	versionIDSet := make(map[uuid.UUID]struct{})
-	difficultyIDSet := make(map[uuid.UUID]struct{})
-	for _, rec := range records {
-		if rec.LatestVersionID.Valid {
-			versionIDSet[rec.LatestVersionID.UUID] = struct{}{}
-		}
-		if rec.ProblemDifficultyID.Valid {
-			difficultyIDSet[rec.ProblemDifficultyID.UUID] = struct{}{}
-		}
+	problemIDs := make([]uuid.UUID, 0, len(contestProblems))
+	for _, cp := range contestProblems {
+		problemIDs = append(problemIDs, cp.ProblemID)
 	}
 
-	versionIDs := make([]uuid.UUID, 0, len(versionIDSet))
-	for id := range versionIDSet {
-		versionIDs = append(versionIDs, id)
+	var versions []database.ProblemVersion
+	if err := db.WithContext(ctx).
+		Select(
+			"problem_version_id",
+			"problem_id",
+			"problem_difficulty_id",
+			"time_limit_ms",
+			"memory_limit_mb",
+			"is_interactive",
+			"input_file",
+			"output_file",
+			"created_at",
+		).
+		Preload("Details", func(db *gorm.DB) *gorm.DB {
+			return db.Select("detail_id", "problem_version_id", "language", "title")
+		}).
+		Preload("ProblemDifficulty.DisplayNames").
+		Where("problem_id IN ?", problemIDs).
+		Order("created_at DESC").
+		Find(&versions).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get problem versions")
 	}
 
-	difficultyIDs := make([]uuid.UUID, 0, len(difficultyIDSet))
-	for id := range difficultyIDSet {
-		difficultyIDs = append(difficultyIDs, id)
+	// Versions are ordered newest first, so the first one seen for a problem
+	// is its latest.
+	latestVersions := make(map[uuid.UUID]database.ProblemVersion, len(contestProblems))
+	for _, v := range versions {
+		if _, ok := latestVersions[v.ProblemID]; !ok {
+			latestVersions[v.ProblemID] = v
+		}
 	}
 
-	titlesByVersionID := make(map[uuid.UUID][]ProblemDetailTitle)
-	if len(versionIDs) > 0 {
-		var detailRows []problemVersionDetailRecord
-		if err := g.db.WithContext(ctx).
-			Table("problem_version_details").
-			Select("problem_version_id, language, title").
-			Where("problem_version_id IN ?", versionIDs).
-			Find(&detailRows).Error; err != nil {
-			return nil, errors.Wrap(err, "failed to get problem titles")
-		}
+	problems := make([]Problem, 0, len(contestProblems))
+	for _, cp := range contestProblems {
+		version := latestVersions[cp.ProblemID]
 
-		for _, row := range detailRows {
-			titlesByVersionID[row.ProblemVersionID] = append(titlesByVersionID[row.ProblemVersionID], ProblemDetailTitle{
-				Language: row.Language,
-				Title:    row.Title,
+		titles := make([]ProblemDetailTitle, 0, len(version.Details))
+		for _, d := range version.Details {
+			titles = append(titles, ProblemDetailTitle{
+				Language: d.Language,
+				Title:    d.Title,
 			})
 		}
-	}
 
-	displayNamesByDifficultyID := make(map[uuid.UUID][]ProblemDifficultyDisplayName)
-	if len(difficultyIDs) > 0 {
-		var difficultyRows []problemDifficultyDisplayRecord
-		if err := g.db.WithContext(ctx).
-			Table("problem_difficulty_display_names").
-			Select("problem_difficulty_id, language, display_name").
-			Where("problem_difficulty_id IN ?", difficultyIDs).
-			Find(&difficultyRows).Error; err != nil {
-			return nil, errors.Wrap(err, "failed to get difficulty display names")
+		displayNames := make([]ProblemDifficultyDisplayName, 0, len(version.ProblemDifficulty.DisplayNames))
+		for _, n := range version.ProblemDifficulty.DisplayNames {
+			displayNames = append(displayNames, ProblemDifficultyDisplayName{
+				Language:    n.Language,
+				DisplayName: n.DisplayName,
+			})
 		}
 
-		for _, row := range difficultyRows {
-			displayNamesByDifficultyID[row.ProblemDifficultyID] = append(displayNamesByDifficultyID[row.ProblemDifficultyID], ProblemDifficultyDisplayName{
-				Language:    row.Language,
-				DisplayName: row.DisplayName,
-			})
+		limits := ProblemLimits{
+			TimeLimitMs:   version.TimeLimitMs,
+			MemoryLimitMb: version.MemoryLimitMb,
+			IsInteractive: version.IsInteractive,
+			InputFile:     version.InputFile,
+			OutputFile:    version.OutputFile,
 		}
-	}
 
-	problems := make([]Problem, 0, len(records))
-	for _, rec := range records {
-		titles := titlesByVersionID[rec.LatestVersionID.UUID]
-		if titles == nil {
-			titles = make([]ProblemDetailTitle, 0)
+		if cp.TimeLimitMs > 0 {
+			limits.TimeLimitMs = cp.TimeLimitMs
 		}
 
-		displayNames := displayNamesByDifficultyID[rec.ProblemDifficultyID.UUID]
-		if displayNames == nil {
-			displayNames = make([]ProblemDifficultyDisplayName, 0)
+		if cp.MemoryLimitMb > 0 {
+			limits.MemoryLimitMb = cp.MemoryLimitMb
 		}
 
-		problem := Problem{
-			ProblemID: rec.ProblemID,
-			CreatedAt: rec.CreatedAt,
-			UpdatedAt: rec.UpdatedAt,
+		problems = append(problems, Problem{
+			ProblemID: cp.ProblemID,
+			Position:  cp.Position,
+			Label:     cp.Label,
 			Title:     titles,
 			ProblemDifficulty: ProblemDifficulty{
-				ProblemDifficultyID: rec.ProblemDifficultyID.UUID,
+				ProblemDifficultyID: version.ProblemDifficultyID,
 				DisplayNames:        displayNames,
 			},
-		}
-
-		problems = append(problems, problem)
+			Limits: limits,
+			Overrides: ProblemOverrides{
+				DisplayTitle:  cp.DisplayTitle,
+				TimeLimitMs:   cp.TimeLimitMs,
+				MemoryLimitMb: cp.MemoryLimitMb,
+			},
+			CreatedAt: cp.Problem.CreatedAt,
+			UpdatedAt: cp.Problem.UpdatedAt,
+		})
 	}
 
 	return problems, nil
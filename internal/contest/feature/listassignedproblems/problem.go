@@ -8,8 +8,12 @@ import (
 
 type Problem struct {
 	ProblemID         uuid.UUID            `json:"problem_id"`
-	Title             []ProblemDetailTitle `json:"title" gorm:"-"`
-	ProblemDifficulty ProblemDifficulty    `json:"problem_difficulty" gorm:"-"`
+	Position          uint                 `json:"position"`
+	Label             string               `json:"label"`
+	Title             []ProblemDetailTitle `json:"title"`
+	ProblemDifficulty ProblemDifficulty    `json:"problem_difficulty"`
+	Limits            ProblemLimits        `json:"limits"` // With the contest's overrides applied
+	Overrides         ProblemOverrides     `json:"overrides"`
 	CreatedAt         time.Time            `json:"created_at"`
 	UpdatedAt         time.Time            `json:"updated_at"`
 }
@@ -19,9 +23,24 @@ type ProblemDetailTitle struct {
 	Title    string `json:"title"`
 }
 
+type ProblemLimits struct {
+	TimeLimitMs   uint   `json:"time_limit_ms"`
+	MemoryLimitMb uint   `json:"memory_limit_mb"`
+	IsInteractive bool   `json:"is_interactive"`
+	InputFile     string `json:"input_file"`
+	OutputFile    string `json:"output_file"`
+}
+
+// ProblemOverrides are set per contest. Empty values mean the problem's own.
+type ProblemOverrides struct {
+	DisplayTitle  string `json:"display_title"`
+	TimeLimitMs   uint   `json:"time_limit_ms"`
+	MemoryLimitMb uint   `json:"memory_limit_mb"`
+}
+
 type ProblemDifficulty struct {
 	ProblemDifficultyID uuid.UUID                      `json:"problem_difficulty_id"`
-	DisplayNames        []ProblemDifficultyDisplayName `json:"display_names" gorm:"-"`
+	DisplayNames        []ProblemDifficultyDisplayName `json:"display_names"`
 }
 
 type ProblemDifficultyDisplayName struct {
@@ -3,15 +3,23 @@ package listcontest
 import (
 	"time"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
 	"github.com/google/uuid"
 )
 
 type Contest struct {
-	ContestID        uuid.UUID `json:"contest_id"`
-	Title            string    `json:"title"`
-	Description      string    `json:"description"`
-	MinProblemCount  uint      `json:"min_problem_count"`
-	MaxProblemCount  uint      `json:"max_problem_count"`
-	DeadlineDatetime time.Time `json:"deadline_datetime"`
-	CreatedAt        time.Time `json:"created_at"`
+	ContestID        uuid.UUID             `json:"contest_id"`
+	Title            string                `json:"title"`
+	Description      string                `json:"description"`
+	MinProblemCount  uint                  `json:"min_problem_count"`
+	MaxProblemCount  uint                  `json:"max_problem_count"`
+	MaxTimeLimitMs   uint                  `json:"max_time_limit_ms"`
+	MaxMemoryLimitMb uint                  `json:"max_memory_limit_mb"`
+	DeadlineDatetime time.Time             `json:"deadline_datetime"`
+	Phase            constant.ContestPhase `json:"phase"`
+	StartDatetime    *time.Time            `json:"start_datetime"`
+	EndDatetime      *time.Time            `json:"end_datetime"`
+	CreatedAt        time.Time             `json:"created_at"`
+	UpdatedAt        time.Time             `json:"updated_at"`
 }
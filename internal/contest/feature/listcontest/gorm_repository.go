@@ -3,6 +3,7 @@ package listcontest
 import (
 	"context"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
 
 	"emperror.dev/errors"
@@ -35,8 +36,14 @@ func (g *GormRepository) GetAllContests(ctx context.Context) ([]Contest, error)
 			Description:      contest.Description,
 			MinProblemCount:  contest.MinProblemCount,
 			MaxProblemCount:  contest.MaxProblemCount,
+			MaxTimeLimitMs:   contest.MaxTimeLimitMs,
+			MaxMemoryLimitMb: contest.MaxMemoryLimitMb,
 			DeadlineDatetime: contest.DeadlineDatetime,
+			Phase:            constant.FromStringToContestPhase(contest.Phase),
+			StartDatetime:    contest.StartDatetime,
+			EndDatetime:      contest.EndDatetime,
 			CreatedAt:        contest.CreatedAt,
+			UpdatedAt:        contest.UpdatedAt,
 		})
 	}
 
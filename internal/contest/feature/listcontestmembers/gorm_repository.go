@@ -0,0 +1,63 @@
+package listcontestmembers
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) DoesContestExist(ctx context.Context, contestID uuid.UUID) (bool, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var count int64
+	if err := db.WithContext(ctx).
+		Model(&database.Contest{}).
+		Where("contest_id = ?", contestID).
+		Count(&count).Error; err != nil {
+		return false, errors.WrapIf(err, "failed to check if contest exists")
+	}
+
+	return count > 0, nil
+}
+
+func (r *GormRepository) GetMembers(ctx context.Context, contestID uuid.UUID) ([]Member, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var memberships []database.ContestMembership
+	if err := db.WithContext(ctx).
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("user_id", "username")
+		}).
+		Where("contest_id = ?", contestID).
+		Order("created_at ASC").
+		Find(&memberships).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get contest memberships")
+	}
+
+	members := make([]Member, 0, len(memberships))
+	for _, membership := range memberships {
+		members = append(members, Member{
+			UserID:    membership.UserID,
+			Username:  membership.User.Username,
+			Role:      constant.FromStringToContestMemberRole(membership.Role),
+			CreatedAt: membership.CreatedAt,
+		})
+	}
+
+	return members, nil
+}
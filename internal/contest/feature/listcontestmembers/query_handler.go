@@ -0,0 +1,73 @@
+package listcontestmembers
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+var ErrContestNotFound = errors.New("contest not found")
+
+type Repository interface {
+	DoesContestExist(ctx context.Context, contestID uuid.UUID) (bool, error)
+	GetMembers(ctx context.Context, contestID uuid.UUID) ([]Member, error)
+}
+
+type QueryHandler struct {
+	repo         Repository
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+}
+
+func NewQueryHandler(
+	repo Repository,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+) *QueryHandler {
+	return &QueryHandler{
+		repo:         repo,
+		validator:    validator,
+		authProvider: authProvider,
+	}
+}
+
+func (h *QueryHandler) Handle(ctx context.Context, query *Query) (*Response, error) {
+	if query == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(query); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	if can, err := h.authProvider.CanInContest(
+		ctx,
+		query.ContestID,
+		constant.PermissionContestManageMembersAny,
+	); err != nil {
+		return nil, errors.WrapIf(err, "failed to check permission")
+	} else if !can {
+		return nil, customerror.NewNoPermissionError(constant.PermissionContestManageMembersAny)
+	}
+
+	if ok, err := h.repo.DoesContestExist(ctx, query.ContestID); err != nil {
+		return nil, errors.WrapIf(err, "failed to check if contest exists")
+	} else if !ok {
+		return nil, errors.WithStack(ErrContestNotFound)
+	}
+
+	members, err := h.repo.GetMembers(ctx, query.ContestID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get contest members")
+	}
+
+	return &Response{
+		Members: members,
+	}, nil
+}
@@ -0,0 +1,20 @@
+package listcontestmembers
+
+import (
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
+	"github.com/google/uuid"
+)
+
+type Member struct {
+	UserID    uuid.UUID                  `json:"user_id"`
+	Username  string                     `json:"username"`
+	Role      constant.ContestMemberRole `json:"role"`
+	CreatedAt time.Time                  `json:"created_at"`
+}
+
+type Response struct {
+	Members []Member `json:"members"`
+}
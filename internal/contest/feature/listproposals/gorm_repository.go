@@ -0,0 +1,124 @@
+package listproposals
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) DoesContestExist(ctx context.Context, contestID uuid.UUID) (bool, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var count int64
+	if err := db.WithContext(ctx).
+		Model(&database.Contest{}).
+		Where("contest_id = ?", contestID).
+		Count(&count).Error; err != nil {
+		return false, errors.WrapIf(err, "failed to check if contest exists")
+	}
+
+	return count > 0, nil
+}
+
+func (r *GormRepository) GetProposals(ctx context.Context, contestID uuid.UUID) ([]Proposal, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var problems []database.Problem
+	if err := db.WithContext(ctx).
+		Preload("Creator", func(db *gorm.DB) *gorm.DB {
+			return db.Select("user_id", "username")
+		}).
+		Where("target_contest_id = ?", contestID).
+		Where(
+			"NOT EXISTS (SELECT 1 FROM contest_problems cp WHERE cp.problem_id = problems.problem_id AND cp.contest_id = ?)",
+			contestID,
+		).
+		Order("created_at ASC").
+		Find(&problems).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get proposed problems")
+	}
+
+	if len(problems) == 0 {
+		return []Proposal{}, nil
+	}
+
+	problemIDs := make([]uuid.UUID, 0, len(problems))
+	for _, p := range problems {
+		problemIDs = append(problemIDs, p.ProblemID)
+	}
+
+	var versions []database.ProblemVersion
+	if err := db.WithContext(ctx).
+		Select("problem_version_id", "problem_id", "problem_difficulty_id", "created_at").
+		Preload("Details", func(db *gorm.DB) *gorm.DB {
+			return db.Select("detail_id", "problem_version_id", "language", "title")
+		}).
+		Preload("ProblemDifficulty.DisplayNames").
+		Where("problem_id IN ?", problemIDs).
+		Order("created_at DESC").
+		Find(&versions).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get problem versions")
+	}
+
+	// Versions are ordered newest first, so the first one seen for a problem
+	// is its latest.
+	latestVersions := make(map[uuid.UUID]database.ProblemVersion, len(problems))
+	for _, v := range versions {
+		if _, ok := latestVersions[v.ProblemID]; !ok {
+			latestVersions[v.ProblemID] = v
+		}
+	}
+
+	proposals := make([]Proposal, 0, len(problems))
+	for _, p := range problems {
+		version := latestVersions[p.ProblemID]
+
+		titles := make([]ProblemDetailTitle, 0, len(version.Details))
+		for _, d := range version.Details {
+			titles = append(titles, ProblemDetailTitle{
+				Language: d.Language,
+				Title:    d.Title,
+			})
+		}
+
+		displayNames := make([]ProblemDifficultyDisplayName, 0, len(version.ProblemDifficulty.DisplayNames))
+		for _, n := range version.ProblemDifficulty.DisplayNames {
+			displayNames = append(displayNames, ProblemDifficultyDisplayName{
+				Language:    n.Language,
+				DisplayName: n.DisplayName,
+			})
+		}
+
+		proposals = append(proposals, Proposal{
+			ProblemID: p.ProblemID,
+			Title:     titles,
+			Status:    p.Status,
+			ProblemDifficulty: ProblemDifficulty{
+				ProblemDifficultyID: version.ProblemDifficultyID,
+				DisplayNames:        displayNames,
+			},
+			Creator: Creator{
+				UserID:   p.Creator.UserID,
+				Username: p.Creator.Username,
+			},
+			CreatedAt: p.CreatedAt,
+			UpdatedAt: p.UpdatedAt,
+		})
+	}
+
+	return proposals, nil
+}
@@ -0,0 +1,37 @@
+package listproposals
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Proposal struct {
+	ProblemID         uuid.UUID            `json:"problem_id"`
+	Title             []ProblemDetailTitle `json:"title"`
+	Status            string               `json:"status"`
+	ProblemDifficulty ProblemDifficulty    `json:"problem_difficulty"`
+	Creator           Creator              `json:"creator"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+}
+
+type ProblemDetailTitle struct {
+	Language string `json:"language"`
+	Title    string `json:"title"`
+}
+
+type ProblemDifficulty struct {
+	ProblemDifficultyID uuid.UUID                      `json:"problem_difficulty_id"`
+	DisplayNames        []ProblemDifficultyDisplayName `json:"display_names"`
+}
+
+type ProblemDifficultyDisplayName struct {
+	Language    string `json:"language"`
+	DisplayName string `json:"display_name"`
+}
+
+type Creator struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+}
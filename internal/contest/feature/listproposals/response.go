@@ -0,0 +1,5 @@
+package listproposals
+
+type Response struct {
+	Proposals []Proposal `json:"proposals"`
+}
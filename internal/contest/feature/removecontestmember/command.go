@@ -0,0 +1,8 @@
+package removecontestmember
+
+import "github.com/google/uuid"
+
+type Command struct {
+	ContestID uuid.UUID `param:"contest_id" validate:"required"`
+	UserID    uuid.UUID `param:"user_id"    validate:"required"`
+}
@@ -0,0 +1,70 @@
+package removecontestmember
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+var ErrMemberNotFound = errors.New("user is not a member of the contest")
+
+type Repository interface {
+	DeleteMember(ctx context.Context, contestID uuid.UUID, userID uuid.UUID) (bool, error)
+}
+
+type CommandHandler struct {
+	repo         Repository
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+	revoker      contract.RoomAccessRevoker
+}
+
+func NewCommandHandler(
+	repo Repository,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+	revoker contract.RoomAccessRevoker,
+) *CommandHandler {
+	return &CommandHandler{
+		repo:         repo,
+		validator:    validator,
+		authProvider: authProvider,
+		revoker:      revoker,
+	}
+}
+
+func (h *CommandHandler) Handle(ctx context.Context, command *Command) error {
+	if command == nil {
+		return errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(command); err != nil {
+		return errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	if can, err := h.authProvider.CanInContest(
+		ctx,
+		command.ContestID,
+		constant.PermissionContestManageMembersAny,
+	); err != nil {
+		return errors.WrapIf(err, "failed to check permission")
+	} else if !can {
+		return customerror.NewNoPermissionError(constant.PermissionContestManageMembersAny)
+	}
+
+	if ok, err := h.repo.DeleteMember(ctx, command.ContestID, command.UserID); err != nil {
+		return errors.WrapIf(err, "failed to delete contest member")
+	} else if !ok {
+		return errors.WithStack(ErrMemberNotFound)
+	}
+
+	h.revoker.RecheckUserRooms(command.UserID)
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package removecontestmember
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) DeleteMember(ctx context.Context, contestID uuid.UUID, userID uuid.UUID) (bool, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	result := db.WithContext(ctx).
+		Where("contest_id = ? AND user_id = ?", contestID, userID).
+		Delete(&database.ContestMembership{})
+	if result.Error != nil {
+		return false, errors.WrapIf(result.Error, "failed to delete contest membership")
+	}
+
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,8 @@
+package reorderproblems
+
+import "github.com/google/uuid"
+
+type Command struct {
+	ContestID  uuid.UUID   `param:"contest_id"  validate:"required"`
+	ProblemIDs []uuid.UUID `json:"problem_ids" validate:"required,dive,required"`
+}
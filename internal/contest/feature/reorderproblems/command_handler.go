@@ -0,0 +1,138 @@
+package reorderproblems
+
+import (
+	"context"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrContestNotFound = errors.New("contest not found")
+	ErrOrderMismatch   = errors.New("the order must list every assigned problem exactly once")
+)
+
+type Repository interface {
+	// LockContest serializes the changes to the contest's problem set and
+	// reports whether the contest exists.
+	LockContest(ctx context.Context, contestID uuid.UUID) (bool, error)
+	GetContestLifecycle(ctx context.Context, contestID uuid.UUID) (constant.ContestPhase, time.Time, error)
+	GetAssignedProblemIDs(ctx context.Context, contestID uuid.UUID) ([]uuid.UUID, error)
+	// SetProblemPositions moves every problem of the contest to its new
+	// position.
+	SetProblemPositions(ctx context.Context, contestID uuid.UUID, problems []ContestProblem) error
+}
+
+type CommandHandler struct {
+	repo         Repository
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+	uowFactory   contract.UnitOfWorkFactory
+	l            logger.Logger
+}
+
+func NewCommandHandler(
+	repo Repository,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+	uowFactory contract.UnitOfWorkFactory,
+	l logger.Logger,
+) *CommandHandler {
+	return &CommandHandler{
+		repo:         repo,
+		validator:    validator,
+		authProvider: authProvider,
+		uowFactory:   uowFactory,
+		l:            l,
+	}
+}
+
+func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Response, error) {
+	if command == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(command); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	if can, err := h.authProvider.Can(ctx, constant.PermissionContestUpdateAny); err != nil {
+		return nil, errors.WrapIf(err, "failed to check permission")
+	} else if !can {
+		return nil, customerror.NewNoPermissionError(constant.PermissionContestUpdateAny)
+	}
+
+	uow := h.uowFactory.New()
+	return uowhelper.DoWithResult(ctx, uow, h.l, func(ctx context.Context) (*Response, error) {
+		if ok, err := h.repo.LockContest(ctx, command.ContestID); err != nil {
+			return nil, errors.WrapIf(err, "failed to lock contest")
+		} else if !ok {
+			return nil, errors.WithStack(ErrContestNotFound)
+		}
+
+		if phase, deadline, err := h.repo.GetContestLifecycle(ctx, command.ContestID); err != nil {
+			return nil, errors.WrapIf(err, "failed to get contest lifecycle")
+		} else if contest.IsProblemSetLocked(phase, deadline, time.Now()) {
+			return nil, errors.WithStack(contest.ErrProblemSetLocked)
+		}
+
+		assigned, err := h.repo.GetAssignedProblemIDs(ctx, command.ContestID)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to get assigned problems")
+		}
+
+		if !isPermutation(command.ProblemIDs, assigned) {
+			return nil, errors.WithStack(ErrOrderMismatch)
+		}
+
+		problems := make([]ContestProblem, len(command.ProblemIDs))
+		for i, problemID := range command.ProblemIDs {
+			position := uint(i + 1)
+			problems[i] = ContestProblem{
+				ProblemID: problemID,
+				Position:  position,
+				Label:     contest.Label(position),
+			}
+		}
+
+		if err := h.repo.SetProblemPositions(ctx, command.ContestID, problems); err != nil {
+			return nil, errors.WrapIf(err, "failed to set problem positions")
+		}
+
+		return &Response{
+			Problems: problems,
+		}, nil
+	})
+}
+
+// isPermutation reports whether order holds the same problems as assigned,
+// each exactly once.
+func isPermutation(order []uuid.UUID, assigned []uuid.UUID) bool {
+	if len(order) != len(assigned) {
+		return false
+	}
+
+	remaining := make(map[uuid.UUID]struct{}, len(assigned))
+	for _, id := range assigned {
+		remaining[id] = struct{}{}
+	}
+
+	for _, id := range order {
+		if _, ok := remaining[id]; !ok {
+			return false
+		}
+
+		delete(remaining, id)
+	}
+
+	return true
+}
@@ -0,0 +1,109 @@
+package reorderproblems
+
+import (
+	"context"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+// LockContest locks the row of the contest until the unit of work ends, so
+// that changes to its problem set run one at a time, and reports whether the
+// contest exists.
+func (r *GormRepository) LockContest(ctx context.Context, contestID uuid.UUID) (bool, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var contestIDs []uuid.UUID
+	if err := db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Model(&database.Contest{}).
+		Where("contest_id = ?", contestID).
+		Pluck("contest_id", &contestIDs).Error; err != nil {
+		return false, errors.WrapIf(err, "failed to lock contest")
+	}
+
+	return len(contestIDs) > 0, nil
+}
+
+func (r *GormRepository) GetContestLifecycle(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (constant.ContestPhase, time.Time, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var contest database.Contest
+	if err := db.WithContext(ctx).
+		Select("phase", "deadline_datetime").
+		Where("contest_id = ?", contestID).
+		First(&contest).Error; err != nil {
+		return "", time.Time{}, errors.WrapIf(err, "failed to get contest lifecycle")
+	}
+
+	return constant.FromStringToContestPhase(contest.Phase), contest.DeadlineDatetime, nil
+}
+
+func (r *GormRepository) GetAssignedProblemIDs(ctx context.Context, contestID uuid.UUID) ([]uuid.UUID, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var problemIDs []uuid.UUID
+	if err := db.WithContext(ctx).
+		Model(&database.ContestProblem{}).
+		Where("contest_id = ?", contestID).
+		Pluck("problem_id", &problemIDs).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get assigned problem ids")
+	}
+
+	return problemIDs, nil
+}
+
+// SetProblemPositions first moves the problems past the end of the contest,
+// as positions are unique within it, and then to where they belong.
+func (r *GormRepository) SetProblemPositions(ctx context.Context, contestID uuid.UUID, problems []ContestProblem) error {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var lastPosition uint
+	if err := db.WithContext(ctx).
+		Model(&database.ContestProblem{}).
+		Select("COALESCE(MAX(position), 0)").
+		Where("contest_id = ?", contestID).
+		Scan(&lastPosition).Error; err != nil {
+		return errors.WrapIf(err, "failed to get last problem position")
+	}
+
+	if err := db.WithContext(ctx).
+		Model(&database.ContestProblem{}).
+		Where("contest_id = ?", contestID).
+		Update("position", gorm.Expr("position + ?", lastPosition)).Error; err != nil {
+		return errors.WrapIf(err, "failed to move contest problems")
+	}
+
+	for _, problem := range problems {
+		if err := db.WithContext(ctx).
+			Model(&database.ContestProblem{}).
+			Where("contest_id = ? AND problem_id = ?", contestID, problem.ProblemID).
+			Updates(map[string]any{
+				"position": problem.Position,
+				"label":    problem.Label,
+			}).Error; err != nil {
+			return errors.WrapIf(err, "failed to update contest problem position")
+		}
+	}
+
+	return nil
+}
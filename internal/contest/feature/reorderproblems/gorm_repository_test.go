@@ -0,0 +1,72 @@
+package reorderproblems
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestSetProblemPositions(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{DisableForeignKeyConstraintWhenMigrating: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every connection to an in-memory database gets a database of its own.
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(&database.ContestProblem{}); err != nil {
+		t.Fatal(err)
+	}
+
+	contestID := uuid.New()
+	problemIDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for i, problemID := range problemIDs {
+		position := uint(i + 1)
+		if err := db.Create(&database.ContestProblem{
+			ContestID: contestID,
+			ProblemID: problemID,
+			Position:  position,
+			Label:     contest.Label(position),
+			CreatedAt: time.Now(),
+		}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := db.Create(&database.ContestProblem{ContestID: contestID, ProblemID: uuid.New(), Position: 1}).Error; err == nil {
+		t.Fatal("Create() succeeded with a taken position, want positions to be unique")
+	}
+
+	// Reversed, so that every problem takes the position of another.
+	problems := []ContestProblem{
+		{ProblemID: problemIDs[2], Position: 1, Label: "A"},
+		{ProblemID: problemIDs[1], Position: 2, Label: "B"},
+		{ProblemID: problemIDs[0], Position: 3, Label: "C"},
+	}
+	if err := NewGormRepository(db).SetProblemPositions(context.Background(), contestID, problems); err != nil {
+		t.Fatalf("SetProblemPositions() error = %v", err)
+	}
+
+	for _, want := range problems {
+		var got database.ContestProblem
+		if err := db.Where("contest_id = ? AND problem_id = ?", contestID, want.ProblemID).First(&got).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		if got.Position != want.Position || got.Label != want.Label {
+			t.Errorf("problem %s at %d (%s), want %d (%s)", want.ProblemID, got.Position, got.Label, want.Position, want.Label)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package reorderproblems
+
+import "github.com/google/uuid"
+
+type ContestProblem struct {
+	ProblemID uuid.UUID `json:"problem_id"`
+	Position  uint      `json:"position"`
+	Label     string    `json:"label"`
+}
+
+type Response struct {
+	Problems []ContestProblem `json:"problems"`
+}
@@ -0,0 +1,15 @@
+package setcontestmember
+
+import (
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
+	"github.com/google/uuid"
+)
+
+// Command adds the user to the contest's staff, or changes their role if they
+// are already part of it.
+type Command struct {
+	ContestID uuid.UUID                  `param:"contest_id" validate:"required"`
+	UserID    uuid.UUID                  `param:"user_id"    validate:"required"`
+	Role      constant.ContestMemberRole `json:"role"        validate:"required,contest_member_role"`
+}
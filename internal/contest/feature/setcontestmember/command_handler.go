@@ -0,0 +1,118 @@
+package setcontestmember
+
+import (
+	"context"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrContestNotFound = errors.New("contest not found")
+	ErrUserNotFound    = errors.New("user not found")
+)
+
+type Repository interface {
+	DoesContestExist(ctx context.Context, contestID uuid.UUID) (bool, error)
+	GetUsername(ctx context.Context, userID uuid.UUID) (*string, error)
+	UpsertMember(
+		ctx context.Context,
+		contestID uuid.UUID,
+		userID uuid.UUID,
+		role constant.ContestMemberRole,
+		updatedAt time.Time,
+	) (time.Time, error)
+}
+
+type CommandHandler struct {
+	repo         Repository
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+	revoker      contract.RoomAccessRevoker
+	uowFactory   contract.UnitOfWorkFactory
+	l            logger.Logger
+}
+
+func NewCommandHandler(
+	repo Repository,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+	revoker contract.RoomAccessRevoker,
+	uowFactory contract.UnitOfWorkFactory,
+	l logger.Logger,
+) *CommandHandler {
+	return &CommandHandler{
+		repo:         repo,
+		validator:    validator,
+		authProvider: authProvider,
+		revoker:      revoker,
+		uowFactory:   uowFactory,
+		l:            l,
+	}
+}
+
+func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Response, error) {
+	if command == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(command); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	if can, err := h.authProvider.CanInContest(
+		ctx,
+		command.ContestID,
+		constant.PermissionContestManageMembersAny,
+	); err != nil {
+		return nil, errors.WrapIf(err, "failed to check permission")
+	} else if !can {
+		return nil, customerror.NewNoPermissionError(constant.PermissionContestManageMembersAny)
+	}
+
+	uow := h.uowFactory.New()
+	response, err := uowhelper.DoWithResult(ctx, uow, h.l, func(ctx context.Context) (*Response, error) {
+		if ok, err := h.repo.DoesContestExist(ctx, command.ContestID); err != nil {
+			return nil, errors.WrapIf(err, "failed to check if contest exists")
+		} else if !ok {
+			return nil, errors.WithStack(ErrContestNotFound)
+		}
+
+		username, err := h.repo.GetUsername(ctx, command.UserID)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to get user")
+		} else if username == nil {
+			return nil, errors.WithStack(ErrUserNotFound)
+		}
+
+		createdAt, err := h.repo.UpsertMember(ctx, command.ContestID, command.UserID, command.Role, time.Now())
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to upsert contest member")
+		}
+
+		return &Response{
+			Member: Member{
+				UserID:    command.UserID,
+				Username:  *username,
+				Role:      command.Role,
+				CreatedAt: createdAt,
+			},
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// A lower role may no longer let the member read the contest's problems.
+	h.revoker.RecheckUserRooms(command.UserID)
+
+	return response, nil
+}
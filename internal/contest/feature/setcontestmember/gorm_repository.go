@@ -0,0 +1,100 @@
+package setcontestmember
+
+import (
+	"context"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) DoesContestExist(ctx context.Context, contestID uuid.UUID) (bool, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var count int64
+	if err := db.WithContext(ctx).
+		Model(&database.Contest{}).
+		Where("contest_id = ?", contestID).
+		Count(&count).Error; err != nil {
+		return false, errors.WrapIf(err, "failed to check if contest exists")
+	}
+
+	return count > 0, nil
+}
+
+func (r *GormRepository) GetUsername(ctx context.Context, userID uuid.UUID) (*string, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var user database.User
+	if err := db.WithContext(ctx).
+		Select("username").
+		Where("user_id = ?", userID).
+		First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, errors.WrapIf(err, "failed to get user")
+	}
+
+	return &user.Username, nil
+}
+
+func (r *GormRepository) UpsertMember(
+	ctx context.Context,
+	contestID uuid.UUID,
+	userID uuid.UUID,
+	role constant.ContestMemberRole,
+	updatedAt time.Time,
+) (time.Time, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var membership database.ContestMembership
+	if err := db.WithContext(ctx).
+		Where("contest_id = ? AND user_id = ?", contestID, userID).
+		First(&membership).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return time.Time{}, errors.WrapIf(err, "failed to get contest membership")
+		}
+
+		membership = database.ContestMembership{
+			ContestID: contestID,
+			UserID:    userID,
+			Role:      string(role),
+			CreatedAt: updatedAt,
+			UpdatedAt: updatedAt,
+		}
+
+		if err := db.WithContext(ctx).Create(&membership).Error; err != nil {
+			return time.Time{}, errors.WrapIf(err, "failed to create contest membership")
+		}
+
+		return membership.CreatedAt, nil
+	}
+
+	if err := db.WithContext(ctx).
+		Model(&database.ContestMembership{}).
+		Where("contest_id = ? AND user_id = ?", contestID, userID).
+		Updates(map[string]any{
+			"role":       string(role),
+			"updated_at": updatedAt,
+		}).Error; err != nil {
+		return time.Time{}, errors.WrapIf(err, "failed to update contest membership")
+	}
+
+	return membership.CreatedAt, nil
+}
@@ -2,7 +2,10 @@ package unassignproblem
 
 import (
 	"context"
+	"time"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
@@ -14,21 +17,29 @@ import (
 )
 
 var (
-	ErrProblemNotFound = errors.New("problem not found")
-	ErrContestNotFound = errors.New("contest not found")
+	ErrProblemNotFound    = errors.New("problem not found")
+	ErrContestNotFound    = errors.New("contest not found")
+	ErrProblemNotAssigned = errors.New("problem is not assigned to the contest")
 )
 
 type Repository interface {
-	DoesContestExist(ctx context.Context, contestID uuid.UUID) (bool, error)
+	// LockContest serializes the changes to the contest's problem set and
+	// reports whether the contest exists.
+	LockContest(ctx context.Context, contestID uuid.UUID) (bool, error)
+	GetContestLifecycle(ctx context.Context, contestID uuid.UUID) (constant.ContestPhase, time.Time, error)
 	DoesProblemExist(ctx context.Context, problemID uuid.UUID) (bool, error)
-	UnassignProblemFromContest(ctx context.Context, problemID uuid.UUID) error
+	// UnassignProblemFromContest reports false when the problem is not
+	// assigned to the contest. The problems after it move up one position.
+	UnassignProblemFromContest(ctx context.Context, problemID uuid.UUID, contestID uuid.UUID) (bool, error)
 }
 
 type CommandHandler struct {
 	repo         Repository
 	validator    *validator.Validate
 	authProvider contract.AuthProvider
+	revoker      contract.RoomAccessRevoker
 	uowFactory   contract.UnitOfWorkFactory
+	broadcaster  contract.MessageBroadcaster
 	l            logger.Logger
 }
 
@@ -36,14 +47,18 @@ func NewCommandHandler(
 	repo Repository,
 	validator *validator.Validate,
 	authProvider contract.AuthProvider,
+	revoker contract.RoomAccessRevoker,
 	uowFactory contract.UnitOfWorkFactory,
+	broadcaster contract.MessageBroadcaster,
 	l logger.Logger,
 ) *CommandHandler {
 	return &CommandHandler{
 		repo:         repo,
 		validator:    validator,
 		authProvider: authProvider,
+		revoker:      revoker,
 		uowFactory:   uowFactory,
+		broadcaster:  broadcaster,
 		l:            l,
 	}
 }
@@ -57,31 +72,61 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) error {
 		return errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
 	}
 
-	_, err := h.authProvider.MustGetUser(ctx)
+	user, err := h.authProvider.MustGetUser(ctx)
 	if err != nil {
 		return errors.WrapIf(err, "failed to get user from auth provider")
 	}
 
-	// TODO: check if the current user has permissions or not
+	if can, err := h.authProvider.CanInContest(ctx, command.ContestID, constant.PermissionContestUnassignProblemAny); err != nil {
+		return errors.WrapIf(err, "failed to check permission")
+	} else if !can {
+		return customerror.NewNoPermissionError(constant.PermissionContestUnassignProblemAny)
+	}
 
 	uow := h.uowFactory.New()
-	return uowhelper.Do(ctx, uow, h.l, func(ctx context.Context) error {
-		if ok, err := h.repo.DoesContestExist(ctx, command.ContestID); err != nil {
-			return errors.WrapIf(err, "failed to check if contest exists")
+	if err := uowhelper.Do(ctx, uow, h.l, func(ctx context.Context) error {
+		if ok, err := h.repo.LockContest(ctx, command.ContestID); err != nil {
+			return errors.WrapIf(err, "failed to lock contest")
 		} else if !ok {
 			return errors.WithStack(ErrContestNotFound)
 		}
 
+		if phase, deadline, err := h.repo.GetContestLifecycle(ctx, command.ContestID); err != nil {
+			return errors.WrapIf(err, "failed to get contest lifecycle")
+		} else if contest.IsProblemSetLocked(phase, deadline, time.Now()) {
+			return errors.WithStack(contest.ErrProblemSetLocked)
+		}
+
 		if ok, err := h.repo.DoesProblemExist(ctx, command.ProblemID); err != nil {
 			return errors.WrapIf(err, "failed to check if problem exists")
 		} else if !ok {
 			return errors.WithStack(ErrProblemNotFound)
 		}
 
-		if err := h.repo.UnassignProblemFromContest(ctx, command.ProblemID); err != nil {
+		if ok, err := h.repo.UnassignProblemFromContest(ctx, command.ProblemID, command.ContestID); err != nil {
 			return errors.WrapIf(err, "failed to unassign problem from contest")
+		} else if !ok {
+			return errors.WithStack(ErrProblemNotAssigned)
+		}
+
+		details, err := h.authProvider.MustGetUserDetails(ctx, user.UserID)
+		if err != nil {
+			return errors.WrapIf(err, "failed to get user details")
+		}
+
+		if err := h.broadcaster.BroadcastProblemUnassignedMessage(ctx, command.ContestID, command.ProblemID, contract.MessageUser{
+			UserID:   user.UserID,
+			Username: details.Username,
+		}, time.Now()); err != nil {
+			return errors.WrapIf(err, "failed to broadcast problem unassigned message")
 		}
 
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	h.revoker.RecheckProblemRoom(command.ProblemID)
+
+	return nil
 }
@@ -2,12 +2,16 @@ package unassignproblem
 
 import (
 	"context"
+	"time"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
 
 	"emperror.dev/errors"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type GormRepository struct {
@@ -18,18 +22,39 @@ func NewGormRepository(db *gorm.DB) *GormRepository {
 	return &GormRepository{db: db}
 }
 
-func (r *GormRepository) DoesContestExist(ctx context.Context, contestID uuid.UUID) (bool, error) {
+// LockContest locks the row of the contest until the unit of work ends, so
+// that changes to its problem set run one at a time, and reports whether the
+// contest exists.
+func (r *GormRepository) LockContest(ctx context.Context, contestID uuid.UUID) (bool, error) {
 	db := database.GetDBFromContext(ctx, r.db)
 
-	var count int64
+	var contestIDs []uuid.UUID
 	if err := db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
 		Model(&database.Contest{}).
 		Where("contest_id = ?", contestID).
-		Count(&count).Error; err != nil {
-		return false, errors.WrapIf(err, "failed to check if contest exists")
+		Pluck("contest_id", &contestIDs).Error; err != nil {
+		return false, errors.WrapIf(err, "failed to lock contest")
 	}
 
-	return count > 0, nil
+	return len(contestIDs) > 0, nil
+}
+
+func (r *GormRepository) GetContestLifecycle(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (constant.ContestPhase, time.Time, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var contest database.Contest
+	if err := db.WithContext(ctx).
+		Select("phase", "deadline_datetime").
+		Where("contest_id = ?", contestID).
+		First(&contest).Error; err != nil {
+		return "", time.Time{}, errors.WrapIf(err, "failed to get contest lifecycle")
+	}
+
+	return constant.FromStringToContestPhase(contest.Phase), contest.DeadlineDatetime, nil
 }
 
 func (r *GormRepository) DoesProblemExist(ctx context.Context, problemID uuid.UUID) (bool, error) {
@@ -46,16 +71,47 @@ func (r *GormRepository) DoesProblemExist(ctx context.Context, problemID uuid.UU
 	return count > 0, nil
 }
 
-func (r *GormRepository) UnassignProblemFromContest(ctx context.Context, problemID uuid.UUID) error {
+func (r *GormRepository) UnassignProblemFromContest(
+	ctx context.Context,
+	problemID uuid.UUID,
+	contestID uuid.UUID,
+) (bool, error) {
 	db := database.GetDBFromContext(ctx, r.db)
 
-	problem := database.Problem{ProblemID: problemID}
+	result := db.WithContext(ctx).
+		Where("contest_id = ? AND problem_id = ?", contestID, problemID).
+		Delete(&database.ContestProblem{})
+	if result.Error != nil {
+		return false, errors.WrapIf(result.Error, "failed to delete contest problem")
+	} else if result.RowsAffected == 0 {
+		return false, nil
+	}
 
+	var remaining []database.ContestProblem
 	if err := db.WithContext(ctx).
-		Model(&problem).
-		Update("assigned_contest_id", nil).Error; err != nil {
-		return errors.WrapIf(err, "failed to reset assigned contest id")
+		Select("problem_id", "position").
+		Where("contest_id = ?", contestID).
+		Order("position ASC").
+		Find(&remaining).Error; err != nil {
+		return false, errors.WrapIf(err, "failed to get remaining contest problems")
+	}
+
+	for i, p := range remaining {
+		position := uint(i + 1)
+		if p.Position == position {
+			continue
+		}
+
+		if err := db.WithContext(ctx).
+			Model(&database.ContestProblem{}).
+			Where("contest_id = ? AND problem_id = ?", contestID, p.ProblemID).
+			Updates(map[string]any{
+				"position": position,
+				"label":    contest.Label(position),
+			}).Error; err != nil {
+			return false, errors.WrapIf(err, "failed to renumber contest problems")
+		}
 	}
 
-	return nil
+	return true, nil
 }
@@ -0,0 +1,60 @@
+package updatecontest
+
+import (
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
+	"github.com/google/uuid"
+)
+
+// Command changes the fields of a contest that are set. A replacing command
+// also clears the schedule times that are not set.
+type Command struct {
+	ContestID        uuid.UUID              `param:"contest_id"         validate:"required"`
+	Title            *string                `json:"title"               validate:"omitempty,min=1"`
+	Description      *string                `json:"description"         validate:"omitempty,min=1"`
+	MinProblemCount  *uint                  `json:"min_problem_count"   validate:"omitempty,gte=1"`
+	MaxProblemCount  *uint                  `json:"max_problem_count"   validate:"omitempty,gte=1"`
+	MaxTimeLimitMs   *uint                  `json:"max_time_limit_ms"   validate:"omitempty,eq=0|min=100,max=60000"`
+	MaxMemoryLimitMb *uint                  `json:"max_memory_limit_mb" validate:"omitempty,eq=0|min=16,max=4096"`
+	DeadlineDatetime *time.Time             `json:"deadline_datetime"`
+	StartDatetime    *time.Time             `json:"start_datetime"`
+	EndDatetime      *time.Time             `json:"end_datetime"`
+	Phase            *constant.ContestPhase `json:"phase"               validate:"omitempty,contest_phase"`
+
+	replace bool
+}
+
+// ReplaceCommand is the body of a PUT request, which sets every field of a
+// contest.
+type ReplaceCommand struct {
+	ContestID        uuid.UUID             `param:"contest_id"         validate:"required"`
+	Title            string                `json:"title"               validate:"required"`
+	Description      string                `json:"description"         validate:"required"`
+	MinProblemCount  uint                  `json:"min_problem_count"   validate:"required,gte=1"`
+	MaxProblemCount  uint                  `json:"max_problem_count"   validate:"required,gte=1"`
+	MaxTimeLimitMs   uint                  `json:"max_time_limit_ms"   validate:"omitempty,min=100,max=60000"`
+	MaxMemoryLimitMb uint                  `json:"max_memory_limit_mb" validate:"omitempty,min=16,max=4096"`
+	DeadlineDatetime time.Time             `json:"deadline_datetime"   validate:"required"`
+	StartDatetime    *time.Time            `json:"start_datetime"`
+	EndDatetime      *time.Time            `json:"end_datetime"`
+	Phase            constant.ContestPhase `json:"phase"               validate:"required,contest_phase"`
+}
+
+func (c *ReplaceCommand) ToCommand() *Command {
+	return &Command{
+		ContestID:        c.ContestID,
+		Title:            &c.Title,
+		Description:      &c.Description,
+		MinProblemCount:  &c.MinProblemCount,
+		MaxProblemCount:  &c.MaxProblemCount,
+		MaxTimeLimitMs:   &c.MaxTimeLimitMs,
+		MaxMemoryLimitMb: &c.MaxMemoryLimitMb,
+		DeadlineDatetime: &c.DeadlineDatetime,
+		StartDatetime:    c.StartDatetime,
+		EndDatetime:      c.EndDatetime,
+		Phase:            &c.Phase,
+		replace:          true,
+	}
+}
@@ -0,0 +1,193 @@
+package updatecontest
+
+import (
+	"context"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrContestNotFound          = errors.New("contest not found")
+	ErrContestArchived          = errors.New("archived contests cannot be changed")
+	ErrProblemCountRangeFlipped = errors.New("minProblemCount cannot be greater than maxProblemCount")
+	ErrInvalidDeadlineDatetime  = errors.New("deadline datetime must be in the future")
+	ErrInvalidSchedule          = errors.New("contest must start before it ends")
+	ErrMaxBelowAssignedCount    = errors.New("maxProblemCount cannot be lower than the number of assigned problems")
+	ErrNotEnoughProblems        = errors.New("contest has fewer problems than minProblemCount")
+)
+
+type Repository interface {
+	GetContest(ctx context.Context, contestID uuid.UUID) (*Contest, error)
+	CountAssignedProblems(ctx context.Context, contestID uuid.UUID) (uint, error)
+	UpdateContest(ctx context.Context, contest Contest) error
+}
+
+type CommandHandler struct {
+	repo         Repository
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+	uowFactory   contract.UnitOfWorkFactory
+	l            logger.Logger
+}
+
+func NewCommandHandler(
+	repo Repository,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+	uowFactory contract.UnitOfWorkFactory,
+	l logger.Logger,
+) *CommandHandler {
+	return &CommandHandler{
+		repo:         repo,
+		validator:    validator,
+		authProvider: authProvider,
+		uowFactory:   uowFactory,
+		l:            l,
+	}
+}
+
+func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Response, error) {
+	if command == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(command); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	if can, err := h.authProvider.Can(ctx, constant.PermissionContestUpdateAny); err != nil {
+		return nil, errors.WrapIf(err, "failed to check permission")
+	} else if !can {
+		return nil, customerror.NewNoPermissionError(constant.PermissionContestUpdateAny)
+	}
+
+	uow := h.uowFactory.New()
+	return uowhelper.DoWithResult(ctx, uow, h.l, func(ctx context.Context) (*Response, error) {
+		current, err := h.repo.GetContest(ctx, command.ContestID)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to get contest")
+		}
+
+		if current.Phase == constant.ContestPhaseArchived {
+			return nil, errors.WithStack(ErrContestArchived)
+		}
+
+		updated, err := apply(*current, command, time.Now())
+		if err != nil {
+			return nil, err
+		}
+
+		count, err := h.repo.CountAssignedProblems(ctx, command.ContestID)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to count assigned problems")
+		}
+
+		if err := checkProblemCount(*current, updated, count); err != nil {
+			return nil, err
+		}
+
+		if err := h.repo.UpdateContest(ctx, updated); err != nil {
+			return nil, errors.WrapIf(err, "failed to update contest")
+		}
+
+		return &Response{
+			Contest: updated,
+		}, nil
+	})
+}
+
+// apply returns the contest with the changes of the command, checking that
+// the result is consistent.
+func apply(c Contest, command *Command, now time.Time) (Contest, error) {
+	if command.Title != nil {
+		c.Title = *command.Title
+	}
+
+	if command.Description != nil {
+		c.Description = *command.Description
+	}
+
+	if command.MinProblemCount != nil {
+		c.MinProblemCount = *command.MinProblemCount
+	}
+
+	if command.MaxProblemCount != nil {
+		c.MaxProblemCount = *command.MaxProblemCount
+	}
+
+	if command.MaxTimeLimitMs != nil {
+		c.MaxTimeLimitMs = *command.MaxTimeLimitMs
+	}
+
+	if command.MaxMemoryLimitMb != nil {
+		c.MaxMemoryLimitMb = *command.MaxMemoryLimitMb
+	}
+
+	// Only a new deadline has to be in the future, so that contests past
+	// their deadline can still be edited.
+	if command.DeadlineDatetime != nil && !command.DeadlineDatetime.Equal(c.DeadlineDatetime) {
+		if command.DeadlineDatetime.Before(now) {
+			return Contest{}, errors.WithStack(ErrInvalidDeadlineDatetime)
+		}
+
+		c.DeadlineDatetime = *command.DeadlineDatetime
+	}
+
+	if command.StartDatetime != nil || command.replace {
+		c.StartDatetime = command.StartDatetime
+	}
+
+	if command.EndDatetime != nil || command.replace {
+		c.EndDatetime = command.EndDatetime
+	}
+
+	if command.Phase != nil {
+		if !contest.CanMoveToPhase(c.Phase, *command.Phase) {
+			return Contest{}, errors.Wrapf(
+				contest.ErrPhaseTransitionNotAllowed,
+				"cannot move contest from %s to %s",
+				c.Phase,
+				*command.Phase,
+			)
+		}
+
+		c.Phase = *command.Phase
+	}
+
+	if c.MinProblemCount > c.MaxProblemCount {
+		return Contest{}, errors.WithStack(ErrProblemCountRangeFlipped)
+	}
+
+	if c.StartDatetime != nil && c.EndDatetime != nil && !c.StartDatetime.Before(*c.EndDatetime) {
+		return Contest{}, errors.WithStack(ErrInvalidSchedule)
+	}
+
+	c.UpdatedAt = now
+	return c, nil
+}
+
+// checkProblemCount keeps the assigned problems within the contest's range.
+// The maximum cannot drop below the problems already assigned, and a contest
+// can only be frozen once it has its minimum number of problems.
+func checkProblemCount(current Contest, updated Contest, count uint) error {
+	if updated.MaxProblemCount != current.MaxProblemCount && count > updated.MaxProblemCount {
+		return errors.WithStack(ErrMaxBelowAssignedCount)
+	}
+
+	if updated.Phase != current.Phase && updated.Phase == constant.ContestPhaseFrozen &&
+		count < updated.MinProblemCount {
+		return errors.WithStack(ErrNotEnoughProblems)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package updatecontest
+
+import (
+	"net/http"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/httperror"
+
+	"emperror.dev/errors"
+	"github.com/labstack/echo/v4"
+)
+
+type Endpoint struct {
+	*contest.EndpointParams
+	handler *CommandHandler
+}
+
+func NewEndpoint(params *contest.EndpointParams, handler *CommandHandler) *Endpoint {
+	return &Endpoint{
+		EndpointParams: params,
+		handler:        handler,
+	}
+}
+
+func (e *Endpoint) MapEndpoint() {
+	e.ContestsGroup.PUT("/:contest_id", e.handleReplace())
+	e.ContestsGroup.PATCH("/:contest_id", e.handleUpdate())
+}
+
+func (e *Endpoint) handleReplace() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		command := &ReplaceCommand{}
+		if err := ctx.Bind(command); err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid request format")
+		}
+
+		if err := ctx.Validate(command); err != nil {
+			return err
+		}
+
+		return e.handle(ctx, command.ToCommand())
+	}
+}
+
+func (e *Endpoint) handleUpdate() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		command := &Command{}
+		if err := ctx.Bind(command); err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid request format")
+		}
+
+		if err := ctx.Validate(command); err != nil {
+			return err
+		}
+
+		return e.handle(ctx, command)
+	}
+}
+
+func (e *Endpoint) handle(ctx echo.Context, command *Command) error {
+	response, err := e.handler.Handle(ctx.Request().Context(), command)
+	if errors.Is(err, customerror.ErrBaseNoPermission) ||
+		errors.Is(err, customerror.ErrCommandNil) ||
+		errors.Is(err, customerror.ErrValidationFailed) {
+		return err
+	} else if errors.Is(err, ErrContestNotFound) {
+		return httperror.New(http.StatusNotFound, "The contest does not exist")
+	} else if errors.Is(err, ErrContestArchived) {
+		return httperror.New(http.StatusConflict, "Archived contests cannot be changed")
+	} else if errors.Is(err, contest.ErrPhaseTransitionNotAllowed) {
+		return httperror.New(http.StatusConflict, err.Error()).WithInternal(err)
+	} else if errors.Is(err, ErrProblemCountRangeFlipped) {
+		return httperror.New(http.StatusUnprocessableEntity, "Problem count range flipped. Max should be greater than min")
+	} else if errors.Is(err, ErrInvalidDeadlineDatetime) {
+		return httperror.New(http.StatusUnprocessableEntity, "Deadline must be in the future")
+	} else if errors.Is(err, ErrInvalidSchedule) {
+		return httperror.New(http.StatusUnprocessableEntity, "Contest must start before it ends")
+	} else if errors.Is(err, ErrMaxBelowAssignedCount) {
+		return httperror.New(http.StatusUnprocessableEntity, "The contest already has more problems than the new maximum")
+	} else if errors.Is(err, ErrNotEnoughProblems) {
+		return httperror.New(http.StatusUnprocessableEntity, "The contest needs at least its minimum number of problems to be frozen")
+	} else if err != nil {
+		return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,92 @@
+package updatecontest
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) GetContest(ctx context.Context, contestID uuid.UUID) (*Contest, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var contest database.Contest
+	if err := db.WithContext(ctx).
+		Where("contest_id = ?", contestID).
+		First(&contest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithStack(ErrContestNotFound)
+		}
+
+		return nil, errors.WrapIf(err, "failed to get contest")
+	}
+
+	return &Contest{
+		ContestID:        contest.ContestID,
+		Title:            contest.Title,
+		Description:      contest.Description,
+		MinProblemCount:  contest.MinProblemCount,
+		MaxProblemCount:  contest.MaxProblemCount,
+		MaxTimeLimitMs:   contest.MaxTimeLimitMs,
+		MaxMemoryLimitMb: contest.MaxMemoryLimitMb,
+		DeadlineDatetime: contest.DeadlineDatetime,
+		Phase:            constant.FromStringToContestPhase(contest.Phase),
+		StartDatetime:    contest.StartDatetime,
+		EndDatetime:      contest.EndDatetime,
+		CreatedAt:        contest.CreatedAt,
+		UpdatedAt:        contest.UpdatedAt,
+	}, nil
+}
+
+func (r *GormRepository) CountAssignedProblems(ctx context.Context, contestID uuid.UUID) (uint, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var count int64
+	if err := db.WithContext(ctx).
+		Model(&database.ContestProblem{}).
+		Where("contest_id = ?", contestID).
+		Count(&count).Error; err != nil {
+		return 0, errors.WrapIf(err, "failed to count assigned problems")
+	}
+
+	return uint(count), nil
+}
+
+func (r *GormRepository) UpdateContest(ctx context.Context, contest Contest) error {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	if err := db.WithContext(ctx).
+		Model(&database.Contest{}).
+		Where("contest_id = ?", contest.ContestID).
+		Updates(map[string]any{
+			"title":               contest.Title,
+			"description":         contest.Description,
+			"min_problem_count":   contest.MinProblemCount,
+			"max_problem_count":   contest.MaxProblemCount,
+			"max_time_limit_ms":   contest.MaxTimeLimitMs,
+			"max_memory_limit_mb": contest.MaxMemoryLimitMb,
+			"deadline_datetime":   contest.DeadlineDatetime,
+			"phase":               string(contest.Phase),
+			"start_datetime":      contest.StartDatetime,
+			"end_datetime":        contest.EndDatetime,
+			"updated_at":          contest.UpdatedAt,
+		}).Error; err != nil {
+		return errors.WrapIf(err, "failed to update contest")
+	}
+
+	return nil
+}
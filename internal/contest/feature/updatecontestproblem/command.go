@@ -0,0 +1,13 @@
+package updatecontestproblem
+
+import "github.com/google/uuid"
+
+// Command changes the overrides of an assigned problem. Fields left out are
+// kept, and empty values reset the override to the problem's own.
+type Command struct {
+	ContestID     uuid.UUID `param:"contest_id"     validate:"required"`
+	ProblemID     uuid.UUID `param:"problem_id"     validate:"required"`
+	DisplayTitle  *string   `json:"display_title"   validate:"omitempty,max=256"`
+	TimeLimitMs   *uint     `json:"time_limit_ms"   validate:"omitempty,eq=0|min=100,max=60000"`
+	MemoryLimitMb *uint     `json:"memory_limit_mb" validate:"omitempty,eq=0|min=16,max=4096"`
+}
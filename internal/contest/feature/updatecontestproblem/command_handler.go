@@ -0,0 +1,124 @@
+package updatecontestproblem
+
+import (
+	"context"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrContestNotFound    = errors.New("contest not found")
+	ErrProblemNotAssigned = errors.New("problem is not assigned to the contest")
+	ErrExceedsLimits      = errors.New("overridden limits exceed contest maximums")
+)
+
+type Contest struct {
+	Phase            constant.ContestPhase
+	DeadlineDatetime time.Time
+	MaxTimeLimitMs   uint
+	MaxMemoryLimitMb uint
+}
+
+type Repository interface {
+	GetContest(ctx context.Context, contestID uuid.UUID) (*Contest, error)
+	GetContestProblem(ctx context.Context, contestID uuid.UUID, problemID uuid.UUID) (*ContestProblem, error)
+	UpdateContestProblem(ctx context.Context, contestProblem ContestProblem) error
+}
+
+type CommandHandler struct {
+	repo         Repository
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+	uowFactory   contract.UnitOfWorkFactory
+	l            logger.Logger
+}
+
+func NewCommandHandler(
+	repo Repository,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+	uowFactory contract.UnitOfWorkFactory,
+	l logger.Logger,
+) *CommandHandler {
+	return &CommandHandler{
+		repo:         repo,
+		validator:    validator,
+		authProvider: authProvider,
+		uowFactory:   uowFactory,
+		l:            l,
+	}
+}
+
+func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Response, error) {
+	if command == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(command); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	if can, err := h.authProvider.Can(ctx, constant.PermissionContestUpdateAny); err != nil {
+		return nil, errors.WrapIf(err, "failed to check permission")
+	} else if !can {
+		return nil, customerror.NewNoPermissionError(constant.PermissionContestUpdateAny)
+	}
+
+	uow := h.uowFactory.New()
+	return uowhelper.DoWithResult(ctx, uow, h.l, func(ctx context.Context) (*Response, error) {
+		c, err := h.repo.GetContest(ctx, command.ContestID)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to get contest")
+		} else if c == nil {
+			return nil, errors.WithStack(ErrContestNotFound)
+		}
+
+		now := time.Now()
+		if contest.IsProblemSetLocked(c.Phase, c.DeadlineDatetime, now) {
+			return nil, errors.WithStack(contest.ErrProblemSetLocked)
+		}
+
+		contestProblem, err := h.repo.GetContestProblem(ctx, command.ContestID, command.ProblemID)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to get contest problem")
+		} else if contestProblem == nil {
+			return nil, errors.WithStack(ErrProblemNotAssigned)
+		}
+
+		if command.DisplayTitle != nil {
+			contestProblem.DisplayTitle = *command.DisplayTitle
+		}
+
+		if command.TimeLimitMs != nil {
+			contestProblem.TimeLimitMs = *command.TimeLimitMs
+		}
+
+		if command.MemoryLimitMb != nil {
+			contestProblem.MemoryLimitMb = *command.MemoryLimitMb
+		}
+
+		if (c.MaxTimeLimitMs > 0 && contestProblem.TimeLimitMs > c.MaxTimeLimitMs) ||
+			(c.MaxMemoryLimitMb > 0 && contestProblem.MemoryLimitMb > c.MaxMemoryLimitMb) {
+			return nil, errors.WithStack(ErrExceedsLimits)
+		}
+
+		contestProblem.UpdatedAt = now
+		if err := h.repo.UpdateContestProblem(ctx, *contestProblem); err != nil {
+			return nil, errors.WrapIf(err, "failed to update contest problem")
+		}
+
+		return &Response{
+			ContestProblem: *contestProblem,
+		}, nil
+	})
+}
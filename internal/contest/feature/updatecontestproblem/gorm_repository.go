@@ -0,0 +1,93 @@
+package updatecontestproblem
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) GetContest(ctx context.Context, contestID uuid.UUID) (*Contest, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var contest database.Contest
+	if err := db.WithContext(ctx).
+		Select("phase", "deadline_datetime", "max_time_limit_ms", "max_memory_limit_mb").
+		Where("contest_id = ?", contestID).
+		First(&contest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, errors.WrapIf(err, "failed to get contest")
+	}
+
+	return &Contest{
+		Phase:            constant.FromStringToContestPhase(contest.Phase),
+		DeadlineDatetime: contest.DeadlineDatetime,
+		MaxTimeLimitMs:   contest.MaxTimeLimitMs,
+		MaxMemoryLimitMb: contest.MaxMemoryLimitMb,
+	}, nil
+}
+
+func (r *GormRepository) GetContestProblem(
+	ctx context.Context,
+	contestID uuid.UUID,
+	problemID uuid.UUID,
+) (*ContestProblem, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var contestProblem database.ContestProblem
+	if err := db.WithContext(ctx).
+		Where("contest_id = ? AND problem_id = ?", contestID, problemID).
+		First(&contestProblem).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, errors.WrapIf(err, "failed to get contest problem")
+	}
+
+	return &ContestProblem{
+		ContestID:     contestProblem.ContestID,
+		ProblemID:     contestProblem.ProblemID,
+		Position:      contestProblem.Position,
+		Label:         contestProblem.Label,
+		DisplayTitle:  contestProblem.DisplayTitle,
+		TimeLimitMs:   contestProblem.TimeLimitMs,
+		MemoryLimitMb: contestProblem.MemoryLimitMb,
+		UpdatedAt:     contestProblem.UpdatedAt,
+	}, nil
+}
+
+func (r *GormRepository) UpdateContestProblem(ctx context.Context, contestProblem ContestProblem) error {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	if err := db.WithContext(ctx).
+		Model(&database.ContestProblem{}).
+		Where("contest_id = ? AND problem_id = ?", contestProblem.ContestID, contestProblem.ProblemID).
+		Updates(map[string]any{
+			"display_title":   contestProblem.DisplayTitle,
+			"time_limit_ms":   contestProblem.TimeLimitMs,
+			"memory_limit_mb": contestProblem.MemoryLimitMb,
+			"updated_at":      contestProblem.UpdatedAt,
+		}).Error; err != nil {
+		return errors.WrapIf(err, "failed to update contest problem")
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package updatecontestproblem
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ContestProblem struct {
+	ContestID     uuid.UUID `json:"contest_id"`
+	ProblemID     uuid.UUID `json:"problem_id"`
+	Position      uint      `json:"position"`
+	Label         string    `json:"label"`
+	DisplayTitle  string    `json:"display_title"`
+	TimeLimitMs   uint      `json:"time_limit_ms"`
+	MemoryLimitMb uint      `json:"memory_limit_mb"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type Response struct {
+	ContestProblem ContestProblem `json:"contest_problem"`
+}
@@ -0,0 +1,16 @@
+package contest
+
+// Label returns the letter of the problem at the given position, counting
+// from 1, continuing with AA, AB, ... after Z.
+func Label(position uint) string {
+	if position == 0 {
+		return ""
+	}
+
+	i := position - 1
+	if i < 26 {
+		return string(rune('A' + i))
+	}
+
+	return Label(i/26) + Label(i%26+1)
+}
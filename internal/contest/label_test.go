@@ -0,0 +1,23 @@
+package contest
+
+import "testing"
+
+func TestLabel(t *testing.T) {
+	tests := map[uint]string{
+		1:   "A",
+		2:   "B",
+		26:  "Z",
+		27:  "AA",
+		28:  "AB",
+		52:  "AZ",
+		53:  "BA",
+		702: "ZZ",
+		703: "AAA",
+	}
+
+	for position, want := range tests {
+		if got := Label(position); got != want {
+			t.Errorf("Label(%d) = %q, want %q", position, got, want)
+		}
+	}
+}
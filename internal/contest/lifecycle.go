@@ -0,0 +1,56 @@
+package contest
+
+import (
+	"slices"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
+	"emperror.dev/errors"
+)
+
+var (
+	ErrPhaseTransitionNotAllowed = errors.New("contest phase transition not allowed")
+	ErrProblemSetLocked          = errors.New("contest problem set is locked")
+)
+
+// phaseTransitions lists the phases a contest may move to from each phase.
+// A frozen contest can be reopened for problem selection until it is
+// published, and any contest can be archived. Archived contests are final.
+var phaseTransitions = map[constant.ContestPhase][]constant.ContestPhase{
+	constant.ContestPhaseProposal: {
+		constant.ContestPhaseProblemSelection,
+		constant.ContestPhaseArchived,
+	},
+	constant.ContestPhaseProblemSelection: {
+		constant.ContestPhaseProposal,
+		constant.ContestPhaseFrozen,
+		constant.ContestPhaseArchived,
+	},
+	constant.ContestPhaseFrozen: {
+		constant.ContestPhaseProblemSelection,
+		constant.ContestPhasePublished,
+		constant.ContestPhaseArchived,
+	},
+	constant.ContestPhasePublished: {
+		constant.ContestPhaseArchived,
+	},
+}
+
+// CanMoveToPhase reports whether a contest may move from one phase to
+// another. Staying in the same phase is always allowed.
+func CanMoveToPhase(from, to constant.ContestPhase) bool {
+	return from == to || slices.Contains(phaseTransitions[from], to)
+}
+
+// IsProblemSetLocked reports whether problems can no longer be assigned to or
+// unassigned from a contest, either because it has been frozen or because its
+// deadline has passed.
+func IsProblemSetLocked(phase constant.ContestPhase, deadline time.Time, now time.Time) bool {
+	switch phase {
+	case constant.ContestPhaseFrozen, constant.ContestPhasePublished, constant.ContestPhaseArchived:
+		return true
+	default:
+		return !deadline.IsZero() && now.After(deadline)
+	}
+}
@@ -0,0 +1,44 @@
+package contest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+)
+
+func TestCanMoveToPhase(t *testing.T) {
+	tests := []struct {
+		from, to constant.ContestPhase
+		want     bool
+	}{
+		{constant.ContestPhaseProposal, constant.ContestPhaseProblemSelection, true},
+		{constant.ContestPhaseProposal, constant.ContestPhaseFrozen, false},
+		{constant.ContestPhaseFrozen, constant.ContestPhaseProblemSelection, true},
+		{constant.ContestPhasePublished, constant.ContestPhaseFrozen, false},
+		{constant.ContestPhaseArchived, constant.ContestPhaseArchived, true},
+		{constant.ContestPhaseArchived, constant.ContestPhaseProposal, false},
+	}
+
+	for _, tt := range tests {
+		if got := CanMoveToPhase(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanMoveToPhase(%s, %s) = %t, want %t", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestIsProblemSetLocked(t *testing.T) {
+	now := time.Now()
+
+	if IsProblemSetLocked(constant.ContestPhaseProblemSelection, now.Add(time.Hour), now) {
+		t.Error("problem set is locked before the deadline")
+	}
+
+	if !IsProblemSetLocked(constant.ContestPhaseProblemSelection, now.Add(-time.Hour), now) {
+		t.Error("problem set is not locked after the deadline")
+	}
+
+	if !IsProblemSetLocked(constant.ContestPhaseFrozen, now.Add(time.Hour), now) {
+		t.Error("problem set of a frozen contest is not locked")
+	}
+}
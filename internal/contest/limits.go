@@ -0,0 +1,14 @@
+package contest
+
+// Limits are the largest resource limits a contest accepts for its problems.
+// Zero means the contest sets no maximum.
+type Limits struct {
+	MaxTimeLimitMs   uint
+	MaxMemoryLimitMb uint
+}
+
+// Allows reports whether a problem with the given limits fits the contest.
+func (l Limits) Allows(timeLimitMs uint, memoryLimitMb uint) bool {
+	return (l.MaxTimeLimitMs == 0 || timeLimitMs <= l.MaxTimeLimitMs) &&
+		(l.MaxMemoryLimitMb == 0 || memoryLimitMb <= l.MaxMemoryLimitMb)
+}
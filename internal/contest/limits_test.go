@@ -0,0 +1,26 @@
+package contest
+
+import "testing"
+
+func TestLimitsAllows(t *testing.T) {
+	tests := []struct {
+		limits        Limits
+		timeLimitMs   uint
+		memoryLimitMb uint
+		want          bool
+	}{
+		{Limits{}, 60000, 4096, true},
+		{Limits{MaxTimeLimitMs: 2000}, 2000, 4096, true},
+		{Limits{MaxTimeLimitMs: 2000}, 2001, 256, false},
+		{Limits{MaxMemoryLimitMb: 256}, 60000, 256, true},
+		{Limits{MaxMemoryLimitMb: 256}, 1000, 512, false},
+		{Limits{MaxTimeLimitMs: 2000, MaxMemoryLimitMb: 256}, 1000, 256, true},
+		{Limits{MaxTimeLimitMs: 2000, MaxMemoryLimitMb: 256}, 3000, 512, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.limits.Allows(tt.timeLimitMs, tt.memoryLimitMb); got != tt.want {
+			t.Errorf("%+v.Allows(%d, %d) = %t, want %t", tt.limits, tt.timeLimitMs, tt.memoryLimitMb, got, tt.want)
+		}
+	}
+}
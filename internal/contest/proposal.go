@@ -0,0 +1,48 @@
+package contest
+
+import (
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
+	"emperror.dev/errors"
+)
+
+var (
+	ErrProblemNotCompleted = errors.New("problem is not completed")
+	ErrExceedsLimits       = errors.New("problem limits exceed contest maximums")
+)
+
+// ProposedProblem is a problem, as of its latest version, that is proposed for
+// a contest.
+type ProposedProblem struct {
+	Status        constant.ProblemStatus
+	TimeLimitMs   uint
+	MemoryLimitMb uint
+}
+
+// ProposalTarget is the contest a problem is proposed for.
+type ProposalTarget struct {
+	Phase            constant.ContestPhase
+	DeadlineDatetime time.Time
+	Limits           Limits
+}
+
+// CheckProposal checks that a problem can be proposed for a contest: the
+// problem has to be completed and within the contest's limits, and the
+// contest's problem set must not be locked yet.
+func CheckProposal(p ProposedProblem, target ProposalTarget, now time.Time) error {
+	if p.Status != constant.ProblemStatusCompleted {
+		return errors.WithStack(ErrProblemNotCompleted)
+	}
+
+	if IsProblemSetLocked(target.Phase, target.DeadlineDatetime, now) {
+		return errors.WithStack(ErrProblemSetLocked)
+	}
+
+	if !target.Limits.Allows(p.TimeLimitMs, p.MemoryLimitMb) {
+		return errors.WithStack(ErrExceedsLimits)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package contest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
+	"emperror.dev/errors"
+)
+
+func TestCheckProposal(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	open := ProposalTarget{
+		Phase:            constant.ContestPhaseProposal,
+		DeadlineDatetime: now.Add(time.Hour),
+		Limits:           Limits{MaxTimeLimitMs: 2000, MaxMemoryLimitMb: 256},
+	}
+	completed := ProposedProblem{Status: constant.ProblemStatusCompleted, TimeLimitMs: 1000, MemoryLimitMb: 256}
+
+	tests := []struct {
+		name    string
+		problem ProposedProblem
+		target  ProposalTarget
+		want    error
+	}{
+		{name: "completed", problem: completed, target: open},
+		{
+			name:    "not completed",
+			problem: ProposedProblem{Status: constant.ProblemStatusPendingTesting, TimeLimitMs: 1000, MemoryLimitMb: 256},
+			target:  open,
+			want:    ErrProblemNotCompleted,
+		},
+		{
+			name:    "frozen",
+			problem: completed,
+			target:  ProposalTarget{Phase: constant.ContestPhaseFrozen, DeadlineDatetime: open.DeadlineDatetime},
+			want:    ErrProblemSetLocked,
+		},
+		{
+			name:    "past the deadline",
+			problem: completed,
+			target:  ProposalTarget{Phase: constant.ContestPhaseProposal, DeadlineDatetime: now.Add(-time.Hour)},
+			want:    ErrProblemSetLocked,
+		},
+		{
+			name:    "over the limits",
+			problem: ProposedProblem{Status: constant.ProblemStatusCompleted, TimeLimitMs: 3000, MemoryLimitMb: 256},
+			target:  open,
+			want:    ErrExceedsLimits,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckProposal(tt.problem, tt.target, now)
+			if tt.want == nil && err != nil {
+				t.Errorf("CheckProposal() error = %v, want nil", err)
+			} else if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("CheckProposal() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package media
+
+import "github.com/google/uuid"
+
+// ContentPath is the API path that streams a media object through the backend.
+// It is used as the media URL when the storage driver has no public URL.
+func ContentPath(mediaID uuid.UUID) string {
+	return "/api/v1/media/" + mediaID.String() + "/content"
+}
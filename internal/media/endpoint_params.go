@@ -0,0 +1,22 @@
+package media
+
+import (
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/echoweb"
+
+	"github.com/labstack/echo/v4"
+)
+
+type EndpointParams struct {
+	MediaGroup *echo.Group
+	Uploads    *echoweb.UploadRoutes
+}
+
+func NewEndpointParams(
+	v1Group *echoweb.V1Group,
+) *EndpointParams {
+	media := v1Group.Group.Group("/media")
+	return &EndpointParams{
+		MediaGroup: media,
+		Uploads:    v1Group.Uploads,
+	}
+}
@@ -0,0 +1,63 @@
+package getmediacontent
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/media"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/httperror"
+
+	"emperror.dev/errors"
+	"github.com/labstack/echo/v4"
+)
+
+type Endpoint struct {
+	*media.EndpointParams
+	handler *QueryHandler
+}
+
+func NewEndpoint(params *media.EndpointParams, handler *QueryHandler) *Endpoint {
+	return &Endpoint{
+		EndpointParams: params,
+		handler:        handler,
+	}
+}
+
+func (e *Endpoint) MapEndpoint() {
+	e.MediaGroup.GET("/:media_id/content", e.handle())
+}
+
+func (e *Endpoint) handle() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		query := &Query{}
+		if err := ctx.Bind(query); err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid request format")
+		}
+
+		if err := ctx.Validate(query); err != nil {
+			return err
+		}
+
+		response, err := e.handler.Handle(ctx.Request().Context(), query)
+		if errors.Is(err, customerror.ErrBaseNoPermission) {
+			return err
+		} else if errors.Is(err, ErrMediaNotFound) {
+			return httperror.New(http.StatusNotFound, "The media does not exist")
+		} else if err != nil {
+			return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
+		}
+		defer response.Content.Close()
+
+		header := ctx.Response().Header()
+		header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("inline", map[string]string{
+			"filename": response.FileName,
+		}))
+		header.Set(echo.HeaderContentLength, strconv.FormatUint(response.FileSize, 10))
+		header.Set(echo.HeaderXContentTypeOptions, "nosniff")
+		header.Set("Cache-Control", "private, max-age=86400")
+
+		return ctx.Stream(http.StatusOK, response.MIMEType, response.Content)
+	}
+}
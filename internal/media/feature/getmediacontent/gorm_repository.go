@@ -0,0 +1,90 @@
+package getmediacontent
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) GetMedia(ctx context.Context, mediaID uuid.UUID) (*database.Media, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var media database.Media
+	if err := db.WithContext(ctx).
+		Where("media_id = ?", mediaID).
+		First(&media).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithStack(ErrMediaNotFound)
+		}
+
+		return nil, errors.WrapIf(err, "failed to get media")
+	}
+
+	return &media, nil
+}
+
+// GetReferencingProblems gets the access of the problems whose chat has the
+// media attached or whose statement links to it.
+func (r *GormRepository) GetReferencingProblems(ctx context.Context, mediaID uuid.UUID) ([]*problem.Access, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var chatProblemIDs []uuid.UUID
+	if err := db.WithContext(ctx).
+		Model(&database.ProblemChatMessage{}).
+		Distinct("problem_chat_messages.problem_id").
+		Joins("JOIN problem_chat_message_attachments a ON a.message_id = problem_chat_messages.message_id").
+		Where("a.media_id = ?", mediaID).
+		Pluck("problem_chat_messages.problem_id", &chatProblemIDs).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get problems with the media attached")
+	}
+
+	// Statements link to media by URL, which holds the media ID.
+	pattern := "%" + mediaID.String() + "%"
+
+	var statementProblemIDs []uuid.UUID
+	if err := db.WithContext(ctx).
+		Model(&database.ProblemVersionDetail{}).
+		Distinct("v.problem_id").
+		Joins("JOIN problem_versions v ON v.problem_version_id = problem_version_details.problem_version_id").
+		Where(
+			"background LIKE ? OR statement LIKE ? OR input_format LIKE ? OR output_format LIKE ? OR note LIKE ?",
+			pattern, pattern, pattern, pattern, pattern,
+		).
+		Pluck("v.problem_id", &statementProblemIDs).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get problems linking to the media")
+	}
+
+	problemIDs := append(chatProblemIDs, statementProblemIDs...)
+	if len(problemIDs) == 0 {
+		return nil, nil
+	}
+
+	var problems []database.Problem
+	if err := db.WithContext(ctx).
+		Preload("Testers").
+		Preload("ContestProblems").
+		Where("problem_id IN ?", problemIDs).
+		Find(&problems).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get problems referencing the media")
+	}
+
+	accesses := make([]*problem.Access, 0, len(problems))
+	for i := range problems {
+		accesses = append(accesses, problem.NewAccess(&problems[i]))
+	}
+
+	return accesses, nil
+}
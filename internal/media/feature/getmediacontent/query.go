@@ -0,0 +1,7 @@
+package getmediacontent
+
+import "github.com/google/uuid"
+
+type Query struct {
+	MediaID uuid.UUID `param:"media_id" validate:"required"`
+}
@@ -0,0 +1,94 @@
+package getmediacontent
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/storage"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+var ErrMediaNotFound = errors.New("media not found")
+
+type Repository interface {
+	GetMedia(ctx context.Context, mediaID uuid.UUID) (*database.Media, error)
+	GetReferencingProblems(ctx context.Context, mediaID uuid.UUID) ([]*problem.Access, error)
+}
+
+type QueryHandler struct {
+	repo         Repository
+	storage      contract.FileStorage
+	authProvider contract.AuthProvider
+}
+
+func NewQueryHandler(
+	repo Repository,
+	storage contract.FileStorage,
+	authProvider contract.AuthProvider,
+) *QueryHandler {
+	return &QueryHandler{
+		repo:         repo,
+		storage:      storage,
+		authProvider: authProvider,
+	}
+}
+
+func (q *QueryHandler) Handle(ctx context.Context, query *Query) (*Response, error) {
+	user, err := q.authProvider.MustGetUser(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get user from auth provider")
+	}
+
+	media, err := q.repo.GetMedia(ctx, query.MediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !media.UploaderID.Valid || media.UploaderID.UUID != user.UserID {
+		if err := q.checkReferencingProblems(ctx, media.MediaID); err != nil {
+			return nil, err
+		}
+	}
+
+	content, err := q.storage.Open(ctx, media.StorageKey)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, errors.WithStack(ErrMediaNotFound)
+	} else if err != nil {
+		return nil, errors.WrapIf(err, "failed to open media content")
+	}
+
+	return &Response{
+		FileName: media.FileName,
+		MIMEType: media.MIMEType,
+		FileSize: media.FileSize,
+		Content:  content,
+	}, nil
+}
+
+// checkReferencingProblems lets users other than the uploader read the media
+// when they can read a problem whose chat or statement references it.
+func (q *QueryHandler) checkReferencingProblems(ctx context.Context, mediaID uuid.UUID) error {
+	accesses, err := q.repo.GetReferencingProblems(ctx, mediaID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get problems referencing the media")
+	}
+
+	scope, err := problem.GetReadScope(ctx, q.authProvider)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get read scope")
+	}
+
+	for _, access := range accesses {
+		if scope.Allows(access) {
+			return nil
+		}
+	}
+
+	return customerror.NewNoPermissionError(constant.PermissionProblemReadDetailsAny)
+}
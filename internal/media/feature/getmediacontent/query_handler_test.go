@@ -0,0 +1,102 @@
+package getmediacontent
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/contracttest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+type fakeRepository struct {
+	media    *database.Media
+	problems []*problem.Access
+}
+
+func (r *fakeRepository) GetMedia(context.Context, uuid.UUID) (*database.Media, error) {
+	return r.media, nil
+}
+
+func (r *fakeRepository) GetReferencingProblems(context.Context, uuid.UUID) ([]*problem.Access, error) {
+	return r.problems, nil
+}
+
+type fakeStorage struct{}
+
+func (fakeStorage) Save(context.Context, string, io.Reader, int64, string) error { return nil }
+
+func (fakeStorage) Open(context.Context, string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("content")), nil
+}
+
+func (fakeStorage) Delete(context.Context, string) error { return nil }
+
+func TestHandleChecksAccess(t *testing.T) {
+	uploaderID := uuid.New()
+	setterID := uuid.New()
+
+	referencing := []*problem.Access{{
+		Status:    constant.ProblemStatusPendingReview,
+		CreatorID: setterID,
+	}}
+
+	tests := []struct {
+		name        string
+		permissions []string
+		problems    []*problem.Access
+		asUploader  bool
+		asSetter    bool
+		wantAllowed bool
+	}{
+		{name: "uploader", asUploader: true, wantAllowed: true},
+		{name: "unreferenced", wantAllowed: false},
+		{name: "referenced by an unreadable problem", problems: referencing, wantAllowed: false},
+		{name: "setter of the referencing problem", problems: referencing, asSetter: true, wantAllowed: true},
+		{
+			name:        "reader of every problem",
+			problems:    referencing,
+			permissions: []string{constant.PermissionProblemReadDetailsAny},
+			wantAllowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			switch {
+			case tt.asUploader:
+				userID = uploaderID
+			case tt.asSetter:
+				userID = setterID
+			}
+
+			handler := NewQueryHandler(
+				&fakeRepository{
+					media: &database.Media{
+						MediaID:    uuid.New(),
+						UploaderID: uuid.NullUUID{UUID: uploaderID, Valid: true},
+					},
+					problems: tt.problems,
+				},
+				fakeStorage{},
+				contracttest.NewAuthProvider(userID, &contract.AuthUserDetails{Permissions: tt.permissions}),
+			)
+
+			_, err := handler.Handle(context.Background(), &Query{MediaID: uuid.New()})
+			if tt.wantAllowed && err != nil {
+				t.Fatalf("Handle failed: %v", err)
+			} else if !tt.wantAllowed && !errors.Is(err, customerror.ErrBaseNoPermission) {
+				t.Fatalf("Handle returned %v, want no permission", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package getmediacontent
+
+import "io"
+
+type Response struct {
+	FileName string
+	MIMEType string
+	FileSize uint64
+	Content  io.ReadCloser
+}
@@ -0,0 +1,15 @@
+package uploadmedia
+
+import "io"
+
+const (
+	PurposeChat  = "chat"
+	PurposeDraft = "draft"
+)
+
+type Command struct {
+	Purpose  string    `validate:"required,oneof=chat draft"`
+	FileName string    `validate:"required,max=255"`
+	FileSize int64     `validate:"gt=0"`
+	Content  io.Reader `validate:"required"`
+}
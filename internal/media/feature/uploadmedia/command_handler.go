@@ -0,0 +1,186 @@
+package uploadmedia
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/media"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/storage"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+// sniffLength is the number of bytes http.DetectContentType considers.
+const sniffLength = 512
+
+var (
+	ErrFileTooLarge        = errors.New("file too large")
+	ErrUnsupportedMIMEType = errors.New("unsupported mime type")
+)
+
+type Repository interface {
+	CreateMedia(ctx context.Context, media Media) error
+}
+
+type CommandHandler struct {
+	repo         Repository
+	storage      contract.FileStorage
+	opts         *storage.Options
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+	uowFactory   contract.UnitOfWorkFactory
+	l            logger.Logger
+}
+
+func NewCommandHandler(
+	repo Repository,
+	storage contract.FileStorage,
+	opts *storage.Options,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+	uowFactory contract.UnitOfWorkFactory,
+	l logger.Logger,
+) *CommandHandler {
+	return &CommandHandler{
+		repo:         repo,
+		storage:      storage,
+		opts:         opts,
+		validator:    validator,
+		authProvider: authProvider,
+		uowFactory:   uowFactory,
+		l:            l,
+	}
+}
+
+func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Response, error) {
+	if command == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(command); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	user, err := h.authProvider.MustGetUser(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get user from auth provider")
+	}
+
+	permission := constant.PermissionMediaUploadForChatOwn
+	if command.Purpose == PurposeDraft {
+		permission = constant.PermissionMediaUploadForDraftOwn
+	}
+
+	if can, err := h.authProvider.Can(ctx, permission); err != nil {
+		return nil, errors.WrapIf(err, "failed to check permission")
+	} else if !can {
+		return nil, customerror.NewNoPermissionError(permission)
+	}
+
+	maxFileSize := h.opts.GetMaxFileSize()
+	if command.FileSize > maxFileSize {
+		return nil, errors.WithStack(ErrFileTooLarge)
+	}
+
+	// The MIME type is always sniffed from the content; whatever the client
+	// claims in the multipart headers is ignored.
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(command.Content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, errors.WrapIf(err, "failed to read file content")
+	}
+	head = head[:n]
+
+	mimeType := http.DetectContentType(head)
+	if !h.opts.IsMIMETypeAllowed(mimeType) {
+		return nil, errors.WithStack(ErrUnsupportedMIMEType)
+	}
+
+	mediaID, err := uuid.NewV7()
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to generate media id")
+	}
+
+	key := "media/" + mediaID.String()
+	content := &countingReader{r: io.LimitReader(io.MultiReader(bytes.NewReader(head), command.Content), maxFileSize+1)}
+
+	if err := h.storage.Save(ctx, key, content, command.FileSize, mimeType); err != nil {
+		return nil, errors.WrapIf(err, "failed to save file to storage")
+	}
+
+	if content.n > maxFileSize {
+		h.deleteObject(ctx, key)
+		return nil, errors.WithStack(ErrFileTooLarge)
+	}
+
+	// The content is always served through the API, which checks that the
+	// media can be read.
+	m := Media{
+		MediaID:    mediaID,
+		URL:        media.ContentPath(mediaID),
+		FileName:   sanitizeFileName(command.FileName),
+		MIMEType:   mimeType,
+		FileSize:   uint64(content.n),
+		StorageKey: key,
+		UploaderID: user.UserID,
+		CreatedAt:  time.Now(),
+	}
+
+	uow := h.uowFactory.New()
+	if err := uowhelper.Do(ctx, uow, h.l, func(ctx context.Context) error {
+		if err := h.repo.CreateMedia(ctx, m); err != nil {
+			return errors.WrapIf(err, "failed to create media")
+		}
+
+		return nil
+	}); err != nil {
+		h.deleteObject(ctx, key)
+		return nil, err
+	}
+
+	return &Response{
+		MediaID:  m.MediaID,
+		URL:      m.URL,
+		FileName: m.FileName,
+		MIMEType: m.MIMEType,
+		FileSize: m.FileSize,
+	}, nil
+}
+
+func (h *CommandHandler) deleteObject(ctx context.Context, key string) {
+	if err := h.storage.Delete(ctx, key); err != nil {
+		h.l.Error("failed to delete orphaned media object", errors.WithStack(err))
+	}
+}
+
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" {
+		return "file"
+	}
+
+	return name
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,77 @@
+package uploadmedia
+
+import (
+	"net/http"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/media"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/httperror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/storage"
+
+	"emperror.dev/errors"
+	"github.com/labstack/echo/v4"
+)
+
+// multipartOverhead leaves room for multipart boundaries and form fields on top
+// of the file itself when capping the request body.
+const multipartOverhead = 64 << 10
+
+type Endpoint struct {
+	*media.EndpointParams
+	handler *CommandHandler
+	opts    *storage.Options
+}
+
+func NewEndpoint(params *media.EndpointParams, handler *CommandHandler, opts *storage.Options) *Endpoint {
+	return &Endpoint{
+		EndpointParams: params,
+		handler:        handler,
+		opts:           opts,
+	}
+}
+
+func (e *Endpoint) MapEndpoint() {
+	e.Uploads.Add(e.MediaGroup.POST("", e.handle()))
+}
+
+func (e *Endpoint) handle() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		req := ctx.Request()
+		req.Body = http.MaxBytesReader(ctx.Response(), req.Body, e.opts.GetMaxFileSize()+multipartOverhead)
+
+		fileHeader, err := ctx.FormFile("file")
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return httperror.New(http.StatusRequestEntityTooLarge, "The file is too large")
+		} else if err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid request format")
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid request format")
+		}
+		defer file.Close()
+
+		command := &Command{
+			Purpose:  ctx.FormValue("purpose"),
+			FileName: fileHeader.Filename,
+			FileSize: fileHeader.Size,
+			Content:  file,
+		}
+
+		if err := ctx.Validate(command); err != nil {
+			return err
+		}
+
+		response, err := e.handler.Handle(req.Context(), command)
+		if errors.Is(err, ErrFileTooLarge) {
+			return httperror.New(http.StatusRequestEntityTooLarge, "The file is too large")
+		} else if errors.Is(err, ErrUnsupportedMIMEType) {
+			return httperror.New(http.StatusUnsupportedMediaType, "The file type is not supported")
+		} else if err != nil {
+			return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
+		}
+
+		return ctx.JSON(http.StatusCreated, response)
+	}
+}
@@ -0,0 +1,40 @@
+package uploadmedia
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) CreateMedia(ctx context.Context, media Media) error {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	mediaModel := database.Media{
+		MediaID:    media.MediaID,
+		URL:        media.URL,
+		FileName:   media.FileName,
+		MIMEType:   media.MIMEType,
+		FileSize:   media.FileSize,
+		StorageKey: media.StorageKey,
+		UploaderID: uuid.NullUUID{UUID: media.UploaderID, Valid: true},
+		CreatedAt:  media.CreatedAt,
+	}
+
+	if err := db.WithContext(ctx).Create(&mediaModel).Error; err != nil {
+		return errors.WrapIf(err, "failed to create media")
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package uploadmedia
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Media struct {
+	MediaID    uuid.UUID
+	URL        string
+	FileName   string
+	MIMEType   string
+	FileSize   uint64
+	StorageKey string
+	UploaderID uuid.UUID
+	CreatedAt  time.Time
+}
@@ -0,0 +1,11 @@
+package uploadmedia
+
+import "github.com/google/uuid"
+
+type Response struct {
+	MediaID  uuid.UUID `json:"media_id"`
+	URL      string    `json:"url"`
+	FileName string    `json:"file_name"`
+	MIMEType string    `json:"mime_type"`
+	FileSize uint64    `json:"file_size"`
+}
@@ -10,8 +10,10 @@ import (
 	"time"
 
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/echoweb"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/judge"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
 	defaultLogger "github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger/defaultlogger"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/outbox"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/websocket"
 
 	"emperror.dev/errors"
@@ -23,6 +25,8 @@ type Application struct {
 	Container    *dig.Container
 	Echo         *echo.Echo
 	WebsocketHub *websocket.Hub
+	JudgePool    *judge.WorkerPool
+	Outbox       *outbox.Dispatcher
 	Logger       logger.Logger
 	EchoOptions  *echoweb.Options
 
@@ -38,10 +42,19 @@ func NewApplication(container *dig.Container) *Application {
 		appCancel: appCancel,
 	}
 
-	if err := container.Invoke(func(opts *echoweb.Options, e *echo.Echo, wh *websocket.Hub, logger logger.Logger) error {
+	if err := container.Invoke(func(
+		opts *echoweb.Options,
+		e *echo.Echo,
+		wh *websocket.Hub,
+		jp *judge.WorkerPool,
+		od *outbox.Dispatcher,
+		logger logger.Logger,
+	) error {
 		app.Container = container
 		app.Echo = e
 		app.WebsocketHub = wh
+		app.JudgePool = jp
+		app.Outbox = od
 		app.Logger = logger
 		app.EchoOptions = opts
 
@@ -101,6 +114,17 @@ func (a *Application) Start() {
 		a.WebsocketHub.Run(a.appCtx)
 		a.Logger.Info("WebSocket Hub stopped.")
 	}()
+
+	go func() {
+		a.JudgePool.Run(a.appCtx)
+		a.Logger.Info("Judge workers stopped.")
+	}()
+
+	go func() {
+		a.Logger.Info("Outbox dispatcher starting...")
+		a.Outbox.Run(a.appCtx)
+		a.Logger.Info("Outbox dispatcher stopped.")
+	}()
 }
 
 func (a *Application) Stop(ctx context.Context) {
@@ -1,29 +1,54 @@
 package application
 
 import (
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/acceptproposal"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/assignproblem"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/clonecontest"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/createcontest"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/deletecontest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/exportcontest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/getcontestreadiness"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/listcontest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/listcontestmembers"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/listproposals"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/removecontestmember"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/reorderproblems"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/setcontestmember"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/unassignproblem"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/updatecontest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/updatecontestproblem"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/media/feature/getmediacontent"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/media/feature/uploadmedia"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/validation"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/assigntesters"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/checkoutdraft"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/exportproblem"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/getproblem"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/judgeproblem"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/listjudgerun"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/listmessage"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/listproblem"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/liststatushistory"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/markcomplete"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/retargetproblem"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/reviewproblem"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/searchproblem"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/sendmessage"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/testproblem"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdifficulty/feature/listproblemdifficulty"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/feature/deleteproblemdraft"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/feature/generatetestcases"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/feature/importpolygonpackage"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/feature/listproblemdraft"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/feature/submitproblemdraft"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/feature/uploadtestcases"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/feature/upsertproblemdraft"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/feature/validatetestcases"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/user/feature/getcurrentuser"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/user/feature/listtester"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/user/feature/login"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/user/feature/logout"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/user/feature/managerole"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/user/feature/manageuser"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/user/feature/register"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/user/feature/requestemailverification"
@@ -31,11 +56,10 @@ import (
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/user/feature/verifyemail"
 
 	"emperror.dev/errors"
-	"github.com/go-playground/validator"
 )
 
 func (a *Application) AddHandlers() error {
-	if err := a.Container.Provide(validator.New); err != nil {
+	if err := a.Container.Provide(validation.New); err != nil {
 		return errors.WrapIf(err, "failed to provide validator")
 	}
 
@@ -83,6 +107,30 @@ func (a *Application) AddHandlers() error {
 		return errors.WrapIf(err, "failed to provide manage user reset password command handler")
 	}
 
+	if err := a.Container.Provide(managerole.NewListQueryHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide list role query handler")
+	}
+
+	if err := a.Container.Provide(managerole.NewGetQueryHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide get role query handler")
+	}
+
+	if err := a.Container.Provide(managerole.NewCreateCommandHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide manage role create command handler")
+	}
+
+	if err := a.Container.Provide(managerole.NewUpdateCommandHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide manage role update command handler")
+	}
+
+	if err := a.Container.Provide(managerole.NewDeleteCommandHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide manage role delete command handler")
+	}
+
+	if err := a.Container.Provide(managerole.NewListPermissionsQueryHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide list permission query handler")
+	}
+
 	if err := a.Container.Provide(createcontest.NewCommandHandler); err != nil {
 		return errors.WrapIf(err, "failed to provide create contest command handler")
 	}
@@ -163,5 +211,97 @@ func (a *Application) AddHandlers() error {
 		return errors.WrapIf(err, "failed to provide unassign problem command handler")
 	}
 
+	if err := a.Container.Provide(uploadmedia.NewCommandHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide upload media command handler")
+	}
+
+	if err := a.Container.Provide(getmediacontent.NewQueryHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide get media content query handler")
+	}
+
+	if err := a.Container.Provide(uploadtestcases.NewCommandHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide upload testcases command handler")
+	}
+
+	if err := a.Container.Provide(judgeproblem.NewCommandHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide judge problem command handler")
+	}
+
+	if err := a.Container.Provide(listjudgerun.NewQueryHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide list judge run query handler")
+	}
+
+	if err := a.Container.Provide(generatetestcases.NewCommandHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide generate testcases command handler")
+	}
+
+	if err := a.Container.Provide(validatetestcases.NewCommandHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide validate testcases command handler")
+	}
+
+	if err := a.Container.Provide(importpolygonpackage.NewCommandHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide import polygon package command handler")
+	}
+
+	if err := a.Container.Provide(exportcontest.NewQueryHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide export contest query handler")
+	}
+
+	if err := a.Container.Provide(exportproblem.NewQueryHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide export problem query handler")
+	}
+
+	if err := a.Container.Provide(liststatushistory.NewQueryHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide list status history query handler")
+	}
+
+	if err := a.Container.Provide(updatecontest.NewCommandHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide update contest command handler")
+	}
+
+	if err := a.Container.Provide(listproposals.NewQueryHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide list proposals query handler")
+	}
+
+	if err := a.Container.Provide(acceptproposal.NewCommandHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide accept proposal command handler")
+	}
+
+	if err := a.Container.Provide(reorderproblems.NewCommandHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide reorder problems command handler")
+	}
+
+	if err := a.Container.Provide(updatecontestproblem.NewCommandHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide update contest problem command handler")
+	}
+
+	if err := a.Container.Provide(getcontestreadiness.NewQueryHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide get contest readiness query handler")
+	}
+
+	if err := a.Container.Provide(listcontestmembers.NewQueryHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide list contest members query handler")
+	}
+
+	if err := a.Container.Provide(setcontestmember.NewCommandHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide set contest member command handler")
+	}
+
+	if err := a.Container.Provide(removecontestmember.NewCommandHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide remove contest member command handler")
+	}
+
+	if err := a.Container.Provide(retargetproblem.NewCommandHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide retarget problem command handler")
+	}
+
+	if err := a.Container.Provide(clonecontest.NewCommandHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide clone contest command handler")
+	}
+
+	if err := a.Container.Provide(searchproblem.NewQueryHandler); err != nil {
+		return errors.WrapIf(err, "failed to provide search problem query handler")
+	}
+
 	return nil
 }
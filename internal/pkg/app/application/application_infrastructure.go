@@ -4,15 +4,18 @@ import (
 	"strings"
 	"time"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
 
 	"emperror.dev/errors"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func (a *Application) ConfigInfrastructure() error {
@@ -28,7 +31,8 @@ func (a *Application) ConfigInfrastructure() error {
 		return errors.WrapIf(err, "failed to seed problem difficulties")
 	}
 
-	if err := a.seedPermissions(); err != nil {
+	added, err := a.seedPermissions()
+	if err != nil {
 		return errors.WrapIf(err, "failed to seed permissions")
 	}
 
@@ -36,6 +40,10 @@ func (a *Application) ConfigInfrastructure() error {
 		return errors.WrapIf(err, "failed to seed roles")
 	}
 
+	if err := a.grantAddedPermissions(added); err != nil {
+		return errors.WrapIf(err, "failed to grant added permissions")
+	}
+
 	return nil
 }
 
@@ -68,6 +76,7 @@ func (a *Application) migrateDatabase() error {
 
 		err := g.AutoMigrate(
 			&database.User{},
+			&database.Blob{},
 			&database.Role{},
 			&database.Permission{},
 			&database.EmailVerificationCode{},
@@ -77,15 +86,32 @@ func (a *Application) migrateDatabase() error {
 			&database.ProblemDraft{},
 			&database.ProblemDraftDetail{},
 			&database.ProblemDraftExample{},
+			&database.ProblemDraftTestcase{},
+			&database.ProblemDraftSolution{},
+			&database.ProblemDraftGenerator{},
 			&database.Problem{},
+			&database.ContestProblem{},
+			&database.ContestMembership{},
 			&database.ProblemVersion{},
 			&database.ProblemVersionDetail{},
 			&database.ProblemVersionExample{},
+			&database.ProblemVersionTestcase{},
+			&database.ProblemVersionSolution{},
+			&database.ProblemVersionGenerator{},
 			&database.ProblemReview{},
 			&database.ProblemTestResult{},
+			&database.ProblemTestSolutionResult{},
+			&database.ProblemStatusHistory{},
+			&database.AuditLogEntry{},
+			&database.JudgeRun{},
+			&database.JudgeTestResult{},
 			&database.Media{},
 			&database.ProblemChatMessage{},
 			&database.ProblemChatMessageAttachment{},
+			&database.ProblemSimilarity{},
+			&database.RoomEvent{},
+			&database.RoomSequence{},
+			&database.OutboxEvent{},
 		)
 		if err != nil {
 			return err
@@ -95,6 +121,18 @@ func (a *Application) migrateDatabase() error {
 			return errors.WrapIf(err, "failed to normalize problem statuses")
 		}
 
+		if err := a.migrateContestAssignments(g); err != nil {
+			return errors.WrapIf(err, "failed to migrate contest assignments")
+		}
+
+		if err := database.CreateTextSearchIndexes(g); err != nil {
+			return errors.WrapIf(err, "failed to create text search indexes")
+		}
+
+		if err := a.backfillProblemSignatures(g); err != nil {
+			return errors.WrapIf(err, "failed to backfill problem signatures")
+		}
+
 		return nil
 	})
 }
@@ -169,89 +207,56 @@ func (a *Application) seedProblemDifficulties() error {
 	})
 }
 
-func (a *Application) seedPermissions() error {
-	return a.ResolveDependencyFunc(func(g *gorm.DB, l logger.Logger) error {
-		var count int64
-		if err := g.Model(&database.Permission{}).Count(&count).Error; err != nil {
-			return errors.WrapIf(err, "failed to count permissions")
+// seedPermissions inserts the permissions missing from the database and returns
+// their names.
+func (a *Application) seedPermissions() ([]string, error) {
+	var added []string
+
+	err := a.ResolveDependencyFunc(func(g *gorm.DB, l logger.Logger) error {
+		var existing []string
+		if err := g.Model(&database.Permission{}).Pluck("name", &existing).Error; err != nil {
+			return errors.WrapIf(err, "failed to get permissions")
 		}
 
-		if count > 0 {
-			return nil
+		seeded := make(map[string]struct{}, len(existing))
+		for _, name := range existing {
+			seeded[name] = struct{}{}
 		}
 
 		now := time.Now()
 
+		// Permissions added to the catalogue after the database was seeded are
+		// inserted as well, so that they can be granted through the role API.
 		permissions := make([]database.Permission, 0)
-		addPermission := func(name, description string) {
+		for _, p := range constant.Permissions {
+			if _, ok := seeded[p.Name]; ok {
+				continue
+			}
+
 			permissions = append(permissions, database.Permission{
 				PermissionID: a.mustGenerateUUID(l),
-				Name:         name,
-				Description:  description,
+				Name:         p.Name,
+				Description:  p.Description,
 				CreatedAt:    now,
 			})
 		}
 
-		addPermission(constant.PermissionMediaUploadForDraftOwn, "Upload media for one's own problem draft")
-		addPermission(constant.PermissionMediaUploadForChatOwn, "Upload media for one's own problem chat")
-
-		addPermission(constant.PermissionUserListAll, "List all users")
-		addPermission(constant.PermissionUserReadProfileAny, "Read any user's profile")
-		addPermission(constant.PermissionUserReadProfileOwn, "Read one's own profile")
-		addPermission(constant.PermissionUserUpdateProfileOwn, "Update one's own profile")
-		addPermission(constant.PermissionUserManageRolesAny, "Manage any user's roles (assign/remove)")
-
-		addPermission(constant.PermissionRoleListAll, "List all roles")
-		addPermission(constant.PermissionRoleManageAny, "Manage any role (create/update/delete)")
-
-		addPermission(constant.PermissionProblemDraftCreate, "Create a problem draft")
-		addPermission(constant.PermissionProblemDraftReadOwn, "Read one's own problem draft")
-		addPermission(constant.PermissionProblemDraftUpdateOwn, "Update one's own problem draft")
-		addPermission(constant.PermissionProblemDraftDeleteOwn, "Delete one's own problem draft")
-		addPermission(constant.PermissionProblemDraftSubmitOwn, "Submit one's own problem draft")
-
-		addPermission(constant.PermissionProblemListAll, "List all problems")
-		addPermission(constant.PermissionProblemListCreatedOwn, "List problems created by oneself")
-		addPermission(constant.PermissionProblemListAwaitingReviewAll, "List all problems awaiting review")
-		addPermission(constant.PermissionProblemListAssignedTest, "List problems assigned to oneself as a tester")
-		addPermission(constant.PermissionProblemReadDetailsAny, "Read details of any problem")
-		addPermission(constant.PermissionProblemReadDetailsCreatedOwn, "Read details of problems created by oneself")
-		addPermission(
-			constant.PermissionProblemReadDetailsAwaitingReviewAny,
-			"Read details of any problems awaiting review",
-		)
-		addPermission(
-			constant.PermissionProblemReadDetailsAssignedTest,
-			"Read details of problems assigned to oneself as a tester",
-		)
-		addPermission(constant.PermissionProblemReviewAny, "Review any problem")
-		addPermission(constant.PermissionProblemReviewOverride, "Review any problem, even if not the reviewer")
-		addPermission(constant.PermissionProblemAssignTesters, "Assign testers to problems")
-		addPermission(
-			constant.PermissionProblemTestAssigned,
-			"Submit test result to problems assigned to oneself as a tester",
-		)
-		addPermission(
-			constant.PermissionProblemTestOverride,
-			"Submit test result to any problem, even if not assigned as a tester",
-		)
-
-		addPermission(constant.PermissionContestListAll, "List all contests")
-		addPermission(constant.PermissionContestReadDetailsAny, "Read details of any contest")
-
-		addPermission(constant.PermissionContestCreate, "Create a contest")
-		addPermission(constant.PermissionContestUpdateAny, "Update any contest")
-		addPermission(constant.PermissionContestDeleteAny, "Delete any contest")
-
-		addPermission(constant.PermissionContestAssignProblemAny, "Assign problems to any contest")
-		addPermission(constant.PermissionContestUnassignProblemAny, "Unassign problems from any contest")
+		if len(permissions) == 0 {
+			return nil
+		}
 
 		if err := g.Create(&permissions).Error; err != nil {
 			return errors.WrapIf(err, "failed to create permissions")
 		}
 
+		for _, permission := range permissions {
+			added = append(added, permission.Name)
+		}
+
 		return nil
 	})
+
+	return added, err
 }
 
 func (a *Application) seedRoles() error {
@@ -310,6 +315,7 @@ func (a *Application) seedRoles() error {
 			constant.PermissionProblemReadDetailsAwaitingReviewAny,
 			constant.PermissionProblemReviewAny,
 			constant.PermissionProblemAssignTesters,
+			constant.PermissionProblemCompleteAny,
 		}, false)
 
 		addRole("tester", "Problem tester", []string{
@@ -331,7 +337,10 @@ func (a *Application) seedRoles() error {
 			constant.PermissionContestDeleteAny,
 			constant.PermissionContestAssignProblemAny,
 			constant.PermissionContestUnassignProblemAny,
+			constant.PermissionContestExportAny,
+			constant.PermissionContestManageMembersAny,
 			constant.PermissionProblemListAll,
+			constant.PermissionProblemExportAny,
 		}, false)
 
 		permissionNames := make([]string, 0)
@@ -367,6 +376,52 @@ func (a *Application) seedRoles() error {
 	})
 }
 
+// addedPermissionGrants lists the seeded roles that hold permissions added to
+// the catalogue after the roles were first seeded.
+var addedPermissionGrants = map[string][]string{
+	constant.PermissionProblemCompleteAny:      {"reviewer"},
+	constant.PermissionProblemExportAny:        {"contest_manager"},
+	constant.PermissionContestExportAny:        {"contest_manager"},
+	constant.PermissionContestManageMembersAny: {"contest_manager"},
+}
+
+// grantAddedPermissions grants the permissions that were just added to the
+// seeded roles that hold them in a fresh database. Only newly added permissions
+// are granted, so that permissions later taken away from a role stay away.
+func (a *Application) grantAddedPermissions(added []string) error {
+	return a.ResolveDependencyFunc(func(g *gorm.DB) error {
+		for _, name := range added {
+			roleNames, ok := addedPermissionGrants[name]
+			if !ok {
+				continue
+			}
+
+			var permission database.Permission
+			if err := g.Where("name = ?", name).First(&permission).Error; err != nil {
+				return errors.WrapIf(err, "failed to get permission")
+			}
+
+			var roles []database.Role
+			if err := g.Where("name IN ?", roleNames).Find(&roles).Error; err != nil {
+				return errors.WrapIf(err, "failed to get roles")
+			}
+
+			for _, role := range roles {
+				if err := g.Table("role_permissions").
+					Clauses(clause.OnConflict{DoNothing: true}).
+					Create(map[string]any{
+						"role_role_id":             role.RoleID,
+						"permission_permission_id": permission.PermissionID,
+					}).Error; err != nil {
+					return errors.WrapIf(err, "failed to grant permission")
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
 func (a *Application) normalizeProblemStatuses(g *gorm.DB) error {
 	type statusUpdate struct {
 		from string
@@ -390,6 +445,114 @@ func (a *Application) normalizeProblemStatuses(g *gorm.DB) error {
 	return nil
 }
 
+// migrateContestAssignments moves the assignments of the legacy
+// problems.assigned_contest_id column into contest_problems, ordering each
+// contest's problems by creation time, and drops the column.
+func (a *Application) migrateContestAssignments(g *gorm.DB) error {
+	if !g.Migrator().HasColumn(&database.Problem{}, "assigned_contest_id") {
+		return nil
+	}
+
+	type assignment struct {
+		ProblemID         uuid.UUID
+		AssignedContestID uuid.UUID
+	}
+
+	var assignments []assignment
+	if err := g.Table("problems").
+		Select("problem_id", "assigned_contest_id").
+		Where("assigned_contest_id IS NOT NULL").
+		Order("created_at ASC").
+		Scan(&assignments).Error; err != nil {
+		return errors.WrapIf(err, "failed to get legacy contest assignments")
+	}
+
+	now := time.Now()
+	positions := make(map[uuid.UUID]uint)
+
+	contestProblems := make([]database.ContestProblem, 0, len(assignments))
+	for _, assignment := range assignments {
+		positions[assignment.AssignedContestID]++
+		position := positions[assignment.AssignedContestID]
+
+		contestProblems = append(contestProblems, database.ContestProblem{
+			ContestID: assignment.AssignedContestID,
+			ProblemID: assignment.ProblemID,
+			Position:  position,
+			Label:     contest.Label(position),
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	}
+
+	return g.Transaction(func(tx *gorm.DB) error {
+		if len(contestProblems) > 0 {
+			if err := tx.Create(&contestProblems).Error; err != nil {
+				return errors.WrapIf(err, "failed to create contest problems")
+			}
+		}
+
+		if err := tx.Migrator().DropColumn(&database.Problem{}, "assigned_contest_id"); err != nil {
+			return errors.WrapIf(err, "failed to drop legacy assigned contest column")
+		}
+
+		return nil
+	})
+}
+
+// backfillProblemSignatures signs the statements and examples of the versions
+// submitted before duplicates were detected, so that new problems are
+// compared with them too.
+func (a *Application) backfillProblemSignatures(g *gorm.DB) error {
+	var details []database.ProblemVersionDetail
+	if err := g.Where("signature IS NULL").
+		FindInBatches(&details, 100, func(tx *gorm.DB, _ int) error {
+			for _, detail := range details {
+				signature := problem.StatementSignature(
+					detail.Background,
+					detail.Statement,
+					detail.InputFormat,
+					detail.OutputFormat,
+				)
+
+				if err := g.Model(&database.ProblemVersionDetail{}).
+					Where("detail_id = ?", detail.DetailID).
+					Update("signature", signature.Bytes()).Error; err != nil {
+					return errors.WrapIf(err, "failed to update problem version detail signature")
+				}
+			}
+
+			return nil
+		}).Error; err != nil {
+		return errors.WrapIf(err, "failed to sign problem version details")
+	}
+
+	var versions []database.ProblemVersion
+	if err := g.Select("problem_version_id").
+		Preload("Examples").
+		Where("example_signature IS NULL").
+		FindInBatches(&versions, 100, func(tx *gorm.DB, _ int) error {
+			for _, version := range versions {
+				texts := make([]string, 0, 2*len(version.Examples))
+				for _, example := range version.Examples {
+					texts = append(texts, example.Input, example.Output)
+				}
+
+				if err := g.Model(&database.ProblemVersion{}).
+					Where("problem_version_id = ?", version.ProblemVersionID).
+					Update("example_signature", problem.ExampleSignature(texts...).Bytes()).Error; err != nil {
+					return errors.WrapIf(err, "failed to update problem version example signature")
+				}
+			}
+
+			return nil
+		}).Error; err != nil {
+		return errors.WrapIf(err, "failed to sign problem version examples")
+	}
+
+	return nil
+}
+
 func (a *Application) mustGenerateUUID(l logger.Logger) uuid.UUID {
 	id, err := uuid.NewV7()
 	if err != nil {
@@ -2,37 +2,63 @@ package applicationbuilder
 
 import (
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/acceptproposal"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/assignproblem"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/clonecontest"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/createcontest"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/deletecontest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/exportcontest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/getcontestreadiness"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/listassignedproblems"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/listcontest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/listcontestmembers"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/listproposals"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/removecontestmember"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/reorderproblems"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/setcontestmember"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/unassignproblem"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/updatecontest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest/feature/updatecontestproblem"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/media"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/media/feature/getmediacontent"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/media/feature/uploadmedia"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/websocket"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/assigntesters"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/checkoutdraft"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/exportproblem"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/getproblem"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/judgeproblem"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/listjudgerun"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/listmessage"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/listproblem"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/liststatushistory"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/markcomplete"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/retargetproblem"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/reviewproblem"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/searchproblem"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/sendmessage"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/testproblem"
 	problemInfra "github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/infrastructure"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/workflow"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdifficulty"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdifficulty/feature/listproblemdifficulty"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/feature/deleteproblemdraft"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/feature/generatetestcases"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/feature/importpolygonpackage"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/feature/listproblemdraft"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/feature/submitproblemdraft"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/feature/uploadtestcases"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/feature/upsertproblemdraft"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/feature/validatetestcases"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/user"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/user/feature/getcurrentuser"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/user/feature/listtester"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/user/feature/login"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/user/feature/logout"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/user/feature/managerole"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/user/feature/manageuser"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/user/feature/register"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/user/feature/requestemailverification"
@@ -102,6 +128,10 @@ func (b *ApplicationBuilder) addRoutes() error {
 		return errors.WrapIf(err, "failed to provide problem difficulty endpoint params")
 	}
 
+	if err := b.Container.Provide(media.NewEndpointParams); err != nil {
+		return errors.WrapIf(err, "failed to provide media endpoint params")
+	}
+
 	// ======== Endpoints ========
 	if err := b.Container.Provide(websocket.NewEndpoint); err != nil {
 		return errors.WrapIf(err, "failed to provide websocket endpoint")
@@ -139,6 +169,10 @@ func (b *ApplicationBuilder) addRoutes() error {
 		return errors.WrapIf(err, "failed to provide manage user endpoint")
 	}
 
+	if err := b.Container.Provide(managerole.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide manage role endpoint")
+	}
+
 	if err := b.Container.Provide(createcontest.NewEndpoint); err != nil {
 		return errors.WrapIf(err, "failed to provide create contest endpoint")
 	}
@@ -223,6 +257,98 @@ func (b *ApplicationBuilder) addRoutes() error {
 		return errors.WrapIf(err, "failed to provide list assigned problems endpoint")
 	}
 
+	if err := b.Container.Provide(uploadmedia.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide upload media endpoint")
+	}
+
+	if err := b.Container.Provide(getmediacontent.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide get media content endpoint")
+	}
+
+	if err := b.Container.Provide(uploadtestcases.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide upload testcases endpoint")
+	}
+
+	if err := b.Container.Provide(judgeproblem.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide judge problem endpoint")
+	}
+
+	if err := b.Container.Provide(listjudgerun.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide list judge run endpoint")
+	}
+
+	if err := b.Container.Provide(generatetestcases.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide generate testcases endpoint")
+	}
+
+	if err := b.Container.Provide(validatetestcases.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide validate testcases endpoint")
+	}
+
+	if err := b.Container.Provide(importpolygonpackage.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide import polygon package endpoint")
+	}
+
+	if err := b.Container.Provide(exportcontest.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide export contest endpoint")
+	}
+
+	if err := b.Container.Provide(exportproblem.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide export problem endpoint")
+	}
+
+	if err := b.Container.Provide(liststatushistory.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide list status history endpoint")
+	}
+
+	if err := b.Container.Provide(updatecontest.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide update contest endpoint")
+	}
+
+	if err := b.Container.Provide(listproposals.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide list proposals endpoint")
+	}
+
+	if err := b.Container.Provide(acceptproposal.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide accept proposal endpoint")
+	}
+
+	if err := b.Container.Provide(reorderproblems.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide reorder problems endpoint")
+	}
+
+	if err := b.Container.Provide(updatecontestproblem.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide update contest problem endpoint")
+	}
+
+	if err := b.Container.Provide(getcontestreadiness.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide get contest readiness endpoint")
+	}
+
+	if err := b.Container.Provide(listcontestmembers.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide list contest members endpoint")
+	}
+
+	if err := b.Container.Provide(setcontestmember.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide set contest member endpoint")
+	}
+
+	if err := b.Container.Provide(removecontestmember.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide remove contest member endpoint")
+	}
+
+	if err := b.Container.Provide(retargetproblem.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide retarget problem endpoint")
+	}
+
+	if err := b.Container.Provide(clonecontest.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide clone contest endpoint")
+	}
+
+	if err := b.Container.Provide(searchproblem.NewEndpoint); err != nil {
+		return errors.WrapIf(err, "failed to provide search problem endpoint")
+	}
+
 	if err := b.Container.Provide(listassignedproblems.NewQueryHandler); err != nil {
 		return errors.WrapIf(err, "failed to provide list assigned problems query handler")
 	}
@@ -258,6 +384,30 @@ func (b *ApplicationBuilder) addRoutes() error {
 		assignProblemEndpoint *assignproblem.Endpoint,
 		unassignProblemEndpoint *unassignproblem.Endpoint,
 		manageUserEndpoint *manageuser.Endpoint,
+		manageRoleEndpoint *managerole.Endpoint,
+		uploadMediaEndpoint *uploadmedia.Endpoint,
+		getMediaContentEndpoint *getmediacontent.Endpoint,
+		uploadTestcasesEndpoint *uploadtestcases.Endpoint,
+		judgeProblemEndpoint *judgeproblem.Endpoint,
+		listJudgeRunEndpoint *listjudgerun.Endpoint,
+		generateTestcasesEndpoint *generatetestcases.Endpoint,
+		validateTestcasesEndpoint *validatetestcases.Endpoint,
+		importPolygonPackageEndpoint *importpolygonpackage.Endpoint,
+		exportContestEndpoint *exportcontest.Endpoint,
+		exportProblemEndpoint *exportproblem.Endpoint,
+		listStatusHistoryEndpoint *liststatushistory.Endpoint,
+		updateContestEndpoint *updatecontest.Endpoint,
+		listProposalsEndpoint *listproposals.Endpoint,
+		acceptProposalEndpoint *acceptproposal.Endpoint,
+		reorderProblemsEndpoint *reorderproblems.Endpoint,
+		updateContestProblemEndpoint *updatecontestproblem.Endpoint,
+		getContestReadinessEndpoint *getcontestreadiness.Endpoint,
+		listContestMembersEndpoint *listcontestmembers.Endpoint,
+		setContestMemberEndpoint *setcontestmember.Endpoint,
+		removeContestMemberEndpoint *removecontestmember.Endpoint,
+		retargetProblemEndpoint *retargetproblem.Endpoint,
+		cloneContestEndpoint *clonecontest.Endpoint,
+		searchProblemEndpoint *searchproblem.Endpoint,
 	) []contract.Endpoint {
 		return []contract.Endpoint{
 			websocketEndpoint,
@@ -290,6 +440,30 @@ func (b *ApplicationBuilder) addRoutes() error {
 			assignProblemEndpoint,
 			unassignProblemEndpoint,
 			manageUserEndpoint,
+			manageRoleEndpoint,
+			uploadMediaEndpoint,
+			getMediaContentEndpoint,
+			uploadTestcasesEndpoint,
+			judgeProblemEndpoint,
+			listJudgeRunEndpoint,
+			generateTestcasesEndpoint,
+			validateTestcasesEndpoint,
+			importPolygonPackageEndpoint,
+			exportContestEndpoint,
+			exportProblemEndpoint,
+			listStatusHistoryEndpoint,
+			updateContestEndpoint,
+			listProposalsEndpoint,
+			acceptProposalEndpoint,
+			reorderProblemsEndpoint,
+			updateContestProblemEndpoint,
+			getContestReadinessEndpoint,
+			listContestMembersEndpoint,
+			setContestMemberEndpoint,
+			removeContestMemberEndpoint,
+			retargetProblemEndpoint,
+			cloneContestEndpoint,
+			searchProblemEndpoint,
 		}
 	}); err != nil {
 		return errors.WrapIf(err, "failed to provide endpoint array")
@@ -364,6 +538,15 @@ func (b *ApplicationBuilder) addRepositories() error {
 		return errors.WrapIf(err, "failed to provide checkout draft repository")
 	}
 
+	if err := b.Container.Provide(workflow.NewGormRepository,
+		dig.As(new(workflow.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide problem workflow repository")
+	}
+
+	if err := b.Container.Provide(workflow.NewMachine); err != nil {
+		return errors.WrapIf(err, "failed to provide problem workflow machine")
+	}
+
 	if err := b.Container.Provide(problemInfra.NewProblemActionGormRepository,
 		dig.As(new(problemInfra.ProblemActionRepository)),
 		dig.As(new(reviewproblem.Repository)),
@@ -371,13 +554,18 @@ func (b *ApplicationBuilder) addRepositories() error {
 		return errors.WrapIf(err, "failed to provide shared problem action repository")
 	}
 
-	if err := b.Container.Provide(assigntesters.NewGormRepository,
-		dig.As(new(assigntesters.Repository))); err != nil {
-		return errors.WrapIf(err, "failed to provide assign tester repository")
+	if err := b.Container.Provide(problemInfra.NewRoomAccessPolicy,
+		dig.As(new(contract.RoomAccessPolicy))); err != nil {
+		return errors.WrapIf(err, "failed to provide problem room access policy")
+	}
+
+	if err := b.Container.Provide(problemInfra.NewProblemFollowerGormRepository,
+		dig.As(new(contract.ProblemFollowerProvider))); err != nil {
+		return errors.WrapIf(err, "failed to provide problem follower repository")
 	}
 
-	if err := b.Container.Provide(markcomplete.NewGormRepository,
-		dig.As(new(markcomplete.Repository))); err != nil {
+	if err := b.Container.Provide(assigntesters.NewGormRepository,
+		dig.As(new(assigntesters.Repository))); err != nil {
 		return errors.WrapIf(err, "failed to provide assign tester repository")
 	}
 
@@ -431,5 +619,125 @@ func (b *ApplicationBuilder) addRepositories() error {
 		return errors.WrapIf(err, "failed to provide manage user repository")
 	}
 
+	if err := b.Container.Provide(managerole.NewGormRepository,
+		dig.As(new(managerole.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide manage role repository")
+	}
+
+	if err := b.Container.Provide(uploadmedia.NewGormRepository,
+		dig.As(new(uploadmedia.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide upload media repository")
+	}
+
+	if err := b.Container.Provide(getmediacontent.NewGormRepository,
+		dig.As(new(getmediacontent.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide get media content repository")
+	}
+
+	if err := b.Container.Provide(uploadtestcases.NewGormRepository,
+		dig.As(new(uploadtestcases.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide upload testcases repository")
+	}
+
+	if err := b.Container.Provide(judgeproblem.NewGormRepository,
+		dig.As(new(judgeproblem.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide judge problem repository")
+	}
+
+	if err := b.Container.Provide(listjudgerun.NewGormRepository,
+		dig.As(new(listjudgerun.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide list judge run repository")
+	}
+
+	if err := b.Container.Provide(generatetestcases.NewGormRepository,
+		dig.As(new(generatetestcases.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide generate testcases repository")
+	}
+
+	if err := b.Container.Provide(validatetestcases.NewGormRepository,
+		dig.As(new(validatetestcases.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide validate testcases repository")
+	}
+
+	if err := b.Container.Provide(importpolygonpackage.NewGormRepository,
+		dig.As(new(importpolygonpackage.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide import polygon package repository")
+	}
+
+	if err := b.Container.Provide(exportcontest.NewGormRepository,
+		dig.As(new(exportcontest.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide export contest repository")
+	}
+
+	if err := b.Container.Provide(exportproblem.NewGormRepository,
+		dig.As(new(exportproblem.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide export problem repository")
+	}
+
+	if err := b.Container.Provide(liststatushistory.NewGormRepository,
+		dig.As(new(liststatushistory.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide list status history repository")
+	}
+
+	if err := b.Container.Provide(updatecontest.NewGormRepository,
+		dig.As(new(updatecontest.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide update contest repository")
+	}
+
+	if err := b.Container.Provide(listproposals.NewGormRepository,
+		dig.As(new(listproposals.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide list proposals repository")
+	}
+
+	if err := b.Container.Provide(acceptproposal.NewGormRepository,
+		dig.As(new(acceptproposal.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide accept proposal repository")
+	}
+
+	if err := b.Container.Provide(reorderproblems.NewGormRepository,
+		dig.As(new(reorderproblems.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide reorder problems repository")
+	}
+
+	if err := b.Container.Provide(updatecontestproblem.NewGormRepository,
+		dig.As(new(updatecontestproblem.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide update contest problem repository")
+	}
+
+	if err := b.Container.Provide(getcontestreadiness.NewGormRepository,
+		dig.As(new(getcontestreadiness.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide get contest readiness repository")
+	}
+
+	if err := b.Container.Provide(listcontestmembers.NewGormRepository,
+		dig.As(new(listcontestmembers.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide list contest members repository")
+	}
+
+	if err := b.Container.Provide(setcontestmember.NewGormRepository,
+		dig.As(new(setcontestmember.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide set contest member repository")
+	}
+
+	if err := b.Container.Provide(removecontestmember.NewGormRepository,
+		dig.As(new(removecontestmember.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide remove contest member repository")
+	}
+
+	if err := b.Container.Provide(retargetproblem.NewGormRepository,
+		dig.As(new(retargetproblem.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide retarget problem repository")
+	}
+
+	if err := b.Container.Provide(clonecontest.NewGormRepository,
+		dig.As(new(clonecontest.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide clone contest repository")
+	}
+
+	if err := b.Container.Provide(searchproblem.NewGormRepository,
+		dig.As(new(searchproblem.Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide search problem repository")
+	}
+
 	return nil
 }
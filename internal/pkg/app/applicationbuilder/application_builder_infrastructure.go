@@ -1,11 +1,16 @@
 package applicationbuilder
 
 import (
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/blobstore"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/config"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/echoweb"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/judge"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/mailing"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/outbox"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/postmark"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/storage"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/websocket"
 )
 
@@ -31,6 +36,14 @@ func (b *ApplicationBuilder) AddInfrastructure() {
 		b.Logger.Fatal(err)
 	}
 
+	if err := b.Container.Provide(func(cfg *config.Config) *storage.Options { return &cfg.StorageOptions }); err != nil {
+		b.Logger.Fatal(err)
+	}
+
+	if err := b.Container.Provide(func(cfg *config.Config) *judge.Options { return &cfg.JudgeOptions }); err != nil {
+		b.Logger.Fatal(err)
+	}
+
 	if err := database.AddGorm(b.Container); err != nil {
 		b.Logger.Fatal(err)
 	}
@@ -42,4 +55,26 @@ func (b *ApplicationBuilder) AddInfrastructure() {
 	if err := websocket.AddWebsocket(b.Container); err != nil {
 		b.Logger.Fatal(err)
 	}
+
+	if err := b.Container.Provide(func(p *websocket.Publisher) []contract.OutboxHandler {
+		return []contract.OutboxHandler{p}
+	}); err != nil {
+		b.Logger.Fatal(err)
+	}
+
+	if err := outbox.AddOutbox(b.Container); err != nil {
+		b.Logger.Fatal(err)
+	}
+
+	if err := storage.AddStorage(b.Container); err != nil {
+		b.Logger.Fatal(err)
+	}
+
+	if err := blobstore.AddBlobStore(b.Container); err != nil {
+		b.Logger.Fatal(err)
+	}
+
+	if err := judge.AddJudge(b.Container); err != nil {
+		b.Logger.Fatal(err)
+	}
 }
@@ -0,0 +1,121 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const blobMIMEType = "application/octet-stream"
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+type BlobStore struct {
+	db      *gorm.DB
+	storage contract.FileStorage
+}
+
+func NewBlobStore(db *gorm.DB, storage contract.FileStorage) *BlobStore {
+	return &BlobStore{
+		db:      db,
+		storage: storage,
+	}
+}
+
+// Put spools the content to a temporary file to compute its hash before
+// uploading, since the storage key depends on the hash.
+func (s *BlobStore) Put(ctx context.Context, r io.Reader) (contract.BlobInfo, error) {
+	tmp, err := os.CreateTemp("", "blob-*")
+	if err != nil {
+		return contract.BlobInfo{}, errors.WrapIf(err, "failed to create temporary file")
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hasher), r)
+	if err != nil {
+		return contract.BlobInfo{}, errors.WrapIf(err, "failed to read blob content")
+	}
+
+	info := contract.BlobInfo{
+		Hash: hex.EncodeToString(hasher.Sum(nil)),
+		Size: uint64(size),
+	}
+
+	db := database.GetDBFromContext(ctx, s.db)
+
+	var count int64
+	if err := db.WithContext(ctx).
+		Model(&database.Blob{}).
+		Where("hash = ?", info.Hash).
+		Count(&count).Error; err != nil {
+		return contract.BlobInfo{}, errors.WrapIf(err, "failed to check existing blob")
+	}
+
+	if count > 0 {
+		return info, nil
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return contract.BlobInfo{}, errors.WrapIf(err, "failed to rewind temporary file")
+	}
+
+	key := storageKey(info.Hash)
+	if err := s.storage.Save(ctx, key, tmp, size, blobMIMEType); err != nil {
+		return contract.BlobInfo{}, errors.WrapIf(err, "failed to save blob to storage")
+	}
+
+	// Concurrent uploads of the same content write the same object, so losing
+	// the insert race is harmless.
+	if err := db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&database.Blob{
+			Hash:       info.Hash,
+			Size:       info.Size,
+			StorageKey: key,
+			CreatedAt:  time.Now(),
+		}).Error; err != nil {
+		return contract.BlobInfo{}, errors.WrapIf(err, "failed to create blob")
+	}
+
+	return info, nil
+}
+
+func (s *BlobStore) Open(ctx context.Context, hash string) (io.ReadCloser, error) {
+	db := database.GetDBFromContext(ctx, s.db)
+
+	var blob database.Blob
+	if err := db.WithContext(ctx).
+		Where("hash = ?", hash).
+		First(&blob).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithStack(ErrBlobNotFound)
+		}
+
+		return nil, errors.WrapIf(err, "failed to get blob")
+	}
+
+	r, err := s.storage.Open(ctx, blob.StorageKey)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to open blob from storage")
+	}
+
+	return r, nil
+}
+
+func storageKey(hash string) string {
+	return "blobs/" + hash[:2] + "/" + hash
+}
@@ -0,0 +1,16 @@
+package blobstore
+
+import (
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+
+	"emperror.dev/errors"
+	"go.uber.org/dig"
+)
+
+func AddBlobStore(container *dig.Container) error {
+	if err := container.Provide(NewBlobStore, dig.As(new(contract.BlobStore))); err != nil {
+		return errors.WrapIf(err, "failed to provide blob store")
+	}
+
+	return nil
+}
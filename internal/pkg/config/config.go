@@ -4,9 +4,11 @@ import (
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/environment"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/echoweb"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/judge"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/mailing"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/postmark"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/storage"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/websocket"
 )
 
@@ -20,4 +22,6 @@ type Config struct {
 	GomailOptions            mailing.Options         `mapstructure:"GOMAILOPTIONS"`
 	WebsocketOptions         websocket.Options       `mapstructure:"WEBSOCKETOPTIONS"`
 	LoggerOptions            logger.Options          `mapstructure:"LOGGEROPTIONS"`
+	StorageOptions           storage.Options         `mapstructure:"STORAGEOPTIONS"`
+	JudgeOptions             judge.Options           `mapstructure:"JUDGEOPTIONS"`
 }
@@ -51,6 +51,29 @@ func BindAllConfigs() (*Config, error) {
 	// WebsocketOptions
 	_ = viper.BindEnv("websocketOptions.skipTLSVerification", "WS_SKIP_TLS_VERIFICATION")
 	_ = viper.BindEnv("websocketOptions.originPatterns", "WS_ORIGIN_PATTERNS")
+	_ = viper.BindEnv("websocketOptions.replayWindow", "WS_REPLAY_WINDOW")
+	_ = viper.BindEnv("websocketOptions.backplane", "WS_BACKPLANE")
+
+	// StorageOptions
+	_ = viper.BindEnv("storageOptions.driver", "STORAGE_DRIVER")
+	_ = viper.BindEnv("storageOptions.maxFileSize", "STORAGE_MAX_FILE_SIZE")
+	_ = viper.BindEnv("storageOptions.allowedMIMETypes", "STORAGE_ALLOWED_MIME_TYPES")
+	_ = viper.BindEnv("storageOptions.localDirectory", "STORAGE_LOCAL_DIRECTORY")
+	_ = viper.BindEnv("storageOptions.s3Endpoint", "STORAGE_S3_ENDPOINT")
+	_ = viper.BindEnv("storageOptions.s3Region", "STORAGE_S3_REGION")
+	_ = viper.BindEnv("storageOptions.s3Bucket", "STORAGE_S3_BUCKET")
+	_ = viper.BindEnv("storageOptions.s3AccessKeyID", "STORAGE_S3_ACCESS_KEY_ID")
+	_ = viper.BindEnv("storageOptions.s3SecretAccessKey", "STORAGE_S3_SECRET_ACCESS_KEY")
+	_ = viper.BindEnv("storageOptions.s3UseSSL", "STORAGE_S3_USE_SSL")
+
+	// JudgeOptions
+	_ = viper.BindEnv("judgeOptions.enabled", "JUDGE_ENABLED")
+	_ = viper.BindEnv("judgeOptions.workers", "JUDGE_WORKERS")
+	_ = viper.BindEnv("judgeOptions.workDir", "JUDGE_WORK_DIR")
+	_ = viper.BindEnv("judgeOptions.pollInterval", "JUDGE_POLL_INTERVAL")
+	_ = viper.BindEnv("judgeOptions.runAsUID", "JUDGE_RUN_AS_UID")
+	_ = viper.BindEnv("judgeOptions.runAsGID", "JUDGE_RUN_AS_GID")
+	_ = viper.BindEnv("judgeOptions.testlibDir", "JUDGE_TESTLIB_DIR")
 
 	cfg := &Config{}
 	if err := viper.Unmarshal(cfg); err != nil {
@@ -0,0 +1,19 @@
+package constant
+
+const (
+	CheckerTypeExact  = "exact"  // Line by line, ignoring trailing whitespace
+	CheckerTypeToken  = "token"  // Whitespace-separated tokens
+	CheckerTypeFloat  = "float"  // Tokens, numbers within an absolute or relative epsilon
+	CheckerTypeYesNo  = "yesno"  // Tokens, case-insensitive
+	CheckerTypeCustom = "custom" // A testlib-compatible checker program
+)
+
+var CheckerTypes = []string{
+	CheckerTypeExact,
+	CheckerTypeToken,
+	CheckerTypeFloat,
+	CheckerTypeYesNo,
+	CheckerTypeCustom,
+}
+
+const DefaultCheckerEpsilon = 1e-6
@@ -1,9 +1,10 @@
 package constant
 
 const (
-	BodyLimit  = "2M"
-	GzipLevel  = 5
-	Dev        = "development"
-	Test       = "test"
-	Production = "production"
+	BodyLimit       = "2M"
+	UploadBodyLimit = "300M"
+	GzipLevel       = 5
+	Dev             = "development"
+	Test            = "test"
+	Production      = "production"
 )
@@ -0,0 +1,64 @@
+package constant
+
+import "slices"
+
+// ContestMemberRole is a user's staff role within a single contest. It grants
+// its permissions for that contest only, on top of the user's global roles.
+type ContestMemberRole string
+
+const (
+	ContestMemberRoleCoordinator ContestMemberRole = "coordinator"
+	ContestMemberRoleReviewer    ContestMemberRole = "reviewer"
+	ContestMemberRoleTester      ContestMemberRole = "tester"
+)
+
+var contestMemberRolePermissions = map[ContestMemberRole][]string{
+	ContestMemberRoleCoordinator: {
+		PermissionContestReadDetailsAny,
+		PermissionContestUpdateAny,
+		PermissionContestAssignProblemAny,
+		PermissionContestUnassignProblemAny,
+		PermissionContestManageMembersAny,
+		PermissionProblemListAll,
+		PermissionProblemReadDetailsAny,
+		PermissionProblemAssignTesters,
+		PermissionProblemCompleteAny,
+	},
+	ContestMemberRoleReviewer: {
+		PermissionContestReadDetailsAny,
+		PermissionProblemListAll,
+		PermissionProblemReadDetailsAny,
+		PermissionProblemReviewAny,
+	},
+	ContestMemberRoleTester: {
+		PermissionContestReadDetailsAny,
+		PermissionProblemListAll,
+		PermissionProblemReadDetailsAny,
+		PermissionProblemTestAssigned,
+	},
+}
+
+func FromStringToContestMemberRole(role string) ContestMemberRole {
+	switch role {
+	case string(ContestMemberRoleCoordinator):
+		return ContestMemberRoleCoordinator
+	case string(ContestMemberRoleReviewer):
+		return ContestMemberRoleReviewer
+	case string(ContestMemberRoleTester):
+		return ContestMemberRoleTester
+	default:
+		return ""
+	}
+}
+
+// Grants reports whether the role grants any of the permissions within its
+// contest.
+func (r ContestMemberRole) Grants(permissionNames ...string) bool {
+	for _, permissionName := range permissionNames {
+		if slices.Contains(contestMemberRolePermissions[r], permissionName) {
+			return true
+		}
+	}
+
+	return false
+}
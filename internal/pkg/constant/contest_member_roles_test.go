@@ -0,0 +1,45 @@
+package constant
+
+import "testing"
+
+func TestContestMemberRoleGrants(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       ContestMemberRole
+		permission string
+		expected   bool
+	}{
+		{
+			name:       "Coordinator manages members",
+			role:       ContestMemberRoleCoordinator,
+			permission: PermissionContestManageMembersAny,
+			expected:   true,
+		},
+		{
+			name:       "Reviewer does not manage members",
+			role:       ContestMemberRoleReviewer,
+			permission: PermissionContestManageMembersAny,
+			expected:   false,
+		},
+		{
+			name:       "Tester reads the contest's problems",
+			role:       ContestMemberRoleTester,
+			permission: PermissionProblemReadDetailsAny,
+			expected:   true,
+		},
+		{
+			name:       "Unknown role grants nothing",
+			role:       FromStringToContestMemberRole("owner"),
+			permission: PermissionProblemListAll,
+			expected:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.role.Grants(tt.permission); got != tt.expected {
+				t.Errorf("Grants(%q) = %v, want %v", tt.permission, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package constant
+
+type ContestPhase string
+
+const (
+	ContestPhaseProposal         ContestPhase = "proposal"
+	ContestPhaseProblemSelection ContestPhase = "problem_selection"
+	ContestPhaseFrozen           ContestPhase = "frozen"
+	ContestPhasePublished        ContestPhase = "published"
+	ContestPhaseArchived         ContestPhase = "archived"
+)
+
+func FromStringToContestPhase(phase string) ContestPhase {
+	switch phase {
+	case string(ContestPhaseProposal):
+		return ContestPhaseProposal
+	case string(ContestPhaseProblemSelection):
+		return ContestPhaseProblemSelection
+	case string(ContestPhaseFrozen):
+		return ContestPhaseFrozen
+	case string(ContestPhasePublished):
+		return ContestPhasePublished
+	case string(ContestPhaseArchived):
+		return ContestPhaseArchived
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,8 @@
+package constant
+
+const (
+	JudgeRunStatusQueued   = "queued"
+	JudgeRunStatusRunning  = "running"
+	JudgeRunStatusFinished = "finished"
+	JudgeRunStatusFailed   = "failed" // The run could not be judged, e.g. missing test data
+)
@@ -4,3 +4,6 @@ const (
 	LanguageEnUS = "en-US"
 	LanguageZhCN = "zh-CN"
 )
+
+// Languages lists the languages every problem statement is expected in.
+var Languages = []string{LanguageEnUS, LanguageZhCN}
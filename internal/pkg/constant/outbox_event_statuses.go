@@ -0,0 +1,8 @@
+package constant
+
+const (
+	OutboxEventStatusPending    = "pending"
+	OutboxEventStatusLeased     = "leased" // Being handled until its next attempt is due
+	OutboxEventStatusDispatched = "dispatched"
+	OutboxEventStatusFailed     = "failed" // Given up on after too many attempts
+)
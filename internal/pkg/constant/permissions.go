@@ -31,6 +31,8 @@ const (
 	PermissionProblemAssignTesters                = "problem:assign:testers"
 	PermissionProblemTestAssigned                 = "problem:test:assigned"
 	PermissionProblemTestOverride                 = "problem:test:override"
+	PermissionProblemCompleteAny                  = "problem:complete:any"
+	PermissionProblemExportAny                    = "problem:export:any"
 
 	PermissionContestListAll            = "contest:list_all"
 	PermissionContestReadDetailsAny     = "contest:read_details_any"
@@ -39,4 +41,59 @@ const (
 	PermissionContestDeleteAny          = "contest:delete_any"
 	PermissionContestAssignProblemAny   = "contest:assign_problem_any"
 	PermissionContestUnassignProblemAny = "contest:unassign_problem_any"
+	PermissionContestExportAny          = "contest:export_any"
+	PermissionContestManageMembersAny   = "contest:manage_members_any"
 )
+
+type PermissionInfo struct {
+	Name        string
+	Description string
+}
+
+// Permissions is the catalogue of every permission that can be granted to a
+// role. Permissions missing from the database are seeded from it on startup.
+var Permissions = []PermissionInfo{
+	{PermissionMediaUploadForDraftOwn, "Upload media for one's own problem draft"},
+	{PermissionMediaUploadForChatOwn, "Upload media for one's own problem chat"},
+
+	{PermissionUserListAll, "List all users"},
+	{PermissionUserReadProfileAny, "Read any user's profile"},
+	{PermissionUserReadProfileOwn, "Read one's own profile"},
+	{PermissionUserUpdateProfileOwn, "Update one's own profile"},
+	{PermissionUserManageRolesAny, "Manage any user's roles (assign/remove)"},
+
+	{PermissionRoleListAll, "List all roles"},
+	{PermissionRoleManageAny, "Manage any role (create/update/delete)"},
+
+	{PermissionProblemDraftCreate, "Create a problem draft"},
+	{PermissionProblemDraftReadOwn, "Read one's own problem draft"},
+	{PermissionProblemDraftUpdateOwn, "Update one's own problem draft"},
+	{PermissionProblemDraftDeleteOwn, "Delete one's own problem draft"},
+	{PermissionProblemDraftSubmitOwn, "Submit one's own problem draft"},
+
+	{PermissionProblemListAll, "List all problems"},
+	{PermissionProblemListCreatedOwn, "List problems created by oneself"},
+	{PermissionProblemListAwaitingReviewAll, "List all problems awaiting review"},
+	{PermissionProblemListAssignedTest, "List problems assigned to oneself as a tester"},
+	{PermissionProblemReadDetailsAny, "Read details of any problem"},
+	{PermissionProblemReadDetailsCreatedOwn, "Read details of problems created by oneself"},
+	{PermissionProblemReadDetailsAwaitingReviewAny, "Read details of any problems awaiting review"},
+	{PermissionProblemReadDetailsAssignedTest, "Read details of problems assigned to oneself as a tester"},
+	{PermissionProblemReviewAny, "Review any problem"},
+	{PermissionProblemReviewOverride, "Review any problem, even if not the reviewer"},
+	{PermissionProblemAssignTesters, "Assign testers to problems"},
+	{PermissionProblemTestAssigned, "Submit test result to problems assigned to oneself as a tester"},
+	{PermissionProblemTestOverride, "Submit test result to any problem, even if not assigned as a tester"},
+	{PermissionProblemCompleteAny, "Mark any problem that passed testing as completed"},
+	{PermissionProblemExportAny, "Export any completed problem as a problem package"},
+
+	{PermissionContestListAll, "List all contests"},
+	{PermissionContestReadDetailsAny, "Read details of any contest"},
+	{PermissionContestCreate, "Create a contest"},
+	{PermissionContestUpdateAny, "Update any contest"},
+	{PermissionContestDeleteAny, "Delete any contest"},
+	{PermissionContestAssignProblemAny, "Assign problems to any contest"},
+	{PermissionContestUnassignProblemAny, "Unassign problems from any contest"},
+	{PermissionContestExportAny, "Export any contest as problem packages"},
+	{PermissionContestManageMembersAny, "Manage the staff of any contest"},
+}
@@ -0,0 +1,7 @@
+package constant
+
+// Defaults applied to drafts that do not specify their own resource limits.
+const (
+	DefaultTimeLimitMs   = 1000
+	DefaultMemoryLimitMb = 256
+)
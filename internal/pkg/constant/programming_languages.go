@@ -0,0 +1,25 @@
+package constant
+
+const (
+	ProgrammingLanguageC       = "c"
+	ProgrammingLanguageCpp17   = "cpp17"
+	ProgrammingLanguageCpp20   = "cpp20"
+	ProgrammingLanguagePython3 = "python3"
+	ProgrammingLanguageJava    = "java"
+)
+
+// ProgrammingLanguages lists the languages solutions can be written in.
+var ProgrammingLanguages = []string{
+	ProgrammingLanguageC,
+	ProgrammingLanguageCpp17,
+	ProgrammingLanguageCpp20,
+	ProgrammingLanguagePython3,
+	ProgrammingLanguageJava,
+}
+
+// TestlibLanguages lists the languages of checkers, interactors, validators
+// and generators, which are built against testlib.
+var TestlibLanguages = []string{
+	ProgrammingLanguageCpp17,
+	ProgrammingLanguageCpp20,
+}
@@ -0,0 +1,61 @@
+package constant
+
+type Verdict string
+
+const (
+	VerdictAccepted            Verdict = "accepted"
+	VerdictWrongAnswer         Verdict = "wrong_answer"
+	VerdictTimeLimitExceeded   Verdict = "time_limit_exceeded"
+	VerdictMemoryLimitExceeded Verdict = "memory_limit_exceeded"
+	VerdictRuntimeError        Verdict = "runtime_error"
+	VerdictCompilationError    Verdict = "compilation_error"
+	VerdictRejected            Verdict = "rejected" // Any verdict other than accepted
+)
+
+// ExpectedVerdicts lists the verdicts a solution can be expected to get.
+var ExpectedVerdicts = []Verdict{
+	VerdictAccepted,
+	VerdictWrongAnswer,
+	VerdictTimeLimitExceeded,
+	VerdictMemoryLimitExceeded,
+	VerdictRuntimeError,
+	VerdictRejected,
+}
+
+// TesterVerdicts lists the verdicts testers can report for a solution they ran.
+var TesterVerdicts = []Verdict{
+	VerdictAccepted,
+	VerdictWrongAnswer,
+	VerdictTimeLimitExceeded,
+	VerdictMemoryLimitExceeded,
+	VerdictRuntimeError,
+}
+
+// Matches reports whether an observed verdict satisfies this expected verdict.
+func (v Verdict) Matches(actual Verdict) bool {
+	if v == VerdictRejected {
+		return actual != VerdictAccepted
+	}
+
+	return v == actual
+}
+
+// Abbreviation returns the short form judges usually display, e.g. "AC".
+func (v Verdict) Abbreviation() string {
+	switch v {
+	case VerdictAccepted:
+		return "AC"
+	case VerdictWrongAnswer:
+		return "WA"
+	case VerdictTimeLimitExceeded:
+		return "TLE"
+	case VerdictMemoryLimitExceeded:
+		return "MLE"
+	case VerdictRuntimeError:
+		return "RE"
+	case VerdictCompilationError:
+		return "CE"
+	default:
+		return string(v)
+	}
+}
@@ -0,0 +1,25 @@
+package constant
+
+import "testing"
+
+func TestVerdictMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected Verdict
+		actual   Verdict
+		want     bool
+	}{
+		{name: "same verdict", expected: VerdictWrongAnswer, actual: VerdictWrongAnswer, want: true},
+		{name: "different verdict", expected: VerdictTimeLimitExceeded, actual: VerdictWrongAnswer, want: false},
+		{name: "rejected accepts failure", expected: VerdictRejected, actual: VerdictRuntimeError, want: true},
+		{name: "rejected refuses accepted", expected: VerdictRejected, actual: VerdictAccepted, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.expected.Matches(tt.actual); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -22,6 +22,14 @@ type AuthProvider interface {
 	GetUser(ctx context.Context) (*AuthUser, error)
 
 	Can(ctx context.Context, permissionNames ...string) (bool, error)
+	// CanInContest is Can with the user's staff role in the contest counted
+	// alongside their global roles.
+	CanInContest(ctx context.Context, contestID uuid.UUID, permissionNames ...string) (bool, error)
+	// ContestsWhereCan lists the contests in which the user's staff role grants
+	// any of the permissions. Global roles are not considered.
+	ContestsWhereCan(ctx context.Context, permissionNames ...string) ([]uuid.UUID, error)
+	// ContestsWhereUserCan is ContestsWhereCan for any user.
+	ContestsWhereUserCan(ctx context.Context, userID uuid.UUID, permissionNames ...string) ([]uuid.UUID, error)
 
 	MustGetUser(ctx context.Context) (AuthUser, error)
 	MustGetUserDetails(ctx context.Context, userID uuid.UUID) (*AuthUserDetails, error)
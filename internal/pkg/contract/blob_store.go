@@ -0,0 +1,18 @@
+package contract
+
+import (
+	"context"
+	"io"
+)
+
+type BlobInfo struct {
+	Hash string
+	Size uint64
+}
+
+// BlobStore stores immutable content-addressed objects. Putting the same content
+// twice yields the same hash and does not duplicate the stored object.
+type BlobStore interface {
+	Put(ctx context.Context, r io.Reader) (BlobInfo, error)
+	Open(ctx context.Context, hash string) (io.ReadCloser, error)
+}
@@ -0,0 +1,125 @@
+// Package contracttest provides implementations of the contracts for tests.
+package contracttest
+
+import (
+	"context"
+	"slices"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+// AuthProvider is logged in as UserID, or not logged in when it is uuid.Nil.
+// Users hold the permissions of their details globally, and those of
+// ContestPermissions in a contest.
+type AuthProvider struct {
+	UserID             uuid.UUID
+	Users              map[uuid.UUID]*contract.AuthUserDetails
+	ContestPermissions map[uuid.UUID]map[uuid.UUID][]string // By user, then by contest
+}
+
+func NewAuthProvider(userID uuid.UUID, details *contract.AuthUserDetails) *AuthProvider {
+	return &AuthProvider{
+		UserID:             userID,
+		Users:              map[uuid.UUID]*contract.AuthUserDetails{userID: details},
+		ContestPermissions: make(map[uuid.UUID]map[uuid.UUID][]string),
+	}
+}
+
+// AddUser adds another user holding the permissions.
+func (p *AuthProvider) AddUser(userID uuid.UUID, permissions ...string) {
+	p.Users[userID] = &contract.AuthUserDetails{
+		Username:    userID.String(),
+		Permissions: permissions,
+	}
+}
+
+// GrantInContest gives the user the permissions in the contest.
+func (p *AuthProvider) GrantInContest(userID uuid.UUID, contestID uuid.UUID, permissions ...string) {
+	if p.ContestPermissions[userID] == nil {
+		p.ContestPermissions[userID] = make(map[uuid.UUID][]string)
+	}
+
+	p.ContestPermissions[userID][contestID] = append(p.ContestPermissions[userID][contestID], permissions...)
+}
+
+func (p *AuthProvider) GetUser(context.Context) (*contract.AuthUser, error) {
+	if p.UserID == uuid.Nil {
+		return nil, nil
+	}
+
+	return &contract.AuthUser{UserID: p.UserID, Email: p.UserID.String() + "@example.com"}, nil
+}
+
+func (p *AuthProvider) MustGetUser(ctx context.Context) (contract.AuthUser, error) {
+	user, _ := p.GetUser(ctx)
+	if user == nil {
+		return contract.AuthUser{}, errors.WithStack(customerror.ErrNotAuthenticated)
+	}
+
+	return *user, nil
+}
+
+func (p *AuthProvider) MustGetUserDetails(_ context.Context, userID uuid.UUID) (*contract.AuthUserDetails, error) {
+	details, ok := p.Users[userID]
+	if !ok {
+		return nil, errors.Errorf("user %s not found", userID)
+	}
+
+	return details, nil
+}
+
+func (p *AuthProvider) Can(ctx context.Context, permissionNames ...string) (bool, error) {
+	user, err := p.MustGetUser(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	details, err := p.MustGetUserDetails(ctx, user.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	return details.IsSuperAdmin || containsAny(details.Permissions, permissionNames), nil
+}
+
+func (p *AuthProvider) CanInContest(ctx context.Context, contestID uuid.UUID, permissionNames ...string) (bool, error) {
+	if can, err := p.Can(ctx, permissionNames...); err != nil || can {
+		return can, err
+	}
+
+	return containsAny(p.ContestPermissions[p.UserID][contestID], permissionNames), nil
+}
+
+func (p *AuthProvider) ContestsWhereCan(ctx context.Context, permissionNames ...string) ([]uuid.UUID, error) {
+	user, err := p.MustGetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.ContestsWhereUserCan(ctx, user.UserID, permissionNames...)
+}
+
+func (p *AuthProvider) ContestsWhereUserCan(
+	_ context.Context,
+	userID uuid.UUID,
+	permissionNames ...string,
+) ([]uuid.UUID, error) {
+	var contestIDs []uuid.UUID
+	for contestID, permissions := range p.ContestPermissions[userID] {
+		if containsAny(permissions, permissionNames) {
+			contestIDs = append(contestIDs, contestID)
+		}
+	}
+
+	return contestIDs, nil
+}
+
+func containsAny(permissions []string, permissionNames []string) bool {
+	return slices.ContainsFunc(permissions, func(permission string) bool {
+		return slices.Contains(permissionNames, permission)
+	})
+}
@@ -0,0 +1,12 @@
+package contract
+
+import (
+	"context"
+	"io"
+)
+
+type FileStorage interface {
+	Save(ctx context.Context, key string, r io.Reader, size int64, mimeType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,8 @@
+package contract
+
+// JudgeQueue wakes the judge workers after judge runs have been queued. Runs
+// are queued by inserting them into the database, so that they survive
+// restarts; Notify only spares the workers from waiting for their next poll.
+type JudgeQueue interface {
+	Notify()
+}
@@ -1,8 +1,12 @@
 package contract
 
 import (
+	"context"
+	"fmt"
 	"time"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
 	"github.com/google/uuid"
 )
 
@@ -18,6 +22,15 @@ const (
 	MessageTypeReviewed  MessageType = "reviewed"
 	MessageTypeTested    MessageType = "tested"
 	MessageTypeCompleted MessageType = "completed"
+	MessageTypeJudged    MessageType = "judged"
+	MessageTypeDuplicate MessageType = "duplicate"
+
+	MessageTypeTestersAssigned MessageType = "testers_assigned"
+
+	MessageTypeProblemAssigned   MessageType = "problem_assigned"
+	MessageTypeProblemUnassigned MessageType = "problem_unassigned"
+
+	MessageTypeNotification MessageType = "notification"
 )
 
 type MessageUser struct {
@@ -32,8 +45,48 @@ type MessageAttachment struct {
 	Size     uint64 `json:"size"`
 }
 
+// MessageJudgeRun summarizes how a reference or wrong solution fared against
+// the testcases of a problem version.
+type MessageJudgeRun struct {
+	JudgeRunID      uuid.UUID `json:"judge_run_id"`
+	SolutionName    string    `json:"solution_name"`
+	IsMain          bool      `json:"is_main"`
+	ExpectedVerdict string    `json:"expected_verdict"`
+	Verdict         string    `json:"verdict"`
+	PassedCount     int       `json:"passed_count"`
+	TotalCount      int       `json:"total_count"`
+}
+
+// Summary renders the run the way it is announced in the problem chat, e.g.
+// "main solution: AC on 42/42".
+func (r MessageJudgeRun) Summary() string {
+	name := "main solution"
+	if !r.IsMain {
+		name = fmt.Sprintf("solution %q", r.SolutionName)
+	}
+
+	verdict := constant.Verdict(r.Verdict)
+	if verdict == constant.VerdictCompilationError {
+		return fmt.Sprintf("%s: %s", name, verdict.Abbreviation())
+	}
+
+	return fmt.Sprintf("%s: %s on %d/%d", name, verdict.Abbreviation(), r.PassedCount, r.TotalCount)
+}
+
+// MessageDuplicate is another problem found to be a potential duplicate of a
+// submitted problem version.
+type MessageDuplicate struct {
+	ProblemID           uuid.UUID `json:"problem_id"`
+	StatementSimilarity float64   `json:"statement_similarity"`
+	ExampleSimilarity   float64   `json:"example_similarity"`
+}
+
+// MessageBroadcaster broadcasts events as part of the transaction of the
+// context: they reach the clients once it commits, and never if it rolls
+// back.
 type MessageBroadcaster interface {
 	BroadcastUserMessage(
+		ctx context.Context,
 		problemID uuid.UUID,
 		messageID uuid.UUID,
 		content string,
@@ -43,18 +96,21 @@ type MessageBroadcaster interface {
 	) error
 
 	BroadcastSubmittedMessage(
+		ctx context.Context,
 		problemID uuid.UUID,
 		submitter MessageUser,
 		timestamp time.Time,
 	) error
 
 	BroadcastEditedMessage(
+		ctx context.Context,
 		problemID uuid.UUID,
 		editor MessageUser,
 		timestamp time.Time,
 	) error
 
 	BroadcastReviewedMessage(
+		ctx context.Context,
 		problemID uuid.UUID,
 		reviewer MessageUser,
 		decision string,
@@ -62,6 +118,7 @@ type MessageBroadcaster interface {
 	) error
 
 	BroadcastTestedMessage(
+		ctx context.Context,
 		problemID uuid.UUID,
 		tester MessageUser,
 		status string,
@@ -69,8 +126,51 @@ type MessageBroadcaster interface {
 	) error
 
 	BroadcastCompletedMessage(
+		ctx context.Context,
 		problemID uuid.UUID,
 		completer MessageUser,
 		timestamp time.Time,
 	) error
+
+	BroadcastJudgedMessage(
+		ctx context.Context,
+		problemID uuid.UUID,
+		run MessageJudgeRun,
+		timestamp time.Time,
+	) error
+
+	// BroadcastDuplicateMessage only announces that potential duplicates were
+	// found, as not everyone in the room can read them. The messages of the
+	// problem list those the user can.
+	BroadcastDuplicateMessage(
+		ctx context.Context,
+		problemID uuid.UUID,
+		timestamp time.Time,
+	) error
+
+	BroadcastTestersAssignedMessage(
+		ctx context.Context,
+		problemID uuid.UUID,
+		assigner MessageUser,
+		testerIDs []uuid.UUID,
+		timestamp time.Time,
+	) error
+
+	// BroadcastProblemAssignedMessage and BroadcastProblemUnassignedMessage
+	// go to the room of the contest rather than that of the problem.
+	BroadcastProblemAssignedMessage(
+		ctx context.Context,
+		contestID uuid.UUID,
+		problemID uuid.UUID,
+		assigner MessageUser,
+		timestamp time.Time,
+	) error
+
+	BroadcastProblemUnassignedMessage(
+		ctx context.Context,
+		contestID uuid.UUID,
+		problemID uuid.UUID,
+		unassigner MessageUser,
+		timestamp time.Time,
+	) error
 }
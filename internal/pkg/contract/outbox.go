@@ -0,0 +1,23 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Outbox stores events in the transaction of the context, if any, so that
+// they are dispatched once it commits and never when it rolls back. Events
+// with the same key are dispatched one at a time, in the order they were
+// added, while the others are dispatched concurrently.
+type Outbox interface {
+	Add(ctx context.Context, topic string, key string, payload []byte) error
+}
+
+// OutboxHandler handles the events of a topic after they are committed.
+// Events are delivered at least once, so handlers use the event ID to skip
+// those they have already handled.
+type OutboxHandler interface {
+	Topic() string
+	HandleOutboxEvent(ctx context.Context, eventID uuid.UUID, payload []byte) error
+}
@@ -0,0 +1,29 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// RoomAccessPolicy decides who may join the realtime room of a problem or a
+// contest, and so receive everything broadcast about it.
+type RoomAccessPolicy interface {
+	CanAccessProblemRoom(ctx context.Context, userID uuid.UUID, problemID uuid.UUID) (bool, error)
+	CanAccessContestRoom(ctx context.Context, userID uuid.UUID, contestID uuid.UUID) (bool, error)
+}
+
+// ProblemFollowerProvider lists the users following a problem, that is, the
+// people working on it, who are notified of what happens to it wherever they
+// are.
+type ProblemFollowerProvider interface {
+	GetProblemFollowers(ctx context.Context, problemID uuid.UUID) ([]uuid.UUID, error)
+}
+
+// RoomAccessRevoker removes the clients that have lost access to the rooms
+// they are in. It should be told after every change that can take access
+// away; the checks run in the background, after the change is committed.
+type RoomAccessRevoker interface {
+	RecheckProblemRoom(problemID uuid.UUID)
+	RecheckUserRooms(userID uuid.UUID)
+}
@@ -0,0 +1,78 @@
+package contract
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+)
+
+// ErrTestToolchainUnavailable is returned when programs cannot be run, because
+// the judge is disabled or not supported on this platform.
+var ErrTestToolchainUnavailable = errors.New("test toolchain is unavailable")
+
+// ErrTestToolchainBusy is returned when every judge worker stayed busy for
+// as long as a request may take.
+var ErrTestToolchainBusy = errors.New("test toolchain is busy")
+
+// ErrTestToolchainTimeout is returned when the programs of a request took
+// longer in total than a request may take.
+var ErrTestToolchainTimeout = errors.New("test toolchain timed out")
+
+// TestToolchainError reports a setter-provided program that failed to compile
+// or misbehaved. The message is meant for the setter.
+type TestToolchainError struct {
+	Message string
+}
+
+func (e *TestToolchainError) Error() string {
+	return e.Message
+}
+
+type Program struct {
+	Language string
+	Source   string
+}
+
+type Generator struct {
+	Name string
+	Program
+}
+
+type GenerateTestcasesRequest struct {
+	Generators []Generator
+	Script     string
+	// MainSolution produces the answers. Without one, as for interactive
+	// problems, the answers are left empty.
+	MainSolution  *Program
+	TimeLimitMs   uint
+	MemoryLimitMb uint
+	InputFile     string
+	OutputFile    string
+}
+
+type GeneratedTestcase struct {
+	Name   string
+	Input  BlobInfo
+	Output BlobInfo
+}
+
+// ValidationInput is a test input stored as a blob, or given inline when Hash
+// is empty.
+type ValidationInput struct {
+	Name    string
+	Hash    string
+	Content string
+}
+
+type ValidationResult struct {
+	Name    string
+	Valid   bool
+	Message string // Feedback of the validator
+}
+
+// TestToolchain runs the generators and validators setters write to produce
+// and check the testcases of a problem.
+type TestToolchain interface {
+	GenerateTestcases(ctx context.Context, request GenerateTestcasesRequest) ([]GeneratedTestcase, error)
+	ValidateInputs(ctx context.Context, validator Program, inputs []ValidationInput) ([]ValidationResult, error)
+}
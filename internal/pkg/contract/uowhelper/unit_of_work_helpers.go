@@ -47,7 +47,7 @@ func DoWithResult[T any](
 	res, err := fn(uowCtx)
 	if err == nil {
 		if commitErr := uow.Commit(); commitErr != nil {
-			err = errors.WrapIf(err, "failed to commit unit of work")
+			err = errors.WrapIf(commitErr, "failed to commit unit of work")
 		}
 
 		return res, err
@@ -0,0 +1,56 @@
+package uowhelper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger/defaultlogger"
+
+	"emperror.dev/errors"
+)
+
+var errCommit = errors.New("commit failed")
+
+type fakeUnitOfWork struct {
+	commitErr  error
+	rolledBack bool
+}
+
+func (u *fakeUnitOfWork) Begin(ctx context.Context) (context.Context, error) {
+	return ctx, nil
+}
+
+func (u *fakeUnitOfWork) Commit() error {
+	return u.commitErr
+}
+
+func (u *fakeUnitOfWork) Rollback() error {
+	u.rolledBack = true
+	return nil
+}
+
+func TestDoWithResultReturnsCommitError(t *testing.T) {
+	uow := &fakeUnitOfWork{commitErr: errCommit}
+
+	_, err := DoWithResult(context.Background(), uow, defaultlogger.GetLogger(), func(context.Context) (int, error) {
+		return 1, nil
+	})
+	if !errors.Is(err, errCommit) {
+		t.Errorf("DoWithResult() error = %v, want the commit error", err)
+	}
+}
+
+func TestDoRollsBackOnError(t *testing.T) {
+	uow := &fakeUnitOfWork{}
+	errFn := errors.New("fn failed")
+
+	if err := Do(context.Background(), uow, defaultlogger.GetLogger(), func(context.Context) error {
+		return errFn
+	}); !errors.Is(err, errFn) {
+		t.Errorf("Do() error = %v, want the error of fn", err)
+	}
+
+	if !uow.rolledBack {
+		t.Error("Do() did not roll back")
+	}
+}
@@ -0,0 +1,20 @@
+package database
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLogEntry records an administrative change, such as to the permissions
+// of a role. Details holds a JSON document describing the change.
+type AuditLogEntry struct {
+	AuditLogEntryID uuid.UUID `gorm:"primaryKey;type:uuid"`
+	ActorID         uuid.UUID `gorm:"type:uuid"`
+	Actor           User      `gorm:"foreignKey:ActorID"`
+	Action          string    `gorm:"index"`
+	TargetType      string    `gorm:"index:idx_audit_log_entries_target"`
+	TargetID        uuid.UUID `gorm:"type:uuid;index:idx_audit_log_entries_target"`
+	Details         string
+	CreatedAt       time.Time
+}
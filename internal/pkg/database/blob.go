@@ -0,0 +1,12 @@
+package database
+
+import "time"
+
+// Blob is an immutable, content-addressed object in file storage. Rows are keyed
+// by the SHA-256 of the content, so identical files are only stored once.
+type Blob struct {
+	Hash       string `gorm:"primaryKey;size:64"`
+	Size       uint64
+	StorageKey string
+	CreatedAt  time.Time
+}
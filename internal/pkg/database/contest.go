@@ -13,9 +13,16 @@ type Contest struct {
 	Description      string
 	MinProblemCount  uint
 	MaxProblemCount  uint
-	TargetedProblems []Problem `gorm:"foreignKey:TargetContestID"`
-	AssignedProblems []Problem `gorm:"foreignKey:AssignedContestID"`
+	MaxTimeLimitMs   uint                // Zero means no contest-specific cap
+	MaxMemoryLimitMb uint                // Zero means no contest-specific cap
+	TargetedProblems []Problem           `gorm:"foreignKey:TargetContestID"`
+	Problems         []ContestProblem    `gorm:"foreignKey:ContestID"`
+	Members          []ContestMembership `gorm:"foreignKey:ContestID"`
 	DeadlineDatetime time.Time
+	Phase            string `gorm:"default:proposal;index"`
+	StartDatetime    *time.Time
+	EndDatetime      *time.Time
 	CreatedAt        time.Time
+	UpdatedAt        time.Time
 	Deleted          gorm.DeletedAt `gorm:"index"`
 }
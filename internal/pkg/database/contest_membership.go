@@ -0,0 +1,19 @@
+package database
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ContestMembership makes a user part of a contest's staff. The role is one
+// of the constant.ContestMemberRole values and only applies to that contest.
+type ContestMembership struct {
+	ContestID uuid.UUID `gorm:"primaryKey;type:uuid"`
+	Contest   Contest
+	UserID    uuid.UUID `gorm:"primaryKey;type:uuid;index"`
+	User      User
+	Role      string `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,23 @@
+package database
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ContestProblem assigns a problem to a contest. A problem can be part of
+// several contests, such as the editions of a recurring contest series.
+type ContestProblem struct {
+	ContestID     uuid.UUID `gorm:"primaryKey;type:uuid;uniqueIndex:idx_contest_problems_position"`
+	Contest       Contest
+	ProblemID     uuid.UUID `gorm:"primaryKey;type:uuid;index"`
+	Problem       Problem
+	Position      uint   `gorm:"uniqueIndex:idx_contest_problems_position"` // Counted from 1
+	Label         string // Derived from the position, such as "A"
+	DisplayTitle  string // Empty means the problem's own title
+	TimeLimitMs   uint   // Zero means the problem's own limit
+	MemoryLimitMb uint   // Zero means the problem's own limit
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
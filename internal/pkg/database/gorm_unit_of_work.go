@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"sync"
 
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
@@ -26,12 +27,18 @@ func (g *GormUnitOfWorkFactory) New() contract.UnitOfWork {
 	return NewGormUnitOfWork(g.db, g.l)
 }
 
-type txContextKey struct{}
+type (
+	txContextKey  struct{}
+	uowContextKey struct{}
+)
 
 type gormUnitOfWork struct {
 	db *gorm.DB
 	tx *gorm.DB
 	l  logger.Logger
+
+	mu          sync.Mutex // Protects tx, once the transaction has begun, and afterCommit
+	afterCommit []func()
 }
 
 func NewGormUnitOfWork(db *gorm.DB, l logger.Logger) contract.UnitOfWork {
@@ -54,33 +61,72 @@ func (uow *gormUnitOfWork) Begin(ctx context.Context) (context.Context, error) {
 	uow.tx = tx
 
 	txCtx := context.WithValue(ctx, txContextKey{}, uow.tx)
+	txCtx = context.WithValue(txCtx, uowContextKey{}, uow)
 	return txCtx, nil
 }
 
 func (uow *gormUnitOfWork) Commit() error {
-	if uow.tx == nil {
+	tx, afterCommit := uow.end()
+	if tx == nil {
 		return errors.New("no transaction to commit")
 	}
 
-	tx := uow.tx
-	uow.tx = nil
+	if err := tx.Commit().Error; err != nil {
+		return errors.WrapIf(err, "failed to commit transaction")
+	}
+
+	for _, fn := range afterCommit {
+		fn()
+	}
 
-	err := tx.Commit().Error
-	return errors.WrapIf(err, "failed to commit transaction")
+	return nil
 }
 
 func (uow *gormUnitOfWork) Rollback() error {
-	if uow.tx == nil {
+	tx, _ := uow.end()
+	if tx == nil {
 		return nil
 	}
 
-	tx := uow.tx
-	uow.tx = nil
-
 	err := tx.Rollback().Error
 	return errors.WrapIf(err, "failed to rollback transaction")
 }
 
+// end takes the transaction and the functions to run after it commits.
+func (uow *gormUnitOfWork) end() (*gorm.DB, []func()) {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+
+	tx, afterCommit := uow.tx, uow.afterCommit
+	uow.tx, uow.afterCommit = nil, nil
+
+	return tx, afterCommit
+}
+
+// AfterCommit runs fn once the transaction of the context is committed, or
+// right away outside of a transaction. It is dropped if the transaction rolls
+// back.
+func AfterCommit(ctx context.Context, fn func()) {
+	if uow, ok := ctx.Value(uowContextKey{}).(*gormUnitOfWork); ok && uow.addAfterCommit(fn) {
+		return
+	}
+
+	fn()
+}
+
+// addAfterCommit returns false if the transaction has ended already.
+func (uow *gormUnitOfWork) addAfterCommit(fn func()) bool {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+
+	if uow.tx == nil {
+		return false
+	}
+
+	uow.afterCommit = append(uow.afterCommit, fn)
+	return true
+}
+
 func GetDBFromContext(ctx context.Context, baseDB *gorm.DB) *gorm.DB {
 	if tx, ok := ctx.Value(txContextKey{}).(*gorm.DB); ok && tx != nil {
 		return tx.WithContext(ctx)
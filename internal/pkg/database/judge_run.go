@@ -0,0 +1,32 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JudgeRun struct {
+	JudgeRunID       uuid.UUID              `gorm:"primaryKey;type:uuid"`
+	ProblemID        uuid.UUID              `gorm:"type:uuid;index"`
+	ProblemVersionID uuid.UUID              `gorm:"type:uuid;index"`
+	SolutionID       uuid.UUID              `gorm:"type:uuid"`
+	Solution         ProblemVersionSolution `gorm:"foreignKey:SolutionID;references:SolutionID"`
+	Status           string                 `gorm:"index"`
+	Verdict          string
+	PassedCount      int
+	TotalCount       int
+	MaxTimeMs        uint
+	MaxMemoryKb      uint64
+	Log              string            // Compiler output or the reason the run failed
+	RequestedBy      uuid.NullUUID     `gorm:"type:uuid"`
+	TestResults      []JudgeTestResult `gorm:"foreignKey:JudgeRunID"`
+	CreatedAt        time.Time
+	StartedAt        sql.NullTime
+	FinishedAt       sql.NullTime
+	// ClaimedBy is the worker pool judging a running run. It keeps the run only
+	// as long as it renews the lease; other pools take over expired runs.
+	ClaimedBy  uuid.NullUUID `gorm:"type:uuid"`
+	LeaseUntil sql.NullTime
+}
@@ -0,0 +1,14 @@
+package database
+
+import "github.com/google/uuid"
+
+type JudgeTestResult struct {
+	JudgeTestResultID uuid.UUID `gorm:"primaryKey;type:uuid"`
+	JudgeRunID        uuid.UUID `gorm:"type:uuid;index"`
+	TestcaseID        uuid.UUID `gorm:"type:uuid"`
+	Position          int
+	Verdict           string
+	TimeMs            uint
+	MemoryKb          uint64
+	Message           string // Feedback of a custom checker or interactor
+}
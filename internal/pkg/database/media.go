@@ -7,10 +7,12 @@ import (
 )
 
 type Media struct {
-	MediaID   uuid.UUID `gorm:"primaryKey"`
-	URL       string
-	FileName  string
-	MIMEType  string
-	FileSize  uint64
-	CreatedAt time.Time
+	MediaID    uuid.UUID `gorm:"primaryKey"`
+	URL        string
+	FileName   string
+	MIMEType   string
+	FileSize   uint64
+	StorageKey string
+	UploaderID uuid.NullUUID `gorm:"type:uuid"`
+	CreatedAt  time.Time
 }
@@ -0,0 +1,23 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEvent is an event stored with the changes that caused it, and
+// dispatched to its handler after they are committed.
+type OutboxEvent struct {
+	OutboxEventID uuid.UUID `gorm:"primaryKey;type:uuid"` // Also the idempotency key of the event
+	Topic         string
+	OrderingKey   string `gorm:"index:idx_outbox_events_ordering_key_created_at"` // Events with the same key are dispatched in order
+	Payload       []byte
+	Status        string `gorm:"index:idx_outbox_events_status_next_attempt_at"`
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time `gorm:"index:idx_outbox_events_status_next_attempt_at"` // When the lease of a leased event runs out
+	CreatedAt     time.Time `gorm:"index:idx_outbox_events_ordering_key_created_at"`
+	DispatchedAt  sql.NullTime
+}
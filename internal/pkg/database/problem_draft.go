@@ -11,11 +11,29 @@ type ProblemDraft struct {
 	ProblemDraftID      uuid.UUID     `gorm:"primaryKey;type:uuid"`
 	CreatorID           uuid.UUID     `gorm:"type:uuid"`
 	ProblemDifficultyID uuid.NullUUID `gorm:"type:uuid"`
+	TargetContestID     uuid.NullUUID `gorm:"type:uuid"` // The contest the setter proposes the problem for
 	ProblemDifficulty   ProblemDifficulty
-	SubmittedProblem    Problem               `gorm:"foreignKey:ProblemDraftID"`
-	Examples            []ProblemDraftExample `gorm:"foreignKey:ProblemDraftID"`
-	Details             []ProblemDraftDetail  `gorm:"foreignKey:ProblemDraftID"`
-	IsActive            bool                  // False after the problem draft is submitted, then true again when it needs revision
+	SubmittedProblem    Problem                 `gorm:"foreignKey:ProblemDraftID"`
+	Examples            []ProblemDraftExample   `gorm:"foreignKey:ProblemDraftID"`
+	Details             []ProblemDraftDetail    `gorm:"foreignKey:ProblemDraftID"`
+	Solutions           []ProblemDraftSolution  `gorm:"foreignKey:ProblemDraftID"`
+	Generators          []ProblemDraftGenerator `gorm:"foreignKey:ProblemDraftID"`
+	Testcases           []ProblemDraftTestcase  `gorm:"foreignKey:ProblemDraftID"`
+	TimeLimitMs         uint                    `gorm:"default:1000"`
+	MemoryLimitMb       uint                    `gorm:"default:256"`
+	IsInteractive       bool
+	InputFile           string  // Empty means standard input
+	OutputFile          string  // Empty means standard output
+	CheckerType         string  `gorm:"default:token"`
+	CheckerEpsilon      float64 // Only used by the float checker
+	CheckerLanguage     string  // Only used by custom checkers
+	CheckerSource       string
+	InteractorLanguage  string // Only used by interactive problems
+	InteractorSource    string
+	ValidatorLanguage   string
+	ValidatorSource     string
+	GeneratorScript     string // One generator invocation per line, see genscript
+	IsActive            bool   // False after the problem draft is submitted, then true again when it needs revision
 	CreatedAt           time.Time
 	UpdatedAt           time.Time
 	Deleted             gorm.DeletedAt `gorm:"index"`
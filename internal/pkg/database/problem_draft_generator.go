@@ -0,0 +1,11 @@
+package database
+
+import "github.com/google/uuid"
+
+type ProblemDraftGenerator struct {
+	GeneratorID    uuid.UUID `gorm:"primaryKey;type:uuid"`
+	ProblemDraftID uuid.UUID `gorm:"type:uuid;index"`
+	Name           string    // Referenced by the generator script
+	Language       string
+	Source         string
+}
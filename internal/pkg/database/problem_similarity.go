@@ -0,0 +1,19 @@
+package database
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProblemSimilarity records another problem found to be a potential duplicate
+// of a submitted problem version.
+type ProblemSimilarity struct {
+	ProblemVersionID    uuid.UUID `gorm:"primaryKey;type:uuid"`
+	SimilarProblemID    uuid.UUID `gorm:"primaryKey;type:uuid"`
+	SimilarProblem      Problem   `gorm:"foreignKey:SimilarProblemID"`
+	ProblemID           uuid.UUID `gorm:"type:uuid;index"`
+	StatementSimilarity float64
+	ExampleSimilarity   float64
+	CreatedAt           time.Time
+}
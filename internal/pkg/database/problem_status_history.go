@@ -0,0 +1,23 @@
+package database
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ProblemStatusHistory struct {
+	ProblemStatusHistoryID uuid.UUID `gorm:"primaryKey;type:uuid"`
+	ProblemID              uuid.UUID `gorm:"type:uuid;index"`
+	ActorID                uuid.UUID `gorm:"type:uuid"`
+	Actor                  User      `gorm:"foreignKey:ActorID"`
+	Event                  string
+	FromStatus             string
+	ToStatus               string
+	Reason                 string
+	CreatedAt              time.Time
+}
+
+func (ProblemStatusHistory) TableName() string {
+	return "problem_status_history"
+}
@@ -13,5 +13,6 @@ type ProblemTestResult struct {
 	Tester              User      `gorm:"foreignKey:TesterID"`
 	Status              string
 	Comment             string
+	SolutionResults     []ProblemTestSolutionResult `gorm:"foreignKey:ProblemTestResultID"`
 	CreatedAt           time.Time
 }
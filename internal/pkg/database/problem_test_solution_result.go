@@ -0,0 +1,11 @@
+package database
+
+import "github.com/google/uuid"
+
+// ProblemTestSolutionResult records the verdict a tester observed for one of the
+// version's solutions.
+type ProblemTestSolutionResult struct {
+	ProblemTestResultID uuid.UUID `gorm:"primaryKey;type:uuid"`
+	SolutionID          uuid.UUID `gorm:"primaryKey;type:uuid"`
+	ActualVerdict       string
+}
@@ -11,11 +11,29 @@ type ProblemVersion struct {
 	ProblemID           uuid.UUID `gorm:"type:uuid"`
 	ProblemDifficultyID uuid.UUID `gorm:"type:uuid"`
 	ProblemDifficulty   ProblemDifficulty
-	SubmittedBy         uuid.UUID               `gorm:"type:uuid"`
-	SubmittedByUser     User                    `gorm:"foreignKey:SubmittedBy"`
-	Details             []ProblemVersionDetail  `gorm:"foreignKey:ProblemVersionID"`
-	Examples            []ProblemVersionExample `gorm:"foreignKey:ProblemVersionID"`
-	Review              *ProblemReview          `gorm:"foreignKey:VersionID"`
-	TestResults         []ProblemTestResult     `gorm:"foreignKey:VersionID"`
+	SubmittedBy         uuid.UUID                 `gorm:"type:uuid"`
+	SubmittedByUser     User                      `gorm:"foreignKey:SubmittedBy"`
+	Details             []ProblemVersionDetail    `gorm:"foreignKey:ProblemVersionID"`
+	Examples            []ProblemVersionExample   `gorm:"foreignKey:ProblemVersionID"`
+	Solutions           []ProblemVersionSolution  `gorm:"foreignKey:ProblemVersionID"`
+	Generators          []ProblemVersionGenerator `gorm:"foreignKey:ProblemVersionID"`
+	Testcases           []ProblemVersionTestcase  `gorm:"foreignKey:ProblemVersionID"`
+	TimeLimitMs         uint                      `gorm:"default:1000"`
+	MemoryLimitMb       uint                      `gorm:"default:256"`
+	IsInteractive       bool
+	InputFile           string  // Empty means standard input
+	OutputFile          string  // Empty means standard output
+	CheckerType         string  `gorm:"default:token"`
+	CheckerEpsilon      float64 // Only used by the float checker
+	CheckerLanguage     string  // Only used by custom checkers
+	CheckerSource       string
+	InteractorLanguage  string // Only used by interactive problems
+	InteractorSource    string
+	ValidatorLanguage   string
+	ValidatorSource     string
+	GeneratorScript     string              // One generator invocation per line, see genscript
+	Review              *ProblemReview      `gorm:"foreignKey:VersionID"`
+	TestResults         []ProblemTestResult `gorm:"foreignKey:VersionID"`
+	ExampleSignature    []byte              // MinHash signature of the examples, see problem.ExampleSignature
 	CreatedAt           time.Time
 }
@@ -12,4 +12,5 @@ type ProblemVersionDetail struct {
 	InputFormat      string
 	OutputFormat     string
 	Note             string
+	Signature        []byte // MinHash signature of the statement, see problem.StatementSignature
 }
@@ -0,0 +1,11 @@
+package database
+
+import "github.com/google/uuid"
+
+type ProblemVersionGenerator struct {
+	GeneratorID      uuid.UUID `gorm:"primaryKey;type:uuid"`
+	ProblemVersionID uuid.UUID `gorm:"type:uuid;index"`
+	Name             string    // Referenced by the generator script
+	Language         string
+	Source           string
+}
@@ -0,0 +1,13 @@
+package database
+
+import "github.com/google/uuid"
+
+type ProblemVersionSolution struct {
+	SolutionID       uuid.UUID `gorm:"primaryKey;type:uuid"`
+	ProblemVersionID uuid.UUID `gorm:"type:uuid;index"`
+	Name             string
+	Language         string
+	ExpectedVerdict  string
+	IsMain           bool
+	Source           string
+}
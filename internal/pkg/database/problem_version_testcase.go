@@ -0,0 +1,14 @@
+package database
+
+import "github.com/google/uuid"
+
+type ProblemVersionTestcase struct {
+	TestcaseID       uuid.UUID `gorm:"primaryKey;type:uuid"`
+	ProblemVersionID uuid.UUID `gorm:"type:uuid;index"`
+	Position         int
+	Name             string
+	InputHash        string `gorm:"size:64"`
+	InputSize        uint64
+	OutputHash       string `gorm:"size:64"`
+	OutputSize       uint64
+}
@@ -0,0 +1,25 @@
+package database
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoomEvent is an event broadcast to a websocket room, kept for a while so that
+// clients can catch up on it after reconnecting.
+type RoomEvent struct {
+	RoomEventID    uuid.UUID     `gorm:"primaryKey;type:uuid"`
+	Room           string        `gorm:"uniqueIndex:idx_room_events_room_seq;uniqueIndex:idx_room_events_room_key"` // The type and ID of the room, e.g. "problem:<uuid>"
+	Seq            uint64        `gorm:"uniqueIndex:idx_room_events_room_seq"`
+	IdempotencyKey uuid.NullUUID `gorm:"type:uuid;uniqueIndex:idx_room_events_room_key"` // The outbox event it was published from
+	Payload        []byte        // The event as sent to the clients
+	CreatedAt      time.Time
+}
+
+// RoomSequence holds the sequence number of the last event of a room, which
+// keeps increasing after older events are forgotten.
+type RoomSequence struct {
+	Room    string `gorm:"primaryKey"`
+	LastSeq uint64
+}
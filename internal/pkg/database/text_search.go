@@ -0,0 +1,74 @@
+package database
+
+import (
+	"fmt"
+
+	"emperror.dev/errors"
+	"gorm.io/gorm"
+)
+
+// Problems are written in several languages, so the full-text search neither
+// stems words nor drops stop words.
+const textSearchConfig = "'simple'::regconfig"
+
+// DetailSearchVectorSQL is the full-text search vector of a problem version or
+// draft detail, whose columns are qualified by the given alias. Titles rank
+// above the rest of the text.
+func DetailSearchVectorSQL(alias string) string {
+	return fmt.Sprintf(
+		"setweight(to_tsvector(%[1]s, coalesce(%[2]stitle, '')), 'A') || "+
+			"to_tsvector(%[1]s, coalesce(%[2]sbackground, '') || ' ' || coalesce(%[2]sstatement, '') || ' ' || coalesce(%[2]snote, ''))",
+		textSearchConfig,
+		qualifier(alias),
+	)
+}
+
+// ChatMessageSearchVectorSQL is the full-text search vector of a chat
+// message, whose columns are qualified by the given alias.
+func ChatMessageSearchVectorSQL(alias string) string {
+	return fmt.Sprintf("to_tsvector(%s, coalesce(%scontent, ''))", textSearchConfig, qualifier(alias))
+}
+
+// TextSearchQuerySQL turns the bound search text into a full-text query.
+func TextSearchQuerySQL() string {
+	return fmt.Sprintf("websearch_to_tsquery(%s, ?)", textSearchConfig)
+}
+
+// CreateTextSearchIndexes creates the indexes behind the full-text search.
+// Only PostgreSQL has them; other databases fall back to pattern matching.
+func CreateTextSearchIndexes(db *gorm.DB) error {
+	if db.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	indexes := []struct {
+		name   string
+		table  string
+		vector string
+	}{
+		{"idx_problem_version_details_search", "problem_version_details", DetailSearchVectorSQL("")},
+		{"idx_problem_draft_details_search", "problem_draft_details", DetailSearchVectorSQL("")},
+		{"idx_problem_chat_messages_search", "problem_chat_messages", ChatMessageSearchVectorSQL("")},
+	}
+
+	for _, index := range indexes {
+		if err := db.Exec(fmt.Sprintf(
+			"CREATE INDEX IF NOT EXISTS %s ON %s USING GIN ((%s))",
+			index.name,
+			index.table,
+			index.vector,
+		)).Error; err != nil {
+			return errors.WrapIf(err, "failed to create "+index.name)
+		}
+	}
+
+	return nil
+}
+
+func qualifier(alias string) string {
+	if alias == "" {
+		return ""
+	}
+
+	return alias + "."
+}
@@ -0,0 +1,150 @@
+// Package genscript parses generator scripts, which describe how to produce
+// the testcases of a problem by running generator programs, in the spirit of
+// Polygon's test scripts. Every non-empty line that is not a comment invokes a
+// generator and names the test its standard output becomes:
+//
+//	# comments start with a hash
+//	gen 10 3 > 1
+//	gen 1000 7 > big-1
+//	gen 1000 8 > $
+//
+// A "$" test name is replaced with the smallest positive number not used by
+// any other test. Arguments are separated by whitespace and are not quoted.
+package genscript
+
+import (
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+)
+
+const (
+	MaxCommands   = 1000
+	maxNameLength = 64
+	maxArgCount   = 64
+	autoTestName  = "$"
+)
+
+var ErrInvalidScript = errors.New("invalid generator script")
+
+type Command struct {
+	Line      int // 1-based line number in the script
+	Generator string
+	Args      []string
+	Test      string
+}
+
+func (c Command) String() string {
+	return strings.Join(append(append([]string{c.Generator}, c.Args...), ">", c.Test), " ")
+}
+
+// Parse returns the commands of the script in order, with automatic test names
+// resolved.
+func Parse(script string) ([]Command, error) {
+	var commands []Command
+	used := make(map[string]struct{})
+
+	for i, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		command, err := parseLine(line)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidScript, "line %d: %s", i+1, err.Error())
+		}
+		command.Line = i + 1
+
+		if command.Test != autoTestName {
+			if _, ok := used[command.Test]; ok {
+				return nil, errors.Wrapf(ErrInvalidScript, "line %d: duplicate test name %s", i+1, command.Test)
+			}
+
+			used[command.Test] = struct{}{}
+		}
+
+		if len(commands) == MaxCommands {
+			return nil, errors.Wrapf(ErrInvalidScript, "more than %d tests", MaxCommands)
+		}
+
+		commands = append(commands, command)
+	}
+
+	next := 1
+	for i := range commands {
+		if commands[i].Test != autoTestName {
+			continue
+		}
+
+		for ; ; next++ {
+			if _, ok := used[strconv.Itoa(next)]; !ok {
+				break
+			}
+		}
+
+		commands[i].Test = strconv.Itoa(next)
+		next++
+	}
+
+	return commands, nil
+}
+
+func parseLine(line string) (Command, error) {
+	fields := strings.Fields(line)
+
+	var test string
+	switch last := fields[len(fields)-1]; {
+	case len(last) > 1 && strings.HasPrefix(last, ">"):
+		test = last[1:]
+		fields = fields[:len(fields)-1]
+	case len(fields) >= 2 && fields[len(fields)-2] == ">":
+		test = last
+		fields = fields[:len(fields)-2]
+	default:
+		return Command{}, errors.New("missing \"> test\" at the end")
+	}
+
+	if len(fields) == 0 {
+		return Command{}, errors.New("missing generator name")
+	} else if len(fields)-1 > maxArgCount {
+		return Command{}, errors.Errorf("more than %d arguments", maxArgCount)
+	}
+
+	if !IsValidName(fields[0]) {
+		return Command{}, errors.Errorf("invalid generator name %q", fields[0])
+	}
+
+	if test != autoTestName && !IsValidName(test) {
+		return Command{}, errors.Errorf("invalid test name %q", test)
+	}
+
+	for _, arg := range fields[1:] {
+		if strings.ContainsAny(arg, "<>|") {
+			return Command{}, errors.Errorf("unsupported redirection or pipe in %q", arg)
+		}
+	}
+
+	return Command{
+		Generator: fields[0],
+		Args:      fields[1:],
+		Test:      test,
+	}, nil
+}
+
+// IsValidName reports whether name can be used for a generator or a test: a
+// short plain file name made of letters, digits, '.', '_' and '-'.
+func IsValidName(name string) bool {
+	if name == "" || len(name) > maxNameLength || strings.HasPrefix(name, ".") {
+		return false
+	}
+
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '_' || c == '-') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,54 @@
+package genscript
+
+import (
+	"reflect"
+	"testing"
+
+	"emperror.dev/errors"
+)
+
+func TestParse(t *testing.T) {
+	script := "# samples\n\ngen 1 2 > $\n  gen 5 >2\nrand --n=10 seed > $\nrand > big-1\n"
+
+	got, err := Parse(script)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []Command{
+		{Line: 3, Generator: "gen", Args: []string{"1", "2"}, Test: "1"},
+		{Line: 4, Generator: "gen", Args: []string{"5"}, Test: "2"},
+		{Line: 5, Generator: "rand", Args: []string{"--n=10", "seed"}, Test: "3"},
+		{Line: 6, Generator: "rand", Args: []string{}, Test: "big-1"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	if s := got[2].String(); s != "rand --n=10 seed > 3" {
+		t.Errorf("Unexpected command string %q", s)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{name: "missing test", script: "gen 1 2"},
+		{name: "missing generator", script: "> 1"},
+		{name: "duplicate test", script: "gen 1 > a\ngen 2 > a"},
+		{name: "path in test name", script: "gen 1 > ../a"},
+		{name: "path in generator name", script: "/bin/sh > 1"},
+		{name: "pipe", script: "gen 1 |other > 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.script); !errors.Is(err, ErrInvalidScript) {
+				t.Errorf("Expected ErrInvalidScript, got %v", err)
+			}
+		})
+	}
+}
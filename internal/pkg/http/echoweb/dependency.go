@@ -11,9 +11,9 @@ import (
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/echoweb/middleware/log"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/httperror"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/validation"
 
 	"emperror.dev/errors"
-	"github.com/go-playground/validator"
 	"github.com/labstack/echo-contrib/session"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -28,17 +28,31 @@ func AddEcho(container *dig.Container) error {
 		return errors.WrapIf(err, "failed to provide session auth provider")
 	}
 
-	if err := container.Provide(func(l logger.Logger, opts *Options, db *gorm.DB) *echo.Echo {
+	if err := container.Provide(NewUploadRoutes); err != nil {
+		return errors.WrapIf(err, "failed to provide upload routes")
+	}
+
+	if err := container.Provide(func(
+		l logger.Logger,
+		opts *Options,
+		db *gorm.DB,
+		uploads *UploadRoutes,
+	) (*echo.Echo, error) {
+		v, err := validation.New()
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to create validator")
+		}
+
 		e := echo.New()
 
 		e.HideBanner = true
 		e.HTTPErrorHandler = httperror.Handler
-		e.Validator = &customValidator{validator: validator.New()}
+		e.Validator = &customValidator{validator: v}
 
 		e.Use(context.Middleware())
 		e.Use(middleware.Recover())
 		e.Use(log.EchoLogger(l))
-		e.Use(middleware.BodyLimit(constant.BodyLimit))
+		e.Use(bodyLimit(uploads))
 		e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 			AllowOrigins:     []string{"http://localhost:5173", "https://algorithmia.thusaac.com", "http://algorithmia.thusaac.com"},
 			AllowMethods:     []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPatch, http.MethodPost, http.MethodDelete},
@@ -64,7 +78,7 @@ func AddEcho(container *dig.Container) error {
 
 		e.Use(session.Middleware(store))
 
-		return e
+		return e, nil
 	}); err != nil {
 		return errors.WrapIf(err, "failed to provide echo instance")
 	}
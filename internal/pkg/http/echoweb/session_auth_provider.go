@@ -2,7 +2,9 @@ package echoweb
 
 import (
 	"context"
+	"slices"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
@@ -112,17 +114,57 @@ func (s *SessionAuthProvider) Can(ctx context.Context, permissionNames ...string
 		return false, errors.WrapIf(err, "failed to get user from db")
 	}
 
-	for _, r := range u.Roles {
+	return rolesGrant(u.Roles, permissionNames...), nil
+}
+
+// rolesGrant reports whether any of the roles is a super admin role or holds
+// any of the permissions.
+func rolesGrant(roles []database.Role, permissionNames ...string) bool {
+	for _, r := range roles {
 		if r.IsSuperAdmin {
-			return true, nil
+			return true
+		}
+
+		if r.Permissions == nil {
+			continue
 		}
 
 		for _, p := range *r.Permissions {
-			for _, permissionName := range permissionNames {
-				if p.Name == permissionName {
-					return true, nil
-				}
+			if slices.Contains(permissionNames, p.Name) {
+				return true
 			}
+		}
+	}
+
+	return false
+}
+
+func (s *SessionAuthProvider) CanInContest(
+	ctx context.Context,
+	contestID uuid.UUID,
+	permissionNames ...string,
+) (bool, error) {
+	if can, err := s.Can(ctx, permissionNames...); err != nil || can {
+		return can, err
+	}
+
+	user, err := s.MustGetUser(ctx)
+	if err != nil {
+		return false, errors.WrapIf(err, "failed to get user")
+	}
+
+	db := database.GetDBFromContext(ctx, s.db)
+
+	var memberships []database.ContestMembership
+	if err := db.WithContext(ctx).
+		Where("contest_id = ? AND user_id = ?", contestID, user.UserID).
+		Limit(1).
+		Find(&memberships).Error; err != nil {
+		return false, errors.WrapIf(err, "failed to get contest membership")
+	}
+
+	for _, membership := range memberships {
+		if constant.FromStringToContestMemberRole(membership.Role).Grants(permissionNames...) {
 			return true, nil
 		}
 	}
@@ -130,6 +172,39 @@ func (s *SessionAuthProvider) Can(ctx context.Context, permissionNames ...string
 	return false, nil
 }
 
+func (s *SessionAuthProvider) ContestsWhereCan(ctx context.Context, permissionNames ...string) ([]uuid.UUID, error) {
+	user, err := s.MustGetUser(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get user")
+	}
+
+	return s.ContestsWhereUserCan(ctx, user.UserID, permissionNames...)
+}
+
+func (s *SessionAuthProvider) ContestsWhereUserCan(
+	ctx context.Context,
+	userID uuid.UUID,
+	permissionNames ...string,
+) ([]uuid.UUID, error) {
+	db := database.GetDBFromContext(ctx, s.db)
+
+	var memberships []database.ContestMembership
+	if err := db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Find(&memberships).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get contest memberships")
+	}
+
+	contestIDs := make([]uuid.UUID, 0, len(memberships))
+	for _, membership := range memberships {
+		if constant.FromStringToContestMemberRole(membership.Role).Grants(permissionNames...) {
+			contestIDs = append(contestIDs, membership.ContestID)
+		}
+	}
+
+	return contestIDs, nil
+}
+
 func (s *SessionAuthProvider) MustGetUser(ctx context.Context) (contract.AuthUser, error) {
 	user, err := s.GetUser(ctx)
 	if err != nil {
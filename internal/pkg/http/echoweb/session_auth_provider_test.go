@@ -0,0 +1,73 @@
+package echoweb
+
+import (
+	"testing"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+)
+
+func TestRolesGrant(t *testing.T) {
+	role := func(isSuperAdmin bool, permissionNames ...string) database.Role {
+		permissions := make([]database.Permission, 0, len(permissionNames))
+		for _, name := range permissionNames {
+			permissions = append(permissions, database.Permission{Name: name})
+		}
+
+		return database.Role{IsSuperAdmin: isSuperAdmin, Permissions: &permissions}
+	}
+
+	tests := []struct {
+		name        string
+		roles       []database.Role
+		permissions []string
+		want        bool
+	}{
+		{
+			name:        "no roles",
+			permissions: []string{constant.PermissionProblemReviewAny},
+			want:        false,
+		},
+		{
+			name:        "permission held",
+			roles:       []database.Role{role(false, constant.PermissionProblemReviewAny)},
+			permissions: []string{constant.PermissionProblemReviewAny},
+			want:        true,
+		},
+		{
+			name:        "other permission held",
+			roles:       []database.Role{role(false, constant.PermissionProblemReviewAny)},
+			permissions: []string{constant.PermissionRoleListAll},
+			want:        false,
+		},
+		{
+			name: "any of the permissions held",
+			roles: []database.Role{
+				role(false, constant.PermissionProblemReviewAny),
+				role(false, constant.PermissionRoleListAll),
+			},
+			permissions: []string{constant.PermissionProblemExportAny, constant.PermissionRoleListAll},
+			want:        true,
+		},
+		{
+			name:        "role without permissions",
+			roles:       []database.Role{{}},
+			permissions: []string{constant.PermissionProblemReviewAny},
+			want:        false,
+		},
+		{
+			name:        "super admin",
+			roles:       []database.Role{role(true)},
+			permissions: []string{constant.PermissionRoleListAll},
+			want:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rolesGrant(tt.roles, tt.permissions...); got != tt.want {
+				t.Errorf("rolesGrant() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
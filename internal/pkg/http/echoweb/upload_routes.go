@@ -0,0 +1,62 @@
+package echoweb
+
+import (
+	"sync"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// UploadRoutes holds the routes taking file uploads, which accept bodies of up
+// to constant.UploadBodyLimit instead of constant.BodyLimit. The upload
+// endpoints enforce their own, tighter limits.
+type UploadRoutes struct {
+	mu     sync.RWMutex
+	routes map[string]bool
+}
+
+func NewUploadRoutes() *UploadRoutes {
+	return &UploadRoutes{
+		routes: make(map[string]bool),
+	}
+}
+
+// Add marks the route as taking uploads.
+func (r *UploadRoutes) Add(route *echo.Route) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.routes[route.Method+" "+route.Path] = true
+}
+
+// Contains reports whether the request was routed to an upload route.
+func (r *UploadRoutes) Contains(c echo.Context) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.routes[c.Request().Method+" "+c.Path()]
+}
+
+// bodyLimit gives the larger ceiling to the upload routes only.
+func bodyLimit(uploads *UploadRoutes) echo.MiddlewareFunc {
+	limit := middleware.BodyLimitWithConfig(middleware.BodyLimitConfig{
+		Limit: constant.BodyLimit,
+	})
+	uploadLimit := middleware.BodyLimitWithConfig(middleware.BodyLimitConfig{
+		Limit: constant.UploadBodyLimit,
+	})
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		limited, uploadLimited := limit(next), uploadLimit(next)
+
+		return func(c echo.Context) error {
+			if uploads.Contains(c) {
+				return uploadLimited(c)
+			}
+
+			return limited(c)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package echoweb
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestBodyLimit(t *testing.T) {
+	uploads := NewUploadRoutes()
+
+	e := echo.New()
+	e.Use(bodyLimit(uploads))
+
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e.POST("/login", ok)
+	uploads.Add(e.POST("/drafts/:id/testcases", ok))
+
+	tests := []struct {
+		path string
+		size int
+		want int
+	}{
+		{"/login", 1 << 20, http.StatusOK},
+		{"/login", 3 << 20, http.StatusRequestEntityTooLarge},
+		{"/drafts/1/testcases", 3 << 20, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		// Multipart bodies used to get the upload limit on every route.
+		req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewReader(make([]byte, tt.size)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEMultipartForm+"; boundary=x")
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("POST %s with %d bytes = %d, want %d", tt.path, tt.size, rec.Code, tt.want)
+		}
+	}
+}
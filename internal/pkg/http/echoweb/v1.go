@@ -4,11 +4,13 @@ import "github.com/labstack/echo/v4"
 
 type V1Group struct {
 	*echo.Group
+	Uploads *UploadRoutes
 }
 
-func NewV1Group(e *echo.Echo) *V1Group {
+func NewV1Group(e *echo.Echo, uploads *UploadRoutes) *V1Group {
 	v1 := e.Group("/api/v1")
 	return &V1Group{
-		Group: v1,
+		Group:   v1,
+		Uploads: uploads,
 	}
 }
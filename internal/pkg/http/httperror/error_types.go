@@ -10,6 +10,7 @@ const (
 	ErrTypeRateLimitExceeded            ErrorType = "rate_limit_exceeded"
 	ErrTypeIncompleteProblemDraft       ErrorType = "incomplete_problem_draft"
 	ErrTypeNoPermission                 ErrorType = "no_permission"
+	ErrTypeInvalidTestcases             ErrorType = "invalid_testcases"
 )
 
 func (e ErrorType) String() string {
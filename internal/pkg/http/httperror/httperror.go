@@ -13,6 +13,7 @@ import (
 type HTTPError struct {
 	Type       ErrorType `json:"type,omitempty"`
 	Message    string    `json:"message"`
+	Details    any       `json:"details,omitempty"`
 	StatusCode int       `json:"-"`
 	Internal   error     `json:"-"`
 }
@@ -37,6 +38,13 @@ func (e *HTTPError) WithType(t ErrorType) *HTTPError {
 	return e
 }
 
+// WithDetails attaches structured information about the error, such as which
+// items of a request failed, to the response.
+func (e *HTTPError) WithDetails(details any) *HTTPError {
+	e.Details = details
+	return e
+}
+
 func (e *HTTPError) WithInternal(err error) *HTTPError {
 	e.Internal = err
 	return e
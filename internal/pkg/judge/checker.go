@@ -0,0 +1,141 @@
+package judge
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
+	"emperror.dev/errors"
+)
+
+const maxTokenSize = 16 << 20
+
+var ErrUnknownChecker = errors.New("unknown checker type")
+
+// runBuiltinChecker reports whether the solution's output matches the expected
+// answer according to one of the built-in checker types.
+func runBuiltinChecker(checkerType string, epsilon float64, expected io.Reader, actual io.Reader) (bool, error) {
+	switch checkerType {
+	case constant.CheckerTypeExact:
+		return compareLines(expected, actual)
+	case constant.CheckerTypeToken, "":
+		return compareTokens(expected, actual, bytes.Equal)
+	case constant.CheckerTypeFloat:
+		return compareTokens(expected, actual, func(e []byte, a []byte) bool {
+			return floatTokensEqual(e, a, epsilon)
+		})
+	case constant.CheckerTypeYesNo:
+		return compareTokens(expected, actual, bytes.EqualFold)
+	default:
+		return false, errors.WithStack(ErrUnknownChecker)
+	}
+}
+
+// compareTokens reports whether both streams contain the same sequence of
+// whitespace-separated tokens, which ignores trailing spaces and line ending
+// differences.
+func compareTokens(expected io.Reader, actual io.Reader, equal func(expected []byte, actual []byte) bool) (bool, error) {
+	expectedScanner := newScanner(expected, bufio.ScanWords)
+	actualScanner := newScanner(actual, bufio.ScanWords)
+
+	for {
+		hasExpected := expectedScanner.Scan()
+		hasActual := actualScanner.Scan()
+
+		if ok, err := checkScanErrors(expectedScanner, actualScanner); !ok || err != nil {
+			return false, err
+		}
+
+		if !hasExpected || !hasActual {
+			return hasExpected == hasActual, nil
+		}
+
+		if !equal(expectedScanner.Bytes(), actualScanner.Bytes()) {
+			return false, nil
+		}
+	}
+}
+
+// compareLines compares the streams line by line. Trailing whitespace on each
+// line and trailing empty lines are ignored, since they are invisible in most
+// problem statements.
+func compareLines(expected io.Reader, actual io.Reader) (bool, error) {
+	expectedScanner := newScanner(expected, bufio.ScanLines)
+	actualScanner := newScanner(actual, bufio.ScanLines)
+
+	for {
+		hasExpected := expectedScanner.Scan()
+		hasActual := actualScanner.Scan()
+
+		if ok, err := checkScanErrors(expectedScanner, actualScanner); !ok || err != nil {
+			return false, err
+		}
+
+		if !hasExpected && !hasActual {
+			return true, nil
+		}
+
+		var expectedLine, actualLine []byte
+		if hasExpected {
+			expectedLine = bytes.TrimRight(expectedScanner.Bytes(), " \t\r")
+		}
+
+		if hasActual {
+			actualLine = bytes.TrimRight(actualScanner.Bytes(), " \t\r")
+		}
+
+		if !bytes.Equal(expectedLine, actualLine) {
+			return false, nil
+		}
+	}
+}
+
+// floatTokensEqual accepts numbers within an absolute or relative error of
+// epsilon, like testlib's doubleCompare. Other tokens must match exactly.
+func floatTokensEqual(expected []byte, actual []byte, epsilon float64) bool {
+	e, err := strconv.ParseFloat(string(expected), 64)
+	if err != nil {
+		return bytes.Equal(expected, actual)
+	}
+
+	a, err := strconv.ParseFloat(string(actual), 64)
+	if err != nil || math.IsNaN(a) {
+		return false
+	}
+
+	if e == a {
+		return true
+	}
+
+	diff := math.Abs(e - a)
+	return diff <= epsilon || diff <= epsilon*math.Abs(e)
+}
+
+// checkScanErrors treats an overlong token in the actual output as a mismatch
+// rather than a failure, since it is the solution's fault.
+func checkScanErrors(expected *bufio.Scanner, actual *bufio.Scanner) (bool, error) {
+	if err := expected.Err(); err != nil {
+		return false, errors.WrapIf(err, "failed to read expected output")
+	}
+
+	if err := actual.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return false, nil
+		}
+
+		return false, errors.WrapIf(err, "failed to read actual output")
+	}
+
+	return true, nil
+}
+
+func newScanner(r io.Reader, split bufio.SplitFunc) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), maxTokenSize)
+	scanner.Split(split)
+	return scanner
+}
@@ -0,0 +1,52 @@
+package judge
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+)
+
+func TestRunBuiltinChecker(t *testing.T) {
+	tests := []struct {
+		name     string
+		checker  string
+		epsilon  float64
+		expected string
+		actual   string
+		want     bool
+	}{
+		{name: "token identical", checker: constant.CheckerTypeToken, expected: "1 2 3\n", actual: "1 2 3\n", want: true},
+		{name: "token whitespace differences", checker: constant.CheckerTypeToken, expected: "1 2\n3\n", actual: "1  2 3\r\n\n", want: true},
+		{name: "token missing trailing newline", checker: constant.CheckerTypeToken, expected: "yes\n", actual: "yes", want: true},
+		{name: "token different token", checker: constant.CheckerTypeToken, expected: "1 2 3", actual: "1 2 4", want: false},
+		{name: "token extra token", checker: constant.CheckerTypeToken, expected: "1 2", actual: "1 2 3", want: false},
+		{name: "token missing token", checker: constant.CheckerTypeToken, expected: "1 2 3", actual: "1 2", want: false},
+		{name: "token empty output", checker: constant.CheckerTypeToken, expected: "0", actual: "", want: false},
+		{name: "exact identical", checker: constant.CheckerTypeExact, expected: "a b\nc\n", actual: "a b\nc\n", want: true},
+		{name: "exact trailing whitespace", checker: constant.CheckerTypeExact, expected: "a b\nc\n", actual: "a b  \r\nc\n\n", want: true},
+		{name: "exact inner whitespace", checker: constant.CheckerTypeExact, expected: "a b\n", actual: "a  b\n", want: false},
+		{name: "exact line break", checker: constant.CheckerTypeExact, expected: "a b\n", actual: "a\nb\n", want: false},
+		{name: "exact missing line", checker: constant.CheckerTypeExact, expected: "a\n\nb\n", actual: "a\nb\n", want: false},
+		{name: "float within absolute error", checker: constant.CheckerTypeFloat, epsilon: 1e-6, expected: "0.5", actual: "0.5000004", want: true},
+		{name: "float within relative error", checker: constant.CheckerTypeFloat, epsilon: 1e-6, expected: "1000000", actual: "1000000.5", want: true},
+		{name: "float outside error", checker: constant.CheckerTypeFloat, epsilon: 1e-6, expected: "0.5", actual: "0.50001", want: false},
+		{name: "float words", checker: constant.CheckerTypeFloat, epsilon: 1e-6, expected: "impossible", actual: "impossible", want: true},
+		{name: "float nan", checker: constant.CheckerTypeFloat, epsilon: 1e-6, expected: "1", actual: "nan", want: false},
+		{name: "yesno case", checker: constant.CheckerTypeYesNo, expected: "YES\nno\n", actual: "yes No", want: true},
+		{name: "yesno different", checker: constant.CheckerTypeYesNo, expected: "YES", actual: "no", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runBuiltinChecker(tt.checker, tt.epsilon, strings.NewReader(tt.expected), strings.NewReader(tt.actual))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package judge
+
+import (
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+
+	"emperror.dev/errors"
+	"go.uber.org/dig"
+)
+
+func AddJudge(container *dig.Container) error {
+	if err := container.Provide(NewJudger); err != nil {
+		return errors.WrapIf(err, "failed to provide judger")
+	}
+
+	if err := container.Provide(NewGormRepository, dig.As(new(Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide judge repository")
+	}
+
+	if err := container.Provide(NewWorkerPool); err != nil {
+		return errors.WrapIf(err, "failed to provide judge worker pool")
+	}
+
+	if err := container.Provide(func(p *WorkerPool) contract.JudgeQueue { return p }); err != nil {
+		return errors.WrapIf(err, "failed to provide judge queue")
+	}
+
+	if err := container.Provide(func(j *Judger) contract.TestToolchain { return j }); err != nil {
+		return errors.WrapIf(err, "failed to provide test toolchain")
+	}
+
+	return nil
+}
@@ -0,0 +1,263 @@
+package judge
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) ClaimNextRun(
+	ctx context.Context,
+	claimedBy uuid.UUID,
+	startedAt time.Time,
+	leaseUntil time.Time,
+) (*Job, error) {
+	for {
+		var run database.JudgeRun
+		if err := r.db.WithContext(ctx).
+			Scopes(claimable(startedAt)).
+			Order("created_at ASC, judge_run_id ASC").
+			First(&run).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil
+			}
+
+			return nil, errors.WrapIf(err, "failed to get next queued judge run")
+		}
+
+		// Repeating the conditions makes the claim atomic between workers,
+		// including workers of other server instances sharing the database.
+		result := r.db.WithContext(ctx).
+			Model(&database.JudgeRun{}).
+			Scopes(claimable(startedAt)).
+			Where("judge_run_id = ?", run.JudgeRunID).
+			Updates(map[string]interface{}{
+				"status":      constant.JudgeRunStatusRunning,
+				"started_at":  startedAt,
+				"claimed_by":  uuid.NullUUID{UUID: claimedBy, Valid: true},
+				"lease_until": sql.NullTime{Time: leaseUntil, Valid: true},
+			})
+		if result.Error != nil {
+			return nil, errors.WrapIf(result.Error, "failed to claim judge run")
+		}
+
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		job, err := r.loadJob(ctx, run)
+		if err != nil {
+			// Loading would fail again for whoever takes the run over.
+			if failErr := r.FailRun(ctx, run.JudgeRunID, claimedBy, failureReason(err), startedAt); failErr != nil {
+				return nil, errors.Append(err, failErr)
+			}
+
+			return nil, err
+		}
+
+		return job, nil
+	}
+}
+
+// claimable selects the queued runs and the running runs whose lease expired,
+// which includes runs claimed before leases existed.
+func claimable(now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(
+			"status = ? OR (status = ? AND (lease_until IS NULL OR lease_until < ?))",
+			constant.JudgeRunStatusQueued,
+			constant.JudgeRunStatusRunning,
+			now,
+		)
+	}
+}
+
+func (r *GormRepository) RenewLease(
+	ctx context.Context,
+	judgeRunID uuid.UUID,
+	claimedBy uuid.UUID,
+	leaseUntil time.Time,
+) error {
+	return r.updateClaimedRun(r.db.WithContext(ctx), judgeRunID, claimedBy, map[string]interface{}{
+		"lease_until": sql.NullTime{Time: leaseUntil, Valid: true},
+	})
+}
+
+func (r *GormRepository) ReleaseRun(ctx context.Context, judgeRunID uuid.UUID, claimedBy uuid.UUID) error {
+	return r.updateClaimedRun(r.db.WithContext(ctx), judgeRunID, claimedBy, map[string]interface{}{
+		"status":      constant.JudgeRunStatusQueued,
+		"started_at":  nil,
+		"claimed_by":  nil,
+		"lease_until": nil,
+	})
+}
+
+// updateClaimedRun updates a run only while it is still claimed by claimedBy.
+func (r *GormRepository) updateClaimedRun(
+	db *gorm.DB,
+	judgeRunID uuid.UUID,
+	claimedBy uuid.UUID,
+	values map[string]interface{},
+) error {
+	result := db.Model(&database.JudgeRun{}).
+		Where("judge_run_id = ? AND status = ? AND claimed_by = ?", judgeRunID, constant.JudgeRunStatusRunning, claimedBy).
+		Updates(values)
+	if result.Error != nil {
+		return errors.WrapIf(result.Error, "failed to update judge run")
+	} else if result.RowsAffected == 0 {
+		return errors.WithStack(ErrLeaseLost)
+	}
+
+	return nil
+}
+
+func (r *GormRepository) loadJob(ctx context.Context, run database.JudgeRun) (*Job, error) {
+	var version database.ProblemVersion
+	if err := r.db.WithContext(ctx).
+		Preload("Testcases").
+		Preload("Solutions", "solution_id = ?", run.SolutionID).
+		Where("problem_version_id = ?", run.ProblemVersionID).
+		First(&version).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.WithStack(ErrJobMissing)
+	} else if err != nil {
+		return nil, errors.WrapIf(err, "failed to get problem version for judge run")
+	}
+
+	if len(version.Solutions) == 0 {
+		return nil, errors.WithStack(ErrJobMissing)
+	}
+
+	solution := version.Solutions[0]
+
+	testcases := make([]Testcase, 0, len(version.Testcases))
+	for _, testcase := range version.Testcases {
+		testcases = append(testcases, Testcase{
+			TestcaseID: testcase.TestcaseID,
+			Position:   testcase.Position,
+			InputHash:  testcase.InputHash,
+			OutputHash: testcase.OutputHash,
+		})
+	}
+
+	job := &Job{
+		JudgeRunID:      run.JudgeRunID,
+		ProblemID:       run.ProblemID,
+		SolutionName:    solution.Name,
+		IsMain:          solution.IsMain,
+		ExpectedVerdict: solution.ExpectedVerdict,
+		Submission: Submission{
+			Language:      solution.Language,
+			Source:        solution.Source,
+			TimeLimitMs:   version.TimeLimitMs,
+			MemoryLimitMb: version.MemoryLimitMb,
+			IsInteractive: version.IsInteractive,
+			InputFile:     version.InputFile,
+			OutputFile:    version.OutputFile,
+			Checker: Checker{
+				Type:    version.CheckerType,
+				Epsilon: version.CheckerEpsilon,
+			},
+		},
+		Testcases: testcases,
+	}
+
+	if version.CheckerType == constant.CheckerTypeCustom {
+		job.Submission.Checker.Program = &Program{
+			Language: version.CheckerLanguage,
+			Source:   version.CheckerSource,
+		}
+	}
+
+	if version.InteractorSource != "" {
+		job.Submission.Interactor = &Program{
+			Language: version.InteractorLanguage,
+			Source:   version.InteractorSource,
+		}
+	}
+
+	return job, nil
+}
+
+func (r *GormRepository) FinishRun(
+	ctx context.Context,
+	judgeRunID uuid.UUID,
+	claimedBy uuid.UUID,
+	result *Result,
+	finishedAt time.Time,
+) error {
+	return database.GetDBFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := r.updateClaimedRun(tx, judgeRunID, claimedBy, map[string]interface{}{
+			"status":        constant.JudgeRunStatusFinished,
+			"verdict":       string(result.Verdict),
+			"passed_count":  result.PassedCount,
+			"max_time_ms":   result.MaxTimeMs,
+			"max_memory_kb": result.MaxMemoryKb,
+			"log":           result.Log,
+			"finished_at":   sql.NullTime{Time: finishedAt, Valid: true},
+			"lease_until":   nil,
+		}); err != nil {
+			return err
+		}
+
+		if len(result.Tests) == 0 {
+			return nil
+		}
+
+		testResults := make([]database.JudgeTestResult, 0, len(result.Tests))
+		for _, test := range result.Tests {
+			id, err := uuid.NewV7()
+			if err != nil {
+				return errors.WrapIf(err, "failed to generate judge test result id")
+			}
+
+			testResults = append(testResults, database.JudgeTestResult{
+				JudgeTestResultID: id,
+				JudgeRunID:        judgeRunID,
+				TestcaseID:        test.TestcaseID,
+				Position:          test.Position,
+				Verdict:           string(test.Verdict),
+				TimeMs:            test.TimeMs,
+				MemoryKb:          test.MemoryKb,
+				Message:           test.Message,
+			})
+		}
+
+		if err := tx.Create(&testResults).Error; err != nil {
+			return errors.WrapIf(err, "failed to create judge test results")
+		}
+
+		return nil
+	})
+}
+
+func (r *GormRepository) FailRun(
+	ctx context.Context,
+	judgeRunID uuid.UUID,
+	claimedBy uuid.UUID,
+	reason string,
+	finishedAt time.Time,
+) error {
+	return r.updateClaimedRun(r.db.WithContext(ctx), judgeRunID, claimedBy, map[string]interface{}{
+		"status":      constant.JudgeRunStatusFailed,
+		"log":         reason,
+		"finished_at": sql.NullTime{Time: finishedAt, Valid: true},
+		"lease_until": nil,
+	})
+}
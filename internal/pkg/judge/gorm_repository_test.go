@@ -0,0 +1,170 @@
+package judge
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func newTestRepository(t *testing.T) (*GormRepository, *gorm.DB) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{DisableForeignKeyConstraintWhenMigrating: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every connection to an in-memory database gets a database of its own.
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(
+		&database.JudgeRun{},
+		&database.ProblemVersion{},
+		&database.ProblemVersionSolution{},
+		&database.ProblemVersionTestcase{},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewGormRepository(db), db
+}
+
+// createRun queues a run of a new solution, or of a missing one when
+// withSolution is false.
+func createRun(t *testing.T, db *gorm.DB, withSolution bool) uuid.UUID {
+	t.Helper()
+
+	version := database.ProblemVersion{ProblemVersionID: uuid.New(), ProblemID: uuid.New()}
+	if err := db.Create(&version).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	solutionID := uuid.New()
+	if withSolution {
+		solution := database.ProblemVersionSolution{
+			SolutionID:       solutionID,
+			ProblemVersionID: version.ProblemVersionID,
+			Name:             "main",
+			Language:         constant.ProgrammingLanguageCpp17,
+		}
+		if err := db.Create(&solution).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run := database.JudgeRun{
+		JudgeRunID:       uuid.New(),
+		ProblemID:        version.ProblemID,
+		ProblemVersionID: version.ProblemVersionID,
+		SolutionID:       solutionID,
+		Status:           constant.JudgeRunStatusQueued,
+		CreatedAt:        time.Now(),
+	}
+	if err := db.Create(&run).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return run.JudgeRunID
+}
+
+func getRun(t *testing.T, db *gorm.DB, judgeRunID uuid.UUID) database.JudgeRun {
+	t.Helper()
+
+	var run database.JudgeRun
+	if err := db.First(&run, "judge_run_id = ?", judgeRunID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return run
+}
+
+func TestGormRepositoryTakesOverExpiredLeases(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	runID := createRun(t, db, true)
+	first, second := uuid.New(), uuid.New()
+	now := time.Now()
+
+	job, err := repo.ClaimNextRun(ctx, first, now, now.Add(time.Minute))
+	if err != nil || job == nil || job.JudgeRunID != runID {
+		t.Fatalf("ClaimNextRun() = %v, %v, want run %v", job, err, runID)
+	}
+
+	if job, err := repo.ClaimNextRun(ctx, second, now.Add(30*time.Second), now.Add(90*time.Second)); err != nil || job != nil {
+		t.Fatalf("ClaimNextRun() during the lease = %v, %v, want nil", job, err)
+	}
+
+	if job, err := repo.ClaimNextRun(ctx, second, now.Add(2*time.Minute), now.Add(3*time.Minute)); err != nil || job == nil {
+		t.Fatalf("ClaimNextRun() after the lease = %v, %v, want run %v", job, err, runID)
+	}
+
+	result := &Result{Verdict: constant.VerdictAccepted}
+	if err := repo.FinishRun(ctx, runID, first, result, now); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("FinishRun() by the previous claimer = %v, want %v", err, ErrLeaseLost)
+	}
+
+	if err := repo.RenewLease(ctx, runID, first, now.Add(time.Hour)); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("RenewLease() by the previous claimer = %v, want %v", err, ErrLeaseLost)
+	}
+
+	if err := repo.FinishRun(ctx, runID, second, result, now); err != nil {
+		t.Errorf("FinishRun() by the current claimer = %v", err)
+	}
+
+	if run := getRun(t, db, runID); run.Status != constant.JudgeRunStatusFinished {
+		t.Errorf("status = %q, want %q", run.Status, constant.JudgeRunStatusFinished)
+	}
+}
+
+func TestGormRepositoryReleaseRun(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	runID := createRun(t, db, true)
+	claimedBy := uuid.New()
+	now := time.Now()
+
+	if _, err := repo.ClaimNextRun(ctx, claimedBy, now, now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.ReleaseRun(ctx, runID, claimedBy); err != nil {
+		t.Fatalf("ReleaseRun() = %v", err)
+	}
+
+	// Released runs can be claimed again before the lease would have expired.
+	if job, err := repo.ClaimNextRun(ctx, uuid.New(), now, now.Add(time.Minute)); err != nil || job == nil {
+		t.Errorf("ClaimNextRun() after release = %v, %v, want run %v", job, err, runID)
+	}
+}
+
+func TestGormRepositoryFailsRunsThatCannotBeLoaded(t *testing.T) {
+	repo, db := newTestRepository(t)
+	ctx := context.Background()
+	runID := createRun(t, db, false)
+	now := time.Now()
+
+	if job, err := repo.ClaimNextRun(ctx, uuid.New(), now, now.Add(time.Minute)); !errors.Is(err, ErrJobMissing) {
+		t.Fatalf("ClaimNextRun() = %v, %v, want %v", job, err, ErrJobMissing)
+	}
+
+	run := getRun(t, db, runID)
+	if run.Status != constant.JudgeRunStatusFailed || run.Log != ErrJobMissing.Error() {
+		t.Errorf("run = %q with log %q, want %q with log %q", run.Status, run.Log, constant.JudgeRunStatusFailed, ErrJobMissing.Error())
+	}
+
+	if job, err := repo.ClaimNextRun(ctx, uuid.New(), now.Add(time.Hour), now.Add(2*time.Hour)); err != nil || job != nil {
+		t.Errorf("ClaimNextRun() of a failed run = %v, %v, want nil", job, err)
+	}
+}
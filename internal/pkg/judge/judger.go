@@ -0,0 +1,550 @@
+package judge
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	compileCPUTime    = 30 * time.Second
+	compileWallTime   = 60 * time.Second
+	maxCompileLogSize = 64 << 10
+	maxBinarySize     = 256 << 20
+	maxOutputSize     = 64 << 20
+)
+
+var ErrMissingInteractor = errors.New("interactive problems cannot be judged without an interactor")
+
+// Program is the source of a custom checker or interactor.
+type Program struct {
+	Language string
+	Source   string
+}
+
+type Checker struct {
+	Type    string
+	Epsilon float64
+	Program *Program // Only set for custom checkers
+}
+
+type Submission struct {
+	Language      string
+	Source        string
+	TimeLimitMs   uint
+	MemoryLimitMb uint
+	IsInteractive bool
+	InputFile     string // Empty means standard input
+	OutputFile    string // Empty means standard output
+	Checker       Checker
+	Interactor    *Program // Only set for interactive problems
+}
+
+type Testcase struct {
+	TestcaseID uuid.UUID
+	Position   int
+	InputHash  string
+	OutputHash string
+}
+
+type TestResult struct {
+	TestcaseID uuid.UUID
+	Position   int
+	Verdict    constant.Verdict
+	TimeMs     uint
+	MemoryKb   uint64
+	Message    string // Feedback of a custom checker or interactor
+}
+
+type Result struct {
+	Verdict     constant.Verdict
+	PassedCount int
+	MaxTimeMs   uint
+	MaxMemoryKb uint64
+	Log         string
+	Tests       []TestResult
+}
+
+type Judger struct {
+	opts      *Options
+	sandbox   *sandbox
+	blobStore contract.BlobStore
+	// slots is shared by judging and the test toolchain, so that no more
+	// programs run at once than there are workers.
+	slots chan struct{}
+}
+
+func NewJudger(opts *Options, blobStore contract.BlobStore) (*Judger, error) {
+	if err := opts.validate(); err != nil {
+		return nil, errors.WrapIf(err, "invalid judge options")
+	}
+
+	return &Judger{
+		opts:      opts,
+		sandbox:   newSandbox(opts),
+		blobStore: blobStore,
+		slots:     make(chan struct{}, opts.GetWorkers()),
+	}, nil
+}
+
+// acquireSlot waits for a free slot and returns the function releasing it.
+func (j *Judger) acquireSlot(ctx context.Context) (func(), error) {
+	select {
+	case j.slots <- struct{}{}:
+		return func() { <-j.slots }, nil
+	case <-ctx.Done():
+		return nil, errors.WithStack(ctx.Err())
+	}
+}
+
+// task is the state shared by all testcases of a single submission.
+type task struct {
+	ws         *workspace
+	submission Submission
+	lang       language
+	checker    *program // Nil for built-in checkers
+	interactor *program // Nil for non-interactive problems
+}
+
+// Judge compiles the submission and runs it against every testcase, even after
+// a failing one, so that the per-test results are complete.
+func (j *Judger) Judge(ctx context.Context, submission Submission, testcases []Testcase) (*Result, error) {
+	if submission.IsInteractive && submission.Interactor == nil {
+		return nil, errors.WithStack(ErrMissingInteractor)
+	}
+
+	lang, err := getLanguage(submission.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := j.acquireSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	ws, err := j.newWorkspace()
+	if err != nil {
+		return nil, err
+	}
+	defer ws.remove()
+
+	log, ok, err := j.build(ctx, ws.box, lang, submission.Source, "")
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return &Result{
+			Verdict: constant.VerdictCompilationError,
+			Log:     log,
+			Tests:   []TestResult{},
+		}, nil
+	}
+
+	t := &task{
+		ws:         ws,
+		submission: submission,
+		lang:       lang,
+	}
+
+	if submission.Checker.Type == constant.CheckerTypeCustom {
+		if submission.Checker.Program == nil {
+			return nil, errors.WithStack(&programError{role: programRoleChecker, message: "has no source"})
+		}
+
+		if t.checker, err = j.buildProgram(ctx, programRoleChecker, ws.checker, submission.Checker.Program); err != nil {
+			return nil, err
+		}
+	}
+
+	if submission.IsInteractive {
+		if t.interactor, err = j.buildProgram(ctx, programRoleInteractor, ws.interactor, submission.Interactor); err != nil {
+			return nil, err
+		}
+	}
+
+	testcases = slices.Clone(testcases)
+	slices.SortFunc(testcases, func(a, b Testcase) int { return a.Position - b.Position })
+
+	result := &Result{
+		Verdict: constant.VerdictAccepted,
+		Tests:   make([]TestResult, 0, len(testcases)),
+	}
+
+	for i, testcase := range testcases {
+		test, err := j.runTestcase(ctx, t, testcase, i)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to run testcase "+strconv.Itoa(testcase.Position))
+		}
+
+		result.Tests = append(result.Tests, *test)
+		result.MaxTimeMs = max(result.MaxTimeMs, test.TimeMs)
+		result.MaxMemoryKb = max(result.MaxMemoryKb, test.MemoryKb)
+
+		if test.Verdict == constant.VerdictAccepted {
+			result.PassedCount++
+		} else if result.Verdict == constant.VerdictAccepted {
+			result.Verdict = test.Verdict
+		}
+	}
+
+	return result, nil
+}
+
+// build writes the source into dir and compiles it there if the language needs
+// it. A failed build is reported through ok together with the compiler log.
+func (j *Judger) build(
+	ctx context.Context,
+	dir string,
+	lang language,
+	source string,
+	includeDir string,
+) (log string, ok bool, err error) {
+	if err := os.WriteFile(filepath.Join(dir, lang.sourceFile), []byte(source), 0o644); err != nil {
+		return "", false, errors.WrapIf(err, "failed to write source file")
+	}
+
+	if lang.compile == nil {
+		return "", true, nil
+	}
+
+	buf := &limitedBuffer{limit: maxCompileLogSize}
+
+	u, err := j.sandbox.run(ctx, process{
+		args:   lang.compileArgs(includeDir),
+		dir:    dir,
+		stdout: buf,
+		stderr: buf,
+		limits: limits{
+			cpuTime:    compileCPUTime,
+			wallTime:   compileWallTime,
+			outputSize: maxBinarySize,
+		},
+	})
+	if err != nil {
+		return "", false, errors.WrapIf(err, "failed to run compiler")
+	}
+
+	if u.exceededCPU(compileCPUTime) {
+		buf.WriteString("\ncompilation timed out")
+	}
+
+	return buf.String(), !u.failed() && !u.timedOut, nil
+}
+
+func (j *Judger) runTestcase(ctx context.Context, t *task, testcase Testcase, index int) (*TestResult, error) {
+	prefix := filepath.Join(t.ws.data, strconv.Itoa(index))
+	inputPath := prefix + ".in"
+	answerPath := prefix + ".ans"
+
+	if err := j.fetchBlob(ctx, testcase.InputHash, inputPath); err != nil {
+		return nil, err
+	}
+
+	if err := j.fetchBlob(ctx, testcase.OutputHash, answerPath); err != nil {
+		return nil, err
+	}
+
+	if t.interactor != nil {
+		return j.runInteractive(ctx, t, testcase, inputPath, answerPath)
+	}
+
+	u, outputPath, err := j.runSolution(ctx, t, inputPath, prefix+".out")
+	if err != nil {
+		return nil, err
+	}
+
+	result := t.newTestResult(testcase, u)
+	if verdict, ok := t.resourceVerdict(u); ok {
+		result.Verdict = verdict
+	} else if u.failed() {
+		// Exceeding the output limit also ends up here, killed by SIGXFSZ.
+		result.Verdict = constant.VerdictRuntimeError
+	} else if t.checker != nil {
+		result.Verdict, result.Message, err = j.check(ctx, t.checker, inputPath, outputPath, answerPath)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		ok, err := compareFiles(answerPath, outputPath, t.submission.Checker)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Verdict = constant.VerdictWrongAnswer
+		if ok {
+			result.Verdict = constant.VerdictAccepted
+		}
+	}
+
+	return result, nil
+}
+
+// runInteractive runs the solution and the interactor side by side, each one's
+// standard output connected to the other's standard input. The interactor
+// judges the exchange; a custom checker, if any, then checks what the
+// interactor wrote to its output file.
+func (j *Judger) runInteractive(
+	ctx context.Context,
+	t *task,
+	testcase Testcase,
+	inputPath string,
+	answerPath string,
+) (*TestResult, error) {
+	interactor := t.interactor
+	if err := interactor.stage(inputPath, answerPath); err != nil {
+		return nil, err
+	}
+	defer interactor.unstage()
+
+	toInteractor, fromSolution, err := os.Pipe()
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create solution pipe")
+	}
+
+	toSolution, fromInteractor, err := os.Pipe()
+	if err != nil {
+		_ = toInteractor.Close()
+		_ = fromSolution.Close()
+		return nil, errors.WrapIf(err, "failed to create interactor pipe")
+	}
+
+	solution := t.solutionProcess()
+	solution.stdin = toSolution
+	solution.stdout = fromSolution
+	solution.closeAfterStart = []io.Closer{toSolution, fromSolution}
+
+	// The interactor outlives the solution by a little, so that a solution
+	// that stops talking is reported as too slow rather than the interactor.
+	feedback := &limitedBuffer{limit: maxFeedbackSize}
+	inter := interactor.process(testlibFileArgs, toInteractor, fromInteractor, feedback, solution.limits.wallTime+time.Second)
+	inter.closeAfterStart = []io.Closer{toInteractor, fromInteractor}
+
+	var (
+		interactorUsage *usage
+		interactorErr   error
+		done            = make(chan struct{})
+	)
+
+	go func() {
+		defer close(done)
+		interactorUsage, interactorErr = j.sandbox.run(ctx, inter)
+	}()
+
+	u, err := j.sandbox.run(ctx, solution)
+	<-done
+
+	if err != nil {
+		return nil, err
+	} else if interactorErr != nil {
+		return nil, errors.WrapIf(interactorErr, "failed to run interactor")
+	}
+
+	result := t.newTestResult(testcase, u)
+	result.Message = strings.TrimSpace(feedback.String())
+
+	if verdict, ok := t.resourceVerdict(u); ok {
+		result.Verdict = verdict
+		return result, nil
+	}
+
+	// Whoever gave up first is to blame: a solution that crashes on its own
+	// gets a runtime error, while one that dies because the interactor already
+	// rejected it and hung up gets the interactor's verdict.
+	if u.failed() && u.exitedAt.Before(interactorUsage.exitedAt) {
+		result.Verdict = constant.VerdictRuntimeError
+		return result, nil
+	}
+
+	if result.Verdict, err = interactor.verdict(interactorUsage, result.Message); err != nil {
+		return nil, err
+	} else if result.Verdict != constant.VerdictAccepted {
+		return result, nil
+	}
+
+	if u.failed() {
+		result.Verdict = constant.VerdictRuntimeError
+	} else if t.checker != nil {
+		outputPath := filepath.Join(interactor.dir, programOutputFile)
+		if result.Verdict, result.Message, err = j.check(ctx, t.checker, inputPath, outputPath, answerPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// runSolution runs the solution on one input and returns where its output
+// ended up: stdoutPath, unless the problem uses an output file.
+func (j *Judger) runSolution(ctx context.Context, t *task, inputPath string, stdoutPath string) (*usage, string, error) {
+	p := t.solutionProcess()
+
+	outputPath := stdoutPath
+	if t.submission.OutputFile != "" {
+		outputPath = filepath.Join(t.ws.box, t.submission.OutputFile)
+		if err := os.Remove(outputPath); err != nil && !os.IsNotExist(err) {
+			return nil, "", errors.WrapIf(err, "failed to remove previous output file")
+		}
+	} else {
+		stdout, err := os.Create(outputPath)
+		if err != nil {
+			return nil, "", errors.WrapIf(err, "failed to create output file")
+		}
+		defer stdout.Close()
+
+		p.stdout = stdout
+	}
+
+	if t.submission.InputFile != "" {
+		if err := copyFile(inputPath, filepath.Join(t.ws.box, t.submission.InputFile)); err != nil {
+			return nil, "", err
+		}
+	} else {
+		stdin, err := os.Open(inputPath)
+		if err != nil {
+			return nil, "", errors.WrapIf(err, "failed to open input file")
+		}
+		defer stdin.Close()
+
+		p.stdin = stdin
+	}
+
+	u, err := j.sandbox.run(ctx, p)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return u, outputPath, nil
+}
+
+func (t *task) solutionProcess() process {
+	submission := t.submission
+
+	p := process{
+		args: t.lang.runArgs(submission.MemoryLimitMb),
+		dir:  t.ws.box,
+		limits: limits{
+			cpuTime:    time.Duration(submission.TimeLimitMs) * time.Millisecond,
+			wallTime:   time.Duration(submission.TimeLimitMs)*2*time.Millisecond + time.Second,
+			stackKb:    uint64(submission.MemoryLimitMb) * 1024,
+			outputSize: maxOutputSize,
+		},
+	}
+
+	if t.lang.limitAddressSpace {
+		// Virtual memory is only a safety net against runaway allocations; the
+		// memory limit itself is judged on the peak resident set size so that
+		// exceeding it is reported as such rather than as a crash.
+		p.limits.memoryKb = uint64(submission.MemoryLimitMb)*2*1024 + 64*1024
+	}
+
+	return p
+}
+
+func (t *task) newTestResult(testcase Testcase, u *usage) *TestResult {
+	return &TestResult{
+		TestcaseID: testcase.TestcaseID,
+		Position:   testcase.Position,
+		TimeMs:     uint(u.cpuTime.Milliseconds()),
+		MemoryKb:   u.memoryKb,
+	}
+}
+
+// resourceVerdict reports whether the solution exceeded its time or memory
+// limit, which takes precedence over whatever it printed.
+func (t *task) resourceVerdict(u *usage) (constant.Verdict, bool) {
+	switch {
+	case u.exceededCPU(time.Duration(t.submission.TimeLimitMs) * time.Millisecond):
+		return constant.VerdictTimeLimitExceeded, true
+	case u.memoryKb > uint64(t.submission.MemoryLimitMb)*1024:
+		return constant.VerdictMemoryLimitExceeded, true
+	default:
+		return "", false
+	}
+}
+
+func (j *Judger) fetchBlob(ctx context.Context, hash string, path string) error {
+	r, err := j.blobStore.Open(ctx, hash)
+	if err != nil {
+		return errors.WrapIf(err, "failed to open testcase blob")
+	}
+	defer r.Close()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return errors.WrapIf(err, "failed to create testcase file")
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return errors.WrapIf(err, "failed to copy testcase blob")
+	}
+
+	return nil
+}
+
+func compareFiles(answerPath string, outputPath string, checker Checker) (bool, error) {
+	answer, err := os.Open(answerPath)
+	if err != nil {
+		return false, errors.WrapIf(err, "failed to open answer file")
+	}
+	defer answer.Close()
+
+	output, err := os.Open(outputPath)
+	if os.IsNotExist(err) {
+		// The solution never created its output file.
+		return false, nil
+	} else if err != nil {
+		return false, errors.WrapIf(err, "failed to open output file")
+	}
+	defer output.Close()
+
+	return runBuiltinChecker(checker.Type, checker.Epsilon, answer, output)
+}
+
+func copyFile(src string, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return errors.WrapIf(err, "failed to open file")
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return errors.WrapIf(err, "failed to create file")
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return errors.WrapIf(err, "failed to copy file")
+	}
+
+	return nil
+}
+
+// limitedBuffer keeps the first limit bytes written to it and silently drops
+// the rest, so that a chatty compiler cannot exhaust memory.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(len(p), room)])
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,85 @@
+package judge
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
+	"emperror.dev/errors"
+)
+
+var ErrUnsupportedLanguage = errors.New("unsupported programming language")
+
+const memoryPlaceholder = "{memory_mb}"
+
+type language struct {
+	sourceFile string
+	compile    []string // Nil when the language needs no build step
+	run        []string
+	// limitAddressSpace caps virtual memory at the memory limit. Runtimes that
+	// reserve far more address space than they use (the JVM) enforce the limit
+	// through their own flags instead.
+	limitAddressSpace bool
+}
+
+var languages = map[string]language{
+	constant.ProgrammingLanguageC: {
+		sourceFile:        "main.c",
+		compile:           []string{"gcc", "-O2", "-std=gnu11", "-o", "main", "main.c", "-lm"},
+		run:               []string{"./main"},
+		limitAddressSpace: true,
+	},
+	constant.ProgrammingLanguageCpp17: {
+		sourceFile:        "main.cpp",
+		compile:           []string{"g++", "-O2", "-std=gnu++17", "-o", "main", "main.cpp"},
+		run:               []string{"./main"},
+		limitAddressSpace: true,
+	},
+	constant.ProgrammingLanguageCpp20: {
+		sourceFile:        "main.cpp",
+		compile:           []string{"g++", "-O2", "-std=gnu++20", "-o", "main", "main.cpp"},
+		run:               []string{"./main"},
+		limitAddressSpace: true,
+	},
+	constant.ProgrammingLanguagePython3: {
+		sourceFile:        "main.py",
+		compile:           []string{"python3", "-m", "py_compile", "main.py"},
+		run:               []string{"python3", "main.py"},
+		limitAddressSpace: true,
+	},
+	constant.ProgrammingLanguageJava: {
+		sourceFile: "Main.java",
+		compile:    []string{"javac", "-encoding", "UTF-8", "Main.java"},
+		run:        []string{"java", "-Xmx" + memoryPlaceholder + "m", "-Xss64m", "-XX:+UseSerialGC", "-cp", ".", "Main"},
+	},
+}
+
+func getLanguage(name string) (language, error) {
+	l, ok := languages[name]
+	if !ok {
+		return language{}, errors.WithStack(ErrUnsupportedLanguage)
+	}
+
+	return l, nil
+}
+
+func (l language) runArgs(memoryLimitMb uint) []string {
+	args := make([]string, len(l.run))
+	for i, arg := range l.run {
+		args[i] = strings.ReplaceAll(arg, memoryPlaceholder, strconv.FormatUint(uint64(memoryLimitMb), 10))
+	}
+
+	return args
+}
+
+// compileArgs returns the build command, with includeDir added to the include
+// path of C and C++ compilers when it is not empty.
+func (l language) compileArgs(includeDir string) []string {
+	if includeDir == "" || len(l.compile) == 0 {
+		return l.compile
+	}
+
+	return slices.Concat(l.compile[:1], []string{"-I", includeDir}, l.compile[1:])
+}
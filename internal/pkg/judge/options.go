@@ -0,0 +1,73 @@
+package judge
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+const (
+	defaultWorkers      = 1
+	defaultPollInterval = 5 * time.Second
+)
+
+type Options struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	Workers      int           `mapstructure:"workers"`
+	WorkDir      string        `mapstructure:"workDir"`
+	PollInterval time.Duration `mapstructure:"pollInterval"`
+
+	// RunAsUID and RunAsGID are the unprivileged user and group solutions run
+	// as. Both are required when judging is enabled; the server itself has to
+	// run as root to switch to them and to isolate solutions.
+	RunAsUID uint32 `mapstructure:"runAsUID"`
+	RunAsGID uint32 `mapstructure:"runAsGID"`
+	// TestlibDir is added to the include path of custom checkers and
+	// interactors so that they can include testlib.h. Empty relies on the
+	// compiler's default include path.
+	TestlibDir string `mapstructure:"testlibDir"`
+}
+
+var ErrUnprivilegedUserRequired = errors.New(
+	"the judge needs an unprivileged user and group to run solutions as",
+)
+
+// validate refuses enabled judges that would run solutions with the
+// privileges of the server.
+func (o *Options) validate() error {
+	if !o.Enabled {
+		return nil
+	}
+
+	if o.RunAsUID == 0 || o.RunAsGID == 0 {
+		return errors.WithStack(ErrUnprivilegedUserRequired)
+	}
+
+	return checkSandboxPrivileges()
+}
+
+func (o *Options) GetWorkers() int {
+	if o.Workers <= 0 {
+		return defaultWorkers
+	}
+
+	return o.Workers
+}
+
+func (o *Options) GetWorkDir() string {
+	if o.WorkDir == "" {
+		return filepath.Join(os.TempDir(), "algorithmia-judge")
+	}
+
+	return o.WorkDir
+}
+
+func (o *Options) GetPollInterval() time.Duration {
+	if o.PollInterval <= 0 {
+		return defaultPollInterval
+	}
+
+	return o.PollInterval
+}
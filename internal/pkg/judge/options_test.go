@@ -0,0 +1,28 @@
+package judge
+
+import (
+	"testing"
+
+	"emperror.dev/errors"
+)
+
+func TestOptionsValidateRequiresUnprivilegedUser(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want error
+	}{
+		{name: "disabled", opts: Options{}},
+		{name: "enabled without user", opts: Options{Enabled: true}, want: ErrUnprivilegedUserRequired},
+		{name: "enabled as root", opts: Options{Enabled: true, RunAsGID: 65534}, want: ErrUnprivilegedUserRequired},
+		{name: "enabled with root group", opts: Options{Enabled: true, RunAsUID: 65534}, want: ErrUnprivilegedUserRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.validate(); !errors.Is(err, tt.want) {
+				t.Errorf("validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package judge
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
+	"emperror.dev/errors"
+)
+
+const (
+	programCPUTime        = 10 * time.Second
+	programWallTime       = 20 * time.Second
+	programMemoryMb       = 1024
+	maxFeedbackSize       = 1 << 10
+	programInputFile      = "input.txt"
+	programOutputFile     = "output.txt"
+	programAnswerFile     = "answer.txt"
+	programRoleChecker    = "checker"
+	programRoleInteractor = "interactor"
+	programRoleValidator  = "validator"
+)
+
+var testlibFileArgs = []string{programInputFile, programOutputFile, programAnswerFile}
+
+// Exit codes used by testlib.
+const (
+	testlibOK               = 0
+	testlibWrongAnswer      = 1
+	testlibPresentationErr  = 2
+	testlibFail             = 3
+	testlibDirt             = 4
+	testlibPoints           = 7
+	testlibUnexpectedEOF    = 8
+	testlibPartiallyCorrect = 16
+)
+
+// programError reports a custom checker or interactor that could not be built
+// or misbehaved. Its message is meant for the problem setter.
+type programError struct {
+	role    string
+	message string
+}
+
+func (e *programError) Error() string {
+	return e.role + " " + e.message
+}
+
+// program is a compiled setter-provided program: a custom checker, interactor,
+// validator or generator. Checkers and interactors follow the testlib calling
+// convention: <input> <output> <answer> as arguments, the verdict as exit code
+// and feedback on standard error.
+type program struct {
+	role string
+	lang language
+	dir  string
+}
+
+func (j *Judger) buildProgram(ctx context.Context, role string, dir string, p *Program) (*program, error) {
+	lang, err := getLanguage(p.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	log, ok, err := j.build(ctx, dir, lang, p.Source, j.opts.TestlibDir)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, errors.WithStack(&programError{role: role, message: "failed to compile:\n" + log})
+	}
+
+	return &program{role: role, lang: lang, dir: dir}, nil
+}
+
+// stage copies the testcase files the program reads into its directory, where
+// it can read them even when it runs as a different user.
+func (p *program) stage(inputPath string, answerPath string) error {
+	if err := copyFile(inputPath, filepath.Join(p.dir, programInputFile)); err != nil {
+		return err
+	}
+
+	return copyFile(answerPath, filepath.Join(p.dir, programAnswerFile))
+}
+
+// unstage removes the testcase files again, so that they are not left around
+// for the solution of the next testcase to find.
+func (p *program) unstage() {
+	for _, name := range []string{programInputFile, programOutputFile, programAnswerFile} {
+		_ = os.Remove(filepath.Join(p.dir, name))
+	}
+}
+
+func (p *program) process(
+	args []string,
+	stdin io.Reader,
+	stdout io.Writer,
+	feedback io.Writer,
+	wallTime time.Duration,
+) process {
+	return process{
+		args:   append(p.lang.runArgs(programMemoryMb), args...),
+		dir:    p.dir,
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: feedback,
+		limits: limits{
+			cpuTime:    programCPUTime,
+			wallTime:   wallTime,
+			outputSize: maxOutputSize,
+		},
+	}
+}
+
+// verdict maps the exit status of the program onto a verdict. Anything but an
+// accepted or rejected answer means the program itself is broken.
+func (p *program) verdict(u *usage, feedback string) (constant.Verdict, error) {
+	switch {
+	case u.exceededCPU(programCPUTime):
+		return "", errors.WithStack(&programError{role: p.role, message: "exceeded its time limit"})
+	case u.signal != 0:
+		return "", errors.WithStack(&programError{role: p.role, message: "was killed by " + u.signal.String()})
+	}
+
+	switch u.exitCode {
+	case testlibOK:
+		return constant.VerdictAccepted, nil
+	case testlibWrongAnswer, testlibPresentationErr, testlibDirt, testlibPoints, testlibUnexpectedEOF, testlibPartiallyCorrect:
+		return constant.VerdictWrongAnswer, nil
+	case testlibFail:
+		return "", errors.WithStack(&programError{role: p.role, message: withFeedback("reported a failure", feedback)})
+	default:
+		return "", errors.WithStack(&programError{
+			role:    p.role,
+			message: withFeedback("exited with unexpected code "+strconv.Itoa(u.exitCode), feedback),
+		})
+	}
+}
+
+// describeFailure explains why a program did not exit successfully.
+func describeFailure(u *usage) string {
+	switch {
+	case u.exceededCPU(programCPUTime):
+		return "exceeded its time limit"
+	case u.exceededOutput():
+		return "exceeded the output limit"
+	case u.signal != 0:
+		return "was killed by " + u.signal.String()
+	default:
+		return "exited with code " + strconv.Itoa(u.exitCode)
+	}
+}
+
+func withFeedback(message string, feedback string) string {
+	if feedback == "" {
+		return message
+	}
+
+	return message + ": " + feedback
+}
+
+// check runs the custom checker on the solution's output.
+func (j *Judger) check(ctx context.Context, checker *program, inputPath string, outputPath string, answerPath string) (constant.Verdict, string, error) {
+	if err := checker.stage(inputPath, answerPath); err != nil {
+		return "", "", err
+	}
+	defer checker.unstage()
+
+	if err := copyFile(outputPath, filepath.Join(checker.dir, programOutputFile)); os.IsNotExist(errors.Cause(err)) {
+		// The solution never created its output file, which the checker sees
+		// as an empty output.
+		if err := os.WriteFile(filepath.Join(checker.dir, programOutputFile), nil, 0o644); err != nil {
+			return "", "", errors.WrapIf(err, "failed to create empty output file")
+		}
+	} else if err != nil {
+		return "", "", err
+	}
+
+	feedback := &limitedBuffer{limit: maxFeedbackSize}
+	u, err := j.sandbox.run(ctx, checker.process(testlibFileArgs, nil, nil, feedback, programWallTime))
+	if err != nil {
+		return "", "", errors.WrapIf(err, "failed to run checker")
+	}
+
+	message := strings.TrimSpace(feedback.String())
+	verdict, err := checker.verdict(u, message)
+	return verdict, message, err
+}
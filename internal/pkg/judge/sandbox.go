@@ -0,0 +1,215 @@
+package judge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// The helper starts processes through /bin/sh so that rlimits can be applied
+// with ulimit right before exec'ing the target; the shell is replaced and does
+// not show up in the resource usage.
+const shellPath = "/bin/sh"
+
+// maxProcesses bounds the processes and threads of the unprivileged user, so
+// that a fork bomb cannot exhaust those of the system. The limit counts every
+// process of the user, so it is shared by the runs under way.
+const maxProcesses = 256
+
+var ErrSandboxUnsupported = errors.New("sandbox is not supported on this platform")
+
+type limits struct {
+	cpuTime    time.Duration
+	wallTime   time.Duration
+	memoryKb   uint64 // Address space limit, 0 disables it
+	stackKb    uint64 // 0 keeps the inherited stack limit
+	outputSize int64  // Largest file the process may write, in bytes
+}
+
+type process struct {
+	args   []string
+	dir    string
+	stdin  io.Reader // Nil reads from /dev/null
+	stdout io.Writer // Nil discards the output
+	stderr io.Writer
+	limits limits
+	// closeAfterStart holds the parent's copies of pipe ends handed to the
+	// process, which have to be closed for the peer to ever see EOF.
+	closeAfterStart []io.Closer
+}
+
+type usage struct {
+	cpuTime  time.Duration
+	memoryKb uint64
+	exitCode int
+	signal   syscall.Signal // Zero unless the process was killed by a signal
+	timedOut bool           // Killed after exceeding the wall clock limit
+	exitedAt time.Time
+}
+
+func (u *usage) exceededOutput() bool {
+	return u.signal == signalFileSizeLimit
+}
+
+func (u *usage) exceededCPU(limit time.Duration) bool {
+	return u.timedOut || u.signal == signalCPULimit || u.cpuTime > limit
+}
+
+func (u *usage) failed() bool {
+	return u.exitCode != 0 || u.signal != 0
+}
+
+type sandbox struct {
+	opts *Options
+	self string
+}
+
+func newSandbox(opts *Options) *sandbox {
+	// An unknown executable only surfaces once something needs to be run.
+	self, _ := os.Executable()
+
+	return &sandbox{
+		opts: opts,
+		self: self,
+	}
+}
+
+func (s *sandbox) run(ctx context.Context, p process) (*usage, error) {
+	if !platformSupported {
+		return nil, errors.WithStack(ErrSandboxUnsupported)
+	} else if s.self == "" {
+		return nil, errors.New("failed to locate the server executable for the sandbox helper")
+	}
+
+	defer closeAll(p.closeAfterStart)
+
+	reportReader, reportWriter, err := os.Pipe()
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create report pipe")
+	}
+	defer reportReader.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, p.limits.wallTime)
+	defer cancel()
+
+	config, err := json.Marshal(s.helperConfig())
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to encode sandbox helper config")
+	}
+
+	args := append([]string{SandboxHelperCommand, string(config), shellPath, "-c", ulimitScript(p.limits), "sandbox"}, p.args...)
+	cmd := exec.CommandContext(ctx, s.self, args...)
+	cmd.Dir = p.dir
+	cmd.Env = []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + p.dir,
+		"LANG=C.UTF-8",
+	}
+	cmd.ExtraFiles = []*os.File{reportWriter}
+	cmd.SysProcAttr = sysProcAttr()
+	cmd.Cancel = func() error {
+		// The whole process group goes, so that neither the helper nor anything
+		// the solution forked outlives it.
+		return killProcessGroup(cmd.Process.Pid)
+	}
+	cmd.WaitDelay = time.Second
+
+	if p.stdin != nil {
+		cmd.Stdin = p.stdin
+	}
+
+	if p.stdout != nil {
+		cmd.Stdout = p.stdout
+	}
+
+	cmd.Stderr = p.stderr
+
+	if err := cmd.Start(); err != nil {
+		_ = reportWriter.Close()
+		return nil, errors.WrapIf(err, "failed to start sandbox helper")
+	}
+
+	// Only the helper may hold the write end, or reading the report would
+	// never see EOF.
+	_ = reportWriter.Close()
+	closeAll(p.closeAfterStart)
+
+	var report helperReport
+	decodeErr := json.NewDecoder(reportReader).Decode(&report)
+	if err := killLeftovers(cmd.Process.Pid); err != nil {
+		_ = cmd.Wait()
+		return nil, errors.WrapIf(err, "failed to kill processes left by the command")
+	}
+
+	waitErr := cmd.Wait()
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &usage{
+			cpuTime:  p.limits.wallTime,
+			memoryKb: report.MemoryKb,
+			timedOut: true,
+			exitedAt: time.Now(),
+		}, nil
+	}
+
+	var exitErr *exec.ExitError
+	if waitErr != nil && !errors.As(waitErr, &exitErr) {
+		return nil, errors.WrapIf(waitErr, "failed to wait for sandbox helper")
+	} else if decodeErr != nil || waitErr != nil {
+		return nil, errors.Errorf("sandbox helper failed: %v", errors.Append(decodeErr, waitErr))
+	}
+
+	return &usage{
+		cpuTime:  time.Duration(report.CPUTimeUs) * time.Microsecond,
+		memoryKb: report.MemoryKb,
+		exitCode: report.ExitCode,
+		signal:   syscall.Signal(report.Signal),
+		exitedAt: time.Unix(0, report.ExitedAtNs),
+	}, nil
+}
+
+func (s *sandbox) helperConfig() helperConfig {
+	c := helperConfig{UID: s.opts.RunAsUID, GID: s.opts.RunAsGID}
+	if s.opts.TestlibDir != "" {
+		c.ReadOnlyDirs = []string{s.opts.TestlibDir}
+	}
+
+	return c
+}
+
+func closeAll(closers []io.Closer) {
+	for _, c := range closers {
+		_ = c.Close()
+	}
+}
+
+func ulimitScript(l limits) string {
+	var b strings.Builder
+
+	cpuSeconds := int64(l.cpuTime/time.Second) + 1
+	fmt.Fprintf(&b, "ulimit -t %d || exit 127; ", cpuSeconds)
+	// ulimit -f counts 512-byte blocks in POSIX shells.
+	fmt.Fprintf(&b, "ulimit -f %d || exit 127; ", (l.outputSize+511)/512)
+
+	// dash only knows the limit as -p.
+	fmt.Fprintf(&b, "{ ulimit -u %d || ulimit -p %d; } 2>/dev/null || exit 127; ", maxProcesses, maxProcesses)
+
+	if l.memoryKb > 0 {
+		fmt.Fprintf(&b, "ulimit -v %d || exit 127; ", l.memoryKb)
+	}
+
+	if l.stackKb > 0 {
+		fmt.Fprintf(&b, "ulimit -s %d || exit 127; ", l.stackKb)
+	}
+
+	b.WriteString(`exec "$@"`)
+	return b.String()
+}
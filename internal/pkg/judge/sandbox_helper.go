@@ -0,0 +1,42 @@
+package judge
+
+// SandboxHelperCommand is the first argument the server binary is re-executed
+// with to act as the sandbox helper; main must hand such invocations to
+// RunSandboxHelper before doing anything else.
+//
+// The helper isolates the solution: it replaces the root directory in its own
+// mount namespace with a read-only one holding little more than the system
+// directories and the solution's directory, restricts the system calls that
+// can be made and runs the solution as the unprivileged user.
+//
+// It also measures the solution, because a process inherits the peak memory
+// usage of whoever exec'd it, so a solution started directly by the server
+// would report at least the server's own memory usage. The helper reads the
+// real peak memory usage before the solution exits and reports the resource
+// usage back through an extra file.
+const SandboxHelperCommand = "__judge-sandbox-helper"
+
+// helperReportFD is the descriptor the report is written to, the first one
+// after stdin, stdout and stderr.
+const helperReportFD = 3
+
+// helperConfig is passed to the helper as the argument after
+// SandboxHelperCommand, followed by the command to run.
+type helperConfig struct {
+	UID uint32 `json:"uid"`
+	GID uint32 `json:"gid"`
+	// ReadOnlyDirs are made visible to the command besides the system
+	// directories and its own directory.
+	ReadOnlyDirs []string `json:"read_only_dirs"`
+}
+
+type helperReport struct {
+	CPUTimeUs int64  `json:"cpu_time_us"`
+	MemoryKb  uint64 `json:"memory_kb"`
+	ExitCode  int    `json:"exit_code"`
+	Signal    int    `json:"signal"`
+	// ExitedAtNs is the wall clock time the command started to exit, before
+	// its descriptors were closed, so that a peer on the other end of a pipe
+	// always sees the end after it.
+	ExitedAtNs int64 `json:"exited_at_ns"`
+}
@@ -0,0 +1,167 @@
+//go:build linux
+
+package judge
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// RunSandboxHelper is the entry point of the helper process started by the
+// sandbox, see SandboxHelperCommand. args are the encoded helperConfig and the
+// command to run. It returns the exit code of the helper itself.
+func RunSandboxHelper(args []string) int {
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr, "sandbox helper: missing command")
+		return 2
+	}
+
+	var config helperConfig
+	if err := json.Unmarshal([]byte(args[0]), &config); err != nil {
+		fmt.Fprintln(os.Stderr, "sandbox helper: invalid config:", err)
+		return 2
+	} else if config.UID == 0 || config.GID == 0 {
+		fmt.Fprintln(os.Stderr, "sandbox helper:", ErrUnprivilegedUserRequired)
+		return 2
+	}
+
+	report := os.NewFile(helperReportFD, "report")
+	if report == nil {
+		fmt.Fprintln(os.Stderr, "sandbox helper: missing report file")
+		return 2
+	}
+	defer report.Close()
+
+	// The command must not inherit the report, or it could forge it.
+	syscall.CloseOnExec(helperReportFD)
+
+	if err := isolate(config.ReadOnlyDirs); err != nil {
+		fmt.Fprintln(os.Stderr, "sandbox helper:", err)
+		return 1
+	}
+
+	if err := installSyscallFilter(); err != nil {
+		fmt.Fprintln(os.Stderr, "sandbox helper:", err)
+		return 1
+	}
+
+	r, err := runAndWait(args[1:], &syscall.Credential{Uid: config.UID, Gid: config.GID, Groups: []uint32{}})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "sandbox helper:", err)
+		return 1
+	}
+
+	if err := json.NewEncoder(report).Encode(r); err != nil {
+		fmt.Fprintln(os.Stderr, "sandbox helper: failed to write report:", err)
+		return 1
+	}
+
+	return 0
+}
+
+// runAndWait runs the command traced as the given user, so that its peak
+// memory usage can be read from /proc right before it exits. The peak reported
+// by wait4 cannot be used: it includes the memory of the helper, which exec'd
+// the command.
+func runAndWait(args []string, credential *syscall.Credential) (*helperReport, error) {
+	// All ptrace requests must come from the thread that started the tracee.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	pid, err := syscall.ForkExec(args[0], args, &syscall.ProcAttr{
+		Env:   os.Environ(),
+		Files: []uintptr{0, 1, 2},
+		Sys: &syscall.SysProcAttr{
+			Credential: credential,
+			Ptrace:     true,
+			Pdeathsig:  syscall.SIGKILL,
+		},
+	})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to start command")
+	}
+
+	r := &helperReport{}
+	optionsSet := false
+
+	for {
+		var status syscall.WaitStatus
+		var rusage syscall.Rusage
+		if _, err := syscall.Wait4(pid, &status, 0, &rusage); errors.Is(err, syscall.EINTR) {
+			continue
+		} else if err != nil {
+			return nil, errors.WrapIf(err, "failed to wait for command")
+		}
+
+		if status.Exited() || status.Signaled() {
+			if r.ExitedAtNs == 0 {
+				// Killed without stopping at the exit event.
+				r.ExitedAtNs = time.Now().UnixNano()
+			}
+
+			r.CPUTimeUs = rusage.Utime.Nano()/1000 + rusage.Stime.Nano()/1000
+			r.ExitCode = status.ExitStatus()
+			if status.Signaled() {
+				r.Signal = int(status.Signal())
+			}
+
+			return r, nil
+		}
+
+		if !status.Stopped() {
+			continue
+		}
+
+		// Signals the command receives are passed on, apart from the stops
+		// caused by tracing itself.
+		signal := status.StopSignal()
+
+		switch {
+		case !optionsSet:
+			// The initial stop right after the first exec.
+			if err := syscall.PtraceSetOptions(pid, syscall.PTRACE_O_TRACEEXIT|syscall.PTRACE_O_TRACEEXEC); err != nil {
+				_ = syscall.Kill(pid, syscall.SIGKILL)
+				return nil, errors.WrapIf(err, "failed to set ptrace options")
+			}
+
+			optionsSet = true
+			signal = 0
+		case status.TrapCause() == syscall.PTRACE_EVENT_EXIT:
+			r.ExitedAtNs = time.Now().UnixNano()
+			r.MemoryKb = max(r.MemoryKb, peakMemoryKb(pid))
+			signal = 0
+		case status.TrapCause() == syscall.PTRACE_EVENT_EXEC:
+			signal = 0
+		}
+
+		if err := syscall.PtraceCont(pid, int(signal)); err != nil && !errors.Is(err, syscall.ESRCH) {
+			return nil, errors.WrapIf(err, "failed to resume command")
+		}
+	}
+}
+
+// peakMemoryKb reads the peak resident set size of a process, which is still
+// available while it is stopped on its way out.
+func peakMemoryKb(pid int) uint64 {
+	content, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/status")
+	if err != nil {
+		return 0
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if value, ok := strings.CutPrefix(line, "VmHWM:"); ok {
+			kb, _ := strconv.ParseUint(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "kB")), 10, 64)
+			return kb
+		}
+	}
+
+	return 0
+}
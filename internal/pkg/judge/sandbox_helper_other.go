@@ -0,0 +1,13 @@
+//go:build !linux
+
+package judge
+
+import (
+	"fmt"
+	"os"
+)
+
+func RunSandboxHelper([]string) int {
+	fmt.Fprintln(os.Stderr, "sandbox helper:", ErrSandboxUnsupported)
+	return 2
+}
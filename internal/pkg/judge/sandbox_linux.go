@@ -0,0 +1,70 @@
+//go:build linux
+
+package judge
+
+import (
+	"os"
+	"syscall"
+
+	"emperror.dev/errors"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	platformSupported   = true
+	signalCPULimit      = syscall.SIGXCPU
+	signalFileSizeLimit = syscall.SIGXFSZ
+)
+
+// checkSandboxPrivileges makes sure the server can switch to the unprivileged
+// user and set up the mount namespace of the helper.
+func checkSandboxPrivileges() error {
+	if os.Geteuid() != 0 {
+		return errors.New("the judge has to be started as root to isolate solutions")
+	}
+
+	return nil
+}
+
+// sysProcAttr starts the helper as root in fresh namespaces. The helper builds
+// the file system the solution sees in its mount namespace and only then drops
+// privileges for the solution, see RunSandboxHelper.
+func sysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		Setpgid:   true,
+		Pdeathsig: syscall.SIGKILL,
+		// A fresh network namespace only has a loopback interface that is down,
+		// so the solution cannot reach anything. A PID namespace is deliberately
+		// not used: the solution would become its init process, which ignores
+		// the SIGXCPU and SIGXFSZ signals the rlimits rely on.
+		Cloneflags: syscall.CLONE_NEWNS | syscall.CLONE_NEWNET | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS,
+	}
+}
+
+func killProcessGroup(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGKILL)
+}
+
+// killLeftovers waits for the helper to exit and kills whatever the command
+// left running in its process group, which none of them can leave. The helper
+// is only reaped afterwards, so that its process group cannot be reused in the
+// meantime.
+func killLeftovers(pid int) error {
+	var info unix.Siginfo
+	for {
+		err := unix.Waitid(unix.P_PID, pid, &info, unix.WEXITED|unix.WNOWAIT, nil)
+		if errors.Is(err, syscall.EINTR) {
+			continue
+		} else if err != nil {
+			return errors.WrapIf(err, "failed to wait for sandbox helper")
+		}
+
+		break
+	}
+
+	if err := killProcessGroup(pid); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return errors.WrapIf(err, "failed to kill process group")
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package judge
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSandboxConfinesFilesToWorkDir(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("isolating solutions needs root")
+	}
+
+	j, err := NewJudger(&Options{Enabled: true, WorkDir: t.TempDir(), RunAsUID: 65534, RunAsGID: 65534}, nil)
+	if err != nil {
+		t.Fatalf("NewJudger() error = %v", err)
+	}
+
+	ws, err := j.newWorkspace()
+	if err != nil {
+		t.Fatalf("newWorkspace() error = %v", err)
+	}
+	defer ws.remove()
+
+	// Readable by anyone, so only the sandbox can keep the solution out.
+	outside := t.TempDir()
+	if err := os.Chmod(outside, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	secret := filepath.Join(outside, "secret")
+	answer := filepath.Join(ws.data, "0.ans")
+	own := filepath.Join(ws.box, "own")
+
+	for _, path := range []string{secret, answer, own} {
+		if err := os.WriteFile(path, []byte("content"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		wantOK bool
+	}{
+		{name: "read own file", args: []string{"cat", own}, wantOK: true},
+		{name: "write own directory", args: []string{"sh", "-c", "echo x > new"}, wantOK: true},
+		{name: "read file outside", args: []string{"cat", secret}},
+		{name: "read testcase data", args: []string{"cat", answer}},
+		{name: "write directory outside", args: []string{"sh", "-c", "echo x > " + filepath.Join(outside, "new")}},
+		{name: "write system directory", args: []string{"sh", "-c", "echo x > /etc/new"}},
+		{name: "system calls filtered", args: []string{"grep", "-q", "^Seccomp:[[:space:]]*2", "/proc/self/status"}, wantOK: true},
+		{name: "runs unprivileged", args: []string{"sh", "-c", "test \"$(id -u)\" = 65534"}, wantOK: true},
+		{name: "processes limited", args: []string{"grep", "-q", "^Max processes *" + strconv.Itoa(maxProcesses) + " ", "/proc/self/limits"}, wantOK: true},
+		{name: "new session", args: []string{"setsid", "true"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			u, err := j.sandbox.run(context.Background(), process{
+				args:   tt.args,
+				dir:    ws.box,
+				stdout: &stdout,
+				limits: limits{cpuTime: 5 * time.Second, wallTime: 10 * time.Second, outputSize: 1 << 20},
+			})
+			if err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+
+			if ok := !u.failed(); ok != tt.wantOK {
+				t.Errorf("run(%v) succeeded = %v, want %v (stdout %q)", tt.args, ok, tt.wantOK, stdout.String())
+			}
+		})
+	}
+}
+
+func TestSandboxKillsLeftoverProcesses(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("isolating solutions needs root")
+	}
+
+	j, err := NewJudger(&Options{Enabled: true, WorkDir: t.TempDir(), RunAsUID: 65534, RunAsGID: 65534}, nil)
+	if err != nil {
+		t.Fatalf("NewJudger() error = %v", err)
+	}
+
+	ws, err := j.newWorkspace()
+	if err != nil {
+		t.Fatalf("newWorkspace() error = %v", err)
+	}
+	defer ws.remove()
+
+	var stdout bytes.Buffer
+	if _, err := j.sandbox.run(context.Background(), process{
+		args:   []string{"sh", "-c", "sleep 60 >/dev/null 2>&1 & echo $!"},
+		dir:    ws.box,
+		stdout: &stdout,
+		limits: limits{cpuTime: 5 * time.Second, wallTime: 10 * time.Second, outputSize: 1 << 20},
+	}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(stdout.String()))
+	if err != nil {
+		t.Fatalf("background process id %q: %v", stdout.String(), err)
+	}
+
+	// It is killed, but may not have been reaped yet.
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(10 * time.Millisecond) {
+		stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+		if errors.Is(err, os.ErrNotExist) || strings.Contains(string(stat), ") Z ") {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			_ = syscall.Kill(pid, syscall.SIGKILL)
+			t.Fatal("background process outlived the run")
+		}
+	}
+}
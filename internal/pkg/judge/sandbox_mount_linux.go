@@ -0,0 +1,191 @@
+//go:build linux
+
+package judge
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"emperror.dev/errors"
+)
+
+// systemDirs are mounted read-only into the sandbox so that compilers and
+// runtimes keep working. Symbolic links among them, such as /bin on systems
+// with a merged /usr, are recreated instead.
+var systemDirs = []string{"/bin", "/sbin", "/lib", "/lib32", "/lib64", "/libx32", "/usr", "/etc", "/opt"}
+
+var devices = []string{"null", "zero", "full", "random", "urandom"}
+
+// isolate replaces the root directory of the helper's mount namespace with a
+// read-only tmpfs holding the system directories, readOnlyDirs, the usual
+// devices, a private /tmp and /proc, and the working directory, which is the
+// only directory of the host the command can write to. Everything else, such as
+// the server's configuration and other runs, disappears.
+func isolate(readOnlyDirs []string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return errors.WrapIf(err, "failed to get working directory")
+	}
+
+	// Nothing mounted from here on may show up outside of the namespace.
+	if err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, ""); err != nil {
+		return errors.WrapIf(err, "failed to make mounts private")
+	}
+
+	// The new root is mounted next to the working directory, in the run
+	// directory the server removes afterwards.
+	root, err := os.MkdirTemp(filepath.Dir(dir), ".root-*")
+	if err != nil {
+		return errors.WrapIf(err, "failed to create sandbox root")
+	}
+
+	if err := syscall.Mount("tmpfs", root, "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV, "mode=0755,size=1m"); err != nil {
+		return errors.WrapIf(err, "failed to mount sandbox root")
+	}
+
+	for _, d := range systemDirs {
+		info, err := os.Lstat(d)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return errors.WrapIf(err, "failed to inspect system directory")
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(d)
+			if err != nil {
+				return errors.WrapIf(err, "failed to read system directory link")
+			}
+
+			if err := os.Symlink(target, filepath.Join(root, d)); err != nil {
+				return errors.WrapIf(err, "failed to link system directory")
+			}
+
+			continue
+		}
+
+		if err := bindMount(d, root, syscall.MS_RDONLY|syscall.MS_NOSUID|syscall.MS_NODEV); err != nil {
+			return err
+		}
+	}
+
+	for _, d := range readOnlyDirs {
+		if err := bindMount(d, root, syscall.MS_RDONLY|syscall.MS_NOSUID|syscall.MS_NODEV); err != nil {
+			return err
+		}
+	}
+
+	if err := mountFS("tmpfs", filepath.Join(root, "tmp"), syscall.MS_NOSUID|syscall.MS_NODEV, "mode=1777,size=64m"); err != nil {
+		return err
+	}
+
+	// Mounted after /tmp, since the work directory usually lives inside it.
+	if err := bindMount(dir, root, syscall.MS_NOSUID|syscall.MS_NODEV); err != nil {
+		return err
+	}
+
+	if err := makeDevices(root); err != nil {
+		return err
+	}
+
+	// Other users' processes, including the server, stay hidden.
+	if err := mountFS("proc", filepath.Join(root, "proc"), syscall.MS_NOSUID|syscall.MS_NODEV|syscall.MS_NOEXEC, "hidepid=2"); err != nil {
+		return err
+	}
+
+	if err := pivotRoot(root); err != nil {
+		return err
+	}
+
+	if err := syscall.Mount("", "/", "", syscall.MS_REMOUNT|syscall.MS_BIND|syscall.MS_RDONLY|syscall.MS_NOSUID|syscall.MS_NODEV, ""); err != nil {
+		return errors.WrapIf(err, "failed to make sandbox root read-only")
+	}
+
+	return errors.WrapIf(os.Chdir(dir), "failed to enter working directory")
+}
+
+// bindMount mounts source at the same path below root, with flags applied to
+// the new mount.
+func bindMount(source, root string, flags uintptr) error {
+	info, err := os.Stat(source)
+	if err != nil {
+		return errors.WrapIf(err, "failed to inspect bind mount source")
+	}
+
+	target := filepath.Join(root, source)
+	if info.IsDir() {
+		err = os.MkdirAll(target, 0o755)
+	} else if err = os.MkdirAll(filepath.Dir(target), 0o755); err == nil {
+		err = os.WriteFile(target, nil, 0o644)
+	}
+
+	if err != nil {
+		return errors.WrapIf(err, "failed to create bind mount target")
+	}
+
+	if err := syscall.Mount(source, target, "", syscall.MS_BIND, ""); err != nil {
+		return errors.WrapIf(err, "failed to bind mount "+source)
+	}
+
+	// Flags of a bind mount can only be changed by remounting it.
+	if err := syscall.Mount("", target, "", syscall.MS_REMOUNT|syscall.MS_BIND|flags, ""); err != nil {
+		return errors.WrapIf(err, "failed to remount "+source)
+	}
+
+	return nil
+}
+
+func mountFS(fsType, target string, flags uintptr, data string) error {
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		return errors.WrapIf(err, "failed to create mount point")
+	}
+
+	return errors.WrapIf(syscall.Mount(fsType, target, fsType, flags, data), "failed to mount "+fsType)
+}
+
+func makeDevices(root string) error {
+	for _, name := range devices {
+		if err := bindMount(filepath.Join("/dev", name), root, syscall.MS_NOSUID|syscall.MS_NOEXEC); err != nil {
+			return err
+		}
+	}
+
+	links := map[string]string{
+		"fd":     "/proc/self/fd",
+		"stdin":  "/proc/self/fd/0",
+		"stdout": "/proc/self/fd/1",
+		"stderr": "/proc/self/fd/2",
+	}
+
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, "dev", name)); err != nil {
+			return errors.WrapIf(err, "failed to create device link")
+		}
+	}
+
+	return nil
+}
+
+// pivotRoot makes root the root directory and detaches the old one, so that
+// it cannot be reached through any path afterwards.
+func pivotRoot(root string) error {
+	oldRoot := filepath.Join(root, ".old")
+	if err := os.Mkdir(oldRoot, 0o700); err != nil {
+		return errors.WrapIf(err, "failed to create old root mount point")
+	}
+
+	if err := syscall.PivotRoot(root, oldRoot); err != nil {
+		return errors.WrapIf(err, "failed to pivot root")
+	}
+
+	if err := os.Chdir("/"); err != nil {
+		return errors.WrapIf(err, "failed to enter new root")
+	}
+
+	if err := syscall.Unmount("/.old", syscall.MNT_DETACH); err != nil {
+		return errors.WrapIf(err, "failed to detach old root")
+	}
+
+	return errors.WrapIf(os.Remove("/.old"), "failed to remove old root mount point")
+}
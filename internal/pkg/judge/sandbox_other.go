@@ -0,0 +1,34 @@
+//go:build !linux
+
+package judge
+
+import (
+	"syscall"
+
+	"emperror.dev/errors"
+)
+
+const (
+	platformSupported = false
+	// Never reported since nothing runs; they only need to be distinct.
+	signalCPULimit      = syscall.Signal(-1)
+	signalFileSizeLimit = syscall.Signal(-2)
+)
+
+// checkSandboxPrivileges has nothing to check, every run fails with
+// ErrSandboxUnsupported instead.
+func checkSandboxPrivileges() error {
+	return nil
+}
+
+func sysProcAttr() *syscall.SysProcAttr {
+	return nil
+}
+
+func killProcessGroup(int) error {
+	return errors.WithStack(ErrSandboxUnsupported)
+}
+
+func killLeftovers(int) error {
+	return errors.WithStack(ErrSandboxUnsupported)
+}
@@ -0,0 +1,133 @@
+//go:build linux && (amd64 || arm64)
+
+package judge
+
+import (
+	"runtime"
+	"unsafe"
+
+	"emperror.dev/errors"
+	"golang.org/x/sys/unix"
+)
+
+// deniedSyscalls fail with EPERM. They reach beyond the process itself, by
+// changing mounts, namespaces or the kernel, by reading the memory of other
+// processes or by leaving the process group that is killed after the run, and
+// none of them is needed to compile or run a program.
+var deniedSyscalls = append([]uintptr{
+	unix.SYS_SETSID, unix.SYS_SETPGID,
+	unix.SYS_MOUNT, unix.SYS_UMOUNT2, unix.SYS_PIVOT_ROOT, unix.SYS_CHROOT,
+	unix.SYS_FSOPEN, unix.SYS_FSCONFIG, unix.SYS_FSMOUNT, unix.SYS_FSPICK,
+	unix.SYS_MOVE_MOUNT, unix.SYS_OPEN_TREE, unix.SYS_MOUNT_SETATTR,
+	unix.SYS_UNSHARE, unix.SYS_SETNS,
+	unix.SYS_INIT_MODULE, unix.SYS_FINIT_MODULE, unix.SYS_DELETE_MODULE,
+	unix.SYS_KEXEC_LOAD, unix.SYS_KEXEC_FILE_LOAD, unix.SYS_REBOOT,
+	unix.SYS_SWAPON, unix.SYS_SWAPOFF, unix.SYS_SYSLOG, unix.SYS_ACCT, unix.SYS_QUOTACTL,
+	unix.SYS_SETTIMEOFDAY, unix.SYS_CLOCK_SETTIME, unix.SYS_ADJTIMEX, unix.SYS_CLOCK_ADJTIME,
+	unix.SYS_KEYCTL, unix.SYS_ADD_KEY, unix.SYS_REQUEST_KEY,
+	unix.SYS_BPF, unix.SYS_PERF_EVENT_OPEN, unix.SYS_USERFAULTFD, unix.SYS_FANOTIFY_INIT,
+	unix.SYS_IO_URING_SETUP, unix.SYS_IO_URING_ENTER, unix.SYS_IO_URING_REGISTER,
+	unix.SYS_PROCESS_VM_READV, unix.SYS_PROCESS_VM_WRITEV, unix.SYS_KCMP, unix.SYS_PIDFD_GETFD,
+	unix.SYS_OPEN_BY_HANDLE_AT, unix.SYS_NAME_TO_HANDLE_AT, unix.SYS_LOOKUP_DCOOKIE,
+}, archDeniedSyscalls...)
+
+// namespaceCloneFlags are the clone flags that create namespaces.
+const namespaceCloneFlags = unix.CLONE_NEWNS | unix.CLONE_NEWUSER | unix.CLONE_NEWPID | unix.CLONE_NEWNET |
+	unix.CLONE_NEWIPC | unix.CLONE_NEWUTS | unix.CLONE_NEWCGROUP
+
+// Offsets into struct seccomp_data.
+const (
+	seccompDataNr   = 0
+	seccompDataArch = 4
+	seccompDataArg0 = 16 // Low 32 bits on little-endian architectures
+)
+
+// installSyscallFilter restricts the system calls of every thread of the
+// helper, and so of the command it starts, which inherits the filter. It also
+// sets no_new_privs, so that set-user-ID programs cannot regain privileges.
+func installSyscallFilter() error {
+	// Both have to happen on the same thread; the filter is then synchronized
+	// to the other threads along with no_new_privs.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return errors.WrapIf(err, "failed to set no_new_privs")
+	}
+
+	filter := syscallFilter()
+	prog := unix.SockFprog{Len: uint16(len(filter)), Filter: &filter[0]}
+
+	thread, _, errno := unix.Syscall(
+		unix.SYS_SECCOMP,
+		unix.SECCOMP_SET_MODE_FILTER,
+		unix.SECCOMP_FILTER_FLAG_TSYNC,
+		uintptr(unsafe.Pointer(&prog)),
+	)
+	if errno != 0 {
+		return errors.WrapIf(errno, "failed to install system call filter")
+	} else if thread != 0 {
+		return errors.Errorf("thread %d could not adopt the system call filter", thread)
+	}
+
+	return nil
+}
+
+// syscallFilter returns the BPF program of the filter. It kills processes
+// making system calls for another architecture, whose numbers differ.
+func syscallFilter() []unix.SockFilter {
+	deny := unix.SECCOMP_RET_ERRNO | uint32(unix.EPERM)
+
+	f := []unix.SockFilter{
+		bpfLoad(seccompDataArch),
+		bpfJump(unix.BPF_JEQ, auditArch, 1, 0),
+		bpfReturn(unix.SECCOMP_RET_KILL_PROCESS),
+		bpfLoad(seccompDataNr),
+	}
+	f = append(f, archFilter()...)
+
+	for _, nr := range deniedSyscalls {
+		f = append(f, bpfJump(unix.BPF_JEQ, uint32(nr), 0, 1), bpfReturn(deny))
+	}
+
+	// The flags of clone3 sit behind a pointer the filter cannot follow.
+	// Failing with ENOSYS makes the C library fall back to clone.
+	f = append(f,
+		bpfJump(unix.BPF_JEQ, unix.SYS_CLONE3, 0, 1),
+		bpfReturn(unix.SECCOMP_RET_ERRNO|uint32(unix.ENOSYS)),
+	)
+
+	// clone still starts processes and threads, only not in new namespaces.
+	f = append(f,
+		bpfJump(unix.BPF_JEQ, unix.SYS_CLONE, 0, 4),
+		bpfLoad(seccompDataArg0),
+		bpfJump(unix.BPF_JSET, namespaceCloneFlags, 0, 1),
+		bpfReturn(deny),
+		bpfReturn(unix.SECCOMP_RET_ALLOW),
+	)
+
+	// The helper traces the command, which in turn must not attach to any
+	// other process.
+	f = append(f,
+		bpfJump(unix.BPF_JEQ, unix.SYS_PTRACE, 0, 5),
+		bpfLoad(seccompDataArg0),
+		bpfJump(unix.BPF_JEQ, unix.PTRACE_ATTACH, 2, 0),
+		bpfJump(unix.BPF_JEQ, unix.PTRACE_SEIZE, 1, 0),
+		bpfReturn(unix.SECCOMP_RET_ALLOW),
+		bpfReturn(deny),
+	)
+
+	return append(f, bpfReturn(unix.SECCOMP_RET_ALLOW))
+}
+
+func bpfLoad(offset uint32) unix.SockFilter {
+	return unix.SockFilter{Code: unix.BPF_LD | unix.BPF_W | unix.BPF_ABS, K: offset}
+}
+
+func bpfJump(op uint16, k uint32, jumpTrue, jumpFalse uint8) unix.SockFilter {
+	return unix.SockFilter{Code: unix.BPF_JMP | op | unix.BPF_K, Jt: jumpTrue, Jf: jumpFalse, K: k}
+}
+
+func bpfReturn(k uint32) unix.SockFilter {
+	return unix.SockFilter{Code: unix.BPF_RET | unix.BPF_K, K: k}
+}
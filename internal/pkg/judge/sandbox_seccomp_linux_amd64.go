@@ -0,0 +1,20 @@
+package judge
+
+import "golang.org/x/sys/unix"
+
+const auditArch = unix.AUDIT_ARCH_X86_64
+
+// x32SyscallBit marks the system calls of the x32 ABI, which share the
+// architecture with regular ones.
+const x32SyscallBit = 0x40000000
+
+var archDeniedSyscalls = []uintptr{unix.SYS_IOPERM, unix.SYS_IOPL, unix.SYS_USELIB}
+
+// archFilter kills processes making x32 system calls, which would otherwise
+// bypass the deny list with numbers of their own.
+func archFilter() []unix.SockFilter {
+	return []unix.SockFilter{
+		bpfJump(unix.BPF_JGE, x32SyscallBit, 0, 1),
+		bpfReturn(unix.SECCOMP_RET_KILL_PROCESS),
+	}
+}
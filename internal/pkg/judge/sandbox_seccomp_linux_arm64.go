@@ -0,0 +1,11 @@
+package judge
+
+import "golang.org/x/sys/unix"
+
+const auditArch = unix.AUDIT_ARCH_AARCH64
+
+var archDeniedSyscalls []uintptr
+
+func archFilter() []unix.SockFilter {
+	return nil
+}
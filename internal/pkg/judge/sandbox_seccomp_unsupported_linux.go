@@ -0,0 +1,10 @@
+//go:build linux && !amd64 && !arm64
+
+package judge
+
+import "emperror.dev/errors"
+
+// installSyscallFilter only knows the system call numbers of amd64 and arm64.
+func installSyscallFilter() error {
+	return errors.WithStack(ErrSandboxUnsupported)
+}
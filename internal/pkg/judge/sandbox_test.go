@@ -0,0 +1,16 @@
+package judge
+
+import (
+	"os"
+	"testing"
+)
+
+// TestMain lets the test binary act as the sandbox helper, like the server
+// binary does.
+func TestMain(m *testing.M) {
+	if len(os.Args) > 1 && os.Args[1] == SandboxHelperCommand {
+		os.Exit(RunSandboxHelper(os.Args[2:]))
+	}
+
+	os.Exit(m.Run())
+}
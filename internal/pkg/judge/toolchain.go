@@ -0,0 +1,401 @@
+package judge
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/genscript"
+
+	"emperror.dev/errors"
+)
+
+// toolchainTimeout bounds a whole toolchain request, including the wait for a
+// free slot, since requests are served synchronously.
+const toolchainTimeout = 2 * time.Minute
+
+// GenerateTestcases runs the generator script and produces the answers with the
+// main solution. Every generator invocation runs twice to make sure that it is
+// deterministic, so that the same script always yields the same tests.
+func (j *Judger) GenerateTestcases(
+	ctx context.Context,
+	request contract.GenerateTestcasesRequest,
+) ([]contract.GeneratedTestcase, error) {
+	return runToolchain(ctx, j, func(ctx context.Context) ([]contract.GeneratedTestcase, error) {
+		return j.generateTestcases(ctx, request)
+	})
+}
+
+func (j *Judger) generateTestcases(
+	ctx context.Context,
+	request contract.GenerateTestcasesRequest,
+) ([]contract.GeneratedTestcase, error) {
+	commands, err := genscript.Parse(request.Script)
+	if err != nil {
+		return nil, toolchainError(err.Error())
+	}
+
+	ws, err := j.newWorkspace()
+	if err != nil {
+		return nil, err
+	}
+	defer ws.remove()
+
+	generators, err := j.buildGenerators(ctx, ws, request.Generators, commands)
+	if err != nil {
+		return nil, asToolchainError(err)
+	}
+
+	var t *task
+	if request.MainSolution != nil {
+		if t, err = j.buildMainSolution(ctx, ws, request); err != nil {
+			return nil, asToolchainError(err)
+		}
+	}
+
+	testcases := make([]contract.GeneratedTestcase, 0, len(commands))
+	for i, command := range commands {
+		prefix := filepath.Join(ws.data, strconv.Itoa(i))
+
+		if err := j.runGenerator(ctx, generators[command.Generator], command, prefix+".in"); err != nil {
+			return nil, err
+		}
+
+		input, err := j.putFile(ctx, prefix+".in")
+		if err != nil {
+			return nil, err
+		}
+
+		var output contract.BlobInfo
+		if t != nil {
+			if output, err = j.generateAnswer(ctx, t, command, prefix); err != nil {
+				return nil, err
+			}
+		} else if output, err = j.blobStore.Put(ctx, strings.NewReader("")); err != nil {
+			return nil, errors.WrapIf(err, "failed to store empty answer")
+		}
+
+		testcases = append(testcases, contract.GeneratedTestcase{
+			Name:   command.Test,
+			Input:  input,
+			Output: output,
+		})
+	}
+
+	return testcases, nil
+}
+
+// ValidateInputs runs the validator on every input. testlib validators read the
+// input from standard input and exit with a non-zero code when it is invalid.
+func (j *Judger) ValidateInputs(
+	ctx context.Context,
+	validator contract.Program,
+	inputs []contract.ValidationInput,
+) ([]contract.ValidationResult, error) {
+	return runToolchain(ctx, j, func(ctx context.Context) ([]contract.ValidationResult, error) {
+		return j.validateInputs(ctx, validator, inputs)
+	})
+}
+
+func (j *Judger) validateInputs(
+	ctx context.Context,
+	validator contract.Program,
+	inputs []contract.ValidationInput,
+) ([]contract.ValidationResult, error) {
+	ws, err := j.newWorkspace()
+	if err != nil {
+		return nil, err
+	}
+	defer ws.remove()
+
+	dir, err := j.newBox(ws, programRoleValidator)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := j.buildProgram(ctx, programRoleValidator, dir, &Program{Language: validator.Language, Source: validator.Source})
+	if err != nil {
+		return nil, asToolchainError(err)
+	}
+
+	results := make([]contract.ValidationResult, 0, len(inputs))
+	for i, input := range inputs {
+		inputPath := filepath.Join(ws.data, strconv.Itoa(i)+".in")
+		if input.Hash != "" {
+			err = j.fetchBlob(ctx, input.Hash, inputPath)
+		} else {
+			err = os.WriteFile(inputPath, []byte(input.Content), 0o600)
+		}
+
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to prepare input "+input.Name)
+		}
+
+		result, err := j.validate(ctx, p, input.Name, inputPath)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, *result)
+	}
+
+	return results, nil
+}
+
+func (j *Judger) toolchainAvailable() bool {
+	return j.opts.Enabled && platformSupported
+}
+
+// runToolchain runs fn in a judge slot within toolchainTimeout.
+func runToolchain[T any](ctx context.Context, j *Judger, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	if !j.toolchainAvailable() {
+		return zero, errors.WithStack(contract.ErrTestToolchainUnavailable)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, toolchainTimeout)
+	defer cancel()
+
+	release, err := j.acquireSlot(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return zero, errors.WithStack(contract.ErrTestToolchainBusy)
+	} else if err != nil {
+		return zero, err
+	}
+	defer release()
+
+	// Programs cut short by the deadline look like they exceeded their own
+	// time limit, so their results cannot be used either.
+	result, err := fn(ctx)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return zero, errors.WithStack(contract.ErrTestToolchainTimeout)
+	}
+
+	return result, err
+}
+
+func (j *Judger) buildGenerators(
+	ctx context.Context,
+	ws *workspace,
+	available []contract.Generator,
+	commands []genscript.Command,
+) (map[string]*program, error) {
+	sources := make(map[string]contract.Generator, len(available))
+	for _, generator := range available {
+		sources[generator.Name] = generator
+	}
+
+	generators := make(map[string]*program)
+	for _, command := range commands {
+		if _, ok := generators[command.Generator]; ok {
+			continue
+		}
+
+		generator, ok := sources[command.Generator]
+		if !ok {
+			return nil, toolchainError(fmt.Sprintf("line %d: unknown generator %s", command.Line, command.Generator))
+		}
+
+		dir, err := j.newBox(ws, "generator-"+strconv.Itoa(len(generators)))
+		if err != nil {
+			return nil, err
+		}
+
+		p, err := j.buildProgram(ctx, "generator "+generator.Name, dir, &Program{
+			Language: generator.Language,
+			Source:   generator.Source,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		generators[command.Generator] = p
+	}
+
+	return generators, nil
+}
+
+func (j *Judger) buildMainSolution(ctx context.Context, ws *workspace, request contract.GenerateTestcasesRequest) (*task, error) {
+	lang, err := getLanguage(request.MainSolution.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	log, ok, err := j.build(ctx, ws.box, lang, request.MainSolution.Source, "")
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, toolchainError("main solution failed to compile:\n" + log)
+	}
+
+	return &task{
+		ws:   ws,
+		lang: lang,
+		submission: Submission{
+			Language:      request.MainSolution.Language,
+			Source:        request.MainSolution.Source,
+			TimeLimitMs:   request.TimeLimitMs,
+			MemoryLimitMb: request.MemoryLimitMb,
+			InputFile:     request.InputFile,
+			OutputFile:    request.OutputFile,
+		},
+	}, nil
+}
+
+func (j *Judger) runGenerator(ctx context.Context, generator *program, command genscript.Command, outputPath string) error {
+	checkPath := outputPath + ".check"
+	defer os.Remove(checkPath)
+
+	for _, path := range []string{outputPath, checkPath} {
+		if err := j.runGeneratorOnce(ctx, generator, command, path); err != nil {
+			return err
+		}
+	}
+
+	same, err := sameContent(outputPath, checkPath)
+	if err != nil {
+		return err
+	} else if !same {
+		return toolchainError(fmt.Sprintf(
+			"line %d (%s): %s is not deterministic, two runs produced different tests",
+			command.Line, command, generator.role,
+		))
+	}
+
+	return nil
+}
+
+func (j *Judger) runGeneratorOnce(ctx context.Context, generator *program, command genscript.Command, outputPath string) error {
+	out, err := os.Create(outputPath)
+	if err != nil {
+		return errors.WrapIf(err, "failed to create generated input file")
+	}
+	defer out.Close()
+
+	feedback := &limitedBuffer{limit: maxFeedbackSize}
+	u, err := j.sandbox.run(ctx, generator.process(command.Args, nil, out, feedback, programWallTime))
+	if err != nil {
+		return errors.WrapIf(err, "failed to run generator")
+	}
+
+	if u.failed() || u.timedOut {
+		return toolchainError(fmt.Sprintf(
+			"line %d (%s): %s %s",
+			command.Line, command, generator.role, withFeedback(describeFailure(u), strings.TrimSpace(feedback.String())),
+		))
+	}
+
+	return nil
+}
+
+func (j *Judger) generateAnswer(ctx context.Context, t *task, command genscript.Command, prefix string) (contract.BlobInfo, error) {
+	u, outputPath, err := j.runSolution(ctx, t, prefix+".in", prefix+".out")
+	if err != nil {
+		return contract.BlobInfo{}, err
+	}
+
+	if verdict, ok := t.resourceVerdict(u); ok {
+		return contract.BlobInfo{}, toolchainError(fmt.Sprintf("main solution got %s on test %s", verdict.Abbreviation(), command.Test))
+	} else if u.failed() {
+		return contract.BlobInfo{}, toolchainError(fmt.Sprintf("main solution failed on test %s: %s", command.Test, describeFailure(u)))
+	}
+
+	return j.putFile(ctx, outputPath)
+}
+
+func (j *Judger) validate(ctx context.Context, validator *program, name string, inputPath string) (*contract.ValidationResult, error) {
+	stdin, err := os.Open(inputPath)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to open input file")
+	}
+	defer stdin.Close()
+
+	feedback := &limitedBuffer{limit: maxFeedbackSize}
+	u, err := j.sandbox.run(ctx, validator.process(nil, stdin, nil, feedback, programWallTime))
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to run validator")
+	}
+
+	result := &contract.ValidationResult{
+		Name:    name,
+		Valid:   !u.failed() && !u.timedOut,
+		Message: strings.TrimSpace(feedback.String()),
+	}
+
+	if !result.Valid && (result.Message == "" || u.signal != 0 || u.timedOut) {
+		result.Message = withFeedback(programRoleValidator+" "+describeFailure(u), result.Message)
+	}
+
+	return result, nil
+}
+
+// putFile stores a file as a blob; a file that was never created is stored as
+// empty content.
+func (j *Judger) putFile(ctx context.Context, path string) (contract.BlobInfo, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return j.blobStore.Put(ctx, strings.NewReader(""))
+	} else if err != nil {
+		return contract.BlobInfo{}, errors.WrapIf(err, "failed to open generated file")
+	}
+	defer f.Close()
+
+	info, err := j.blobStore.Put(ctx, f)
+	if err != nil {
+		return contract.BlobInfo{}, errors.WrapIf(err, "failed to store generated file")
+	}
+
+	return info, nil
+}
+
+func sameContent(a string, b string) (bool, error) {
+	hashA, err := hashFile(a)
+	if err != nil {
+		return false, err
+	}
+
+	hashB, err := hashFile(b)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(hashA, hashB), nil
+}
+
+func hashFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to open file")
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, errors.WrapIf(err, "failed to hash file")
+	}
+
+	return h.Sum(nil), nil
+}
+
+func toolchainError(message string) error {
+	return errors.WithStack(&contract.TestToolchainError{Message: message})
+}
+
+// asToolchainError exposes a failing checker, interactor, validator or
+// generator to the setter, and leaves other errors alone.
+func asToolchainError(err error) error {
+	var programErr *programError
+	if errors.As(err, &programErr) {
+		return toolchainError(programErr.Error())
+	}
+
+	return err
+}
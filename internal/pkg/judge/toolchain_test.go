@@ -0,0 +1,66 @@
+package judge
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+
+	"emperror.dev/errors"
+)
+
+func TestRunToolchain(t *testing.T) {
+	tests := []struct {
+		name      string
+		enabled   bool
+		slotTaken bool
+		runFor    time.Duration
+		wantErr   error
+	}{
+		{name: "disabled", wantErr: contract.ErrTestToolchainUnavailable},
+		{name: "free slot", enabled: true},
+		{name: "no free slot before the deadline", enabled: true, slotTaken: true, wantErr: contract.ErrTestToolchainBusy},
+		{name: "running past the deadline", enabled: true, runFor: time.Second, wantErr: contract.ErrTestToolchainTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !platformSupported && tt.enabled {
+				t.Skip(ErrSandboxUnsupported)
+			}
+
+			j := &Judger{opts: &Options{Enabled: tt.enabled}, slots: make(chan struct{}, 1)}
+			if tt.slotTaken {
+				j.slots <- struct{}{}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			got, err := runToolchain(ctx, j, func(ctx context.Context) (int, error) {
+				select {
+				case <-time.After(tt.runFor):
+				case <-ctx.Done():
+				}
+
+				return 1, nil
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("runToolchain() error = %v, want %v", err, tt.wantErr)
+			} else if tt.wantErr == nil && got != 1 {
+				t.Errorf("runToolchain() = %d, want 1", got)
+			}
+
+			wantTaken := 0
+			if tt.slotTaken {
+				wantTaken = 1
+			}
+
+			if len(j.slots) != wantTaken {
+				t.Errorf("%d slots taken afterwards, want %d", len(j.slots), wantTaken)
+			}
+		})
+	}
+}
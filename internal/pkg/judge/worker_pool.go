@@ -0,0 +1,259 @@
+package judge
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+type Job struct {
+	JudgeRunID      uuid.UUID
+	ProblemID       uuid.UUID
+	SolutionName    string
+	IsMain          bool
+	ExpectedVerdict string
+	Submission      Submission
+	Testcases       []Testcase
+}
+
+// ErrLeaseLost is returned for runs another worker pool took over after their
+// lease expired.
+var ErrLeaseLost = errors.New("judge run is no longer claimed by this worker pool")
+
+// ErrJobMissing is returned for runs whose solution or problem version is gone.
+var ErrJobMissing = errors.New("solution of judge run no longer exists")
+
+const (
+	// leaseDuration is how long a claimed run stays with its worker pool
+	// without being renewed, e.g. after the server crashed.
+	leaseDuration      = time.Minute
+	leaseRenewInterval = leaseDuration / 3
+)
+
+// Repository changes runs claimed by a worker pool only while the pool still
+// holds their lease, and returns ErrLeaseLost otherwise.
+type Repository interface {
+	// ClaimNextRun marks the oldest queued run, or a running one whose lease
+	// expired, as running under a lease of claimedBy and returns it, or nil when
+	// there is none. Runs whose job cannot be loaded are marked as failed.
+	ClaimNextRun(ctx context.Context, claimedBy uuid.UUID, startedAt time.Time, leaseUntil time.Time) (*Job, error)
+	RenewLease(ctx context.Context, judgeRunID uuid.UUID, claimedBy uuid.UUID, leaseUntil time.Time) error
+	// ReleaseRun puts a claimed run back into the queue.
+	ReleaseRun(ctx context.Context, judgeRunID uuid.UUID, claimedBy uuid.UUID) error
+	FinishRun(ctx context.Context, judgeRunID uuid.UUID, claimedBy uuid.UUID, result *Result, finishedAt time.Time) error
+	FailRun(ctx context.Context, judgeRunID uuid.UUID, claimedBy uuid.UUID, reason string, finishedAt time.Time) error
+}
+
+type WorkerPool struct {
+	id          uuid.UUID // Identifies the leases of this pool
+	opts        *Options
+	repo        Repository
+	judger      *Judger
+	uowFactory  contract.UnitOfWorkFactory
+	broadcaster contract.MessageBroadcaster
+	l           logger.Logger
+	wake        chan struct{}
+}
+
+func NewWorkerPool(
+	opts *Options,
+	repo Repository,
+	judger *Judger,
+	uowFactory contract.UnitOfWorkFactory,
+	broadcaster contract.MessageBroadcaster,
+	l logger.Logger,
+) *WorkerPool {
+	return &WorkerPool{
+		id:          uuid.New(),
+		opts:        opts,
+		repo:        repo,
+		judger:      judger,
+		uowFactory:  uowFactory,
+		broadcaster: broadcaster,
+		l:           l,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+func (p *WorkerPool) Notify() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run processes queued judge runs until ctx is cancelled. It returns right
+// away when judging is disabled; runs then stay queued.
+func (p *WorkerPool) Run(ctx context.Context) {
+	if !p.opts.Enabled {
+		p.l.Info("Judge is disabled, queued judge runs will not be processed.")
+		return
+	}
+
+	var wg sync.WaitGroup
+	for range p.opts.GetWorkers() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (p *WorkerPool) work(ctx context.Context) {
+	ticker := time.NewTicker(p.opts.GetPollInterval())
+	defer ticker.Stop()
+
+	for {
+		processed, err := p.processNext(ctx)
+		if err != nil {
+			p.l.Error("failed to process judge run", err)
+		}
+
+		if processed {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.wake:
+			// Pass the wake-up on, since more than one run may have been queued.
+			p.Notify()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *WorkerPool) processNext(ctx context.Context) (bool, error) {
+	now := time.Now()
+	job, err := p.repo.ClaimNextRun(ctx, p.id, now, now.Add(leaseDuration))
+	if err != nil {
+		// A run that failed to load has been marked as failed, so the next one
+		// can be tried right away.
+		return errors.Is(err, ErrJobMissing), errors.WrapIf(err, "failed to claim judge run")
+	} else if job == nil {
+		return false, nil
+	}
+
+	judgeCtx, cancelJudge := context.WithCancelCause(ctx)
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		p.renewLease(judgeCtx, cancelJudge, job.JudgeRunID)
+	}()
+
+	result, err := p.judger.Judge(judgeCtx, job.Submission, job.Testcases)
+	cancelJudge(nil)
+	<-renewed
+
+	if ctx.Err() != nil {
+		// Shutting down; the run goes back to the queue for the next worker.
+		if err := p.repo.ReleaseRun(context.WithoutCancel(ctx), job.JudgeRunID, p.id); err != nil {
+			return true, errors.WrapIf(err, "failed to release judge run")
+		}
+
+		return true, nil
+	} else if errors.Is(context.Cause(judgeCtx), ErrLeaseLost) {
+		// Another worker pool has taken the run over.
+		return true, nil
+	}
+
+	if err != nil {
+		p.l.Errorw("judge run failed", map[string]interface{}{
+			"judge_run_id": job.JudgeRunID,
+			"error":        err.Error(),
+		})
+
+		if err := p.repo.FailRun(ctx, job.JudgeRunID, p.id, failureReason(err), time.Now()); err != nil {
+			return true, errors.WrapIf(err, "failed to mark judge run as failed")
+		}
+
+		return true, nil
+	}
+
+	finishedAt := time.Now()
+
+	// The judged message is sent if and only if the result is saved.
+	if err := uowhelper.Do(ctx, p.uowFactory.New(), p.l, func(ctx context.Context) error {
+		if err := p.repo.FinishRun(ctx, job.JudgeRunID, p.id, result, finishedAt); err != nil {
+			return errors.WrapIf(err, "failed to save judge run result")
+		}
+
+		if err := p.broadcaster.BroadcastJudgedMessage(ctx, job.ProblemID, contract.MessageJudgeRun{
+			JudgeRunID:      job.JudgeRunID,
+			SolutionName:    job.SolutionName,
+			IsMain:          job.IsMain,
+			ExpectedVerdict: job.ExpectedVerdict,
+			Verdict:         string(result.Verdict),
+			PassedCount:     result.PassedCount,
+			TotalCount:      len(job.Testcases),
+		}, finishedAt); err != nil {
+			return errors.WrapIf(err, "failed to broadcast judged message")
+		}
+
+		return nil
+	}); err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
+
+// renewLease keeps the lease of a run until ctx is done, and stops judging it
+// once another worker pool has taken it over.
+func (p *WorkerPool) renewLease(ctx context.Context, cancel context.CancelCauseFunc, judgeRunID uuid.UUID) {
+	ticker := time.NewTicker(leaseRenewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := p.repo.RenewLease(ctx, judgeRunID, p.id, time.Now().Add(leaseDuration))
+		if errors.Is(err, ErrLeaseLost) {
+			p.l.Errorw("lost the lease of a judge run", map[string]interface{}{
+				"judge_run_id": judgeRunID,
+			})
+			cancel(ErrLeaseLost)
+
+			return
+		} else if err != nil && ctx.Err() == nil {
+			// The lease outlives a few failed renewals.
+			p.l.Error("failed to renew judge run lease", err)
+		}
+	}
+}
+
+// failureReason keeps internal details such as file paths out of the reason
+// shown to users for the errors they can act on.
+func failureReason(err error) string {
+	var programErr *programError
+
+	switch {
+	case errors.As(err, &programErr):
+		return programErr.Error()
+	case errors.Is(err, ErrMissingInteractor):
+		return ErrMissingInteractor.Error()
+	case errors.Is(err, ErrUnsupportedLanguage):
+		return ErrUnsupportedLanguage.Error()
+	case errors.Is(err, ErrSandboxUnsupported):
+		return ErrSandboxUnsupported.Error()
+	case errors.Is(err, ErrJobMissing):
+		return ErrJobMissing.Error()
+	default:
+		return "internal judge error"
+	}
+}
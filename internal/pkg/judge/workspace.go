@@ -0,0 +1,83 @@
+package judge
+
+import (
+	"os"
+	"path/filepath"
+
+	"emperror.dev/errors"
+)
+
+// workspace is a scratch directory for a single run. The solution works inside
+// box, the only directory of the run mounted into its sandbox, while testcase
+// inputs and answers are kept in data. Custom checkers and interactors get their
+// own directories next to the box.
+type workspace struct {
+	root       string
+	box        string
+	checker    string
+	interactor string
+	data       string
+}
+
+func (j *Judger) newWorkspace() (*workspace, error) {
+	if err := os.MkdirAll(j.opts.GetWorkDir(), 0o755); err != nil {
+		return nil, errors.WrapIf(err, "failed to create judge work directory")
+	}
+
+	root, err := os.MkdirTemp(j.opts.GetWorkDir(), "run-*")
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create run directory")
+	}
+
+	ws := &workspace{
+		root:       root,
+		box:        filepath.Join(root, "box"),
+		checker:    filepath.Join(root, "checker"),
+		interactor: filepath.Join(root, "interactor"),
+		data:       filepath.Join(root, "data"),
+	}
+
+	if err := os.Mkdir(ws.data, 0o700); err != nil {
+		ws.remove()
+		return nil, errors.WrapIf(err, "failed to create data directory")
+	}
+
+	for _, dir := range []string{ws.box, ws.checker, ws.interactor} {
+		if err := j.makeBox(dir); err != nil {
+			ws.remove()
+			return nil, err
+		}
+	}
+
+	return ws, nil
+}
+
+// newBox creates another directory for a program to run in, next to the box.
+func (j *Judger) newBox(ws *workspace, name string) (string, error) {
+	dir := filepath.Join(ws.root, name)
+	if err := j.makeBox(dir); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+func (j *Judger) makeBox(dir string) error {
+	// Solutions run as a different user, who has to be able to write here.
+	const mode = os.FileMode(0o777)
+
+	if err := os.Mkdir(dir, mode); err != nil {
+		return errors.WrapIf(err, "failed to create box directory")
+	}
+
+	// Mkdir is subject to the umask.
+	if err := os.Chmod(dir, mode); err != nil {
+		return errors.WrapIf(err, "failed to set box directory permissions")
+	}
+
+	return nil
+}
+
+func (ws *workspace) remove() {
+	_ = os.RemoveAll(ws.root)
+}
@@ -0,0 +1,23 @@
+package outbox
+
+import (
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+
+	"emperror.dev/errors"
+	"go.uber.org/dig"
+)
+
+// AddOutbox expects the handlers of all topics to be provided as a
+// []contract.OutboxHandler.
+func AddOutbox(container *dig.Container) error {
+	if err := container.Provide(NewGormRepository,
+		dig.As(new(Repository), new(contract.Outbox))); err != nil {
+		return errors.WrapIf(err, "failed to provide outbox repository")
+	}
+
+	if err := container.Provide(NewDispatcher); err != nil {
+		return errors.WrapIf(err, "failed to provide outbox dispatcher")
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	pollInterval = 500 * time.Millisecond
+
+	// Number of events handled at once, of different keys.
+	maxInFlight = 16
+
+	// Time allowed to handle an event. The event is handed out again once
+	// its lease runs out, should the instance handling it stop.
+	handleWait    = 30 * time.Second
+	leaseDuration = 2 * handleWait
+
+	// Failed events are retried with exponential backoff until they are
+	// given up on.
+	maxAttempts = 10
+	minBackoff  = time.Second
+	maxBackoff  = 5 * time.Minute
+
+	// Dispatched events are kept for a while to look into what happened.
+	retention       = 7 * 24 * time.Hour
+	cleanupInterval = time.Hour
+)
+
+// ErrLeaseLost is returned when an event is updated after its lease ran out
+// and it was claimed again.
+var ErrLeaseLost = errors.New("outbox event lease was lost")
+
+type Event struct {
+	EventID  uuid.UUID
+	Topic    string
+	Key      string
+	Payload  []byte
+	Attempts int // Including the one under way
+}
+
+type Repository interface {
+	// Added is signalled once events added in a transaction are committed.
+	Added() <-chan struct{}
+	// ClaimNextEvent leases the oldest event due for dispatch, whose key has
+	// no older event left to dispatch, until leaseUntil and returns it, or nil
+	// when there is none.
+	ClaimNextEvent(ctx context.Context, now time.Time, leaseUntil time.Time) (*Event, error)
+	// MarkDispatched, RetryEvent and FailEvent return ErrLeaseLost unless the
+	// event is still leased by the claim that counted the given attempts.
+	MarkDispatched(ctx context.Context, eventID uuid.UUID, attempts int, dispatchedAt time.Time) error
+	RetryEvent(ctx context.Context, eventID uuid.UUID, attempts int, reason string, retryAt time.Time) error
+	FailEvent(ctx context.Context, eventID uuid.UUID, attempts int, reason string) error
+	DeleteDispatchedEvents(ctx context.Context, before time.Time) error
+}
+
+// Dispatcher hands the committed events of the outbox to the handlers of
+// their topics. Each server instance runs one, and the events are delivered
+// at least once.
+type Dispatcher struct {
+	repo     Repository
+	handlers map[string]contract.OutboxHandler
+	l        logger.Logger
+
+	mu    sync.Mutex
+	lanes map[string][]*Event // The events claimed for each key being dispatched, in order
+}
+
+func NewDispatcher(repo Repository, handlers []contract.OutboxHandler, l logger.Logger) *Dispatcher {
+	d := &Dispatcher{
+		repo:     repo,
+		handlers: make(map[string]contract.OutboxHandler, len(handlers)),
+		l:        l,
+		lanes:    make(map[string][]*Event),
+	}
+
+	for _, handler := range handlers {
+		d.handlers[handler.Topic()] = handler
+	}
+
+	return d
+}
+
+// Run dispatches the events of the outbox until ctx is cancelled. It is woken
+// up as soon as events are committed, and polls for those committed on other
+// server instances and those due for a retry.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	slots := make(chan struct{}, maxInFlight)
+	var lastCleanup time.Time
+
+	for ctx.Err() == nil {
+		if time.Since(lastCleanup) >= cleanupInterval {
+			if err := d.repo.DeleteDispatchedEvents(ctx, time.Now().Add(-retention)); err != nil {
+				d.l.Error("failed to delete dispatched outbox events", err)
+			}
+
+			lastCleanup = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case slots <- struct{}{}:
+		}
+
+		now := time.Now()
+		event, err := d.repo.ClaimNextEvent(ctx, now, now.Add(leaseDuration))
+		if err != nil {
+			d.l.Error("failed to claim outbox event", err)
+		}
+
+		if event != nil {
+			d.enqueue(ctx, &wg, event, func() { <-slots })
+			continue
+		}
+
+		<-slots
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.repo.Added():
+		case <-ticker.C:
+		}
+	}
+}
+
+// enqueue dispatches the event after the events of its key claimed before,
+// and calls done once it is dispatched.
+func (d *Dispatcher) enqueue(ctx context.Context, wg *sync.WaitGroup, event *Event, done func()) {
+	d.mu.Lock()
+	queue, dispatching := d.lanes[event.Key]
+	d.lanes[event.Key] = append(queue, event)
+	d.mu.Unlock()
+
+	if dispatching {
+		return
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			d.mu.Lock()
+			queue := d.lanes[event.Key]
+			if len(queue) == 0 {
+				delete(d.lanes, event.Key)
+				d.mu.Unlock()
+
+				return
+			}
+
+			next := queue[0]
+			d.lanes[event.Key] = queue[1:]
+			d.mu.Unlock()
+
+			if err := d.dispatch(ctx, next); err != nil {
+				d.l.Error("failed to dispatch outbox event", err)
+			}
+
+			done()
+		}
+	}()
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, event *Event) error {
+	handler, ok := d.handlers[event.Topic]
+	if !ok {
+		if err := d.repo.FailEvent(ctx, event.EventID, event.Attempts, "no handler for topic "+event.Topic); err != nil {
+			return errors.WrapIf(err, "failed to mark outbox event as failed")
+		}
+
+		return nil
+	}
+
+	handleCtx, cancel := context.WithTimeout(ctx, handleWait)
+	err := handler.HandleOutboxEvent(handleCtx, event.EventID, event.Payload)
+	cancel()
+
+	if ctx.Err() != nil {
+		// Shutting down; the event is handed out again once its lease runs out.
+		return nil
+	}
+
+	if err == nil {
+		if err := d.repo.MarkDispatched(ctx, event.EventID, event.Attempts, time.Now()); err != nil {
+			return errors.WrapIf(err, "failed to mark outbox event as dispatched")
+		}
+
+		return nil
+	}
+
+	d.l.Errorw("outbox event failed", map[string]interface{}{
+		"outbox_event_id": event.EventID,
+		"topic":           event.Topic,
+		"attempts":        event.Attempts,
+		"error":           err.Error(),
+	})
+
+	if event.Attempts >= maxAttempts {
+		if err := d.repo.FailEvent(ctx, event.EventID, event.Attempts, err.Error()); err != nil {
+			return errors.WrapIf(err, "failed to mark outbox event as failed")
+		}
+
+		return nil
+	}
+
+	if err := d.repo.RetryEvent(ctx, event.EventID, event.Attempts, err.Error(), time.Now().Add(backoff(event.Attempts))); err != nil {
+		return errors.WrapIf(err, "failed to schedule outbox event retry")
+	}
+
+	return nil
+}
+
+// backoff returns the time to wait before retrying an event after its given
+// number of attempts.
+func backoff(attempts int) time.Duration {
+	wait := minBackoff
+	for range attempts - 1 {
+		wait *= 2
+		if wait >= maxBackoff {
+			return maxBackoff
+		}
+	}
+
+	return wait
+}
@@ -0,0 +1,283 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger/defaultlogger"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+const testTopic = "test"
+
+type fakeRepository struct {
+	mu         sync.Mutex
+	events     []*Event // Due for dispatch, oldest first
+	dispatched []uuid.UUID
+	retried    []uuid.UUID
+	failed     []uuid.UUID
+}
+
+func (r *fakeRepository) Added() <-chan struct{} {
+	return nil
+}
+
+func (r *fakeRepository) ClaimNextEvent(context.Context, time.Time, time.Time) (*Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.events) == 0 {
+		return nil, nil
+	}
+
+	event := r.events[0]
+	r.events = r.events[1:]
+	event.Attempts++
+
+	return event, nil
+}
+
+func (r *fakeRepository) MarkDispatched(_ context.Context, eventID uuid.UUID, _ int, _ time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.dispatched = append(r.dispatched, eventID)
+	return nil
+}
+
+func (r *fakeRepository) RetryEvent(_ context.Context, eventID uuid.UUID, _ int, _ string, _ time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.retried = append(r.retried, eventID)
+	return nil
+}
+
+func (r *fakeRepository) FailEvent(_ context.Context, eventID uuid.UUID, _ int, _ string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failed = append(r.failed, eventID)
+	return nil
+}
+
+func (r *fakeRepository) DeleteDispatchedEvents(context.Context, time.Time) error {
+	return nil
+}
+
+type fakeHandler struct {
+	handle func(eventID uuid.UUID, payload []byte) error
+}
+
+func (h *fakeHandler) Topic() string {
+	return testTopic
+}
+
+func (h *fakeHandler) HandleOutboxEvent(_ context.Context, eventID uuid.UUID, payload []byte) error {
+	return h.handle(eventID, payload)
+}
+
+func TestDispatch(t *testing.T) {
+	errHandle := errors.New("handler failed")
+
+	tests := []struct {
+		name           string
+		topic          string
+		attempts       int
+		handleErr      error
+		wantHandled    bool
+		wantDispatched bool
+		wantRetried    bool
+		wantFailed     bool
+	}{
+		{name: "handled", topic: testTopic, attempts: 1, wantHandled: true, wantDispatched: true},
+		{name: "retried", topic: testTopic, attempts: 1, handleErr: errHandle, wantHandled: true, wantRetried: true},
+		{name: "retried before the last attempt", topic: testTopic, attempts: maxAttempts - 1, handleErr: errHandle, wantHandled: true, wantRetried: true},
+		{name: "failed after the last attempt", topic: testTopic, attempts: maxAttempts, handleErr: errHandle, wantHandled: true, wantFailed: true},
+		{name: "unknown topic", topic: "unknown", attempts: 1, wantFailed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepository{}
+			handled := false
+			d := NewDispatcher(repo, []contract.OutboxHandler{&fakeHandler{handle: func(uuid.UUID, []byte) error {
+				handled = true
+				return tt.handleErr
+			}}}, defaultlogger.GetLogger())
+
+			event := &Event{EventID: uuid.New(), Topic: tt.topic, Attempts: tt.attempts}
+			if err := d.dispatch(context.Background(), event); err != nil {
+				t.Fatalf("dispatch() error = %v", err)
+			}
+
+			if handled != tt.wantHandled {
+				t.Errorf("handled = %t, want %t", handled, tt.wantHandled)
+			}
+
+			if got := len(repo.dispatched) == 1; got != tt.wantDispatched {
+				t.Errorf("dispatched = %t, want %t", got, tt.wantDispatched)
+			}
+
+			if got := len(repo.retried) == 1; got != tt.wantRetried {
+				t.Errorf("retried = %t, want %t", got, tt.wantRetried)
+			}
+
+			if got := len(repo.failed) == 1; got != tt.wantFailed {
+				t.Errorf("failed = %t, want %t", got, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestRunDispatchesKeysConcurrently(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first, second, other := uuid.New(), uuid.New(), uuid.New()
+	repo := &fakeRepository{events: []*Event{
+		{EventID: first, Topic: testTopic, Key: "a"},
+		{EventID: second, Topic: testTopic, Key: "a"},
+		{EventID: other, Topic: testTopic, Key: "b"},
+	}}
+
+	// The first event of key a is held up until the event of key b is
+	// handled, which must not wait for it.
+	otherHandled := make(chan struct{})
+	handled := make(chan uuid.UUID, 3)
+	d := NewDispatcher(repo, []contract.OutboxHandler{&fakeHandler{handle: func(eventID uuid.UUID, _ []byte) error {
+		switch eventID {
+		case first:
+			select {
+			case <-otherHandled:
+			case <-time.After(time.Second):
+				t.Error("event of another key was not handled in the meantime")
+			}
+		case other:
+			close(otherHandled)
+		}
+
+		handled <- eventID
+		return nil
+	}}}, defaultlogger.GetLogger())
+
+	go d.Run(ctx)
+
+	got := make([]uuid.UUID, 0, 3)
+	for range 3 {
+		select {
+		case eventID := <-handled:
+			got = append(got, eventID)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("handled %v, want 3 events", got)
+		}
+	}
+
+	if want := []uuid.UUID{other, first, second}; got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("handled %v, want %v", got, want)
+	}
+}
+
+func TestRunDispatchesCommittedEventsOnly(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewGormRepository(db)
+	add := func(payload string, commit bool) {
+		uow := database.NewGormUnitOfWork(db, defaultlogger.GetLogger())
+
+		uowCtx, err := uow.Begin(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := repo.Add(uowCtx, testTopic, "key", []byte(payload)); err != nil {
+			t.Fatal(err)
+		}
+
+		if commit {
+			err = uow.Commit()
+		} else {
+			err = uow.Rollback()
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	add("rolled back", false)
+	select {
+	case <-repo.Added():
+		t.Fatal("dispatcher was woken up for a rolled back event")
+	default:
+	}
+
+	add("committed", true)
+	select {
+	case <-repo.Added():
+	default:
+		t.Fatal("dispatcher was not woken up for a committed event")
+	}
+
+	handled := make(chan string, 2)
+	d := NewDispatcher(repo, []contract.OutboxHandler{&fakeHandler{handle: func(_ uuid.UUID, payload []byte) error {
+		handled <- string(payload)
+		return nil
+	}}}, defaultlogger.GetLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case payload := <-handled:
+		if payload != "committed" {
+			t.Errorf("handled %q, want the committed event", payload)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("committed event was not handled")
+	}
+
+	cancel()
+	<-done
+
+	if len(handled) > 0 {
+		t.Errorf("handled %q, want nothing more", <-handled)
+	}
+
+	var events []database.OutboxEvent
+	if err := db.Find(&events).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 1 || events[0].Status != constant.OutboxEventStatusDispatched {
+		t.Errorf("outbox events = %+v, want the committed event dispatched", events)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for _, tc := range []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{5, 16 * time.Second},
+		{9, 256 * time.Second},
+		{10, maxBackoff},
+		{100, maxBackoff},
+	} {
+		if got := backoff(tc.attempts); got != tc.want {
+			t.Errorf("backoff(%d) = %v, want %v", tc.attempts, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db    *gorm.DB
+	added chan struct{}
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db:    db,
+		added: make(chan struct{}, 1),
+	}
+}
+
+// Add stores the event in the transaction of the context, if any.
+func (r *GormRepository) Add(ctx context.Context, topic string, key string, payload []byte) error {
+	now := time.Now()
+
+	if err := database.GetDBFromContext(ctx, r.db).Create(&database.OutboxEvent{
+		OutboxEventID: uuid.New(),
+		Topic:         topic,
+		OrderingKey:   key,
+		Payload:       payload,
+		Status:        constant.OutboxEventStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}).Error; err != nil {
+		return errors.WrapIf(err, "failed to create outbox event")
+	}
+
+	database.AfterCommit(ctx, func() {
+		select {
+		case r.added <- struct{}{}:
+		default:
+		}
+	})
+
+	return nil
+}
+
+func (r *GormRepository) Added() <-chan struct{} {
+	return r.added
+}
+
+func (r *GormRepository) ClaimNextEvent(ctx context.Context, now time.Time, leaseUntil time.Time) (*Event, error) {
+	for {
+		// An event waits for the events of its key created before it, until
+		// they are dispatched or given up on. Find rather than First, which
+		// would log every poll of an empty outbox as an error.
+		var events []database.OutboxEvent
+		if err := r.db.WithContext(ctx).
+			Where("status IN ? AND next_attempt_at <= ?",
+				[]string{constant.OutboxEventStatusPending, constant.OutboxEventStatusLeased}, now).
+			Where(`NOT EXISTS (
+				SELECT 1 FROM outbox_events AS earlier
+				WHERE earlier.ordering_key = outbox_events.ordering_key
+					AND earlier.status IN ?
+					AND (earlier.created_at < outbox_events.created_at
+						OR (earlier.created_at = outbox_events.created_at
+							AND earlier.outbox_event_id < outbox_events.outbox_event_id))
+			)`, []string{constant.OutboxEventStatusPending, constant.OutboxEventStatusLeased}).
+			Order("created_at ASC, outbox_event_id ASC").
+			Limit(1).
+			Find(&events).Error; err != nil {
+			return nil, errors.WrapIf(err, "failed to get next pending outbox event")
+		} else if len(events) == 0 {
+			return nil, nil
+		}
+
+		event := events[0]
+
+		// Every claim counts an attempt, so the check on the attempts makes
+		// it atomic between the dispatchers of all server instances.
+		result := r.db.WithContext(ctx).
+			Model(&database.OutboxEvent{}).
+			Where("outbox_event_id = ? AND status = ? AND attempts = ?",
+				event.OutboxEventID, event.Status, event.Attempts).
+			Updates(map[string]interface{}{
+				"status":          constant.OutboxEventStatusLeased,
+				"attempts":        event.Attempts + 1,
+				"next_attempt_at": leaseUntil,
+			})
+		if result.Error != nil {
+			return nil, errors.WrapIf(result.Error, "failed to claim outbox event")
+		}
+
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		return &Event{
+			EventID:  event.OutboxEventID,
+			Topic:    event.Topic,
+			Key:      event.OrderingKey,
+			Payload:  event.Payload,
+			Attempts: event.Attempts + 1,
+		}, nil
+	}
+}
+
+func (r *GormRepository) MarkDispatched(ctx context.Context, eventID uuid.UUID, attempts int, dispatchedAt time.Time) error {
+	return r.updateLeased(ctx, eventID, attempts, map[string]interface{}{
+		"status":        constant.OutboxEventStatusDispatched,
+		"last_error":    "",
+		"dispatched_at": dispatchedAt,
+	})
+}
+
+func (r *GormRepository) RetryEvent(ctx context.Context, eventID uuid.UUID, attempts int, reason string, retryAt time.Time) error {
+	return r.updateLeased(ctx, eventID, attempts, map[string]interface{}{
+		"status":          constant.OutboxEventStatusPending,
+		"last_error":      reason,
+		"next_attempt_at": retryAt,
+	})
+}
+
+func (r *GormRepository) FailEvent(ctx context.Context, eventID uuid.UUID, attempts int, reason string) error {
+	return r.updateLeased(ctx, eventID, attempts, map[string]interface{}{
+		"status":     constant.OutboxEventStatusFailed,
+		"last_error": reason,
+	})
+}
+
+// updateLeased updates the event only while it is still leased by the claim
+// that counted the given attempts.
+func (r *GormRepository) updateLeased(ctx context.Context, eventID uuid.UUID, attempts int, updates map[string]interface{}) error {
+	result := r.db.WithContext(ctx).
+		Model(&database.OutboxEvent{}).
+		Where("outbox_event_id = ? AND status = ? AND attempts = ?",
+			eventID, constant.OutboxEventStatusLeased, attempts).
+		Updates(updates)
+	if result.Error != nil {
+		return errors.WrapIf(result.Error, "failed to update outbox event")
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+func (r *GormRepository) DeleteDispatchedEvents(ctx context.Context, before time.Time) error {
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND dispatched_at < ?", constant.OutboxEventStatusDispatched, before).
+		Delete(&database.OutboxEvent{}).Error; err != nil {
+		return errors.WrapIf(err, "failed to delete dispatched outbox events")
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every connection to an in-memory database gets a database of its own.
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(&database.OutboxEvent{}); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func addTestEvent(t *testing.T, db *gorm.DB, key string, status string, createdAt time.Time) uuid.UUID {
+	t.Helper()
+
+	eventID := uuid.New()
+	if err := db.Create(&database.OutboxEvent{
+		OutboxEventID: eventID,
+		Topic:         testTopic,
+		OrderingKey:   key,
+		Status:        status,
+		NextAttemptAt: createdAt,
+		CreatedAt:     createdAt,
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return eventID
+}
+
+func TestClaimNextEventKeepsKeyOrder(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewGormRepository(db)
+
+	now := time.Now()
+	addTestEvent(t, db, "a", constant.OutboxEventStatusDispatched, now.Add(-4*time.Minute))
+	first := addTestEvent(t, db, "a", constant.OutboxEventStatusPending, now.Add(-3*time.Minute))
+	second := addTestEvent(t, db, "a", constant.OutboxEventStatusPending, now.Add(-2*time.Minute))
+	other := addTestEvent(t, db, "b", constant.OutboxEventStatusPending, now.Add(-time.Minute))
+
+	claim := func() *Event {
+		t.Helper()
+
+		event, err := repo.ClaimNextEvent(ctx, now, now.Add(leaseDuration))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return event
+	}
+
+	if event := claim(); event == nil || event.EventID != first {
+		t.Fatalf("claimed %+v, want the first pending event of key a", event)
+	}
+
+	// The second event of key a waits while the first is leased.
+	if event := claim(); event == nil || event.EventID != other {
+		t.Fatalf("claimed %+v, want the event of key b", event)
+	}
+
+	if event := claim(); event != nil {
+		t.Fatalf("claimed %+v, want nothing while the first event of key a is leased", event)
+	}
+
+	// It also waits while the first is pending a retry.
+	if err := repo.RetryEvent(ctx, first, 1, "failed", now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	if event := claim(); event != nil {
+		t.Fatalf("claimed %+v, want nothing while the first event of key a awaits its retry", event)
+	}
+
+	if err := db.Model(&database.OutboxEvent{}).
+		Where("outbox_event_id = ?", first).
+		Update("status", constant.OutboxEventStatusFailed).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if event := claim(); event == nil || event.EventID != second {
+		t.Fatalf("claimed %+v, want the second event of key a once the first is given up on", event)
+	}
+}
+
+func TestClaimNextEventReclaimsExpiredLeases(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewGormRepository(db)
+
+	now := time.Now()
+	eventID := addTestEvent(t, db, "a", constant.OutboxEventStatusPending, now)
+
+	event, err := repo.ClaimNextEvent(ctx, now, now.Add(leaseDuration))
+	if err != nil || event == nil || event.Attempts != 1 {
+		t.Fatalf("ClaimNextEvent() = %+v, %v, want the event on its first attempt", event, err)
+	}
+
+	expired := now.Add(leaseDuration)
+	reclaimed, err := repo.ClaimNextEvent(ctx, expired, expired.Add(leaseDuration))
+	if err != nil || reclaimed == nil || reclaimed.EventID != eventID || reclaimed.Attempts != 2 {
+		t.Fatalf("ClaimNextEvent() = %+v, %v, want the event on its second attempt", reclaimed, err)
+	}
+
+	// The first claim no longer holds the event.
+	if err := repo.MarkDispatched(ctx, eventID, 1, now); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("MarkDispatched() of the expired claim error = %v, want ErrLeaseLost", err)
+	}
+
+	if err := repo.RetryEvent(ctx, eventID, 1, "failed", now); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("RetryEvent() of the expired claim error = %v, want ErrLeaseLost", err)
+	}
+
+	if err := repo.FailEvent(ctx, eventID, 1, "failed"); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("FailEvent() of the expired claim error = %v, want ErrLeaseLost", err)
+	}
+
+	if err := repo.MarkDispatched(ctx, eventID, 2, now); err != nil {
+		t.Fatalf("MarkDispatched() of the current claim error = %v", err)
+	}
+
+	if err := repo.FailEvent(ctx, eventID, 2, "failed"); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("FailEvent() of a dispatched event error = %v, want ErrLeaseLost", err)
+	}
+}
@@ -0,0 +1,566 @@
+// Package polygon reads problem packages exported from Codeforces Polygon: a
+// zip archive with a problem.xml descriptor next to the statements, tests and
+// programs it references.
+//
+// Only the format is handled here. Deciding which parts can be represented on
+// this platform is up to the caller; parts of problem.xml that the parser
+// itself ignores are listed in Package.Issues.
+package polygon
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"emperror.dev/errors"
+)
+
+const (
+	descriptorName = "problem.xml"
+	mainTestset    = "tests"
+
+	// maxFileSize caps every file that is read into memory. Tests are
+	// streamed through Open instead.
+	maxFileSize = 4 << 20
+)
+
+var ErrInvalidPackage = errors.New("invalid polygon package")
+
+// Issue describes a part of a package that is not imported.
+type Issue struct {
+	Part   string
+	Reason string
+}
+
+type Sample struct {
+	Input  string
+	Output string
+}
+
+// Statement holds the sections of a statement in one language, written in
+// Polygon's TeX dialect.
+type Statement struct {
+	Language    string // Polygon language name, e.g. "english"
+	Name        string
+	Legend      string
+	Input       string
+	Output      string
+	Interaction string
+	Notes       string
+	Tutorial    string
+	Samples     []Sample
+}
+
+// Source is a program of the package. Type is Polygon's source type, such as
+// "cpp.g++17" or "python.3".
+type Source struct {
+	Path    string
+	Type    string
+	Content string
+}
+
+type Checker struct {
+	Name   string // Standard checkers are named like "std::wcmp.cpp"
+	Type   string // "testlib" for testlib-based checkers
+	Source *Source
+}
+
+type Solution struct {
+	Tag string // e.g. "main", "accepted" or "wrong-answer"
+	Source
+}
+
+// Test is a test of the main testset. Input and Answer are empty when the file
+// is not part of the package, as in packages exported without generated tests.
+type Test struct {
+	Index  int // 1-based
+	Sample bool
+	Method string // "manual" or "generated"
+	Cmd    string // Generator invocation of generated tests
+	Input  string
+	Answer string
+}
+
+type Package struct {
+	ShortName        string
+	Statements       []Statement
+	InputFile        string // Empty means standard input
+	OutputFile       string // Empty means standard output
+	TimeLimitMs      int
+	MemoryLimitBytes int64
+	Tests            []Test
+	Checker          *Checker
+	Interactor       *Source
+	Validators       []Source
+	Solutions        []Solution
+	Executables      []Source
+	Issues           []Issue
+
+	files map[string]*zip.File
+}
+
+type sourceXML struct {
+	Path string `xml:"path,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type testsetXML struct {
+	Name              string `xml:"name,attr"`
+	TimeLimit         int    `xml:"time-limit"`
+	MemoryLimit       int64  `xml:"memory-limit"`
+	InputPathPattern  string `xml:"input-path-pattern"`
+	AnswerPathPattern string `xml:"answer-path-pattern"`
+	Tests             []struct {
+		Method string `xml:"method,attr"`
+		Cmd    string `xml:"cmd,attr"`
+		Sample bool   `xml:"sample,attr"`
+		Points string `xml:"points,attr"`
+	} `xml:"tests>test"`
+	Groups []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"groups>group"`
+}
+
+type problemXML struct {
+	XMLName   xml.Name `xml:"problem"`
+	ShortName string   `xml:"short-name,attr"`
+	Names     []struct {
+		Language string `xml:"language,attr"`
+		Value    string `xml:"value,attr"`
+	} `xml:"names>name"`
+	Judging struct {
+		InputFile  string       `xml:"input-file,attr"`
+		OutputFile string       `xml:"output-file,attr"`
+		Testsets   []testsetXML `xml:"testset"`
+	} `xml:"judging"`
+	Resources []struct {
+		Path string `xml:"path,attr"`
+	} `xml:"files>resources>file"`
+	Executables []struct {
+		Source sourceXML `xml:"source"`
+	} `xml:"files>executables>executable"`
+	Checker *struct {
+		Name   string     `xml:"name,attr"`
+		Type   string     `xml:"type,attr"`
+		Source *sourceXML `xml:"source"`
+	} `xml:"assets>checker"`
+	Interactor *struct {
+		Source *sourceXML `xml:"source"`
+	} `xml:"assets>interactor"`
+	Validators []struct {
+		Source sourceXML `xml:"source"`
+	} `xml:"assets>validators>validator"`
+	Solutions []struct {
+		Tag    string    `xml:"tag,attr"`
+		Source sourceXML `xml:"source"`
+	} `xml:"assets>solutions>solution"`
+	Tags []struct {
+		Value string `xml:"value,attr"`
+	} `xml:"tags>tag"`
+}
+
+type statementPropertiesJSON struct {
+	Name        string `json:"name"`
+	Legend      string `json:"legend"`
+	Input       string `json:"input"`
+	Output      string `json:"output"`
+	Interaction string `json:"interaction"`
+	Notes       string `json:"notes"`
+	Tutorial    string `json:"tutorial"`
+	SampleTests []struct {
+		Input  string `json:"input"`
+		Output string `json:"output"`
+	} `json:"sampleTests"`
+}
+
+// ignoredResources are resource files that only matter inside Polygon. The
+// judge provides its own testlib.h.
+var ignoredResources = map[string]struct{}{
+	"testlib.h":      {},
+	"olymp.sty":      {},
+	"problem.tex":    {},
+	"statements.ftl": {},
+	"tutorial.tex":   {},
+}
+
+// Parse reads the descriptor of the package and every file it references,
+// except for tests. problem.xml may be at the root of the archive or inside a
+// single top-level directory.
+func Parse(r io.ReaderAt, size int64) (*Package, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidPackage, "corrupt zip archive")
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, file := range zr.File {
+		if file.Mode().IsRegular() {
+			files[path.Clean(file.Name)] = file
+		}
+	}
+
+	prefix, err := findRoot(files)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Package{files: make(map[string]*zip.File, len(files))}
+	for name, file := range files {
+		if rel, ok := strings.CutPrefix(name, prefix); ok {
+			p.files[rel] = file
+		}
+	}
+
+	descriptor, err := p.ReadFile(descriptorName)
+	if err != nil {
+		return nil, err
+	}
+
+	var problem problemXML
+	if err := xml.Unmarshal(descriptor, &problem); err != nil {
+		return nil, errors.Wrapf(ErrInvalidPackage, "malformed %s: %s", descriptorName, err.Error())
+	}
+
+	p.ShortName = problem.ShortName
+	p.InputFile = standardIOFile(problem.Judging.InputFile, "stdin")
+	p.OutputFile = standardIOFile(problem.Judging.OutputFile, "stdout")
+
+	if err := p.parseStatements(&problem); err != nil {
+		return nil, err
+	}
+
+	if err := p.parseTests(&problem); err != nil {
+		return nil, err
+	}
+
+	if err := p.parsePrograms(&problem); err != nil {
+		return nil, err
+	}
+
+	for _, resource := range problem.Resources {
+		if _, ok := ignoredResources[path.Base(resource.Path)]; !ok {
+			p.addIssue(resource.Path, "resource files are not supported")
+		}
+	}
+
+	if len(problem.Tags) > 0 {
+		p.addIssue("tags", "problem tags are not supported")
+	}
+
+	return p, nil
+}
+
+// Open streams a file of the package, such as the input of a test.
+func (p *Package) Open(name string) (io.ReadCloser, error) {
+	file, ok := p.files[path.Clean(name)]
+	if !ok {
+		return nil, errors.Wrapf(ErrInvalidPackage, "missing file %s", name)
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidPackage, "cannot read %s", name)
+	}
+
+	return rc, nil
+}
+
+// ReadFile reads a whole file of the package into memory.
+func (p *Package) ReadFile(name string) ([]byte, error) {
+	rc, err := p.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, maxFileSize+1))
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidPackage, "cannot read %s", name)
+	} else if len(content) > maxFileSize {
+		return nil, errors.Wrapf(ErrInvalidPackage, "%s is too large", name)
+	}
+
+	return content, nil
+}
+
+func (p *Package) has(name string) bool {
+	_, ok := p.files[path.Clean(name)]
+	return ok
+}
+
+func (p *Package) addIssue(part string, reason string) {
+	p.Issues = append(p.Issues, Issue{Part: part, Reason: reason})
+}
+
+// parseStatements reads the statement sections of every language the problem
+// is named in, preferring the problem-properties.json that Polygon puts next
+// to the TeX statement over the raw statement-sections directory.
+func (p *Package) parseStatements(problem *problemXML) error {
+	for _, name := range problem.Names {
+		statement := Statement{Language: name.Language, Name: name.Value}
+
+		propertiesPath := path.Join("statements", name.Language, "problem-properties.json")
+		sectionsDir := path.Join("statement-sections", name.Language)
+
+		switch {
+		case p.has(propertiesPath):
+			content, err := p.ReadFile(propertiesPath)
+			if err != nil {
+				return err
+			}
+
+			var properties statementPropertiesJSON
+			if err := json.Unmarshal(content, &properties); err != nil {
+				return errors.Wrapf(ErrInvalidPackage, "malformed %s", propertiesPath)
+			}
+
+			if properties.Name != "" {
+				statement.Name = properties.Name
+			}
+
+			statement.Legend = properties.Legend
+			statement.Input = properties.Input
+			statement.Output = properties.Output
+			statement.Interaction = properties.Interaction
+			statement.Notes = properties.Notes
+			statement.Tutorial = properties.Tutorial
+
+			for _, sample := range properties.SampleTests {
+				statement.Samples = append(statement.Samples, Sample{Input: sample.Input, Output: sample.Output})
+			}
+		case p.has(path.Join(sectionsDir, "legend.tex")):
+			sections := map[string]*string{
+				"legend.tex":      &statement.Legend,
+				"input.tex":       &statement.Input,
+				"output.tex":      &statement.Output,
+				"interaction.tex": &statement.Interaction,
+				"notes.tex":       &statement.Notes,
+				"tutorial.tex":    &statement.Tutorial,
+			}
+
+			for file, section := range sections {
+				if !p.has(path.Join(sectionsDir, file)) {
+					continue
+				}
+
+				content, err := p.ReadFile(path.Join(sectionsDir, file))
+				if err != nil {
+					return err
+				}
+
+				*section = strings.TrimSpace(string(content))
+			}
+
+			samples, err := p.readSectionSamples(sectionsDir)
+			if err != nil {
+				return err
+			}
+			statement.Samples = samples
+		default:
+			p.addIssue(
+				path.Join("statements", name.Language),
+				"only rendered files of the statement are included, not its sections",
+			)
+		}
+
+		p.Statements = append(p.Statements, statement)
+	}
+
+	return nil
+}
+
+// readSectionSamples collects the example.NN and example.NN.a files of a
+// statement-sections directory in order.
+func (p *Package) readSectionSamples(dir string) ([]Sample, error) {
+	var samples []Sample
+	for i := 1; ; i++ {
+		inputPath := path.Join(dir, fmt.Sprintf("example.%02d", i))
+		if !p.has(inputPath) {
+			return samples, nil
+		}
+
+		input, err := p.ReadFile(inputPath)
+		if err != nil {
+			return nil, err
+		}
+
+		output, err := p.ReadFile(inputPath + ".a")
+		if err != nil {
+			return nil, err
+		}
+
+		samples = append(samples, Sample{Input: string(input), Output: string(output)})
+	}
+}
+
+// parseTests lists the tests of the main testset. Other testsets, such as
+// pretests, and scoring information are reported as issues.
+func (p *Package) parseTests(problem *problemXML) error {
+	var testset *testsetXML
+	for i := range problem.Judging.Testsets {
+		if problem.Judging.Testsets[i].Name == mainTestset {
+			testset = &problem.Judging.Testsets[i]
+		}
+	}
+
+	for _, other := range problem.Judging.Testsets {
+		if other.Name != mainTestset {
+			p.addIssue("testset "+other.Name, "only the main testset is imported")
+		}
+	}
+
+	if testset == nil {
+		return errors.Wrapf(ErrInvalidPackage, "%s has no %q testset", descriptorName, mainTestset)
+	}
+
+	p.TimeLimitMs = testset.TimeLimit
+	p.MemoryLimitBytes = testset.MemoryLimit
+
+	if len(testset.Groups) > 0 {
+		p.addIssue("testset tests", "test groups are not supported")
+	}
+
+	hasPoints := false
+	for i, test := range testset.Tests {
+		inputPath, err := testPath(testset.InputPathPattern, i+1)
+		if err != nil {
+			return err
+		}
+
+		answerPath, err := testPath(testset.AnswerPathPattern, i+1)
+		if err != nil {
+			return err
+		}
+
+		if !p.has(inputPath) {
+			inputPath = ""
+		}
+
+		if !p.has(answerPath) {
+			answerPath = ""
+		}
+
+		hasPoints = hasPoints || test.Points != ""
+		p.Tests = append(p.Tests, Test{
+			Index:  i + 1,
+			Sample: test.Sample,
+			Method: test.Method,
+			Cmd:    strings.TrimSpace(test.Cmd),
+			Input:  inputPath,
+			Answer: answerPath,
+		})
+	}
+
+	if hasPoints {
+		p.addIssue("testset tests", "points per test are not supported")
+	}
+
+	return nil
+}
+
+// parsePrograms reads the sources of the checker, interactor, validators,
+// solutions and executables, the latter being generators in practice.
+func (p *Package) parsePrograms(problem *problemXML) error {
+	if problem.Checker != nil {
+		p.Checker = &Checker{Name: problem.Checker.Name, Type: problem.Checker.Type}
+		if problem.Checker.Source != nil {
+			source, err := p.readSource(*problem.Checker.Source)
+			if err != nil {
+				return err
+			}
+			p.Checker.Source = &source
+		}
+	}
+
+	if problem.Interactor != nil && problem.Interactor.Source != nil {
+		source, err := p.readSource(*problem.Interactor.Source)
+		if err != nil {
+			return err
+		}
+		p.Interactor = &source
+	}
+
+	for _, validator := range problem.Validators {
+		source, err := p.readSource(validator.Source)
+		if err != nil {
+			return err
+		}
+		p.Validators = append(p.Validators, source)
+	}
+
+	for _, solution := range problem.Solutions {
+		source, err := p.readSource(solution.Source)
+		if err != nil {
+			return err
+		}
+		p.Solutions = append(p.Solutions, Solution{Tag: solution.Tag, Source: source})
+	}
+
+	for _, executable := range problem.Executables {
+		source, err := p.readSource(executable.Source)
+		if err != nil {
+			return err
+		}
+		p.Executables = append(p.Executables, source)
+	}
+
+	return nil
+}
+
+func (p *Package) readSource(source sourceXML) (Source, error) {
+	content, err := p.ReadFile(source.Path)
+	if err != nil {
+		return Source{}, err
+	}
+
+	return Source{
+		Path:    source.Path,
+		Type:    source.Type,
+		Content: string(content),
+	}, nil
+}
+
+// findRoot returns the directory prefix of problem.xml, which is either empty
+// or the single top-level directory of the archive.
+func findRoot(files map[string]*zip.File) (string, error) {
+	if _, ok := files[descriptorName]; ok {
+		return "", nil
+	}
+
+	var roots []string
+	for name := range files {
+		if dir, file := path.Split(name); file == descriptorName && strings.Count(dir, "/") == 1 {
+			roots = append(roots, dir)
+		}
+	}
+
+	if len(roots) != 1 {
+		return "", errors.Wrapf(ErrInvalidPackage, "expected exactly one %s, found %d", descriptorName, len(roots))
+	}
+
+	return roots[0], nil
+}
+
+// testPath expands a printf-style path pattern such as "tests/%02d".
+func testPath(pattern string, index int) (string, error) {
+	name := fmt.Sprintf(pattern, index)
+	if pattern == "" || strings.Contains(name, "%!") || strings.Count(pattern, "%") != 1 {
+		return "", errors.Wrapf(ErrInvalidPackage, "unsupported test path pattern %q", pattern)
+	}
+
+	return path.Clean(name), nil
+}
+
+// standardIOFile maps Polygon's names for the standard streams to an empty
+// file name.
+func standardIOFile(name string, stream string) string {
+	if name == stream {
+		return ""
+	}
+
+	return name
+}
@@ -0,0 +1,182 @@
+package polygon
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"emperror.dev/errors"
+)
+
+const testDescriptor = `<?xml version="1.0" encoding="utf-8" standalone="no"?>
+<problem revision="3" short-name="a-plus-b">
+    <names>
+        <name language="english" value="A+B"/>
+        <name language="russian" value="A+B"/>
+    </names>
+    <judging input-file="" output-file="">
+        <testset name="tests">
+            <time-limit>2000</time-limit>
+            <memory-limit>268435456</memory-limit>
+            <test-count>3</test-count>
+            <input-path-pattern>tests/%02d</input-path-pattern>
+            <answer-path-pattern>tests/%02d.a</answer-path-pattern>
+            <tests>
+                <test method="manual" sample="true"/>
+                <test cmd="gen 1" method="generated"/>
+                <test cmd="gen 2" method="generated" points="5"/>
+            </tests>
+        </testset>
+        <testset name="pretests">
+            <input-path-pattern>pretests/%02d</input-path-pattern>
+            <answer-path-pattern>pretests/%02d.a</answer-path-pattern>
+        </testset>
+    </judging>
+    <files>
+        <resources>
+            <file path="files/testlib.h" type="h.g++"/>
+            <file path="files/extra.h" type="h.g++"/>
+        </resources>
+        <executables>
+            <executable><source path="files/gen.cpp" type="cpp.g++17"/></executable>
+        </executables>
+    </files>
+    <assets>
+        <checker name="std::ncmp.cpp" type="testlib">
+            <source path="files/check.cpp" type="cpp.g++17"/>
+        </checker>
+        <validators>
+            <validator><source path="files/val.cpp" type="cpp.g++17"/></validator>
+        </validators>
+        <solutions>
+            <solution tag="main"><source path="solutions/main.cpp" type="cpp.g++20"/></solution>
+            <solution tag="wrong-answer"><source path="solutions/wa.py" type="python.3"/></solution>
+        </solutions>
+    </assets>
+</problem>`
+
+func writeZip(t *testing.T, files map[string]string) *bytes.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestParse(t *testing.T) {
+	r := writeZip(t, map[string]string{
+		"a-plus-b/problem.xml":        testDescriptor,
+		"a-plus-b/files/gen.cpp":      "gen",
+		"a-plus-b/files/check.cpp":    "check",
+		"a-plus-b/files/val.cpp":      "val",
+		"a-plus-b/solutions/main.cpp": "main",
+		"a-plus-b/solutions/wa.py":    "wa",
+		"a-plus-b/tests/01":           "1 2\n",
+		"a-plus-b/tests/01.a":         "3\n",
+		"a-plus-b/statements/english/problem-properties.json": `{
+			"name": "A plus B", "legend": "Add.", "input": "Two integers.", "output": "Their sum.",
+			"sampleTests": [{"input": "1 2\n", "output": "3\n"}]
+		}`,
+	})
+
+	pkg, err := Parse(r, r.Size())
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if pkg.ShortName != "a-plus-b" || pkg.TimeLimitMs != 2000 || pkg.MemoryLimitBytes != 256<<20 {
+		t.Errorf("Parse() = %+v", pkg)
+	}
+
+	if len(pkg.Statements) != 2 {
+		t.Fatalf("len(Statements) = %d, want 2", len(pkg.Statements))
+	}
+
+	english := pkg.Statements[0]
+	if english.Name != "A plus B" || english.Legend != "Add." || len(english.Samples) != 1 {
+		t.Errorf("Statements[0] = %+v", english)
+	}
+
+	if len(pkg.Tests) != 3 {
+		t.Fatalf("len(Tests) = %d, want 3", len(pkg.Tests))
+	}
+
+	if test := pkg.Tests[0]; !test.Sample || test.Input != "tests/01" || test.Answer != "tests/01.a" {
+		t.Errorf("Tests[0] = %+v", test)
+	}
+
+	if test := pkg.Tests[1]; test.Cmd != "gen 1" || test.Input != "" || test.Answer != "" {
+		t.Errorf("Tests[1] = %+v", test)
+	}
+
+	if pkg.Checker == nil || pkg.Checker.Name != "std::ncmp.cpp" || pkg.Checker.Source.Content != "check" {
+		t.Errorf("Checker = %+v", pkg.Checker)
+	}
+
+	if len(pkg.Solutions) != 2 || pkg.Solutions[0].Tag != "main" || pkg.Solutions[1].Content != "wa" {
+		t.Errorf("Solutions = %+v", pkg.Solutions)
+	}
+
+	if len(pkg.Validators) != 1 || len(pkg.Executables) != 1 {
+		t.Errorf("Validators = %+v, Executables = %+v", pkg.Validators, pkg.Executables)
+	}
+
+	wantIssues := map[string]bool{
+		"statements/russian": true,
+		"testset pretests":   true,
+		"testset tests":      true,
+		"files/extra.h":      true,
+	}
+	for _, issue := range pkg.Issues {
+		if !wantIssues[issue.Part] {
+			t.Errorf("unexpected issue %+v", issue)
+		}
+		delete(wantIssues, issue.Part)
+	}
+
+	for part := range wantIssues {
+		t.Errorf("missing issue for %s", part)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{name: "no descriptor", files: map[string]string{"statement.tex": ""}},
+		{name: "malformed descriptor", files: map[string]string{"problem.xml": "<problem"}},
+		{name: "no main testset", files: map[string]string{"problem.xml": "<problem><judging/></problem>"}},
+		{name: "missing source", files: map[string]string{"problem.xml": `<problem><judging><testset name="tests"/></judging>
+			<assets><checker><source path="files/check.cpp"/></checker></assets></problem>`}},
+		{name: "ambiguous root", files: map[string]string{"a/problem.xml": "", "b/problem.xml": ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := writeZip(t, tt.files)
+			if _, err := Parse(r, r.Size()); !errors.Is(err, ErrInvalidPackage) {
+				t.Errorf("Parse() error = %v, want ErrInvalidPackage", err)
+			}
+		})
+	}
+
+	if _, err := Parse(bytes.NewReader([]byte("not a zip")), 9); !errors.Is(err, ErrInvalidPackage) {
+		t.Errorf("Parse() error = %v, want ErrInvalidPackage", err)
+	}
+}
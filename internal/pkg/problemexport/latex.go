@@ -0,0 +1,128 @@
+package problemexport
+
+import (
+	"regexp"
+	"strings"
+)
+
+// texReplacer escapes the characters LaTeX treats specially in text.
+var texReplacer = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`$`, `\$`,
+	`&`, `\&`,
+	`#`, `\#`,
+	`%`, `\%`,
+	`_`, `\_`,
+	`^`, `\textasciicircum{}`,
+	`~`, `\textasciitilde{}`,
+	`<`, `\textless{}`,
+	`>`, `\textgreater{}`,
+)
+
+// unsafeMathCommands are control sequences that read or write files, run
+// commands or redefine others, which a statement could use to attack whoever
+// renders the package.
+var unsafeMathCommands = map[string]struct{}{
+	"input": {}, "include": {}, "includeonly": {}, "InputIfFileExists": {}, "verbatiminput": {},
+	"lstinputlisting": {}, "openin": {}, "openout": {}, "read": {}, "readline": {}, "write": {},
+	"immediate": {}, "closein": {}, "closeout": {}, "special": {}, "directlua": {}, "ShellEscape": {},
+	"def": {}, "edef": {}, "gdef": {}, "xdef": {}, "let": {}, "futurelet": {}, "newcommand": {},
+	"renewcommand": {}, "providecommand": {}, "DeclareRobustCommand": {}, "newenvironment": {},
+	"renewenvironment": {}, "catcode": {}, "csname": {}, "endcsname": {}, "expandafter": {},
+	"usepackage": {}, "RequirePackage": {}, "documentclass": {}, "makeatletter": {}, "jobname": {},
+}
+
+var controlSequencePattern = regexp.MustCompile(`\\([A-Za-z@]+)`)
+
+// texEscape turns text into LaTeX that prints it as it is.
+func texEscape(text string) string {
+	return texReplacer.Replace(text)
+}
+
+// texStatement escapes statement text but keeps the math between $ or $$
+// delimiters, which statements use like LaTeX, as long as it is safe. Escaped
+// dollars and unsafe or unterminated math are printed as text.
+func texStatement(text string) string {
+	var b strings.Builder
+
+	for text != "" {
+		i := strings.IndexAny(text, `\$`)
+		if i < 0 {
+			b.WriteString(texEscape(text))
+			break
+		}
+
+		b.WriteString(texEscape(text[:i]))
+		text = text[i:]
+
+		if strings.HasPrefix(text, `\$`) {
+			b.WriteString(`\$`)
+			text = text[2:]
+			continue
+		} else if text[0] == '\\' {
+			b.WriteString(texEscape(`\`))
+			text = text[1:]
+			continue
+		}
+
+		delimiter := "$"
+		if strings.HasPrefix(text, "$$") {
+			delimiter = "$$"
+		}
+
+		end := strings.Index(text[len(delimiter):], delimiter)
+		if end < 0 {
+			b.WriteString(texEscape(delimiter))
+			text = text[len(delimiter):]
+			continue
+		}
+
+		math := text[len(delimiter) : len(delimiter)+end]
+		if safeMath(math) {
+			b.WriteString(delimiter + math + delimiter)
+		} else {
+			b.WriteString(texEscape(delimiter + math + delimiter))
+		}
+
+		text = text[len(delimiter)+end+len(delimiter):]
+	}
+
+	return b.String()
+}
+
+// safeMath reports whether math can be passed to LaTeX as it is: its braces
+// are balanced, and it neither uses unsafe commands nor comments, blank lines
+// or ^^ notation, which could hide them or end the formula early.
+func safeMath(math string) bool {
+	if strings.ContainsAny(math, "%") || strings.Contains(math, "^^") || strings.Contains(math, "\n\n") {
+		return false
+	}
+
+	depth := 0
+	for i := 0; i < len(math); i++ {
+		switch math[i] {
+		case '\\':
+			i++ // Escaped braces do not count
+		case '{':
+			depth++
+		case '}':
+			if depth--; depth < 0 {
+				return false
+			}
+		}
+	}
+
+	if depth != 0 {
+		return false
+	}
+
+	for _, match := range controlSequencePattern.FindAllStringSubmatch(math, -1) {
+		if _, ok := unsafeMathCommands[match[1]]; ok {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,36 @@
+package problemexport
+
+import "testing"
+
+func TestTexStatement(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "plain text", text: "Print a & b, 100% sure.", want: `Print a \& b, 100\% sure.`},
+		{name: "inline math", text: "Given $a_i \\le 10^9$.", want: `Given $a_i \le 10^9$.`},
+		{name: "display math", text: "$$\\sum_{i=1}^n a_i$$", want: `$$\sum_{i=1}^n a_i$$`},
+		{name: "escaped dollar", text: "Costs \\$5 and $x$.", want: `Costs \$5 and $x$.`},
+		{name: "unterminated math", text: "Costs $5.", want: `Costs \$5.`},
+		{name: "backslash in text", text: "\\input{/etc/passwd}", want: `\textbackslash{}input\{/etc/passwd\}`},
+		{name: "unsafe command", text: "$\\input{/etc/passwd}$", want: `\$\textbackslash{}input\{/etc/passwd\}\$`},
+		{name: "comment in math", text: "$x % y$", want: `\$x \% y\$`},
+		{name: "unbalanced braces", text: "$}\\def$", want: `\$\}\textbackslash{}def\$`},
+		{name: "caret notation", text: "$^^5c$", want: `\$\textasciicircum{}\textasciicircum{}5c\$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := texStatement(tt.text); got != tt.want {
+				t.Errorf("texStatement(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTexEscape(t *testing.T) {
+	if got, want := texEscape(`A_B {$x$} \ ~`), `A\_B \{\$x\$\} \textbackslash{} \textasciitilde{}`; got != want {
+		t.Errorf("texEscape() = %q, want %q", got, want)
+	}
+}
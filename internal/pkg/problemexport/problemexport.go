@@ -0,0 +1,261 @@
+// Package problemexport writes problems as ICPC problem packages, the format
+// DOMjudge and Kattis import, in their legacy flavour:
+//
+//	problem.yaml
+//	problem_statement/problem.<lang>.tex
+//	data/sample/*.in, *.ans
+//	data/secret/*.in, *.ans
+//	output_validators/, input_format_validators/
+//	submissions/<expected verdict>/
+//
+// Custom checkers, interactors and validators are testlib programs, which are
+// wrapped with build and run scripts that adapt them to the validator
+// interface of the format.
+package problemexport
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+type Format string
+
+const (
+	// FormatDOMjudge adds the .timelimit file DOMjudge reads, and the CLICS
+	// contest.yaml and problems.yaml to contest exports.
+	FormatDOMjudge Format = "domjudge"
+	// FormatKattis leaves the time limit to be derived from the accepted
+	// submissions, as Kattis does.
+	FormatKattis Format = "kattis"
+)
+
+var (
+	ErrUnknownFormat      = errors.New("unknown export format")
+	ErrUnsupportedProblem = errors.New("problem cannot be exported")
+)
+
+// UnsupportedError reports why a problem cannot be represented in a package.
+type UnsupportedError struct {
+	ProblemID uuid.UUID
+	Reason    string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("problem %s cannot be exported: %s", e.ProblemID, e.Reason)
+}
+
+func (e *UnsupportedError) Is(target error) bool {
+	return target == ErrUnsupportedProblem
+}
+
+type Statement struct {
+	Language     string
+	Title        string
+	Background   string
+	Statement    string
+	InputFormat  string
+	OutputFormat string
+	Note         string
+}
+
+type Example struct {
+	Input  string
+	Output string
+}
+
+type Testcase struct {
+	Name       string
+	InputHash  string
+	OutputHash string
+}
+
+type Checker struct {
+	Type    string
+	Epsilon float64
+	Program *contract.Program // Only for custom checkers
+}
+
+type Solution struct {
+	Name            string
+	ExpectedVerdict constant.Verdict
+	contract.Program
+}
+
+type Problem struct {
+	ProblemID     uuid.UUID
+	ShortName     string
+	Statements    []Statement
+	Examples      []Example
+	Testcases     []Testcase
+	TimeLimitMs   uint
+	MemoryLimitMb uint
+	IsInteractive bool
+	InputFile     string
+	OutputFile    string
+	Checker       Checker
+	Interactor    *contract.Program
+	Validator     *contract.Program
+	Solutions     []Solution
+}
+
+// ContestProblem is a problem of a contest together with its label, such as
+// "A".
+type ContestProblem struct {
+	Label string
+	Problem
+}
+
+type Contest struct {
+	ContestID uuid.UUID
+	Title     string
+	Problems  []ContestProblem
+}
+
+func ParseFormat(format string) (Format, error) {
+	switch Format(format) {
+	case FormatDOMjudge, FormatKattis:
+		return Format(format), nil
+	default:
+		return "", errors.WithStack(ErrUnknownFormat)
+	}
+}
+
+// Check reports problems that cannot be expressed in a package, so that they
+// can be rejected before anything is written.
+func Check(problem Problem) error {
+	if problem.InputFile != "" || problem.OutputFile != "" {
+		return errors.WithStack(&UnsupportedError{
+			ProblemID: problem.ProblemID,
+			Reason:    "file input/output is not supported by the package format",
+		})
+	}
+
+	if problem.IsInteractive && problem.Interactor == nil {
+		return errors.WithStack(&UnsupportedError{ProblemID: problem.ProblemID, Reason: "the interactor is missing"})
+	}
+
+	if problem.IsInteractive && problem.Checker.Type == constant.CheckerTypeCustom {
+		return errors.WithStack(&UnsupportedError{
+			ProblemID: problem.ProblemID,
+			Reason:    "interactive problems cannot have a separate checker in the package format",
+		})
+	}
+
+	return nil
+}
+
+// FromGormProblemVersion maps a problem version with its details, examples,
+// solutions and testcases preloaded.
+func FromGormProblemVersion(version database.ProblemVersion) Problem {
+	problem := Problem{
+		ProblemID:     version.ProblemID,
+		Statements:    make([]Statement, len(version.Details)),
+		Examples:      make([]Example, len(version.Examples)),
+		Testcases:     make([]Testcase, len(version.Testcases)),
+		TimeLimitMs:   version.TimeLimitMs,
+		MemoryLimitMb: version.MemoryLimitMb,
+		IsInteractive: version.IsInteractive,
+		InputFile:     version.InputFile,
+		OutputFile:    version.OutputFile,
+		Checker: Checker{
+			Type:    version.CheckerType,
+			Epsilon: version.CheckerEpsilon,
+		},
+		Solutions: make([]Solution, len(version.Solutions)),
+	}
+
+	for i, detail := range version.Details {
+		problem.Statements[i] = Statement{
+			Language:     detail.Language,
+			Title:        detail.Title,
+			Background:   detail.Background,
+			Statement:    detail.Statement,
+			InputFormat:  detail.InputFormat,
+			OutputFormat: detail.OutputFormat,
+			Note:         detail.Note,
+		}
+	}
+
+	for i, example := range version.Examples {
+		problem.Examples[i] = Example{Input: example.Input, Output: example.Output}
+	}
+
+	for i, testcase := range version.Testcases {
+		problem.Testcases[i] = Testcase{
+			Name:       testcase.Name,
+			InputHash:  testcase.InputHash,
+			OutputHash: testcase.OutputHash,
+		}
+	}
+
+	if version.CheckerType == constant.CheckerTypeCustom {
+		problem.Checker.Program = &contract.Program{Language: version.CheckerLanguage, Source: version.CheckerSource}
+	}
+
+	if version.InteractorSource != "" {
+		problem.Interactor = &contract.Program{Language: version.InteractorLanguage, Source: version.InteractorSource}
+	}
+
+	if version.ValidatorSource != "" {
+		problem.Validator = &contract.Program{Language: version.ValidatorLanguage, Source: version.ValidatorSource}
+	}
+
+	for i, solution := range version.Solutions {
+		problem.Solutions[i] = Solution{
+			Name:            solution.Name,
+			ExpectedVerdict: constant.Verdict(solution.ExpectedVerdict),
+			Program:         contract.Program{Language: solution.Language, Source: solution.Source},
+		}
+	}
+
+	problem.ShortName = slug(problem.Title())
+	if problem.ShortName == "" {
+		problem.ShortName = "problem-" + strings.SplitN(problem.ProblemID.String(), "-", 2)[0]
+	}
+
+	return problem
+}
+
+// Title prefers the English title of the problem.
+func (p Problem) Title() string {
+	for _, statement := range p.Statements {
+		if statement.Language == constant.LanguageEnUS {
+			return statement.Title
+		}
+	}
+
+	if len(p.Statements) > 0 {
+		return p.Statements[0].Title
+	}
+
+	return ""
+}
+
+// slug turns a title into a lowercase short name made of letters, digits and
+// dashes.
+func slug(title string) string {
+	var b strings.Builder
+	dash := false
+
+	for _, c := range strings.ToLower(title) {
+		if c >= 'a' && c <= 'z' || c >= '0' && c <= '9' {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+
+			b.WriteRune(c)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+
+	return b.String()
+}
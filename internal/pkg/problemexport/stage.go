@@ -0,0 +1,50 @@
+package problemexport
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"emperror.dev/errors"
+)
+
+// StagedArchive is an archive written to a temporary file, which is removed
+// when it is closed.
+type StagedArchive struct {
+	*os.File
+	Size int64
+}
+
+// Stage writes an archive to a temporary file before anything is sent, so that
+// a failure can still be answered with an error status rather than a truncated
+// download.
+func Stage(ctx context.Context, write func(ctx context.Context, w io.Writer) error) (*StagedArchive, error) {
+	f, err := os.CreateTemp("", "export-*.zip")
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create archive file")
+	}
+
+	archive := &StagedArchive{File: f}
+	if err := write(ctx, f); err != nil {
+		_ = archive.Close()
+		return nil, err
+	}
+
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+
+	if err != nil {
+		_ = archive.Close()
+		return nil, errors.WrapIf(err, "failed to rewind archive file")
+	}
+
+	archive.Size = size
+	return archive, nil
+}
+
+func (a *StagedArchive) Close() error {
+	closeErr := a.File.Close()
+	return errors.Append(closeErr, os.Remove(a.Name()))
+}
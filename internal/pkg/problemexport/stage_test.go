@@ -0,0 +1,55 @@
+package problemexport
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"emperror.dev/errors"
+)
+
+func TestStage(t *testing.T) {
+	archive, err := Stage(context.Background(), func(_ context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, "archive")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Stage() error = %v", err)
+	}
+
+	content, err := io.ReadAll(archive)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+
+	if string(content) != "archive" || archive.Size != int64(len(content)) {
+		t.Errorf("Stage() = %q (size %d), want %q", content, archive.Size, "archive")
+	}
+
+	if err := archive.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+
+	if _, err := os.Stat(archive.Name()); !os.IsNotExist(err) {
+		t.Errorf("Stat() error = %v, want the file to be removed", err)
+	}
+}
+
+func TestStageWriteError(t *testing.T) {
+	errBlob := errors.New("blob missing")
+
+	var name string
+	_, err := Stage(context.Background(), func(_ context.Context, w io.Writer) error {
+		name = w.(*os.File).Name()
+		_, _ = io.WriteString(w, "partial")
+		return errBlob
+	})
+	if !errors.Is(err, errBlob) {
+		t.Errorf("Stage() error = %v, want %v", err, errBlob)
+	}
+
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		t.Errorf("Stat() error = %v, want the file to be removed", err)
+	}
+}
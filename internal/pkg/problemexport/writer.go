@@ -0,0 +1,449 @@
+package problemexport
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+
+	"emperror.dev/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const testlibHeader = "testlib.h"
+
+// submissionDirs maps expected verdicts to submission directories. There is
+// no directory for solutions that are only expected to be rejected, and
+// memory limit exceeded counts as a run-time error as on Kattis.
+var submissionDirs = map[constant.Verdict]string{
+	constant.VerdictAccepted:            "accepted",
+	constant.VerdictWrongAnswer:         "wrong_answer",
+	constant.VerdictTimeLimitExceeded:   "time_limit_exceeded",
+	constant.VerdictRuntimeError:        "run_time_error",
+	constant.VerdictMemoryLimitExceeded: "run_time_error",
+}
+
+var sourceExtensions = map[string]string{
+	constant.ProgrammingLanguageC:       ".c",
+	constant.ProgrammingLanguageCpp17:   ".cpp",
+	constant.ProgrammingLanguageCpp20:   ".cpp",
+	constant.ProgrammingLanguagePython3: ".py",
+	constant.ProgrammingLanguageJava:    ".java",
+}
+
+var javaClassPattern = regexp.MustCompile(`public\s+(?:final\s+)?class\s+([A-Za-z_][A-Za-z0-9_]*)`)
+
+// checkerRunScript adapts a testlib checker to an output validator, which is
+// run as "run input answer feedback_dir < team_output" and exits with 42 for
+// accepted and 43 for wrong answer.
+const checkerRunScript = `#!/bin/sh
+dir=$(dirname "$0")
+"$dir/checker" "$1" /dev/stdin "$2" 2> "$3/judgemessage.txt"
+case $? in
+0) exit 42 ;;
+1 | 2 | 4 | 7 | 8 | 16) exit 43 ;;
+*) exit 1 ;;
+esac
+`
+
+// interactorRunScript adapts a testlib interactor to an interactive output
+// validator, whose standard streams are connected to the submission.
+const interactorRunScript = `#!/bin/sh
+dir=$(dirname "$0")
+"$dir/interactor" "$1" "$3/interactor_output.txt" "$2" 2> "$3/judgemessage.txt"
+case $? in
+0) exit 42 ;;
+1 | 2 | 4 | 7 | 8 | 16) exit 43 ;;
+*) exit 1 ;;
+esac
+`
+
+// validatorRunScript adapts a testlib validator to an input format validator,
+// which reads the input on standard input and exits with 42 if it is valid.
+const validatorRunScript = `#!/bin/sh
+dir=$(dirname "$0")
+if "$dir/validator"; then
+	exit 42
+fi
+exit 43
+`
+
+type problemYAML struct {
+	Name           string     `yaml:"name"`
+	Source         string     `yaml:"source,omitempty"`
+	Validation     string     `yaml:"validation"`
+	ValidatorFlags string     `yaml:"validator_flags,omitempty"`
+	Limits         limitsYAML `yaml:"limits"`
+}
+
+type limitsYAML struct {
+	Memory uint `yaml:"memory"`
+}
+
+type contestYAML struct {
+	ID         string `yaml:"id"`
+	Name       string `yaml:"name"`
+	FormalName string `yaml:"formal_name"`
+}
+
+type contestProblemYAML struct {
+	ID      string `yaml:"id"`
+	Label   string `yaml:"label"`
+	Name    string `yaml:"name"`
+	Ordinal int    `yaml:"ordinal"`
+}
+
+// Writer writes problem packages, reading testcases from the blob store.
+type Writer struct {
+	format     Format
+	blobStore  contract.BlobStore
+	testlibDir string
+}
+
+// NewWriter creates a writer for the format. testlib.h is bundled with the
+// testlib programs when testlibDir contains it.
+func NewWriter(format Format, blobStore contract.BlobStore, testlibDir string) *Writer {
+	return &Writer{
+		format:     format,
+		blobStore:  blobStore,
+		testlibDir: testlibDir,
+	}
+}
+
+// WriteProblem writes a zip archive with the package of the problem at its
+// root.
+func (w *Writer) WriteProblem(ctx context.Context, out io.Writer, problem Problem) error {
+	zw := zip.NewWriter(out)
+	if err := w.writeProblem(ctx, zw, "", problem, ""); err != nil {
+		return err
+	}
+
+	return errors.WrapIf(zw.Close(), "failed to finish archive")
+}
+
+// WriteContest writes a zip archive with one directory per problem, named
+// after its short name.
+func (w *Writer) WriteContest(ctx context.Context, out io.Writer, contest Contest) error {
+	zw := zip.NewWriter(out)
+
+	used := make(map[string]int)
+	shortNames := make([]string, len(contest.Problems))
+	for i, problem := range contest.Problems {
+		shortNames[i] = problem.ShortName
+		if used[problem.ShortName]++; used[problem.ShortName] > 1 {
+			shortNames[i] = fmt.Sprintf("%s-%d", problem.ShortName, used[problem.ShortName])
+		}
+	}
+
+	if w.format == FormatDOMjudge {
+		if err := writeYAML(zw, "contest.yaml", contestYAML{
+			ID:         slug(contest.Title),
+			Name:       contest.Title,
+			FormalName: contest.Title,
+		}); err != nil {
+			return err
+		}
+
+		problems := make([]contestProblemYAML, len(contest.Problems))
+		for i, problem := range contest.Problems {
+			problems[i] = contestProblemYAML{
+				ID:      shortNames[i],
+				Label:   problem.Label,
+				Name:    problem.Title(),
+				Ordinal: i,
+			}
+		}
+
+		if err := writeYAML(zw, "problems.yaml", problems); err != nil {
+			return err
+		}
+	}
+
+	for i, problem := range contest.Problems {
+		if err := w.writeProblem(ctx, zw, shortNames[i]+"/", problem.Problem, contest.Title); err != nil {
+			return err
+		}
+	}
+
+	return errors.WrapIf(zw.Close(), "failed to finish archive")
+}
+
+func (w *Writer) writeProblem(ctx context.Context, zw *zip.Writer, dir string, problem Problem, source string) error {
+	if err := Check(problem); err != nil {
+		return err
+	}
+
+	metadata := problemYAML{
+		Name:       problem.Title(),
+		Source:     source,
+		Validation: "default",
+		Limits:     limitsYAML{Memory: problem.MemoryLimitMb},
+	}
+
+	switch {
+	case problem.IsInteractive:
+		metadata.Validation = "custom interactive"
+	case problem.Checker.Type == constant.CheckerTypeCustom:
+		metadata.Validation = "custom"
+	case problem.Checker.Type == constant.CheckerTypeExact:
+		metadata.ValidatorFlags = "case_sensitive space_change_sensitive"
+	case problem.Checker.Type == constant.CheckerTypeToken:
+		metadata.ValidatorFlags = "case_sensitive"
+	case problem.Checker.Type == constant.CheckerTypeFloat:
+		metadata.ValidatorFlags = "float_tolerance " + strconv.FormatFloat(problem.Checker.Epsilon, 'g', -1, 64)
+	}
+
+	if err := writeYAML(zw, dir+"problem.yaml", metadata); err != nil {
+		return err
+	}
+
+	if w.format == FormatDOMjudge {
+		seconds := strconv.FormatFloat(float64(problem.TimeLimitMs)/1000, 'f', -1, 64)
+		if err := writeFile(zw, dir+".timelimit", seconds+"\n", false); err != nil {
+			return err
+		}
+	}
+
+	for _, statement := range problem.Statements {
+		language := strings.ToLower(strings.SplitN(statement.Language, "-", 2)[0])
+		name := fmt.Sprintf("%sproblem_statement/problem.%s.tex", dir, language)
+		if err := writeFile(zw, name, statementTeX(statement), false); err != nil {
+			return err
+		}
+	}
+
+	for i, example := range problem.Examples {
+		name := fmt.Sprintf("%sdata/sample/%02d", dir, i+1)
+		if err := writeFile(zw, name+".in", example.Input, false); err != nil {
+			return err
+		}
+
+		if err := writeFile(zw, name+".ans", example.Output, false); err != nil {
+			return err
+		}
+	}
+
+	width := len(strconv.Itoa(len(problem.Testcases)))
+	for i, testcase := range problem.Testcases {
+		name := fmt.Sprintf("%sdata/secret/%0*d", dir, width, i+1)
+		if testcase.Name != strconv.Itoa(i+1) {
+			name += "-" + safeName(testcase.Name)
+		}
+
+		if err := w.copyBlob(ctx, zw, name+".in", testcase.InputHash); err != nil {
+			return err
+		}
+
+		if err := w.copyBlob(ctx, zw, name+".ans", testcase.OutputHash); err != nil {
+			return err
+		}
+	}
+
+	if problem.IsInteractive {
+		if err := w.writeTestlibProgram(zw, dir+"output_validators/interactor/", "interactor", *problem.Interactor, interactorRunScript); err != nil {
+			return err
+		}
+	} else if problem.Checker.Program != nil {
+		if err := w.writeTestlibProgram(zw, dir+"output_validators/checker/", "checker", *problem.Checker.Program, checkerRunScript); err != nil {
+			return err
+		}
+	}
+
+	if problem.Validator != nil {
+		if err := w.writeTestlibProgram(zw, dir+"input_format_validators/validator/", "validator", *problem.Validator, validatorRunScript); err != nil {
+			return err
+		}
+	}
+
+	return writeSubmissions(zw, dir, problem.Solutions)
+}
+
+func (w *Writer) copyBlob(ctx context.Context, zw *zip.Writer, name string, hash string) error {
+	rc, err := w.blobStore.Open(ctx, hash)
+	if err != nil {
+		return errors.WrapIf(err, "failed to open testcase blob")
+	}
+	defer rc.Close()
+
+	f, err := zw.Create(name)
+	if err != nil {
+		return errors.WrapIf(err, "failed to add file to archive")
+	}
+
+	if _, err := io.Copy(f, rc); err != nil {
+		return errors.WrapIf(err, "failed to copy testcase blob")
+	}
+
+	return nil
+}
+
+// writeTestlibProgram writes the source of a testlib program next to a build
+// script that compiles it and a run script that adapts it to the package
+// format.
+func (w *Writer) writeTestlibProgram(zw *zip.Writer, dir string, name string, program contract.Program, runScript string) error {
+	standard := "gnu++17"
+	if program.Language == constant.ProgrammingLanguageCpp20 {
+		standard = "gnu++20"
+	}
+
+	buildScript := fmt.Sprintf(`#!/bin/sh
+dir=$(dirname "$0")
+g++ -O2 -std=%s -I "$dir" -o "$dir/%s" "$dir/%s.cpp"
+`, standard, name, name)
+
+	if err := writeFile(zw, dir+name+".cpp", program.Source, false); err != nil {
+		return err
+	}
+
+	if err := writeFile(zw, dir+"build", buildScript, true); err != nil {
+		return err
+	}
+
+	if err := writeFile(zw, dir+"run", runScript, true); err != nil {
+		return err
+	}
+
+	if w.testlibDir == "" {
+		return nil
+	}
+
+	header, err := os.ReadFile(filepath.Join(w.testlibDir, testlibHeader))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return errors.WrapIf(err, "failed to read testlib.h")
+	}
+
+	return writeFile(zw, dir+testlibHeader, string(header), false)
+}
+
+func writeSubmissions(zw *zip.Writer, dir string, solutions []Solution) error {
+	used := make(map[string]struct{})
+
+	for _, solution := range solutions {
+		submissionDir, ok := submissionDirs[solution.ExpectedVerdict]
+		if !ok {
+			continue
+		}
+
+		extension := sourceExtensions[solution.Language]
+		base := safeName(strings.TrimSuffix(solution.Name, extension))
+
+		// Java sources have to be named after their public class, so each
+		// one gets a directory of its own.
+		fileName := func(base string) string {
+			return path.Join(dir+"submissions", submissionDir, base+extension)
+		}
+		if solution.Language == constant.ProgrammingLanguageJava {
+			className := "Main"
+			if match := javaClassPattern.FindStringSubmatch(solution.Source); match != nil {
+				className = match[1]
+			}
+
+			fileName = func(base string) string {
+				return path.Join(dir+"submissions", submissionDir, base, className+extension)
+			}
+		}
+
+		name := fileName(base)
+		for i := 2; ; i++ {
+			if _, ok := used[name]; !ok {
+				break
+			}
+
+			name = fileName(fmt.Sprintf("%s-%d", base, i))
+		}
+		used[name] = struct{}{}
+
+		if err := writeFile(zw, name, solution.Source, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// statementTeX writes a statement as LaTeX. The title is plain text, while the
+// other parts keep their math, see texStatement.
+func statementTeX(statement Statement) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "\\problemname{%s}\n\n", texEscape(statement.Title))
+	for _, paragraph := range []string{statement.Background, statement.Statement} {
+		if paragraph != "" {
+			b.WriteString(texStatement(strings.TrimSpace(paragraph)) + "\n\n")
+		}
+	}
+
+	for _, section := range []struct {
+		title   string
+		content string
+	}{
+		{"Input", statement.InputFormat},
+		{"Output", statement.OutputFormat},
+		{"Notes", statement.Note},
+	} {
+		if section.content != "" {
+			fmt.Fprintf(&b, "\\section*{%s}\n%s\n\n", section.title, texStatement(strings.TrimSpace(section.content)))
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+func writeYAML(zw *zip.Writer, name string, value any) error {
+	content, err := yaml.Marshal(value)
+	if err != nil {
+		return errors.WrapIf(err, "failed to marshal "+name)
+	}
+
+	return writeFile(zw, name, string(content), false)
+}
+
+func writeFile(zw *zip.Writer, name string, content string, executable bool) error {
+	header := &zip.FileHeader{Name: name, Method: zip.Deflate}
+	if executable {
+		header.SetMode(0o755)
+	} else {
+		header.SetMode(0o644)
+	}
+
+	f, err := zw.CreateHeader(header)
+	if err != nil {
+		return errors.WrapIf(err, "failed to add file to archive")
+	}
+
+	if _, err := io.WriteString(f, content); err != nil {
+		return errors.WrapIf(err, "failed to write "+name)
+	}
+
+	return nil
+}
+
+// safeName keeps letters, digits, dots, dashes and underscores of a name to
+// use it as a file name.
+func safeName(name string) string {
+	name = strings.Map(func(c rune) rune {
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.' {
+			return c
+		}
+
+		return '_'
+	}, name)
+
+	name = strings.TrimLeft(name, ".")
+	if name == "" {
+		return "unnamed"
+	}
+
+	return name
+}
@@ -0,0 +1,163 @@
+package problemexport
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+type mapBlobStore map[string]string
+
+func (s mapBlobStore) Put(context.Context, io.Reader) (contract.BlobInfo, error) {
+	return contract.BlobInfo{}, errors.New("read-only")
+}
+
+func (s mapBlobStore) Open(_ context.Context, hash string) (io.ReadCloser, error) {
+	content, ok := s[hash]
+	if !ok {
+		return nil, errors.New("blob not found")
+	}
+
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+func testProblem() Problem {
+	return Problem{
+		ProblemID: uuid.New(),
+		ShortName: "a-plus-b",
+		Statements: []Statement{
+			{Language: constant.LanguageZhCN, Title: "加法"},
+			{Language: constant.LanguageEnUS, Title: "A Plus B", Statement: "Add.", InputFormat: "Two integers."},
+		},
+		Examples:      []Example{{Input: "1 2\n", Output: "3\n"}},
+		Testcases:     []Testcase{{Name: "1", InputHash: "in1", OutputHash: "out1"}, {Name: "max", InputHash: "in2", OutputHash: "out2"}},
+		TimeLimitMs:   1500,
+		MemoryLimitMb: 256,
+		Checker: Checker{
+			Type:    constant.CheckerTypeCustom,
+			Program: &contract.Program{Language: constant.ProgrammingLanguageCpp20, Source: "checker"},
+		},
+		Solutions: []Solution{
+			{Name: "main.cpp", ExpectedVerdict: constant.VerdictAccepted, Program: contract.Program{Language: constant.ProgrammingLanguageCpp17, Source: "main"}},
+			{Name: "main", ExpectedVerdict: constant.VerdictAccepted, Program: contract.Program{Language: constant.ProgrammingLanguageCpp17, Source: "main2"}},
+			{Name: "slow", ExpectedVerdict: constant.VerdictMemoryLimitExceeded, Program: contract.Program{Language: constant.ProgrammingLanguageJava, Source: "public class Slow {}"}},
+			{Name: "bad", ExpectedVerdict: constant.VerdictRejected, Program: contract.Program{Language: constant.ProgrammingLanguagePython3, Source: "bad"}},
+		},
+	}
+}
+
+func readZip(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		files[f.Name] = string(content)
+	}
+
+	return files
+}
+
+func TestWriteProblem(t *testing.T) {
+	blobs := mapBlobStore{"in1": "1 1\n", "out1": "2\n", "in2": "9 9\n", "out2": "18\n"}
+
+	var buf bytes.Buffer
+	if err := NewWriter(FormatDOMjudge, blobs, "").WriteProblem(context.Background(), &buf, testProblem()); err != nil {
+		t.Fatalf("WriteProblem() error = %v", err)
+	}
+
+	files := readZip(t, buf.Bytes())
+	want := map[string]string{
+		".timelimit":                                "1.5\n",
+		"data/sample/01.in":                         "1 2\n",
+		"data/secret/1.ans":                         "2\n",
+		"data/secret/2-max.in":                      "9 9\n",
+		"output_validators/checker/checker.cpp":     "checker",
+		"submissions/accepted/main.cpp":             "main",
+		"submissions/accepted/main-2.cpp":           "main2",
+		"submissions/run_time_error/slow/Slow.java": "public class Slow {}",
+	}
+	for name, content := range want {
+		if got, ok := files[name]; !ok || got != content {
+			t.Errorf("%s = %q, want %q", name, got, content)
+		}
+	}
+
+	for _, name := range []string{"problem.yaml", "problem_statement/problem.en.tex", "problem_statement/problem.zh.tex", "output_validators/checker/run"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("%s is missing", name)
+		}
+	}
+
+	if !strings.Contains(files["problem.yaml"], "name: A Plus B") || !strings.Contains(files["problem.yaml"], "validation: custom") {
+		t.Errorf("problem.yaml = %q", files["problem.yaml"])
+	}
+
+	if strings.Contains(files["output_validators/checker/build"], "gnu++17") {
+		t.Errorf("build = %q, want gnu++20", files["output_validators/checker/build"])
+	}
+
+	if len(files) != 16 {
+		t.Errorf("len(files) = %d, want 16", len(files))
+	}
+}
+
+func TestWriteContest(t *testing.T) {
+	blobs := mapBlobStore{"in1": "", "out1": "", "in2": "", "out2": ""}
+	problem := testProblem()
+	contest := Contest{
+		Title:    "Spring Cup",
+		Problems: []ContestProblem{{Label: "A", Problem: problem}, {Label: "B", Problem: problem}},
+	}
+
+	var buf bytes.Buffer
+	if err := NewWriter(FormatKattis, blobs, "").WriteContest(context.Background(), &buf, contest); err != nil {
+		t.Fatalf("WriteContest() error = %v", err)
+	}
+
+	files := readZip(t, buf.Bytes())
+	for _, name := range []string{"a-plus-b/problem.yaml", "a-plus-b-2/problem.yaml"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("%s is missing", name)
+		}
+	}
+
+	for _, name := range []string{"contest.yaml", "problems.yaml", "a-plus-b/.timelimit"} {
+		if _, ok := files[name]; ok {
+			t.Errorf("%s is not expected in Kattis exports", name)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	problem := testProblem()
+	problem.InputFile = "input.txt"
+
+	if err := Check(problem); !errors.Is(err, ErrUnsupportedProblem) {
+		t.Errorf("Check() error = %v, want ErrUnsupportedProblem", err)
+	}
+}
@@ -0,0 +1,169 @@
+// Package similarity estimates how alike two texts are from MinHash
+// signatures of their shingles, the overlapping runs of characters or tokens
+// they are made of. Signatures are small and fixed in size, so a new problem
+// can be compared with every other one without loading their texts.
+//
+// The share of positions two signatures agree on estimates the Jaccard
+// similarity of their shingle sets: 1 for the same text, 0 for texts that
+// share nothing.
+package similarity
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"strings"
+	"unicode"
+
+	"emperror.dev/errors"
+)
+
+const SignatureSize = 128
+
+const (
+	textShingleSize  = 5 // Runes per shingle of prose, which works for scripts without spaces too
+	tokenShingleSize = 3 // Tokens per shingle of example data
+	minShingles      = 4 // Fewer shingles than this say too little to compare
+)
+
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// Signature is the MinHash signature of a text. An empty signature stands for
+// a text too short to compare, and is not similar to anything.
+type Signature []uint64
+
+// seeds pick the hash functions of the signature. They are fixed, so that
+// stored signatures stay comparable.
+var seeds = func() [SignatureSize]uint64 {
+	var seeds [SignatureSize]uint64
+
+	state := uint64(0x5eed_a190_417e_a000)
+	for i := range seeds {
+		state += 0x9e3779b97f4a7c15
+		seeds[i] = mix(state)
+	}
+
+	return seeds
+}()
+
+// Text signs prose, such as statements, ignoring case, punctuation and how
+// the words are spaced.
+func Text(texts ...string) Signature {
+	var runes []rune
+	for _, text := range texts {
+		for _, r := range strings.ToLower(text) {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				runes = append(runes, r)
+			} else if len(runes) > 0 && runes[len(runes)-1] != ' ' {
+				runes = append(runes, ' ')
+			}
+		}
+
+		if len(runes) > 0 && runes[len(runes)-1] != ' ' {
+			runes = append(runes, ' ')
+		}
+	}
+
+	shingles := make(map[uint64]struct{})
+	for i := 0; i+textShingleSize <= len(runes); i++ {
+		shingles[hash(string(runes[i:i+textShingleSize]))] = struct{}{}
+	}
+
+	return sign(shingles)
+}
+
+// Tokens signs whitespace separated data, such as example inputs and
+// outputs.
+func Tokens(texts ...string) Signature {
+	var tokens []string
+	for _, text := range texts {
+		tokens = append(tokens, strings.Fields(strings.ToLower(text))...)
+	}
+
+	shingles := make(map[uint64]struct{})
+	for i := 0; i+tokenShingleSize <= len(tokens); i++ {
+		shingles[hash(strings.Join(tokens[i:i+tokenShingleSize], " "))] = struct{}{}
+	}
+
+	return sign(shingles)
+}
+
+// Similarity estimates the Jaccard similarity of the texts behind two
+// signatures.
+func (s Signature) Similarity(other Signature) float64 {
+	if len(s) != SignatureSize || len(other) != SignatureSize {
+		return 0
+	}
+
+	equal := 0
+	for i := range s {
+		if s[i] == other[i] {
+			equal++
+		}
+	}
+
+	return float64(equal) / SignatureSize
+}
+
+// Bytes encodes the signature for storage. Empty signatures encode to no
+// bytes rather than nil, so that they can be told apart from missing ones.
+func (s Signature) Bytes() []byte {
+	b := make([]byte, 0, len(s)*8)
+	for _, v := range s {
+		b = binary.BigEndian.AppendUint64(b, v)
+	}
+
+	return b
+}
+
+// Parse decodes a signature encoded with Bytes.
+func Parse(b []byte) (Signature, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+
+	if len(b) != SignatureSize*8 {
+		return nil, errors.WithStack(ErrInvalidSignature)
+	}
+
+	s := make(Signature, SignatureSize)
+	for i := range s {
+		s[i] = binary.BigEndian.Uint64(b[i*8:])
+	}
+
+	return s, nil
+}
+
+func sign(shingles map[uint64]struct{}) Signature {
+	if len(shingles) < minShingles {
+		return nil
+	}
+
+	s := make(Signature, SignatureSize)
+	for i := range s {
+		s[i] = ^uint64(0)
+	}
+
+	for shingle := range shingles {
+		for i, seed := range seeds {
+			if h := mix(shingle ^ seed); h < s[i] {
+				s[i] = h
+			}
+		}
+	}
+
+	return s
+}
+
+func hash(shingle string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(shingle))
+	return h.Sum64()
+}
+
+// mix is the finalizer of SplitMix64, which spreads every input bit over the
+// whole output.
+func mix(x uint64) uint64 {
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
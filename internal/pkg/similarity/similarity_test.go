@@ -0,0 +1,74 @@
+package similarity
+
+import (
+	"reflect"
+	"testing"
+)
+
+const statement = `Given an array of n integers, answer q queries. Each query asks for the
+sum of the elements between positions l and r, inclusive, after some of the
+elements have been increased by a value. Print the answer to every query.`
+
+func TestTextSimilarity(t *testing.T) {
+	reworded := `Given an array of n integers, answer q queries! Each query asks for the
+SUM of the elements between positions l and r (inclusive) after some of the
+elements have been increased by a constant. Print the answer to each query.`
+	unrelated := `A robot walks on a grid from the top left corner to the bottom right
+corner, moving only down or right. Count the paths avoiding the blocked cells.`
+
+	if got := Text(statement).Similarity(Text(statement)); got != 1 {
+		t.Errorf("Similarity() of the same text = %v, want 1", got)
+	}
+
+	if got := Text(statement).Similarity(Text(reworded)); got < 0.6 {
+		t.Errorf("Similarity() of a reworded text = %v, want at least 0.6", got)
+	}
+
+	if got := Text(statement).Similarity(Text(unrelated)); got > 0.2 {
+		t.Errorf("Similarity() of an unrelated text = %v, want at most 0.2", got)
+	}
+}
+
+func TestShortTexts(t *testing.T) {
+	if got := Text("a b"); got != nil {
+		t.Errorf("Text() of a short text = %v, want nil", got)
+	}
+
+	if got := Tokens("1\n2"); got != nil {
+		t.Errorf("Tokens() of little data = %v, want nil", got)
+	}
+
+	if got := Signature(nil).Similarity(nil); got != 0 {
+		t.Errorf("Similarity() of empty signatures = %v, want 0", got)
+	}
+}
+
+func TestTokensIgnoreSpacing(t *testing.T) {
+	a := Tokens("5\n1 2 3 4 5", "15")
+	b := Tokens("5 1  2 3\n4 5\n15")
+
+	if got := a.Similarity(b); got != 1 {
+		t.Errorf("Similarity() of differently spaced data = %v, want 1", got)
+	}
+}
+
+func TestBytesRoundTrip(t *testing.T) {
+	s := Text(statement)
+
+	got, err := Parse(s.Bytes())
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, s) {
+		t.Error("Parse() did not return the encoded signature")
+	}
+
+	if b := Signature(nil).Bytes(); b == nil || len(b) != 0 {
+		t.Errorf("Bytes() of an empty signature = %#v, want no bytes", b)
+	}
+
+	if _, err := Parse([]byte{1, 2, 3}); err == nil {
+		t.Error("Parse() of a truncated signature succeeded, want an error")
+	}
+}
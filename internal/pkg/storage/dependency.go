@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+
+	"emperror.dev/errors"
+	"go.uber.org/dig"
+)
+
+func AddStorage(container *dig.Container) error {
+	if err := container.Provide(func(opts *Options) (contract.FileStorage, error) {
+		switch opts.Driver {
+		case "", DriverLocal:
+			return NewLocalStorage(opts)
+		case DriverS3:
+			return NewS3Storage(context.Background(), opts)
+		default:
+			return nil, errors.Errorf("unknown storage driver %q", opts.Driver)
+		}
+	}); err != nil {
+		return errors.WrapIf(err, "failed to provide file storage")
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"emperror.dev/errors"
+)
+
+const defaultLocalDirectory = "uploads"
+
+type LocalStorage struct {
+	root string
+}
+
+func NewLocalStorage(opts *Options) (*LocalStorage, error) {
+	root := opts.LocalDirectory
+	if root == "" {
+		root = defaultLocalDirectory
+	}
+
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, errors.WrapIf(err, "failed to create local storage directory")
+	}
+
+	return &LocalStorage{root: root}, nil
+}
+
+func (s *LocalStorage) Save(_ context.Context, key string, r io.Reader, _ int64, _ string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return errors.WrapIf(err, "failed to create object directory")
+	}
+
+	// Write to a temporary file first so readers never observe a partial object.
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return errors.WrapIf(err, "failed to create temporary file")
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return errors.WrapIf(err, "failed to write object")
+	}
+
+	if err := tmp.Close(); err != nil {
+		return errors.WrapIf(err, "failed to close temporary file")
+	}
+
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return errors.WrapIf(err, "failed to move object into place")
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errors.WithStack(ErrObjectNotFound)
+	} else if err != nil {
+		return nil, errors.WrapIf(err, "failed to open object")
+	}
+
+	return f, nil
+}
+
+func (s *LocalStorage) Delete(_ context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return errors.WrapIf(err, "failed to delete object")
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) path(key string) (string, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"emperror.dev/errors"
+)
+
+func TestLocalStorage(t *testing.T) {
+	s, err := NewLocalStorage(&Options{LocalDirectory: t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create local storage: %v", err)
+	}
+
+	ctx := context.Background()
+
+	if err := s.Save(ctx, "media/abc", strings.NewReader("hello"), 5, "text/plain"); err != nil {
+		t.Fatalf("failed to save object: %v", err)
+	}
+
+	r, err := s.Open(ctx, "media/abc")
+	if err != nil {
+		t.Fatalf("failed to open object: %v", err)
+	}
+
+	content, _ := io.ReadAll(r)
+	_ = r.Close()
+
+	if string(content) != "hello" {
+		t.Errorf("Expected %q, got %q", "hello", string(content))
+	}
+
+	if err := s.Delete(ctx, "media/abc"); err != nil {
+		t.Fatalf("failed to delete object: %v", err)
+	}
+
+	if _, err := s.Open(ctx, "media/abc"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("Expected ErrObjectNotFound, got %v", err)
+	}
+}
+
+func TestCleanKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{name: "simple key", key: "media/abc"},
+		{name: "leading slash", key: "/media/abc"},
+		{name: "parent traversal", key: "../etc/passwd", wantErr: true},
+		{name: "nested traversal", key: "media/../../etc/passwd", wantErr: true},
+		{name: "empty key", key: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cleanKey(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestIsMIMETypeAllowed(t *testing.T) {
+	opts := &Options{}
+
+	if !opts.IsMIMETypeAllowed("text/plain; charset=utf-8") {
+		t.Error("Expected text/plain to be allowed by default")
+	}
+
+	if opts.IsMIMETypeAllowed("text/html; charset=utf-8") {
+		t.Error("Expected text/html to be rejected by default")
+	}
+}
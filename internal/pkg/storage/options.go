@@ -0,0 +1,21 @@
+package storage
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+type Options struct {
+	Driver           string   `mapstructure:"driver"`
+	MaxFileSize      int64    `mapstructure:"maxFileSize"`
+	AllowedMIMETypes []string `mapstructure:"allowedMIMETypes"`
+
+	LocalDirectory string `mapstructure:"localDirectory"`
+
+	S3Endpoint        string `mapstructure:"s3Endpoint"`
+	S3Region          string `mapstructure:"s3Region"`
+	S3Bucket          string `mapstructure:"s3Bucket"`
+	S3AccessKeyID     string `mapstructure:"s3AccessKeyID"`
+	S3SecretAccessKey string `mapstructure:"s3SecretAccessKey"`
+	S3UseSSL          bool   `mapstructure:"s3UseSSL"`
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"io"
+
+	"emperror.dev/errors"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Storage talks to any S3-compatible object store (AWS S3, MinIO, Ceph RGW, ...).
+// A local MinIO container is enough to exercise it during development.
+type S3Storage struct {
+	client *minio.Client
+	bucket string
+}
+
+func NewS3Storage(ctx context.Context, opts *Options) (*S3Storage, error) {
+	if opts.S3Endpoint == "" || opts.S3Bucket == "" {
+		return nil, errors.New("s3 endpoint and bucket are required for the s3 storage driver")
+	}
+
+	client, err := minio.New(opts.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(opts.S3AccessKeyID, opts.S3SecretAccessKey, ""),
+		Secure: opts.S3UseSSL,
+		Region: opts.S3Region,
+	})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create s3 client")
+	}
+
+	exists, err := client.BucketExists(ctx, opts.S3Bucket)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to check s3 bucket")
+	}
+
+	if !exists {
+		if err := client.MakeBucket(ctx, opts.S3Bucket, minio.MakeBucketOptions{Region: opts.S3Region}); err != nil {
+			return nil, errors.WrapIf(err, "failed to create s3 bucket")
+		}
+	}
+
+	return &S3Storage{
+		client: client,
+		bucket: opts.S3Bucket,
+	}, nil
+}
+
+func (s *S3Storage) Save(ctx context.Context, key string, r io.Reader, size int64, mimeType string) error {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.client.PutObject(ctx, s.bucket, cleaned, r, size, minio.PutObjectOptions{
+		ContentType: mimeType,
+	}); err != nil {
+		return errors.WrapIf(err, "failed to put object")
+	}
+
+	return nil
+}
+
+func (s *S3Storage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := s.client.GetObject(ctx, s.bucket, cleaned, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get object")
+	}
+
+	// GetObject is lazy, so stat the object to surface missing keys up front.
+	if _, err := obj.Stat(); err != nil {
+		_ = obj.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, errors.WithStack(ErrObjectNotFound)
+		}
+
+		return nil, errors.WrapIf(err, "failed to stat object")
+	}
+
+	return obj, nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.RemoveObject(ctx, s.bucket, cleaned, minio.RemoveObjectOptions{}); err != nil {
+		return errors.WrapIf(err, "failed to remove object")
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+// TestS3Storage runs against the MinIO server at STORAGE_S3_TEST_ENDPOINT,
+// such as one started with
+//
+//	docker run -p 9000:9000 minio/minio server /data
+//
+// and the credentials in STORAGE_S3_TEST_ACCESS_KEY_ID and
+// STORAGE_S3_TEST_SECRET_ACCESS_KEY, which default to those of MinIO.
+func TestS3Storage(t *testing.T) {
+	endpoint := os.Getenv("STORAGE_S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("STORAGE_S3_TEST_ENDPOINT is not set")
+	}
+
+	accessKeyID, secretAccessKey := os.Getenv("STORAGE_S3_TEST_ACCESS_KEY_ID"), os.Getenv("STORAGE_S3_TEST_SECRET_ACCESS_KEY")
+	if accessKeyID == "" {
+		accessKeyID, secretAccessKey = "minioadmin", "minioadmin"
+	}
+
+	ctx := context.Background()
+
+	// The bucket is created by the storage if it does not exist yet.
+	s, err := NewS3Storage(ctx, &Options{
+		S3Endpoint:        endpoint,
+		S3Region:          "us-east-1",
+		S3Bucket:          "algorithmia-test",
+		S3AccessKeyID:     accessKeyID,
+		S3SecretAccessKey: secretAccessKey,
+	})
+	if err != nil {
+		t.Fatalf("failed to create s3 storage: %v", err)
+	}
+
+	key := "media/" + uuid.NewString()
+
+	if _, err := s.Open(ctx, key); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("Expected ErrObjectNotFound before saving, got %v", err)
+	}
+
+	if err := s.Save(ctx, "/"+key, strings.NewReader("hello"), 5, "text/plain"); err != nil {
+		t.Fatalf("failed to save object: %v", err)
+	}
+
+	r, err := s.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("failed to open object: %v", err)
+	}
+
+	content, _ := io.ReadAll(r)
+	_ = r.Close()
+
+	if string(content) != "hello" {
+		t.Errorf("Expected %q, got %q", "hello", string(content))
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("failed to delete object: %v", err)
+	}
+
+	if _, err := s.Open(ctx, key); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("Expected ErrObjectNotFound after deleting, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"mime"
+	"path"
+	"strings"
+
+	"emperror.dev/errors"
+)
+
+const DefaultMaxFileSize int64 = 10 << 20
+
+var DefaultAllowedMIMETypes = []string{
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"application/pdf",
+	"text/plain",
+	"application/zip",
+}
+
+var (
+	ErrObjectNotFound = errors.New("object not found")
+	ErrInvalidKey     = errors.New("invalid object key")
+)
+
+func (o *Options) GetMaxFileSize() int64 {
+	if o.MaxFileSize <= 0 {
+		return DefaultMaxFileSize
+	}
+
+	return o.MaxFileSize
+}
+
+func (o *Options) IsMIMETypeAllowed(mimeType string) bool {
+	allowed := o.AllowedMIMETypes
+	if len(allowed) == 0 {
+		allowed = DefaultAllowedMIMETypes
+	}
+
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return false
+	}
+
+	for _, a := range allowed {
+		if strings.EqualFold(strings.TrimSpace(a), mediaType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cleanKey normalizes an object key and rejects keys that would escape the
+// storage root, e.g. "../secret".
+func cleanKey(key string) (string, error) {
+	cleaned := strings.TrimPrefix(path.Clean("/"+key), "/")
+	if cleaned == "" || cleaned != strings.TrimPrefix(key, "/") {
+		return "", errors.WithStack(ErrInvalidKey)
+	}
+
+	return cleaned, nil
+}
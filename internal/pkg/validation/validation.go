@@ -0,0 +1,50 @@
+// Package validation builds the validator of requests, which knows the values
+// defined in the constant package by tag, e.g. `validate:"required,language"`.
+// Requests then accept exactly the values the rest of the code handles.
+package validation
+
+import (
+	"slices"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+)
+
+var tags = map[string]func(value string) bool{
+	"language":         oneOf(constant.ProgrammingLanguages),
+	"testlib_language": oneOf(constant.TestlibLanguages),
+	"checker_type":     oneOf(constant.CheckerTypes),
+	"expected_verdict": oneOf(constant.ExpectedVerdicts),
+	"tester_verdict":   oneOf(constant.TesterVerdicts),
+	"contest_phase": func(value string) bool {
+		return constant.FromStringToContestPhase(value) != ""
+	},
+	"contest_member_role": func(value string) bool {
+		return constant.FromStringToContestMemberRole(value) != ""
+	},
+	"problem_status": func(value string) bool {
+		return constant.FromStringToProblemStatus(value) != ""
+	},
+}
+
+func New() (*validator.Validate, error) {
+	v := validator.New()
+
+	for tag, valid := range tags {
+		if err := v.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+			return valid(fl.Field().String())
+		}); err != nil {
+			return nil, errors.WrapIf(err, "failed to register validation "+tag)
+		}
+	}
+
+	return v, nil
+}
+
+func oneOf[T ~string](values []T) func(value string) bool {
+	return func(value string) bool {
+		return slices.Contains(values, T(value))
+	}
+}
@@ -0,0 +1,70 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+)
+
+func TestTags(t *testing.T) {
+	v, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	tests := []struct {
+		tag   string
+		value string
+		valid bool
+	}{
+		{"language", constant.ProgrammingLanguageJava, true},
+		{"language", "rust", false},
+		{"testlib_language", constant.ProgrammingLanguageCpp20, true},
+		{"testlib_language", constant.ProgrammingLanguagePython3, false},
+		{"checker_type", constant.CheckerTypeYesNo, true},
+		{"checker_type", "fuzzy", false},
+		{"expected_verdict", string(constant.VerdictRejected), true},
+		{"expected_verdict", string(constant.VerdictCompilationError), false},
+		{"tester_verdict", string(constant.VerdictRuntimeError), true},
+		{"tester_verdict", string(constant.VerdictRejected), false},
+		{"contest_phase", string(constant.ContestPhaseFrozen), true},
+		{"contest_phase", "running", false},
+		{"contest_member_role", string(constant.ContestMemberRoleTester), true},
+		{"contest_member_role", "owner", false},
+		{"problem_status", string(constant.ProblemStatusAwaitingFinalCheck), true},
+		{"problem_status", "draft", false},
+	}
+
+	for _, tt := range tests {
+		err := v.Var(tt.value, tt.tag)
+		if tt.valid && err != nil {
+			t.Errorf("%s rejects %q: %v", tt.tag, tt.value, err)
+		} else if !tt.valid && err == nil {
+			t.Errorf("%s accepts %q", tt.tag, tt.value)
+		}
+	}
+}
+
+func TestNamedTypes(t *testing.T) {
+	v, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	type request struct {
+		Verdict  *constant.Verdict  `validate:"omitempty,tester_verdict"`
+		Verdicts []constant.Verdict `validate:"omitempty,dive,expected_verdict"`
+	}
+
+	accepted := constant.VerdictAccepted
+	if err := v.Struct(request{
+		Verdict:  &accepted,
+		Verdicts: []constant.Verdict{constant.VerdictRejected},
+	}); err != nil {
+		t.Errorf("valid request rejected: %v", err)
+	}
+
+	if err := v.Struct(request{Verdicts: []constant.Verdict{constant.VerdictCompilationError}}); err == nil {
+		t.Error("request with an unknown verdict accepted")
+	}
+}
@@ -0,0 +1,93 @@
+package websocket
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+const (
+	BackplaneMemory   = "memory"
+	BackplanePostgres = "postgres"
+)
+
+// Event is an event of a room, marshalled as sent to the clients.
+type Event struct {
+	Room    Room
+	Seq     uint64
+	Payload []byte
+}
+
+// Recheck asks the hubs to recheck the access of the clients in the room of a
+// problem, or of the clients of a user.
+type Recheck struct {
+	ProblemID uuid.NullUUID `json:"problem_id,omitempty"`
+	UserID    uuid.NullUUID `json:"user_id,omitempty"`
+}
+
+// Backplane fans out the events published on any server instance to the hubs
+// of every instance, this one included.
+type Backplane interface {
+	Publish(ctx context.Context, event *Event) error
+	PublishRecheck(ctx context.Context, recheck *Recheck) error
+	// Listen delivers the events and rechecks published on every instance
+	// until ctx is done.
+	Listen(ctx context.Context, deliver func(event *Event), recheck func(recheck *Recheck)) error
+}
+
+type memoryListener struct {
+	deliver func(event *Event)
+	recheck func(recheck *Recheck)
+}
+
+// MemoryBackplane delivers events within the process only, for a single
+// server instance.
+type MemoryBackplane struct {
+	mu        sync.RWMutex
+	listeners map[*memoryListener]bool
+}
+
+func NewMemoryBackplane() *MemoryBackplane {
+	return &MemoryBackplane{
+		listeners: make(map[*memoryListener]bool),
+	}
+}
+
+func (b *MemoryBackplane) Publish(_ context.Context, event *Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for listener := range b.listeners {
+		listener.deliver(event)
+	}
+
+	return nil
+}
+
+func (b *MemoryBackplane) PublishRecheck(_ context.Context, recheck *Recheck) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for listener := range b.listeners {
+		listener.recheck(recheck)
+	}
+
+	return nil
+}
+
+func (b *MemoryBackplane) Listen(ctx context.Context, deliver func(event *Event), recheck func(recheck *Recheck)) error {
+	listener := &memoryListener{deliver: deliver, recheck: recheck}
+
+	b.mu.Lock()
+	b.listeners[listener] = true
+	b.mu.Unlock()
+
+	<-ctx.Done()
+
+	b.mu.Lock()
+	delete(b.listeners, listener)
+	b.mu.Unlock()
+
+	return nil
+}
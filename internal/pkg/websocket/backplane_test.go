@@ -0,0 +1,121 @@
+package websocket
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseRoom(t *testing.T) {
+	for _, room := range []Room{ProblemRoom(uuid.New()), ContestRoom(uuid.New()), UserRoom(uuid.New())} {
+		got, err := ParseRoom(room.String())
+		if err != nil {
+			t.Fatalf("ParseRoom(%q) failed: %v", room, err)
+		}
+
+		if got != room {
+			t.Errorf("ParseRoom(%q) = %v, want %v", room, got, room)
+		}
+	}
+
+	for _, s := range []string{"", "problem", "team:" + uuid.NewString(), "problem:not-a-uuid"} {
+		if _, err := ParseRoom(s); err == nil {
+			t.Errorf("ParseRoom(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func TestNotification(t *testing.T) {
+	small := &Event{Room: ProblemRoom(uuid.New()), Seq: 7, Payload: []byte(`{"type":"user"}`)}
+
+	payload, err := encodeNotification(small)
+	if err != nil {
+		t.Fatalf("failed to encode notification: %v", err)
+	}
+
+	got, _, err := decodeNotification(payload)
+	if err != nil {
+		t.Fatalf("failed to decode notification: %v", err)
+	}
+
+	if got.Room != small.Room || got.Seq != small.Seq || !bytes.Equal(got.Payload, small.Payload) {
+		t.Errorf("decodeNotification() = %+v, want %+v", got, small)
+	}
+
+	large := &Event{
+		Room:    UserRoom(uuid.New()),
+		Seq:     8,
+		Payload: []byte(`{"content":"` + string(bytes.Repeat([]byte("a"), maxNotificationSize)) + `"}`),
+	}
+
+	if payload, err = encodeNotification(large); err != nil {
+		t.Fatalf("failed to encode notification: %v", err)
+	} else if len(payload) >= maxNotificationSize {
+		t.Fatalf("notification of %d bytes is too large", len(payload))
+	}
+
+	if got, _, err = decodeNotification(payload); err != nil {
+		t.Fatalf("failed to decode notification: %v", err)
+	}
+
+	if got.Room != large.Room || got.Seq != large.Seq || got.Payload != nil {
+		t.Errorf("decodeNotification() = %+v, want the event without its payload", got)
+	}
+}
+
+func TestRecheckNotification(t *testing.T) {
+	recheck := &Recheck{UserID: uuid.NullUUID{UUID: uuid.New(), Valid: true}}
+
+	payload, err := encodeRecheck(recheck)
+	if err != nil {
+		t.Fatalf("failed to encode recheck: %v", err)
+	}
+
+	event, got, err := decodeNotification(payload)
+	if err != nil {
+		t.Fatalf("failed to decode notification: %v", err)
+	}
+
+	if event != nil || got == nil || *got != *recheck {
+		t.Errorf("decodeNotification() = %+v, %+v, want %+v", event, got, recheck)
+	}
+}
+
+func TestMemoryBackplane(t *testing.T) {
+	b := NewMemoryBackplane()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	delivered := make(chan *Event, 1)
+	go func() {
+		_ = b.Listen(ctx, func(event *Event) {
+			delivered <- event
+		}, func(*Recheck) {})
+	}()
+
+	event := &Event{Room: ContestRoom(uuid.New()), Seq: 1, Payload: []byte("{}")}
+
+	// Listen registers in the background.
+	deadline := time.After(time.Second)
+	for {
+		if err := b.Publish(ctx, event); err != nil {
+			t.Fatalf("failed to publish event: %v", err)
+		}
+
+		select {
+		case got := <-delivered:
+			if got != event {
+				t.Errorf("delivered %+v, want %+v", got, event)
+			}
+
+			return
+		case <-deadline:
+			t.Fatal("event was not delivered")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
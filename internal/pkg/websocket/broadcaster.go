@@ -1,6 +1,7 @@
 package websocket
 
 import (
+	"context"
 	"encoding/json"
 	"time"
 
@@ -11,19 +12,23 @@ import (
 	"github.com/google/uuid"
 )
 
+// WsBroadcaster adds the events to the outbox in the transaction of the
+// context, so that they are published once the changes that caused them are
+// committed, and never if they are rolled back.
 type WsBroadcaster struct {
-	hub *Hub
-	l   logger.Logger
+	outbox contract.Outbox
+	l      logger.Logger
 }
 
-func NewWsBroadcaster(hub *Hub, l logger.Logger) *WsBroadcaster {
+func NewWsBroadcaster(outbox contract.Outbox, l logger.Logger) *WsBroadcaster {
 	return &WsBroadcaster{
-		hub: hub,
-		l:   l,
+		outbox: outbox,
+		l:      l,
 	}
 }
 
 func (b *WsBroadcaster) BroadcastUserMessage(
+	ctx context.Context,
 	problemID uuid.UUID,
 	messageID uuid.UUID,
 	content string,
@@ -62,13 +67,14 @@ func (b *WsBroadcaster) BroadcastUserMessage(
 		Payload: payload,
 	}
 
-	if err := b.broadcastEnvelope(problemID, &envelope); err != nil {
+	if err := b.broadcastProblemEvent(ctx, problemID, sender.UserID, &envelope); err != nil {
 		return errors.WrapIf(err, "failed to broadcast message")
 	}
 	return nil
 }
 
 func (b *WsBroadcaster) BroadcastSubmittedMessage(
+	ctx context.Context,
 	problemID uuid.UUID,
 	submitter contract.MessageUser,
 	timestamp time.Time,
@@ -92,13 +98,14 @@ func (b *WsBroadcaster) BroadcastSubmittedMessage(
 		Payload: payload,
 	}
 
-	if err := b.broadcastEnvelope(problemID, &envelope); err != nil {
+	if err := b.broadcastProblemEvent(ctx, problemID, submitter.UserID, &envelope); err != nil {
 		return errors.WrapIf(err, "failed to broadcast message")
 	}
 	return nil
 }
 
 func (b *WsBroadcaster) BroadcastEditedMessage(
+	ctx context.Context,
 	problemID uuid.UUID,
 	editor contract.MessageUser,
 	timestamp time.Time,
@@ -122,13 +129,14 @@ func (b *WsBroadcaster) BroadcastEditedMessage(
 		Payload: payload,
 	}
 
-	if err := b.broadcastEnvelope(problemID, &envelope); err != nil {
+	if err := b.broadcastProblemEvent(ctx, problemID, editor.UserID, &envelope); err != nil {
 		return errors.WrapIf(err, "failed to broadcast edited message")
 	}
 	return nil
 }
 
 func (b *WsBroadcaster) BroadcastReviewedMessage(
+	ctx context.Context,
 	problemID uuid.UUID,
 	reviewer contract.MessageUser,
 	decision string,
@@ -154,13 +162,14 @@ func (b *WsBroadcaster) BroadcastReviewedMessage(
 		Payload: payload,
 	}
 
-	if err := b.broadcastEnvelope(problemID, &envelope); err != nil {
+	if err := b.broadcastProblemEvent(ctx, problemID, reviewer.UserID, &envelope); err != nil {
 		return errors.WrapIf(err, "failed to broadcast message")
 	}
 	return nil
 }
 
 func (b *WsBroadcaster) BroadcastTestedMessage(
+	ctx context.Context,
 	problemID uuid.UUID,
 	tester contract.MessageUser,
 	status string,
@@ -186,13 +195,14 @@ func (b *WsBroadcaster) BroadcastTestedMessage(
 		Payload: payload,
 	}
 
-	if err := b.broadcastEnvelope(problemID, &envelope); err != nil {
+	if err := b.broadcastProblemEvent(ctx, problemID, tester.UserID, &envelope); err != nil {
 		return errors.WrapIf(err, "failed to broadcast message")
 	}
 	return nil
 }
 
 func (b *WsBroadcaster) BroadcastCompletedMessage(
+	ctx context.Context,
 	problemID uuid.UUID,
 	completer contract.MessageUser,
 	timestamp time.Time,
@@ -216,18 +226,225 @@ func (b *WsBroadcaster) BroadcastCompletedMessage(
 		Payload: payload,
 	}
 
-	if err := b.broadcastEnvelope(problemID, &envelope); err != nil {
+	if err := b.broadcastProblemEvent(ctx, problemID, completer.UserID, &envelope); err != nil {
 		return errors.WrapIf(err, "failed to broadcast message")
 	}
 	return nil
 }
 
-func (b *WsBroadcaster) broadcastEnvelope(problemID uuid.UUID, e *OutgoingMessageEnvelope) error {
-	messageBytes, err := json.Marshal(e)
+func (b *WsBroadcaster) BroadcastJudgedMessage(
+	ctx context.Context,
+	problemID uuid.UUID,
+	run contract.MessageJudgeRun,
+	timestamp time.Time,
+) error {
+	b.l.Infow("WS Broadcaster: Broadcasting judged message", map[string]interface{}{
+		"problem_id":   problemID,
+		"judge_run_id": run.JudgeRunID,
+	})
+
+	payload := JudgedMessageServerPayload{
+		ProblemID: problemID,
+		Run:       run,
+		Summary:   run.Summary(),
+		Timestamp: timestamp,
+	}
+
+	envelope := OutgoingMessageEnvelope{
+		Type:    contract.MessageTypeJudged,
+		Payload: payload,
+	}
+
+	if err := b.broadcastEnvelope(ctx, ProblemRoom(problemID), &envelope); err != nil {
+		return errors.WrapIf(err, "failed to broadcast message")
+	}
+	return nil
+}
+
+func (b *WsBroadcaster) BroadcastDuplicateMessage(
+	ctx context.Context,
+	problemID uuid.UUID,
+	timestamp time.Time,
+) error {
+	b.l.Infow("WS Broadcaster: Broadcasting duplicate message", map[string]interface{}{
+		"problem_id": problemID,
+	})
+
+	payload := DuplicateMessageServerPayload{
+		ProblemID: problemID,
+		Timestamp: timestamp,
+	}
+
+	envelope := OutgoingMessageEnvelope{
+		Type:    contract.MessageTypeDuplicate,
+		Payload: payload,
+	}
+
+	if err := b.broadcastProblemEvent(ctx, problemID, uuid.Nil, &envelope); err != nil {
+		return errors.WrapIf(err, "failed to broadcast message")
+	}
+	return nil
+}
+
+func (b *WsBroadcaster) BroadcastTestersAssignedMessage(
+	ctx context.Context,
+	problemID uuid.UUID,
+	assigner contract.MessageUser,
+	testerIDs []uuid.UUID,
+	timestamp time.Time,
+) error {
+	b.l.Infow("WS Broadcaster: Broadcasting testers assigned message", map[string]interface{}{
+		"problem_id":   problemID,
+		"assigner_id":  assigner.UserID,
+		"tester_count": len(testerIDs),
+	})
+
+	payload := TestersAssignedMessageServerPayload{
+		ProblemID: problemID,
+		Assigner: UserServerPayload{
+			ID:       assigner.UserID,
+			Username: assigner.Username,
+		},
+		TesterIDs: testerIDs,
+		Timestamp: timestamp,
+	}
+
+	envelope := OutgoingMessageEnvelope{
+		Type:    contract.MessageTypeTestersAssigned,
+		Payload: payload,
+	}
+
+	if err := b.broadcastProblemEvent(ctx, problemID, assigner.UserID, &envelope, testerIDs...); err != nil {
+		return errors.WrapIf(err, "failed to broadcast message")
+	}
+	return nil
+}
+
+func (b *WsBroadcaster) BroadcastProblemAssignedMessage(
+	ctx context.Context,
+	contestID uuid.UUID,
+	problemID uuid.UUID,
+	assigner contract.MessageUser,
+	timestamp time.Time,
+) error {
+	b.l.Infow("WS Broadcaster: Broadcasting problem assigned message", map[string]interface{}{
+		"contest_id":  contestID,
+		"problem_id":  problemID,
+		"assigner_id": assigner.UserID,
+	})
+
+	payload := ContestProblemMessageServerPayload{
+		ContestID: contestID,
+		ProblemID: problemID,
+		Actor: UserServerPayload{
+			ID:       assigner.UserID,
+			Username: assigner.Username,
+		},
+		Timestamp: timestamp,
+	}
+
+	envelope := OutgoingMessageEnvelope{
+		Type:    contract.MessageTypeProblemAssigned,
+		Payload: payload,
+	}
+
+	if err := b.broadcastEnvelope(ctx, ContestRoom(contestID), &envelope); err != nil {
+		return errors.WrapIf(err, "failed to broadcast message")
+	}
+	return nil
+}
+
+func (b *WsBroadcaster) BroadcastProblemUnassignedMessage(
+	ctx context.Context,
+	contestID uuid.UUID,
+	problemID uuid.UUID,
+	unassigner contract.MessageUser,
+	timestamp time.Time,
+) error {
+	b.l.Infow("WS Broadcaster: Broadcasting problem unassigned message", map[string]interface{}{
+		"contest_id":    contestID,
+		"problem_id":    problemID,
+		"unassigner_id": unassigner.UserID,
+	})
+
+	payload := ContestProblemMessageServerPayload{
+		ContestID: contestID,
+		ProblemID: problemID,
+		Actor: UserServerPayload{
+			ID:       unassigner.UserID,
+			Username: unassigner.Username,
+		},
+		Timestamp: timestamp,
+	}
+
+	envelope := OutgoingMessageEnvelope{
+		Type:    contract.MessageTypeProblemUnassigned,
+		Payload: payload,
+	}
+
+	if err := b.broadcastEnvelope(ctx, ContestRoom(contestID), &envelope); err != nil {
+		return errors.WrapIf(err, "failed to broadcast message")
+	}
+	return nil
+}
+
+// broadcastEnvelope sends the event to the clients in the room.
+func (b *WsBroadcaster) broadcastEnvelope(ctx context.Context, room Room, e *OutgoingMessageEnvelope) error {
+	publication, err := newPublication(room, e)
 	if err != nil {
-		return errors.WrapIf(err, "failed to marshal message for broadcast")
+		return err
+	}
+
+	return b.addPublication(ctx, publication)
+}
+
+// broadcastProblemEvent sends the event to the room of the problem, and as a
+// notification to its followers and the other recipients, except the user
+// who caused it.
+func (b *WsBroadcaster) broadcastProblemEvent(
+	ctx context.Context,
+	problemID uuid.UUID,
+	actorID uuid.UUID,
+	e *OutgoingMessageEnvelope,
+	recipientIDs ...uuid.UUID,
+) error {
+	publication, err := newPublication(ProblemRoom(problemID), e)
+	if err != nil {
+		return err
+	}
+
+	publication.NotifiedProblemID = uuid.NullUUID{UUID: problemID, Valid: true}
+	publication.ActorID = actorID
+	publication.RecipientIDs = recipientIDs
+
+	return b.addPublication(ctx, publication)
+}
+
+func (b *WsBroadcaster) addPublication(ctx context.Context, publication *Publication) error {
+	bytes, err := json.Marshal(publication)
+	if err != nil {
+		return errors.WrapIf(err, "failed to marshal publication")
+	}
+
+	// The events of a room are published in the order they were broadcast.
+	room := Room{Type: publication.RoomType, ID: publication.RoomID}
+	if err := b.outbox.Add(ctx, PublicationTopic, room.String(), bytes); err != nil {
+		return errors.WrapIf(err, "failed to add publication to outbox")
 	}
 
-	b.hub.BroadcastToProblemChat(problemID, messageBytes)
 	return nil
 }
+
+func newPublication(room Room, e *OutgoingMessageEnvelope) (*Publication, error) {
+	payload, err := json.Marshal(e.Payload)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to marshal event payload")
+	}
+
+	return &Publication{
+		RoomType: room.Type,
+		RoomID:   room.ID,
+		Type:     e.Type,
+		Payload:  payload,
+	}, nil
+}
@@ -37,7 +37,12 @@ type Client struct {
 
 	// Buffered channel of outbound messages.
 	// Messages placed here will be picked up by the writePump.
-	send chan []byte
+	send      chan []byte
+	closeOnce sync.Once
+
+	// The rooms the client is in. Protected by the mu of the hub, which keeps
+	// it in step with its own rooms map.
+	rooms map[Room]bool
 
 	mu               sync.RWMutex
 	focusedProblemID uuid.NullUUID
@@ -49,7 +54,8 @@ func NewClient(l logger.Logger, hub *Hub, conn *websocket.Conn, userID uuid.UUID
 		hub:    hub,
 		conn:   conn,
 		userID: userID,
-		send:   make(chan []byte, 256),
+		send:   make(chan []byte, sendBufferSize),
+		rooms:  make(map[Room]bool),
 	}
 }
 
@@ -154,10 +160,25 @@ func (c *Client) sendRaw(message []byte) {
 	select {
 	case c.send <- message:
 	default: // Don't block if client's send buffer is full
-		c.l.Infow("WS Client send buffer full", map[string]interface{}{
+		c.closeSlow()
+	}
+}
+
+// closeSlow disconnects a client that does not keep up with its messages
+// rather than silently dropping some. It can resume from the last event it
+// received once it reconnects.
+func (c *Client) closeSlow() {
+	c.closeOnce.Do(func() {
+		c.l.Infow("WS Client send buffer full, disconnecting", map[string]interface{}{
 			"user_id": c.userID,
 		})
-	}
+
+		// Closing waits for the close handshake, which must not hold up the
+		// broadcast.
+		go func() {
+			_ = c.conn.Close(websocket.StatusPolicyViolation, "Client too slow to keep up")
+		}()
+	})
 }
 
 func (c *Client) sendError(errMsg string, code ErrorCode, requestID string) {
@@ -2,9 +2,11 @@ package websocket
 
 import (
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
 
 	"emperror.dev/errors"
 	"go.uber.org/dig"
+	"gorm.io/gorm"
 )
 
 func AddWebsocket(container *dig.Container) error {
@@ -12,10 +14,42 @@ func AddWebsocket(container *dig.Container) error {
 		return errors.WrapIf(err, "failed to provide websocket router")
 	}
 
+	if err := container.Provide(NewGormRepository, dig.As(new(Repository))); err != nil {
+		return errors.WrapIf(err, "failed to provide websocket repository")
+	}
+
+	if err := container.Provide(func(
+		opts *Options,
+		db *gorm.DB,
+		repo Repository,
+		l logger.Logger,
+	) (Backplane, error) {
+		switch opts.Backplane {
+		case "", BackplaneMemory:
+			return NewMemoryBackplane(), nil
+		case BackplanePostgres:
+			return NewPostgresBackplane(db, repo, l)
+		default:
+			return nil, errors.Errorf("unknown websocket backplane %q", opts.Backplane)
+		}
+	}); err != nil {
+		return errors.WrapIf(err, "failed to provide websocket backplane")
+	}
+
 	if err := container.Provide(NewHub); err != nil {
 		return errors.WrapIf(err, "failed to provide websocket hub")
 	}
 
+	if err := container.Provide(func(h *Hub) contract.RoomAccessRevoker {
+		return h
+	}); err != nil {
+		return errors.WrapIf(err, "failed to provide websocket room access revoker")
+	}
+
+	if err := container.Provide(NewPublisher); err != nil {
+		return errors.WrapIf(err, "failed to provide websocket publisher")
+	}
+
 	if err := container.Provide(NewWsBroadcaster,
 		dig.As(new(contract.MessageBroadcaster))); err != nil {
 		return errors.WrapIf(err, "failed to provide websocket broadcaster")
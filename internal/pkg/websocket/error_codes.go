@@ -6,4 +6,8 @@ const (
 	ErrCodeInvalidPayload      ErrorCode = "invalid_payload"
 	ErrCodeInternalServerError ErrorCode = "internal_server_error"
 	ErrCodeUnknownAction       ErrorCode = "unknown_action"
+	ErrCodeAccessDenied        ErrorCode = "access_denied"
+	ErrCodeTooManyRooms        ErrorCode = "too_many_rooms"
+	ErrCodeNotSubscribed       ErrorCode = "not_subscribed"
+	ErrCodeResumeUnavailable   ErrorCode = "resume_unavailable"
 )
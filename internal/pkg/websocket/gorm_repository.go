@@ -0,0 +1,144 @@
+package websocket
+
+import (
+	"context"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormRepository struct {
+	db   *gorm.DB
+	opts *Options
+}
+
+func NewGormRepository(db *gorm.DB, opts *Options) *GormRepository {
+	return &GormRepository{
+		db:   db,
+		opts: opts,
+	}
+}
+
+func (r *GormRepository) AppendEvent(
+	ctx context.Context,
+	room Room,
+	key uuid.UUID,
+	createdAt time.Time,
+	marshal func(seq uint64) ([]byte, error),
+) (*Event, error) {
+	event := &Event{Room: room}
+
+	if err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing []database.RoomEvent
+		if err := tx.
+			Where("room = ? AND idempotency_key = ?", room.String(), key).
+			Limit(1).
+			Find(&existing).Error; err != nil {
+			return errors.WrapIf(err, "failed to get room event")
+		} else if len(existing) > 0 {
+			event.Seq, event.Payload = existing[0].Seq, existing[0].Payload
+			return nil
+		}
+
+		// The upsert locks the row of the room until the event is stored, so
+		// that concurrent events get consecutive sequence numbers in order.
+		sequence := database.RoomSequence{Room: room.String(), LastSeq: 1}
+		if err := tx.Clauses(
+			clause.OnConflict{
+				Columns: []clause.Column{{Name: "room"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"last_seq": gorm.Expr("room_sequences.last_seq + 1"),
+				}),
+			},
+			clause.Returning{Columns: []clause.Column{{Name: "last_seq"}}},
+		).Create(&sequence).Error; err != nil {
+			return errors.WrapIf(err, "failed to increment room sequence")
+		}
+
+		var err error
+		if event.Payload, err = marshal(sequence.LastSeq); err != nil {
+			return errors.WrapIf(err, "failed to marshal event")
+		}
+		event.Seq = sequence.LastSeq
+
+		// Should the same event be appended concurrently, the unique index on
+		// the key fails one of them, which finds the other's when retried.
+		if err := tx.Create(&database.RoomEvent{
+			RoomEventID:    uuid.New(),
+			Room:           room.String(),
+			Seq:            sequence.LastSeq,
+			IdempotencyKey: uuid.NullUUID{UUID: key, Valid: true},
+			Payload:        event.Payload,
+			CreatedAt:      createdAt,
+		}).Error; err != nil {
+			return errors.WrapIf(err, "failed to create room event")
+		}
+
+		window := uint64(r.opts.GetReplayWindow())
+		if sequence.LastSeq > window {
+			if err := tx.
+				Where("room = ? AND seq <= ?", room.String(), sequence.LastSeq-window).
+				Delete(&database.RoomEvent{}).Error; err != nil {
+				return errors.WrapIf(err, "failed to delete old room events")
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func (r *GormRepository) GetEventsAfter(ctx context.Context, room Room, afterSeq uint64) (*EventPage, error) {
+	// A room without events yet is at sequence number zero.
+	var sequence database.RoomSequence
+	if err := r.db.WithContext(ctx).
+		Where("room = ?", room.String()).
+		First(&sequence).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.WrapIf(err, "failed to get room sequence")
+	}
+
+	var events []database.RoomEvent
+	if err := r.db.WithContext(ctx).
+		Where("room = ? AND seq > ? AND seq <= ?", room.String(), afterSeq, sequence.LastSeq).
+		Order("seq ASC").
+		Limit(r.opts.GetReplayWindow()).
+		Find(&events).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get room events")
+	}
+
+	page := &EventPage{
+		LastSeq:  sequence.LastSeq,
+		Payloads: make([][]byte, 0, len(events)),
+	}
+
+	for _, event := range events {
+		page.Payloads = append(page.Payloads, event.Payload)
+	}
+
+	// Every event after afterSeq is there unless the first of them has been
+	// forgotten already. A client ahead of the room has seen events of
+	// another history, e.g. before the database was restored.
+	page.Complete = afterSeq == sequence.LastSeq ||
+		(len(events) > 0 && events[0].Seq == afterSeq+1)
+
+	return page, nil
+}
+
+func (r *GormRepository) GetEvent(ctx context.Context, room Room, seq uint64) ([]byte, error) {
+	var event database.RoomEvent
+	if err := r.db.WithContext(ctx).
+		Where("room = ? AND seq = ?", room.String(), seq).
+		First(&event).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get room event")
+	}
+
+	return event.Payload, nil
+}
@@ -0,0 +1,106 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func newTestRepository(t *testing.T, replayWindow int) *GormRepository {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every connection to an in-memory database gets a database of its own.
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(&database.RoomEvent{}, &database.RoomSequence{}); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewGormRepository(db, &Options{ReplayWindow: replayWindow})
+}
+
+func appendEvents(t *testing.T, repo *GormRepository, room Room, count int) {
+	t.Helper()
+
+	for range count {
+		if _, err := repo.AppendEvent(context.Background(), room, uuid.New(), time.Now(), func(seq uint64) ([]byte, error) {
+			return []byte(fmt.Sprintf(`{"seq":%d}`, seq)), nil
+		}); err != nil {
+			t.Fatalf("AppendEvent() error = %v", err)
+		}
+	}
+}
+
+func TestAppendEventKeepsSeqOfSameKey(t *testing.T) {
+	repo := newTestRepository(t, 0)
+	room := ProblemRoom(uuid.New())
+	key := uuid.New()
+
+	marshal := func(seq uint64) ([]byte, error) {
+		return []byte(fmt.Sprintf(`{"seq":%d}`, seq)), nil
+	}
+
+	first, err := repo.AppendEvent(context.Background(), room, key, time.Now(), marshal)
+	if err != nil {
+		t.Fatalf("AppendEvent() error = %v", err)
+	}
+
+	appendEvents(t, repo, room, 1)
+
+	again, err := repo.AppendEvent(context.Background(), room, key, time.Now(), marshal)
+	if err != nil {
+		t.Fatalf("AppendEvent() error = %v", err)
+	}
+
+	if first.Seq != 1 || again.Seq != first.Seq || string(again.Payload) != string(first.Payload) {
+		t.Errorf("AppendEvent() again = %d %s, want %d %s", again.Seq, again.Payload, first.Seq, first.Payload)
+	}
+}
+
+func TestGetEventsAfter(t *testing.T) {
+	repo := newTestRepository(t, 3)
+	room := ContestRoom(uuid.New())
+	appendEvents(t, repo, room, 5)
+
+	// Only the last 3 events, 3 to 5, are kept.
+	tests := []struct {
+		afterSeq     uint64
+		wantCount    int
+		wantComplete bool
+	}{
+		{afterSeq: 5, wantCount: 0, wantComplete: true},
+		{afterSeq: 3, wantCount: 2, wantComplete: true},
+		{afterSeq: 2, wantCount: 3, wantComplete: true},
+		{afterSeq: 1, wantCount: 3, wantComplete: false},
+		{afterSeq: 0, wantCount: 3, wantComplete: false},
+		{afterSeq: 9, wantCount: 0, wantComplete: false},
+	}
+
+	for _, tt := range tests {
+		page, err := repo.GetEventsAfter(context.Background(), room, tt.afterSeq)
+		if err != nil {
+			t.Fatalf("GetEventsAfter(%d) error = %v", tt.afterSeq, err)
+		}
+
+		if len(page.Payloads) != tt.wantCount || page.Complete != tt.wantComplete || page.LastSeq != 5 {
+			t.Errorf("GetEventsAfter(%d) = %d events, complete %t, last seq %d, want %d, %t, 5",
+				tt.afterSeq, len(page.Payloads), page.Complete, page.LastSeq, tt.wantCount, tt.wantComplete)
+		}
+	}
+}
@@ -2,35 +2,88 @@ package websocket
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
 
 	"github.com/coder/websocket"
 	"github.com/google/uuid"
 )
 
+const (
+	// Time allowed to recheck the access of the clients in a room.
+	recheckWait = 30 * time.Second
+
+	// Maximum number of rooms a client can be in, its personal channel
+	// included.
+	maxRoomsPerClient = 64
+)
+
+type Repository interface {
+	// AppendEvent stores the event marshalled with the next sequence number
+	// of the room, and returns it. An event appended to the room before with
+	// the same key is returned as it is, so that redelivered events keep
+	// their sequence number.
+	AppendEvent(
+		ctx context.Context,
+		room Room,
+		key uuid.UUID,
+		createdAt time.Time,
+		marshal func(seq uint64) ([]byte, error),
+	) (*Event, error)
+	GetEvent(ctx context.Context, room Room, seq uint64) ([]byte, error)
+	GetEventsAfter(ctx context.Context, room Room, afterSeq uint64) (*EventPage, error)
+}
+
+// EventPage holds the events of a room after a sequence number, oldest first.
+type EventPage struct {
+	Payloads [][]byte
+	LastSeq  uint64 // The sequence number of the last event of the room
+	Complete bool   // Whether none of the events after the sequence number is forgotten
+}
+
 type Hub struct {
 	l logger.Logger
 
-	r *Router
+	r         *Router
+	policy    contract.RoomAccessPolicy
+	repo      Repository
+	backplane Backplane
 
 	register   chan *Client
 	unregister chan *Client
 
 	mu      sync.RWMutex // Protects clients and rooms maps
 	clients map[*Client]bool
-	rooms   map[uuid.UUID]map[*Client]bool // problemID -> set of clients focused on this problem's chat
+	rooms   map[Room]map[*Client]bool // room -> set of clients subscribed to it
+
+	// Counts the rechecks of room access, so that a client joining a room
+	// can tell whether one may have missed it while it was authorized.
+	rechecks atomic.Uint64
 }
 
-func NewHub(l logger.Logger, r *Router) *Hub {
+func NewHub(
+	l logger.Logger,
+	r *Router,
+	policy contract.RoomAccessPolicy,
+	repo Repository,
+	backplane Backplane,
+) *Hub {
 	return &Hub{
 		l:          l,
 		r:          r,
+		policy:     policy,
+		repo:       repo,
+		backplane:  backplane,
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		clients:    make(map[*Client]bool),
-		rooms:      make(map[uuid.UUID]map[*Client]bool),
+		rooms:      make(map[Room]map[*Client]bool),
 	}
 }
 
@@ -38,6 +91,18 @@ func (h *Hub) Run(ctx context.Context) {
 	h.l.Info("WS Hub: Starting...")
 	defer h.l.Info("WS Hub: Stopped.")
 
+	// The events of every server instance reach the clients of this one
+	// through the backplane, including those published here.
+	go func() {
+		if err := h.backplane.Listen(ctx, func(event *Event) {
+			h.broadcastToRoom(event)
+		}, h.handleRecheck); err != nil {
+			h.l.Errorw("WS Hub: Failed to listen to the backplane", map[string]interface{}{
+				"error": err,
+			})
+		}
+	}()
+
 	for {
 		select {
 		case <-ctx.Done(): // Main application context is done
@@ -46,6 +111,7 @@ func (h *Hub) Run(ctx context.Context) {
 		case client := <-h.register:
 			h.mu.Lock()
 			h.clients[client] = true
+			h.join(client, UserRoom(client.userID))
 			h.mu.Unlock()
 
 			h.l.Infow("WS Hub: Client registered", map[string]interface{}{
@@ -58,23 +124,16 @@ func (h *Hub) Run(ctx context.Context) {
 				delete(h.clients, client)
 				close(client.send) // Signal client's writePump to stop
 
-				oldProblemID := client.getFocusedProblemID()
-				if oldProblemID.Valid {
-					if clients, ok := h.rooms[oldProblemID.UUID]; ok {
-						delete(clients, client)
-						if len(clients) == 0 {
-							delete(h.rooms, oldProblemID.UUID)
-						}
-
-						h.l.Infow("WS Hub: Client unregistered from room", map[string]interface{}{
-							"user_id":       client.userID,
-							"problem_id":    oldProblemID.UUID,
-							"total_clients": len(h.clients),
-						})
-					}
+				for room := range client.rooms {
+					h.leave(client, room)
 				}
 
 				client.clearFocusedProblem()
+
+				h.l.Infow("WS Hub: Client unregistered", map[string]interface{}{
+					"user_id":       client.userID,
+					"total_clients": len(h.clients),
+				})
 			}
 			h.mu.Unlock()
 		}
@@ -93,7 +152,83 @@ func (h *Hub) shutdown() {
 	}
 
 	h.clients = make(map[*Client]bool)
-	h.rooms = make(map[uuid.UUID]map[*Client]bool)
+	h.rooms = make(map[Room]map[*Client]bool)
+}
+
+// join must be called with mu held.
+func (h *Hub) join(client *Client, room Room) {
+	if _, ok := h.rooms[room]; !ok {
+		h.rooms[room] = make(map[*Client]bool)
+	}
+
+	h.rooms[room][client] = true
+	client.rooms[room] = true
+}
+
+// leave must be called with mu held.
+func (h *Hub) leave(client *Client, room Room) {
+	if clients, ok := h.rooms[room]; ok {
+		delete(clients, client)
+		if len(clients) == 0 {
+			delete(h.rooms, room)
+		}
+	}
+
+	delete(client.rooms, room)
+}
+
+func (h *Hub) canAccess(ctx context.Context, userID uuid.UUID, room Room) (bool, error) {
+	switch room.Type {
+	case RoomTypeProblem:
+		return h.policy.CanAccessProblemRoom(ctx, userID, room.ID)
+	case RoomTypeContest:
+		return h.policy.CanAccessContestRoom(ctx, userID, room.ID)
+	case RoomTypeUser:
+		return room.ID == userID, nil
+	default:
+		return false, nil
+	}
+}
+
+// authorize sends the client an error and returns false if it may not join
+// the room.
+func (h *Hub) authorize(ctx context.Context, client *Client, room Room, requestID string) bool {
+	ok, err := h.canAccess(ctx, client.userID, room)
+	if err != nil {
+		h.l.Errorw("WS Hub: Failed to check room access", map[string]interface{}{
+			"user_id": client.userID,
+			"room":    room.String(),
+			"error":   err,
+		})
+
+		client.sendError("Failed to check access to the room", ErrCodeInternalServerError, requestID)
+		return false
+	} else if !ok {
+		client.sendError("Access denied to this room", ErrCodeAccessDenied, requestID)
+		return false
+	}
+
+	return true
+}
+
+// confirmJoin checks the access of a client that has just joined the room
+// again if access was rechecked since it was authorized, as the recheck may
+// have run before the client was in the room. It takes the client out of the
+// room and returns false if it may not stay.
+func (h *Hub) confirmJoin(ctx context.Context, client *Client, room Room, requestID string, rechecks uint64) bool {
+	if h.rechecks.Load() == rechecks || h.authorize(ctx, client, room, requestID) {
+		return true
+	}
+
+	h.mu.Lock()
+	h.leave(client, room)
+	h.mu.Unlock()
+
+	if focused := client.getFocusedProblemID(); focused.Valid && ProblemRoom(focused.UUID) == room {
+		client.clearFocusedProblem()
+	}
+
+	return false
 }
 
 func (h *Hub) processIncomingMessage(ctx context.Context, client *Client, rawMsg IncomingMessageBase) {
@@ -101,19 +236,10 @@ func (h *Hub) processIncomingMessage(ctx context.Context, client *Client, rawMsg
 	case "set-active-problem-chat":
 		var payload SetActiveProblemChatClientPayload
 		if err := h.r.UnmarshalPayload(rawMsg.Payload, &payload); err != nil {
-			client.sendError("Invalid set-active-problem-chat payload", "INVALID_PAYLOAD", rawMsg.RequestID)
+			client.sendError("Invalid set-active-problem-chat payload", ErrCodeInvalidPayload, rawMsg.RequestID)
 			return
 		}
 
-		// TODO: Authorization check here or in an application service
-		// Can client.userID access/view payload.ProblemID?
-		// For example:
-		// canAccess, err := h.problemAuthService.CanUserAccessProblemChat(ctx, client.userID, payload.ProblemID)
-		// if err != nil || !canAccess {
-		//     client.sendError("Access denied to this problem chat.", "ACCESS_DENIED", rawMsg.RequestID)
-		//     return
-		// }
-
 		newProblemID := payload.ProblemID
 		oldProblemID := client.getFocusedProblemID()
 
@@ -122,29 +248,47 @@ func (h *Hub) processIncomingMessage(ctx context.Context, client *Client, rawMsg
 			return
 		}
 
+		rechecks := h.rechecks.Load()
+		if !h.authorize(ctx, client, ProblemRoom(newProblemID), rawMsg.RequestID) {
+			return
+		}
+
 		h.mu.Lock()
+		// The focused problem takes the place of the previous one, so that
+		// clients that only ever focus one problem stay in one problem room.
 		if oldProblemID.Valid {
-			if room, ok := h.rooms[oldProblemID.UUID]; ok {
-				delete(room, client)
-				if len(room) == 0 {
-					delete(h.rooms, oldProblemID.UUID)
-				}
+			h.leave(client, ProblemRoom(oldProblemID.UUID))
 
-				h.l.Infow("WS Hub: Client removed from room", map[string]interface{}{
-					"user_id":    client.userID,
-					"problem_id": oldProblemID.UUID,
-				})
-			}
+			h.l.Infow("WS Hub: Client removed from room", map[string]interface{}{
+				"user_id":    client.userID,
+				"problem_id": oldProblemID.UUID,
+			})
 		}
 
-		if _, ok := h.rooms[newProblemID]; !ok {
-			h.rooms[newProblemID] = make(map[*Client]bool)
+		// A problem subscribed to explicitly is already in, and stays when
+		// the focus moves on.
+		subscribed := client.rooms[ProblemRoom(newProblemID)]
+		if !subscribed && len(client.rooms) >= maxRoomsPerClient {
+			h.mu.Unlock()
+
+			client.clearFocusedProblem()
+			client.sendError("Too many rooms subscribed", ErrCodeTooManyRooms, rawMsg.RequestID)
+			return
 		}
 
-		h.rooms[newProblemID][client] = true
+		h.join(client, ProblemRoom(newProblemID))
 		h.mu.Unlock()
 
-		client.setFocusedProblemID(newProblemID)
+		if !h.confirmJoin(ctx, client, ProblemRoom(newProblemID), rawMsg.RequestID, rechecks) {
+			return
+		}
+
+		if subscribed {
+			client.clearFocusedProblem()
+		} else {
+			client.setFocusedProblemID(newProblemID)
+		}
+
 		client.sendAck("Active problem chat set to "+newProblemID.String(), rawMsg.RequestID)
 
 		h.l.Infow("WS Hub: Client added to room", map[string]interface{}{
@@ -152,6 +296,120 @@ func (h *Hub) processIncomingMessage(ctx context.Context, client *Client, rawMsg
 			"problem_id": newProblemID,
 		})
 
+	case "subscribe":
+		var payload RoomClientPayload
+		if err := h.r.UnmarshalPayload(rawMsg.Payload, &payload); err != nil ||
+			(payload.RoomType != RoomTypeProblem && payload.RoomType != RoomTypeContest) {
+			client.sendError("Invalid subscribe payload", ErrCodeInvalidPayload, rawMsg.RequestID)
+			return
+		}
+
+		room := Room{Type: payload.RoomType, ID: payload.RoomID}
+		rechecks := h.rechecks.Load()
+		if !h.authorize(ctx, client, room, rawMsg.RequestID) {
+			return
+		}
+
+		h.mu.Lock()
+		if len(client.rooms) >= maxRoomsPerClient && !client.rooms[room] {
+			h.mu.Unlock()
+
+			client.sendError("Too many rooms subscribed", ErrCodeTooManyRooms, rawMsg.RequestID)
+			return
+		}
+
+		h.join(client, room)
+		h.mu.Unlock()
+
+		if !h.confirmJoin(ctx, client, room, rawMsg.RequestID, rechecks) {
+			return
+		}
+
+		// A room subscribed to explicitly stays when the focus moves on.
+		if focused := client.getFocusedProblemID(); focused.Valid && ProblemRoom(focused.UUID) == room {
+			client.clearFocusedProblem()
+		}
+
+		client.sendAck("Subscribed to "+room.String(), rawMsg.RequestID)
+
+		h.l.Infow("WS Hub: Client subscribed to room", map[string]interface{}{
+			"user_id": client.userID,
+			"room":    room.String(),
+		})
+
+	case "unsubscribe":
+		var payload RoomClientPayload
+		if err := h.r.UnmarshalPayload(rawMsg.Payload, &payload); err != nil ||
+			(payload.RoomType != RoomTypeProblem && payload.RoomType != RoomTypeContest) {
+			client.sendError("Invalid unsubscribe payload", ErrCodeInvalidPayload, rawMsg.RequestID)
+			return
+		}
+
+		room := Room{Type: payload.RoomType, ID: payload.RoomID}
+
+		h.mu.Lock()
+		h.leave(client, room)
+		h.mu.Unlock()
+
+		if focused := client.getFocusedProblemID(); focused.Valid && ProblemRoom(focused.UUID) == room {
+			client.clearFocusedProblem()
+		}
+
+		client.sendAck("Unsubscribed from "+room.String(), rawMsg.RequestID)
+
+		h.l.Infow("WS Hub: Client unsubscribed from room", map[string]interface{}{
+			"user_id": client.userID,
+			"room":    room.String(),
+		})
+
+	case "resume":
+		var payload ResumeClientPayload
+		if err := h.r.UnmarshalPayload(rawMsg.Payload, &payload); err != nil {
+			client.sendError("Invalid resume payload", ErrCodeInvalidPayload, rawMsg.RequestID)
+			return
+		}
+
+		room := Room{Type: payload.RoomType, ID: payload.RoomID}
+		if room.Type == RoomTypeUser {
+			room.ID = client.userID
+		}
+
+		h.mu.RLock()
+		subscribed := client.rooms[room]
+		h.mu.RUnlock()
+
+		if !subscribed {
+			client.sendError("Not subscribed to "+room.String(), ErrCodeNotSubscribed, rawMsg.RequestID)
+			return
+		}
+
+		page, err := h.repo.GetEventsAfter(ctx, room, payload.AfterSeq)
+		if err != nil {
+			h.l.Errorw("WS Hub: Failed to get events to resume", map[string]interface{}{
+				"user_id": client.userID,
+				"room":    room.String(),
+				"error":   err,
+			})
+
+			client.sendError("Failed to resume", ErrCodeInternalServerError, rawMsg.RequestID)
+			return
+		} else if !page.Complete {
+			client.sendError(
+				"Events of "+room.String()+" are no longer available, fetch the room again",
+				ErrCodeResumeUnavailable,
+				rawMsg.RequestID,
+			)
+			return
+		}
+
+		// Events published since the client joined may arrive twice; clients
+		// skip those with a sequence number they have already seen.
+		for _, messageBytes := range page.Payloads {
+			client.sendRaw(messageBytes)
+		}
+
+		client.sendAck(fmt.Sprintf("Resumed %s up to seq %d", room, page.LastSeq), rawMsg.RequestID)
+
 	default:
 		if ok := h.r.Trigger(ctx, rawMsg.Action, rawMsg.Payload, func(message string, code ErrorCode) {
 			client.sendError(message, code, rawMsg.RequestID)
@@ -168,26 +426,164 @@ func (h *Hub) processIncomingMessage(ctx context.Context, client *Client, rawMsg
 	}
 }
 
-func (h *Hub) BroadcastToProblemChat(problemID uuid.UUID, messageBytes []byte) {
+func (h *Hub) broadcastToRoom(event *Event) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	roomClients, ok := h.rooms[problemID]
+	roomClients, ok := h.rooms[event.Room]
 	if !ok || len(roomClients) == 0 {
-		h.l.Infow("WS Hub Broadcast: No clients in problem chat room", map[string]interface{}{
-			"problem_id": problemID,
+		h.l.Infow("WS Hub Broadcast: No clients in room", map[string]interface{}{
+			"room": event.Room.String(),
 		})
 
 		return
 	}
 
+	// The payload is left out, as it may hold what only the clients in the
+	// room can read.
+	var envelope struct {
+		Type contract.MessageType `json:"type"`
+	}
+	_ = json.Unmarshal(event.Payload, &envelope)
+
 	h.l.Infow("WS Hub: Broadcasting message", map[string]interface{}{
-		"problem_id":   problemID,
-		"message":      string(messageBytes),
+		"room":         event.Room.String(),
+		"type":         envelope.Type,
+		"seq":          event.Seq,
+		"size":         len(event.Payload),
 		"client_count": len(roomClients),
 	})
 
 	for client := range roomClients {
-		client.sendRaw(messageBytes)
+		client.sendRaw(event.Payload)
+	}
+}
+
+// RecheckProblemRoom evicts the clients in the room of the problem that can no
+// longer access it, on every server instance.
+func (h *Hub) RecheckProblemRoom(problemID uuid.UUID) {
+	h.publishRecheck(&Recheck{ProblemID: uuid.NullUUID{UUID: problemID, Valid: true}})
+}
+
+// RecheckUserRooms evicts the clients of the user from the rooms they can no
+// longer access, on every server instance.
+func (h *Hub) RecheckUserRooms(userID uuid.UUID) {
+	h.publishRecheck(&Recheck{UserID: uuid.NullUUID{UUID: userID, Valid: true}})
+}
+
+func (h *Hub) publishRecheck(recheck *Recheck) {
+	ctx, cancel := context.WithTimeout(context.Background(), recheckWait)
+	defer cancel()
+
+	if err := h.backplane.PublishRecheck(ctx, recheck); err != nil {
+		// The clients of this instance are rechecked all the same.
+		h.l.Errorw("WS Hub: Failed to publish recheck", map[string]interface{}{
+			"error": err,
+		})
+
+		h.handleRecheck(recheck)
+	}
+}
+
+// handleRecheck rechecks the clients of this instance.
+func (h *Hub) handleRecheck(recheck *Recheck) {
+	if recheck.ProblemID.Valid {
+		h.recheckProblemRoom(recheck.ProblemID.UUID)
+	}
+
+	if recheck.UserID.Valid {
+		h.recheckUserRooms(recheck.UserID.UUID)
+	}
+}
+
+func (h *Hub) recheckProblemRoom(problemID uuid.UUID) {
+	room := ProblemRoom(problemID)
+
+	h.rechecks.Add(1)
+
+	h.mu.RLock()
+	clients := make([]*Client, 0, len(h.rooms[room]))
+	for client := range h.rooms[room] {
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	go h.recheck(clients, func(r Room) bool {
+		return r == room
+	})
+}
+
+func (h *Hub) recheckUserRooms(userID uuid.UUID) {
+	h.rechecks.Add(1)
+
+	h.mu.RLock()
+	clients := make([]*Client, 0)
+	for client := range h.clients {
+		if client.userID == userID {
+			clients = append(clients, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	go h.recheck(clients, func(r Room) bool {
+		return r.Type != RoomTypeUser
+	})
+}
+
+func (h *Hub) recheck(clients []*Client, shouldCheck func(Room) bool) {
+	for _, client := range clients {
+		h.mu.RLock()
+		rooms := make([]Room, 0, len(client.rooms))
+		for room := range client.rooms {
+			if shouldCheck(room) {
+				rooms = append(rooms, room)
+			}
+		}
+		h.mu.RUnlock()
+
+		for _, room := range rooms {
+			ctx, cancel := context.WithTimeout(context.Background(), recheckWait)
+			ok, err := h.canAccess(ctx, client.userID, room)
+
+			cancel()
+
+			if err != nil {
+				// Keep the client rather than evict it for an error of ours.
+				h.l.Errorw("WS Hub: Failed to recheck room access", map[string]interface{}{
+					"user_id": client.userID,
+					"room":    room.String(),
+					"error":   err,
+				})
+
+				continue
+			}
+
+			if !ok {
+				h.evict(client, room)
+			}
+		}
 	}
 }
+
+func (h *Hub) evict(client *Client, room Room) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// The client may have left the room, or disconnected, in the meantime.
+	if !client.rooms[room] || !h.clients[client] {
+		return
+	}
+
+	h.leave(client, room)
+
+	if focused := client.getFocusedProblemID(); focused.Valid && ProblemRoom(focused.UUID) == room {
+		client.clearFocusedProblem()
+	}
+
+	client.sendError("Access to "+room.String()+" has been revoked", ErrCodeAccessDenied, "")
+
+	h.l.Infow("WS Hub: Client evicted from room", map[string]interface{}{
+		"user_id": client.userID,
+		"room":    room.String(),
+	})
+}
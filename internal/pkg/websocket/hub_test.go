@@ -0,0 +1,353 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger/defaultlogger"
+
+	"github.com/google/uuid"
+)
+
+type fakePolicy struct {
+	canAccessProblemRoom func(problemID uuid.UUID) bool
+}
+
+func (p *fakePolicy) CanAccessProblemRoom(_ context.Context, _ uuid.UUID, problemID uuid.UUID) (bool, error) {
+	return p.canAccessProblemRoom(problemID), nil
+}
+
+func (p *fakePolicy) CanAccessContestRoom(context.Context, uuid.UUID, uuid.UUID) (bool, error) {
+	return true, nil
+}
+
+type reply struct {
+	Type    contract.MessageType `json:"type"`
+	Payload struct {
+		Code ErrorCode `json:"code"`
+	} `json:"payload"`
+	Seq uint64 `json:"seq"`
+}
+
+func newTestHub(policy contract.RoomAccessPolicy, repo Repository) *Hub {
+	return NewHub(defaultlogger.GetLogger(), NewRouter(), policy, repo, NewMemoryBackplane())
+}
+
+// connect registers a client without a connection, as Run would.
+func connect(h *Hub, userID uuid.UUID) *Client {
+	client := NewClient(defaultlogger.GetLogger(), h, nil, userID)
+
+	h.mu.Lock()
+	h.clients[client] = true
+	h.join(client, UserRoom(userID))
+	h.mu.Unlock()
+
+	return client
+}
+
+func subscribe(h *Hub, client *Client, room Room) {
+	h.processIncomingMessage(context.Background(), client, IncomingMessageBase{
+		Action:  "subscribe",
+		Payload: RoomClientPayload{RoomType: room.Type, RoomID: room.ID},
+	})
+}
+
+// replies takes the messages sent to the client so far.
+func replies(t *testing.T, client *Client) []reply {
+	t.Helper()
+
+	var got []reply
+	for {
+		select {
+		case message := <-client.send:
+			var r reply
+			if err := json.Unmarshal(message, &r); err != nil {
+				t.Fatalf("failed to unmarshal message %s: %v", message, err)
+			}
+
+			got = append(got, r)
+		default:
+			return got
+		}
+	}
+}
+
+func isInRoom(h *Hub, client *Client, room Room) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return client.rooms[room] && h.rooms[room][client]
+}
+
+func TestSubscribeConfirmsAccessRevokedWhileAuthorizing(t *testing.T) {
+	problemID := uuid.New()
+
+	var h *Hub
+	revoked := false
+	h = newTestHub(&fakePolicy{canAccessProblemRoom: func(uuid.UUID) bool {
+		if revoked {
+			return false
+		}
+
+		// Access is revoked right after it was granted, and rechecked
+		// before the client is in the room.
+		revoked = true
+		h.handleRecheck(&Recheck{ProblemID: uuid.NullUUID{UUID: problemID, Valid: true}})
+		return true
+	}}, nil)
+
+	client := connect(h, uuid.New())
+	subscribe(h, client, ProblemRoom(problemID))
+
+	if isInRoom(h, client, ProblemRoom(problemID)) {
+		t.Error("client is in the room after its access was revoked")
+	}
+
+	got := replies(t, client)
+	if len(got) != 1 || got[0].Payload.Code != ErrCodeAccessDenied {
+		t.Errorf("replies = %+v, want access denied", got)
+	}
+}
+
+func TestSubscribeWithoutRecheck(t *testing.T) {
+	h := newTestHub(&fakePolicy{canAccessProblemRoom: func(uuid.UUID) bool { return true }}, nil)
+
+	client := connect(h, uuid.New())
+	subscribe(h, client, ProblemRoom(uuid.New()))
+
+	got := replies(t, client)
+	if len(got) != 1 || got[0].Type != contract.MessageTypeAck {
+		t.Errorf("replies = %+v, want an ack", got)
+	}
+}
+
+func setActiveProblemChat(h *Hub, client *Client, problemID uuid.UUID) {
+	h.processIncomingMessage(context.Background(), client, IncomingMessageBase{
+		Action:  "set-active-problem-chat",
+		Payload: SetActiveProblemChatClientPayload{ProblemID: problemID},
+	})
+}
+
+func allowAll() *fakePolicy {
+	return &fakePolicy{canAccessProblemRoom: func(uuid.UUID) bool { return true }}
+}
+
+func TestSubscribeLimitsRooms(t *testing.T) {
+	h := newTestHub(allowAll(), nil)
+	client := connect(h, uuid.New())
+
+	// The personal channel takes one of the rooms.
+	rooms := make([]Room, 0, maxRoomsPerClient-1)
+	for range maxRoomsPerClient - 1 {
+		room := ContestRoom(uuid.New())
+		rooms = append(rooms, room)
+		subscribe(h, client, room)
+	}
+
+	for _, r := range replies(t, client) {
+		if r.Type != contract.MessageTypeAck {
+			t.Fatalf("reply = %+v, want an ack", r)
+		}
+	}
+
+	subscribe(h, client, ProblemRoom(uuid.New()))
+	if got := replies(t, client); len(got) != 1 || got[0].Payload.Code != ErrCodeTooManyRooms {
+		t.Errorf("replies = %+v, want too many rooms", got)
+	}
+
+	// A room the client is in already doesn't count again.
+	subscribe(h, client, rooms[0])
+	if got := replies(t, client); len(got) != 1 || got[0].Type != contract.MessageTypeAck {
+		t.Errorf("replies = %+v, want an ack", got)
+	}
+
+	h.processIncomingMessage(context.Background(), client, IncomingMessageBase{
+		Action:  "unsubscribe",
+		Payload: RoomClientPayload{RoomType: rooms[0].Type, RoomID: rooms[0].ID},
+	})
+	subscribe(h, client, ProblemRoom(uuid.New()))
+	if got := replies(t, client); len(got) != 2 || got[1].Type != contract.MessageTypeAck {
+		t.Errorf("replies = %+v, want an ack once a room is left", got)
+	}
+}
+
+func TestSetActiveProblemChatLimitsRooms(t *testing.T) {
+	h := newTestHub(allowAll(), nil)
+	client := connect(h, uuid.New())
+
+	for range maxRoomsPerClient - 1 {
+		subscribe(h, client, ContestRoom(uuid.New()))
+	}
+	replies(t, client)
+
+	problemID := uuid.New()
+	setActiveProblemChat(h, client, problemID)
+
+	if got := replies(t, client); len(got) != 1 || got[0].Payload.Code != ErrCodeTooManyRooms {
+		t.Errorf("replies = %+v, want too many rooms", got)
+	}
+
+	if isInRoom(h, client, ProblemRoom(problemID)) {
+		t.Error("client is in the room of the problem")
+	}
+
+	if focused := client.getFocusedProblemID(); focused.Valid {
+		t.Errorf("focused problem = %s, want none", focused.UUID)
+	}
+}
+
+func TestSetActiveProblemChatReplacesFocusedProblem(t *testing.T) {
+	h := newTestHub(allowAll(), nil)
+	client := connect(h, uuid.New())
+
+	subscribedID, firstID, secondID := uuid.New(), uuid.New(), uuid.New()
+	subscribe(h, client, ProblemRoom(subscribedID))
+	setActiveProblemChat(h, client, firstID)
+	setActiveProblemChat(h, client, secondID)
+
+	// The problem subscribed to explicitly stays when the focus moves on.
+	setActiveProblemChat(h, client, subscribedID)
+	setActiveProblemChat(h, client, firstID)
+
+	for _, r := range replies(t, client) {
+		if r.Type != contract.MessageTypeAck {
+			t.Fatalf("reply = %+v, want an ack", r)
+		}
+	}
+
+	want := map[Room]bool{
+		UserRoom(client.userID):   true,
+		ProblemRoom(subscribedID): true,
+		ProblemRoom(firstID):      true,
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if len(client.rooms) != len(want) {
+		t.Errorf("rooms = %v, want %v", client.rooms, want)
+	}
+
+	for room := range want {
+		if !client.rooms[room] {
+			t.Errorf("client is not in %s", room)
+		}
+	}
+}
+
+func resume(h *Hub, client *Client, room Room, afterSeq uint64) {
+	h.processIncomingMessage(context.Background(), client, IncomingMessageBase{
+		Action:  "resume",
+		Payload: ResumeClientPayload{RoomType: room.Type, RoomID: room.ID, AfterSeq: afterSeq},
+	})
+}
+
+func TestResume(t *testing.T) {
+	repo := newTestRepository(t, 3)
+	h := newTestHub(allowAll(), repo)
+	client := connect(h, uuid.New())
+
+	room := ProblemRoom(uuid.New())
+	appendEvents(t, repo, room, 5)
+
+	resume(h, client, room, 0)
+	if got := replies(t, client); len(got) != 1 || got[0].Payload.Code != ErrCodeNotSubscribed {
+		t.Errorf("replies = %+v, want not subscribed", got)
+	}
+
+	subscribe(h, client, room)
+	replies(t, client)
+
+	resume(h, client, room, 3)
+	got := replies(t, client)
+	if len(got) != 3 || got[0].Seq != 4 || got[1].Seq != 5 || got[2].Type != contract.MessageTypeAck {
+		t.Errorf("replies = %+v, want events 4 and 5 and an ack", got)
+	}
+
+	// Event 2 has been forgotten already.
+	resume(h, client, room, 1)
+	if got := replies(t, client); len(got) != 1 || got[0].Payload.Code != ErrCodeResumeUnavailable {
+		t.Errorf("replies = %+v, want resume unavailable", got)
+	}
+}
+
+func TestResumePersonalChannel(t *testing.T) {
+	repo := newTestRepository(t, 0)
+	h := newTestHub(allowAll(), repo)
+	client := connect(h, uuid.New())
+
+	appendEvents(t, repo, UserRoom(client.userID), 2)
+
+	// The room ID of the personal channel can be left out.
+	resume(h, client, Room{Type: RoomTypeUser}, 0)
+	if got := replies(t, client); len(got) != 3 || got[0].Seq != 1 || got[1].Seq != 2 {
+		t.Errorf("replies = %+v, want events 1 and 2 and an ack", got)
+	}
+}
+
+func TestRecheckReachesEveryHub(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	problemID := uuid.New()
+	var revoked atomic.Bool
+	policy := &fakePolicy{canAccessProblemRoom: func(uuid.UUID) bool {
+		return !revoked.Load()
+	}}
+
+	backplane := NewMemoryBackplane()
+	revoking := NewHub(defaultlogger.GetLogger(), NewRouter(), policy, nil, backplane)
+	serving := NewHub(defaultlogger.GetLogger(), NewRouter(), policy, nil, backplane)
+	go revoking.Run(ctx)
+	go serving.Run(ctx)
+
+	// The hubs listen to the backplane in the background.
+	deadline := time.Now().Add(time.Second)
+	for {
+		backplane.mu.RLock()
+		listening := len(backplane.listeners)
+		backplane.mu.RUnlock()
+
+		if listening == 2 {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatal("hubs are not listening to the backplane")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	// The client has no connection for the hub to close when it stops.
+	client := connect(serving, uuid.New())
+	defer func() {
+		serving.unregister <- client
+	}()
+
+	subscribe(serving, client, ProblemRoom(problemID))
+	replies(t, client)
+
+	revoked.Store(true)
+	revoking.RecheckProblemRoom(problemID)
+
+	select {
+	case message := <-client.send:
+		var r reply
+		if err := json.Unmarshal(message, &r); err != nil {
+			t.Fatal(err)
+		}
+
+		if r.Payload.Code != ErrCodeAccessDenied {
+			t.Errorf("reply = %+v, want access denied", r)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("client was not evicted")
+	}
+
+	if isInRoom(serving, client, ProblemRoom(problemID)) {
+		t.Error("client is in the room after its access was revoked")
+	}
+}
@@ -1,6 +1,29 @@
 package websocket
 
+const (
+	// Number of outbound messages buffered for a client before it is
+	// disconnected as too slow.
+	sendBufferSize = 256
+
+	defaultReplayWindow = 100
+)
+
 type Options struct {
 	SkipTLSVerification bool     `mapstructure:"skipTLSVerification"`
 	OriginPatterns      []string `mapstructure:"originPatterns"`
+	// Backplane fans out events between server instances: "memory" for a
+	// single instance, or "postgres" to use LISTEN/NOTIFY on the database.
+	Backplane string `mapstructure:"backplane"`
+	// ReplayWindow is the number of recent events kept per room for clients
+	// resuming after a reconnect. It is capped at half the send buffer, so that
+	// a replay cannot disconnect the client for being too slow.
+	ReplayWindow int `mapstructure:"replayWindow"`
+}
+
+func (o *Options) GetReplayWindow() int {
+	if o.ReplayWindow <= 0 {
+		return defaultReplayWindow
+	}
+
+	return min(o.ReplayWindow, sendBufferSize/2)
 }
@@ -0,0 +1,223 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+
+	"emperror.dev/errors"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+)
+
+const (
+	backplaneChannel = "algorithmia_ws_events"
+
+	// PostgreSQL rejects notifications of 8000 bytes or more. Larger events
+	// are loaded from the room events by the listeners instead.
+	maxNotificationSize = 7900
+
+	// Time to wait before listening again after losing the connection.
+	relistenWait = 5 * time.Second
+)
+
+// PostgresBackplane fans out events between server instances sharing a
+// PostgreSQL database with LISTEN/NOTIFY.
+type PostgresBackplane struct {
+	db   *gorm.DB
+	repo Repository
+	l    logger.Logger
+}
+
+func NewPostgresBackplane(db *gorm.DB, repo Repository, l logger.Logger) (*PostgresBackplane, error) {
+	if db.Dialector.Name() != "postgres" {
+		return nil, errors.Errorf("the %s backplane needs a PostgreSQL database", BackplanePostgres)
+	}
+
+	return &PostgresBackplane{
+		db:   db,
+		repo: repo,
+		l:    l,
+	}, nil
+}
+
+// notification holds either an event or a recheck.
+type notification struct {
+	Room    string          `json:"room,omitempty"`
+	Seq     uint64          `json:"seq,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"` // Left out when too large to notify
+	Recheck *Recheck        `json:"recheck,omitempty"`
+}
+
+func encodeNotification(event *Event) (string, error) {
+	n := notification{
+		Room:    event.Room.String(),
+		Seq:     event.Seq,
+		Payload: event.Payload,
+	}
+
+	bytes, err := json.Marshal(n)
+	if err != nil {
+		return "", errors.WrapIf(err, "failed to marshal notification")
+	}
+
+	if len(bytes) < maxNotificationSize {
+		return string(bytes), nil
+	}
+
+	n.Payload = nil
+	if bytes, err = json.Marshal(n); err != nil {
+		return "", errors.WrapIf(err, "failed to marshal notification")
+	}
+
+	return string(bytes), nil
+}
+
+func encodeRecheck(recheck *Recheck) (string, error) {
+	bytes, err := json.Marshal(notification{Recheck: recheck})
+	if err != nil {
+		return "", errors.WrapIf(err, "failed to marshal notification")
+	}
+
+	return string(bytes), nil
+}
+
+// decodeNotification returns either an event, without payload if it has to
+// be loaded, or a recheck.
+func decodeNotification(payload string) (*Event, *Recheck, error) {
+	var n notification
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		return nil, nil, errors.WrapIf(err, "failed to unmarshal notification")
+	}
+
+	if n.Recheck != nil {
+		return nil, n.Recheck, nil
+	}
+
+	room, err := ParseRoom(n.Room)
+	if err != nil {
+		return nil, nil, errors.WrapIf(err, "failed to parse room")
+	}
+
+	return &Event{
+		Room:    room,
+		Seq:     n.Seq,
+		Payload: n.Payload,
+	}, nil, nil
+}
+
+func (b *PostgresBackplane) Publish(ctx context.Context, event *Event) error {
+	payload, err := encodeNotification(event)
+	if err != nil {
+		return err
+	}
+
+	return b.notify(ctx, payload)
+}
+
+func (b *PostgresBackplane) PublishRecheck(ctx context.Context, recheck *Recheck) error {
+	payload, err := encodeRecheck(recheck)
+	if err != nil {
+		return err
+	}
+
+	return b.notify(ctx, payload)
+}
+
+func (b *PostgresBackplane) notify(ctx context.Context, payload string) error {
+	if err := b.db.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", backplaneChannel, payload).Error; err != nil {
+		return errors.WrapIf(err, "failed to notify")
+	}
+
+	return nil
+}
+
+// Listen keeps listening after losing the connection to the database. The
+// events notified in the meantime are missed, and the clients catch up on
+// them when they resume. The rechecks are missed as well.
+func (b *PostgresBackplane) Listen(ctx context.Context, deliver func(event *Event), recheck func(recheck *Recheck)) error {
+	for {
+		err := b.listen(ctx, deliver, recheck)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		b.l.Errorw("WS Backplane: Stopped listening, retrying", map[string]interface{}{
+			"error": err,
+		})
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(relistenWait):
+		}
+	}
+}
+
+func (b *PostgresBackplane) listen(ctx context.Context, deliver func(event *Event), recheck func(recheck *Recheck)) error {
+	sqlDB, err := b.db.DB()
+	if err != nil {
+		return errors.WrapIf(err, "failed to get database")
+	}
+
+	// LISTEN holds on to a connection of the pool for as long as it lasts.
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get connection")
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		stdlibConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errors.New("the connection is not a pgx connection")
+		}
+
+		pgxConn := stdlibConn.Conn()
+		if _, err := pgxConn.Exec(ctx, "LISTEN "+backplaneChannel); err != nil {
+			return errors.WrapIf(err, "failed to listen")
+		}
+
+		// Don't leave the connection listening when it goes back to the pool.
+		defer func() {
+			_, _ = pgxConn.Exec(context.Background(), "UNLISTEN "+backplaneChannel)
+		}()
+
+		b.l.Info("WS Backplane: Listening for events")
+
+		for {
+			n, err := pgxConn.WaitForNotification(ctx)
+			if err != nil {
+				return errors.WrapIf(err, "failed to wait for notification")
+			}
+
+			event, r, err := decodeNotification(n.Payload)
+			if err != nil {
+				b.l.Errorw("WS Backplane: Invalid notification", map[string]interface{}{
+					"error": err,
+				})
+
+				continue
+			} else if r != nil {
+				recheck(r)
+				continue
+			}
+
+			if event.Payload == nil {
+				if event.Payload, err = b.repo.GetEvent(ctx, event.Room, event.Seq); err != nil {
+					b.l.Errorw("WS Backplane: Failed to load event", map[string]interface{}{
+						"room":  event.Room.String(),
+						"seq":   event.Seq,
+						"error": err,
+					})
+
+					continue
+				}
+			}
+
+			deliver(event)
+		}
+	})
+}
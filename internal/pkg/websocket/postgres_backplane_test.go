@@ -0,0 +1,155 @@
+package websocket
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger/defaultlogger"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newTestPostgresBackplane connects to the PostgreSQL database of
+// WS_BACKPLANE_TEST_DSN, such as
+//
+//	host=localhost port=5433 user=algorithmia password=password dbname=algorithmia sslmode=disable
+//
+// and starts listening on it. The events are delivered to the returned
+// channel.
+func newTestPostgresBackplane(t *testing.T) (*PostgresBackplane, *GormRepository, <-chan *Event, <-chan *Recheck) {
+	t.Helper()
+
+	dsn := os.Getenv("WS_BACKPLANE_TEST_DSN")
+	if dsn == "" {
+		t.Skip("WS_BACKPLANE_TEST_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.AutoMigrate(&database.RoomEvent{}, &database.RoomSequence{}); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewGormRepository(db, &Options{})
+	backplane, err := NewPostgresBackplane(db, repo, defaultlogger.GetLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, rechecks := make(chan *Event, 16), make(chan *Recheck, 16)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_ = backplane.Listen(ctx, func(event *Event) {
+			events <- event
+		}, func(recheck *Recheck) {
+			rechecks <- recheck
+		})
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	return backplane, repo, events, rechecks
+}
+
+// publishUntilDelivered publishes the event until it comes back from the
+// backplane, as it may not be listening yet, and returns what was delivered.
+func publishUntilDelivered(t *testing.T, backplane *PostgresBackplane, events <-chan *Event, event *Event, wait time.Duration) *Event {
+	t.Helper()
+
+	deadline := time.After(wait)
+	for {
+		if err := backplane.Publish(context.Background(), event); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+
+		select {
+		case got := <-events:
+			if got.Room == event.Room && got.Seq == event.Seq {
+				return got
+			}
+		case <-time.After(100 * time.Millisecond):
+		case <-deadline:
+			t.Fatalf("event %s #%d was not delivered", event.Room, event.Seq)
+		}
+	}
+}
+
+func TestPostgresBackplaneRoundTrip(t *testing.T) {
+	backplane, _, events, rechecks := newTestPostgresBackplane(t)
+
+	event := &Event{Room: ProblemRoom(uuid.New()), Seq: 1, Payload: []byte(`{"type":"user"}`)}
+	if got := publishUntilDelivered(t, backplane, events, event, 5*time.Second); !bytes.Equal(got.Payload, event.Payload) {
+		t.Errorf("delivered payload %s, want %s", got.Payload, event.Payload)
+	}
+
+	recheck := &Recheck{UserID: uuid.NullUUID{UUID: uuid.New(), Valid: true}}
+	if err := backplane.PublishRecheck(context.Background(), recheck); err != nil {
+		t.Fatalf("PublishRecheck() error = %v", err)
+	}
+
+	select {
+	case got := <-rechecks:
+		if got.UserID != recheck.UserID || got.ProblemID.Valid {
+			t.Errorf("delivered recheck %+v, want %+v", got, recheck)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("recheck was not delivered")
+	}
+}
+
+func TestPostgresBackplaneLoadsLargeEvents(t *testing.T) {
+	backplane, repo, events, _ := newTestPostgresBackplane(t)
+
+	room := UserRoom(uuid.New())
+	payload := []byte(`{"content":"` + string(bytes.Repeat([]byte("a"), maxNotificationSize)) + `"}`)
+
+	event, err := repo.AppendEvent(context.Background(), room, uuid.New(), time.Now(), func(uint64) ([]byte, error) {
+		return payload, nil
+	})
+	if err != nil {
+		t.Fatalf("AppendEvent() error = %v", err)
+	}
+
+	if got := publishUntilDelivered(t, backplane, events, event, 5*time.Second); !bytes.Equal(got.Payload, payload) {
+		t.Errorf("delivered payload of %d bytes, want the %d bytes stored", len(got.Payload), len(payload))
+	}
+}
+
+func TestPostgresBackplaneListensAgainAfterLosingConnection(t *testing.T) {
+	backplane, _, events, _ := newTestPostgresBackplane(t)
+
+	room := ContestRoom(uuid.New())
+	publishUntilDelivered(t, backplane, events, &Event{Room: room, Seq: 1, Payload: []byte(`{}`)}, 5*time.Second)
+
+	var terminated int64
+	if err := backplane.db.Raw(
+		"SELECT COUNT(pg_terminate_backend(pid)) FROM pg_stat_activity WHERE pid <> pg_backend_pid() AND query = ?",
+		"LISTEN "+backplaneChannel,
+	).Scan(&terminated).Error; err != nil {
+		t.Fatal(err)
+	} else if terminated == 0 {
+		t.Fatal("found no listening connection to terminate")
+	}
+
+	publishUntilDelivered(t, backplane, events, &Event{Room: room, Seq: 2, Payload: []byte(`{}`)}, relistenWait+5*time.Second)
+}
@@ -1,6 +1,7 @@
 package websocket
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
@@ -18,6 +19,12 @@ type OutgoingMessageEnvelope struct {
 	Type      contract.MessageType `json:"type"`
 	Payload   interface{}          `json:"payload"`
 	RequestID string               `json:"request_id,omitempty"`
+
+	// Events broadcast to a room are numbered from 1 within the room, so
+	// that clients can resume from the last one they received.
+	RoomType RoomType   `json:"room_type,omitempty"`
+	RoomID   *uuid.UUID `json:"room_id,omitempty"`
+	Seq      uint64     `json:"seq,omitempty"`
 }
 
 // ErrorServerPayload for sending errors back to the client
@@ -64,6 +71,22 @@ type TestedMessageServerPayload struct {
 	Timestamp time.Time         `json:"timestamp"`
 }
 
+// JudgedMessageServerPayload for judge run results
+type JudgedMessageServerPayload struct {
+	ProblemID uuid.UUID                `json:"problem_id"`
+	Run       contract.MessageJudgeRun `json:"run"`
+	Summary   string                   `json:"summary"`
+	Timestamp time.Time                `json:"timestamp"`
+}
+
+// DuplicateMessageServerPayload for potential duplicates found for a submitted
+// version, which are listed with the messages of the problem to those who can
+// read them
+type DuplicateMessageServerPayload struct {
+	ProblemID uuid.UUID `json:"problem_id"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
 // CompletedMessageServerPayload for problem completion messages
 type CompletedMessageServerPayload struct {
 	ProblemID uuid.UUID         `json:"problem_id"`
@@ -71,6 +94,29 @@ type CompletedMessageServerPayload struct {
 	Timestamp time.Time         `json:"timestamp"`
 }
 
+// TestersAssignedMessageServerPayload for tester assignment messages
+type TestersAssignedMessageServerPayload struct {
+	ProblemID uuid.UUID         `json:"problem_id"`
+	Assigner  UserServerPayload `json:"assigner"`
+	TesterIDs []uuid.UUID       `json:"tester_ids"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// ContestProblemMessageServerPayload for problems assigned to or unassigned
+// from a contest
+type ContestProblemMessageServerPayload struct {
+	ContestID uuid.UUID         `json:"contest_id"`
+	ProblemID uuid.UUID         `json:"problem_id"`
+	Actor     UserServerPayload `json:"actor"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// NotificationServerPayload for events sent to the personal channel of a user
+// following the problem, wherever they are in the frontend
+type NotificationServerPayload struct {
+	Event json.RawMessage `json:"event"` // The event as sent to the room of the problem
+}
+
 // UserServerPayload for user information in messages
 type UserServerPayload struct {
 	ID       uuid.UUID `json:"id"`
@@ -88,3 +134,19 @@ type AttachmentServerInfo struct {
 type SetActiveProblemChatClientPayload struct {
 	ProblemID uuid.UUID `json:"problem_id"`
 }
+
+// RoomClientPayload for subscribing to and unsubscribing from problem and
+// contest rooms
+type RoomClientPayload struct {
+	RoomType RoomType  `json:"room_type"`
+	RoomID   uuid.UUID `json:"room_id"`
+}
+
+// ResumeClientPayload for receiving the events of a room the client is in
+// after the last one it received. The room_id of the personal channel can be
+// left out.
+type ResumeClientPayload struct {
+	RoomType RoomType  `json:"room_type"`
+	RoomID   uuid.UUID `json:"room_id"`
+	AfterSeq uint64    `json:"after_seq"`
+}
@@ -0,0 +1,164 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+// PublicationTopic is the outbox topic of the events broadcast to rooms.
+const PublicationTopic = "websocket.publication"
+
+// Publication is an event broadcast to a room, kept in the outbox until it
+// is published.
+type Publication struct {
+	RoomType RoomType             `json:"room_type"`
+	RoomID   uuid.UUID            `json:"room_id"`
+	Type     contract.MessageType `json:"type"`
+	Payload  json.RawMessage      `json:"payload"`
+
+	// The followers of the problem and the other recipients, except the user
+	// who caused the event, are notified of it.
+	NotifiedProblemID uuid.NullUUID `json:"notified_problem_id,omitempty"`
+	ActorID           uuid.UUID     `json:"actor_id,omitempty"`
+	RecipientIDs      []uuid.UUID   `json:"recipient_ids,omitempty"`
+}
+
+// Publisher stores the events taken from the outbox as the next ones of their
+// rooms and publishes them to the clients on every server instance.
+type Publisher struct {
+	repo      Repository
+	backplane Backplane
+	followers contract.ProblemFollowerProvider
+	l         logger.Logger
+
+	// Publishes the events of each room on this instance in the order of
+	// their sequence numbers, while other rooms go ahead.
+	mu        sync.Mutex
+	roomLocks map[Room]*roomLock
+}
+
+type roomLock struct {
+	sync.Mutex
+	waiters int // Including the holder; the lock is dropped when none is left
+}
+
+func NewPublisher(
+	repo Repository,
+	backplane Backplane,
+	followers contract.ProblemFollowerProvider,
+	l logger.Logger,
+) *Publisher {
+	return &Publisher{
+		repo:      repo,
+		backplane: backplane,
+		followers: followers,
+		l:         l,
+		roomLocks: make(map[Room]*roomLock),
+	}
+}
+
+func (p *Publisher) Topic() string {
+	return PublicationTopic
+}
+
+// HandleOutboxEvent can be retried as a whole: the outbox event ID keys the
+// events appended to the rooms, which are only published again.
+func (p *Publisher) HandleOutboxEvent(ctx context.Context, eventID uuid.UUID, payload []byte) error {
+	var publication Publication
+	if err := json.Unmarshal(payload, &publication); err != nil {
+		return errors.WrapIf(err, "failed to unmarshal publication")
+	}
+
+	event, err := p.publish(ctx, Room{Type: publication.RoomType, ID: publication.RoomID}, eventID, &OutgoingMessageEnvelope{
+		Type:    publication.Type,
+		Payload: publication.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !publication.NotifiedProblemID.Valid {
+		return nil
+	}
+
+	// The followers are looked up once the changes that caused the event are
+	// committed, e.g. the testers it assigns.
+	followerIDs, err := p.followers.GetProblemFollowers(ctx, publication.NotifiedProblemID.UUID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get problem followers")
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(followerIDs)+len(publication.RecipientIDs))
+	for _, userID := range slices.Concat(followerIDs, publication.RecipientIDs) {
+		if userID != publication.ActorID && !slices.Contains(userIDs, userID) {
+			userIDs = append(userIDs, userID)
+		}
+	}
+
+	for _, userID := range userIDs {
+		// Each personal channel numbers its events on its own. The wrapped
+		// event keeps the number it has in the room of the problem.
+		if _, err := p.publish(ctx, UserRoom(userID), eventID, &OutgoingMessageEnvelope{
+			Type:    contract.MessageTypeNotification,
+			Payload: NotificationServerPayload{Event: event.Payload},
+		}); err != nil {
+			return errors.WrapIf(err, "failed to notify follower")
+		}
+	}
+
+	return nil
+}
+
+func (p *Publisher) publish(ctx context.Context, room Room, key uuid.UUID, e *OutgoingMessageEnvelope) (*Event, error) {
+	unlock := p.lockRoom(room)
+	defer unlock()
+
+	event, err := p.repo.AppendEvent(ctx, room, key, time.Now(), func(seq uint64) ([]byte, error) {
+		e.RoomType, e.RoomID, e.Seq = room.Type, &room.ID, seq
+		return json.Marshal(e)
+	})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to store event")
+	}
+
+	// Clients skip the events they have already received, should the event
+	// be published again.
+	if err := p.backplane.Publish(ctx, event); err != nil {
+		return nil, errors.WrapIf(err, "failed to publish event")
+	}
+
+	return event, nil
+}
+
+func (p *Publisher) lockRoom(room Room) func() {
+	p.mu.Lock()
+	lock, ok := p.roomLocks[room]
+	if !ok {
+		lock = &roomLock{}
+		p.roomLocks[room] = lock
+	}
+	lock.waiters++
+	p.mu.Unlock()
+
+	lock.Lock()
+
+	return func() {
+		lock.Unlock()
+
+		p.mu.Lock()
+		lock.waiters--
+		if lock.waiters == 0 {
+			delete(p.roomLocks, room)
+		}
+		p.mu.Unlock()
+	}
+}
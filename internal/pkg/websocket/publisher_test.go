@@ -0,0 +1,143 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger/defaultlogger"
+
+	"github.com/google/uuid"
+)
+
+type fakeRepository struct {
+	mu      sync.Mutex
+	lastSeq map[Room]uint64
+	events  map[Room]map[uuid.UUID]*Event
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		lastSeq: make(map[Room]uint64),
+		events:  make(map[Room]map[uuid.UUID]*Event),
+	}
+}
+
+func (r *fakeRepository) AppendEvent(
+	_ context.Context,
+	room Room,
+	key uuid.UUID,
+	_ time.Time,
+	marshal func(seq uint64) ([]byte, error),
+) (*Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if event, ok := r.events[room][key]; ok {
+		return event, nil
+	}
+
+	payload, err := marshal(r.lastSeq[room] + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	r.lastSeq[room]++
+	event := &Event{Room: room, Seq: r.lastSeq[room], Payload: payload}
+
+	if r.events[room] == nil {
+		r.events[room] = make(map[uuid.UUID]*Event)
+	}
+	r.events[room][key] = event
+
+	return event, nil
+}
+
+func (r *fakeRepository) GetEvent(context.Context, Room, uint64) ([]byte, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) GetEventsAfter(context.Context, Room, uint64) (*EventPage, error) {
+	return &EventPage{}, nil
+}
+
+type fakeFollowers struct {
+	followerIDs []uuid.UUID
+}
+
+func (f *fakeFollowers) GetProblemFollowers(context.Context, uuid.UUID) ([]uuid.UUID, error) {
+	return f.followerIDs, nil
+}
+
+type recordingBackplane struct {
+	*MemoryBackplane
+	published []*Event
+}
+
+func (b *recordingBackplane) Publish(_ context.Context, event *Event) error {
+	b.published = append(b.published, event)
+	return nil
+}
+
+func TestPublisherRedeliveryKeepsSeq(t *testing.T) {
+	problemID, followerID := uuid.New(), uuid.New()
+	backplane := &recordingBackplane{MemoryBackplane: NewMemoryBackplane()}
+	publisher := NewPublisher(newFakeRepository(), backplane, &fakeFollowers{followerIDs: []uuid.UUID{followerID}}, defaultlogger.GetLogger())
+
+	newPayload := func() []byte {
+		t.Helper()
+
+		payload, err := json.Marshal(Publication{
+			RoomType:          RoomTypeProblem,
+			RoomID:            problemID,
+			Type:              contract.MessageTypeUser,
+			Payload:           json.RawMessage(`{}`),
+			NotifiedProblemID: uuid.NullUUID{UUID: problemID, Valid: true},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return payload
+	}
+
+	first, redelivered := uuid.New(), uuid.New()
+	for _, eventID := range []uuid.UUID{first, redelivered, redelivered} {
+		if err := publisher.HandleOutboxEvent(context.Background(), eventID, newPayload()); err != nil {
+			t.Fatalf("HandleOutboxEvent() error = %v", err)
+		}
+	}
+
+	// Each delivery publishes to the room of the problem and notifies the
+	// follower.
+	type published struct {
+		room Room
+		seq  uint64
+	}
+
+	want := []published{
+		{ProblemRoom(problemID), 1}, {UserRoom(followerID), 1},
+		{ProblemRoom(problemID), 2}, {UserRoom(followerID), 2},
+		{ProblemRoom(problemID), 2}, {UserRoom(followerID), 2},
+	}
+
+	if len(backplane.published) != len(want) {
+		t.Fatalf("published %d events, want %d", len(backplane.published), len(want))
+	}
+
+	for i, event := range backplane.published {
+		var envelope struct {
+			Seq uint64 `json:"seq"`
+		}
+		if err := json.Unmarshal(event.Payload, &envelope); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := (published{event.Room, event.Seq}); got != want[i] || envelope.Seq != want[i].seq {
+			t.Errorf("published event %d = %v with seq %d, want %v", i, got, envelope.Seq, want[i])
+		}
+	}
+}
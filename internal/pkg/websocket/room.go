@@ -0,0 +1,61 @@
+package websocket
+
+import (
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+type RoomType string
+
+const (
+	RoomTypeProblem RoomType = "problem"
+	RoomTypeContest RoomType = "contest"
+	RoomTypeUser    RoomType = "user"
+)
+
+// Room is a set of clients that receive the same broadcasts. Every client is
+// in the room of its user, its personal channel, and joins the rooms of
+// problems and contests on request.
+type Room struct {
+	Type RoomType
+	ID   uuid.UUID
+}
+
+func ProblemRoom(problemID uuid.UUID) Room {
+	return Room{Type: RoomTypeProblem, ID: problemID}
+}
+
+func ContestRoom(contestID uuid.UUID) Room {
+	return Room{Type: RoomTypeContest, ID: contestID}
+}
+
+func UserRoom(userID uuid.UUID) Room {
+	return Room{Type: RoomTypeUser, ID: userID}
+}
+
+func (r Room) String() string {
+	return string(r.Type) + ":" + r.ID.String()
+}
+
+// ParseRoom parses a room written by String.
+func ParseRoom(s string) (Room, error) {
+	roomType, id, ok := strings.Cut(s, ":")
+	if !ok {
+		return Room{}, errors.Errorf("invalid room %q", s)
+	}
+
+	switch RoomType(roomType) {
+	case RoomTypeProblem, RoomTypeContest, RoomTypeUser:
+	default:
+		return Room{}, errors.Errorf("unknown room type %q", roomType)
+	}
+
+	roomID, err := uuid.Parse(id)
+	if err != nil {
+		return Room{}, errors.WrapIf(err, "invalid room ID")
+	}
+
+	return Room{Type: RoomType(roomType), ID: roomID}, nil
+}
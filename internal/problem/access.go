@@ -0,0 +1,132 @@
+package problem
+
+import (
+	"context"
+	"slices"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+// Access holds what decides who can read a problem and its chat.
+type Access struct {
+	Status     constant.ProblemStatus
+	CreatorID  uuid.UUID
+	ReviewerID uuid.NullUUID
+	TesterIDs  []uuid.UUID
+	ContestIDs []uuid.UUID // The contests the problem targets or is assigned to
+}
+
+// NewAccess gets the access of a problem loaded with its testers and contest
+// assignments.
+func NewAccess(p *database.Problem) *Access {
+	access := &Access{
+		Status:     constant.FromStringToProblemStatus(p.Status),
+		CreatorID:  p.CreatorID,
+		ReviewerID: p.ReviewerID,
+		TesterIDs:  make([]uuid.UUID, 0, len(p.Testers)),
+		ContestIDs: make([]uuid.UUID, 0, len(p.ContestProblems)+1),
+	}
+
+	for _, tester := range p.Testers {
+		access.TesterIDs = append(access.TesterIDs, tester.UserID)
+	}
+
+	for _, contestProblem := range p.ContestProblems {
+		access.ContestIDs = append(access.ContestIDs, contestProblem.ContestID)
+	}
+
+	if p.TargetContestID.Valid {
+		access.ContestIDs = append(access.ContestIDs, p.TargetContestID.UUID)
+	}
+
+	return access
+}
+
+// ReadScope describes every problem the user can read, so that it can be
+// checked against many problems, or turned into a query, at once.
+type ReadScope struct {
+	UserID        uuid.UUID
+	All           bool
+	PendingReview bool
+	ContestIDs    []uuid.UUID // The contests in which the user's staff role can read problems
+}
+
+// GetReadScope gets the read scope of the current user.
+func GetReadScope(ctx context.Context, authProvider contract.AuthProvider) (*ReadScope, error) {
+	user, err := authProvider.MustGetUser(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get user")
+	}
+
+	return GetUserReadScope(ctx, authProvider, user.UserID)
+}
+
+// GetUserReadScope gets the read scope of any user. The people working on a
+// problem can always read it; everyone else needs a global permission, or a
+// staff role in one of the problem's contests when that is missing.
+func GetUserReadScope(ctx context.Context, authProvider contract.AuthProvider, userID uuid.UUID) (*ReadScope, error) {
+	details, err := authProvider.MustGetUserDetails(ctx, userID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get user details")
+	}
+
+	hasAny := func(permissions ...string) bool {
+		return details.IsSuperAdmin || slices.ContainsFunc(details.Permissions, func(p string) bool {
+			return slices.Contains(permissions, p)
+		})
+	}
+
+	// Listing every problem shows them, so it is enough to read them as well.
+	scope := &ReadScope{
+		UserID: userID,
+		All:    hasAny(constant.PermissionProblemReadDetailsAny, constant.PermissionProblemListAll),
+		PendingReview: hasAny(
+			constant.PermissionProblemReadDetailsAwaitingReviewAny,
+			constant.PermissionProblemListAwaitingReviewAll,
+		),
+	}
+
+	if scope.All {
+		return scope, nil
+	}
+
+	scope.ContestIDs, err = authProvider.ContestsWhereUserCan(ctx, userID, constant.PermissionProblemReadDetailsAny)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to check contest permission")
+	}
+
+	return scope, nil
+}
+
+// Allows reports whether the problem is within the scope.
+func (s *ReadScope) Allows(access *Access) bool {
+	if s.All ||
+		access.CreatorID == s.UserID ||
+		(access.ReviewerID.Valid && access.ReviewerID.UUID == s.UserID) ||
+		slices.Contains(access.TesterIDs, s.UserID) {
+		return true
+	}
+
+	if s.PendingReview && access.Status == constant.ProblemStatusPendingReview {
+		return true
+	}
+
+	return slices.ContainsFunc(access.ContestIDs, func(contestID uuid.UUID) bool {
+		return slices.Contains(s.ContestIDs, contestID)
+	})
+}
+
+// CanRead reports whether the current user can read the problem.
+func CanRead(ctx context.Context, authProvider contract.AuthProvider, access *Access) (bool, error) {
+	scope, err := GetReadScope(ctx, authProvider)
+	if err != nil {
+		return false, err
+	}
+
+	return scope.Allows(access), nil
+}
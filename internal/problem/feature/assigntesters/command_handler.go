@@ -2,7 +2,9 @@ package assigntesters
 
 import (
 	"context"
+	"time"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
@@ -23,13 +25,16 @@ type Repository interface {
 	UpdateProblemTesters(ctx context.Context, problemID uuid.UUID, testerIDs []uuid.UUID) error
 	IsProblemCompleted(ctx context.Context, problemID uuid.UUID) (bool, error)
 	DoUsersExist(ctx context.Context, userIDs []uuid.UUID) (bool, error)
+	GetProblemContestIDs(ctx context.Context, problemID uuid.UUID) ([]uuid.UUID, error)
 }
 
 type CommandHandler struct {
 	repo         Repository
 	validator    *validator.Validate
 	authProvider contract.AuthProvider
+	revoker      contract.RoomAccessRevoker
 	uowFactory   contract.UnitOfWorkFactory
+	broadcaster  contract.MessageBroadcaster
 	l            logger.Logger
 }
 
@@ -37,14 +42,18 @@ func NewCommandHandler(
 	repo Repository,
 	validator *validator.Validate,
 	authProvider contract.AuthProvider,
+	revoker contract.RoomAccessRevoker,
 	uowFactory contract.UnitOfWorkFactory,
+	broadcaster contract.MessageBroadcaster,
 	l logger.Logger,
 ) *CommandHandler {
 	return &CommandHandler{
 		repo:         repo,
 		validator:    validator,
 		authProvider: authProvider,
+		revoker:      revoker,
 		uowFactory:   uowFactory,
+		broadcaster:  broadcaster,
 		l:            l,
 	}
 }
@@ -58,15 +67,19 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) error {
 		return errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
 	}
 
-	_, err := h.authProvider.MustGetUser(ctx)
+	user, err := h.authProvider.MustGetUser(ctx)
 	if err != nil {
 		return errors.WrapIf(err, "failed to get user from auth provider")
 	}
 
-	// TODO: check if the current user has permission to assign tester or not
+	if can, err := h.canAssignTesters(ctx, command.ProblemID); err != nil {
+		return errors.WrapIf(err, "failed to check permission")
+	} else if !can {
+		return errors.WithStack(ErrForbiddenToAssignTester)
+	}
 
 	uow := h.uowFactory.New()
-	return uowhelper.Do(ctx, uow, h.l, func(ctx context.Context) error {
+	if err := uowhelper.Do(ctx, uow, h.l, func(ctx context.Context) error {
 		if ok, err := h.repo.DoUsersExist(ctx, command.TesterIDs); err != nil {
 			return errors.WrapIf(err, "failed to check if users exist")
 		} else if !ok {
@@ -83,6 +96,47 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) error {
 			return errors.WrapIf(err, "failed to update problem tester")
 		}
 
+		details, err := h.authProvider.MustGetUserDetails(ctx, user.UserID)
+		if err != nil {
+			return errors.WrapIf(err, "failed to get user details")
+		}
+
+		if err := h.broadcaster.BroadcastTestersAssignedMessage(ctx, command.ProblemID, contract.MessageUser{
+			UserID:   user.UserID,
+			Username: details.Username,
+		}, command.TesterIDs, time.Now()); err != nil {
+			return errors.WrapIf(err, "failed to broadcast testers assigned message")
+		}
+
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	// The testers taken off the problem may have no other reason to be in
+	// its room.
+	h.revoker.RecheckProblemRoom(command.ProblemID)
+
+	return nil
+}
+
+// canAssignTesters reports whether the user may assign testers to the problem,
+// either everywhere or as a staff member of one of the problem's contests.
+func (h *CommandHandler) canAssignTesters(ctx context.Context, problemID uuid.UUID) (bool, error) {
+	if can, err := h.authProvider.Can(ctx, constant.PermissionProblemAssignTesters); err != nil || can {
+		return can, err
+	}
+
+	contestIDs, err := h.repo.GetProblemContestIDs(ctx, problemID)
+	if err != nil {
+		return false, errors.WrapIf(err, "failed to get problem contests")
+	}
+
+	for _, contestID := range contestIDs {
+		if can, err := h.authProvider.CanInContest(ctx, contestID, constant.PermissionProblemAssignTesters); err != nil || can {
+			return can, err
+		}
+	}
+
+	return false, nil
 }
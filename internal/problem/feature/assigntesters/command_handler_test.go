@@ -0,0 +1,127 @@
+package assigntesters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/contracttest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger/defaultlogger"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+type fakeRepository struct {
+	contestIDs []uuid.UUID
+	updated    bool
+}
+
+func (r *fakeRepository) UpdateProblemTesters(context.Context, uuid.UUID, []uuid.UUID) error {
+	r.updated = true
+	return nil
+}
+
+func (r *fakeRepository) IsProblemCompleted(context.Context, uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeRepository) DoUsersExist(context.Context, []uuid.UUID) (bool, error) { return true, nil }
+
+func (r *fakeRepository) GetProblemContestIDs(context.Context, uuid.UUID) ([]uuid.UUID, error) {
+	return r.contestIDs, nil
+}
+
+type fakeUnitOfWork struct{}
+
+func (u fakeUnitOfWork) Begin(ctx context.Context) (context.Context, error) { return ctx, nil }
+
+func (u fakeUnitOfWork) Commit() error { return nil }
+
+func (u fakeUnitOfWork) Rollback() error { return nil }
+
+func (u fakeUnitOfWork) New() contract.UnitOfWork { return u }
+
+type fakeBroadcaster struct {
+	contract.MessageBroadcaster
+}
+
+func (fakeBroadcaster) BroadcastTestersAssignedMessage(
+	context.Context,
+	uuid.UUID,
+	contract.MessageUser,
+	[]uuid.UUID,
+	time.Time,
+) error {
+	return nil
+}
+
+type fakeRevoker struct{}
+
+func (fakeRevoker) RecheckProblemRoom(uuid.UUID) {}
+
+func (fakeRevoker) RecheckUserRooms(uuid.UUID) {}
+
+func TestHandleChecksPermission(t *testing.T) {
+	contestID := uuid.New()
+
+	tests := []struct {
+		name               string
+		permissions        []string
+		contestPermissions map[uuid.UUID][]string
+		wantAllowed        bool
+	}{
+		{name: "no permission", wantAllowed: false},
+		{name: "global permission", permissions: []string{constant.PermissionProblemAssignTesters}, wantAllowed: true},
+		{
+			name:               "coordinator of the problem's contest",
+			contestPermissions: map[uuid.UUID][]string{contestID: {constant.PermissionProblemAssignTesters}},
+			wantAllowed:        true,
+		},
+		{
+			name:               "coordinator of another contest",
+			contestPermissions: map[uuid.UUID][]string{uuid.New(): {constant.PermissionProblemAssignTesters}},
+			wantAllowed:        false,
+		},
+		{
+			name:               "tester of the problem's contest",
+			contestPermissions: map[uuid.UUID][]string{contestID: {constant.PermissionProblemTestAssigned}},
+			wantAllowed:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			authProvider := contracttest.NewAuthProvider(userID, &contract.AuthUserDetails{Permissions: tt.permissions})
+			for id, permissions := range tt.contestPermissions {
+				authProvider.GrantInContest(userID, id, permissions...)
+			}
+
+			repo := &fakeRepository{contestIDs: []uuid.UUID{contestID}}
+			handler := NewCommandHandler(
+				repo,
+				validator.New(),
+				authProvider,
+				fakeRevoker{},
+				fakeUnitOfWork{},
+				fakeBroadcaster{},
+				defaultlogger.GetLogger(),
+			)
+
+			err := handler.Handle(context.Background(), &Command{ProblemID: uuid.New(), TesterIDs: []uuid.UUID{uuid.New()}})
+			if tt.wantAllowed && err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			} else if !tt.wantAllowed && !errors.Is(err, ErrForbiddenToAssignTester) {
+				t.Fatalf("Handle() error = %v, want %v", err, ErrForbiddenToAssignTester)
+			}
+
+			if repo.updated != tt.wantAllowed {
+				t.Errorf("updated = %v, want %v", repo.updated, tt.wantAllowed)
+			}
+		})
+	}
+}
@@ -33,6 +33,20 @@ func (r *GormRepository) DoUsersExist(ctx context.Context, userIDs []uuid.UUID)
 	return int(count) == len(userIDs), nil
 }
 
+func (r *GormRepository) GetProblemContestIDs(ctx context.Context, problemID uuid.UUID) ([]uuid.UUID, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var contestIDs []uuid.UUID
+	if err := db.WithContext(ctx).
+		Model(&database.ContestProblem{}).
+		Where("problem_id = ?", problemID).
+		Pluck("contest_id", &contestIDs).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get problem contests")
+	}
+
+	return contestIDs, nil
+}
+
 func (r *GormRepository) UpdateProblemTesters(ctx context.Context, problemID uuid.UUID, testerIDs []uuid.UUID) error {
 	db := database.GetDBFromContext(ctx, r.db)
 
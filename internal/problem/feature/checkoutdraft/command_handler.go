@@ -36,8 +36,25 @@ type Repository interface {
 type VersionAggregate struct {
 	ProblemVersionID    uuid.UUID
 	ProblemDifficultyID uuid.UUID
+	TimeLimitMs         uint
+	MemoryLimitMb       uint
+	IsInteractive       bool
+	InputFile           string
+	OutputFile          string
+	CheckerType         string
+	CheckerEpsilon      float64
+	CheckerLanguage     string
+	CheckerSource       string
+	InteractorLanguage  string
+	InteractorSource    string
+	ValidatorLanguage   string
+	ValidatorSource     string
+	GeneratorScript     string
+	Generators          []VersionGenerator
 	Details             []VersionDetail
 	Examples            []VersionExample
+	Solutions           []VersionSolution
+	Testcases           []VersionTestcase
 }
 
 type VersionDetail struct {
@@ -55,6 +72,28 @@ type VersionExample struct {
 	Output string
 }
 
+type VersionSolution struct {
+	Name            string
+	Language        string
+	ExpectedVerdict string
+	IsMain          bool
+	Source          string
+}
+
+type VersionGenerator struct {
+	Name     string
+	Language string
+	Source   string
+}
+
+type VersionTestcase struct {
+	Name       string
+	InputHash  string
+	InputSize  uint64
+	OutputHash string
+	OutputSize uint64
+}
+
 type CommandHandler struct {
 	repo         Repository
 	authProvider contract.AuthProvider
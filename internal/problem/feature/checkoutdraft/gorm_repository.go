@@ -48,6 +48,11 @@ func (r *GormRepository) GetLatestVersion(ctx context.Context, problemID uuid.UU
 	if err := db.WithContext(ctx).
 		Preload("Details").
 		Preload("Examples").
+		Preload("Solutions").
+		Preload("Generators").
+		Preload("Testcases", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
 		Where("problem_id = ?", problemID).
 		Order("created_at DESC").
 		First(&version).Error; err != nil {
@@ -60,8 +65,25 @@ func (r *GormRepository) GetLatestVersion(ctx context.Context, problemID uuid.UU
 	v := &VersionAggregate{
 		ProblemVersionID:    version.ProblemVersionID,
 		ProblemDifficultyID: version.ProblemDifficultyID,
+		TimeLimitMs:         version.TimeLimitMs,
+		MemoryLimitMb:       version.MemoryLimitMb,
+		IsInteractive:       version.IsInteractive,
+		InputFile:           version.InputFile,
+		OutputFile:          version.OutputFile,
+		CheckerType:         version.CheckerType,
+		CheckerEpsilon:      version.CheckerEpsilon,
+		CheckerLanguage:     version.CheckerLanguage,
+		CheckerSource:       version.CheckerSource,
+		InteractorLanguage:  version.InteractorLanguage,
+		InteractorSource:    version.InteractorSource,
+		ValidatorLanguage:   version.ValidatorLanguage,
+		ValidatorSource:     version.ValidatorSource,
+		GeneratorScript:     version.GeneratorScript,
+		Generators:          make([]VersionGenerator, len(version.Generators)),
 		Details:             make([]VersionDetail, len(version.Details)),
 		Examples:            make([]VersionExample, len(version.Examples)),
+		Solutions:           make([]VersionSolution, len(version.Solutions)),
+		Testcases:           make([]VersionTestcase, len(version.Testcases)),
 	}
 
 	for i, detail := range version.Details {
@@ -83,6 +105,34 @@ func (r *GormRepository) GetLatestVersion(ctx context.Context, problemID uuid.UU
 		}
 	}
 
+	for i, solution := range version.Solutions {
+		v.Solutions[i] = VersionSolution{
+			Name:            solution.Name,
+			Language:        solution.Language,
+			ExpectedVerdict: solution.ExpectedVerdict,
+			IsMain:          solution.IsMain,
+			Source:          solution.Source,
+		}
+	}
+
+	for i, generator := range version.Generators {
+		v.Generators[i] = VersionGenerator{
+			Name:     generator.Name,
+			Language: generator.Language,
+			Source:   generator.Source,
+		}
+	}
+
+	for i, testcase := range version.Testcases {
+		v.Testcases[i] = VersionTestcase{
+			Name:       testcase.Name,
+			InputHash:  testcase.InputHash,
+			InputSize:  testcase.InputSize,
+			OutputHash: testcase.OutputHash,
+			OutputSize: testcase.OutputSize,
+		}
+	}
+
 	return v, nil
 }
 
@@ -103,6 +153,18 @@ func (r *GormRepository) ReplaceDraftFromVersion(
 			return errors.WrapIf(err, "failed to delete draft examples")
 		}
 
+		if err := tx.Where("problem_draft_id = ?", draftID).Delete(&database.ProblemDraftSolution{}).Error; err != nil {
+			return errors.WrapIf(err, "failed to delete draft solutions")
+		}
+
+		if err := tx.Where("problem_draft_id = ?", draftID).Delete(&database.ProblemDraftGenerator{}).Error; err != nil {
+			return errors.WrapIf(err, "failed to delete draft generators")
+		}
+
+		if err := tx.Where("problem_draft_id = ?", draftID).Delete(&database.ProblemDraftTestcase{}).Error; err != nil {
+			return errors.WrapIf(err, "failed to delete draft testcases")
+		}
+
 		details := make([]database.ProblemDraftDetail, len(version.Details))
 		for i, detail := range version.Details {
 			details[i] = database.ProblemDraftDetail{
@@ -140,9 +202,79 @@ func (r *GormRepository) ReplaceDraftFromVersion(
 			}
 		}
 
+		solutions := make([]database.ProblemDraftSolution, len(version.Solutions))
+		for i, solution := range version.Solutions {
+			solutions[i] = database.ProblemDraftSolution{
+				SolutionID:      uuid.Must(uuid.NewV7()),
+				ProblemDraftID:  draftID,
+				Name:            solution.Name,
+				Language:        solution.Language,
+				ExpectedVerdict: solution.ExpectedVerdict,
+				IsMain:          solution.IsMain,
+				Source:          solution.Source,
+			}
+		}
+
+		if len(solutions) > 0 {
+			if err := tx.Create(&solutions).Error; err != nil {
+				return errors.WrapIf(err, "failed to insert draft solutions")
+			}
+		}
+
+		generators := make([]database.ProblemDraftGenerator, len(version.Generators))
+		for i, generator := range version.Generators {
+			generators[i] = database.ProblemDraftGenerator{
+				GeneratorID:    uuid.Must(uuid.NewV7()),
+				ProblemDraftID: draftID,
+				Name:           generator.Name,
+				Language:       generator.Language,
+				Source:         generator.Source,
+			}
+		}
+
+		if len(generators) > 0 {
+			if err := tx.Create(&generators).Error; err != nil {
+				return errors.WrapIf(err, "failed to insert draft generators")
+			}
+		}
+
+		testcases := make([]database.ProblemDraftTestcase, len(version.Testcases))
+		for i, testcase := range version.Testcases {
+			testcases[i] = database.ProblemDraftTestcase{
+				TestcaseID:     uuid.Must(uuid.NewV7()),
+				ProblemDraftID: draftID,
+				Position:       i,
+				Name:           testcase.Name,
+				InputHash:      testcase.InputHash,
+				InputSize:      testcase.InputSize,
+				OutputHash:     testcase.OutputHash,
+				OutputSize:     testcase.OutputSize,
+			}
+		}
+
+		if len(testcases) > 0 {
+			if err := tx.Create(&testcases).Error; err != nil {
+				return errors.WrapIf(err, "failed to insert draft testcases")
+			}
+		}
+
 		update := map[string]interface{}{
-			"is_active":  true,
-			"updated_at": updatedAt,
+			"is_active":           true,
+			"time_limit_ms":       version.TimeLimitMs,
+			"memory_limit_mb":     version.MemoryLimitMb,
+			"is_interactive":      version.IsInteractive,
+			"input_file":          version.InputFile,
+			"output_file":         version.OutputFile,
+			"checker_type":        version.CheckerType,
+			"checker_epsilon":     version.CheckerEpsilon,
+			"checker_language":    version.CheckerLanguage,
+			"checker_source":      version.CheckerSource,
+			"interactor_language": version.InteractorLanguage,
+			"interactor_source":   version.InteractorSource,
+			"validator_language":  version.ValidatorLanguage,
+			"validator_source":    version.ValidatorSource,
+			"generator_script":    version.GeneratorScript,
+			"updated_at":          updatedAt,
 		}
 
 		if version.ProblemDifficultyID != uuid.Nil {
@@ -173,6 +305,11 @@ func (r *GormRepository) GetProblemDraft(ctx context.Context, draftID uuid.UUID)
 		Preload("ProblemDifficulty.DisplayNames").
 		Preload("Details").
 		Preload("Examples").
+		Preload("Solutions").
+		Preload("Generators").
+		Preload("Testcases", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
 		Preload("SubmittedProblem").
 		Where("problem_draft_id = ?", draftID).
 		First(&draft).Error; err != nil {
@@ -0,0 +1,74 @@
+package exportproblem
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/httperror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/problemexport"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
+
+	"emperror.dev/errors"
+	"github.com/labstack/echo/v4"
+)
+
+type Endpoint struct {
+	*problem.EndpointParams
+	handler *QueryHandler
+}
+
+func NewEndpoint(params *problem.EndpointParams, handler *QueryHandler) *Endpoint {
+	return &Endpoint{
+		EndpointParams: params,
+		handler:        handler,
+	}
+}
+
+func (e *Endpoint) MapEndpoint() {
+	e.ProblemsGroup.GET("/:problem_id/export", e.handle())
+}
+
+func (e *Endpoint) handle() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		query := &Query{}
+		if err := ctx.Bind(query); err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid request format")
+		}
+
+		if err := ctx.Validate(query); err != nil {
+			return err
+		}
+
+		response, err := e.handler.Handle(ctx.Request().Context(), query)
+		var unsupportedErr *problemexport.UnsupportedError
+		if errors.Is(err, customerror.ErrValidationFailed) {
+			return httperror.New(http.StatusBadRequest, err.Error())
+		} else if errors.Is(err, problem.ErrProblemNotFound) {
+			return httperror.New(http.StatusNotFound, "Problem not found")
+		} else if errors.Is(err, ErrProblemNotCompleted) {
+			return httperror.New(http.StatusConflict, "Only completed problems can be exported")
+		} else if errors.Is(err, ErrNoApprovedVersion) {
+			return httperror.New(http.StatusConflict, "The problem has no approved version")
+		} else if errors.As(err, &unsupportedErr) {
+			return httperror.New(http.StatusUnprocessableEntity, unsupportedErr.Reason)
+		} else if err != nil {
+			return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
+		}
+
+		archive, err := problemexport.Stage(ctx.Request().Context(), response.Write)
+		if err != nil {
+			return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
+		}
+		defer archive.Close()
+
+		header := ctx.Response().Header()
+		header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{
+			"filename": response.FileName,
+		}))
+		header.Set(echo.HeaderContentLength, strconv.FormatInt(archive.Size, 10))
+
+		return ctx.Stream(http.StatusOK, "application/zip", archive)
+	}
+}
@@ -0,0 +1,71 @@
+package exportproblem
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/problemexport"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) GetProblemStatus(ctx context.Context, problemID uuid.UUID) (constant.ProblemStatus, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var p database.Problem
+	if err := db.WithContext(ctx).
+		Select("status").
+		Where("problem_id = ?", problemID).
+		First(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", errors.WithStack(problem.ErrProblemNotFound)
+		}
+
+		return "", errors.WrapIf(err, "failed to get problem status")
+	}
+
+	return constant.FromStringToProblemStatus(p.Status), nil
+}
+
+func (r *GormRepository) GetLatestApprovedVersion(
+	ctx context.Context,
+	problemID uuid.UUID,
+) (*problemexport.Problem, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var version database.ProblemVersion
+	if err := db.WithContext(ctx).
+		Joins("JOIN problem_reviews ON problem_reviews.version_id = problem_versions.problem_version_id").
+		Where("problem_versions.problem_id = ? AND problem_reviews.decision = ?", problemID, "approve").
+		Preload("Details").
+		Preload("Examples").
+		Preload("Testcases", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Preload("Solutions").
+		Order("problem_versions.created_at DESC").
+		First(&version).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, errors.WrapIf(err, "failed to get latest approved version")
+	}
+
+	p := problemexport.FromGormProblemVersion(version)
+	return &p, nil
+}
@@ -0,0 +1,8 @@
+package exportproblem
+
+import "github.com/google/uuid"
+
+type Query struct {
+	ProblemID uuid.UUID `param:"problem_id" validate:"required"`
+	Format    string    `query:"format"     validate:"required,oneof=domjudge kattis"`
+}
@@ -0,0 +1,103 @@
+package exportproblem
+
+import (
+	"context"
+	"io"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/judge"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/problemexport"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrProblemNotCompleted = errors.New("problem is not completed")
+	ErrNoApprovedVersion   = errors.New("problem has no approved version")
+)
+
+type Repository interface {
+	GetProblemStatus(ctx context.Context, problemID uuid.UUID) (constant.ProblemStatus, error)
+	// GetLatestApprovedVersion returns nil if no version of the problem has
+	// been approved.
+	GetLatestApprovedVersion(ctx context.Context, problemID uuid.UUID) (*problemexport.Problem, error)
+}
+
+type QueryHandler struct {
+	repo         Repository
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+	blobStore    contract.BlobStore
+	judgeOpts    *judge.Options
+}
+
+func NewQueryHandler(
+	repo Repository,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+	blobStore contract.BlobStore,
+	judgeOpts *judge.Options,
+) *QueryHandler {
+	return &QueryHandler{
+		repo:         repo,
+		validator:    validator,
+		authProvider: authProvider,
+		blobStore:    blobStore,
+		judgeOpts:    judgeOpts,
+	}
+}
+
+func (h *QueryHandler) Handle(ctx context.Context, query *Query) (*Response, error) {
+	if query == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(query); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	format, err := problemexport.ParseFormat(query.Format)
+	if err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	can, err := h.authProvider.Can(ctx, constant.PermissionProblemExportAny)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to check permission for exporting problems")
+	}
+
+	if !can {
+		return nil, customerror.NewNoPermissionError(constant.PermissionProblemExportAny)
+	}
+
+	if status, err := h.repo.GetProblemStatus(ctx, query.ProblemID); err != nil {
+		return nil, errors.WrapIf(err, "failed to get problem status")
+	} else if status != constant.ProblemStatusCompleted {
+		return nil, errors.WithStack(ErrProblemNotCompleted)
+	}
+
+	problem, err := h.repo.GetLatestApprovedVersion(ctx, query.ProblemID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get latest approved version")
+	} else if problem == nil {
+		return nil, errors.WithStack(ErrNoApprovedVersion)
+	}
+
+	// Reject problems the package cannot represent before the response is
+	// committed.
+	if err := problemexport.Check(*problem); err != nil {
+		return nil, err
+	}
+
+	writer := problemexport.NewWriter(format, h.blobStore, h.judgeOpts.TestlibDir)
+	return &Response{
+		FileName: problem.ShortName + "-" + string(format) + ".zip",
+		Write: func(ctx context.Context, w io.Writer) error {
+			return writer.WriteProblem(ctx, w, *problem)
+		},
+	}, nil
+}
@@ -3,6 +3,7 @@ package getproblem
 import (
 	"net/http"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/httperror"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
 
@@ -38,7 +39,10 @@ func (e *Endpoint) handle() echo.HandlerFunc {
 		}
 
 		response, err := e.handler.Handle(ctx.Request().Context(), query)
-		if errors.Is(err, problem.ErrProblemNotFound) {
+		if errors.Is(err, customerror.ErrBaseNoPermission) ||
+			errors.Is(err, customerror.ErrCommandNil) {
+			return err
+		} else if errors.Is(err, problem.ErrProblemNotFound) {
 			return httperror.New(http.StatusNotFound, "Problem not found")
 		} else if err != nil {
 			return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
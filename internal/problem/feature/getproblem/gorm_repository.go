@@ -40,14 +40,23 @@ func (r *GormRepository) GetProblem(
 		}).
 		Preload("ProblemVersions.Details").
 		Preload("ProblemVersions.Examples").
+		Preload("ProblemVersions.Testcases", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
 		Preload("ProblemVersions.Review").
 		Preload("ProblemVersions.Review.Reviewer").
 		Preload("ProblemVersions.TestResults").
 		Preload("ProblemVersions.TestResults.Tester").
+		Preload("ProblemVersions.TestResults.SolutionResults").
+		Preload("ProblemVersions.Solutions").
+		Preload("ProblemVersions.Generators").
 		Preload("ProblemVersions.ProblemDifficulty").
 		Preload("ProblemVersions.ProblemDifficulty.DisplayNames").
 		Preload("TargetContest").
-		Preload("AssignedContest").
+		Preload("ContestProblems", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("ContestProblems.Contest").
 		Where("problem_id = ?", problemID).
 		First(&p).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -67,19 +76,56 @@ func (r *GormRepository) GetProblem(
 			UserID:   p.Creator.UserID,
 			Username: p.Creator.Username,
 		},
-		Testers:   make([]ResponseUser, 0, len(p.Testers)),
-		CreatedAt: p.CreatedAt,
-		UpdatedAt: p.UpdatedAt,
+		Testers:          make([]ResponseUser, 0, len(p.Testers)),
+		AssignedContests: make([]ResponseContest, 0, len(p.ContestProblems)),
+		CreatedAt:        p.CreatedAt,
+		UpdatedAt:        p.UpdatedAt,
 	}
 
 	for _, version := range p.ProblemVersions {
 		v := ResponseProblemVersion{
 			VersionID:         version.ProblemVersionID,
 			ProblemDifficulty: dto.FromGormProblemDifficulty(version.ProblemDifficulty),
-			Details:           make([]ResponseProblemDetail, 0, len(version.Details)),
-			Examples:          make([]ResponseProblemExample, 0, len(version.Examples)),
-			TestResults:       make([]ResponseTestResult, 0, len(version.TestResults)),
-			CreatedAt:         version.CreatedAt,
+			TimeLimitMs:       version.TimeLimitMs,
+			MemoryLimitMb:     version.MemoryLimitMb,
+			IsInteractive:     version.IsInteractive,
+			InputFile:         version.InputFile,
+			OutputFile:        version.OutputFile,
+			Checker: ResponseChecker{
+				Type:     version.CheckerType,
+				Epsilon:  version.CheckerEpsilon,
+				Language: version.CheckerLanguage,
+				Source:   version.CheckerSource,
+			},
+			Details:       make([]ResponseProblemDetail, 0, len(version.Details)),
+			Examples:      make([]ResponseProblemExample, 0, len(version.Examples)),
+			Solutions:     make([]ResponseSolution, 0, len(version.Solutions)),
+			TestcaseCount: len(version.Testcases),
+			Testcases:     make([]ResponseTestcase, 0, len(version.Testcases)),
+			TestResults:   make([]ResponseTestResult, 0, len(version.TestResults)),
+			CreatedAt:     version.CreatedAt,
+		}
+
+		if version.InteractorSource != "" {
+			v.Interactor = &ResponseInteractor{
+				Language: version.InteractorLanguage,
+				Source:   version.InteractorSource,
+			}
+		}
+
+		if version.ValidatorSource != "" {
+			v.Validator = &ResponseValidator{
+				Language: version.ValidatorLanguage,
+				Source:   version.ValidatorSource,
+			}
+		}
+
+		for _, generator := range version.Generators {
+			v.Generators = append(v.Generators, ResponseGenerator{
+				Name:     generator.Name,
+				Language: generator.Language,
+				Source:   generator.Source,
+			})
 		}
 
 		if version.Review != nil {
@@ -91,15 +137,41 @@ func (r *GormRepository) GetProblem(
 			}
 		}
 
-		for _, testResult := range version.TestResults {
-			v.TestResults = append(v.TestResults, ResponseTestResult{
-				TesterID:  testResult.Tester.UserID,
-				Comment:   testResult.Comment,
-				Status:    testResult.Status,
-				CreatedAt: testResult.CreatedAt,
+		expectedVerdicts := make(map[uuid.UUID]string, len(version.Solutions))
+		for _, solution := range version.Solutions {
+			expectedVerdicts[solution.SolutionID] = solution.ExpectedVerdict
+			v.Solutions = append(v.Solutions, ResponseSolution{
+				SolutionID:      solution.SolutionID,
+				Name:            solution.Name,
+				Language:        solution.Language,
+				ExpectedVerdict: solution.ExpectedVerdict,
+				IsMain:          solution.IsMain,
+				Source:          solution.Source,
 			})
 		}
 
+		for _, testResult := range version.TestResults {
+			r := ResponseTestResult{
+				TesterID:        testResult.Tester.UserID,
+				Comment:         testResult.Comment,
+				Status:          testResult.Status,
+				SolutionResults: make([]ResponseSolutionResult, 0, len(testResult.SolutionResults)),
+				CreatedAt:       testResult.CreatedAt,
+			}
+
+			for _, solutionResult := range testResult.SolutionResults {
+				expected := expectedVerdicts[solutionResult.SolutionID]
+				r.SolutionResults = append(r.SolutionResults, ResponseSolutionResult{
+					SolutionID:      solutionResult.SolutionID,
+					ExpectedVerdict: expected,
+					ActualVerdict:   solutionResult.ActualVerdict,
+					Matches:         constant.Verdict(expected).Matches(constant.Verdict(solutionResult.ActualVerdict)),
+				})
+			}
+
+			v.TestResults = append(v.TestResults, r)
+		}
+
 		for _, detail := range version.Details {
 			v.Details = append(v.Details, ResponseProblemDetail{
 				Language:     detail.Language,
@@ -119,6 +191,17 @@ func (r *GormRepository) GetProblem(
 			})
 		}
 
+		for _, testcase := range version.Testcases {
+			v.TestcaseTotalSize += testcase.InputSize + testcase.OutputSize
+			v.Testcases = append(v.Testcases, ResponseTestcase{
+				Name:       testcase.Name,
+				InputHash:  testcase.InputHash,
+				InputSize:  testcase.InputSize,
+				OutputHash: testcase.OutputHash,
+				OutputSize: testcase.OutputSize,
+			})
+		}
+
 		result.Versions = append(result.Versions, v)
 	}
 
@@ -143,12 +226,119 @@ func (r *GormRepository) GetProblem(
 		}
 	}
 
-	if p.AssignedContest != nil {
-		result.AssignedContest = &ResponseContest{
-			ContestID: p.AssignedContest.ContestID,
-			Title:     p.AssignedContest.Title,
+	// The assignments are ordered oldest first, so the last one is the
+	// problem's current contest.
+	for i, contestProblem := range p.ContestProblems {
+		assigned := ResponseContest{
+			ContestID: contestProblem.Contest.ContestID,
+			Title:     contestProblem.Contest.Title,
+			Label:     contestProblem.Label,
+		}
+
+		result.AssignedContests = append(result.AssignedContests, assigned)
+		if i == len(p.ContestProblems)-1 {
+			result.AssignedContest = &assigned
 		}
 	}
 
 	return result, nil
 }
+
+func (r *GormRepository) GetProblemAccess(ctx context.Context, problemID uuid.UUID) (*problem.Access, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var p database.Problem
+	if err := db.WithContext(ctx).
+		Model(&database.Problem{}).
+		Preload("Testers").
+		Preload("ContestProblems").
+		Where("problem_id = ?", problemID).
+		First(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithStack(problem.ErrProblemNotFound)
+		}
+
+		return nil, errors.WrapIf(err, "failed to get problem access")
+	}
+
+	return problem.NewAccess(&p), nil
+}
+
+func (r *GormRepository) GetPotentialDuplicates(
+	ctx context.Context,
+	problemVersionID uuid.UUID,
+) ([]PotentialDuplicate, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var similarities []database.ProblemSimilarity
+	if err := db.WithContext(ctx).
+		Preload("SimilarProblem").
+		Preload("SimilarProblem.Testers").
+		Preload("SimilarProblem.ContestProblems").
+		Where("problem_version_id = ?", problemVersionID).
+		Find(&similarities).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get problem similarities")
+	}
+
+	if len(similarities) == 0 {
+		return make([]PotentialDuplicate, 0), nil
+	}
+
+	similarProblemIDs := make([]uuid.UUID, 0, len(similarities))
+	for _, similarity := range similarities {
+		similarProblemIDs = append(similarProblemIDs, similarity.SimilarProblemID)
+	}
+
+	type titleRow struct {
+		ProblemID uuid.UUID
+		Language  string
+		Title     string
+	}
+
+	var titles []titleRow
+	if err := db.WithContext(ctx).
+		Table("problem_version_details d").
+		Joins("JOIN problem_versions v ON v.problem_version_id = d.problem_version_id").
+		Select("v.problem_id, d.language, d.title").
+		Where("v.problem_id IN ?", similarProblemIDs).
+		Where(`v.problem_version_id = (
+			SELECT lpv.problem_version_id
+			FROM problem_versions lpv
+			WHERE lpv.problem_id = v.problem_id
+			ORDER BY lpv.created_at DESC
+			LIMIT 1
+		)`).
+		Scan(&titles).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get titles of similar problems")
+	}
+
+	titlesByProblemID := make(map[uuid.UUID][]ResponseProblemTitle, len(similarities))
+	for _, title := range titles {
+		titlesByProblemID[title.ProblemID] = append(titlesByProblemID[title.ProblemID], ResponseProblemTitle{
+			Language: title.Language,
+			Title:    title.Title,
+		})
+	}
+
+	duplicates := make([]PotentialDuplicate, 0, len(similarities))
+	for _, similarity := range similarities {
+		duplicate := PotentialDuplicate{
+			ResponsePotentialDuplicate: ResponsePotentialDuplicate{
+				ProblemID:           similarity.SimilarProblemID,
+				Titles:              titlesByProblemID[similarity.SimilarProblemID],
+				Status:              constant.FromStringToProblemStatus(similarity.SimilarProblem.Status),
+				StatementSimilarity: similarity.StatementSimilarity,
+				ExampleSimilarity:   similarity.ExampleSimilarity,
+			},
+			Access: problem.NewAccess(&similarity.SimilarProblem),
+		}
+
+		if duplicate.Titles == nil {
+			duplicate.Titles = make([]ResponseProblemTitle, 0)
+		}
+
+		duplicates = append(duplicates, duplicate)
+	}
+
+	return duplicates, nil
+}
@@ -1,17 +1,30 @@
 package getproblem
 
 import (
+	"cmp"
 	"context"
+	"slices"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
 
 	"emperror.dev/errors"
 	"github.com/google/uuid"
 )
 
+// PotentialDuplicate comes with the access of the similar problem, as only the
+// problems the user can read are shown.
+type PotentialDuplicate struct {
+	ResponsePotentialDuplicate
+	Access *problem.Access
+}
+
 type Repository interface {
+	GetProblemAccess(ctx context.Context, problemID uuid.UUID) (*problem.Access, error)
 	GetProblem(ctx context.Context, problemID uuid.UUID) (*ResponseProblem, error)
+	GetPotentialDuplicates(ctx context.Context, problemVersionID uuid.UUID) ([]PotentialDuplicate, error)
 }
 
 type QueryHandler struct {
@@ -31,12 +44,43 @@ func (q *QueryHandler) Handle(ctx context.Context, query *Query) (*Response, err
 		return nil, errors.WithStack(customerror.ErrCommandNil)
 	}
 
-	problem, err := q.repo.GetProblem(ctx, query.ProblemID)
+	access, err := q.repo.GetProblemAccess(ctx, query.ProblemID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get problem access")
+	}
+
+	scope, err := problem.GetReadScope(ctx, q.authProvider)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get read scope")
+	} else if !scope.Allows(access) {
+		return nil, customerror.NewNoPermissionError(constant.PermissionProblemReadDetailsAny)
+	}
+
+	p, err := q.repo.GetProblem(ctx, query.ProblemID)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to get problem")
 	}
 
+	duplicates, err := q.repo.GetPotentialDuplicates(ctx, p.LatestVersionID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get potential duplicates")
+	}
+
+	p.PotentialDuplicates = make([]ResponsePotentialDuplicate, 0, len(duplicates))
+	for _, duplicate := range duplicates {
+		if scope.Allows(duplicate.Access) {
+			p.PotentialDuplicates = append(p.PotentialDuplicates, duplicate.ResponsePotentialDuplicate)
+		}
+	}
+
+	slices.SortStableFunc(p.PotentialDuplicates, func(a, b ResponsePotentialDuplicate) int {
+		return cmp.Compare(
+			max(b.StatementSimilarity, b.ExampleSimilarity),
+			max(a.StatementSimilarity, a.ExampleSimilarity),
+		)
+	})
+
 	return &Response{
-		Problem: *problem,
+		Problem: *p,
 	}, nil
 }
@@ -22,6 +22,7 @@ type ResponseUser struct {
 type ResponseContest struct {
 	ContestID uuid.UUID `json:"contest_id"`
 	Title     string    `json:"title"`
+	Label     string    `json:"label,omitempty"` // Only set for the assigned contest
 }
 
 type ResponseProblemDetail struct {
@@ -39,6 +40,14 @@ type ResponseProblemExample struct {
 	Output string `json:"output"`
 }
 
+type ResponseTestcase struct {
+	Name       string `json:"name"`
+	InputHash  string `json:"input_hash"`
+	InputSize  uint64 `json:"input_size"`
+	OutputHash string `json:"output_hash"`
+	OutputSize uint64 `json:"output_size"`
+}
+
 type ResponseReview struct {
 	ReviewerID uuid.UUID `json:"reviewer_id"`
 	Comment    string    `json:"comment"`
@@ -46,36 +55,102 @@ type ResponseReview struct {
 	CreatedAt  time.Time `json:"created_at"`
 }
 
+type ResponseSolution struct {
+	SolutionID      uuid.UUID `json:"solution_id"`
+	Name            string    `json:"name"`
+	Language        string    `json:"language"`
+	ExpectedVerdict string    `json:"expected_verdict"`
+	IsMain          bool      `json:"is_main"`
+	Source          string    `json:"source"`
+}
+
+type ResponseSolutionResult struct {
+	SolutionID      uuid.UUID `json:"solution_id"`
+	ExpectedVerdict string    `json:"expected_verdict"`
+	ActualVerdict   string    `json:"actual_verdict"`
+	Matches         bool      `json:"matches"`
+}
+
 type ResponseTestResult struct {
-	TesterID  uuid.UUID `json:"tester_id"`
-	Status    string    `json:"status"`
-	Comment   string    `json:"comment"`
-	CreatedAt time.Time `json:"created_at"`
+	TesterID        uuid.UUID                `json:"tester_id"`
+	Status          string                   `json:"status"`
+	Comment         string                   `json:"comment"`
+	SolutionResults []ResponseSolutionResult `json:"solution_results"`
+	CreatedAt       time.Time                `json:"created_at"`
+}
+
+type ResponseChecker struct {
+	Type     string  `json:"type"`
+	Epsilon  float64 `json:"epsilon,omitempty"`
+	Language string  `json:"language,omitempty"`
+	Source   string  `json:"source,omitempty"`
+}
+
+type ResponseInteractor struct {
+	Language string `json:"language"`
+	Source   string `json:"source"`
+}
+
+type ResponseValidator struct {
+	Language string `json:"language"`
+	Source   string `json:"source"`
+}
+
+type ResponseGenerator struct {
+	Name     string `json:"name"`
+	Language string `json:"language"`
+	Source   string `json:"source"`
 }
 
 type ResponseProblemVersion struct {
 	VersionID         uuid.UUID                `json:"version_id"`
 	ProblemDifficulty dto.ProblemDifficulty    `json:"problem_difficulty"`
+	TimeLimitMs       uint                     `json:"time_limit_ms"`
+	MemoryLimitMb     uint                     `json:"memory_limit_mb"`
+	IsInteractive     bool                     `json:"is_interactive"`
+	InputFile         string                   `json:"input_file"`
+	OutputFile        string                   `json:"output_file"`
+	Checker           ResponseChecker          `json:"checker"`
+	Interactor        *ResponseInteractor      `json:"interactor"`
+	Validator         *ResponseValidator       `json:"validator"`
+	Generators        []ResponseGenerator      `json:"generators"`
+	GeneratorScript   string                   `json:"generator_script"`
 	Details           []ResponseProblemDetail  `json:"details"`
 	Examples          []ResponseProblemExample `json:"examples"`
+	Solutions         []ResponseSolution       `json:"solutions"`
+	TestcaseCount     int                      `json:"testcase_count"`
+	TestcaseTotalSize uint64                   `json:"testcase_total_size"`
+	Testcases         []ResponseTestcase       `json:"testcases"`
 	Review            *ResponseReview          `json:"review"`
 	TestResults       []ResponseTestResult     `json:"test_results"`
 	CreatedAt         time.Time                `json:"created_at"`
 }
 
+// ResponsePotentialDuplicate is another problem found similar to the latest
+// version when it was submitted.
+type ResponsePotentialDuplicate struct {
+	ProblemID           uuid.UUID              `json:"problem_id"`
+	Titles              []ResponseProblemTitle `json:"titles"`
+	Status              constant.ProblemStatus `json:"status"`
+	StatementSimilarity float64                `json:"statement_similarity"`
+	ExampleSimilarity   float64                `json:"example_similarity"`
+}
+
 type ResponseProblem struct {
-	ProblemID       uuid.UUID                `json:"problem_id"`
-	ProblemDraftID  uuid.UUID                `json:"problem_draft_id"`
-	LatestVersionID uuid.UUID                `json:"latest_version_id"`
-	Versions        []ResponseProblemVersion `json:"versions"`
-	Status          constant.ProblemStatus   `json:"status"`
-	Creator         ResponseUser             `json:"creator"`
-	Reviewer        *ResponseUser            `json:"reviewer"`
-	Testers         []ResponseUser           `json:"testers"`
-	TargetContest   *ResponseContest         `json:"target_contest"`
-	AssignedContest *ResponseContest         `json:"assigned_contest"`
-	CreatedAt       time.Time                `json:"created_at"`
-	UpdatedAt       time.Time                `json:"updated_at"`
+	ProblemID           uuid.UUID                    `json:"problem_id"`
+	ProblemDraftID      uuid.UUID                    `json:"problem_draft_id"`
+	LatestVersionID     uuid.UUID                    `json:"latest_version_id"`
+	Versions            []ResponseProblemVersion     `json:"versions"`
+	Status              constant.ProblemStatus       `json:"status"`
+	Creator             ResponseUser                 `json:"creator"`
+	Reviewer            *ResponseUser                `json:"reviewer"`
+	Testers             []ResponseUser               `json:"testers"`
+	TargetContest       *ResponseContest             `json:"target_contest"`
+	AssignedContest     *ResponseContest             `json:"assigned_contest"` // The latest of AssignedContests
+	AssignedContests    []ResponseContest            `json:"assigned_contests"`
+	PotentialDuplicates []ResponsePotentialDuplicate `json:"potential_duplicates"` // Only those the user can read
+	CreatedAt           time.Time                    `json:"created_at"`
+	UpdatedAt           time.Time                    `json:"updated_at"`
 }
 
 type Response struct {
@@ -0,0 +1,9 @@
+package judgeproblem
+
+import "github.com/google/uuid"
+
+type Command struct {
+	ProblemID uuid.UUID `param:"problem_id" validate:"required"`
+	// ProblemVersionID selects the version to judge; the latest one by default.
+	ProblemVersionID uuid.NullUUID `json:"problem_version_id"`
+}
@@ -0,0 +1,122 @@
+package judgeproblem
+
+import (
+	"context"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUserNotPartOfRoom      = errors.New("user is not part of room")
+	ErrProblemVersionNotFound = errors.New("problem version not found")
+	ErrNoSolutions            = errors.New("problem version has no solutions")
+	ErrNoTestcases            = errors.New("problem version has no testcases")
+	ErrJudgeRunsPending       = errors.New("problem version still has pending judge runs")
+)
+
+type Version struct {
+	ProblemVersionID uuid.UUID
+	SolutionCount    int
+	TestcaseCount    int
+	PendingRunCount  int
+}
+
+type Repository interface {
+	IsUserPartOfRoom(ctx context.Context, problemID uuid.UUID, userID uuid.UUID) (bool, error)
+	GetVersion(ctx context.Context, problemID uuid.UUID, problemVersionID uuid.NullUUID) (*Version, error)
+	QueueJudgeRuns(
+		ctx context.Context,
+		problemID uuid.UUID,
+		version *Version,
+		requestedBy uuid.UUID,
+		createdAt time.Time,
+	) ([]uuid.UUID, error)
+}
+
+type CommandHandler struct {
+	repo         Repository
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+	uowFactory   contract.UnitOfWorkFactory
+	judgeQueue   contract.JudgeQueue
+	l            logger.Logger
+}
+
+func NewCommandHandler(
+	repo Repository,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+	uowFactory contract.UnitOfWorkFactory,
+	judgeQueue contract.JudgeQueue,
+	l logger.Logger,
+) *CommandHandler {
+	return &CommandHandler{
+		repo:         repo,
+		validator:    validator,
+		authProvider: authProvider,
+		uowFactory:   uowFactory,
+		judgeQueue:   judgeQueue,
+		l:            l,
+	}
+}
+
+func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Response, error) {
+	if command == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(command); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	user, err := h.authProvider.MustGetUser(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get user from auth provider")
+	}
+
+	uow := h.uowFactory.New()
+	response, err := uowhelper.DoWithResult(ctx, uow, h.l, func(ctx context.Context) (*Response, error) {
+		if ok, err := h.repo.IsUserPartOfRoom(ctx, command.ProblemID, user.UserID); err != nil {
+			return nil, errors.WrapIf(err, "failed to check if user is part of room")
+		} else if !ok {
+			return nil, errors.WithStack(ErrUserNotPartOfRoom)
+		}
+
+		version, err := h.repo.GetVersion(ctx, command.ProblemID, command.ProblemVersionID)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to get problem version")
+		}
+
+		if version.SolutionCount == 0 {
+			return nil, errors.WithStack(ErrNoSolutions)
+		} else if version.TestcaseCount == 0 {
+			return nil, errors.WithStack(ErrNoTestcases)
+		} else if version.PendingRunCount > 0 {
+			return nil, errors.WithStack(ErrJudgeRunsPending)
+		}
+
+		judgeRunIDs, err := h.repo.QueueJudgeRuns(ctx, command.ProblemID, version, user.UserID, time.Now())
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to queue judge runs")
+		}
+
+		return &Response{
+			ProblemVersionID: version.ProblemVersionID,
+			JudgeRunIDs:      judgeRunIDs,
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	h.judgeQueue.Notify()
+	return response, nil
+}
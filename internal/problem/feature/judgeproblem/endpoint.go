@@ -0,0 +1,59 @@
+package judgeproblem
+
+import (
+	"net/http"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/httperror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
+
+	"emperror.dev/errors"
+	"github.com/labstack/echo/v4"
+)
+
+type Endpoint struct {
+	*problem.EndpointParams
+	handler *CommandHandler
+}
+
+func NewEndpoint(params *problem.EndpointParams, handler *CommandHandler) *Endpoint {
+	return &Endpoint{
+		EndpointParams: params,
+		handler:        handler,
+	}
+}
+
+func (e *Endpoint) MapEndpoint() {
+	e.ProblemsGroup.POST("/:problem_id/judge-runs", e.handle())
+}
+
+func (e *Endpoint) handle() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		command := &Command{}
+		if err := ctx.Bind(command); err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid request format")
+		}
+
+		if err := ctx.Validate(command); err != nil {
+			return err
+		}
+
+		response, err := e.handler.Handle(ctx.Request().Context(), command)
+		if errors.Is(err, problem.ErrProblemNotFound) {
+			return httperror.New(http.StatusNotFound, "The problem does not exist")
+		} else if errors.Is(err, ErrProblemVersionNotFound) {
+			return httperror.New(http.StatusNotFound, "The problem version does not exist")
+		} else if errors.Is(err, ErrUserNotPartOfRoom) {
+			return httperror.New(http.StatusForbidden, "You are not involved in this problem")
+		} else if errors.Is(err, ErrNoSolutions) {
+			return httperror.New(http.StatusUnprocessableEntity, "The problem version has no solutions to judge")
+		} else if errors.Is(err, ErrNoTestcases) {
+			return httperror.New(http.StatusUnprocessableEntity, "The problem version has no testcases to judge against")
+		} else if errors.Is(err, ErrJudgeRunsPending) {
+			return httperror.New(http.StatusConflict, "The solutions of this problem version are already being judged")
+		} else if err != nil {
+			return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
+		}
+
+		return ctx.JSON(http.StatusAccepted, response)
+	}
+}
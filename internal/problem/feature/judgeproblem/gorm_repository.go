@@ -0,0 +1,159 @@
+package judgeproblem
+
+import (
+	"context"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) IsUserPartOfRoom(ctx context.Context, problemID uuid.UUID, userID uuid.UUID) (bool, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var p database.Problem
+	if err := db.WithContext(ctx).
+		Model(&database.Problem{}).
+		Preload("Testers").
+		Where("problem_id = ?", problemID).
+		First(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, errors.WithStack(problem.ErrProblemNotFound)
+		}
+
+		return false, errors.WrapIf(err, "failed to check if user is part of room")
+	}
+
+	if p.CreatorID == userID || p.ReviewerID.UUID == userID {
+		return true, nil
+	}
+
+	if len(p.Testers) > 0 {
+		for _, tester := range p.Testers {
+			if tester.UserID == userID {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (r *GormRepository) GetVersion(
+	ctx context.Context,
+	problemID uuid.UUID,
+	problemVersionID uuid.NullUUID,
+) (*Version, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	query := db.WithContext(ctx).
+		Model(&database.ProblemVersion{}).
+		Where("problem_id = ?", problemID)
+
+	if problemVersionID.Valid {
+		query = query.Where("problem_version_id = ?", problemVersionID.UUID)
+	} else {
+		query = query.Order("created_at DESC")
+	}
+
+	var version database.ProblemVersion
+	if err := query.First(&version).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithStack(ErrProblemVersionNotFound)
+		}
+
+		return nil, errors.WrapIf(err, "failed to get problem version")
+	}
+
+	var solutionCount, testcaseCount, pendingRunCount int64
+	if err := db.WithContext(ctx).
+		Model(&database.ProblemVersionSolution{}).
+		Where("problem_version_id = ?", version.ProblemVersionID).
+		Count(&solutionCount).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to count problem version solutions")
+	}
+
+	if err := db.WithContext(ctx).
+		Model(&database.ProblemVersionTestcase{}).
+		Where("problem_version_id = ?", version.ProblemVersionID).
+		Count(&testcaseCount).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to count problem version testcases")
+	}
+
+	if err := db.WithContext(ctx).
+		Model(&database.JudgeRun{}).
+		Where("problem_version_id = ?", version.ProblemVersionID).
+		Where("status IN ?", []string{constant.JudgeRunStatusQueued, constant.JudgeRunStatusRunning}).
+		Count(&pendingRunCount).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to count pending judge runs")
+	}
+
+	return &Version{
+		ProblemVersionID: version.ProblemVersionID,
+		SolutionCount:    int(solutionCount),
+		TestcaseCount:    int(testcaseCount),
+		PendingRunCount:  int(pendingRunCount),
+	}, nil
+}
+
+func (r *GormRepository) QueueJudgeRuns(
+	ctx context.Context,
+	problemID uuid.UUID,
+	version *Version,
+	requestedBy uuid.UUID,
+	createdAt time.Time,
+) ([]uuid.UUID, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var solutionIDs []uuid.UUID
+	if err := db.WithContext(ctx).
+		Model(&database.ProblemVersionSolution{}).
+		Where("problem_version_id = ?", version.ProblemVersionID).
+		Pluck("solution_id", &solutionIDs).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get problem version solutions")
+	}
+
+	runs := make([]database.JudgeRun, 0, len(solutionIDs))
+	judgeRunIDs := make([]uuid.UUID, 0, len(solutionIDs))
+	for _, solutionID := range solutionIDs {
+		judgeRunID, err := uuid.NewV7()
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to generate new judge run ID")
+		}
+
+		runs = append(runs, database.JudgeRun{
+			JudgeRunID:       judgeRunID,
+			ProblemID:        problemID,
+			ProblemVersionID: version.ProblemVersionID,
+			SolutionID:       solutionID,
+			Status:           constant.JudgeRunStatusQueued,
+			TotalCount:       version.TestcaseCount,
+			RequestedBy:      uuid.NullUUID{UUID: requestedBy, Valid: true},
+			CreatedAt:        createdAt,
+		})
+		judgeRunIDs = append(judgeRunIDs, judgeRunID)
+	}
+
+	if err := db.WithContext(ctx).Omit("Solution", "TestResults").Create(&runs).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to create judge runs")
+	}
+
+	return judgeRunIDs, nil
+}
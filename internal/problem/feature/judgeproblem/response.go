@@ -0,0 +1,8 @@
+package judgeproblem
+
+import "github.com/google/uuid"
+
+type Response struct {
+	ProblemVersionID uuid.UUID   `json:"problem_version_id"`
+	JudgeRunIDs      []uuid.UUID `json:"judge_run_ids"`
+}
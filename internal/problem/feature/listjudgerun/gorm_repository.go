@@ -0,0 +1,73 @@
+package listjudgerun
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) IsUserPartOfRoom(ctx context.Context, problemID uuid.UUID, userID uuid.UUID) (bool, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var p database.Problem
+	if err := db.WithContext(ctx).
+		Model(&database.Problem{}).
+		Preload("Testers").
+		Where("problem_id = ?", problemID).
+		First(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, errors.WithStack(problem.ErrProblemNotFound)
+		}
+
+		return false, errors.WrapIf(err, "failed to check if user is part of room")
+	}
+
+	if p.CreatorID == userID || p.ReviewerID.UUID == userID {
+		return true, nil
+	}
+
+	if len(p.Testers) > 0 {
+		for _, tester := range p.Testers {
+			if tester.UserID == userID {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (r *GormRepository) GetJudgeRuns(ctx context.Context, problemID uuid.UUID) ([]database.JudgeRun, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var runs []database.JudgeRun
+	if err := db.WithContext(ctx).
+		Preload("Solution").
+		Preload("TestResults", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Where("problem_id = ?", problemID).
+		Order("created_at DESC, judge_run_id ASC").
+		Find(&runs).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get judge runs")
+	}
+
+	return runs, nil
+}
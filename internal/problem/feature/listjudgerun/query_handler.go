@@ -0,0 +1,114 @@
+package listjudgerun
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+var ErrUserNotPartOfRoom = errors.New("user is not part of room")
+
+type Repository interface {
+	IsUserPartOfRoom(ctx context.Context, problemID uuid.UUID, userID uuid.UUID) (bool, error)
+	GetJudgeRuns(ctx context.Context, problemID uuid.UUID) ([]database.JudgeRun, error)
+}
+
+type QueryHandler struct {
+	repo         Repository
+	authProvider contract.AuthProvider
+}
+
+func NewQueryHandler(repo Repository, authProvider contract.AuthProvider) *QueryHandler {
+	return &QueryHandler{
+		repo:         repo,
+		authProvider: authProvider,
+	}
+}
+
+func (q *QueryHandler) Handle(ctx context.Context, query *Query) (*Response, error) {
+	if query == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	user, err := q.authProvider.MustGetUser(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get user from auth provider")
+	}
+
+	if ok, err := q.repo.IsUserPartOfRoom(ctx, query.ProblemID, user.UserID); err != nil {
+		return nil, errors.WrapIf(err, "failed to check if user is part of room")
+	} else if !ok {
+		return nil, errors.WithStack(ErrUserNotPartOfRoom)
+	}
+
+	runs, err := q.repo.GetJudgeRuns(ctx, query.ProblemID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get judge runs")
+	}
+
+	response := &Response{
+		JudgeRuns: make([]ResponseJudgeRun, 0, len(runs)),
+	}
+
+	for _, run := range runs {
+		r := ResponseJudgeRun{
+			JudgeRunID:       run.JudgeRunID,
+			ProblemVersionID: run.ProblemVersionID,
+			SolutionID:       run.SolutionID,
+			SolutionName:     run.Solution.Name,
+			Language:         run.Solution.Language,
+			IsMain:           run.Solution.IsMain,
+			ExpectedVerdict:  run.Solution.ExpectedVerdict,
+			Status:           run.Status,
+			PassedCount:      run.PassedCount,
+			TotalCount:       run.TotalCount,
+			MaxTimeMs:        run.MaxTimeMs,
+			MaxMemoryKb:      run.MaxMemoryKb,
+			Log:              run.Log,
+			TestResults:      make([]ResponseTestResult, 0, len(run.TestResults)),
+			CreatedAt:        run.CreatedAt,
+		}
+
+		if run.StartedAt.Valid {
+			r.StartedAt = &run.StartedAt.Time
+		}
+
+		if run.FinishedAt.Valid {
+			r.FinishedAt = &run.FinishedAt.Time
+		}
+
+		if run.Status == constant.JudgeRunStatusFinished {
+			matches := constant.Verdict(run.Solution.ExpectedVerdict).Matches(constant.Verdict(run.Verdict))
+			r.Verdict = run.Verdict
+			r.Matches = &matches
+			r.Summary = contract.MessageJudgeRun{
+				SolutionName: run.Solution.Name,
+				IsMain:       run.Solution.IsMain,
+				Verdict:      run.Verdict,
+				PassedCount:  run.PassedCount,
+				TotalCount:   run.TotalCount,
+			}.Summary()
+		}
+
+		for _, test := range run.TestResults {
+			r.TestResults = append(r.TestResults, ResponseTestResult{
+				TestcaseID: test.TestcaseID,
+				Position:   test.Position,
+				Verdict:    test.Verdict,
+				TimeMs:     test.TimeMs,
+				MemoryKb:   test.MemoryKb,
+				Message:    test.Message,
+			})
+		}
+
+		response.JudgeRuns = append(response.JudgeRuns, r)
+	}
+
+	return response, nil
+}
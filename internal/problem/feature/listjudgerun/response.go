@@ -0,0 +1,44 @@
+package listjudgerun
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ResponseTestResult struct {
+	TestcaseID uuid.UUID `json:"testcase_id"`
+	Position   int       `json:"position"`
+	Verdict    string    `json:"verdict"`
+	TimeMs     uint      `json:"time_ms"`
+	MemoryKb   uint64    `json:"memory_kb"`
+	Message    string    `json:"message,omitempty"`
+}
+
+type ResponseJudgeRun struct {
+	JudgeRunID       uuid.UUID `json:"judge_run_id"`
+	ProblemVersionID uuid.UUID `json:"problem_version_id"`
+	SolutionID       uuid.UUID `json:"solution_id"`
+	SolutionName     string    `json:"solution_name"`
+	Language         string    `json:"language"`
+	IsMain           bool      `json:"is_main"`
+	ExpectedVerdict  string    `json:"expected_verdict"`
+	Status           string    `json:"status"`
+	// Verdict and Matches are only set once the run has finished.
+	Verdict     string               `json:"verdict,omitempty"`
+	Matches     *bool                `json:"matches,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	PassedCount int                  `json:"passed_count"`
+	TotalCount  int                  `json:"total_count"`
+	MaxTimeMs   uint                 `json:"max_time_ms"`
+	MaxMemoryKb uint64               `json:"max_memory_kb"`
+	Log         string               `json:"log,omitempty"`
+	TestResults []ResponseTestResult `json:"test_results"`
+	CreatedAt   time.Time            `json:"created_at"`
+	StartedAt   *time.Time           `json:"started_at,omitempty"`
+	FinishedAt  *time.Time           `json:"finished_at,omitempty"`
+}
+
+type Response struct {
+	JudgeRuns []ResponseJudgeRun `json:"judge_runs"`
+}
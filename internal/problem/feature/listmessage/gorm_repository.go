@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
 
 	"emperror.dev/errors"
 	"github.com/google/uuid"
@@ -225,37 +227,24 @@ func itoa(i int) string {
 	return string(buf[bp:])
 }
 
-func (r *GormRepository) IsUserPartOfRoom(ctx context.Context, problemID uuid.UUID, userID uuid.UUID) (bool, error) {
+func (r *GormRepository) GetProblemAccess(ctx context.Context, problemID uuid.UUID) (*problem.Access, error) {
 	db := database.GetDBFromContext(ctx, r.db)
 
 	var p database.Problem
 	if err := db.WithContext(ctx).
 		Model(&database.Problem{}).
 		Preload("Testers").
+		Preload("ContestProblems").
 		Where("problem_id = ?", problemID).
 		First(&p).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return false, nil
-		}
-
-		return false, errors.WrapIf(err, "failed to check if user is part of room")
-	}
-
-	if p.CreatorID == userID || p.ReviewerID.UUID == userID {
-		return true, nil
-	}
-
-	if len(p.Testers) > 0 {
-		for _, tester := range p.Testers {
-			if tester.UserID == userID {
-				return true, nil
-			}
+			return nil, errors.WithStack(ErrProblemNotFound)
 		}
 
-		return false, nil
+		return nil, errors.WrapIf(err, "failed to get problem access")
 	}
 
-	return true, nil
+	return problem.NewAccess(&p), nil
 }
 
 func (r *GormRepository) GetUserChatMessages(ctx context.Context, problemID uuid.UUID) ([]ResponseChatMessage, error) {
@@ -445,6 +434,84 @@ func (r *GormRepository) GetCompletedMessage(ctx context.Context, problemID uuid
 	}, nil
 }
 
+func (r *GormRepository) GetJudgedMessages(ctx context.Context, problemID uuid.UUID) ([]ResponseChatMessage, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var runs []database.JudgeRun
+	if err := db.WithContext(ctx).
+		Preload("Solution").
+		Where("problem_id = ? AND status = ?", problemID, constant.JudgeRunStatusFinished).
+		Find(&runs).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get judge runs")
+	}
+
+	dtos := make([]ResponseChatMessage, 0, len(runs))
+	for _, run := range runs {
+		judgeRun := contract.MessageJudgeRun{
+			JudgeRunID:      run.JudgeRunID,
+			SolutionName:    run.Solution.Name,
+			IsMain:          run.Solution.IsMain,
+			ExpectedVerdict: run.Solution.ExpectedVerdict,
+			Verdict:         run.Verdict,
+			PassedCount:     run.PassedCount,
+			TotalCount:      run.TotalCount,
+		}
+
+		dtos = append(dtos, ResponseChatMessage{
+			MessageType: string(contract.MessageTypeJudged),
+			Payload: ResponseChatJudgedPayload{
+				Run:     judgeRun,
+				Summary: judgeRun.Summary(),
+			},
+			Timestamp: run.FinishedAt.Time,
+		})
+	}
+
+	return dtos, nil
+}
+
+// GetDuplicateMessages announces the potential duplicates of each version in
+// one message.
+func (r *GormRepository) GetDuplicateMessages(ctx context.Context, problemID uuid.UUID) ([]DuplicateMessage, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var similarities []database.ProblemSimilarity
+	if err := db.WithContext(ctx).
+		Preload("SimilarProblem").
+		Preload("SimilarProblem.Testers").
+		Preload("SimilarProblem.ContestProblems").
+		Where("problem_id = ?", problemID).
+		Order("created_at ASC").
+		Find(&similarities).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get problem similarities")
+	}
+
+	messages := make([]DuplicateMessage, 0)
+	messageIndexByVersionID := make(map[uuid.UUID]int)
+	for _, similarity := range similarities {
+		i, ok := messageIndexByVersionID[similarity.ProblemVersionID]
+		if !ok {
+			i = len(messages)
+			messageIndexByVersionID[similarity.ProblemVersionID] = i
+			messages = append(messages, DuplicateMessage{
+				Duplicates: make([]Duplicate, 0),
+				Timestamp:  similarity.CreatedAt,
+			})
+		}
+
+		messages[i].Duplicates = append(messages[i].Duplicates, Duplicate{
+			MessageDuplicate: contract.MessageDuplicate{
+				ProblemID:           similarity.SimilarProblemID,
+				StatementSimilarity: similarity.StatementSimilarity,
+				ExampleSimilarity:   similarity.ExampleSimilarity,
+			},
+			Access: problem.NewAccess(&similarity.SimilarProblem),
+		})
+	}
+
+	return messages, nil
+}
+
 func (r *GormRepository) fetchUsers(
 	ctx context.Context,
 	db *gorm.DB,
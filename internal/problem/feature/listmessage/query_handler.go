@@ -3,9 +3,11 @@ package listmessage
 import (
 	"context"
 	"slices"
+	"time"
 
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
 
 	"emperror.dev/errors"
 	"github.com/google/uuid"
@@ -21,13 +23,27 @@ type Query struct {
 	ProblemID uuid.UUID `param:"problem_id" validate:"required"`
 }
 
+// DuplicateMessage comes with the access of every potential duplicate, as
+// only the problems the user can read are shown.
+type DuplicateMessage struct {
+	Duplicates []Duplicate
+	Timestamp  time.Time
+}
+
+type Duplicate struct {
+	contract.MessageDuplicate
+	Access *problem.Access
+}
+
 type Repository interface {
-	IsUserPartOfRoom(ctx context.Context, problemID uuid.UUID, userID uuid.UUID) (bool, error)
+	GetProblemAccess(ctx context.Context, problemID uuid.UUID) (*problem.Access, error)
 	GetSubmissionMessages(ctx context.Context, problemID uuid.UUID) ([]ResponseChatMessage, error)
 	GetUserChatMessages(ctx context.Context, problemID uuid.UUID) ([]ResponseChatMessage, error)
 	GetReviewedMessages(ctx context.Context, problemID uuid.UUID) ([]ResponseChatMessage, error)
 	GetTestedMessages(ctx context.Context, problemID uuid.UUID) ([]ResponseChatMessage, error)
 	GetCompletedMessage(ctx context.Context, problemID uuid.UUID) (*ResponseChatMessage, error)
+	GetJudgedMessages(ctx context.Context, problemID uuid.UUID) ([]ResponseChatMessage, error)
+	GetDuplicateMessages(ctx context.Context, problemID uuid.UUID) ([]DuplicateMessage, error)
 }
 
 type QueryHandler struct {
@@ -47,14 +63,15 @@ func (q *QueryHandler) Handle(ctx context.Context, query *Query) (*Response, err
 		return nil, errors.WithStack(customerror.ErrCommandNil)
 	}
 
-	user, err := q.authProvider.MustGetUser(ctx)
+	access, err := q.repo.GetProblemAccess(ctx, query.ProblemID)
 	if err != nil {
-		return nil, errors.WrapIf(err, "failed to get user ID from auth provider")
+		return nil, errors.WrapIf(err, "failed to get problem access")
 	}
 
-	if ok, err := q.repo.IsUserPartOfRoom(ctx, query.ProblemID, user.UserID); err != nil {
+	scope, err := problem.GetReadScope(ctx, q.authProvider)
+	if err != nil {
 		return nil, errors.WrapIf(err, "failed to check if user is part of room")
-	} else if !ok {
+	} else if !scope.Allows(access) {
 		return nil, errors.WithStack(ErrUserNotPartOfRoom)
 	}
 
@@ -63,6 +80,8 @@ func (q *QueryHandler) Handle(ctx context.Context, query *Query) (*Response, err
 	var reviewedMessages []ResponseChatMessage
 	var testedMessages []ResponseChatMessage
 	var completedMessage *ResponseChatMessage
+	var judgedMessages []ResponseChatMessage
+	var duplicateMessages []DuplicateMessage
 
 	g, ctx := errgroup.WithContext(ctx)
 	g.Go(func() error {
@@ -95,15 +114,29 @@ func (q *QueryHandler) Handle(ctx context.Context, query *Query) (*Response, err
 		return errors.WrapIf(err, "failed to get completed message")
 	})
 
+	g.Go(func() error {
+		var err error
+		judgedMessages, err = q.repo.GetJudgedMessages(ctx, query.ProblemID)
+		return errors.WrapIf(err, "failed to get judged messages")
+	})
+
+	g.Go(func() error {
+		var err error
+		duplicateMessages, err = q.repo.GetDuplicateMessages(ctx, query.ProblemID)
+		return errors.WrapIf(err, "failed to get duplicate messages")
+	})
+
 	if err := g.Wait(); err != nil {
 		return nil, errors.WrapIf(err, "failed to get messages")
 	}
 
-	messages := make([]ResponseChatMessage, 0, len(submissionMessages)+len(userMessages)+len(reviewedMessages)+len(testedMessages)+1)
+	messages := make([]ResponseChatMessage, 0, len(submissionMessages)+len(userMessages)+len(reviewedMessages)+len(testedMessages)+len(judgedMessages)+len(duplicateMessages)+1)
 	messages = append(messages, submissionMessages...)
 	messages = append(messages, userMessages...)
 	messages = append(messages, reviewedMessages...)
 	messages = append(messages, testedMessages...)
+	messages = append(messages, judgedMessages...)
+	messages = append(messages, readableDuplicateMessages(scope, duplicateMessages)...)
 	if completedMessage != nil {
 		messages = append(messages, *completedMessage)
 	}
@@ -122,3 +155,29 @@ func (q *QueryHandler) Handle(ctx context.Context, query *Query) (*Response, err
 		Messages: messages,
 	}, nil
 }
+
+// readableDuplicateMessages keeps the potential duplicates the user can read,
+// and leaves out the messages without any.
+func readableDuplicateMessages(scope *problem.ReadScope, messages []DuplicateMessage) []ResponseChatMessage {
+	dtos := make([]ResponseChatMessage, 0, len(messages))
+	for _, message := range messages {
+		payload := ResponseChatDuplicatePayload{Duplicates: make([]contract.MessageDuplicate, 0, len(message.Duplicates))}
+		for _, duplicate := range message.Duplicates {
+			if scope.Allows(duplicate.Access) {
+				payload.Duplicates = append(payload.Duplicates, duplicate.MessageDuplicate)
+			}
+		}
+
+		if len(payload.Duplicates) == 0 {
+			continue
+		}
+
+		dtos = append(dtos, ResponseChatMessage{
+			MessageType: string(contract.MessageTypeDuplicate),
+			Payload:     payload,
+			Timestamp:   message.Timestamp,
+		})
+	}
+
+	return dtos
+}
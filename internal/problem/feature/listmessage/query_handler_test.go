@@ -0,0 +1,128 @@
+package listmessage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/contracttest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
+
+	"github.com/google/uuid"
+)
+
+type fakeRepository struct {
+	access            *problem.Access
+	duplicateMessages []DuplicateMessage
+}
+
+func (r *fakeRepository) GetProblemAccess(context.Context, uuid.UUID) (*problem.Access, error) {
+	return r.access, nil
+}
+
+func (r *fakeRepository) GetSubmissionMessages(context.Context, uuid.UUID) ([]ResponseChatMessage, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) GetUserChatMessages(context.Context, uuid.UUID) ([]ResponseChatMessage, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) GetReviewedMessages(context.Context, uuid.UUID) ([]ResponseChatMessage, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) GetTestedMessages(context.Context, uuid.UUID) ([]ResponseChatMessage, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) GetCompletedMessage(context.Context, uuid.UUID) (*ResponseChatMessage, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) GetJudgedMessages(context.Context, uuid.UUID) ([]ResponseChatMessage, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) GetDuplicateMessages(context.Context, uuid.UUID) ([]DuplicateMessage, error) {
+	return r.duplicateMessages, nil
+}
+
+func TestHandleShowsReadableDuplicates(t *testing.T) {
+	setterID := uuid.New()
+	ownDuplicate := Duplicate{
+		MessageDuplicate: contract.MessageDuplicate{ProblemID: uuid.New()},
+		Access:           &problem.Access{Status: constant.ProblemStatusCompleted, CreatorID: setterID},
+	}
+	otherDuplicate := Duplicate{
+		MessageDuplicate: contract.MessageDuplicate{ProblemID: uuid.New()},
+		Access:           &problem.Access{Status: constant.ProblemStatusCompleted, CreatorID: uuid.New()},
+	}
+
+	repo := &fakeRepository{
+		access: &problem.Access{Status: constant.ProblemStatusPendingReview, CreatorID: setterID},
+		duplicateMessages: []DuplicateMessage{
+			{Duplicates: []Duplicate{otherDuplicate}, Timestamp: time.Now().Add(-time.Minute)},
+			{Duplicates: []Duplicate{ownDuplicate, otherDuplicate}, Timestamp: time.Now()},
+		},
+	}
+
+	// Messages come newest first, and those left without duplicates are
+	// left out.
+	tests := []struct {
+		name        string
+		permissions []string
+		want        [][]uuid.UUID
+	}{
+		{
+			name: "setter",
+			want: [][]uuid.UUID{{ownDuplicate.ProblemID}},
+		},
+		{
+			name:        "reader of every problem",
+			permissions: []string{constant.PermissionProblemReadDetailsAny},
+			want:        [][]uuid.UUID{{ownDuplicate.ProblemID, otherDuplicate.ProblemID}, {otherDuplicate.ProblemID}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewQueryHandler(repo, contracttest.NewAuthProvider(setterID, &contract.AuthUserDetails{
+				Permissions: tt.permissions,
+			}))
+
+			response, err := handler.Handle(context.Background(), &Query{ProblemID: uuid.New()})
+			if err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			}
+
+			got := make([][]uuid.UUID, 0)
+			for _, message := range response.Messages {
+				problemIDs := make([]uuid.UUID, 0)
+				for _, duplicate := range message.Payload.(ResponseChatDuplicatePayload).Duplicates {
+					problemIDs = append(problemIDs, duplicate.ProblemID)
+				}
+
+				got = append(got, problemIDs)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Handle() duplicates = %v, want %v", got, tt.want)
+			}
+
+			for i := range got {
+				if len(got[i]) != len(tt.want[i]) {
+					t.Fatalf("Handle() duplicates = %v, want %v", got, tt.want)
+				}
+
+				for j := range got[i] {
+					if got[i][j] != tt.want[i][j] {
+						t.Errorf("Handle() duplicates = %v, want %v", got, tt.want)
+					}
+				}
+			}
+		})
+	}
+}
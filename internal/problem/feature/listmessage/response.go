@@ -50,6 +50,15 @@ type ResponseChatCompletedPayload struct {
 	Status    string               `json:"status"`
 }
 
+type ResponseChatJudgedPayload struct {
+	Run     contract.MessageJudgeRun `json:"run"`
+	Summary string                   `json:"summary"`
+}
+
+type ResponseChatDuplicatePayload struct {
+	Duplicates []contract.MessageDuplicate `json:"duplicates"`
+}
+
 type Response struct {
 	Messages []ResponseChatMessage `json:"messages"`
 }
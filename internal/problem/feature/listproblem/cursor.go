@@ -0,0 +1,91 @@
+package listproblem
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// statusOrder is the order of the workflow, in which problems are sorted by
+// status. Rejected problems come last.
+var statusOrder = []constant.ProblemStatus{
+	constant.ProblemStatusPendingReview,
+	constant.ProblemStatusNeedsRevision,
+	constant.ProblemStatusPendingTesting,
+	constant.ProblemStatusTestingChangesRequested,
+	constant.ProblemStatusAwaitingFinalCheck,
+	constant.ProblemStatusCompleted,
+	constant.ProblemStatusRejected,
+}
+
+func statusRank(status constant.ProblemStatus) int {
+	if i := slices.Index(statusOrder, status); i >= 0 {
+		return i
+	}
+
+	return len(statusOrder)
+}
+
+// Cursor points right after the last problem of a page. It remembers the
+// sorting it was made for, since it means nothing under another one.
+type Cursor struct {
+	Sort       SortField `json:"s"`
+	Descending bool      `json:"d"`
+	Time       time.Time `json:"t"`
+	StatusRank int       `json:"r"`
+	ProblemID  uuid.UUID `json:"id"`
+}
+
+func newCursor(sort SortField, descending bool, problem ResponseProblem) Cursor {
+	cursor := Cursor{
+		Sort:       sort,
+		Descending: descending,
+		ProblemID:  problem.ProblemID,
+	}
+
+	switch sort {
+	case SortFieldUpdatedAt:
+		cursor.Time = problem.UpdatedAt
+	case SortFieldStatus:
+		cursor.StatusRank = statusRank(problem.Status)
+	default:
+		cursor.Time = problem.CreatedAt
+	}
+
+	return cursor
+}
+
+func (c Cursor) Encode() (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", errors.WrapIf(err, "failed to marshal cursor")
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(encoded string, sort SortField, descending bool) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.WithStack(ErrInvalidCursor)
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, errors.WithStack(ErrInvalidCursor)
+	}
+
+	if cursor.Sort != sort || cursor.Descending != descending || cursor.ProblemID == uuid.Nil {
+		return nil, errors.WithStack(ErrInvalidCursor)
+	}
+
+	return &cursor, nil
+}
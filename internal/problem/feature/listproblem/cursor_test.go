@@ -0,0 +1,59 @@
+package listproblem
+
+import (
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	problem := ResponseProblem{
+		ProblemID: uuid.New(),
+		Status:    constant.ProblemStatusPendingTesting,
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 123456000, time.UTC),
+	}
+
+	encoded, err := newCursor(SortFieldCreatedAt, true, problem).Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	cursor, err := decodeCursor(encoded, SortFieldCreatedAt, true)
+	if err != nil {
+		t.Fatalf("decodeCursor() error = %v", err)
+	}
+
+	if !cursor.Time.Equal(problem.CreatedAt) || cursor.ProblemID != problem.ProblemID {
+		t.Errorf("decodeCursor() = %+v, want the time and ID of %+v", cursor, problem)
+	}
+
+	if _, err := decodeCursor(encoded, SortFieldStatus, true); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("decodeCursor() with another sort error = %v, want %v", err, ErrInvalidCursor)
+	}
+
+	if _, err := decodeCursor(encoded, SortFieldCreatedAt, false); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("decodeCursor() with another order error = %v, want %v", err, ErrInvalidCursor)
+	}
+
+	if _, err := decodeCursor("not a cursor", SortFieldCreatedAt, true); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("decodeCursor() with garbage error = %v, want %v", err, ErrInvalidCursor)
+	}
+}
+
+func TestStatusRankFollowsWorkflow(t *testing.T) {
+	if statusRank(constant.ProblemStatusPendingReview) >= statusRank(constant.ProblemStatusCompleted) {
+		t.Error("pending review should sort before completed")
+	}
+
+	if statusRank(constant.ProblemStatusRejected) != len(statusOrder)-1 {
+		t.Error("rejected should sort last")
+	}
+
+	if statusRank("") != len(statusOrder) {
+		t.Error("unknown statuses should sort after every known one")
+	}
+}
@@ -3,9 +3,11 @@ package listproblem
 import (
 	"net/http"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/httperror"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
 
+	"emperror.dev/errors"
 	"github.com/labstack/echo/v4"
 )
 
@@ -27,13 +29,22 @@ func (e *Endpoint) MapEndpoint() {
 
 func (e *Endpoint) handle() echo.HandlerFunc {
 	return func(ctx echo.Context) error {
-		onlyShowCompleted := false
-		if ctx.QueryParam("is_completed") == "true" {
-			onlyShowCompleted = true
+		query := &Query{}
+		if err := ctx.Bind(query); err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid request format")
 		}
 
-		response, err := e.handler.Handle(ctx.Request().Context(), onlyShowCompleted)
-		if err != nil {
+		if err := ctx.Validate(query); err != nil {
+			return err
+		}
+
+		response, err := e.handler.Handle(ctx.Request().Context(), query)
+		if errors.Is(err, customerror.ErrCommandNil) ||
+			errors.Is(err, customerror.ErrValidationFailed) {
+			return err
+		} else if errors.Is(err, ErrInvalidCursor) {
+			return httperror.New(http.StatusBadRequest, "The cursor is invalid or was made for another sorting")
+		} else if err != nil {
 			return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
 		}
 
@@ -44,9 +44,6 @@ type flatProblemData struct {
 
 	AssignedContestID    uuid.NullUUID  `gorm:"column:assigned_contest_id"`
 	AssignedContestTitle sql.NullString `gorm:"column:assigned_contest_title"`
-
-	LatestVersionID           uuid.NullUUID `gorm:"column:latest_version_id"`
-	LatestVersionDifficultyID uuid.NullUUID `gorm:"column:latest_version_difficulty_id"`
 }
 
 type flatProblemTesterData struct {
@@ -55,29 +52,73 @@ type flatProblemTesterData struct {
 	TesterName string    `gorm:"column:tester_username"`
 }
 
-func (r *GormRepository) GetAllRelatedProblems(
+// latestDifficultySQL selects the difficulty of the latest version of the
+// problem in the outer query.
+const latestDifficultySQL = `
+	SELECT lpv.problem_difficulty_id
+	FROM problem_versions lpv
+	WHERE lpv.problem_id = p.problem_id
+	ORDER BY lpv.created_at DESC
+	LIMIT 1
+`
+
+// latestAssignmentSQL selects the latest contest assignment of every
+// problem. The contest ID breaks ties between assignments created at the same
+// time, so that no problem is listed twice.
+func latestAssignmentSQL(db *gorm.DB) string {
+	if db.Dialector.Name() == "postgres" {
+		return `
+			SELECT DISTINCT ON (problem_id) problem_id, contest_id
+			FROM contest_problems
+			ORDER BY problem_id, created_at DESC, contest_id DESC
+		`
+	}
+
+	return `
+		SELECT problem_id, contest_id
+		FROM (
+			SELECT
+				problem_id,
+				contest_id,
+				ROW_NUMBER() OVER (PARTITION BY problem_id ORDER BY created_at DESC, contest_id DESC) AS assignment_rank
+			FROM contest_problems
+		) ranked_cp
+		WHERE assignment_rank = 1
+	`
+}
+
+func (r *GormRepository) CountProblems(ctx context.Context, visibility Visibility, query *Query) (int64, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var count int64
+	if err := r.filterProblems(db.WithContext(ctx).Table("problems p"), visibility, query).
+		Count(&count).Error; err != nil {
+		return 0, errors.WrapIf(err, "failed to count problems")
+	}
+
+	return count, nil
+}
+
+func (r *GormRepository) GetProblems(
 	ctx context.Context,
-	userID uuid.UUID,
-	showAll bool,
-	showCreated bool,
-	showAllPendingReview bool,
-	showAssignedTesting bool,
-	onlyShowCompleted bool,
+	visibility Visibility,
+	query *Query,
+	page Page,
 ) ([]ResponseProblem, error) {
 	db := database.GetDBFromContext(ctx, r.db)
 
-	flatProblems, err := r.fetchRelatedProblems(
-		ctx,
-		db,
-		userID,
-		showAll,
-		showCreated,
-		showAllPendingReview,
-		showAssignedTesting,
-		onlyShowCompleted,
-	)
+	flatProblems, err := r.fetchProblemPage(ctx, db, visibility, query, page)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to fetch related problems")
+		return nil, errors.WrapIf(err, "failed to fetch problems")
+	}
+
+	problemIDs := make([]uuid.UUID, 0, len(flatProblems))
+	for _, problem := range flatProblems {
+		problemIDs = append(problemIDs, problem.ProblemID)
+	}
+
+	if len(problemIDs) == 0 {
+		return make([]ResponseProblem, 0), nil
 	}
 
 	var problemTesters []flatProblemTesterData
@@ -85,13 +126,14 @@ func (r *GormRepository) GetAllRelatedProblems(
 		Table("problem_testers pt").
 		Joins("LEFT JOIN users ON users.user_id = pt.user_user_id").
 		Select("pt.problem_problem_id as problem_id, pt.user_user_id as tester_id, users.username as tester_username").
+		Where("pt.problem_problem_id IN ?", problemIDs).
 		Scan(&problemTesters).Error; err != nil {
-		return nil, errors.Wrap(err, "failed to fetch problem testers")
+		return nil, errors.WrapIf(err, "failed to fetch problem testers")
 	}
 
 	testerByProblemID := make(map[uuid.UUID][]ResponseUser)
-	for _, p := range flatProblems {
-		testerByProblemID[p.ProblemID] = make([]ResponseUser, 0)
+	for _, problemID := range problemIDs {
+		testerByProblemID[problemID] = make([]ResponseUser, 0)
 	}
 
 	for _, pt := range problemTesters {
@@ -101,17 +143,29 @@ func (r *GormRepository) GetAllRelatedProblems(
 		})
 	}
 
-	latestVersionIDs := make([]uuid.UUID, 0, len(flatProblems))
-	difficultyIDs := make([]uuid.UUID, 0, len(flatProblems))
+	var versions []database.ProblemVersion
+	if err := db.WithContext(ctx).
+		Select("problem_version_id", "problem_id", "problem_difficulty_id").
+		Where("problem_id IN ?", problemIDs).
+		Order("created_at DESC").
+		Find(&versions).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to fetch problem versions")
+	}
+
+	// Versions are ordered newest first, so the first one seen for a problem
+	// is its latest.
+	latestVersionByProblemID := make(map[uuid.UUID]database.ProblemVersion, len(problemIDs))
+	latestVersionIDs := make([]uuid.UUID, 0, len(problemIDs))
+	difficultyIDs := make([]uuid.UUID, 0, len(problemIDs))
 
-	for _, problem := range flatProblems {
-		if problem.LatestVersionID.Valid {
-			latestVersionIDs = append(latestVersionIDs, problem.LatestVersionID.UUID)
+	for _, version := range versions {
+		if _, ok := latestVersionByProblemID[version.ProblemID]; ok {
+			continue
 		}
 
-		if problem.LatestVersionDifficultyID.Valid {
-			difficultyIDs = append(difficultyIDs, problem.LatestVersionDifficultyID.UUID)
-		}
+		latestVersionByProblemID[version.ProblemID] = version
+		latestVersionIDs = append(latestVersionIDs, version.ProblemVersionID)
+		difficultyIDs = append(difficultyIDs, version.ProblemDifficultyID)
 	}
 
 	titlesByVersionID, err := r.fetchTitlesForVersions(ctx, db, latestVersionIDs)
@@ -161,12 +215,12 @@ func (r *GormRepository) GetAllRelatedProblems(
 			}
 		}
 
-		if problem.LatestVersionID.Valid {
-			p.Titles = titlesByVersionID[problem.LatestVersionID.UUID]
-		}
+		if version, ok := latestVersionByProblemID[problem.ProblemID]; ok {
+			if titles, ok := titlesByVersionID[version.ProblemVersionID]; ok {
+				p.Titles = titles
+			}
 
-		if problem.LatestVersionDifficultyID.Valid {
-			p.ProblemDifficulty = difficultiesByID[problem.LatestVersionDifficultyID.UUID]
+			p.ProblemDifficulty = difficultiesByID[version.ProblemDifficultyID]
 		}
 
 		result = append(result, p)
@@ -175,70 +229,81 @@ func (r *GormRepository) GetAllRelatedProblems(
 	return result, nil
 }
 
-func (r *GormRepository) fetchRelatedProblems(
+func (r *GormRepository) fetchProblemPage(
 	ctx context.Context,
 	db *gorm.DB,
-	userID uuid.UUID,
-	showAll bool,
-	showCreated bool,
-	showAllPendingReview bool,
-	showAssignedTesting bool,
-	onlyShowCompleted bool,
+	visibility Visibility,
+	query *Query,
+	page Page,
 ) ([]flatProblemData, error) {
-	var problems []flatProblemData
-	query := db.WithContext(ctx).
+	tx := db.WithContext(ctx).
 		Table("problems p").
 		Joins("LEFT JOIN users creator_u ON creator_u.user_id = p.creator_id").
 		Joins("LEFT JOIN users reviewer_u ON reviewer_u.user_id = p.reviewer_id").
 		Joins("LEFT JOIN contests target_c ON target_c.contest_id = p.target_contest_id").
-		Joins("LEFT JOIN contests assigned_c ON assigned_c.contest_id = p.assigned_contest_id").
-		Joins(`
-			LEFT JOIN LATERAL (
-				SELECT
-					pv.problem_version_id,
-					pv.problem_difficulty_id,
-					pv.created_at
-				FROM
-					problem_versions pv
-				WHERE
-					pv.problem_id = p.problem_id
-				ORDER BY
-					pv.created_at DESC
-				LIMIT 1
-			) rpv ON TRUE
-		`).
+		// A problem reused across contests is shown with its latest assignment
+		Joins("LEFT JOIN (" + latestAssignmentSQL(db) + ") cp ON cp.problem_id = p.problem_id").
+		Joins("LEFT JOIN contests assigned_c ON assigned_c.contest_id = cp.contest_id").
 		Select(`
-            p.problem_id,
-            p.status as problem_status,
-            p.created_at as problem_created_at,
-            p.updated_at as problem_updated_at,
-            p.problem_draft_id,
-            p.creator_id,
-            p.reviewer_id,
-            p.target_contest_id,
-            p.assigned_contest_id,
-            creator_u.username as creator_username,
-            reviewer_u.username as reviewer_username,
-            target_c.title as target_contest_title,
-            assigned_c.title as assigned_contest_title,
-			rpv.problem_version_id as latest_version_id,
-            rpv.problem_difficulty_id as latest_version_difficulty_id 
-        `)
+			p.problem_id,
+			p.status as problem_status,
+			p.created_at as problem_created_at,
+			p.updated_at as problem_updated_at,
+			p.problem_draft_id,
+			p.creator_id,
+			p.reviewer_id,
+			p.target_contest_id,
+			cp.contest_id as assigned_contest_id,
+			creator_u.username as creator_username,
+			reviewer_u.username as reviewer_username,
+			target_c.title as target_contest_title,
+			assigned_c.title as assigned_contest_title
+		`)
 
-	// We build OR visibility predicates; user should ALWAYS see their own created problems
-	visibilityPredicates := make([]string, 0)
-	visibilityArgs := make([]interface{}, 0)
+	tx = r.filterProblems(tx, visibility, query)
+
+	sortSQL := sortExpression(page.Sort)
+	direction, comparison := "ASC", ">"
+	if page.Descending {
+		direction, comparison = "DESC", "<"
+	}
 
-	// Always own problems
-	visibilityPredicates = append(visibilityPredicates, "p.creator_id = ?")
-	visibilityArgs = append(visibilityArgs, userID)
+	// The problem ID breaks ties, so that pages neither skip nor repeat
+	// problems sharing a sort key.
+	if page.After != nil {
+		var after any = page.After.Time
+		if page.Sort == SortFieldStatus {
+			after = page.After.StatusRank
+		}
 
-	// As reviewer
-	visibilityPredicates = append(visibilityPredicates, "p.reviewer_id = ?")
-	visibilityArgs = append(visibilityArgs, userID)
+		tx = tx.Where(
+			fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND p.problem_id %[2]s ?))", sortSQL, comparison),
+			after,
+			after,
+			page.After.ProblemID,
+		)
+	}
+
+	var problems []flatProblemData
+	if err := tx.
+		Order(fmt.Sprintf("%s %s, p.problem_id %s", sortSQL, direction, direction)).
+		Limit(page.Limit).
+		Scan(&problems).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get problems")
+	}
+
+	return problems, nil
+}
+
+// filterProblems narrows the problems down to those the user can see and that
+// match the query.
+func (r *GormRepository) filterProblems(tx *gorm.DB, visibility Visibility, query *Query) *gorm.DB {
+	// We build OR visibility predicates; user should ALWAYS see their own created problems
+	visibilityPredicates := []string{"p.creator_id = ?", "p.reviewer_id = ?"}
+	visibilityArgs := []any{visibility.UserID, visibility.UserID}
 
 	// Testing assignments
-	if showAssignedTesting {
+	if visibility.ShowAssignedTesting {
 		visibilityPredicates = append(visibilityPredicates, `
 			EXISTS (
 				SELECT 1
@@ -246,44 +311,105 @@ func (r *GormRepository) fetchRelatedProblems(
 				WHERE ptr.problem_problem_id = p.problem_id AND ptr.user_user_id = ?
 			)
 		`)
-		visibilityArgs = append(visibilityArgs, userID)
+		visibilityArgs = append(visibilityArgs, visibility.UserID)
 	}
 
 	// Pending review for reviewers with global view
-	if showAllPendingReview {
+	if visibility.ShowAllPendingReview {
 		visibilityPredicates = append(visibilityPredicates, "p.status = ?")
 		visibilityArgs = append(visibilityArgs, constant.ProblemStatusPendingReview)
 	}
 
+	// Problems of the contests whose staff the user is part of
+	if len(visibility.MemberContestIDs) > 0 {
+		visibilityPredicates = append(visibilityPredicates, `
+			p.target_contest_id IN ? OR EXISTS (
+				SELECT 1
+				FROM contest_problems mcp
+				WHERE mcp.problem_id = p.problem_id AND mcp.contest_id IN ?
+			)
+		`)
+		visibilityArgs = append(visibilityArgs, visibility.MemberContestIDs, visibility.MemberContestIDs)
+	}
+
 	// Global list all overrides everything else
-	if showAll {
-		visibilityPredicates = []string{"1 = 1"}
-		visibilityArgs = []interface{}{}
+	if !visibility.ShowAll {
+		tx = tx.Where("("+strings.Join(visibilityPredicates, " OR ")+")", visibilityArgs...)
+	}
+
+	if query.IsCompleted {
+		tx = tx.Where("p.status = ?", constant.ProblemStatusCompleted)
 	}
 
-	whereClause := strings.Join(visibilityPredicates, " OR ")
-	if onlyShowCompleted {
-		whereClause = "(" + whereClause + ") AND p.status = ?"
-		visibilityArgs = append(visibilityArgs, constant.ProblemStatusCompleted)
+	if len(query.Statuses) > 0 {
+		tx = tx.Where("p.status IN ?", query.Statuses)
 	}
 
-	fmt.Printf(
-		"fetchRelatedProblems | user=%s showAll=%v showCreated=%v showAllPendingReview=%v showAssignedTesting=%v onlyShowCompleted=%v where=%s args=%v\n",
-		userID,
-		showAll,
-		showCreated,
-		showAllPendingReview,
-		showAssignedTesting,
-		onlyShowCompleted,
-		whereClause,
-		visibilityArgs,
-	)
+	if query.ProblemDifficultyID != nil {
+		tx = tx.Where("("+latestDifficultySQL+") = ?", *query.ProblemDifficultyID)
+	}
 
-	if err := query.Where(whereClause, visibilityArgs...).Scan(&problems).Error; err != nil {
-		return nil, errors.Wrap(err, "failed to get all related problems")
+	if query.CreatorID != nil {
+		tx = tx.Where("p.creator_id = ?", *query.CreatorID)
 	}
 
-	return problems, nil
+	if query.ReviewerID != nil {
+		tx = tx.Where("p.reviewer_id = ?", *query.ReviewerID)
+	}
+
+	if query.TesterID != nil {
+		tx = tx.Where(`
+			EXISTS (
+				SELECT 1
+				FROM problem_testers ftr
+				WHERE ftr.problem_problem_id = p.problem_id AND ftr.user_user_id = ?
+			)
+		`, *query.TesterID)
+	}
+
+	if query.TargetContestID != nil {
+		tx = tx.Where("p.target_contest_id = ?", *query.TargetContestID)
+	}
+
+	if query.AssignedContestID != nil {
+		tx = tx.Where(`
+			EXISTS (
+				SELECT 1
+				FROM contest_problems fcp
+				WHERE fcp.problem_id = p.problem_id AND fcp.contest_id = ?
+			)
+		`, *query.AssignedContestID)
+	}
+
+	if query.CreatedAfter != nil {
+		tx = tx.Where("p.created_at >= ?", *query.CreatedAfter)
+	}
+
+	if query.CreatedBefore != nil {
+		tx = tx.Where("p.created_at < ?", *query.CreatedBefore)
+	}
+
+	return tx
+}
+
+// sortExpression is the SQL that problems are sorted by. Statuses are sorted
+// in the order of the workflow rather than alphabetically.
+func sortExpression(sort SortField) string {
+	switch sort {
+	case SortFieldUpdatedAt:
+		return "p.updated_at"
+	case SortFieldStatus:
+		var b strings.Builder
+		b.WriteString("CASE p.status")
+		for _, status := range statusOrder {
+			fmt.Fprintf(&b, " WHEN '%s' THEN %d", status, statusRank(status))
+		}
+
+		fmt.Fprintf(&b, " ELSE %d END", len(statusOrder))
+		return b.String()
+	default:
+		return "p.created_at"
+	}
 }
 
 func (r *GormRepository) fetchTitlesForVersions(
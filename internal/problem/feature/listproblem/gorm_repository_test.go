@@ -0,0 +1,80 @@
+package listproblem
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestFetchProblemPageListsTiedAssignmentsOnce(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{DisableForeignKeyConstraintWhenMigrating: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every connection to an in-memory database gets a database of its own.
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(&database.User{}, &database.Contest{}, &database.Problem{}, &database.ContestProblem{}); err != nil {
+		t.Fatal(err)
+	}
+
+	creator := database.User{UserID: uuid.New(), Username: "creator", Email: "creator@example.com"}
+	if err := db.Create(&creator).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	firstContestID, secondContestID := uuid.New(), uuid.New()
+	if firstContestID.String() > secondContestID.String() {
+		firstContestID, secondContestID = secondContestID, firstContestID
+	}
+
+	if err := db.Create(&[]database.Contest{
+		{ContestID: firstContestID, Title: "First"},
+		{ContestID: secondContestID, Title: "Second"},
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	problem := database.Problem{ProblemID: uuid.New(), ProblemDraftID: uuid.New(), CreatorID: creator.UserID}
+	if err := db.Create(&problem).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	assignedAt := time.Now()
+	if err := db.Create(&[]database.ContestProblem{
+		{ContestID: firstContestID, ProblemID: problem.ProblemID, Position: 1, CreatedAt: assignedAt},
+		{ContestID: secondContestID, ProblemID: problem.ProblemID, Position: 1, CreatedAt: assignedAt},
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err := NewGormRepository(db).fetchProblemPage(
+		context.Background(),
+		db,
+		Visibility{UserID: creator.UserID},
+		&Query{},
+		Page{Limit: DefaultLimit},
+	)
+	if err != nil {
+		t.Fatalf("fetchProblemPage() error = %v", err)
+	}
+
+	if len(problems) != 1 {
+		t.Fatalf("fetchProblemPage() returned %d problems, want 1", len(problems))
+	}
+
+	if got := problems[0].AssignedContestID.UUID; got != secondContestID {
+		t.Errorf("assigned contest = %s, want %s", got, secondContestID)
+	}
+}
@@ -0,0 +1,41 @@
+package listproblem
+
+import (
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
+	"github.com/google/uuid"
+)
+
+type SortField string
+
+const (
+	SortFieldCreatedAt SortField = "created_at"
+	SortFieldUpdatedAt SortField = "updated_at"
+	SortFieldStatus    SortField = "status"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 100
+)
+
+// Query filters the problems the user can see. Every filter is optional, and
+// the problems come newest first unless sorted otherwise.
+type Query struct {
+	IsCompleted         bool                     `query:"is_completed"`
+	Statuses            []constant.ProblemStatus `query:"status"                validate:"omitempty,dive,problem_status"`
+	ProblemDifficultyID *uuid.UUID               `query:"problem_difficulty_id"`
+	CreatorID           *uuid.UUID               `query:"creator_id"`
+	ReviewerID          *uuid.UUID               `query:"reviewer_id"`
+	TesterID            *uuid.UUID               `query:"tester_id"`
+	TargetContestID     *uuid.UUID               `query:"target_contest_id"`
+	AssignedContestID   *uuid.UUID               `query:"assigned_contest_id"`
+	CreatedAfter        *time.Time               `query:"created_after"`
+	CreatedBefore       *time.Time               `query:"created_before"`
+	Sort                SortField                `query:"sort"                  validate:"omitempty,oneof=created_at updated_at status"`
+	Order               string                   `query:"order"                 validate:"omitempty,oneof=asc desc"`
+	Cursor              string                   `query:"cursor"`
+	Limit               int                      `query:"limit"                 validate:"omitempty,min=1,max=100"`
+}
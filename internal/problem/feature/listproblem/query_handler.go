@@ -5,93 +5,162 @@ import (
 
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
 
 	"emperror.dev/errors"
+	"github.com/go-playground/validator"
 	"github.com/google/uuid"
 )
 
+// Visibility decides which problems the user can see at all, before the
+// filters of the query apply.
+type Visibility struct {
+	UserID               uuid.UUID
+	ShowAll              bool
+	ShowAllPendingReview bool
+	ShowAssignedTesting  bool
+	MemberContestIDs     []uuid.UUID
+}
+
+// Page selects the problems after the cursor, if any, in the given order.
+type Page struct {
+	Sort       SortField
+	Descending bool
+	After      *Cursor
+	Limit      int
+}
+
 type Repository interface {
-	GetAllRelatedProblems(
-		ctx context.Context,
-		userID uuid.UUID,
-		showAll bool,
-		showCreated bool,
-		showAllPendingReview bool,
-		showAssignedTesting bool,
-		onlyShowCompleted bool,
-	) ([]ResponseProblem, error)
+	CountProblems(ctx context.Context, visibility Visibility, query *Query) (int64, error)
+	GetProblems(ctx context.Context, visibility Visibility, query *Query, page Page) ([]ResponseProblem, error)
 }
 
 type QueryHandler struct {
 	repo         Repository
+	validator    *validator.Validate
 	authProvider contract.AuthProvider
 }
 
-func NewQueryHandler(repo Repository, authProvider contract.AuthProvider) *QueryHandler {
+func NewQueryHandler(
+	repo Repository,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+) *QueryHandler {
 	return &QueryHandler{
 		repo:         repo,
+		validator:    validator,
 		authProvider: authProvider,
 	}
 }
 
-func (q *QueryHandler) Handle(ctx context.Context, onlyShowCompleted bool) (*Response, error) {
+func (q *QueryHandler) Handle(ctx context.Context, query *Query) (*Response, error) {
+	if query == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := q.validator.Struct(query); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	visibility, err := q.getVisibility(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page := Page{
+		Sort:       query.Sort,
+		Descending: query.Order != "asc",
+		Limit:      query.Limit,
+	}
+
+	if page.Sort == "" {
+		page.Sort = SortFieldCreatedAt
+	}
+
+	if page.Limit == 0 {
+		page.Limit = DefaultLimit
+	}
+
+	if query.Cursor != "" {
+		page.After, err = decodeCursor(query.Cursor, page.Sort, page.Descending)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	total, err := q.repo.CountProblems(ctx, visibility, query)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to count problems")
+	}
+
+	// One problem more than asked for tells whether there is another page.
+	problems, err := q.repo.GetProblems(ctx, visibility, query, Page{
+		Sort:       page.Sort,
+		Descending: page.Descending,
+		After:      page.After,
+		Limit:      page.Limit + 1,
+	})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get problems")
+	}
+
+	response := &Response{
+		Problems:   problems,
+		TotalCount: total,
+	}
+
+	if len(problems) > page.Limit {
+		response.Problems = problems[:page.Limit]
+
+		nextCursor, err := newCursor(page.Sort, page.Descending, response.Problems[page.Limit-1]).Encode()
+		if err != nil {
+			return nil, err
+		}
+
+		response.NextCursor = &nextCursor
+	}
+
+	return response, nil
+}
+
+func (q *QueryHandler) getVisibility(ctx context.Context) (Visibility, error) {
 	user, err := q.authProvider.MustGetUser(ctx)
 	if err != nil {
-		return nil, errors.WrapIf(err, "failed to get user ID from auth provider")
+		return Visibility{}, errors.WrapIf(err, "failed to get user ID from auth provider")
 	}
 
 	details, err := q.authProvider.MustGetUserDetails(ctx, user.UserID)
 	if err != nil {
-		return nil, errors.WrapIf(err, "failed to get user details from auth provider")
+		return Visibility{}, errors.WrapIf(err, "failed to get user details from auth provider")
 	}
 
-	var (
-		showAll              = false
-		showCreated          = false
-		showAllPendingReview = false
-		showAssignedTesting  = false
-	)
+	visibility := Visibility{
+		UserID: user.UserID,
+	}
 
 	if details.IsSuperAdmin {
-		showAll = true
-		showCreated = true
-		showAllPendingReview = true
-		showAssignedTesting = true
+		visibility.ShowAll = true
 	} else {
 		for _, p := range details.Permissions {
-			if p == constant.PermissionProblemListAll {
-				showAll = true
-				break
-			}
-
-			if p == constant.PermissionProblemListCreatedOwn {
-				showCreated = true
-			}
-
-			if p == constant.PermissionProblemListAwaitingReviewAll {
-				showAllPendingReview = true
-			}
-
-			if p == constant.PermissionProblemListAssignedTest {
-				showAssignedTesting = true
+			switch p {
+			case constant.PermissionProblemListAll:
+				visibility.ShowAll = true
+			case constant.PermissionProblemListAwaitingReviewAll:
+				visibility.ShowAllPendingReview = true
+			case constant.PermissionProblemListAssignedTest:
+				visibility.ShowAssignedTesting = true
 			}
 		}
 	}
 
-	problems, err := q.repo.GetAllRelatedProblems(
-		ctx,
-		user.UserID,
-		showAll,
-		showCreated,
-		showAllPendingReview,
-		showAssignedTesting,
-		onlyShowCompleted,
-	)
-	if err != nil {
-		return nil, err
+	// Without the global permission, the user's contest staff roles still let
+	// them see the problems of those contests.
+	if !visibility.ShowAll {
+		visibility.MemberContestIDs, err = q.authProvider.ContestsWhereCan(ctx, constant.PermissionProblemListAll)
+		if err != nil {
+			return Visibility{}, errors.WrapIf(err, "failed to get contests from auth provider")
+		}
 	}
 
-	return &Response{
-		Problems: problems,
-	}, nil
+	return visibility, nil
 }
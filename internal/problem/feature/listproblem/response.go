@@ -40,5 +40,7 @@ type ResponseProblem struct {
 }
 
 type Response struct {
-	Problems []ResponseProblem `json:"problems"`
+	Problems   []ResponseProblem `json:"problems"`
+	TotalCount int64             `json:"total_count"`
+	NextCursor *string           `json:"next_cursor"` // Null on the last page
 }
@@ -0,0 +1,73 @@
+package liststatushistory
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) GetProblemAccess(ctx context.Context, problemID uuid.UUID) (*problem.Access, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var p database.Problem
+	if err := db.WithContext(ctx).
+		Model(&database.Problem{}).
+		Preload("Testers").
+		Preload("ContestProblems").
+		Where("problem_id = ?", problemID).
+		First(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithStack(problem.ErrProblemNotFound)
+		}
+
+		return nil, errors.WrapIf(err, "failed to get problem access")
+	}
+
+	return problem.NewAccess(&p), nil
+}
+
+func (r *GormRepository) GetStatusHistory(ctx context.Context, problemID uuid.UUID) ([]ResponseEntry, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var entries []database.ProblemStatusHistory
+	if err := db.WithContext(ctx).
+		Preload("Actor").
+		Where("problem_id = ?", problemID).
+		Order("created_at ASC").
+		Find(&entries).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get problem status history")
+	}
+
+	history := make([]ResponseEntry, len(entries))
+	for i, entry := range entries {
+		history[i] = ResponseEntry{
+			HistoryID:  entry.ProblemStatusHistoryID,
+			Event:      entry.Event,
+			FromStatus: entry.FromStatus,
+			ToStatus:   entry.ToStatus,
+			Reason:     entry.Reason,
+			Actor: ResponseUser{
+				UserID:   entry.Actor.UserID,
+				Username: entry.Actor.Username,
+			},
+			CreatedAt: entry.CreatedAt,
+		}
+	}
+
+	return history, nil
+}
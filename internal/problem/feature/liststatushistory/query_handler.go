@@ -0,0 +1,68 @@
+package liststatushistory
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	GetProblemAccess(ctx context.Context, problemID uuid.UUID) (*problem.Access, error)
+	GetStatusHistory(ctx context.Context, problemID uuid.UUID) ([]ResponseEntry, error)
+}
+
+type QueryHandler struct {
+	repo         Repository
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+}
+
+func NewQueryHandler(
+	repo Repository,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+) *QueryHandler {
+	return &QueryHandler{
+		repo:         repo,
+		validator:    validator,
+		authProvider: authProvider,
+	}
+}
+
+func (q *QueryHandler) Handle(ctx context.Context, query *Query) (*Response, error) {
+	if query == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := q.validator.Struct(query); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	access, err := q.repo.GetProblemAccess(ctx, query.ProblemID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get problem access")
+	}
+
+	scope, err := problem.GetReadScope(ctx, q.authProvider)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get read scope")
+	} else if !scope.Allows(access) {
+		return nil, customerror.NewNoPermissionError(constant.PermissionProblemReadDetailsAny)
+	}
+
+	history, err := q.repo.GetStatusHistory(ctx, query.ProblemID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get problem status history")
+	}
+
+	return &Response{
+		History: history,
+	}, nil
+}
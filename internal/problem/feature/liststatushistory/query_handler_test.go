@@ -0,0 +1,77 @@
+package liststatushistory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/contracttest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+type fakeRepository struct {
+	access *problem.Access
+}
+
+func (r *fakeRepository) GetProblemAccess(context.Context, uuid.UUID) (*problem.Access, error) {
+	return r.access, nil
+}
+
+func (r *fakeRepository) GetStatusHistory(context.Context, uuid.UUID) ([]ResponseEntry, error) {
+	return []ResponseEntry{{Event: "submitted"}}, nil
+}
+
+func TestHandleChecksAccess(t *testing.T) {
+	setterID := uuid.New()
+	access := &problem.Access{
+		Status:    constant.ProblemStatusPendingReview,
+		CreatorID: setterID,
+	}
+
+	tests := []struct {
+		name        string
+		permissions []string
+		asSetter    bool
+		wantAllowed bool
+	}{
+		{name: "unrelated user", wantAllowed: false},
+		{name: "setter", asSetter: true, wantAllowed: true},
+		{name: "reader of every problem", permissions: []string{constant.PermissionProblemReadDetailsAny}, wantAllowed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			if tt.asSetter {
+				userID = setterID
+			}
+
+			handler := NewQueryHandler(
+				&fakeRepository{access: access},
+				validator.New(),
+				contracttest.NewAuthProvider(userID, &contract.AuthUserDetails{Permissions: tt.permissions}),
+			)
+
+			response, err := handler.Handle(context.Background(), &Query{ProblemID: uuid.New()})
+			if tt.wantAllowed && (err != nil || len(response.History) != 1) {
+				t.Fatalf("Handle() = %v, %v, want the history", response, err)
+			} else if !tt.wantAllowed && !errors.Is(err, customerror.ErrBaseNoPermission) {
+				t.Fatalf("Handle() error = %v, want no permission", err)
+			}
+		})
+	}
+}
+
+func TestHandleValidatesQuery(t *testing.T) {
+	handler := NewQueryHandler(&fakeRepository{}, validator.New(), contracttest.NewAuthProvider(uuid.New(), &contract.AuthUserDetails{}))
+
+	if _, err := handler.Handle(context.Background(), &Query{}); !errors.Is(err, customerror.ErrValidationFailed) {
+		t.Errorf("Handle() error = %v, want validation failed", err)
+	}
+}
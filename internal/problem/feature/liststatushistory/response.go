@@ -0,0 +1,26 @@
+package liststatushistory
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Response struct {
+	History []ResponseEntry `json:"history"`
+}
+
+type ResponseEntry struct {
+	HistoryID  uuid.UUID    `json:"history_id"`
+	Event      string       `json:"event"`
+	FromStatus string       `json:"from_status"`
+	ToStatus   string       `json:"to_status"`
+	Reason     string       `json:"reason"`
+	Actor      ResponseUser `json:"actor"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+type ResponseUser struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+}
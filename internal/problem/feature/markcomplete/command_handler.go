@@ -4,26 +4,20 @@ import (
 	"context"
 	"time"
 
-	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/workflow"
 
 	"emperror.dev/errors"
 	"github.com/go-playground/validator"
-	"github.com/google/uuid"
 )
 
 var ErrProblemNotAwaitingFinalCheck = errors.New("problem is not awaiting final check")
 
-type Repository interface {
-	MarkProblemCompleted(ctx context.Context, problemID uuid.UUID, completerID uuid.UUID, timestamp time.Time) error
-	GetProblemStatus(ctx context.Context, problemID uuid.UUID) (constant.ProblemStatus, error)
-}
-
 type CommandHandler struct {
-	repo         Repository
+	machine      *workflow.Machine
 	validator    *validator.Validate
 	authProvider contract.AuthProvider
 	uowFactory   contract.UnitOfWorkFactory
@@ -32,7 +26,7 @@ type CommandHandler struct {
 }
 
 func NewCommandHandler(
-	repo Repository,
+	machine *workflow.Machine,
 	validator *validator.Validate,
 	authProvider contract.AuthProvider,
 	uowFactory contract.UnitOfWorkFactory,
@@ -40,7 +34,7 @@ func NewCommandHandler(
 	l logger.Logger,
 ) *CommandHandler {
 	return &CommandHandler{
-		repo:         repo,
+		machine:      machine,
 		validator:    validator,
 		authProvider: authProvider,
 		uowFactory:   uowFactory,
@@ -63,27 +57,27 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) error {
 		return errors.WrapIf(err, "failed to get user from auth provider")
 	}
 
-	// TODO: check if the current user has permission to mark the problem as completed or not
-
 	uow := h.uowFactory.New()
 	return uowhelper.Do(ctx, uow, h.l, func(ctx context.Context) error {
-		if problemStatus, err := h.repo.GetProblemStatus(ctx, command.ProblemID); err != nil {
-			return errors.WrapIf(err, "failed to get problem status")
-		} else if problemStatus != constant.ProblemStatusAwaitingFinalCheck && problemStatus != constant.ProblemStatusCompleted {
+		if _, err := h.machine.Fire(
+			ctx,
+			command.ProblemID,
+			workflow.EventComplete,
+			"",
+		); errors.Is(err, workflow.ErrTransitionNotAllowed) {
 			return errors.WithStack(ErrProblemNotAwaitingFinalCheck)
+		} else if err != nil {
+			return errors.WrapIf(err, "failed to mark problem as completed")
 		}
 
 		timestamp := time.Now()
-		if err := h.repo.MarkProblemCompleted(ctx, command.ProblemID, user.UserID, timestamp); err != nil {
-			return errors.WrapIf(err, "failed to mark problem as completed")
-		}
 
 		details, err := h.authProvider.MustGetUserDetails(ctx, user.UserID)
 		if err != nil {
 			return errors.WrapIf(err, "failed to get user details")
 		}
 
-		if err := h.broadcaster.BroadcastCompletedMessage(command.ProblemID, contract.MessageUser{
+		if err := h.broadcaster.BroadcastCompletedMessage(ctx, command.ProblemID, contract.MessageUser{
 			UserID:   user.UserID,
 			Username: details.Username,
 		}, timestamp); err != nil {
@@ -0,0 +1,8 @@
+package retargetproblem
+
+import "github.com/google/uuid"
+
+type Command struct {
+	ProblemID uuid.UUID `param:"problem_id" validate:"required"`
+	ContestID uuid.UUID `json:"contest_id"  validate:"required"`
+}
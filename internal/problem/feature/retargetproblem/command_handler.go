@@ -0,0 +1,139 @@
+package retargetproblem
+
+import (
+	"context"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrContestNotFound        = errors.New("contest not found")
+	ErrProblemAlreadyAssigned = errors.New("problem is already assigned to the contest")
+)
+
+type Problem struct {
+	contest.ProposedProblem
+	CreatorID uuid.UUID
+}
+
+type Repository interface {
+	GetProblem(ctx context.Context, problemID uuid.UUID) (*Problem, error)
+	GetContest(ctx context.Context, contestID uuid.UUID) (*contest.ProposalTarget, error)
+	IsProblemAssigned(ctx context.Context, problemID uuid.UUID, contestID uuid.UUID) (bool, error)
+	SetTargetContest(ctx context.Context, problemID uuid.UUID, contestID uuid.UUID, updatedAt time.Time) error
+}
+
+type CommandHandler struct {
+	repo         Repository
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+	revoker      contract.RoomAccessRevoker
+	uowFactory   contract.UnitOfWorkFactory
+	l            logger.Logger
+}
+
+func NewCommandHandler(
+	repo Repository,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+	revoker contract.RoomAccessRevoker,
+	uowFactory contract.UnitOfWorkFactory,
+	l logger.Logger,
+) *CommandHandler {
+	return &CommandHandler{
+		repo:         repo,
+		validator:    validator,
+		authProvider: authProvider,
+		revoker:      revoker,
+		uowFactory:   uowFactory,
+		l:            l,
+	}
+}
+
+// Handle proposes a completed problem for another contest. Only the target
+// changes: the problem keeps its versions, its status and the contests it is
+// already assigned to, so that it can be reused across a contest series.
+func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Response, error) {
+	if command == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(command); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	user, err := h.authProvider.MustGetUser(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get user from auth provider")
+	}
+
+	uow := h.uowFactory.New()
+	response, err := uowhelper.DoWithResult(ctx, uow, h.l, func(ctx context.Context) (*Response, error) {
+		p, err := h.repo.GetProblem(ctx, command.ProblemID)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to get problem")
+		} else if p == nil {
+			return nil, errors.WithStack(problem.ErrProblemNotFound)
+		}
+
+		// Setters may propose their own problems; anyone else needs to be able
+		// to assign problems to the target contest.
+		if p.CreatorID != user.UserID {
+			if can, err := h.authProvider.CanInContest(
+				ctx,
+				command.ContestID,
+				constant.PermissionContestAssignProblemAny,
+			); err != nil {
+				return nil, errors.WrapIf(err, "failed to check permission")
+			} else if !can {
+				return nil, customerror.NewNoPermissionError(constant.PermissionContestAssignProblemAny)
+			}
+		}
+
+		c, err := h.repo.GetContest(ctx, command.ContestID)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to get contest")
+		} else if c == nil {
+			return nil, errors.WithStack(ErrContestNotFound)
+		}
+
+		now := time.Now()
+		if err := contest.CheckProposal(p.ProposedProblem, *c, now); err != nil {
+			return nil, err
+		}
+
+		if assigned, err := h.repo.IsProblemAssigned(ctx, command.ProblemID, command.ContestID); err != nil {
+			return nil, errors.WrapIf(err, "failed to check if problem is assigned")
+		} else if assigned {
+			return nil, errors.WithStack(ErrProblemAlreadyAssigned)
+		}
+
+		if err := h.repo.SetTargetContest(ctx, command.ProblemID, command.ContestID, now); err != nil {
+			return nil, errors.WrapIf(err, "failed to set target contest")
+		}
+
+		return &Response{
+			ProblemID:       command.ProblemID,
+			TargetContestID: command.ContestID,
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The staff of the previous target contest no longer see the problem.
+	h.revoker.RecheckProblemRoom(command.ProblemID)
+
+	return response, nil
+}
@@ -0,0 +1,122 @@
+package retargetproblem
+
+import (
+	"context"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) GetProblem(ctx context.Context, problemID uuid.UUID) (*Problem, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var p database.Problem
+	if err := db.WithContext(ctx).
+		Select("status", "creator_id").
+		Where("problem_id = ?", problemID).
+		First(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, errors.WrapIf(err, "failed to get problem")
+	}
+
+	var version database.ProblemVersion
+	if err := db.WithContext(ctx).
+		Select("time_limit_ms", "memory_limit_mb").
+		Where("problem_id = ?", problemID).
+		Order("created_at DESC").
+		First(&version).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.WrapIf(err, "failed to get latest problem version limits")
+	}
+
+	return &Problem{
+		ProposedProblem: contest.ProposedProblem{
+			Status:        constant.FromStringToProblemStatus(p.Status),
+			TimeLimitMs:   version.TimeLimitMs,
+			MemoryLimitMb: version.MemoryLimitMb,
+		},
+		CreatorID: p.CreatorID,
+	}, nil
+}
+
+func (r *GormRepository) GetContest(ctx context.Context, contestID uuid.UUID) (*contest.ProposalTarget, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var c database.Contest
+	if err := db.WithContext(ctx).
+		Select("phase", "deadline_datetime", "max_time_limit_ms", "max_memory_limit_mb").
+		Where("contest_id = ?", contestID).
+		First(&c).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, errors.WrapIf(err, "failed to get contest")
+	}
+
+	return &contest.ProposalTarget{
+		Phase:            constant.FromStringToContestPhase(c.Phase),
+		DeadlineDatetime: c.DeadlineDatetime,
+		Limits: contest.Limits{
+			MaxTimeLimitMs:   c.MaxTimeLimitMs,
+			MaxMemoryLimitMb: c.MaxMemoryLimitMb,
+		},
+	}, nil
+}
+
+func (r *GormRepository) IsProblemAssigned(
+	ctx context.Context,
+	problemID uuid.UUID,
+	contestID uuid.UUID,
+) (bool, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var count int64
+	if err := db.WithContext(ctx).
+		Model(&database.ContestProblem{}).
+		Where("problem_id = ? AND contest_id = ?", problemID, contestID).
+		Count(&count).Error; err != nil {
+		return false, errors.WrapIf(err, "failed to check if problem is assigned")
+	}
+
+	return count > 0, nil
+}
+
+func (r *GormRepository) SetTargetContest(
+	ctx context.Context,
+	problemID uuid.UUID,
+	contestID uuid.UUID,
+	updatedAt time.Time,
+) error {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	if err := db.WithContext(ctx).
+		Model(&database.Problem{}).
+		Where("problem_id = ?", problemID).
+		Updates(map[string]any{
+			"target_contest_id": contestID,
+			"updated_at":        updatedAt,
+		}).Error; err != nil {
+		return errors.WrapIf(err, "failed to update target contest")
+	}
+
+	return nil
+}
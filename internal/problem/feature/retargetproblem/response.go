@@ -0,0 +1,8 @@
+package retargetproblem
+
+import "github.com/google/uuid"
+
+type Response struct {
+	ProblemID       uuid.UUID `json:"problem_id"`
+	TargetContestID uuid.UUID `json:"target_contest_id"`
+}
@@ -4,12 +4,11 @@ import (
 	"context"
 	"time"
 
-	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
-	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/dto"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/workflow"
 
 	"emperror.dev/errors"
 	"github.com/go-playground/validator"
@@ -18,9 +17,10 @@ import (
 
 var ErrProblemNotPendingReview = errors.New("problem not pending review")
 
-type Problem struct {
-	Status  constant.ProblemStatus
-	DraftID uuid.UUID
+var decisionEvents = map[Decision]workflow.Event{
+	DecisionApprove:       workflow.EventApprove,
+	DecisionReject:        workflow.EventReject,
+	DecisionNeedsRevision: workflow.EventRequestRevision,
 }
 
 type Repository interface {
@@ -32,14 +32,12 @@ type Repository interface {
 		versionID uuid.UUID,
 		createdAt time.Time,
 	) (uuid.UUID, error)
-	GetProblem(ctx context.Context, problemID uuid.UUID) (dto.ProblemStatusAndVersion, error)
-	UpdateProblemStatus(ctx context.Context, problemID uuid.UUID, status constant.ProblemStatus) error
 	UpdateProblemReviewer(ctx context.Context, problemID uuid.UUID, reviewerID uuid.UUID) error
-	SetProblemDraftActive(ctx context.Context, problemDraftID uuid.UUID) error
 }
 
 type CommandHandler struct {
 	repo         Repository
+	machine      *workflow.Machine
 	validator    *validator.Validate
 	authProvider contract.AuthProvider
 	uowFactory   contract.UnitOfWorkFactory
@@ -49,6 +47,7 @@ type CommandHandler struct {
 
 func NewCommandHandler(
 	repo Repository,
+	machine *workflow.Machine,
 	validator *validator.Validate,
 	authProvider contract.AuthProvider,
 	uowFactory contract.UnitOfWorkFactory,
@@ -57,6 +56,7 @@ func NewCommandHandler(
 ) *CommandHandler {
 	return &CommandHandler{
 		repo:         repo,
+		machine:      machine,
 		validator:    validator,
 		authProvider: authProvider,
 		uowFactory:   uowFactory,
@@ -83,13 +83,15 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Respons
 
 	uow := h.uowFactory.New()
 	return uowhelper.DoWithResult(ctx, uow, h.l, func(ctx context.Context) (*Response, error) {
-		problem, err := h.repo.GetProblem(ctx, command.ProblemID)
-		if err != nil {
-			return nil, errors.WrapIf(err, "failed to get problem")
-		}
-
-		if problem.Status != constant.ProblemStatusPendingReview {
+		if _, err := h.machine.Fire(
+			ctx,
+			command.ProblemID,
+			decisionEvents[command.Decision],
+			command.Comment,
+		); errors.Is(err, workflow.ErrTransitionNotAllowed) {
 			return nil, errors.WithStack(ErrProblemNotPendingReview)
+		} else if err != nil {
+			return nil, errors.WrapIf(err, "failed to update problem status")
 		}
 
 		versionID, err := h.repo.GetLatestProblemVersionID(ctx, command.ProblemID)
@@ -104,26 +106,6 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Respons
 			return nil, errors.WrapIf(err, "failed to create review")
 		}
 
-		var problemStatus constant.ProblemStatus
-		switch command.Decision {
-		case DecisionApprove:
-			problemStatus = constant.ProblemStatusPendingTesting
-		case DecisionReject:
-			problemStatus = constant.ProblemStatusRejected
-		case DecisionNeedsRevision:
-			problemStatus = constant.ProblemStatusNeedsRevision
-		}
-
-		if err := h.repo.UpdateProblemStatus(ctx, command.ProblemID, problemStatus); err != nil {
-			return nil, errors.WrapIf(err, "failed to update problem status")
-		}
-
-		if command.Decision == DecisionNeedsRevision {
-			if err := h.repo.SetProblemDraftActive(ctx, problem.DraftID); err != nil {
-				return nil, errors.WrapIf(err, "failed to set problem draft active")
-			}
-		}
-
 		if err := h.repo.UpdateProblemReviewer(ctx, command.ProblemID, user.UserID); err != nil {
 			return nil, errors.WrapIf(err, "failed to update problem reviewer")
 		}
@@ -133,7 +115,7 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Respons
 			return nil, errors.WrapIf(err, "failed to get user details")
 		}
 
-		if err := h.broadcaster.BroadcastReviewedMessage(command.ProblemID, contract.MessageUser{
+		if err := h.broadcaster.BroadcastReviewedMessage(ctx, command.ProblemID, contract.MessageUser{
 			UserID:   user.UserID,
 			Username: details.Username,
 		}, string(command.Decision), timestamp); err != nil {
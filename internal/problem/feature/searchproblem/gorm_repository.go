@@ -0,0 +1,296 @@
+package searchproblem
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+type detailRow struct {
+	ID         uuid.UUID `gorm:"column:id"`
+	Timestamp  time.Time `gorm:"column:timestamp"`
+	Language   string    `gorm:"column:language"`
+	Title      string    `gorm:"column:title"`
+	Background string    `gorm:"column:background"`
+	Statement  string    `gorm:"column:statement"`
+	Note       string    `gorm:"column:note"`
+	Rank       float64   `gorm:"column:rank"`
+}
+
+func (d detailRow) fields() []Field {
+	return []Field{
+		{Name: "title", Text: d.Title},
+		{Name: "statement", Text: d.Statement},
+		{Name: "background", Text: d.Background},
+		{Name: "note", Text: d.Note},
+	}
+}
+
+type messageRow struct {
+	MessageID      uuid.UUID      `gorm:"column:message_id"`
+	ProblemID      uuid.UUID      `gorm:"column:problem_id"`
+	SenderID       uuid.UUID      `gorm:"column:sender_id"`
+	SenderUsername sql.NullString `gorm:"column:sender_username"`
+	Content        string         `gorm:"column:content"`
+	CreatedAt      time.Time      `gorm:"column:created_at"`
+	Rank           float64        `gorm:"column:rank"`
+}
+
+var detailColumns = []string{"d.title", "d.background", "d.statement", "d.note"}
+
+func (r *GormRepository) SearchProblems(ctx context.Context, scope *problem.ReadScope, search Search) ([]Hit, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	vectorSQL := database.DetailSearchVectorSQL("d")
+	rankSQL, rankArgs := selectRank(db, search, vectorSQL)
+
+	// Only the latest version of a problem is searched, in each of its
+	// languages.
+	tx := db.WithContext(ctx).
+		Table("problem_version_details d").
+		Joins("JOIN problem_versions v ON v.problem_version_id = d.problem_version_id").
+		Joins("JOIN problems p ON p.problem_id = v.problem_id").
+		Select(`
+			p.problem_id as id,
+			p.updated_at as timestamp,
+			d.language,
+			d.title,
+			d.background,
+			d.statement,
+			d.note,
+		`+rankSQL, rankArgs...).
+		Where(`v.problem_version_id = (
+			SELECT lpv.problem_version_id
+			FROM problem_versions lpv
+			WHERE lpv.problem_id = p.problem_id
+			ORDER BY lpv.created_at DESC
+			LIMIT 1
+		)`)
+
+	tx = whereReadable(tx, scope)
+	tx = whereMatches(db, tx, search, vectorSQL, detailColumns...)
+
+	var rows []detailRow
+	if err := tx.
+		Order("rank DESC, p.updated_at DESC").
+		Limit(search.Limit).
+		Scan(&rows).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to search problem details")
+	}
+
+	hits := make([]Hit, 0, len(rows))
+	for _, row := range rows {
+		hits = append(hits, Hit{
+			ResponseResult: ResponseResult{
+				Kind:      ResultKindProblem,
+				ProblemID: &row.ID,
+				Language:  row.Language,
+				Title:     row.Title,
+				Timestamp: row.Timestamp,
+			},
+			Fields: row.fields(),
+			Rank:   row.Rank,
+		})
+	}
+
+	return hits, nil
+}
+
+func (r *GormRepository) SearchProblemDrafts(ctx context.Context, creatorID uuid.UUID, search Search) ([]Hit, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	vectorSQL := database.DetailSearchVectorSQL("d")
+	rankSQL, rankArgs := selectRank(db, search, vectorSQL)
+
+	// Submitted drafts are searched as problems instead.
+	tx := db.WithContext(ctx).
+		Table("problem_draft_details d").
+		Joins("JOIN problem_drafts pd ON pd.problem_draft_id = d.problem_draft_id").
+		Select(`
+			pd.problem_draft_id as id,
+			pd.updated_at as timestamp,
+			d.language,
+			d.title,
+			d.background,
+			d.statement,
+			d.note,
+		`+rankSQL, rankArgs...).
+		Where("pd.creator_id = ? AND pd.is_active = ? AND pd.deleted IS NULL", creatorID, true)
+
+	tx = whereMatches(db, tx, search, vectorSQL, detailColumns...)
+
+	var rows []detailRow
+	if err := tx.
+		Order("rank DESC, pd.updated_at DESC").
+		Limit(search.Limit).
+		Scan(&rows).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to search problem draft details")
+	}
+
+	hits := make([]Hit, 0, len(rows))
+	for _, row := range rows {
+		hits = append(hits, Hit{
+			ResponseResult: ResponseResult{
+				Kind:           ResultKindProblemDraft,
+				ProblemDraftID: &row.ID,
+				Language:       row.Language,
+				Title:          row.Title,
+				Timestamp:      row.Timestamp,
+			},
+			Fields: row.fields(),
+			Rank:   row.Rank,
+		})
+	}
+
+	return hits, nil
+}
+
+func (r *GormRepository) SearchChatMessages(ctx context.Context, scope *problem.ReadScope, search Search) ([]Hit, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	vectorSQL := database.ChatMessageSearchVectorSQL("m")
+	rankSQL, rankArgs := selectRank(db, search, vectorSQL)
+
+	tx := db.WithContext(ctx).
+		Table("problem_chat_messages m").
+		Joins("JOIN problems p ON p.problem_id = m.problem_id").
+		Joins("LEFT JOIN users u ON u.user_id = m.sender_id").
+		Select(`
+			m.message_id,
+			m.problem_id,
+			m.sender_id,
+			u.username as sender_username,
+			m.content,
+			m.created_at,
+		`+rankSQL, rankArgs...)
+
+	tx = whereReadable(tx, scope)
+	tx = whereMatches(db, tx, search, vectorSQL, "m.content")
+
+	var rows []messageRow
+	if err := tx.
+		Order("rank DESC, m.created_at DESC").
+		Limit(search.Limit).
+		Scan(&rows).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to search chat messages")
+	}
+
+	hits := make([]Hit, 0, len(rows))
+	for _, row := range rows {
+		hits = append(hits, Hit{
+			ResponseResult: ResponseResult{
+				Kind:      ResultKindChatMessage,
+				ProblemID: &row.ProblemID,
+				MessageID: &row.MessageID,
+				Sender: &ResponseUser{
+					UserID:   row.SenderID,
+					Username: row.SenderUsername.String,
+				},
+				Timestamp: row.CreatedAt,
+			},
+			Fields: []Field{{Name: "content", Text: row.Content}},
+			Rank:   row.Rank,
+		})
+	}
+
+	return hits, nil
+}
+
+// usesFullTextSearch reports whether the search can go through the
+// full-text indexes, which only exist on PostgreSQL.
+func usesFullTextSearch(db *gorm.DB, search Search) bool {
+	return db.Dialector.Name() == "postgres" && !search.PatternMatch
+}
+
+// selectRank is the selected rank column, which stays zero when the search
+// matches patterns.
+func selectRank(db *gorm.DB, search Search, vectorSQL string) (string, []any) {
+	if !usesFullTextSearch(db, search) {
+		return "0 as rank", nil
+	}
+
+	return "ts_rank(" + vectorSQL + ", " + database.TextSearchQuerySQL() + ") as rank", []any{search.Text}
+}
+
+// whereMatches keeps the rows matching the search. Without the full-text
+// search, every term has to appear in one of the columns.
+func whereMatches(db *gorm.DB, tx *gorm.DB, search Search, vectorSQL string, columns ...string) *gorm.DB {
+	if usesFullTextSearch(db, search) {
+		return tx.Where("("+vectorSQL+") @@ "+database.TextSearchQuerySQL(), search.Text)
+	}
+
+	for _, term := range search.Terms {
+		pattern := "%" + escapeLike(term) + "%"
+
+		predicates := make([]string, 0, len(columns))
+		args := make([]any, 0, len(columns))
+		for _, column := range columns {
+			predicates = append(predicates, "LOWER("+column+") LIKE ? ESCAPE '\\'")
+			args = append(args, pattern)
+		}
+
+		tx = tx.Where("("+strings.Join(predicates, " OR ")+")", args...)
+	}
+
+	return tx
+}
+
+// whereReadable keeps the problems, aliased p, within the read scope. It
+// follows problem.ReadScope.Allows.
+func whereReadable(tx *gorm.DB, scope *problem.ReadScope) *gorm.DB {
+	if scope.All {
+		return tx
+	}
+
+	predicates := []string{
+		"p.creator_id = ?",
+		"p.reviewer_id = ?",
+		`EXISTS (
+			SELECT 1
+			FROM problem_testers ptr
+			WHERE ptr.problem_problem_id = p.problem_id AND ptr.user_user_id = ?
+		)`,
+	}
+	args := []any{scope.UserID, scope.UserID, scope.UserID}
+
+	if scope.PendingReview {
+		predicates = append(predicates, "p.status = ?")
+		args = append(args, constant.ProblemStatusPendingReview)
+	}
+
+	if len(scope.ContestIDs) > 0 {
+		predicates = append(predicates, `
+			p.target_contest_id IN ? OR EXISTS (
+				SELECT 1
+				FROM contest_problems scp
+				WHERE scp.problem_id = p.problem_id AND scp.contest_id IN ?
+			)
+		`)
+		args = append(args, scope.ContestIDs, scope.ContestIDs)
+	}
+
+	return tx.Where("("+strings.Join(predicates, " OR ")+")", args...)
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
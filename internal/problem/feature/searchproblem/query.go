@@ -0,0 +1,23 @@
+package searchproblem
+
+type ResultKind string
+
+const (
+	ResultKindProblem      ResultKind = "problem"
+	ResultKindProblemDraft ResultKind = "problem_draft"
+	ResultKindChatMessage  ResultKind = "chat_message"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 50
+)
+
+// Query searches the statements of problems and drafts, in every language,
+// and the chat messages of problems. Every kind is searched unless some are
+// given.
+type Query struct {
+	Text  string       `query:"q"     validate:"required,max=200"`
+	Kinds []ResultKind `query:"kind"  validate:"omitempty,dive,oneof=problem problem_draft chat_message"`
+	Limit int          `query:"limit" validate:"omitempty,min=1,max=50"`
+}
@@ -0,0 +1,165 @@
+package searchproblem
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+var ErrNoSearchTerms = errors.New("search text has no words")
+
+// Search is what the repository looks for. Text goes to the full-text search
+// as it is, while Terms are matched as patterns when it is unavailable.
+type Search struct {
+	Text         string
+	Terms        []string
+	PatternMatch bool
+	Limit        int
+}
+
+type Field struct {
+	Name string
+	Text string
+}
+
+// Hit is a result found by the repository, with the searched fields in the
+// order they are preferred for the snippet.
+type Hit struct {
+	ResponseResult
+	Fields []Field
+	Rank   float64
+}
+
+type Repository interface {
+	SearchProblems(ctx context.Context, scope *problem.ReadScope, search Search) ([]Hit, error)
+	SearchProblemDrafts(ctx context.Context, creatorID uuid.UUID, search Search) ([]Hit, error)
+	SearchChatMessages(ctx context.Context, scope *problem.ReadScope, search Search) ([]Hit, error)
+}
+
+type QueryHandler struct {
+	repo         Repository
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+}
+
+func NewQueryHandler(
+	repo Repository,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+) *QueryHandler {
+	return &QueryHandler{
+		repo:         repo,
+		validator:    validator,
+		authProvider: authProvider,
+	}
+}
+
+func (q *QueryHandler) Handle(ctx context.Context, query *Query) (*Response, error) {
+	if query == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := q.validator.Struct(query); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	search := Search{
+		Text:  strings.TrimSpace(query.Text),
+		Terms: parseTerms(query.Text),
+		Limit: query.Limit,
+	}
+
+	if len(search.Terms) == 0 {
+		return nil, errors.WithStack(ErrNoSearchTerms)
+	}
+
+	search.PatternMatch = needsPatternMatch(search.Terms)
+	if search.Limit == 0 {
+		search.Limit = DefaultLimit
+	}
+
+	scope, err := problem.GetReadScope(ctx, q.authProvider)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get read scope")
+	}
+
+	kinds := query.Kinds
+	if len(kinds) == 0 {
+		kinds = []ResultKind{ResultKindProblem, ResultKindProblemDraft, ResultKindChatMessage}
+	}
+
+	var hits []Hit
+	for _, kind := range kinds {
+		var kindHits []Hit
+		switch kind {
+		case ResultKindProblem:
+			kindHits, err = q.repo.SearchProblems(ctx, scope, search)
+		case ResultKindProblemDraft:
+			kindHits, err = q.repo.SearchProblemDrafts(ctx, scope.UserID, search)
+		case ResultKindChatMessage:
+			kindHits, err = q.repo.SearchChatMessages(ctx, scope, search)
+		default:
+			continue
+		}
+
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to search "+string(kind))
+		}
+
+		hits = append(hits, kindHits...)
+	}
+
+	// Ranks only exist with the full-text search; without them, and between
+	// equal ranks, the most recent results come first.
+	slices.SortStableFunc(hits, func(a, b Hit) int {
+		if a.Rank != b.Rank {
+			if a.Rank > b.Rank {
+				return -1
+			}
+			return 1
+		}
+		return b.Timestamp.Compare(a.Timestamp)
+	})
+
+	if len(hits) > search.Limit {
+		hits = hits[:search.Limit]
+	}
+
+	results := make([]ResponseResult, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, toResult(hit, search.Terms))
+	}
+
+	return &Response{Results: results}, nil
+}
+
+// toResult takes the snippet from the first field that matches the terms.
+func toResult(hit Hit, terms []string) ResponseResult {
+	result := hit.ResponseResult
+	for _, field := range hit.Fields {
+		if snippet := highlight(field.Text, terms); snippet != nil {
+			result.Field = field.Name
+			result.Snippet = snippet
+			return result
+		}
+	}
+
+	for _, field := range hit.Fields {
+		if strings.TrimSpace(field.Text) != "" {
+			result.Field = field.Name
+			result.Snippet = excerpt(field.Text)
+			return result
+		}
+	}
+
+	result.Snippet = make([]SnippetSegment, 0)
+	return result
+}
@@ -0,0 +1,36 @@
+package searchproblem
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SnippetSegment is a piece of the text around a match. Highlighted segments
+// are the matched words themselves.
+type SnippetSegment struct {
+	Text        string `json:"text"`
+	Highlighted bool   `json:"highlighted"`
+}
+
+type ResponseUser struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+}
+
+type ResponseResult struct {
+	Kind           ResultKind       `json:"kind"`
+	ProblemID      *uuid.UUID       `json:"problem_id,omitempty"`
+	ProblemDraftID *uuid.UUID       `json:"problem_draft_id,omitempty"`
+	MessageID      *uuid.UUID       `json:"message_id,omitempty"`
+	Language       string           `json:"language,omitempty"`
+	Title          string           `json:"title,omitempty"`
+	Sender         *ResponseUser    `json:"sender,omitempty"`
+	Field          string           `json:"field"` // The field the snippet comes from
+	Snippet        []SnippetSegment `json:"snippet"`
+	Timestamp      time.Time        `json:"timestamp"`
+}
+
+type Response struct {
+	Results []ResponseResult `json:"results"`
+}
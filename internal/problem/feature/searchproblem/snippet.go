@@ -0,0 +1,183 @@
+package searchproblem
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	maxTerms       = 8
+	snippetContext = 60  // Runes kept before the first match
+	snippetLength  = 200 // Runes in a snippet, unless the first match is longer
+)
+
+// parseTerms picks the words to highlight out of the search text. Quotes,
+// excluded words and operators of the web search syntax are left out.
+func parseTerms(text string) []string {
+	var terms []string
+	for _, field := range strings.Fields(strings.ToLower(text)) {
+		if strings.HasPrefix(field, "-") || field == "or" {
+			continue
+		}
+
+		term := strings.TrimFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+
+		if term == "" || slices.Contains(terms, term) {
+			continue
+		}
+
+		terms = append(terms, term)
+		if len(terms) == maxTerms {
+			break
+		}
+	}
+
+	return terms
+}
+
+// needsPatternMatch reports whether the terms are written in a script without
+// spaces between words, which the full-text parser cannot split.
+func needsPatternMatch(terms []string) bool {
+	for _, term := range terms {
+		for _, r := range term {
+			if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul, unicode.Thai) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+type span struct {
+	start, end int
+}
+
+// highlight cuts a snippet out of the text around the first match of the
+// terms, ignoring case. It returns nil when no term matches.
+func highlight(text string, terms []string) []SnippetSegment {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	termRunes := make([][]rune, 0, len(terms))
+	for _, term := range terms {
+		if term != "" {
+			termRunes = append(termRunes, []rune(term))
+		}
+	}
+
+	// The longest term wins where several start at the same rune.
+	var matches []span
+	for i := 0; i < len(lower); {
+		longest := 0
+		for _, term := range termRunes {
+			if len(term) > longest && hasPrefixAt(lower, i, term) {
+				longest = len(term)
+			}
+		}
+
+		if longest == 0 {
+			i++
+			continue
+		}
+
+		matches = append(matches, span{i, i + longest})
+		i += longest
+	}
+
+	if len(matches) == 0 {
+		return nil
+	}
+
+	start := max(0, matches[0].start-snippetContext)
+	end := min(len(runes), max(start+snippetLength, matches[0].end))
+
+	var b snippetBuilder
+	if start > 0 {
+		b.add("…", false)
+	}
+
+	pos := start
+	for _, match := range matches {
+		if match.start >= end {
+			break
+		}
+
+		matchEnd := min(match.end, end)
+		b.add(string(runes[pos:match.start]), false)
+		b.add(string(runes[match.start:matchEnd]), true)
+		pos = matchEnd
+	}
+
+	b.add(string(runes[pos:end]), false)
+	if end < len(runes) {
+		b.add("…", false)
+	}
+
+	return b.segments
+}
+
+// excerpt is the beginning of the text, for results that matched in a way
+// the terms cannot show.
+func excerpt(text string) []SnippetSegment {
+	runes := []rune(text)
+
+	var b snippetBuilder
+	if len(runes) > snippetLength {
+		b.add(string(runes[:snippetLength]), false)
+		b.add("…", false)
+	} else {
+		b.add(text, false)
+	}
+
+	if b.segments == nil {
+		return make([]SnippetSegment, 0)
+	}
+
+	return b.segments
+}
+
+func hasPrefixAt(s []rune, i int, prefix []rune) bool {
+	return len(s)-i >= len(prefix) && slices.Equal(s[i:i+len(prefix)], prefix)
+}
+
+// snippetBuilder joins adjacent segments of the same kind and squeezes line
+// breaks and runs of spaces into single spaces.
+type snippetBuilder struct {
+	segments []SnippetSegment
+}
+
+func (b *snippetBuilder) add(text string, highlighted bool) {
+	var sb strings.Builder
+	lastSpace := false
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			if !lastSpace {
+				sb.WriteRune(' ')
+			}
+
+			lastSpace = true
+			continue
+		}
+
+		sb.WriteRune(r)
+		lastSpace = false
+	}
+
+	if sb.Len() == 0 {
+		return
+	}
+
+	if n := len(b.segments); n > 0 && b.segments[n-1].Highlighted == highlighted {
+		b.segments[n-1].Text += sb.String()
+		return
+	}
+
+	b.segments = append(b.segments, SnippetSegment{Text: sb.String(), Highlighted: highlighted})
+}
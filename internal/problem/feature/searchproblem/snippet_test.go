@@ -0,0 +1,64 @@
+package searchproblem
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTerms(t *testing.T) {
+	got := parseTerms(`"Segment Tree" -heap or  tree 线段树`)
+	want := []string{"segment", "tree", "线段树"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTerms() = %q, want %q", got, want)
+	}
+
+	if !needsPatternMatch(got) {
+		t.Error("needsPatternMatch() = false, want true for Chinese terms")
+	}
+
+	if needsPatternMatch(want[:2]) {
+		t.Error("needsPatternMatch() = true, want false for English terms")
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	got := highlight("Build a Segment tree\nover  the array.", []string{"segment", "tree"})
+	want := []SnippetSegment{
+		{Text: "Build a ", Highlighted: false},
+		{Text: "Segment", Highlighted: true},
+		{Text: " ", Highlighted: false},
+		{Text: "tree", Highlighted: true},
+		{Text: " over the array.", Highlighted: false},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("highlight() = %+v, want %+v", got, want)
+	}
+
+	if got := highlight("No match here", []string{"tree"}); got != nil {
+		t.Errorf("highlight() without a match = %+v, want nil", got)
+	}
+}
+
+func TestHighlightCutsLongText(t *testing.T) {
+	text := strings.Repeat("a ", 100) + "给定一棵线段树" + strings.Repeat(" b", 200)
+
+	got := highlight(text, []string{"线段树"})
+	if len(got) != 3 {
+		t.Fatalf("highlight() = %+v, want the match and the text around it", got)
+	}
+
+	if !strings.HasPrefix(got[0].Text, "…") || !strings.HasSuffix(got[0].Text, "给定一棵") {
+		t.Errorf("highlight() starts with %q, want the context before the match", got[0].Text)
+	}
+
+	if got[1] != (SnippetSegment{Text: "线段树", Highlighted: true}) {
+		t.Errorf("highlight() match = %+v, want the highlighted term", got[1])
+	}
+
+	if !strings.HasPrefix(got[2].Text, " b") || !strings.HasSuffix(got[2].Text, "…") {
+		t.Errorf("highlight() ends with %q, want the context after the match", got[2].Text)
+	}
+}
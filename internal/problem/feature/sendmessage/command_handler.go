@@ -20,7 +20,6 @@ var (
 )
 
 type Repository interface {
-	IsUserPartOfRoom(ctx context.Context, problemID uuid.UUID, userID uuid.UUID) (bool, error)
 	CreateChatMessage(ctx context.Context, command *Command, senderID uuid.UUID, createdAt time.Time) (uuid.UUID, error)
 	GetAttachmentByMediaIDs(ctx context.Context, mediaIDs []uuid.UUID) ([]contract.MessageAttachment, error)
 }
@@ -29,6 +28,7 @@ type CommandHandler struct {
 	repo         Repository
 	validator    *validator.Validate
 	authProvider contract.AuthProvider
+	policy       contract.RoomAccessPolicy
 	uowFactory   contract.UnitOfWorkFactory
 	broadcaster  contract.MessageBroadcaster
 	l            logger.Logger
@@ -38,6 +38,7 @@ func NewCommandHandler(
 	repo Repository,
 	validator *validator.Validate,
 	authProvider contract.AuthProvider,
+	policy contract.RoomAccessPolicy,
 	uowFactory contract.UnitOfWorkFactory,
 	broadcaster contract.MessageBroadcaster,
 	l logger.Logger,
@@ -46,6 +47,7 @@ func NewCommandHandler(
 		repo:         repo,
 		validator:    validator,
 		authProvider: authProvider,
+		policy:       policy,
 		uowFactory:   uowFactory,
 		broadcaster:  broadcaster,
 		l:            l,
@@ -68,7 +70,7 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) error {
 
 	uow := h.uowFactory.New()
 	return uowhelper.Do(ctx, uow, h.l, func(ctx context.Context) error {
-		if ok, err := h.repo.IsUserPartOfRoom(ctx, command.ProblemID, user.UserID); err != nil {
+		if ok, err := h.policy.CanAccessProblemRoom(ctx, user.UserID, command.ProblemID); err != nil {
 			return errors.WrapIf(err, "failed to check if user is part of room")
 		} else if !ok {
 			return errors.WithStack(ErrUserNotPartOfRoom)
@@ -91,7 +93,7 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) error {
 			return errors.WrapIf(err, "failed to get user details")
 		}
 
-		if err := h.broadcaster.BroadcastUserMessage(command.ProblemID, messageID, command.Content, contract.MessageUser{
+		if err := h.broadcaster.BroadcastUserMessage(ctx, command.ProblemID, messageID, command.Content, contract.MessageUser{
 			UserID:   user.UserID,
 			Username: details.Username,
 		}, attachments, timestamp); err != nil {
@@ -49,7 +49,7 @@ func (e *Endpoint) handle() websocket.Handler {
 			sendError("The media does not exist", websocket.ErrCodeInvalidPayload)
 			return
 		} else if errors.Is(err, ErrUserNotPartOfRoom) {
-			sendError("The user is not part of the room", websocket.ErrCodeInvalidPayload)
+			sendError("The user is not part of the room", websocket.ErrCodeAccessDenied)
 			return
 		} else if err != nil {
 			sendError("Failed to send message", websocket.ErrCodeInternalServerError)
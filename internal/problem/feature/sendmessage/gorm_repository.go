@@ -20,39 +20,6 @@ func NewGormRepository(db *gorm.DB) *GormRepository {
 	return &GormRepository{db: db}
 }
 
-func (r *GormRepository) IsUserPartOfRoom(ctx context.Context, problemID uuid.UUID, userID uuid.UUID) (bool, error) {
-	db := database.GetDBFromContext(ctx, r.db)
-
-	var p database.Problem
-	if err := db.WithContext(ctx).
-		Model(&database.Problem{}).
-		Preload("Testers").
-		Where("problem_id = ?", problemID).
-		First(&p).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return false, nil
-		}
-
-		return false, errors.WrapIf(err, "failed to check if user is part of room")
-	}
-
-	if p.CreatorID == userID || p.ReviewerID.UUID == userID {
-		return true, nil
-	}
-
-	if len(p.Testers) > 0 {
-		for _, tester := range p.Testers {
-			if tester.UserID == userID {
-				return true, nil
-			}
-		}
-
-		return false, nil
-	}
-
-	return true, nil
-}
-
 func (r *GormRepository) CreateChatMessage(
 	ctx context.Context,
 	command *Command,
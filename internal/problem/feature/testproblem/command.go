@@ -9,8 +9,16 @@ const (
 	StatusFailed Status = "failed"
 )
 
+// CommandSolutionResult is the verdict the tester observed when running one of
+// the version's solutions.
+type CommandSolutionResult struct {
+	SolutionID    uuid.UUID `json:"solution_id"    validate:"required"`
+	ActualVerdict string    `json:"actual_verdict" validate:"required,tester_verdict"`
+}
+
 type Command struct {
-	ProblemID uuid.UUID `param:"problem_id" validate:"required"`
-	Status    Status    `                   validate:"required,oneof=passed failed" json:"status"`
-	Comment   string    `                                                           json:"comment"`
+	ProblemID       uuid.UUID               `param:"problem_id" validate:"required"`
+	Status          Status                  `                   validate:"required,oneof=passed failed" json:"status"`
+	Comment         string                  `                                                           json:"comment"`
+	SolutionResults []CommandSolutionResult `                   validate:"dive"                         json:"solution_results"`
 }
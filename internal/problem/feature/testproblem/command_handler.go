@@ -10,7 +10,7 @@ import (
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
-	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/dto"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/workflow"
 
 	"emperror.dev/errors"
 	"github.com/go-playground/validator"
@@ -20,11 +20,14 @@ import (
 var (
 	ErrProblemNotPendingTesting = errors.New("problem is not pending testing")
 	ErrTesterNotAssigned        = errors.New("tester is not assigned to this problem")
+	ErrUnknownSolution          = errors.New("solution does not belong to the latest problem version")
+	ErrDuplicateSolutionResult  = errors.New("duplicate result for the same solution")
 )
 
-type ResultSummary struct {
-	TesterID uuid.UUID
-	Status   string
+type SolutionSummary struct {
+	SolutionID      uuid.UUID
+	Name            string
+	ExpectedVerdict string
 }
 
 type Repository interface {
@@ -36,15 +39,13 @@ type Repository interface {
 		versionID uuid.UUID,
 		createdAt time.Time,
 	) (uuid.UUID, error)
-	GetProblem(ctx context.Context, problemID uuid.UUID) (dto.ProblemStatusAndVersion, error)
-	UpdateProblemStatus(ctx context.Context, problemID uuid.UUID, status constant.ProblemStatus) error
-	SetProblemDraftActive(ctx context.Context, problemDraftID uuid.UUID) error
 	GetProblemTesterIDs(ctx context.Context, problemID uuid.UUID) ([]uuid.UUID, error)
-	GetTestResultsForVersion(ctx context.Context, versionID uuid.UUID) ([]ResultSummary, error)
+	GetVersionSolutions(ctx context.Context, versionID uuid.UUID) ([]SolutionSummary, error)
 }
 
 type CommandHandler struct {
 	repo         Repository
+	machine      *workflow.Machine
 	validator    *validator.Validate
 	authProvider contract.AuthProvider
 	uowFactory   contract.UnitOfWorkFactory
@@ -54,6 +55,7 @@ type CommandHandler struct {
 
 func NewCommandHandler(
 	repo Repository,
+	machine *workflow.Machine,
 	validator *validator.Validate,
 	authProvider contract.AuthProvider,
 	uowFactory contract.UnitOfWorkFactory,
@@ -62,6 +64,7 @@ func NewCommandHandler(
 ) *CommandHandler {
 	return &CommandHandler{
 		repo:         repo,
+		machine:      machine,
 		validator:    validator,
 		authProvider: authProvider,
 		uowFactory:   uowFactory,
@@ -86,13 +89,15 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Respons
 
 	uow := h.uowFactory.New()
 	return uowhelper.DoWithResult(ctx, uow, h.l, func(ctx context.Context) (*Response, error) {
-		problem, err := h.repo.GetProblem(ctx, command.ProblemID)
-		if err != nil {
-			return nil, errors.WrapIf(err, "failed to get problem")
+		event := workflow.EventFailTesting
+		if command.Status == StatusPassed {
+			event = workflow.EventPassTesting
 		}
 
-		if problem.Status != constant.ProblemStatusPendingTesting {
+		if err := h.machine.Check(ctx, command.ProblemID, event); errors.Is(err, workflow.ErrTransitionNotAllowed) {
 			return nil, errors.WithStack(ErrProblemNotPendingTesting)
+		} else if err != nil {
+			return nil, errors.WrapIf(err, "failed to check problem status")
 		}
 
 		testerIDs, err := h.repo.GetProblemTesterIDs(ctx, command.ProblemID)
@@ -113,6 +118,11 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Respons
 			return nil, errors.WrapIf(err, "failed to get latest problem version ID")
 		}
 
+		solutionResults, err := h.compareSolutionResults(ctx, versionID, command.SolutionResults)
+		if err != nil {
+			return nil, err
+		}
+
 		timestamp := time.Now()
 
 		resultID, err := h.repo.SaveTestResult(ctx, command, user.UserID, versionID, timestamp)
@@ -120,51 +130,11 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Respons
 			return nil, errors.WrapIf(err, "failed to create test result")
 		}
 
-		newStatus := problem.Status
-		switch command.Status {
-		case StatusPassed:
-			results, err := h.repo.GetTestResultsForVersion(ctx, versionID)
-			if err != nil {
-				return nil, errors.WrapIf(err, "failed to get test results for version")
-			}
-
-			allPassed := true
-			passedMap := make(map[uuid.UUID]struct{}, len(results))
-			for _, result := range results {
-				if result.Status == string(StatusFailed) {
-					allPassed = false
-					break
-				}
-				if result.Status == string(StatusPassed) {
-					passedMap[result.TesterID] = struct{}{}
-				}
-			}
-
-			if allPassed {
-				for _, testerID := range testerIDs {
-					if _, ok := passedMap[testerID]; !ok {
-						allPassed = false
-						break
-					}
-				}
-			}
-
-			if allPassed {
-				newStatus = constant.ProblemStatusAwaitingFinalCheck
-			} else {
-				newStatus = constant.ProblemStatusPendingTesting
-			}
-		case StatusFailed:
-			newStatus = constant.ProblemStatusTestingChangesRequested
-			if err := h.repo.SetProblemDraftActive(ctx, problem.DraftID); err != nil {
-				return nil, errors.WrapIf(err, "failed to set problem draft active")
-			}
-		}
-
-		if newStatus != problem.Status {
-			if err := h.repo.UpdateProblemStatus(ctx, command.ProblemID, newStatus); err != nil {
-				return nil, errors.WrapIf(err, "failed to update problem status")
-			}
+		// The problem only passes testing once every tester has passed it, so
+		// an earlier pass leaves it pending.
+		if _, err := h.machine.Fire(ctx, command.ProblemID, event, command.Comment); err != nil &&
+			!errors.Is(err, workflow.ErrGuardNotMet) {
+			return nil, errors.WrapIf(err, "failed to update problem status")
 		}
 
 		details, err := h.authProvider.MustGetUserDetails(ctx, user.UserID)
@@ -172,7 +142,7 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Respons
 			return nil, errors.WrapIf(err, "failed to get user details")
 		}
 
-		if err := h.broadcaster.BroadcastTestedMessage(command.ProblemID, contract.MessageUser{
+		if err := h.broadcaster.BroadcastTestedMessage(ctx, command.ProblemID, contract.MessageUser{
 			UserID:   user.UserID,
 			Username: details.Username,
 		}, string(command.Status), timestamp); err != nil {
@@ -182,6 +152,50 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Respons
 		return &Response{
 			TestResultID:     resultID,
 			ProblemVersionID: versionID,
+			SolutionResults:  solutionResults,
 		}, nil
 	})
 }
+
+// compareSolutionResults checks that every reported result refers to a solution
+// of the version under test and pairs it with the setter's expectation.
+func (h *CommandHandler) compareSolutionResults(
+	ctx context.Context,
+	versionID uuid.UUID,
+	results []CommandSolutionResult,
+) ([]ResponseSolutionResult, error) {
+	solutions, err := h.repo.GetVersionSolutions(ctx, versionID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get version solutions")
+	}
+
+	solutionsByID := make(map[uuid.UUID]SolutionSummary, len(solutions))
+	for _, solution := range solutions {
+		solutionsByID[solution.SolutionID] = solution
+	}
+
+	seen := make(map[uuid.UUID]struct{}, len(results))
+	compared := make([]ResponseSolutionResult, 0, len(results))
+
+	for _, result := range results {
+		solution, ok := solutionsByID[result.SolutionID]
+		if !ok {
+			return nil, errors.WithStack(ErrUnknownSolution)
+		}
+
+		if _, ok := seen[result.SolutionID]; ok {
+			return nil, errors.WithStack(ErrDuplicateSolutionResult)
+		}
+		seen[result.SolutionID] = struct{}{}
+
+		compared = append(compared, ResponseSolutionResult{
+			SolutionID:      solution.SolutionID,
+			Name:            solution.Name,
+			ExpectedVerdict: solution.ExpectedVerdict,
+			ActualVerdict:   result.ActualVerdict,
+			Matches:         constant.Verdict(solution.ExpectedVerdict).Matches(constant.Verdict(result.ActualVerdict)),
+		})
+	}
+
+	return compared, nil
+}
@@ -44,6 +44,10 @@ func (e *Endpoint) handle() echo.HandlerFunc {
 			return httperror.New(http.StatusUnprocessableEntity, "The problem is not ready for testing yet")
 		} else if errors.Is(err, ErrTesterNotAssigned) {
 			return httperror.New(http.StatusForbidden, "You are not assigned as a tester for this problem")
+		} else if errors.Is(err, ErrUnknownSolution) {
+			return httperror.New(http.StatusUnprocessableEntity, "A solution result refers to a solution that is not part of the latest problem version")
+		} else if errors.Is(err, ErrDuplicateSolutionResult) {
+			return httperror.New(http.StatusUnprocessableEntity, "Each solution can only be reported once")
 		} else if err != nil {
 			return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
 		}
@@ -4,7 +4,16 @@ import (
 	"github.com/google/uuid"
 )
 
+type ResponseSolutionResult struct {
+	SolutionID      uuid.UUID `json:"solution_id"`
+	Name            string    `json:"name"`
+	ExpectedVerdict string    `json:"expected_verdict"`
+	ActualVerdict   string    `json:"actual_verdict"`
+	Matches         bool      `json:"matches"`
+}
+
 type Response struct {
-	TestResultID     uuid.UUID `json:"test_result_id"`
-	ProblemVersionID uuid.UUID `json:"problem_version_id"`
+	TestResultID     uuid.UUID                `json:"test_result_id"`
+	ProblemVersionID uuid.UUID                `json:"problem_version_id"`
+	SolutionResults  []ResponseSolutionResult `json:"solution_results"`
 }
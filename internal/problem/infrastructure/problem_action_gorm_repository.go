@@ -4,10 +4,8 @@ import (
 	"context"
 	"time"
 
-	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
-	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/dto"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/reviewproblem"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/feature/testproblem"
 
@@ -32,11 +30,8 @@ type ProblemActionRepository interface {
 		versionID uuid.UUID,
 		createdAt time.Time,
 	) (uuid.UUID, error)
-	GetProblem(ctx context.Context, problemID uuid.UUID) (dto.ProblemStatusAndVersion, error)
-	UpdateProblemStatus(ctx context.Context, problemID uuid.UUID, status constant.ProblemStatus) error
-	SetProblemDraftActive(ctx context.Context, problemDraftID uuid.UUID) error
 	GetProblemTesterIDs(ctx context.Context, problemID uuid.UUID) ([]uuid.UUID, error)
-	GetTestResultsForVersion(ctx context.Context, versionID uuid.UUID) ([]testproblem.ResultSummary, error)
+	GetVersionSolutions(ctx context.Context, versionID uuid.UUID) ([]testproblem.SolutionSummary, error)
 }
 
 type ProblemActionGormRepository struct {
@@ -99,6 +94,16 @@ func (r *ProblemActionGormRepository) SaveTestResult(
 			return uuid.Nil, errors.WrapIf(err, "failed to update problem test result")
 		}
 
+		if err := db.WithContext(ctx).
+			Where("problem_test_result_id = ?", existing.ProblemTestResultID).
+			Delete(&database.ProblemTestSolutionResult{}).Error; err != nil {
+			return uuid.Nil, errors.WrapIf(err, "failed to delete old solution results")
+		}
+
+		if err := r.createSolutionResults(ctx, existing.ProblemTestResultID, command.SolutionResults); err != nil {
+			return uuid.Nil, err
+		}
+
 		return existing.ProblemTestResultID, nil
 	}
 
@@ -118,9 +123,40 @@ func (r *ProblemActionGormRepository) SaveTestResult(
 		return uuid.Nil, errors.WrapIf(err, "failed to create problem test result")
 	}
 
+	if err := r.createSolutionResults(ctx, resultID, command.SolutionResults); err != nil {
+		return uuid.Nil, err
+	}
+
 	return resultID, nil
 }
 
+func (r *ProblemActionGormRepository) createSolutionResults(
+	ctx context.Context,
+	resultID uuid.UUID,
+	results []testproblem.CommandSolutionResult,
+) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	db := database.GetDBFromContext(ctx, r.db)
+
+	models := make([]database.ProblemTestSolutionResult, len(results))
+	for i, result := range results {
+		models[i] = database.ProblemTestSolutionResult{
+			ProblemTestResultID: resultID,
+			SolutionID:          result.SolutionID,
+			ActualVerdict:       result.ActualVerdict,
+		}
+	}
+
+	if err := db.WithContext(ctx).Create(&models).Error; err != nil {
+		return errors.WrapIf(err, "failed to create solution results")
+	}
+
+	return nil
+}
+
 func (r *ProblemActionGormRepository) CreateReview(
 	ctx context.Context,
 	command *reviewproblem.Command,
@@ -150,47 +186,6 @@ func (r *ProblemActionGormRepository) CreateReview(
 	return reviewID, nil
 }
 
-func (r *ProblemActionGormRepository) GetProblem(
-	ctx context.Context,
-	problemID uuid.UUID,
-) (dto.ProblemStatusAndVersion, error) {
-	db := database.GetDBFromContext(ctx, r.db)
-
-	var p dto.ProblemStatusAndVersion
-	if err := db.WithContext(ctx).
-		Model(&database.Problem{}).
-		Select("status", "problem_draft_id AS draft_id").
-		Where("problem_id = ?", problemID).
-		First(&p).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return dto.ProblemStatusAndVersion{}, problem.ErrProblemNotFound
-		}
-
-		return dto.ProblemStatusAndVersion{}, errors.WrapIf(err, "failed to get problem status")
-	}
-
-	return p, nil
-}
-
-func (r *ProblemActionGormRepository) UpdateProblemStatus(
-	ctx context.Context,
-	problemID uuid.UUID,
-	status constant.ProblemStatus,
-) error {
-	db := database.GetDBFromContext(ctx, r.db)
-
-	if res := db.WithContext(ctx).
-		Model(&database.Problem{}).
-		Where("problem_id = ?", problemID).
-		Update("status", status); res.Error != nil {
-		return errors.WrapIf(res.Error, "failed to update problem status")
-	} else if res.RowsAffected == 0 {
-		return errors.WithStack(problem.ErrProblemNotFound)
-	}
-
-	return nil
-}
-
 func (r *ProblemActionGormRepository) UpdateProblemReviewer(
 	ctx context.Context,
 	problemID uuid.UUID,
@@ -210,21 +205,6 @@ func (r *ProblemActionGormRepository) UpdateProblemReviewer(
 	return nil
 }
 
-func (r *ProblemActionGormRepository) SetProblemDraftActive(ctx context.Context, problemDraftID uuid.UUID) error {
-	db := database.GetDBFromContext(ctx, r.db)
-
-	if res := db.WithContext(ctx).
-		Model(&database.ProblemDraft{}).
-		Where("problem_draft_id = ?", problemDraftID).
-		Update("is_active", true); res.Error != nil {
-		return errors.WrapIf(res.Error, "failed to set problem draft active")
-	} else if res.RowsAffected == 0 {
-		return errors.New("failed to find problem draft")
-	}
-
-	return nil
-}
-
 func (r *ProblemActionGormRepository) GetProblemTesterIDs(ctx context.Context, problemID uuid.UUID) ([]uuid.UUID, error) {
 	db := database.GetDBFromContext(ctx, r.db)
 
@@ -249,26 +229,26 @@ func (r *ProblemActionGormRepository) GetProblemTesterIDs(ctx context.Context, p
 	return testerIDs, nil
 }
 
-func (r *ProblemActionGormRepository) GetTestResultsForVersion(
+func (r *ProblemActionGormRepository) GetVersionSolutions(
 	ctx context.Context,
 	versionID uuid.UUID,
-) ([]testproblem.ResultSummary, error) {
+) ([]testproblem.SolutionSummary, error) {
 	db := database.GetDBFromContext(ctx, r.db)
 
-	var rows []database.ProblemTestResult
+	var rows []database.ProblemVersionSolution
 	if err := db.WithContext(ctx).
-		Model(&database.ProblemTestResult{}).
-		Select("tester_id", "status").
-		Where("version_id = ?", versionID).
+		Select("solution_id", "name", "expected_verdict").
+		Where("problem_version_id = ?", versionID).
 		Find(&rows).Error; err != nil {
-		return nil, errors.WrapIf(err, "failed to get problem test results")
+		return nil, errors.WrapIf(err, "failed to get problem version solutions")
 	}
 
-	summaries := make([]testproblem.ResultSummary, len(rows))
+	summaries := make([]testproblem.SolutionSummary, len(rows))
 	for i, row := range rows {
-		summaries[i] = testproblem.ResultSummary{
-			TesterID: row.TesterID,
-			Status:   row.Status,
+		summaries[i] = testproblem.SolutionSummary{
+			SolutionID:      row.SolutionID,
+			Name:            row.Name,
+			ExpectedVerdict: row.ExpectedVerdict,
 		}
 	}
 
@@ -0,0 +1,56 @@
+package infrastructure
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProblemFollowerGormRepository counts the creator, the reviewer and the
+// testers of a problem as its followers.
+type ProblemFollowerGormRepository struct {
+	db *gorm.DB
+}
+
+func NewProblemFollowerGormRepository(db *gorm.DB) *ProblemFollowerGormRepository {
+	return &ProblemFollowerGormRepository{
+		db: db,
+	}
+}
+
+func (r *ProblemFollowerGormRepository) GetProblemFollowers(
+	ctx context.Context,
+	problemID uuid.UUID,
+) ([]uuid.UUID, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var p database.Problem
+	if err := db.WithContext(ctx).
+		Preload("Testers").
+		Select("problem_id", "creator_id", "reviewer_id").
+		Where("problem_id = ?", problemID).
+		First(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, errors.WrapIf(err, "failed to get problem")
+	}
+
+	followerIDs := make([]uuid.UUID, 0, len(p.Testers)+2)
+	followerIDs = append(followerIDs, p.CreatorID)
+
+	if p.ReviewerID.Valid {
+		followerIDs = append(followerIDs, p.ReviewerID.UUID)
+	}
+
+	for _, tester := range p.Testers {
+		followerIDs = append(followerIDs, tester.UserID)
+	}
+
+	return followerIDs, nil
+}
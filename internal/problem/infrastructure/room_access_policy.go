@@ -0,0 +1,92 @@
+package infrastructure
+
+import (
+	"context"
+	"slices"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RoomAccessPolicy lets the people working on a problem, and everyone else
+// who can read it, into its room, and the people who can read the details of
+// a contest into the room of the contest.
+type RoomAccessPolicy struct {
+	db           *gorm.DB
+	authProvider contract.AuthProvider
+}
+
+func NewRoomAccessPolicy(db *gorm.DB, authProvider contract.AuthProvider) *RoomAccessPolicy {
+	return &RoomAccessPolicy{
+		db:           db,
+		authProvider: authProvider,
+	}
+}
+
+func (p *RoomAccessPolicy) CanAccessProblemRoom(
+	ctx context.Context,
+	userID uuid.UUID,
+	problemID uuid.UUID,
+) (bool, error) {
+	db := database.GetDBFromContext(ctx, p.db)
+
+	var pr database.Problem
+	if err := db.WithContext(ctx).
+		Preload("Testers").
+		Preload("ContestProblems").
+		Where("problem_id = ?", problemID).
+		First(&pr).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+
+		return false, errors.WrapIf(err, "failed to get problem")
+	}
+
+	scope, err := problem.GetUserReadScope(ctx, p.authProvider, userID)
+	if err != nil {
+		return false, errors.WrapIf(err, "failed to get read scope")
+	}
+
+	return scope.Allows(problem.NewAccess(&pr)), nil
+}
+
+func (p *RoomAccessPolicy) CanAccessContestRoom(
+	ctx context.Context,
+	userID uuid.UUID,
+	contestID uuid.UUID,
+) (bool, error) {
+	db := database.GetDBFromContext(ctx, p.db)
+
+	var count int64
+	if err := db.WithContext(ctx).
+		Model(&database.Contest{}).
+		Where("contest_id = ?", contestID).
+		Count(&count).Error; err != nil {
+		return false, errors.WrapIf(err, "failed to check if contest exists")
+	} else if count == 0 {
+		return false, nil
+	}
+
+	details, err := p.authProvider.MustGetUserDetails(ctx, userID)
+	if err != nil {
+		return false, errors.WrapIf(err, "failed to get user details")
+	}
+
+	if details.IsSuperAdmin || slices.Contains(details.Permissions, constant.PermissionContestReadDetailsAny) {
+		return true, nil
+	}
+
+	contestIDs, err := p.authProvider.ContestsWhereUserCan(ctx, userID, constant.PermissionContestReadDetailsAny)
+	if err != nil {
+		return false, errors.WrapIf(err, "failed to check contest permission")
+	}
+
+	return slices.Contains(contestIDs, contestID), nil
+}
@@ -0,0 +1,88 @@
+package problem
+
+import (
+	"slices"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/similarity"
+
+	"github.com/google/uuid"
+)
+
+// A problem is a potential duplicate of another when either its statement or
+// its examples are at least this similar to the other's.
+const (
+	DuplicateStatementSimilarity = 0.5
+	DuplicateExampleSimilarity   = 0.7
+	maxDuplicates                = 5
+)
+
+// Fingerprint holds the signatures a problem version is compared by: one for
+// the statement in each language, and one for all the examples.
+type Fingerprint struct {
+	ProblemID  uuid.UUID
+	Statements []similarity.Signature
+	Examples   similarity.Signature
+}
+
+type Duplicate struct {
+	ProblemID           uuid.UUID
+	StatementSimilarity float64
+	ExampleSimilarity   float64
+}
+
+// StatementSignature signs the parts of a statement that describe the task.
+// Titles and notes say little about it, so they are left out.
+func StatementSignature(background, statement, inputFormat, outputFormat string) similarity.Signature {
+	return similarity.Text(background, statement, inputFormat, outputFormat)
+}
+
+// ExampleSignature signs the inputs and outputs of the examples, given one
+// after the other.
+func ExampleSignature(inputsAndOutputs ...string) similarity.Signature {
+	return similarity.Tokens(inputsAndOutputs...)
+}
+
+// FindDuplicates compares a fingerprint with those of other problems and
+// returns the most similar potential duplicates first. Statements are
+// compared language by language, keeping the closest pair.
+func FindDuplicates(fingerprint Fingerprint, others []Fingerprint) []Duplicate {
+	var duplicates []Duplicate
+	for _, other := range others {
+		if other.ProblemID == fingerprint.ProblemID {
+			continue
+		}
+
+		duplicate := Duplicate{
+			ProblemID:         other.ProblemID,
+			ExampleSimilarity: fingerprint.Examples.Similarity(other.Examples),
+		}
+
+		for _, statement := range fingerprint.Statements {
+			for _, otherStatement := range other.Statements {
+				duplicate.StatementSimilarity = max(duplicate.StatementSimilarity, statement.Similarity(otherStatement))
+			}
+		}
+
+		if duplicate.StatementSimilarity >= DuplicateStatementSimilarity ||
+			duplicate.ExampleSimilarity >= DuplicateExampleSimilarity {
+			duplicates = append(duplicates, duplicate)
+		}
+	}
+
+	slices.SortStableFunc(duplicates, func(a, b Duplicate) int {
+		aScore := max(a.StatementSimilarity, a.ExampleSimilarity)
+		bScore := max(b.StatementSimilarity, b.ExampleSimilarity)
+		if aScore > bScore {
+			return -1
+		} else if aScore < bScore {
+			return 1
+		}
+		return 0
+	})
+
+	if len(duplicates) > maxDuplicates {
+		duplicates = duplicates[:maxDuplicates]
+	}
+
+	return duplicates
+}
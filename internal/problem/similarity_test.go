@@ -0,0 +1,40 @@
+package problem
+
+import (
+	"testing"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/similarity"
+
+	"github.com/google/uuid"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	statement := StatementSignature("", "Given an array of n integers, answer q range sum queries.", "n and q", "q sums")
+	examples := ExampleSignature("5 3\n1 2 3 4 5\n1 5\n2 3\n4 4", "15\n5\n4")
+
+	fingerprint := Fingerprint{
+		ProblemID:  uuid.New(),
+		Statements: []similarity.Signature{StatementSignature("", "完全不同的题面内容在这里", "", ""), statement},
+		Examples:   examples,
+	}
+
+	sameStatement := Fingerprint{ProblemID: uuid.New(), Statements: []similarity.Signature{statement}}
+	sameExamples := Fingerprint{ProblemID: uuid.New(), Examples: examples}
+	unrelated := Fingerprint{
+		ProblemID:  uuid.New(),
+		Statements: []similarity.Signature{StatementSignature("", "Count the paths of a robot on a grid.", "", "")},
+	}
+
+	got := FindDuplicates(fingerprint, []Fingerprint{fingerprint, unrelated, sameStatement, sameExamples})
+	if len(got) != 2 {
+		t.Fatalf("FindDuplicates() = %+v, want the problems with the same statement and examples", got)
+	}
+
+	if got[0].ProblemID != sameStatement.ProblemID || got[0].StatementSimilarity != 1 {
+		t.Errorf("FindDuplicates()[0] = %+v, want the same statement in another language order", got[0])
+	}
+
+	if got[1].ProblemID != sameExamples.ProblemID || got[1].ExampleSimilarity != 1 {
+		t.Errorf("FindDuplicates()[1] = %+v, want the same examples", got[1])
+	}
+}
@@ -0,0 +1,186 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) GetProblemStatus(ctx context.Context, problemID uuid.UUID) (constant.ProblemStatus, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var p database.Problem
+	if err := db.WithContext(ctx).
+		Select("status").
+		Where("problem_id = ?", problemID).
+		First(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return StatusNone, errors.WithStack(problem.ErrProblemNotFound)
+		}
+
+		return StatusNone, errors.WrapIf(err, "failed to get problem status")
+	}
+
+	status := constant.FromStringToProblemStatus(p.Status)
+	if status == StatusNone {
+		return StatusNone, errors.Errorf("problem has unknown status %q", p.Status)
+	}
+
+	return status, nil
+}
+
+func (r *GormRepository) UpdateProblemStatus(
+	ctx context.Context,
+	problemID uuid.UUID,
+	from constant.ProblemStatus,
+	to constant.ProblemStatus,
+	updatedAt time.Time,
+) (bool, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	res := db.WithContext(ctx).
+		Model(&database.Problem{}).
+		Where("problem_id = ? AND status = ?", problemID, from).
+		Updates(map[string]any{
+			"status":     to,
+			"updated_at": updatedAt,
+		})
+	if res.Error != nil {
+		return false, errors.WrapIf(res.Error, "failed to update problem status")
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+func (r *GormRepository) ReactivateDraft(ctx context.Context, problemID uuid.UUID) error {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	if err := db.WithContext(ctx).
+		Model(&database.ProblemDraft{}).
+		Where("problem_draft_id = (?)", db.Model(&database.Problem{}).
+			Select("problem_draft_id").
+			Where("problem_id = ?", problemID)).
+		Update("is_active", true).Error; err != nil {
+		return errors.WrapIf(err, "failed to set problem draft active")
+	}
+
+	return nil
+}
+
+func (r *GormRepository) StampCompletion(
+	ctx context.Context,
+	problemID uuid.UUID,
+	completerID uuid.UUID,
+	completedAt time.Time,
+) error {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	if err := db.WithContext(ctx).
+		Model(&database.Problem{}).
+		Where("problem_id = ?", problemID).
+		Updates(map[string]any{
+			"completed_at": completedAt,
+			"completed_by": completerID,
+		}).Error; err != nil {
+		return errors.WrapIf(err, "failed to stamp problem completion")
+	}
+
+	return nil
+}
+
+func (r *GormRepository) HaveAllTestersPassed(ctx context.Context, problemID uuid.UUID) (bool, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var version database.ProblemVersion
+	if err := db.WithContext(ctx).
+		Select("problem_version_id").
+		Where("problem_id = ?", problemID).
+		Order("created_at DESC").
+		First(&version).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, errors.WithStack(problem.ErrProblemNotFound)
+		}
+
+		return false, errors.WrapIf(err, "failed to get latest problem version")
+	}
+
+	var testerIDs []uuid.UUID
+	if err := db.WithContext(ctx).
+		Table("problem_testers").
+		Where("problem_problem_id = ?", problemID).
+		Pluck("user_user_id", &testerIDs).Error; err != nil {
+		return false, errors.WrapIf(err, "failed to get problem testers")
+	}
+
+	if len(testerIDs) == 0 {
+		return false, nil
+	}
+
+	var results []database.ProblemTestResult
+	if err := db.WithContext(ctx).
+		Select("tester_id", "status").
+		Where("version_id = ?", version.ProblemVersionID).
+		Find(&results).Error; err != nil {
+		return false, errors.WrapIf(err, "failed to get problem test results")
+	}
+
+	passed := make(map[uuid.UUID]struct{}, len(results))
+	for _, result := range results {
+		switch result.Status {
+		case "failed":
+			return false, nil
+		case "passed":
+			passed[result.TesterID] = struct{}{}
+		}
+	}
+
+	for _, testerID := range testerIDs {
+		if _, ok := passed[testerID]; !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (r *GormRepository) CreateHistoryEntry(ctx context.Context, entry HistoryEntry) error {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	historyID, err := uuid.NewV7()
+	if err != nil {
+		return errors.WrapIf(err, "failed to generate history ID")
+	}
+
+	if err := db.WithContext(ctx).
+		Create(&database.ProblemStatusHistory{
+			ProblemStatusHistoryID: historyID,
+			ProblemID:              entry.ProblemID,
+			ActorID:                entry.ActorID,
+			Event:                  string(entry.Event),
+			FromStatus:             string(entry.FromStatus),
+			ToStatus:               string(entry.ToStatus),
+			Reason:                 entry.Reason,
+			CreatedAt:              entry.CreatedAt,
+		}).Error; err != nil {
+		return errors.WrapIf(err, "failed to create problem status history entry")
+	}
+
+	return nil
+}
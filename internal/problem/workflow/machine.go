@@ -0,0 +1,201 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTransitionNotAllowed = errors.New("problem status transition not allowed")
+	ErrGuardNotMet          = errors.New("problem status transition guard not met")
+)
+
+// TransitionError reports an event that cannot happen in the current status
+// of a problem.
+type TransitionError struct {
+	From  constant.ProblemStatus
+	Event Event
+}
+
+func (e *TransitionError) Error() string {
+	if e.From == StatusNone {
+		return fmt.Sprintf("%s is not allowed before the problem is submitted", e.Event)
+	}
+
+	return fmt.Sprintf("%s is not allowed while the problem is %s", e.Event, e.From)
+}
+
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrTransitionNotAllowed
+}
+
+type HistoryEntry struct {
+	ProblemID  uuid.UUID
+	ActorID    uuid.UUID
+	Event      Event
+	FromStatus constant.ProblemStatus
+	ToStatus   constant.ProblemStatus
+	Reason     string
+	CreatedAt  time.Time
+}
+
+type Repository interface {
+	GetProblemStatus(ctx context.Context, problemID uuid.UUID) (constant.ProblemStatus, error)
+	// UpdateProblemStatus only updates the problem if it is still in the
+	// status from, and reports whether it did.
+	UpdateProblemStatus(
+		ctx context.Context,
+		problemID uuid.UUID,
+		from constant.ProblemStatus,
+		to constant.ProblemStatus,
+		updatedAt time.Time,
+	) (bool, error)
+	ReactivateDraft(ctx context.Context, problemID uuid.UUID) error
+	StampCompletion(ctx context.Context, problemID uuid.UUID, completerID uuid.UUID, completedAt time.Time) error
+	HaveAllTestersPassed(ctx context.Context, problemID uuid.UUID) (bool, error)
+	CreateHistoryEntry(ctx context.Context, entry HistoryEntry) error
+}
+
+// Machine moves problems between statuses. It runs in the unit of work of
+// the caller, so that a transition is committed together with the change
+// that caused it.
+type Machine struct {
+	repo         Repository
+	authProvider contract.AuthProvider
+}
+
+func NewMachine(repo Repository, authProvider contract.AuthProvider) *Machine {
+	return &Machine{
+		repo:         repo,
+		authProvider: authProvider,
+	}
+}
+
+// Resolve finds the transition an event triggers from a status and checks
+// that the current user may trigger it. Guards are only evaluated by Fire.
+func (m *Machine) Resolve(ctx context.Context, from constant.ProblemStatus, event Event) (Transition, error) {
+	t, ok := Lookup(from, event)
+	if !ok {
+		return Transition{}, errors.WithStack(&TransitionError{From: from, Event: event})
+	}
+
+	if len(t.Permissions) > 0 {
+		can, err := m.authProvider.Can(ctx, t.Permissions...)
+		if err != nil {
+			return Transition{}, errors.WrapIf(err, "failed to check permission for transition")
+		}
+
+		if !can {
+			return Transition{}, customerror.NewNoPermissionError(t.Permissions[0])
+		}
+	}
+
+	return t, nil
+}
+
+// Check reports whether the event could be fired on the problem now, without
+// evaluating its guard.
+func (m *Machine) Check(ctx context.Context, problemID uuid.UUID, event Event) error {
+	from, err := m.repo.GetProblemStatus(ctx, problemID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get problem status")
+	}
+
+	_, err = m.Resolve(ctx, from, event)
+	return err
+}
+
+// Fire triggers an event on a problem, moving it to the next status. It
+// returns ErrGuardNotMet, leaving the problem as it is, if the guard of the
+// transition does not hold.
+func (m *Machine) Fire(ctx context.Context, problemID uuid.UUID, event Event, reason string) (Transition, error) {
+	from, err := m.repo.GetProblemStatus(ctx, problemID)
+	if err != nil {
+		return Transition{}, errors.WrapIf(err, "failed to get problem status")
+	}
+
+	t, err := m.Resolve(ctx, from, event)
+	if err != nil {
+		return Transition{}, err
+	}
+
+	if ok, err := m.checkGuard(ctx, problemID, t.Guard); err != nil {
+		return Transition{}, err
+	} else if !ok {
+		return Transition{}, errors.WithStack(ErrGuardNotMet)
+	}
+
+	if ok, err := m.repo.UpdateProblemStatus(ctx, problemID, t.From, t.To, time.Now()); err != nil {
+		return Transition{}, errors.WrapIf(err, "failed to update problem status")
+	} else if !ok {
+		// The problem moved on since its status was read.
+		return Transition{}, errors.WithStack(&TransitionError{From: from, Event: event})
+	}
+
+	if err := m.Record(ctx, problemID, t, reason); err != nil {
+		return Transition{}, err
+	}
+
+	return t, nil
+}
+
+// Record applies the side effects of a transition whose status has already
+// been written, such as by the upsert of a submission, and adds it to the
+// history of the problem.
+func (m *Machine) Record(ctx context.Context, problemID uuid.UUID, t Transition, reason string) error {
+	user, err := m.authProvider.MustGetUser(ctx)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get user from auth provider")
+	}
+
+	timestamp := time.Now()
+
+	for _, effect := range t.Effects {
+		switch effect {
+		case EffectReactivateDraft:
+			if err := m.repo.ReactivateDraft(ctx, problemID); err != nil {
+				return errors.WrapIf(err, "failed to reactivate problem draft")
+			}
+		case EffectStampCompletion:
+			if err := m.repo.StampCompletion(ctx, problemID, user.UserID, timestamp); err != nil {
+				return errors.WrapIf(err, "failed to stamp problem completion")
+			}
+		}
+	}
+
+	if err := m.repo.CreateHistoryEntry(ctx, HistoryEntry{
+		ProblemID:  problemID,
+		ActorID:    user.UserID,
+		Event:      t.Event,
+		FromStatus: t.From,
+		ToStatus:   t.To,
+		Reason:     reason,
+		CreatedAt:  timestamp,
+	}); err != nil {
+		return errors.WrapIf(err, "failed to record problem status history")
+	}
+
+	return nil
+}
+
+func (m *Machine) checkGuard(ctx context.Context, problemID uuid.UUID, guard Guard) (bool, error) {
+	switch guard {
+	case GuardAllTestersPassed:
+		ok, err := m.repo.HaveAllTestersPassed(ctx, problemID)
+		if err != nil {
+			return false, errors.WrapIf(err, "failed to check test results")
+		}
+
+		return ok, nil
+	default:
+		return true, nil
+	}
+}
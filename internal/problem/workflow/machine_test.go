@@ -0,0 +1,171 @@
+package workflow
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+type fakeRepository struct {
+	status           constant.ProblemStatus
+	allTestersPassed bool
+	draftReactivated bool
+	history          []HistoryEntry
+}
+
+func (r *fakeRepository) GetProblemStatus(context.Context, uuid.UUID) (constant.ProblemStatus, error) {
+	return r.status, nil
+}
+
+func (r *fakeRepository) UpdateProblemStatus(
+	_ context.Context,
+	_ uuid.UUID,
+	from constant.ProblemStatus,
+	to constant.ProblemStatus,
+	_ time.Time,
+) (bool, error) {
+	if r.status != from {
+		return false, nil
+	}
+
+	r.status = to
+	return true, nil
+}
+
+func (r *fakeRepository) ReactivateDraft(context.Context, uuid.UUID) error {
+	r.draftReactivated = true
+	return nil
+}
+
+func (r *fakeRepository) StampCompletion(context.Context, uuid.UUID, uuid.UUID, time.Time) error {
+	return nil
+}
+
+func (r *fakeRepository) HaveAllTestersPassed(context.Context, uuid.UUID) (bool, error) {
+	return r.allTestersPassed, nil
+}
+
+func (r *fakeRepository) CreateHistoryEntry(_ context.Context, entry HistoryEntry) error {
+	r.history = append(r.history, entry)
+	return nil
+}
+
+type fakeAuthProvider struct {
+	user        contract.AuthUser
+	permissions []string
+}
+
+func (p *fakeAuthProvider) GetUser(context.Context) (*contract.AuthUser, error) {
+	return &p.user, nil
+}
+
+func (p *fakeAuthProvider) Can(_ context.Context, permissionNames ...string) (bool, error) {
+	for _, name := range permissionNames {
+		if slices.Contains(p.permissions, name) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (p *fakeAuthProvider) CanInContest(
+	ctx context.Context,
+	_ uuid.UUID,
+	permissionNames ...string,
+) (bool, error) {
+	return p.Can(ctx, permissionNames...)
+}
+
+func (p *fakeAuthProvider) ContestsWhereCan(context.Context, ...string) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+func (p *fakeAuthProvider) ContestsWhereUserCan(context.Context, uuid.UUID, ...string) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+func (p *fakeAuthProvider) MustGetUser(context.Context) (contract.AuthUser, error) {
+	return p.user, nil
+}
+
+func (p *fakeAuthProvider) MustGetUserDetails(context.Context, uuid.UUID) (*contract.AuthUserDetails, error) {
+	return &contract.AuthUserDetails{}, nil
+}
+
+func TestTransitionsAreDeterministic(t *testing.T) {
+	type key struct {
+		from  constant.ProblemStatus
+		event Event
+	}
+
+	seen := make(map[key]struct{})
+	for _, tr := range transitions {
+		k := key{tr.From, tr.Event}
+		if _, ok := seen[k]; ok {
+			t.Errorf("%s from %q is declared twice", tr.Event, tr.From)
+		}
+		seen[k] = struct{}{}
+
+		if len(tr.Permissions) == 0 {
+			t.Errorf("%s from %q requires no permission", tr.Event, tr.From)
+		}
+	}
+}
+
+func TestFire(t *testing.T) {
+	problemID := uuid.New()
+	reviewer := &fakeAuthProvider{
+		user:        contract.AuthUser{UserID: uuid.New()},
+		permissions: []string{constant.PermissionProblemReviewAny, constant.PermissionProblemTestAssigned},
+	}
+
+	repo := &fakeRepository{status: constant.ProblemStatusPendingReview}
+	m := NewMachine(repo, reviewer)
+
+	if _, err := m.Fire(context.Background(), problemID, EventRequestRevision, "typo"); err != nil {
+		t.Fatalf("Fire() error = %v", err)
+	}
+
+	if repo.status != constant.ProblemStatusNeedsRevision || !repo.draftReactivated {
+		t.Errorf("status = %s, draft reactivated = %t", repo.status, repo.draftReactivated)
+	}
+
+	if len(repo.history) != 1 || repo.history[0].Reason != "typo" || repo.history[0].ActorID != reviewer.user.UserID ||
+		repo.history[0].FromStatus != constant.ProblemStatusPendingReview {
+		t.Errorf("history = %+v", repo.history)
+	}
+
+	if _, err := m.Fire(context.Background(), problemID, EventApprove, ""); !errors.Is(err, ErrTransitionNotAllowed) {
+		t.Errorf("Fire() error = %v, want ErrTransitionNotAllowed", err)
+	}
+
+	repo = &fakeRepository{status: constant.ProblemStatusPendingTesting}
+	m = NewMachine(repo, reviewer)
+
+	if _, err := m.Fire(context.Background(), problemID, EventPassTesting, ""); !errors.Is(err, ErrGuardNotMet) {
+		t.Errorf("Fire() error = %v, want ErrGuardNotMet", err)
+	}
+
+	if repo.status != constant.ProblemStatusPendingTesting || len(repo.history) != 0 {
+		t.Errorf("status = %s, history = %+v", repo.status, repo.history)
+	}
+
+	repo.allTestersPassed = true
+	if tr, err := m.Fire(context.Background(), problemID, EventPassTesting, ""); err != nil ||
+		tr.To != constant.ProblemStatusAwaitingFinalCheck {
+		t.Errorf("Fire() = %+v, %v", tr, err)
+	}
+
+	if _, err := m.Fire(context.Background(), problemID, EventComplete, ""); !errors.Is(err, customerror.ErrBaseNoPermission) {
+		t.Errorf("Fire() error = %v, want no permission", err)
+	}
+}
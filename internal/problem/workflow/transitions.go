@@ -0,0 +1,169 @@
+// Package workflow is the state machine of problem statuses. Every status
+// change goes through it, so that the allowed transitions, the permissions
+// they require and their side effects are declared in one table, and every
+// transition is recorded in the status history of the problem.
+package workflow
+
+import (
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+)
+
+type Event string
+
+const (
+	EventSubmit          Event = "submit"
+	EventResubmit        Event = "resubmit"
+	EventApprove         Event = "approve"
+	EventReject          Event = "reject"
+	EventRequestRevision Event = "request_revision"
+	EventPassTesting     Event = "pass_testing"
+	EventFailTesting     Event = "fail_testing"
+	EventComplete        Event = "complete"
+)
+
+// StatusNone is the status of a problem that has not been submitted yet.
+const StatusNone constant.ProblemStatus = ""
+
+type Effect string
+
+const (
+	// EffectReactivateDraft lets the setter edit the draft of the problem
+	// again.
+	EffectReactivateDraft Effect = "reactivate_draft"
+	// EffectStampCompletion records who completed the problem and when.
+	EffectStampCompletion Effect = "stamp_completion"
+)
+
+type Guard string
+
+// GuardAllTestersPassed holds once every assigned tester has passed the
+// latest version and none has failed it.
+const GuardAllTestersPassed Guard = "all_testers_passed"
+
+type Transition struct {
+	Event Event
+	From  constant.ProblemStatus
+	To    constant.ProblemStatus
+	// Permissions lists the permissions of which the actor needs any.
+	Permissions []string
+	Guard       Guard
+	Effects     []Effect
+}
+
+var (
+	submitPermissions = []string{constant.PermissionProblemDraftSubmitOwn}
+	reviewPermissions = []string{constant.PermissionProblemReviewAny, constant.PermissionProblemReviewOverride}
+	testPermissions   = []string{constant.PermissionProblemTestAssigned, constant.PermissionProblemTestOverride}
+)
+
+// transitions declares every allowed status change. A resubmission sends a
+// problem back to the phase that asked for changes and otherwise keeps its
+// status.
+var transitions = []Transition{
+	{
+		Event:       EventSubmit,
+		From:        StatusNone,
+		To:          constant.ProblemStatusPendingReview,
+		Permissions: submitPermissions,
+	},
+	{
+		Event:       EventResubmit,
+		From:        constant.ProblemStatusNeedsRevision,
+		To:          constant.ProblemStatusPendingReview,
+		Permissions: submitPermissions,
+	},
+	{
+		Event:       EventResubmit,
+		From:        constant.ProblemStatusTestingChangesRequested,
+		To:          constant.ProblemStatusPendingTesting,
+		Permissions: submitPermissions,
+	},
+	{
+		Event:       EventResubmit,
+		From:        constant.ProblemStatusPendingReview,
+		To:          constant.ProblemStatusPendingReview,
+		Permissions: submitPermissions,
+	},
+	{
+		Event:       EventResubmit,
+		From:        constant.ProblemStatusPendingTesting,
+		To:          constant.ProblemStatusPendingTesting,
+		Permissions: submitPermissions,
+	},
+	{
+		Event:       EventResubmit,
+		From:        constant.ProblemStatusAwaitingFinalCheck,
+		To:          constant.ProblemStatusAwaitingFinalCheck,
+		Permissions: submitPermissions,
+	},
+	{
+		Event:       EventResubmit,
+		From:        constant.ProblemStatusCompleted,
+		To:          constant.ProblemStatusCompleted,
+		Permissions: submitPermissions,
+	},
+	{
+		Event:       EventResubmit,
+		From:        constant.ProblemStatusRejected,
+		To:          constant.ProblemStatusRejected,
+		Permissions: submitPermissions,
+	},
+	{
+		Event:       EventApprove,
+		From:        constant.ProblemStatusPendingReview,
+		To:          constant.ProblemStatusPendingTesting,
+		Permissions: reviewPermissions,
+	},
+	{
+		Event:       EventReject,
+		From:        constant.ProblemStatusPendingReview,
+		To:          constant.ProblemStatusRejected,
+		Permissions: reviewPermissions,
+	},
+	{
+		Event:       EventRequestRevision,
+		From:        constant.ProblemStatusPendingReview,
+		To:          constant.ProblemStatusNeedsRevision,
+		Permissions: reviewPermissions,
+		Effects:     []Effect{EffectReactivateDraft},
+	},
+	{
+		Event:       EventPassTesting,
+		From:        constant.ProblemStatusPendingTesting,
+		To:          constant.ProblemStatusAwaitingFinalCheck,
+		Permissions: testPermissions,
+		Guard:       GuardAllTestersPassed,
+	},
+	{
+		Event:       EventFailTesting,
+		From:        constant.ProblemStatusPendingTesting,
+		To:          constant.ProblemStatusTestingChangesRequested,
+		Permissions: testPermissions,
+		Effects:     []Effect{EffectReactivateDraft},
+	},
+	{
+		Event:       EventComplete,
+		From:        constant.ProblemStatusAwaitingFinalCheck,
+		To:          constant.ProblemStatusCompleted,
+		Permissions: []string{constant.PermissionProblemCompleteAny},
+		Effects:     []Effect{EffectStampCompletion},
+	},
+	{
+		Event:       EventComplete,
+		From:        constant.ProblemStatusCompleted,
+		To:          constant.ProblemStatusCompleted,
+		Permissions: []string{constant.PermissionProblemCompleteAny},
+		Effects:     []Effect{EffectStampCompletion},
+	},
+}
+
+// Lookup finds the transition an event triggers from a status.
+func Lookup(from constant.ProblemStatus, event Event) (Transition, bool) {
+	for _, t := range transitions {
+		if t.From == from && t.Event == event {
+			return t, true
+		}
+	}
+
+	return Transition{}, false
+}
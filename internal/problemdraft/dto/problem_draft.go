@@ -23,6 +23,46 @@ type ProblemDraftExample struct {
 	Output string `json:"output"`
 }
 
+type ProblemDraftSolution struct {
+	SolutionID      uuid.UUID `json:"solution_id"`
+	Name            string    `json:"name"`
+	Language        string    `json:"language"`
+	ExpectedVerdict string    `json:"expected_verdict"`
+	IsMain          bool      `json:"is_main"`
+	Source          string    `json:"source"`
+}
+
+type ProblemDraftTestcase struct {
+	Name       string `json:"name"`
+	InputHash  string `json:"input_hash"`
+	InputSize  uint64 `json:"input_size"`
+	OutputHash string `json:"output_hash"`
+	OutputSize uint64 `json:"output_size"`
+}
+
+type ProblemDraftChecker struct {
+	Type     string  `json:"type"`
+	Epsilon  float64 `json:"epsilon,omitempty"`
+	Language string  `json:"language,omitempty"`
+	Source   string  `json:"source,omitempty"`
+}
+
+type ProblemDraftInteractor struct {
+	Language string `json:"language"`
+	Source   string `json:"source"`
+}
+
+type ProblemDraftValidator struct {
+	Language string `json:"language"`
+	Source   string `json:"source"`
+}
+
+type ProblemDraftGenerator struct {
+	Name     string `json:"name"`
+	Language string `json:"language"`
+	Source   string `json:"source"`
+}
+
 type ProblemDifficultyDisplayName struct {
 	Language string `json:"language"`
 	Name     string `json:"display_name"`
@@ -34,15 +74,28 @@ type ProblemDifficulty struct {
 }
 
 type ProblemDraft struct {
-	ProblemDraftID     uuid.UUID             `json:"problem_draft_id"`
-	ProblemDifficulty  ProblemDifficulty     `json:"problem_difficulty"`
-	CreatorID          uuid.UUID             `json:"creator_id"`
-	Details            []ProblemDraftDetail  `json:"details"`
-	Examples           []ProblemDraftExample `json:"examples"`
-	SubmittedProblemID uuid.NullUUID         `json:"submitted_problem_id"`
-	IsActive           bool                  `json:"is_active"`
-	CreatedAt          time.Time             `json:"created_at"`
-	UpdatedAt          time.Time             `json:"updated_at"`
+	ProblemDraftID     uuid.UUID               `json:"problem_draft_id"`
+	ProblemDifficulty  ProblemDifficulty       `json:"problem_difficulty"`
+	TargetContestID    uuid.NullUUID           `json:"target_contest_id"`
+	CreatorID          uuid.UUID               `json:"creator_id"`
+	Details            []ProblemDraftDetail    `json:"details"`
+	Examples           []ProblemDraftExample   `json:"examples"`
+	Solutions          []ProblemDraftSolution  `json:"solutions"`
+	Testcases          []ProblemDraftTestcase  `json:"testcases"`
+	TimeLimitMs        uint                    `json:"time_limit_ms"`
+	MemoryLimitMb      uint                    `json:"memory_limit_mb"`
+	IsInteractive      bool                    `json:"is_interactive"`
+	InputFile          string                  `json:"input_file"`
+	OutputFile         string                  `json:"output_file"`
+	Checker            ProblemDraftChecker     `json:"checker"`
+	Interactor         *ProblemDraftInteractor `json:"interactor"`
+	Validator          *ProblemDraftValidator  `json:"validator"`
+	Generators         []ProblemDraftGenerator `json:"generators"`
+	GeneratorScript    string                  `json:"generator_script"`
+	SubmittedProblemID uuid.NullUUID           `json:"submitted_problem_id"`
+	IsActive           bool                    `json:"is_active"`
+	CreatedAt          time.Time               `json:"created_at"`
+	UpdatedAt          time.Time               `json:"updated_at"`
 }
 
 func FromGormProblemDifficulty(problemDifficulty database.ProblemDifficulty) ProblemDifficulty {
@@ -65,12 +118,42 @@ func FromGormProblemDraft(problemDraft database.ProblemDraft, problemDifficulty
 	dto := ProblemDraft{
 		ProblemDraftID:    problemDraft.ProblemDraftID,
 		ProblemDifficulty: problemDifficulty,
+		TargetContestID:   problemDraft.TargetContestID,
 		CreatorID:         problemDraft.CreatorID,
 		Details:           make([]ProblemDraftDetail, len(problemDraft.Details)),
 		Examples:          make([]ProblemDraftExample, len(problemDraft.Examples)),
-		IsActive:          problemDraft.IsActive,
-		CreatedAt:         problemDraft.CreatedAt,
-		UpdatedAt:         problemDraft.UpdatedAt,
+		Solutions:         make([]ProblemDraftSolution, len(problemDraft.Solutions)),
+		Testcases:         make([]ProblemDraftTestcase, len(problemDraft.Testcases)),
+		Generators:        make([]ProblemDraftGenerator, len(problemDraft.Generators)),
+		GeneratorScript:   problemDraft.GeneratorScript,
+		TimeLimitMs:       problemDraft.TimeLimitMs,
+		MemoryLimitMb:     problemDraft.MemoryLimitMb,
+		IsInteractive:     problemDraft.IsInteractive,
+		InputFile:         problemDraft.InputFile,
+		OutputFile:        problemDraft.OutputFile,
+		Checker: ProblemDraftChecker{
+			Type:     problemDraft.CheckerType,
+			Epsilon:  problemDraft.CheckerEpsilon,
+			Language: problemDraft.CheckerLanguage,
+			Source:   problemDraft.CheckerSource,
+		},
+		IsActive:  problemDraft.IsActive,
+		CreatedAt: problemDraft.CreatedAt,
+		UpdatedAt: problemDraft.UpdatedAt,
+	}
+
+	if problemDraft.InteractorSource != "" {
+		dto.Interactor = &ProblemDraftInteractor{
+			Language: problemDraft.InteractorLanguage,
+			Source:   problemDraft.InteractorSource,
+		}
+	}
+
+	if problemDraft.ValidatorSource != "" {
+		dto.Validator = &ProblemDraftValidator{
+			Language: problemDraft.ValidatorLanguage,
+			Source:   problemDraft.ValidatorSource,
+		}
 	}
 
 	if problemDraft.SubmittedProblem.ProblemID != uuid.Nil {
@@ -96,5 +179,38 @@ func FromGormProblemDraft(problemDraft database.ProblemDraft, problemDifficulty
 		}
 	}
 
+	for i, solution := range problemDraft.Solutions {
+		dto.Solutions[i] = ProblemDraftSolution{
+			SolutionID:      solution.SolutionID,
+			Name:            solution.Name,
+			Language:        solution.Language,
+			ExpectedVerdict: solution.ExpectedVerdict,
+			IsMain:          solution.IsMain,
+			Source:          solution.Source,
+		}
+	}
+
+	for i, generator := range problemDraft.Generators {
+		dto.Generators[i] = ProblemDraftGenerator{
+			Name:     generator.Name,
+			Language: generator.Language,
+			Source:   generator.Source,
+		}
+	}
+
+	for i, testcase := range problemDraft.Testcases {
+		dto.Testcases[i] = FromGormProblemDraftTestcase(testcase)
+	}
+
 	return dto
 }
+
+func FromGormProblemDraftTestcase(testcase database.ProblemDraftTestcase) ProblemDraftTestcase {
+	return ProblemDraftTestcase{
+		Name:       testcase.Name,
+		InputHash:  testcase.InputHash,
+		InputSize:  testcase.InputSize,
+		OutputHash: testcase.OutputHash,
+		OutputSize: testcase.OutputSize,
+	}
+}
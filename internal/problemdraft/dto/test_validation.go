@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"strconv"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+)
+
+const (
+	TestKindExample  = "example"
+	TestKindTestcase = "testcase"
+)
+
+type TestValidationResult struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Valid   bool   `json:"valid"`
+	Message string `json:"message,omitempty"`
+}
+
+// ValidationInputs lists the inputs of the examples followed by those of the
+// testcases, which all have to pass the validator.
+func ValidationInputs(problemDraft *ProblemDraft) []contract.ValidationInput {
+	inputs := make([]contract.ValidationInput, 0, len(problemDraft.Examples)+len(problemDraft.Testcases))
+
+	for i, example := range problemDraft.Examples {
+		inputs = append(inputs, contract.ValidationInput{
+			Name:    strconv.Itoa(i + 1),
+			Content: example.Input,
+		})
+	}
+
+	for _, testcase := range problemDraft.Testcases {
+		inputs = append(inputs, contract.ValidationInput{
+			Name: testcase.Name,
+			Hash: testcase.InputHash,
+		})
+	}
+
+	return inputs
+}
+
+// FromValidationResults maps results for the inputs from ValidationInputs back
+// onto examples and testcases.
+func FromValidationResults(problemDraft *ProblemDraft, results []contract.ValidationResult) []TestValidationResult {
+	dto := make([]TestValidationResult, len(results))
+	for i, result := range results {
+		kind := TestKindTestcase
+		if i < len(problemDraft.Examples) {
+			kind = TestKindExample
+		}
+
+		dto[i] = TestValidationResult{
+			Kind:    kind,
+			Name:    result.Name,
+			Valid:   result.Valid,
+			Message: result.Message,
+		}
+	}
+
+	return dto
+}
@@ -8,6 +8,7 @@ import (
 
 type EndpointParams struct {
 	ProblemDraftsGroup *echo.Group
+	Uploads            *echoweb.UploadRoutes
 }
 
 func NewEndpointParams(
@@ -16,5 +17,6 @@ func NewEndpointParams(
 	problemDrafts := v1Group.Group.Group("/problem-drafts")
 	return &EndpointParams{
 		ProblemDraftsGroup: problemDrafts,
+		Uploads:            v1Group.Uploads,
 	}
 }
@@ -0,0 +1,7 @@
+package generatetestcases
+
+import "github.com/google/uuid"
+
+type Command struct {
+	ProblemDraftID uuid.UUID `validate:"required"`
+}
@@ -0,0 +1,182 @@
+package generatetestcases
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/dto"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrProblemDraftNotFound = errors.New("problem draft not found")
+	ErrNotCreatorOrInactive = errors.New("not the creator of the problem draft or inactive draft")
+	ErrNoGeneratorScript    = errors.New("problem draft has no generator script")
+	ErrNoMainSolution       = errors.New("problem draft has no main solution")
+)
+
+type Testcase struct {
+	TestcaseID uuid.UUID
+	Name       string
+	Input      contract.BlobInfo
+	Output     contract.BlobInfo
+}
+
+type Repository interface {
+	GetProblemDraft(ctx context.Context, problemDraftID uuid.UUID) (*dto.ProblemDraft, error)
+	ReplaceProblemDraftTestcases(
+		ctx context.Context,
+		problemDraftID uuid.UUID,
+		testcases []Testcase,
+		updatedAt time.Time,
+	) error
+}
+
+type CommandHandler struct {
+	repo         Repository
+	toolchain    contract.TestToolchain
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+	uowFactory   contract.UnitOfWorkFactory
+	l            logger.Logger
+}
+
+func NewCommandHandler(
+	repo Repository,
+	toolchain contract.TestToolchain,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+	uowFactory contract.UnitOfWorkFactory,
+	l logger.Logger,
+) *CommandHandler {
+	return &CommandHandler{
+		repo:         repo,
+		toolchain:    toolchain,
+		validator:    validator,
+		authProvider: authProvider,
+		uowFactory:   uowFactory,
+		l:            l,
+	}
+}
+
+// Handle runs the generator script of the draft and replaces its testcases with
+// the generated ones. Answers are produced by the main solution.
+func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Response, error) {
+	if command == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(command); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	user, err := h.authProvider.MustGetUser(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get user from auth provider")
+	}
+
+	problemDraft, err := h.repo.GetProblemDraft(ctx, command.ProblemDraftID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get problem draft")
+	}
+
+	if problemDraft.CreatorID != user.UserID || !problemDraft.IsActive {
+		return nil, errors.WithStack(ErrNotCreatorOrInactive)
+	}
+
+	if strings.TrimSpace(problemDraft.GeneratorScript) == "" {
+		return nil, errors.WithStack(ErrNoGeneratorScript)
+	}
+
+	request := contract.GenerateTestcasesRequest{
+		Generators:    make([]contract.Generator, len(problemDraft.Generators)),
+		Script:        problemDraft.GeneratorScript,
+		TimeLimitMs:   problemDraft.TimeLimitMs,
+		MemoryLimitMb: problemDraft.MemoryLimitMb,
+		InputFile:     problemDraft.InputFile,
+		OutputFile:    problemDraft.OutputFile,
+	}
+
+	for i, generator := range problemDraft.Generators {
+		request.Generators[i] = contract.Generator{
+			Name: generator.Name,
+			Program: contract.Program{
+				Language: generator.Language,
+				Source:   generator.Source,
+			},
+		}
+	}
+
+	if !problemDraft.IsInteractive {
+		for _, solution := range problemDraft.Solutions {
+			if solution.IsMain {
+				request.MainSolution = &contract.Program{
+					Language: solution.Language,
+					Source:   solution.Source,
+				}
+			}
+		}
+
+		if request.MainSolution == nil {
+			return nil, errors.WithStack(ErrNoMainSolution)
+		}
+	}
+
+	generated, err := h.toolchain.GenerateTestcases(ctx, request)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to generate testcases")
+	}
+
+	testcases := make([]Testcase, len(generated))
+	for i, testcase := range generated {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to generate new UUID for testcase")
+		}
+
+		testcases[i] = Testcase{
+			TestcaseID: id,
+			Name:       testcase.Name,
+			Input:      testcase.Input,
+			Output:     testcase.Output,
+		}
+	}
+
+	uow := h.uowFactory.New()
+	if err := uowhelper.Do(ctx, uow, h.l, func(ctx context.Context) error {
+		if err := h.repo.ReplaceProblemDraftTestcases(ctx, command.ProblemDraftID, testcases, time.Now()); err != nil {
+			return errors.WrapIf(err, "failed to replace problem draft testcases")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	response := &Response{
+		ProblemDraftID: command.ProblemDraftID,
+		TestcaseCount:  len(testcases),
+		Testcases:      make([]dto.ProblemDraftTestcase, len(testcases)),
+	}
+
+	for i, testcase := range testcases {
+		response.TotalSize += testcase.Input.Size + testcase.Output.Size
+		response.Testcases[i] = dto.ProblemDraftTestcase{
+			Name:       testcase.Name,
+			InputHash:  testcase.Input.Hash,
+			InputSize:  testcase.Input.Size,
+			OutputHash: testcase.Output.Hash,
+			OutputSize: testcase.Output.Size,
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,69 @@
+package generatetestcases
+
+import (
+	"net/http"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/httperror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type Endpoint struct {
+	*problemdraft.EndpointParams
+	handler *CommandHandler
+}
+
+func NewEndpoint(params *problemdraft.EndpointParams, handler *CommandHandler) *Endpoint {
+	return &Endpoint{
+		EndpointParams: params,
+		handler:        handler,
+	}
+}
+
+func (e *Endpoint) MapEndpoint() {
+	e.ProblemDraftsGroup.POST("/:problem_draft_id/testcases/generate", e.handle())
+}
+
+func (e *Endpoint) handle() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		problemDraftID, err := uuid.Parse(ctx.Param("problem_draft_id"))
+		if err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid problem_draft_id path parameter")
+		}
+
+		command := &Command{
+			ProblemDraftID: problemDraftID,
+		}
+
+		if err := ctx.Validate(command); err != nil {
+			return err
+		}
+
+		response, err := e.handler.Handle(ctx.Request().Context(), command)
+		if errors.Is(err, ErrProblemDraftNotFound) {
+			return httperror.New(http.StatusNotFound, "Problem draft not found")
+		} else if errors.Is(err, ErrNotCreatorOrInactive) {
+			return httperror.New(http.StatusForbidden, "You are not the creator of this problem draft or the draft is inactive")
+		} else if errors.Is(err, ErrNoGeneratorScript) {
+			return httperror.New(http.StatusUnprocessableEntity, "The problem draft has no generator script")
+		} else if errors.Is(err, ErrNoMainSolution) {
+			return httperror.New(http.StatusUnprocessableEntity, "A main solution is required to produce the answers")
+		} else if toolchainErr := (*contract.TestToolchainError)(nil); errors.As(err, &toolchainErr) {
+			return httperror.New(http.StatusUnprocessableEntity, toolchainErr.Message)
+		} else if errors.Is(err, contract.ErrTestToolchainUnavailable) {
+			return httperror.New(http.StatusServiceUnavailable, "Testcases cannot be generated because the judge is disabled on this server")
+		} else if errors.Is(err, contract.ErrTestToolchainBusy) {
+			return httperror.New(http.StatusServiceUnavailable, "The judge is busy, please try again later")
+		} else if errors.Is(err, contract.ErrTestToolchainTimeout) {
+			return httperror.New(http.StatusServiceUnavailable, "The programs took too long to run, please try again with fewer tests")
+		} else if err != nil {
+			return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
+		}
+
+		return ctx.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,87 @@
+package generatetestcases
+
+import (
+	"context"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/dto"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) GetProblemDraft(ctx context.Context, problemDraftID uuid.UUID) (*dto.ProblemDraft, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var problemDraft database.ProblemDraft
+	if err := db.WithContext(ctx).
+		Preload("Solutions").
+		Preload("Generators").
+		Where("problem_draft_id = ?", problemDraftID).
+		First(&problemDraft).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithStack(ErrProblemDraftNotFound)
+		}
+
+		return nil, errors.WrapIf(err, "failed to get problem draft")
+	}
+
+	response := dto.FromGormProblemDraft(problemDraft, dto.FromGormProblemDifficulty(problemDraft.ProblemDifficulty))
+	return &response, nil
+}
+
+func (r *GormRepository) ReplaceProblemDraftTestcases(
+	ctx context.Context,
+	problemDraftID uuid.UUID,
+	testcases []Testcase,
+	updatedAt time.Time,
+) error {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	if err := db.WithContext(ctx).
+		Where("problem_draft_id = ?", problemDraftID).
+		Delete(&database.ProblemDraftTestcase{}).Error; err != nil {
+		return errors.WrapIf(err, "failed to delete old problem draft testcases")
+	}
+
+	models := make([]database.ProblemDraftTestcase, len(testcases))
+	for i, testcase := range testcases {
+		models[i] = database.ProblemDraftTestcase{
+			TestcaseID:     testcase.TestcaseID,
+			ProblemDraftID: problemDraftID,
+			Position:       i,
+			Name:           testcase.Name,
+			InputHash:      testcase.Input.Hash,
+			InputSize:      testcase.Input.Size,
+			OutputHash:     testcase.Output.Hash,
+			OutputSize:     testcase.Output.Size,
+		}
+	}
+
+	if len(models) > 0 {
+		if err := db.WithContext(ctx).Create(&models).Error; err != nil {
+			return errors.WrapIf(err, "failed to create problem draft testcases")
+		}
+	}
+
+	if err := db.WithContext(ctx).
+		Model(&database.ProblemDraft{}).
+		Where("problem_draft_id = ?", problemDraftID).
+		Update("updated_at", updatedAt).Error; err != nil {
+		return errors.WrapIf(err, "failed to update problem draft")
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package generatetestcases
+
+import (
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/dto"
+
+	"github.com/google/uuid"
+)
+
+type Response struct {
+	ProblemDraftID uuid.UUID                  `json:"problem_draft_id"`
+	TestcaseCount  int                        `json:"testcase_count"`
+	TotalSize      uint64                     `json:"total_size"`
+	Testcases      []dto.ProblemDraftTestcase `json:"testcases"`
+}
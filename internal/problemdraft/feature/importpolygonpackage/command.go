@@ -0,0 +1,19 @@
+package importpolygonpackage
+
+import (
+	"io"
+
+	"github.com/google/uuid"
+)
+
+// PackageFile is satisfied by multipart.File; zip archives need random access.
+type PackageFile interface {
+	io.Reader
+	io.ReaderAt
+}
+
+type Command struct {
+	ProblemDifficultyID uuid.NullUUID
+	PackageSize         int64       `validate:"gt=0"`
+	Package             PackageFile `validate:"required"`
+}
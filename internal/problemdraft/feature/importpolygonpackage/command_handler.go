@@ -0,0 +1,264 @@
+package importpolygonpackage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/polygon"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/dto"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+const (
+	MaxPackageSize   = 256 << 20
+	maxExtractedSize = 1 << 30
+	maxTestcaseCount = 1000
+)
+
+var (
+	ErrPackageTooLarge            = errors.New("polygon package too large")
+	ErrInvalidProblemDifficultyID = errors.New("invalid problem difficulty ID")
+)
+
+// IDs are the identifiers of the rows created for an imported draft, in the
+// order of the corresponding slices of the draft.
+type IDs struct {
+	ProblemDraftID uuid.UUID
+	DetailIDs      []uuid.UUID
+	ExampleIDs     []uuid.UUID
+	SolutionIDs    []uuid.UUID
+	GeneratorIDs   []uuid.UUID
+	TestcaseIDs    []uuid.UUID
+}
+
+type Repository interface {
+	CreateProblemDraft(
+		ctx context.Context,
+		problemDraft *dto.ProblemDraft,
+		problemDifficultyID uuid.NullUUID,
+		ids IDs,
+	) error
+}
+
+type CommandHandler struct {
+	repo         Repository
+	blobStore    contract.BlobStore
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+	uowFactory   contract.UnitOfWorkFactory
+	l            logger.Logger
+}
+
+func NewCommandHandler(
+	repo Repository,
+	blobStore contract.BlobStore,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+	uowFactory contract.UnitOfWorkFactory,
+	l logger.Logger,
+) *CommandHandler {
+	return &CommandHandler{
+		repo:         repo,
+		blobStore:    blobStore,
+		validator:    validator,
+		authProvider: authProvider,
+		uowFactory:   uowFactory,
+		l:            l,
+	}
+}
+
+// Handle creates a new problem draft from a Polygon package. Everything that
+// cannot be represented on a draft is listed in the response instead of failing
+// the import.
+func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Response, error) {
+	if command == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(command); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	user, err := h.authProvider.MustGetUser(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get user from auth provider")
+	}
+
+	if command.PackageSize > MaxPackageSize {
+		return nil, errors.WithStack(ErrPackageTooLarge)
+	}
+
+	pkg, err := polygon.Parse(command.Package, command.PackageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := newDraftBuilder(pkg)
+	problemDraft, err := builder.build()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.storeTestcases(ctx, builder); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	problemDraft.CreatorID = user.UserID
+	problemDraft.IsActive = true
+	problemDraft.CreatedAt = now
+	problemDraft.UpdatedAt = now
+
+	ids, err := newIDs(problemDraft)
+	if err != nil {
+		return nil, err
+	}
+
+	uow := h.uowFactory.New()
+	if err := uowhelper.Do(ctx, uow, h.l, func(ctx context.Context) error {
+		if err := h.repo.CreateProblemDraft(ctx, problemDraft, command.ProblemDifficultyID, ids); err != nil {
+			return errors.WrapIf(err, "failed to create problem draft")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &Response{
+		ProblemDraftID: ids.ProblemDraftID,
+		TestcaseCount:  len(problemDraft.Testcases),
+		Unsupported:    builder.unsupported,
+	}, nil
+}
+
+// storeTestcases stores the tests of the package as blobs. Tests are only
+// imported when every one of them is included, since packages exported without
+// generated tests would otherwise silently lose some.
+func (h *CommandHandler) storeTestcases(ctx context.Context, b *draftBuilder) error {
+	b.draft.Testcases = []dto.ProblemDraftTestcase{}
+
+	missing := 0
+	for _, test := range b.pkg.Tests {
+		if test.Input == "" || test.Answer == "" {
+			missing++
+		}
+	}
+
+	if missing > 0 {
+		reason := fmt.Sprintf("%d of %d tests are not included in the package, so no tests are imported", missing, len(b.pkg.Tests))
+		if b.draft.GeneratorScript != "" {
+			reason += "; generate them from the generator script instead"
+		}
+
+		b.report("tests", reason)
+		return nil
+	}
+
+	if len(b.pkg.Tests) > maxTestcaseCount {
+		b.report("tests", fmt.Sprintf("more than %d tests are not supported, so no tests are imported", maxTestcaseCount))
+		return nil
+	}
+
+	remaining := int64(maxExtractedSize)
+	for _, test := range b.pkg.Tests {
+		input, err := h.putFile(ctx, b.pkg, test.Input, &remaining)
+		if err != nil {
+			return err
+		}
+
+		output, err := h.putFile(ctx, b.pkg, test.Answer, &remaining)
+		if err != nil {
+			return err
+		}
+
+		b.draft.Testcases = append(b.draft.Testcases, dto.ProblemDraftTestcase{
+			Name:       fmt.Sprint(test.Index),
+			InputHash:  input.Hash,
+			InputSize:  input.Size,
+			OutputHash: output.Hash,
+			OutputSize: output.Size,
+		})
+	}
+
+	return nil
+}
+
+func (h *CommandHandler) putFile(
+	ctx context.Context,
+	pkg *polygon.Package,
+	name string,
+	remaining *int64,
+) (contract.BlobInfo, error) {
+	rc, err := pkg.Open(name)
+	if err != nil {
+		return contract.BlobInfo{}, err
+	}
+	defer rc.Close()
+
+	info, err := h.blobStore.Put(ctx, io.LimitReader(rc, *remaining+1))
+	if err != nil {
+		return contract.BlobInfo{}, errors.WrapIf(err, "failed to store test file")
+	}
+
+	if int64(info.Size) > *remaining {
+		return contract.BlobInfo{}, errors.WithStack(ErrPackageTooLarge)
+	}
+	*remaining -= int64(info.Size)
+
+	return info, nil
+}
+
+func newIDs(problemDraft *dto.ProblemDraft) (IDs, error) {
+	problemDraftID, err := uuid.NewV7()
+	if err != nil {
+		return IDs{}, errors.WrapIf(err, "failed to generate new UUID for problem draft")
+	}
+
+	ids := IDs{ProblemDraftID: problemDraftID}
+
+	if ids.DetailIDs, err = newIDList(len(problemDraft.Details)); err != nil {
+		return IDs{}, err
+	}
+
+	if ids.ExampleIDs, err = newIDList(len(problemDraft.Examples)); err != nil {
+		return IDs{}, err
+	}
+
+	if ids.SolutionIDs, err = newIDList(len(problemDraft.Solutions)); err != nil {
+		return IDs{}, err
+	}
+
+	if ids.GeneratorIDs, err = newIDList(len(problemDraft.Generators)); err != nil {
+		return IDs{}, err
+	}
+
+	if ids.TestcaseIDs, err = newIDList(len(problemDraft.Testcases)); err != nil {
+		return IDs{}, err
+	}
+
+	return ids, nil
+}
+
+func newIDList(n int) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, n)
+	for i := range ids {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to generate new UUID")
+		}
+
+		ids[i] = id
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,463 @@
+package importpolygonpackage
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/genscript"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/polygon"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/dto"
+)
+
+const (
+	minTimeLimitMs   = 100
+	maxTimeLimitMs   = 60000
+	minMemoryLimitMb = 16
+	maxMemoryLimitMb = 4096
+	maxSourceSize    = 65536
+	maxNameLength    = 128
+)
+
+// statementLanguages maps Polygon statement languages to the languages of
+// problem details.
+var statementLanguages = map[string]string{
+	"english": constant.LanguageEnUS,
+	"chinese": constant.LanguageZhCN,
+}
+
+// solutionVerdicts maps Polygon solution tags to expected verdicts. Tags that
+// are missing have no equivalent and are reported.
+var solutionVerdicts = map[string]constant.Verdict{
+	"main":                  constant.VerdictAccepted,
+	"accepted":              constant.VerdictAccepted,
+	"wrong-answer":          constant.VerdictWrongAnswer,
+	"time-limit-exceeded":   constant.VerdictTimeLimitExceeded,
+	"memory-limit-exceeded": constant.VerdictMemoryLimitExceeded,
+	"failed":                constant.VerdictRuntimeError,
+	"rejected":              constant.VerdictRejected,
+}
+
+// builtinCheckers are the standard testlib checkers that behave like one of
+// the built-in checkers. Other standard checkers are imported as custom
+// checkers from the source Polygon bundles with them.
+var builtinCheckers = map[string]dto.ProblemDraftChecker{
+	"std::wcmp.cpp":  {Type: constant.CheckerTypeToken},
+	"std::fcmp.cpp":  {Type: constant.CheckerTypeExact},
+	"std::yesno.cpp": {Type: constant.CheckerTypeYesNo},
+	"std::rcmp4.cpp": {Type: constant.CheckerTypeFloat, Epsilon: 1e-4},
+	"std::rcmp6.cpp": {Type: constant.CheckerTypeFloat, Epsilon: 1e-6},
+	"std::rcmp9.cpp": {Type: constant.CheckerTypeFloat, Epsilon: 1e-9},
+}
+
+// draftBuilder maps a parsed package onto a problem draft, collecting the
+// parts that cannot be represented along the way. Testcases are added by the
+// handler since they have to be stored as blobs.
+type draftBuilder struct {
+	pkg         *polygon.Package
+	draft       dto.ProblemDraft
+	unsupported []UnsupportedPart
+}
+
+func newDraftBuilder(pkg *polygon.Package) *draftBuilder {
+	b := &draftBuilder{pkg: pkg}
+	for _, issue := range pkg.Issues {
+		b.report(issue.Part, issue.Reason)
+	}
+
+	return b
+}
+
+func (b *draftBuilder) report(part string, reason string) {
+	b.unsupported = append(b.unsupported, UnsupportedPart{Part: part, Reason: reason})
+}
+
+func (b *draftBuilder) build() (*dto.ProblemDraft, error) {
+	b.buildDetails()
+	b.buildLimits()
+	b.buildChecker()
+	b.buildInteractor()
+	b.buildValidator()
+	b.buildSolutions()
+	b.buildGenerators()
+
+	if err := b.buildExamples(); err != nil {
+		return nil, err
+	}
+
+	return &b.draft, nil
+}
+
+func (b *draftBuilder) buildDetails() {
+	b.draft.Details = []dto.ProblemDraftDetail{}
+
+	for _, statement := range b.pkg.Statements {
+		language, ok := statementLanguages[statement.Language]
+		if !ok {
+			b.report("statement "+statement.Language, "only English and Chinese statements are supported")
+			continue
+		}
+
+		detail := dto.ProblemDraftDetail{
+			Language:     language,
+			Title:        statement.Name,
+			Statement:    statement.Legend,
+			InputFormat:  statement.Input,
+			OutputFormat: statement.Output,
+			Note:         statement.Notes,
+		}
+
+		// Interactive problems describe the protocol in a section of its own,
+		// which is shown after the output format.
+		if statement.Interaction != "" {
+			detail.OutputFormat = strings.TrimSpace(detail.OutputFormat + "\n\n" + statement.Interaction)
+		}
+
+		if statement.Tutorial != "" {
+			b.report("statement "+statement.Language, "tutorials are not supported")
+		}
+
+		b.draft.Details = append(b.draft.Details, detail)
+	}
+}
+
+func (b *draftBuilder) buildLimits() {
+	b.draft.TimeLimitMs = constant.DefaultTimeLimitMs
+	if ms := b.pkg.TimeLimitMs; ms > 0 {
+		b.draft.TimeLimitMs = uint(min(max(ms, minTimeLimitMs), maxTimeLimitMs))
+		if int(b.draft.TimeLimitMs) != ms {
+			b.report("time limit", fmt.Sprintf("%d ms is out of range, %d ms is used instead", ms, b.draft.TimeLimitMs))
+		}
+	}
+
+	b.draft.MemoryLimitMb = constant.DefaultMemoryLimitMb
+	if bytes := b.pkg.MemoryLimitBytes; bytes > 0 {
+		mb := bytes >> 20
+		b.draft.MemoryLimitMb = uint(min(max(mb, minMemoryLimitMb), maxMemoryLimitMb))
+		if int64(b.draft.MemoryLimitMb) != mb {
+			b.report("memory limit", fmt.Sprintf("%d MB is out of range, %d MB is used instead", mb, b.draft.MemoryLimitMb))
+		}
+	}
+
+	b.draft.InputFile = b.ioFile("input file", b.pkg.InputFile)
+	b.draft.OutputFile = b.ioFile("output file", b.pkg.OutputFile)
+}
+
+func (b *draftBuilder) ioFile(part string, name string) string {
+	if name == "" {
+		return ""
+	}
+
+	if b.pkg.Interactor != nil {
+		b.report(part, "interactive problems must use standard input and output")
+		return ""
+	}
+
+	if !isValidIOFileName(name) {
+		b.report(part, fmt.Sprintf("%q is not a valid file name, standard I/O is used instead", name))
+		return ""
+	}
+
+	return name
+}
+
+func (b *draftBuilder) buildChecker() {
+	b.draft.Checker = dto.ProblemDraftChecker{Type: constant.CheckerTypeToken}
+
+	checker := b.pkg.Checker
+	if checker == nil {
+		return
+	}
+
+	if builtin, ok := builtinCheckers[checker.Name]; ok {
+		b.draft.Checker = builtin
+		return
+	}
+
+	if checker.Type != "testlib" || checker.Source == nil {
+		b.report("checker", "only testlib checkers are supported, the token checker is used instead")
+		return
+	}
+
+	program, ok := b.program("checker", *checker.Source, true)
+	if !ok {
+		b.report("checker", "the token checker is used instead")
+		return
+	}
+
+	b.draft.Checker = dto.ProblemDraftChecker{
+		Type:     constant.CheckerTypeCustom,
+		Language: program.Language,
+		Source:   program.Source,
+	}
+}
+
+func (b *draftBuilder) buildInteractor() {
+	if b.pkg.Interactor == nil {
+		return
+	}
+
+	b.draft.IsInteractive = true
+	if program, ok := b.program("interactor", *b.pkg.Interactor, true); ok {
+		b.draft.Interactor = &dto.ProblemDraftInteractor{
+			Language: program.Language,
+			Source:   program.Source,
+		}
+	}
+}
+
+func (b *draftBuilder) buildValidator() {
+	for i, source := range b.pkg.Validators {
+		if i > 0 {
+			b.report(source.Path, "only one validator is supported")
+			continue
+		}
+
+		if program, ok := b.program(source.Path, source, true); ok {
+			b.draft.Validator = &dto.ProblemDraftValidator{
+				Language: program.Language,
+				Source:   program.Source,
+			}
+		}
+	}
+}
+
+// buildSolutions imports the solutions whose tag and language are supported.
+// Should the main solution be dropped, the first accepted one takes its place
+// so that the draft keeps a main solution.
+func (b *draftBuilder) buildSolutions() {
+	b.draft.Solutions = []dto.ProblemDraftSolution{}
+	names := make(map[string]struct{})
+	hasMain := false
+
+	for _, solution := range b.pkg.Solutions {
+		verdict, ok := solutionVerdicts[solution.Tag]
+		if !ok {
+			b.report(solution.Path, fmt.Sprintf("solution tag %q is not supported", solution.Tag))
+			continue
+		}
+
+		program, ok := b.program(solution.Path, solution.Source, false)
+		if !ok {
+			continue
+		}
+
+		name := path.Base(solution.Path)
+		if _, ok := names[name]; ok || len(name) > maxNameLength {
+			b.report(solution.Path, "the solution name is too long or not unique")
+			continue
+		}
+		names[name] = struct{}{}
+
+		isMain := solution.Tag == "main" && !hasMain
+		hasMain = hasMain || isMain
+
+		b.draft.Solutions = append(b.draft.Solutions, dto.ProblemDraftSolution{
+			Name:            name,
+			Language:        program.Language,
+			ExpectedVerdict: string(verdict),
+			IsMain:          isMain,
+			Source:          program.Source,
+		})
+	}
+
+	if hasMain {
+		return
+	}
+
+	for i, solution := range b.draft.Solutions {
+		if constant.Verdict(solution.ExpectedVerdict) == constant.VerdictAccepted {
+			b.draft.Solutions[i].IsMain = true
+			b.report("main solution", fmt.Sprintf("the main solution could not be imported, %s is used instead", solution.Name))
+			return
+		}
+	}
+
+	if len(b.draft.Solutions) > 0 {
+		b.report("solutions", "no accepted solution could be imported, so the other solutions are dropped")
+		b.draft.Solutions = []dto.ProblemDraftSolution{}
+	}
+}
+
+// buildGenerators turns the commands of generated tests into a generator
+// script, provided that every generator they invoke can be imported.
+func (b *draftBuilder) buildGenerators() {
+	b.draft.Generators = []dto.ProblemDraftGenerator{}
+
+	executables := make(map[string]polygon.Source, len(b.pkg.Executables))
+	for _, executable := range b.pkg.Executables {
+		name := strings.TrimSuffix(path.Base(executable.Path), path.Ext(executable.Path))
+		executables[name] = executable
+	}
+
+	var (
+		script      strings.Builder
+		used        = make(map[string]struct{})
+		manualCount int
+	)
+
+	for _, test := range b.pkg.Tests {
+		if test.Method != "generated" {
+			manualCount++
+			continue
+		}
+
+		fields := strings.Fields(test.Cmd)
+		if len(fields) == 0 {
+			b.report(fmt.Sprintf("test %d", test.Index), "the generator command is empty")
+			return
+		}
+
+		if _, ok := executables[fields[0]]; !ok {
+			b.report("generator script", fmt.Sprintf("generator %s of test %d is not in the package", fields[0], test.Index))
+			return
+		}
+
+		used[fields[0]] = struct{}{}
+		fmt.Fprintf(&script, "%s > %d\n", test.Cmd, test.Index)
+	}
+
+	if len(used) == 0 {
+		return
+	}
+
+	generators := make([]dto.ProblemDraftGenerator, 0, len(used))
+	for _, executable := range b.pkg.Executables {
+		name := strings.TrimSuffix(path.Base(executable.Path), path.Ext(executable.Path))
+		if _, ok := used[name]; !ok {
+			continue
+		}
+
+		program, ok := b.program(executable.Path, executable, false)
+		if !ok || !genscript.IsValidName(name) {
+			b.report("generator script", fmt.Sprintf("generator %s cannot be imported", name))
+			return
+		}
+
+		generators = append(generators, dto.ProblemDraftGenerator{
+			Name:     name,
+			Language: program.Language,
+			Source:   program.Source,
+		})
+	}
+
+	if _, err := genscript.Parse(script.String()); err != nil {
+		b.report("generator script", err.Error())
+		return
+	}
+
+	b.draft.Generators = generators
+	b.draft.GeneratorScript = script.String()
+
+	if manualCount > 0 {
+		b.report("generator script", fmt.Sprintf(
+			"%d of %d tests are manual, they cannot be expressed in the generator script and would be dropped when tests are generated",
+			manualCount,
+			len(b.pkg.Tests),
+		))
+	}
+}
+
+// buildExamples takes the examples from the sample tests of the package, or
+// from the first statement when the tests are not included.
+func (b *draftBuilder) buildExamples() error {
+	b.draft.Examples = []dto.ProblemDraftExample{}
+
+	for _, test := range b.pkg.Tests {
+		if !test.Sample {
+			continue
+		}
+
+		if test.Input == "" || test.Answer == "" {
+			b.draft.Examples = b.draft.Examples[:0]
+			break
+		}
+
+		input, err := b.pkg.ReadFile(test.Input)
+		if err != nil {
+			return err
+		}
+
+		output, err := b.pkg.ReadFile(test.Answer)
+		if err != nil {
+			return err
+		}
+
+		b.draft.Examples = append(b.draft.Examples, dto.ProblemDraftExample{
+			Input:  string(input),
+			Output: string(output),
+		})
+	}
+
+	if len(b.draft.Examples) > 0 || len(b.pkg.Statements) == 0 {
+		return nil
+	}
+
+	for _, sample := range b.pkg.Statements[0].Samples {
+		b.draft.Examples = append(b.draft.Examples, dto.ProblemDraftExample{
+			Input:  sample.Input,
+			Output: sample.Output,
+		})
+	}
+
+	return nil
+}
+
+// program maps a Polygon source onto a judge language. Checkers, interactors
+// and validators are built against testlib and must be written in C++.
+func (b *draftBuilder) program(part string, source polygon.Source, cppOnly bool) (contract.Program, bool) {
+	language, ok := sourceLanguage(source.Type)
+	if ok && cppOnly {
+		ok = language == constant.ProgrammingLanguageCpp17 || language == constant.ProgrammingLanguageCpp20
+	}
+
+	if !ok {
+		b.report(part, fmt.Sprintf("source type %q is not supported", source.Type))
+		return contract.Program{}, false
+	}
+
+	if len(source.Content) > maxSourceSize {
+		b.report(part, "the source is larger than 64 KiB")
+		return contract.Program{}, false
+	}
+
+	return contract.Program{Language: language, Source: source.Content}, true
+}
+
+// sourceLanguage maps Polygon source types such as "cpp.g++17" or
+// "java21" onto the languages the judge supports.
+func sourceLanguage(sourceType string) (string, bool) {
+	switch {
+	case strings.HasPrefix(sourceType, "cpp.") &&
+		(strings.HasSuffix(sourceType, "++20") || strings.HasSuffix(sourceType, "++23")):
+		return constant.ProgrammingLanguageCpp20, true
+	case strings.HasPrefix(sourceType, "cpp."):
+		return constant.ProgrammingLanguageCpp17, true
+	case strings.HasPrefix(sourceType, "c.gcc"):
+		return constant.ProgrammingLanguageC, true
+	case sourceType == "python.3" || sourceType == "python.pypy3":
+		return constant.ProgrammingLanguagePython3, true
+	case strings.HasPrefix(sourceType, "java"):
+		return constant.ProgrammingLanguageJava, true
+	default:
+		return "", false
+	}
+}
+
+// isValidIOFileName accepts plain file names without any path components,
+// like the check on drafts that are edited directly.
+func isValidIOFileName(name string) bool {
+	if name == "" || strings.HasPrefix(name, ".") || len(name) > 64 {
+		return false
+	}
+
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '_' || c == '-') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,87 @@
+package importpolygonpackage
+
+import (
+	"net/http"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/httperror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/polygon"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// multipartOverhead leaves room for multipart boundaries and the other form
+// fields on top of the package itself when capping the request body.
+const multipartOverhead = 64 << 10
+
+type Endpoint struct {
+	*problemdraft.EndpointParams
+	handler *CommandHandler
+}
+
+func NewEndpoint(params *problemdraft.EndpointParams, handler *CommandHandler) *Endpoint {
+	return &Endpoint{
+		EndpointParams: params,
+		handler:        handler,
+	}
+}
+
+func (e *Endpoint) MapEndpoint() {
+	e.Uploads.Add(e.ProblemDraftsGroup.POST("/import/polygon", e.handle()))
+}
+
+func (e *Endpoint) handle() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		req := ctx.Request()
+		req.Body = http.MaxBytesReader(ctx.Response(), req.Body, MaxPackageSize+multipartOverhead)
+
+		fileHeader, err := ctx.FormFile("package")
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return httperror.New(http.StatusRequestEntityTooLarge, "The package is too large")
+		} else if err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid request format")
+		}
+
+		var problemDifficultyID uuid.NullUUID
+		if value := ctx.FormValue("problem_difficulty_id"); value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				return httperror.New(http.StatusBadRequest, "Invalid problem_difficulty_id form field")
+			}
+
+			problemDifficultyID = uuid.NullUUID{UUID: id, Valid: true}
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid request format")
+		}
+		defer file.Close()
+
+		command := &Command{
+			ProblemDifficultyID: problemDifficultyID,
+			PackageSize:         fileHeader.Size,
+			Package:             file,
+		}
+
+		if err := ctx.Validate(command); err != nil {
+			return err
+		}
+
+		response, err := e.handler.Handle(req.Context(), command)
+		if errors.Is(err, ErrPackageTooLarge) {
+			return httperror.New(http.StatusRequestEntityTooLarge, "The package is too large")
+		} else if errors.Is(err, polygon.ErrInvalidPackage) {
+			return httperror.New(http.StatusUnprocessableEntity, err.Error())
+		} else if errors.Is(err, ErrInvalidProblemDifficultyID) {
+			return httperror.New(http.StatusUnprocessableEntity, "The problem difficulty ID you provided doesn't correspond to any valid problem difficulties")
+		} else if err != nil {
+			return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
+		}
+
+		return ctx.JSON(http.StatusCreated, response)
+	}
+}
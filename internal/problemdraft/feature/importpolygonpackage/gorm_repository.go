@@ -0,0 +1,141 @@
+package importpolygonpackage
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/dto"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) CreateProblemDraft(
+	ctx context.Context,
+	problemDraft *dto.ProblemDraft,
+	problemDifficultyID uuid.NullUUID,
+	ids IDs,
+) error {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	if problemDifficultyID.Valid {
+		var count int64
+		if err := db.WithContext(ctx).
+			Model(&database.ProblemDifficulty{}).
+			Where("problem_difficulty_id = ?", problemDifficultyID.UUID).
+			Count(&count).Error; err != nil {
+			return errors.WrapIf(err, "failed to count problem difficulties")
+		} else if count == 0 {
+			return errors.WithStack(ErrInvalidProblemDifficultyID)
+		}
+	}
+
+	model := database.ProblemDraft{
+		ProblemDraftID:      ids.ProblemDraftID,
+		CreatorID:           problemDraft.CreatorID,
+		ProblemDifficultyID: problemDifficultyID,
+		Details:             make([]database.ProblemDraftDetail, len(problemDraft.Details)),
+		Examples:            make([]database.ProblemDraftExample, len(problemDraft.Examples)),
+		Solutions:           make([]database.ProblemDraftSolution, len(problemDraft.Solutions)),
+		Generators:          make([]database.ProblemDraftGenerator, len(problemDraft.Generators)),
+		Testcases:           make([]database.ProblemDraftTestcase, len(problemDraft.Testcases)),
+		TimeLimitMs:         problemDraft.TimeLimitMs,
+		MemoryLimitMb:       problemDraft.MemoryLimitMb,
+		IsInteractive:       problemDraft.IsInteractive,
+		InputFile:           problemDraft.InputFile,
+		OutputFile:          problemDraft.OutputFile,
+		CheckerType:         problemDraft.Checker.Type,
+		CheckerEpsilon:      problemDraft.Checker.Epsilon,
+		CheckerLanguage:     problemDraft.Checker.Language,
+		CheckerSource:       problemDraft.Checker.Source,
+		GeneratorScript:     problemDraft.GeneratorScript,
+		IsActive:            problemDraft.IsActive,
+		CreatedAt:           problemDraft.CreatedAt,
+		UpdatedAt:           problemDraft.UpdatedAt,
+	}
+
+	if problemDraft.Interactor != nil {
+		model.InteractorLanguage = problemDraft.Interactor.Language
+		model.InteractorSource = problemDraft.Interactor.Source
+	}
+
+	if problemDraft.Validator != nil {
+		model.ValidatorLanguage = problemDraft.Validator.Language
+		model.ValidatorSource = problemDraft.Validator.Source
+	}
+
+	for i, detail := range problemDraft.Details {
+		model.Details[i] = database.ProblemDraftDetail{
+			DetailID:       ids.DetailIDs[i],
+			ProblemDraftID: model.ProblemDraftID,
+			Language:       detail.Language,
+			Title:          detail.Title,
+			Background:     detail.Background,
+			Statement:      detail.Statement,
+			InputFormat:    detail.InputFormat,
+			OutputFormat:   detail.OutputFormat,
+			Note:           detail.Note,
+		}
+	}
+
+	for i, example := range problemDraft.Examples {
+		model.Examples[i] = database.ProblemDraftExample{
+			ExampleID:      ids.ExampleIDs[i],
+			ProblemDraftID: model.ProblemDraftID,
+			Input:          example.Input,
+			Output:         example.Output,
+		}
+	}
+
+	for i, solution := range problemDraft.Solutions {
+		model.Solutions[i] = database.ProblemDraftSolution{
+			SolutionID:      ids.SolutionIDs[i],
+			ProblemDraftID:  model.ProblemDraftID,
+			Name:            solution.Name,
+			Language:        solution.Language,
+			ExpectedVerdict: solution.ExpectedVerdict,
+			IsMain:          solution.IsMain,
+			Source:          solution.Source,
+		}
+	}
+
+	for i, generator := range problemDraft.Generators {
+		model.Generators[i] = database.ProblemDraftGenerator{
+			GeneratorID:    ids.GeneratorIDs[i],
+			ProblemDraftID: model.ProblemDraftID,
+			Name:           generator.Name,
+			Language:       generator.Language,
+			Source:         generator.Source,
+		}
+	}
+
+	for i, testcase := range problemDraft.Testcases {
+		model.Testcases[i] = database.ProblemDraftTestcase{
+			TestcaseID:     ids.TestcaseIDs[i],
+			ProblemDraftID: model.ProblemDraftID,
+			Position:       i,
+			Name:           testcase.Name,
+			InputHash:      testcase.InputHash,
+			InputSize:      testcase.InputSize,
+			OutputHash:     testcase.OutputHash,
+			OutputSize:     testcase.OutputSize,
+		}
+	}
+
+	if err := db.WithContext(ctx).Create(&model).Error; err != nil {
+		return errors.WrapIf(err, "failed to create problem draft")
+	}
+
+	return nil
+}
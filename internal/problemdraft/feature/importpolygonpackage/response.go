@@ -0,0 +1,16 @@
+package importpolygonpackage
+
+import "github.com/google/uuid"
+
+// UnsupportedPart is a part of the package that was left out of the draft or
+// only imported approximately.
+type UnsupportedPart struct {
+	Part   string `json:"part"`
+	Reason string `json:"reason"`
+}
+
+type Response struct {
+	ProblemDraftID uuid.UUID         `json:"problem_draft_id"`
+	TestcaseCount  int               `json:"testcase_count"`
+	Unsupported    []UnsupportedPart `json:"unsupported"`
+}
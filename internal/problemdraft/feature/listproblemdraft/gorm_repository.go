@@ -34,6 +34,11 @@ func (g *GormRepository) GetActiveProblemDraftsByCreator(
 		Preload("ProblemDifficulty.DisplayNames").
 		Preload("Details").
 		Preload("Examples").
+		Preload("Solutions").
+		Preload("Generators").
+		Preload("Testcases", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
 		Preload("SubmittedProblem").
 		Where("creator_id = ? AND is_active = ?", userID, true).
 		Find(&problemDrafts).Error; err != nil {
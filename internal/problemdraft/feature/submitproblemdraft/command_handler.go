@@ -4,11 +4,15 @@ import (
 	"context"
 	"time"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/similarity"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/workflow"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/dto"
 
 	"emperror.dev/errors"
@@ -22,11 +26,36 @@ var (
 	ErrProblemDraftNotActive    = errors.New("problem draft is not active")
 	ErrNotCreator               = errors.New("not the creator of the problem draft")
 	ErrMissingProblemDifficulty = errors.New("problem draft missing difficulty")
+	ErrExceedsContestLimits     = errors.New("problem limits exceed contest maximums")
+	ErrContestClosed            = errors.New("contest no longer accepts problems")
+	ErrMissingInteractor        = errors.New("interactive problem draft missing interactor")
+	ErrInvalidTests             = errors.New("tests rejected by the validator")
 )
 
+// InvalidTestsError carries the per-test report behind ErrInvalidTests.
+type InvalidTestsError struct {
+	Results []dto.TestValidationResult
+}
+
+func (e *InvalidTestsError) Error() string {
+	return ErrInvalidTests.Error()
+}
+
+func (e *InvalidTestsError) Is(target error) bool {
+	return target == ErrInvalidTests
+}
+
+type TargetContest struct {
+	MaxTimeLimitMs   uint
+	MaxMemoryLimitMb uint
+	Phase            constant.ContestPhase
+	DeadlineDatetime time.Time
+}
+
 type Repository interface {
 	GetProblemDraft(ctx context.Context, problemDraftID uuid.UUID) (*dto.ProblemDraft, error)
-	GetProblemStatus(ctx context.Context, problemID uuid.UUID) (*constant.ProblemStatus, error)
+	LockProblemStatus(ctx context.Context, problemID uuid.UUID) (*constant.ProblemStatus, error)
+	GetTargetContest(ctx context.Context, contestID uuid.UUID) (*TargetContest, error)
 	SetProblemDraftInactive(ctx context.Context, problemDraftID uuid.UUID) error
 	UpsertProblemFromDraft(
 		ctx context.Context,
@@ -39,33 +68,58 @@ type Repository interface {
 		ctx context.Context,
 		problemID uuid.UUID,
 		draft *dto.ProblemDraft,
+		fingerprint problem.Fingerprint,
 		createdAt time.Time,
 	) (uuid.UUID, error)
+	GetFingerprints(ctx context.Context, excludedProblemID uuid.UUID) ([]problem.Fingerprint, error)
+	CreateSimilarities(
+		ctx context.Context,
+		problemID uuid.UUID,
+		problemVersionID uuid.UUID,
+		duplicates []problem.Duplicate,
+		createdAt time.Time,
+	) error
+	QueueJudgeRuns(
+		ctx context.Context,
+		problemID uuid.UUID,
+		problemVersionID uuid.UUID,
+		requestedBy uuid.UUID,
+		createdAt time.Time,
+	) error
 }
 
 type CommandHandler struct {
 	repo         Repository
+	machine      *workflow.Machine
 	validator    *validator.Validate
 	authProvider contract.AuthProvider
 	uowFactory   contract.UnitOfWorkFactory
 	broadcaster  contract.MessageBroadcaster
+	judgeQueue   contract.JudgeQueue
+	toolchain    contract.TestToolchain
 	l            logger.Logger
 }
 
 func NewCommandHandler(
 	repo Repository,
+	machine *workflow.Machine,
 	validator *validator.Validate,
 	authProvider contract.AuthProvider,
 	uowFactory contract.UnitOfWorkFactory,
 	broadcaster contract.MessageBroadcaster,
+	judgeQueue contract.JudgeQueue,
+	toolchain contract.TestToolchain,
 	l logger.Logger,
 ) *CommandHandler {
 	return &CommandHandler{
 		repo:         repo,
+		machine:      machine,
 		validator:    validator,
 		authProvider: authProvider,
 		uowFactory:   uowFactory,
 		broadcaster:  broadcaster,
+		judgeQueue:   judgeQueue,
+		toolchain:    toolchain,
 		l:            l,
 	}
 }
@@ -99,29 +153,51 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Respons
 		return nil, errors.WithStack(ErrMissingProblemDifficulty)
 	}
 
-	isResubmission := problemDraft.SubmittedProblemID.Valid
-	targetStatus := constant.ProblemStatusPendingReview
+	if problemDraft.IsInteractive && problemDraft.Interactor == nil {
+		return nil, errors.WithStack(ErrMissingInteractor)
+	}
 
-	if isResubmission {
-		status, err := h.repo.GetProblemStatus(ctx, problemDraft.SubmittedProblemID.UUID)
+	if err := h.validateTests(ctx, problemDraft); err != nil {
+		return nil, err
+	}
+
+	// The contest picked on the draft is the target unless the submission
+	// names another one.
+	targetContestID := problemDraft.TargetContestID
+	if command.TargetContestID.Valid {
+		targetContestID = command.TargetContestID
+	}
+
+	if targetContestID.Valid {
+		target, err := h.repo.GetTargetContest(ctx, targetContestID.UUID)
 		if err != nil {
-			return nil, errors.WrapIf(err, "failed to get problem status")
+			return nil, errors.WrapIf(err, "failed to get target contest")
 		}
 
-		if status != nil {
-			switch *status {
-			case constant.ProblemStatusTestingChangesRequested:
-				targetStatus = constant.ProblemStatusPendingTesting
-			case constant.ProblemStatusNeedsRevision:
-				targetStatus = constant.ProblemStatusPendingReview
-			default:
-				targetStatus = *status
-			}
+		if targetContestID != problemDraft.TargetContestID &&
+			contest.IsProblemSetLocked(target.Phase, target.DeadlineDatetime, time.Now()) {
+			return nil, errors.WithStack(ErrContestClosed)
+		}
+
+		limits := contest.Limits{MaxTimeLimitMs: target.MaxTimeLimitMs, MaxMemoryLimitMb: target.MaxMemoryLimitMb}
+		if !limits.Allows(problemDraft.TimeLimitMs, problemDraft.MemoryLimitMb) {
+			return nil, errors.WithStack(ErrExceedsContestLimits)
 		}
 	}
 
+	isResubmission := problemDraft.SubmittedProblemID.Valid
+	fingerprint := newFingerprint(problemDraft)
+
+	// Judging the solutions only tells something once there are tests to run.
+	shouldJudge := len(problemDraft.Solutions) > 0 && len(problemDraft.Testcases) > 0
+
 	uow := h.uowFactory.New()
-	return uowhelper.DoWithResult(ctx, uow, h.l, func(ctx context.Context) (*Response, error) {
+	response, err := uowhelper.DoWithResult(ctx, uow, h.l, func(ctx context.Context) (*Response, error) {
+		transition, err := h.resolveTransition(ctx, problemDraft)
+		if err != nil {
+			return nil, err
+		}
+
 		if err := h.repo.SetProblemDraftInactive(ctx, command.ProblemDraftID); err != nil {
 			return nil, errors.WrapIf(err, "failed to set problem draft inactive")
 		}
@@ -131,19 +207,34 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Respons
 		problemID, err := h.repo.UpsertProblemFromDraft(
 			ctx,
 			problemDraft,
-			command.TargetContestID,
-			targetStatus,
+			targetContestID,
+			transition.To,
 			timestamp,
 		)
 		if err != nil {
 			return nil, errors.WrapIf(err, "failed to create problem from draft")
 		}
 
-		problemVersionID, err := h.repo.CreateProblemVersionFromDraft(ctx, problemID, problemDraft, timestamp)
+		problemVersionID, err := h.repo.CreateProblemVersionFromDraft(ctx, problemID, problemDraft, fingerprint, timestamp)
 		if err != nil {
 			return nil, errors.WrapIf(err, "failed to create problem version from draft")
 		}
 
+		duplicates, err := h.findDuplicates(ctx, problemID, problemVersionID, fingerprint, timestamp)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := h.machine.Record(ctx, problemID, transition, ""); err != nil {
+			return nil, errors.WrapIf(err, "failed to record problem status transition")
+		}
+
+		if shouldJudge {
+			if err := h.repo.QueueJudgeRuns(ctx, problemID, problemVersionID, user.UserID, timestamp); err != nil {
+				return nil, errors.WrapIf(err, "failed to queue judge runs")
+			}
+		}
+
 		details, err := h.authProvider.MustGetUserDetails(ctx, user.UserID)
 		if err != nil {
 			return nil, errors.WrapIf(err, "failed to get user details")
@@ -155,18 +246,135 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Respons
 		}
 
 		if isResubmission {
-			if err := h.broadcaster.BroadcastEditedMessage(problemID, broadcastUser, timestamp); err != nil {
+			if err := h.broadcaster.BroadcastEditedMessage(ctx, problemID, broadcastUser, timestamp); err != nil {
 				return nil, errors.WrapIf(err, "failed to broadcast edited message")
 			}
 		} else {
-			if err := h.broadcaster.BroadcastSubmittedMessage(problemID, broadcastUser, timestamp); err != nil {
+			if err := h.broadcaster.BroadcastSubmittedMessage(ctx, problemID, broadcastUser, timestamp); err != nil {
 				return nil, errors.WrapIf(err, "failed to broadcast submitted message")
 			}
 		}
 
+		if len(duplicates) > 0 {
+			if err := h.broadcaster.BroadcastDuplicateMessage(ctx, problemID, timestamp); err != nil {
+				return nil, errors.WrapIf(err, "failed to broadcast duplicate message")
+			}
+		}
+
 		return &Response{
 			ProblemID:        problemID,
 			ProblemVersionID: problemVersionID,
 		}, nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	if shouldJudge {
+		h.judgeQueue.Notify()
+	}
+
+	return response, nil
+}
+
+// resolveTransition resolves the submission of the draft from the status of
+// its problem, which stays locked until the transaction ends so that it
+// cannot move on before the problem is updated.
+func (h *CommandHandler) resolveTransition(ctx context.Context, problemDraft *dto.ProblemDraft) (workflow.Transition, error) {
+	event, fromStatus := workflow.EventSubmit, workflow.StatusNone
+
+	if problemDraft.SubmittedProblemID.Valid {
+		status, err := h.repo.LockProblemStatus(ctx, problemDraft.SubmittedProblemID.UUID)
+		if err != nil {
+			return workflow.Transition{}, errors.WrapIf(err, "failed to get problem status")
+		}
+
+		if status != nil {
+			event, fromStatus = workflow.EventResubmit, *status
+		}
+	}
+
+	transition, err := h.machine.Resolve(ctx, fromStatus, event)
+	if err != nil {
+		return workflow.Transition{}, errors.WrapIf(err, "failed to resolve problem status transition")
+	}
+
+	return transition, nil
+}
+
+// findDuplicates compares the new version with the latest version of every
+// other problem and records the potential duplicates.
+func (h *CommandHandler) findDuplicates(
+	ctx context.Context,
+	problemID uuid.UUID,
+	problemVersionID uuid.UUID,
+	fingerprint problem.Fingerprint,
+	timestamp time.Time,
+) ([]problem.Duplicate, error) {
+	others, err := h.repo.GetFingerprints(ctx, problemID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get fingerprints of other problems")
+	}
+
+	fingerprint.ProblemID = problemID
+	duplicates := problem.FindDuplicates(fingerprint, others)
+	if len(duplicates) == 0 {
+		return nil, nil
+	}
+
+	if err := h.repo.CreateSimilarities(ctx, problemID, problemVersionID, duplicates, timestamp); err != nil {
+		return nil, errors.WrapIf(err, "failed to create problem similarities")
+	}
+
+	return duplicates, nil
+}
+
+// newFingerprint signs the statement of the draft in every language, in the
+// order of its details, and its examples.
+func newFingerprint(problemDraft *dto.ProblemDraft) problem.Fingerprint {
+	fingerprint := problem.Fingerprint{
+		Statements: make([]similarity.Signature, 0, len(problemDraft.Details)),
+	}
+
+	for _, detail := range problemDraft.Details {
+		fingerprint.Statements = append(fingerprint.Statements, problem.StatementSignature(
+			detail.Background,
+			detail.Statement,
+			detail.InputFormat,
+			detail.OutputFormat,
+		))
+	}
+
+	texts := make([]string, 0, 2*len(problemDraft.Examples))
+	for _, example := range problemDraft.Examples {
+		texts = append(texts, example.Input, example.Output)
+	}
+
+	fingerprint.Examples = problem.ExampleSignature(texts...)
+	return fingerprint
+}
+
+// validateTests runs the validator, if the draft has one, on every example and
+// testcase, so that invalid tests never make it into a version.
+func (h *CommandHandler) validateTests(ctx context.Context, problemDraft *dto.ProblemDraft) error {
+	if problemDraft.Validator == nil {
+		return nil
+	}
+
+	results, err := h.toolchain.ValidateInputs(ctx, contract.Program{
+		Language: problemDraft.Validator.Language,
+		Source:   problemDraft.Validator.Source,
+	}, dto.ValidationInputs(problemDraft))
+	if err != nil {
+		return errors.WrapIf(err, "failed to validate tests")
+	}
+
+	report := dto.FromValidationResults(problemDraft, results)
+	for _, result := range report {
+		if !result.Valid {
+			return errors.WithStack(&InvalidTestsError{Results: report})
+		}
+	}
+
+	return nil
 }
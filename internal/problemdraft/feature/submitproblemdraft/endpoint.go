@@ -3,7 +3,9 @@ package submitproblemdraft
 import (
 	"net/http"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/httperror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem/workflow"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft"
 
 	"emperror.dev/errors"
@@ -62,8 +64,29 @@ func (e *Endpoint) handle() echo.HandlerFunc {
 		} else if errors.Is(err, ErrMissingProblemDifficulty) {
 			return httperror.New(http.StatusUnprocessableEntity, "The problem draft you're trying to submit is missing its problem difficulty").
 				WithType(httperror.ErrTypeIncompleteProblemDraft)
+		} else if errors.Is(err, ErrMissingInteractor) {
+			return httperror.New(http.StatusUnprocessableEntity, "Interactive problems need an interactor before they can be submitted").
+				WithType(httperror.ErrTypeIncompleteProblemDraft)
+		} else if invalidTestsErr := (*InvalidTestsError)(nil); errors.As(err, &invalidTestsErr) {
+			return httperror.New(http.StatusUnprocessableEntity, "Some tests were rejected by the validator").
+				WithType(httperror.ErrTypeInvalidTestcases).
+				WithDetails(invalidTestsErr.Results)
+		} else if toolchainErr := (*contract.TestToolchainError)(nil); errors.As(err, &toolchainErr) {
+			return httperror.New(http.StatusUnprocessableEntity, toolchainErr.Message)
+		} else if errors.Is(err, contract.ErrTestToolchainUnavailable) {
+			return httperror.New(http.StatusServiceUnavailable, "Validators cannot be run because the judge is disabled on this server")
+		} else if errors.Is(err, contract.ErrTestToolchainBusy) {
+			return httperror.New(http.StatusServiceUnavailable, "The judge is busy, please try again later")
+		} else if errors.Is(err, contract.ErrTestToolchainTimeout) {
+			return httperror.New(http.StatusServiceUnavailable, "The programs took too long to run, please try again with fewer tests")
+		} else if errors.Is(err, ErrExceedsContestLimits) {
+			return httperror.New(http.StatusUnprocessableEntity, "The problem's time or memory limit exceeds the maximum allowed by the contest")
 		} else if errors.Is(err, ErrContestNotFound) {
 			return httperror.New(http.StatusUnprocessableEntity, "The contest you're trying to submit to does not exist")
+		} else if errors.Is(err, ErrContestClosed) {
+			return httperror.New(http.StatusUnprocessableEntity, "The contest you're trying to submit to is frozen or past its deadline")
+		} else if errors.Is(err, workflow.ErrTransitionNotAllowed) {
+			return httperror.New(http.StatusConflict, "The problem cannot be resubmitted in its current status")
 		} else if err != nil {
 			return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
 		}
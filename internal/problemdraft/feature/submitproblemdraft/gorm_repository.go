@@ -6,6 +6,8 @@ import (
 
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/similarity"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problem"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/dto"
 
 	"emperror.dev/errors"
@@ -31,6 +33,11 @@ func (r *GormRepository) GetProblemDraft(ctx context.Context, problemDraftID uui
 	if err := db.WithContext(ctx).
 		Preload("Examples").
 		Preload("Details").
+		Preload("Solutions").
+		Preload("Generators").
+		Preload("Testcases", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
 		Preload("ProblemDifficulty").
 		Preload("ProblemDifficulty.DisplayNames").
 		Preload("SubmittedProblem").
@@ -47,11 +54,14 @@ func (r *GormRepository) GetProblemDraft(ctx context.Context, problemDraftID uui
 	return &response, nil
 }
 
-func (r *GormRepository) GetProblemStatus(ctx context.Context, problemID uuid.UUID) (*constant.ProblemStatus, error) {
+// LockProblemStatus gets the status of the problem and locks it until the end
+// of the transaction, or returns nil if the problem does not exist.
+func (r *GormRepository) LockProblemStatus(ctx context.Context, problemID uuid.UUID) (*constant.ProblemStatus, error) {
 	db := database.GetDBFromContext(ctx, r.db)
 
 	var problem database.Problem
 	if err := db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
 		Where("problem_id = ?", problemID).
 		Select("status").
 		First(&problem).Error; err != nil {
@@ -63,9 +73,36 @@ func (r *GormRepository) GetProblemStatus(ctx context.Context, problemID uuid.UU
 	}
 
 	status := constant.FromStringToProblemStatus(problem.Status)
+	if status == "" {
+		return nil, errors.Errorf("problem has unknown status %q", problem.Status)
+	}
+
 	return &status, nil
 }
 
+func (r *GormRepository) GetTargetContest(ctx context.Context, contestID uuid.UUID) (*TargetContest, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var contest database.Contest
+	if err := db.WithContext(ctx).
+		Select("max_time_limit_ms", "max_memory_limit_mb", "phase", "deadline_datetime").
+		Where("contest_id = ?", contestID).
+		First(&contest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithStack(ErrContestNotFound)
+		}
+
+		return nil, errors.WrapIf(err, "failed to get target contest")
+	}
+
+	return &TargetContest{
+		MaxTimeLimitMs:   contest.MaxTimeLimitMs,
+		MaxMemoryLimitMb: contest.MaxMemoryLimitMb,
+		Phase:            constant.FromStringToContestPhase(contest.Phase),
+		DeadlineDatetime: contest.DeadlineDatetime,
+	}, nil
+}
+
 func (r *GormRepository) SetProblemDraftInactive(ctx context.Context, problemDraftID uuid.UUID) error {
 	db := database.GetDBFromContext(ctx, r.db)
 
@@ -143,6 +180,7 @@ func (r *GormRepository) CreateProblemVersionFromDraft(
 	ctx context.Context,
 	problemID uuid.UUID,
 	draft *dto.ProblemDraft,
+	fingerprint problem.Fingerprint,
 	createdAt time.Time,
 ) (uuid.UUID, error) {
 	db := database.GetDBFromContext(ctx, r.db)
@@ -157,11 +195,35 @@ func (r *GormRepository) CreateProblemVersionFromDraft(
 		ProblemID:           problemID,
 		ProblemDifficultyID: draft.ProblemDifficulty.ProblemDifficultyID,
 		SubmittedBy:         draft.CreatorID,
+		TimeLimitMs:         draft.TimeLimitMs,
+		MemoryLimitMb:       draft.MemoryLimitMb,
+		IsInteractive:       draft.IsInteractive,
+		InputFile:           draft.InputFile,
+		OutputFile:          draft.OutputFile,
+		CheckerType:         draft.Checker.Type,
+		CheckerEpsilon:      draft.Checker.Epsilon,
+		CheckerLanguage:     draft.Checker.Language,
+		CheckerSource:       draft.Checker.Source,
+		GeneratorScript:     draft.GeneratorScript,
 		Details:             make([]database.ProblemVersionDetail, len(draft.Details)),
 		Examples:            make([]database.ProblemVersionExample, len(draft.Examples)),
+		Solutions:           make([]database.ProblemVersionSolution, len(draft.Solutions)),
+		Generators:          make([]database.ProblemVersionGenerator, len(draft.Generators)),
+		Testcases:           make([]database.ProblemVersionTestcase, len(draft.Testcases)),
+		ExampleSignature:    fingerprint.Examples.Bytes(),
 		CreatedAt:           createdAt,
 	}
 
+	if draft.Interactor != nil {
+		problemVersion.InteractorLanguage = draft.Interactor.Language
+		problemVersion.InteractorSource = draft.Interactor.Source
+	}
+
+	if draft.Validator != nil {
+		problemVersion.ValidatorLanguage = draft.Validator.Language
+		problemVersion.ValidatorSource = draft.Validator.Source
+	}
+
 	for i, detail := range draft.Details {
 		detailID, err := uuid.NewV7()
 		if err != nil {
@@ -178,6 +240,7 @@ func (r *GormRepository) CreateProblemVersionFromDraft(
 			InputFormat:      detail.InputFormat,
 			OutputFormat:     detail.OutputFormat,
 			Note:             detail.Note,
+			Signature:        fingerprint.Statements[i].Bytes(),
 		}
 	}
 
@@ -195,6 +258,58 @@ func (r *GormRepository) CreateProblemVersionFromDraft(
 		}
 	}
 
+	for i, solution := range draft.Solutions {
+		solutionID, err := uuid.NewV7()
+		if err != nil {
+			return uuid.Nil, errors.WrapIf(err, "failed to generate new problem version solution ID")
+		}
+
+		problemVersion.Solutions[i] = database.ProblemVersionSolution{
+			SolutionID:       solutionID,
+			ProblemVersionID: problemVersionID,
+			Name:             solution.Name,
+			Language:         solution.Language,
+			ExpectedVerdict:  solution.ExpectedVerdict,
+			IsMain:           solution.IsMain,
+			Source:           solution.Source,
+		}
+	}
+
+	for i, generator := range draft.Generators {
+		generatorID, err := uuid.NewV7()
+		if err != nil {
+			return uuid.Nil, errors.WrapIf(err, "failed to generate new problem version generator ID")
+		}
+
+		problemVersion.Generators[i] = database.ProblemVersionGenerator{
+			GeneratorID:      generatorID,
+			ProblemVersionID: problemVersionID,
+			Name:             generator.Name,
+			Language:         generator.Language,
+			Source:           generator.Source,
+		}
+	}
+
+	// Testcase blobs are immutable, so referencing the same hashes is enough to
+	// freeze the test set for this version.
+	for i, testcase := range draft.Testcases {
+		testcaseID, err := uuid.NewV7()
+		if err != nil {
+			return uuid.Nil, errors.WrapIf(err, "failed to generate new problem version testcase ID")
+		}
+
+		problemVersion.Testcases[i] = database.ProblemVersionTestcase{
+			TestcaseID:       testcaseID,
+			ProblemVersionID: problemVersionID,
+			Position:         i,
+			Name:             testcase.Name,
+			InputHash:        testcase.InputHash,
+			InputSize:        testcase.InputSize,
+			OutputHash:       testcase.OutputHash,
+			OutputSize:       testcase.OutputSize,
+		}
+	}
+
 	if err := db.WithContext(ctx).
 		Create(&problemVersion).Error; err != nil {
 		return uuid.Nil, errors.WrapIf(err, "failed to create problem version")
@@ -202,3 +317,150 @@ func (r *GormRepository) CreateProblemVersionFromDraft(
 
 	return problemVersion.ProblemVersionID, nil
 }
+
+func (r *GormRepository) GetFingerprints(ctx context.Context, excludedProblemID uuid.UUID) ([]problem.Fingerprint, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	type signatureRow struct {
+		ProblemID        uuid.UUID
+		ProblemVersionID uuid.UUID
+		ExampleSignature []byte
+		Signature        []byte
+	}
+
+	// Every other problem is compared by its latest version.
+	var rows []signatureRow
+	if err := db.WithContext(ctx).
+		Table("problem_versions v").
+		Joins("LEFT JOIN problem_version_details d ON d.problem_version_id = v.problem_version_id").
+		Select("v.problem_id, v.problem_version_id, v.example_signature, d.signature").
+		Where("v.problem_id <> ?", excludedProblemID).
+		Where(`v.problem_version_id = (
+			SELECT lpv.problem_version_id
+			FROM problem_versions lpv
+			WHERE lpv.problem_id = v.problem_id
+			ORDER BY lpv.created_at DESC
+			LIMIT 1
+		)`).
+		Scan(&rows).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get problem version signatures")
+	}
+
+	fingerprintByVersionID := make(map[uuid.UUID]*problem.Fingerprint)
+	fingerprints := make([]*problem.Fingerprint, 0)
+	for _, row := range rows {
+		fingerprint, ok := fingerprintByVersionID[row.ProblemVersionID]
+		if !ok {
+			examples, err := similarity.Parse(row.ExampleSignature)
+			if err != nil {
+				return nil, errors.WrapIf(err, "failed to parse example signature")
+			}
+
+			fingerprint = &problem.Fingerprint{
+				ProblemID: row.ProblemID,
+				Examples:  examples,
+			}
+			fingerprintByVersionID[row.ProblemVersionID] = fingerprint
+			fingerprints = append(fingerprints, fingerprint)
+		}
+
+		statement, err := similarity.Parse(row.Signature)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to parse statement signature")
+		}
+
+		if statement != nil {
+			fingerprint.Statements = append(fingerprint.Statements, statement)
+		}
+	}
+
+	result := make([]problem.Fingerprint, 0, len(fingerprints))
+	for _, fingerprint := range fingerprints {
+		result = append(result, *fingerprint)
+	}
+
+	return result, nil
+}
+
+func (r *GormRepository) CreateSimilarities(
+	ctx context.Context,
+	problemID uuid.UUID,
+	problemVersionID uuid.UUID,
+	duplicates []problem.Duplicate,
+	createdAt time.Time,
+) error {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	similarities := make([]database.ProblemSimilarity, 0, len(duplicates))
+	for _, duplicate := range duplicates {
+		similarities = append(similarities, database.ProblemSimilarity{
+			ProblemVersionID:    problemVersionID,
+			SimilarProblemID:    duplicate.ProblemID,
+			ProblemID:           problemID,
+			StatementSimilarity: duplicate.StatementSimilarity,
+			ExampleSimilarity:   duplicate.ExampleSimilarity,
+			CreatedAt:           createdAt,
+		})
+	}
+
+	if err := db.WithContext(ctx).Omit("SimilarProblem").Create(&similarities).Error; err != nil {
+		return errors.WrapIf(err, "failed to create problem similarities")
+	}
+
+	return nil
+}
+
+func (r *GormRepository) QueueJudgeRuns(
+	ctx context.Context,
+	problemID uuid.UUID,
+	problemVersionID uuid.UUID,
+	requestedBy uuid.UUID,
+	createdAt time.Time,
+) error {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var solutionIDs []uuid.UUID
+	if err := db.WithContext(ctx).
+		Model(&database.ProblemVersionSolution{}).
+		Where("problem_version_id = ?", problemVersionID).
+		Pluck("solution_id", &solutionIDs).Error; err != nil {
+		return errors.WrapIf(err, "failed to get problem version solutions")
+	}
+
+	if len(solutionIDs) == 0 {
+		return nil
+	}
+
+	var testcaseCount int64
+	if err := db.WithContext(ctx).
+		Model(&database.ProblemVersionTestcase{}).
+		Where("problem_version_id = ?", problemVersionID).
+		Count(&testcaseCount).Error; err != nil {
+		return errors.WrapIf(err, "failed to count problem version testcases")
+	}
+
+	runs := make([]database.JudgeRun, 0, len(solutionIDs))
+	for _, solutionID := range solutionIDs {
+		judgeRunID, err := uuid.NewV7()
+		if err != nil {
+			return errors.WrapIf(err, "failed to generate new judge run ID")
+		}
+
+		runs = append(runs, database.JudgeRun{
+			JudgeRunID:       judgeRunID,
+			ProblemID:        problemID,
+			ProblemVersionID: problemVersionID,
+			SolutionID:       solutionID,
+			Status:           constant.JudgeRunStatusQueued,
+			TotalCount:       int(testcaseCount),
+			RequestedBy:      uuid.NullUUID{UUID: requestedBy, Valid: true},
+			CreatedAt:        createdAt,
+		})
+	}
+
+	if err := db.WithContext(ctx).Omit("Solution", "TestResults").Create(&runs).Error; err != nil {
+		return errors.WrapIf(err, "failed to create judge runs")
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package uploadtestcases
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"path"
+	"strings"
+
+	"emperror.dev/errors"
+)
+
+// archiveEntryFunc is called for every regular file in an archive. name is the
+// slash-separated path of the file inside the archive.
+type archiveEntryFunc func(name string, r io.Reader) error
+
+var (
+	zipMagic  = []byte("PK\x03\x04")
+	zipEmpty  = []byte("PK\x05\x06")
+	gzipMagic = []byte{0x1f, 0x8b}
+	tarMagic  = []byte("ustar")
+)
+
+// tarMagicOffset is where the "ustar" magic lives in a tar header block.
+const tarMagicOffset = 257
+
+// walkArchive detects whether the file is a zip, tar or gzipped tar archive
+// from its content and calls fn for each regular file inside it.
+func walkArchive(f ArchiveFile, size int64, fn archiveEntryFunc) error {
+	head := make([]byte, tarMagicOffset+len(tarMagic))
+	n, err := f.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return errors.WrapIf(err, "failed to read archive header")
+	}
+	head = head[:n]
+
+	switch {
+	case bytes.HasPrefix(head, zipMagic), bytes.HasPrefix(head, zipEmpty):
+		return walkZip(f, size, fn)
+	case bytes.HasPrefix(head, gzipMagic):
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return errors.WrapIf(err, "failed to rewind archive")
+		}
+
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return errors.Wrap(ErrInvalidArchive, "corrupt gzip stream")
+		}
+		defer gz.Close()
+
+		return walkTar(gz, fn)
+	case len(head) >= tarMagicOffset+len(tarMagic) &&
+		bytes.Equal(head[tarMagicOffset:tarMagicOffset+len(tarMagic)], tarMagic):
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return errors.WrapIf(err, "failed to rewind archive")
+		}
+
+		return walkTar(f, fn)
+	default:
+		return errors.WithStack(ErrUnsupportedArchiveFormat)
+	}
+}
+
+func walkZip(f io.ReaderAt, size int64, fn archiveEntryFunc) error {
+	zr, err := zip.NewReader(f, size)
+	if err != nil {
+		return errors.Wrap(ErrInvalidArchive, "corrupt zip archive")
+	}
+
+	for _, file := range zr.File {
+		if !file.Mode().IsRegular() || isIgnoredEntry(file.Name) {
+			continue
+		}
+
+		if err := func() error {
+			rc, err := file.Open()
+			if err != nil {
+				return errors.Wrapf(ErrInvalidArchive, "cannot read %s", file.Name)
+			}
+			defer rc.Close()
+
+			return fn(path.Clean(file.Name), rc)
+		}(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func walkTar(r io.Reader, fn archiveEntryFunc) error {
+	tr := tar.NewReader(r)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return errors.Wrap(ErrInvalidArchive, "corrupt tar archive")
+		}
+
+		if header.Typeflag != tar.TypeReg || isIgnoredEntry(header.Name) {
+			continue
+		}
+
+		if err := fn(path.Clean(header.Name), tr); err != nil {
+			return err
+		}
+	}
+}
+
+// isIgnoredEntry skips metadata that archivers commonly add next to the actual
+// files, such as macOS resource forks and dotfiles.
+func isIgnoredEntry(name string) bool {
+	for _, part := range strings.Split(path.Clean(name), "/") {
+		if strings.HasPrefix(part, ".") || part == "__MACOSX" {
+			return true
+		}
+	}
+
+	return false
+}
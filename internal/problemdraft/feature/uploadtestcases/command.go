@@ -0,0 +1,20 @@
+package uploadtestcases
+
+import (
+	"io"
+
+	"github.com/google/uuid"
+)
+
+// ArchiveFile is satisfied by multipart.File; zip archives need random access.
+type ArchiveFile interface {
+	io.Reader
+	io.ReaderAt
+	io.Seeker
+}
+
+type Command struct {
+	ProblemDraftID uuid.UUID   `validate:"required"`
+	ArchiveSize    int64       `validate:"gt=0"`
+	Archive        ArchiveFile `validate:"required"`
+}
@@ -0,0 +1,225 @@
+package uploadtestcases
+
+import (
+	"context"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/dto"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+const (
+	MaxArchiveSize   = 256 << 20
+	maxExtractedSize = 1 << 30
+	maxTestcaseCount = 1000
+)
+
+var (
+	ErrNotCreatorOrInactive     = errors.New("not the creator of the problem draft or inactive draft")
+	ErrArchiveTooLarge          = errors.New("testcase archive too large")
+	ErrUnsupportedArchiveFormat = errors.New("unsupported testcase archive format")
+	ErrInvalidArchive           = errors.New("invalid testcase archive")
+)
+
+type Testcase struct {
+	TestcaseID uuid.UUID
+	Name       string
+	Input      contract.BlobInfo
+	Output     contract.BlobInfo
+}
+
+type Repository interface {
+	VerifyActiveProblemDraftCreator(ctx context.Context, problemDraftID uuid.UUID, creatorID uuid.UUID) (bool, error)
+	ReplaceProblemDraftTestcases(
+		ctx context.Context,
+		problemDraftID uuid.UUID,
+		testcases []Testcase,
+		updatedAt time.Time,
+	) error
+}
+
+type CommandHandler struct {
+	repo         Repository
+	blobStore    contract.BlobStore
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+	uowFactory   contract.UnitOfWorkFactory
+	l            logger.Logger
+}
+
+func NewCommandHandler(
+	repo Repository,
+	blobStore contract.BlobStore,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+	uowFactory contract.UnitOfWorkFactory,
+	l logger.Logger,
+) *CommandHandler {
+	return &CommandHandler{
+		repo:         repo,
+		blobStore:    blobStore,
+		validator:    validator,
+		authProvider: authProvider,
+		uowFactory:   uowFactory,
+		l:            l,
+	}
+}
+
+func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Response, error) {
+	if command == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(command); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	user, err := h.authProvider.MustGetUser(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get user from auth provider")
+	}
+
+	if ok, err := h.repo.VerifyActiveProblemDraftCreator(ctx, command.ProblemDraftID, user.UserID); err != nil {
+		return nil, errors.WrapIf(err, "failed to verify active problem draft creator")
+	} else if !ok {
+		return nil, errors.WithStack(ErrNotCreatorOrInactive)
+	}
+
+	if command.ArchiveSize > MaxArchiveSize {
+		return nil, errors.WithStack(ErrArchiveTooLarge)
+	}
+
+	testcases, err := h.extractTestcases(ctx, command.Archive, command.ArchiveSize)
+	if err != nil {
+		return nil, err
+	}
+
+	uow := h.uowFactory.New()
+	if err := uowhelper.Do(ctx, uow, h.l, func(ctx context.Context) error {
+		if err := h.repo.ReplaceProblemDraftTestcases(ctx, command.ProblemDraftID, testcases, time.Now()); err != nil {
+			return errors.WrapIf(err, "failed to replace problem draft testcases")
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	response := &Response{
+		ProblemDraftID: command.ProblemDraftID,
+		TestcaseCount:  len(testcases),
+		Testcases:      make([]dto.ProblemDraftTestcase, len(testcases)),
+	}
+
+	for i, testcase := range testcases {
+		response.TotalSize += testcase.Input.Size + testcase.Output.Size
+		response.Testcases[i] = dto.ProblemDraftTestcase{
+			Name:       testcase.Name,
+			InputHash:  testcase.Input.Hash,
+			InputSize:  testcase.Input.Size,
+			OutputHash: testcase.Output.Hash,
+			OutputSize: testcase.Output.Size,
+		}
+	}
+
+	return response, nil
+}
+
+// extractTestcases stores every file of the archive as a blob and pairs inputs
+// with outputs by name. Blobs are written before the draft is touched; since
+// they are content-addressed, a failed upload only leaves unreferenced blobs.
+func (h *CommandHandler) extractTestcases(ctx context.Context, archive ArchiveFile, size int64) ([]Testcase, error) {
+	type pair struct {
+		input  *contract.BlobInfo
+		output *contract.BlobInfo
+	}
+
+	pairs := make(map[string]*pair)
+	remaining := int64(maxExtractedSize)
+
+	if err := walkArchive(archive, size, func(name string, r io.Reader) error {
+		key, isInput, ok := classifyTestcaseFile(name)
+		if !ok {
+			return errors.Wrapf(ErrInvalidArchive, "unrecognized file %s", name)
+		}
+
+		p, exists := pairs[key]
+		if !exists {
+			if len(pairs) >= maxTestcaseCount {
+				return errors.Wrapf(ErrInvalidArchive, "more than %d testcases", maxTestcaseCount)
+			}
+
+			p = &pair{}
+			pairs[key] = p
+		}
+
+		if (isInput && p.input != nil) || (!isInput && p.output != nil) {
+			return errors.Wrapf(ErrInvalidArchive, "duplicate file for testcase %s", key)
+		}
+
+		info, err := h.blobStore.Put(ctx, io.LimitReader(r, remaining+1))
+		if err != nil {
+			return errors.WrapIf(err, "failed to store testcase file")
+		}
+
+		if int64(info.Size) > remaining {
+			return errors.WithStack(ErrArchiveTooLarge)
+		}
+		remaining -= int64(info.Size)
+
+		if isInput {
+			p.input = &info
+		} else {
+			p.output = &info
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if len(pairs) == 0 {
+		return nil, errors.Wrap(ErrInvalidArchive, "no testcases found")
+	}
+
+	names := make([]string, 0, len(pairs))
+	for name, p := range pairs {
+		if p.input == nil {
+			return nil, errors.Wrapf(ErrInvalidArchive, "missing input for testcase %s", name)
+		} else if p.output == nil {
+			return nil, errors.Wrapf(ErrInvalidArchive, "missing output for testcase %s", name)
+		}
+
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		return naturalLess(names[i], names[j])
+	})
+
+	testcases := make([]Testcase, len(names))
+	for i, name := range names {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to generate new UUID for testcase")
+		}
+
+		testcases[i] = Testcase{
+			TestcaseID: id,
+			Name:       name,
+			Input:      *pairs[name].input,
+			Output:     *pairs[name].output,
+		}
+	}
+
+	return testcases, nil
+}
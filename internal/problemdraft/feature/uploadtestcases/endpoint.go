@@ -0,0 +1,83 @@
+package uploadtestcases
+
+import (
+	"net/http"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/httperror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// multipartOverhead leaves room for multipart boundaries on top of the archive
+// itself when capping the request body.
+const multipartOverhead = 64 << 10
+
+type Endpoint struct {
+	*problemdraft.EndpointParams
+	handler *CommandHandler
+}
+
+func NewEndpoint(params *problemdraft.EndpointParams, handler *CommandHandler) *Endpoint {
+	return &Endpoint{
+		EndpointParams: params,
+		handler:        handler,
+	}
+}
+
+func (e *Endpoint) MapEndpoint() {
+	e.Uploads.Add(e.ProblemDraftsGroup.POST("/:problem_draft_id/testcases", e.handle()))
+}
+
+func (e *Endpoint) handle() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		problemDraftID, err := uuid.Parse(ctx.Param("problem_draft_id"))
+		if err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid problem_draft_id path parameter")
+		}
+
+		req := ctx.Request()
+		req.Body = http.MaxBytesReader(ctx.Response(), req.Body, MaxArchiveSize+multipartOverhead)
+
+		fileHeader, err := ctx.FormFile("archive")
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return httperror.New(http.StatusRequestEntityTooLarge, "The testcase archive is too large")
+		} else if err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid request format")
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid request format")
+		}
+		defer file.Close()
+
+		command := &Command{
+			ProblemDraftID: problemDraftID,
+			ArchiveSize:    fileHeader.Size,
+			Archive:        file,
+		}
+
+		if err := ctx.Validate(command); err != nil {
+			return err
+		}
+
+		response, err := e.handler.Handle(req.Context(), command)
+		if errors.Is(err, ErrNotCreatorOrInactive) {
+			return httperror.New(http.StatusForbidden, "You are not the creator of this problem draft or the draft is inactive")
+		} else if errors.Is(err, ErrArchiveTooLarge) {
+			return httperror.New(http.StatusRequestEntityTooLarge, "The testcase archive is too large")
+		} else if errors.Is(err, ErrUnsupportedArchiveFormat) {
+			return httperror.New(http.StatusUnsupportedMediaType, "The testcase archive must be a zip, tar or tar.gz file")
+		} else if errors.Is(err, ErrInvalidArchive) {
+			return httperror.New(http.StatusUnprocessableEntity, err.Error())
+		} else if err != nil {
+			return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
+		}
+
+		return ctx.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,74 @@
+package uploadtestcases
+
+import (
+	"path"
+	"strings"
+)
+
+// classifyTestcaseFile maps an archive entry to the testcase it belongs to.
+// Both the common "name.in"/"name.out" (or "name.ans") layout and Polygon's
+// "name"/"name.a" layout are understood.
+func classifyTestcaseFile(name string) (key string, isInput bool, ok bool) {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	switch strings.ToLower(ext) {
+	case ".in", "":
+		return base, true, true
+	case ".out", ".ans", ".a":
+		return base, false, true
+	default:
+		return "", false, false
+	}
+}
+
+// naturalLess orders names so that "2" sorts before "10".
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		aDigits, bDigits := isDigit(a[0]), isDigit(b[0])
+
+		if aDigits && bDigits {
+			aNum, aRest := splitDigits(a)
+			bNum, bRest := splitDigits(b)
+
+			aTrimmed := strings.TrimLeft(aNum, "0")
+			bTrimmed := strings.TrimLeft(bNum, "0")
+
+			if len(aTrimmed) != len(bTrimmed) {
+				return len(aTrimmed) < len(bTrimmed)
+			}
+
+			if aTrimmed != bTrimmed {
+				return aTrimmed < bTrimmed
+			}
+
+			if len(aNum) != len(bNum) {
+				return len(aNum) < len(bNum)
+			}
+
+			a, b = aRest, bRest
+			continue
+		}
+
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+
+		a, b = a[1:], b[1:]
+	}
+
+	return len(a) < len(b)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func splitDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+
+	return s[:i], s[i:]
+}
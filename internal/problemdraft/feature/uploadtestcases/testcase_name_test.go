@@ -0,0 +1,47 @@
+package uploadtestcases
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestClassifyTestcaseFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		wantKey     string
+		wantIsInput bool
+		wantOK      bool
+	}{
+		{name: "in file", file: "tests/1.in", wantKey: "tests/1", wantIsInput: true, wantOK: true},
+		{name: "out file", file: "1.out", wantKey: "1", wantOK: true},
+		{name: "ans file", file: "1.ans", wantKey: "1", wantOK: true},
+		{name: "polygon input", file: "tests/01", wantKey: "tests/01", wantIsInput: true, wantOK: true},
+		{name: "polygon answer", file: "tests/01.a", wantKey: "tests/01", wantOK: true},
+		{name: "unknown extension", file: "README.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, isInput, ok := classifyTestcaseFile(tt.file)
+			if key != tt.wantKey || isInput != tt.wantIsInput || ok != tt.wantOK {
+				t.Errorf("Expected (%q, %v, %v), got (%q, %v, %v)",
+					tt.wantKey, tt.wantIsInput, tt.wantOK, key, isInput, ok)
+			}
+		})
+	}
+}
+
+func TestNaturalLess(t *testing.T) {
+	names := []string{"10", "2", "a10", "a2", "1", "01b", "a"}
+	sort.Slice(names, func(i, j int) bool {
+		return naturalLess(names[i], names[j])
+	})
+
+	expected := []string{"1", "01b", "2", "10", "a", "a2", "a10"}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, names)
+		}
+	}
+}
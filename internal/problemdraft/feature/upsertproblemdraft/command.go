@@ -17,9 +17,56 @@ type CommandExample struct {
 	Output string `json:"output"`
 }
 
+type CommandSolution struct {
+	Name            string `json:"name"             validate:"required,max=128"`
+	Language        string `json:"language"         validate:"required,language"`
+	ExpectedVerdict string `json:"expected_verdict" validate:"required,expected_verdict"`
+	IsMain          bool   `json:"is_main"`
+	Source          string `json:"source"           validate:"required,max=65536"`
+}
+
+// CommandChecker selects how outputs are compared. Custom checkers and
+// interactors are testlib-compatible C++ programs.
+type CommandChecker struct {
+	Type     string  `json:"type"     validate:"required,checker_type"`
+	Epsilon  float64 `json:"epsilon"  validate:"omitempty,gt=0,lt=1"`
+	Language string  `json:"language" validate:"omitempty,testlib_language"`
+	Source   string  `json:"source"   validate:"omitempty,max=65536"`
+}
+
+type CommandInteractor struct {
+	Language string `json:"language" validate:"required,testlib_language"`
+	Source   string `json:"source"   validate:"required,max=65536"`
+}
+
+type CommandValidator struct {
+	Language string `json:"language" validate:"required,testlib_language"`
+	Source   string `json:"source"   validate:"required,max=65536"`
+}
+
+// CommandGenerator is a program that prints a test input. It is invoked by
+// name from the generator script.
+type CommandGenerator struct {
+	Name     string `json:"name"     validate:"required,max=64"`
+	Language string `json:"language" validate:"required,language"`
+	Source   string `json:"source"   validate:"required,max=65536"`
+}
+
 type Command struct {
-	ProblemDraftID      uuid.NullUUID    `json:"problem_draft_id"`
-	ProblemDifficultyID uuid.NullUUID    `json:"problem_difficulty_id"`
-	Details             []CommandDetail  `json:"details"               validate:"required"`
-	Examples            []CommandExample `json:"examples"`
+	ProblemDraftID      uuid.NullUUID      `json:"problem_draft_id"`
+	ProblemDifficultyID uuid.NullUUID      `json:"problem_difficulty_id"`
+	TargetContestID     uuid.NullUUID      `json:"target_contest_id"`
+	Details             []CommandDetail    `json:"details"               validate:"required"`
+	Examples            []CommandExample   `json:"examples"`
+	Solutions           []CommandSolution  `json:"solutions"             validate:"dive"`
+	TimeLimitMs         uint               `json:"time_limit_ms"         validate:"omitempty,min=100,max=60000"`
+	MemoryLimitMb       uint               `json:"memory_limit_mb"       validate:"omitempty,min=16,max=4096"`
+	IsInteractive       bool               `json:"is_interactive"`
+	InputFile           string             `json:"input_file"            validate:"omitempty,max=64"`
+	OutputFile          string             `json:"output_file"           validate:"omitempty,max=64"`
+	Checker             *CommandChecker    `json:"checker"`
+	Interactor          *CommandInteractor `json:"interactor"`
+	Validator           *CommandValidator  `json:"validator"`
+	Generators          []CommandGenerator `json:"generators"            validate:"dive"`
+	GeneratorScript     string             `json:"generator_script"      validate:"max=65536"`
 }
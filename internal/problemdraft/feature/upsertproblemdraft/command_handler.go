@@ -2,10 +2,14 @@ package upsertproblemdraft
 
 import (
 	"context"
+	"strings"
 	"time"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/contest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/genscript"
 
 	"emperror.dev/errors"
 	"github.com/go-playground/validator"
@@ -15,11 +19,22 @@ import (
 var (
 	ErrInvalidProblemDraftID      = errors.New("invalid problem draft ID")
 	ErrInvalidProblemDifficultyID = errors.New("invalid problem difficulty ID")
+	ErrInvalidTargetContestID     = errors.New("invalid target contest ID")
+	ErrTargetContestClosed        = errors.New("target contest no longer accepts problems")
 	ErrNotCreatorOrInactive       = errors.New("not the creator of the problem draft or inactive draft")
+	ErrInvalidIOFileName          = errors.New("invalid input/output file name")
+	ErrInteractiveFileIO          = errors.New("interactive problems cannot use file input/output")
+	ErrInvalidMainSolution        = errors.New("exactly one main solution expected to be accepted is required")
+	ErrDuplicateSolutionName      = errors.New("duplicate solution name")
+	ErrInvalidChecker             = errors.New("custom checkers require a language and source, built-in checkers take neither")
+	ErrUnexpectedInteractor       = errors.New("only interactive problems can have an interactor")
+	ErrInvalidGeneratorName       = errors.New("invalid or duplicate generator name")
 )
 
 type Repository interface {
 	VerifyActiveProblemDraftCreator(ctx context.Context, problemDraftID uuid.UUID, creatorID uuid.UUID) (bool, error)
+	GetTargetContestID(ctx context.Context, problemDraftID uuid.UUID) (uuid.NullUUID, error)
+	GetContestLifecycle(ctx context.Context, contestID uuid.UUID) (constant.ContestPhase, time.Time, error)
 
 	UpsertProblemDraft(
 		ctx context.Context,
@@ -29,6 +44,8 @@ type Repository interface {
 		creatorID uuid.UUID,
 		exampleIDs []uuid.UUID,
 		detailIDs []uuid.UUID,
+		solutionIDs []uuid.UUID,
+		generatorIDs []uuid.UUID,
 	) error
 }
 
@@ -59,6 +76,22 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Respons
 		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
 	}
 
+	if err := normalizeLimits(command); err != nil {
+		return nil, err
+	}
+
+	if err := normalizeChecker(command); err != nil {
+		return nil, err
+	}
+
+	if err := validateSolutions(command.Solutions); err != nil {
+		return nil, err
+	}
+
+	if err := validateGenerators(command.Generators, command.GeneratorScript); err != nil {
+		return nil, err
+	}
+
 	user, err := h.authProvider.MustGetUser(ctx)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to get user from auth provider")
@@ -83,6 +116,12 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Respons
 		return nil, errors.WithStack(ErrNotCreatorOrInactive)
 	}
 
+	if command.TargetContestID.Valid {
+		if err := h.checkTargetContest(ctx, command.ProblemDraftID.UUID, command.TargetContestID.UUID, createdAt != nil); err != nil {
+			return nil, err
+		}
+	}
+
 	exampleIDs := make([]uuid.UUID, len(command.Examples))
 	for i := range command.Examples {
 		id, err := uuid.NewV7()
@@ -103,7 +142,37 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Respons
 		detailIDs[i] = id
 	}
 
-	if err := h.repo.UpsertProblemDraft(ctx, command, createdAt, updatedAt, user.UserID, exampleIDs, detailIDs); err != nil {
+	solutionIDs := make([]uuid.UUID, len(command.Solutions))
+	for i := range command.Solutions {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to generate new UUID for solution")
+		}
+
+		solutionIDs[i] = id
+	}
+
+	generatorIDs := make([]uuid.UUID, len(command.Generators))
+	for i := range command.Generators {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to generate new UUID for generator")
+		}
+
+		generatorIDs[i] = id
+	}
+
+	if err := h.repo.UpsertProblemDraft(
+		ctx,
+		command,
+		createdAt,
+		updatedAt,
+		user.UserID,
+		exampleIDs,
+		detailIDs,
+		solutionIDs,
+		generatorIDs,
+	); err != nil {
 		return nil, errors.WrapIf(err, "failed to upsert problem draft in repository")
 	}
 
@@ -111,3 +180,164 @@ func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Respons
 		ProblemDraftID: command.ProblemDraftID.UUID,
 	}, nil
 }
+
+// checkTargetContest only lets a draft newly target a contest that still
+// accepts problems, while a draft keeps its target if the contest has been
+// frozen since.
+func (h *CommandHandler) checkTargetContest(
+	ctx context.Context,
+	problemDraftID uuid.UUID,
+	targetContestID uuid.UUID,
+	isNew bool,
+) error {
+	if !isNew {
+		current, err := h.repo.GetTargetContestID(ctx, problemDraftID)
+		if err != nil {
+			return errors.WrapIf(err, "failed to get current target contest")
+		}
+
+		if current.Valid && current.UUID == targetContestID {
+			return nil
+		}
+	}
+
+	phase, deadline, err := h.repo.GetContestLifecycle(ctx, targetContestID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get target contest")
+	}
+
+	if contest.IsProblemSetLocked(phase, deadline, time.Now()) {
+		return errors.WithStack(ErrTargetContestClosed)
+	}
+
+	return nil
+}
+
+// normalizeLimits fills in default resource limits and checks the I/O settings
+// that struct tags cannot express. Contest-specific maximums are enforced when
+// the problem is submitted to or assigned to a contest.
+func normalizeLimits(command *Command) error {
+	if command.TimeLimitMs == 0 {
+		command.TimeLimitMs = constant.DefaultTimeLimitMs
+	}
+
+	if command.MemoryLimitMb == 0 {
+		command.MemoryLimitMb = constant.DefaultMemoryLimitMb
+	}
+
+	if !isValidIOFileName(command.InputFile) || !isValidIOFileName(command.OutputFile) {
+		return errors.WithStack(ErrInvalidIOFileName)
+	}
+
+	if command.IsInteractive && (command.InputFile != "" || command.OutputFile != "") {
+		return errors.WithStack(ErrInteractiveFileIO)
+	}
+
+	return nil
+}
+
+// normalizeChecker defaults to the token checker and drops settings that the
+// selected checker does not use. A missing interactor is tolerated while the
+// draft is being written and rejected on submission.
+func normalizeChecker(command *Command) error {
+	if command.Checker == nil {
+		command.Checker = &CommandChecker{Type: constant.CheckerTypeToken}
+	}
+
+	checker := command.Checker
+	isCustom := checker.Type == constant.CheckerTypeCustom
+	if isCustom != (checker.Source != "") || isCustom != (checker.Language != "") {
+		return errors.WithStack(ErrInvalidChecker)
+	}
+
+	if checker.Type != constant.CheckerTypeFloat {
+		checker.Epsilon = 0
+	} else if checker.Epsilon == 0 {
+		checker.Epsilon = constant.DefaultCheckerEpsilon
+	}
+
+	if command.Interactor != nil && !command.IsInteractive {
+		return errors.WithStack(ErrUnexpectedInteractor)
+	}
+
+	return nil
+}
+
+// validateSolutions allows a draft without solutions while it is being written,
+// but once solutions are attached one of them must be the main correct one.
+func validateSolutions(solutions []CommandSolution) error {
+	if len(solutions) == 0 {
+		return nil
+	}
+
+	names := make(map[string]struct{}, len(solutions))
+	mainCount := 0
+
+	for _, solution := range solutions {
+		if _, ok := names[solution.Name]; ok {
+			return errors.WithStack(ErrDuplicateSolutionName)
+		}
+		names[solution.Name] = struct{}{}
+
+		if solution.IsMain {
+			if constant.Verdict(solution.ExpectedVerdict) != constant.VerdictAccepted {
+				return errors.WithStack(ErrInvalidMainSolution)
+			}
+
+			mainCount++
+		}
+	}
+
+	if mainCount != 1 {
+		return errors.WithStack(ErrInvalidMainSolution)
+	}
+
+	return nil
+}
+
+// validateGenerators checks that the generator script parses and only invokes
+// generators attached to the draft. Whether the generators actually work only
+// shows when the tests are generated.
+func validateGenerators(generators []CommandGenerator, script string) error {
+	names := make(map[string]struct{}, len(generators))
+	for _, generator := range generators {
+		if _, ok := names[generator.Name]; ok || !genscript.IsValidName(generator.Name) {
+			return errors.WithStack(ErrInvalidGeneratorName)
+		}
+
+		names[generator.Name] = struct{}{}
+	}
+
+	commands, err := genscript.Parse(script)
+	if err != nil {
+		return err
+	}
+
+	for _, command := range commands {
+		if _, ok := names[command.Generator]; !ok {
+			return errors.Wrapf(genscript.ErrInvalidScript, "line %d: unknown generator %s", command.Line, command.Generator)
+		}
+	}
+
+	return nil
+}
+
+// isValidIOFileName accepts an empty name (standard I/O) or a plain file name
+// without any path components.
+func isValidIOFileName(name string) bool {
+	if name == "" {
+		return true
+	}
+
+	if strings.HasPrefix(name, ".") {
+		return false
+	}
+
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '_' || c == '-') {
+			return false
+		}
+	}
+
+	return true
+}
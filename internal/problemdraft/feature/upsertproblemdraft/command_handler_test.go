@@ -0,0 +1,83 @@
+package upsertproblemdraft
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+type fakeRepository struct {
+	Repository
+
+	targetContestID uuid.NullUUID
+	phase           constant.ContestPhase
+	deadline        time.Time
+}
+
+func (r *fakeRepository) GetTargetContestID(context.Context, uuid.UUID) (uuid.NullUUID, error) {
+	return r.targetContestID, nil
+}
+
+func (r *fakeRepository) GetContestLifecycle(context.Context, uuid.UUID) (constant.ContestPhase, time.Time, error) {
+	return r.phase, r.deadline, nil
+}
+
+func TestCheckTargetContest(t *testing.T) {
+	contestID := uuid.New()
+	open := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		isNew   bool
+		current uuid.NullUUID
+		phase   constant.ContestPhase
+		wantErr error
+	}{
+		{name: "new draft, open contest", isNew: true, phase: constant.ContestPhaseProposal},
+		{name: "new draft, frozen contest", isNew: true, phase: constant.ContestPhaseFrozen, wantErr: ErrTargetContestClosed},
+		{
+			name:    "kept target, frozen since",
+			current: uuid.NullUUID{UUID: contestID, Valid: true},
+			phase:   constant.ContestPhaseFrozen,
+		},
+		{
+			name:    "new target, frozen contest",
+			current: uuid.NullUUID{UUID: uuid.New(), Valid: true},
+			phase:   constant.ContestPhaseFrozen,
+			wantErr: ErrTargetContestClosed,
+		},
+		{name: "first target, open contest", phase: constant.ContestPhaseProblemSelection},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewCommandHandler(&fakeRepository{
+				targetContestID: tt.current,
+				phase:           tt.phase,
+				deadline:        open,
+			}, nil, nil)
+
+			err := h.checkTargetContest(context.Background(), uuid.New(), contestID, tt.isNew)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkTargetContest() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckTargetContestPastDeadline(t *testing.T) {
+	h := NewCommandHandler(&fakeRepository{
+		phase:    constant.ContestPhaseProposal,
+		deadline: time.Now().Add(-time.Hour),
+	}, nil, nil)
+
+	err := h.checkTargetContest(context.Background(), uuid.New(), uuid.New(), true)
+	if !errors.Is(err, ErrTargetContestClosed) {
+		t.Errorf("checkTargetContest() = %v, want %v", err, ErrTargetContestClosed)
+	}
+}
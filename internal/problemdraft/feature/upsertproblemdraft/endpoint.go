@@ -3,6 +3,7 @@ package upsertproblemdraft
 import (
 	"net/http"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/genscript"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/httperror"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft"
 
@@ -43,6 +44,26 @@ func (e *Endpoint) handle() echo.HandlerFunc {
 				WithInternal(err)
 		} else if errors.Is(err, ErrInvalidProblemDifficultyID) {
 			return httperror.New(http.StatusUnprocessableEntity, "The problem difficulty ID you provided doesn't correspond to any valid problem difficulties").WithInternal(err)
+		} else if errors.Is(err, ErrInvalidTargetContestID) {
+			return httperror.New(http.StatusUnprocessableEntity, "The target contest you provided does not exist").WithInternal(err)
+		} else if errors.Is(err, ErrTargetContestClosed) {
+			return httperror.New(http.StatusUnprocessableEntity, "The target contest is frozen or past its deadline").WithInternal(err)
+		} else if errors.Is(err, ErrInvalidIOFileName) {
+			return httperror.New(http.StatusUnprocessableEntity, "Input and output file names may only contain letters, digits, '.', '_' and '-'")
+		} else if errors.Is(err, ErrInteractiveFileIO) {
+			return httperror.New(http.StatusUnprocessableEntity, "Interactive problems must use standard input and output")
+		} else if errors.Is(err, ErrInvalidMainSolution) {
+			return httperror.New(http.StatusUnprocessableEntity, "Exactly one solution must be marked as main, and it must be expected to be accepted")
+		} else if errors.Is(err, ErrDuplicateSolutionName) {
+			return httperror.New(http.StatusUnprocessableEntity, "Solution names must be unique within a problem draft")
+		} else if errors.Is(err, ErrInvalidChecker) {
+			return httperror.New(http.StatusUnprocessableEntity, "Custom checkers require a language and source code, built-in checkers take neither")
+		} else if errors.Is(err, ErrUnexpectedInteractor) {
+			return httperror.New(http.StatusUnprocessableEntity, "Only interactive problems can have an interactor")
+		} else if errors.Is(err, ErrInvalidGeneratorName) {
+			return httperror.New(http.StatusUnprocessableEntity, "Generator names must be unique and may only contain letters, digits, '.', '_' and '-'")
+		} else if errors.Is(err, genscript.ErrInvalidScript) {
+			return httperror.New(http.StatusUnprocessableEntity, err.Error())
 		} else if errors.Is(err, ErrNotCreatorOrInactive) {
 			return httperror.New(http.StatusForbidden, "You are not the creator of this problem draft or the draft is inactive").WithInternal(err)
 		} else if err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
 	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
 
 	"emperror.dev/errors"
@@ -30,6 +31,8 @@ func (r *GormRepository) UpsertProblemDraft(
 	creatorID uuid.UUID,
 	exampleIDs []uuid.UUID,
 	detailIDs []uuid.UUID,
+	solutionIDs []uuid.UUID,
+	generatorIDs []uuid.UUID,
 ) error {
 	db := database.GetDBFromContext(ctx, r.db)
 
@@ -40,13 +43,36 @@ func (r *GormRepository) UpsertProblemDraft(
 	problemDraftModel := database.ProblemDraft{
 		ProblemDraftID:      command.ProblemDraftID.UUID,
 		ProblemDifficultyID: command.ProblemDifficultyID,
+		TargetContestID:     command.TargetContestID,
 		CreatorID:           creatorID,
 		Examples:            make([]database.ProblemDraftExample, len(command.Examples)),
 		Details:             make([]database.ProblemDraftDetail, len(command.Details)),
+		Solutions:           make([]database.ProblemDraftSolution, len(command.Solutions)),
+		Generators:          make([]database.ProblemDraftGenerator, len(command.Generators)),
+		TimeLimitMs:         command.TimeLimitMs,
+		MemoryLimitMb:       command.MemoryLimitMb,
+		IsInteractive:       command.IsInteractive,
+		InputFile:           command.InputFile,
+		OutputFile:          command.OutputFile,
+		CheckerType:         command.Checker.Type,
+		CheckerEpsilon:      command.Checker.Epsilon,
+		CheckerLanguage:     command.Checker.Language,
+		CheckerSource:       command.Checker.Source,
+		GeneratorScript:     command.GeneratorScript,
 		UpdatedAt:           updatedAt,
 		IsActive:            true,
 	}
 
+	if command.Interactor != nil {
+		problemDraftModel.InteractorLanguage = command.Interactor.Language
+		problemDraftModel.InteractorSource = command.Interactor.Source
+	}
+
+	if command.Validator != nil {
+		problemDraftModel.ValidatorLanguage = command.Validator.Language
+		problemDraftModel.ValidatorSource = command.Validator.Source
+	}
+
 	if createdAt != nil {
 		problemDraftModel.CreatedAt = *createdAt
 	}
@@ -74,6 +100,28 @@ func (r *GormRepository) UpsertProblemDraft(
 		}
 	}
 
+	for i, solution := range command.Solutions {
+		problemDraftModel.Solutions[i] = database.ProblemDraftSolution{
+			SolutionID:      solutionIDs[i],
+			ProblemDraftID:  problemDraftModel.ProblemDraftID,
+			Name:            solution.Name,
+			Language:        solution.Language,
+			ExpectedVerdict: solution.ExpectedVerdict,
+			IsMain:          solution.IsMain,
+			Source:          solution.Source,
+		}
+	}
+
+	for i, generator := range command.Generators {
+		problemDraftModel.Generators[i] = database.ProblemDraftGenerator{
+			GeneratorID:    generatorIDs[i],
+			ProblemDraftID: problemDraftModel.ProblemDraftID,
+			Name:           generator.Name,
+			Language:       generator.Language,
+			Source:         generator.Source,
+		}
+	}
+
 	var problemDifficulty database.ProblemDifficulty
 
 	if err := db.Transaction(func(tx *gorm.DB) error {
@@ -99,9 +147,35 @@ func (r *GormRepository) UpsertProblemDraft(
 			return errors.WrapIf(err, "failed to delete old problem draft details")
 		}
 
+		if err := tx.WithContext(ctx).Where("problem_draft_id = ?", command.ProblemDraftID.UUID).Delete(&database.ProblemDraftSolution{}).Error; err != nil {
+			return errors.WrapIf(err, "failed to delete old problem draft solutions")
+		}
+
+		if err := tx.WithContext(ctx).Where("problem_draft_id = ?", command.ProblemDraftID.UUID).Delete(&database.ProblemDraftGenerator{}).Error; err != nil {
+			return errors.WrapIf(err, "failed to delete old problem draft generators")
+		}
+
 		if err := tx.WithContext(ctx).Clauses(clause.OnConflict{
-			Columns:   []clause.Column{{Name: "problem_draft_id"}},
-			DoUpdates: clause.AssignmentColumns([]string{"problem_difficulty_id", "updated_at"}),
+			Columns: []clause.Column{{Name: "problem_draft_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"problem_difficulty_id",
+				"target_contest_id",
+				"time_limit_ms",
+				"memory_limit_mb",
+				"is_interactive",
+				"input_file",
+				"output_file",
+				"checker_type",
+				"checker_epsilon",
+				"checker_language",
+				"checker_source",
+				"interactor_language",
+				"interactor_source",
+				"validator_language",
+				"validator_source",
+				"generator_script",
+				"updated_at",
+			}),
 		}).Create(&problemDraftModel).Error; err != nil {
 			return errors.WrapIf(err, "failed to upsert problem draft")
 		}
@@ -131,3 +205,42 @@ func (r *GormRepository) VerifyActiveProblemDraftCreator(
 
 	return count > 0, nil
 }
+
+func (r *GormRepository) GetTargetContestID(ctx context.Context, problemDraftID uuid.UUID) (uuid.NullUUID, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var draft database.ProblemDraft
+	if err := db.WithContext(ctx).
+		Select("target_contest_id").
+		Where("problem_draft_id = ?", problemDraftID).
+		First(&draft).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return uuid.NullUUID{}, errors.WithStack(ErrInvalidProblemDraftID)
+		}
+
+		return uuid.NullUUID{}, errors.WrapIf(err, "failed to get problem draft target contest")
+	}
+
+	return draft.TargetContestID, nil
+}
+
+func (r *GormRepository) GetContestLifecycle(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (constant.ContestPhase, time.Time, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var contest database.Contest
+	if err := db.WithContext(ctx).
+		Select("phase", "deadline_datetime").
+		Where("contest_id = ?", contestID).
+		First(&contest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", time.Time{}, errors.WithStack(ErrInvalidTargetContestID)
+		}
+
+		return "", time.Time{}, errors.WrapIf(err, "failed to get contest lifecycle")
+	}
+
+	return constant.FromStringToContestPhase(contest.Phase), contest.DeadlineDatetime, nil
+}
@@ -0,0 +1,60 @@
+package upsertproblemdraft
+
+import (
+	"testing"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+
+	"emperror.dev/errors"
+)
+
+func TestNormalizeLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		command Command
+		wantErr error
+	}{
+		{name: "standard I/O", command: Command{}},
+		{name: "file I/O", command: Command{InputFile: "a.in", OutputFile: "a_1-b.out"}},
+		{name: "path in input file", command: Command{InputFile: "../a.in"}, wantErr: ErrInvalidIOFileName},
+		{name: "hidden output file", command: Command{OutputFile: ".out"}, wantErr: ErrInvalidIOFileName},
+		{name: "space in input file", command: Command{InputFile: "a b.in"}, wantErr: ErrInvalidIOFileName},
+		{name: "interactive", command: Command{IsInteractive: true}},
+		{
+			name:    "interactive with file I/O",
+			command: Command{IsInteractive: true, InputFile: "a.in"},
+			wantErr: ErrInteractiveFileIO,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command := tt.command
+
+			err := normalizeLimits(&command)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("normalizeLimits() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNormalizeLimitsDefaults(t *testing.T) {
+	command := Command{}
+	if err := normalizeLimits(&command); err != nil {
+		t.Fatalf("normalizeLimits() failed: %v", err)
+	}
+
+	if command.TimeLimitMs != constant.DefaultTimeLimitMs || command.MemoryLimitMb != constant.DefaultMemoryLimitMb {
+		t.Errorf("limits = %d ms, %d MB, want the defaults", command.TimeLimitMs, command.MemoryLimitMb)
+	}
+
+	command = Command{TimeLimitMs: 2500, MemoryLimitMb: 512}
+	if err := normalizeLimits(&command); err != nil {
+		t.Fatalf("normalizeLimits() failed: %v", err)
+	}
+
+	if command.TimeLimitMs != 2500 || command.MemoryLimitMb != 512 {
+		t.Errorf("limits = %d ms, %d MB, want them kept", command.TimeLimitMs, command.MemoryLimitMb)
+	}
+}
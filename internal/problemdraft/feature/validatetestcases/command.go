@@ -0,0 +1,7 @@
+package validatetestcases
+
+import "github.com/google/uuid"
+
+type Command struct {
+	ProblemDraftID uuid.UUID `validate:"required"`
+}
@@ -0,0 +1,96 @@
+package validatetestcases
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/dto"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrProblemDraftNotFound = errors.New("problem draft not found")
+	ErrNotCreator           = errors.New("not the creator of the problem draft")
+	ErrNoValidator          = errors.New("problem draft has no validator")
+)
+
+type Repository interface {
+	GetProblemDraft(ctx context.Context, problemDraftID uuid.UUID) (*dto.ProblemDraft, error)
+}
+
+type CommandHandler struct {
+	repo         Repository
+	toolchain    contract.TestToolchain
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+}
+
+func NewCommandHandler(
+	repo Repository,
+	toolchain contract.TestToolchain,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+) *CommandHandler {
+	return &CommandHandler{
+		repo:         repo,
+		toolchain:    toolchain,
+		validator:    validator,
+		authProvider: authProvider,
+	}
+}
+
+// Handle runs the validator of the draft on its examples and testcases, the
+// same check that submitting the draft performs.
+func (h *CommandHandler) Handle(ctx context.Context, command *Command) (*Response, error) {
+	if command == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(command); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	user, err := h.authProvider.MustGetUser(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get user from auth provider")
+	}
+
+	problemDraft, err := h.repo.GetProblemDraft(ctx, command.ProblemDraftID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get problem draft")
+	}
+
+	if problemDraft.CreatorID != user.UserID {
+		return nil, errors.WithStack(ErrNotCreator)
+	}
+
+	if problemDraft.Validator == nil {
+		return nil, errors.WithStack(ErrNoValidator)
+	}
+
+	results, err := h.toolchain.ValidateInputs(ctx, contract.Program{
+		Language: problemDraft.Validator.Language,
+		Source:   problemDraft.Validator.Source,
+	}, dto.ValidationInputs(problemDraft))
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to validate tests")
+	}
+
+	response := &Response{
+		ProblemDraftID: command.ProblemDraftID,
+		Valid:          true,
+		Results:        dto.FromValidationResults(problemDraft, results),
+	}
+
+	for _, result := range response.Results {
+		if !result.Valid {
+			response.Valid = false
+		}
+	}
+
+	return response, nil
+}
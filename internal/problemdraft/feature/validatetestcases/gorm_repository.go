@@ -0,0 +1,44 @@
+package validatetestcases
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/dto"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{
+		db: db,
+	}
+}
+
+func (r *GormRepository) GetProblemDraft(ctx context.Context, problemDraftID uuid.UUID) (*dto.ProblemDraft, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var problemDraft database.ProblemDraft
+	if err := db.WithContext(ctx).
+		Preload("Examples").
+		Preload("Testcases", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Where("problem_draft_id = ?", problemDraftID).
+		First(&problemDraft).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithStack(ErrProblemDraftNotFound)
+		}
+
+		return nil, errors.WrapIf(err, "failed to get problem draft")
+	}
+
+	response := dto.FromGormProblemDraft(problemDraft, dto.FromGormProblemDifficulty(problemDraft.ProblemDifficulty))
+	return &response, nil
+}
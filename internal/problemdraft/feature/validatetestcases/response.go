@@ -0,0 +1,13 @@
+package validatetestcases
+
+import (
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/problemdraft/dto"
+
+	"github.com/google/uuid"
+)
+
+type Response struct {
+	ProblemDraftID uuid.UUID                  `json:"problem_draft_id"`
+	Valid          bool                       `json:"valid"`
+	Results        []dto.TestValidationResult `json:"results"`
+}
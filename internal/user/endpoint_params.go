@@ -7,8 +7,10 @@ import (
 )
 
 type EndpointParams struct {
-	UsersGroup *echo.Group
-	AuthGroup  *echo.Group
+	UsersGroup       *echo.Group
+	AuthGroup        *echo.Group
+	RolesGroup       *echo.Group
+	PermissionsGroup *echo.Group
 }
 
 func NewEndpointParams(
@@ -16,9 +18,13 @@ func NewEndpointParams(
 ) *EndpointParams {
 	users := v1Group.Group.Group("/users")
 	auth := v1Group.Group.Group("/auth")
+	roles := v1Group.Group.Group("/roles")
+	permissions := v1Group.Group.Group("/permissions")
 
 	return &EndpointParams{
-		UsersGroup: users,
-		AuthGroup:  auth,
+		UsersGroup:       users,
+		AuthGroup:        auth,
+		RolesGroup:       roles,
+		PermissionsGroup: permissions,
 	}
 }
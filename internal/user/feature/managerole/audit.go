@@ -0,0 +1,70 @@
+package managerole
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	auditActionRoleCreate = "role.create"
+	auditActionRoleUpdate = "role.update"
+	auditActionRoleDelete = "role.delete"
+
+	auditTargetRole = "role"
+)
+
+type auditRole struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type auditRoleUpdate struct {
+	Before auditRole `json:"before"`
+	After  auditRole `json:"after"`
+}
+
+func newAuditLogEntry(actorID uuid.UUID, action string, roleID uuid.UUID, details any) (database.AuditLogEntry, error) {
+	entryID, err := uuid.NewV7()
+	if err != nil {
+		return database.AuditLogEntry{}, errors.WrapIf(err, "failed to generate audit log entry ID")
+	}
+
+	detailsJSON, err := json.Marshal(details)
+	if err != nil {
+		return database.AuditLogEntry{}, errors.WrapIf(err, "failed to marshal audit log details")
+	}
+
+	return database.AuditLogEntry{
+		AuditLogEntryID: entryID,
+		ActorID:         actorID,
+		Action:          action,
+		TargetType:      auditTargetRole,
+		TargetID:        roleID,
+		Details:         string(detailsJSON),
+		CreatedAt:       time.Now(),
+	}, nil
+}
+
+// normalizePermissions trims, deduplicates and sorts permission names.
+func normalizePermissions(names []string) []string {
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || slices.Contains(normalized, name) {
+			continue
+		}
+
+		normalized = append(normalized, name)
+	}
+
+	slices.Sort(normalized)
+	return normalized
+}
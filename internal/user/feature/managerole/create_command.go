@@ -0,0 +1,7 @@
+package managerole
+
+type CreateCommand struct {
+	Name        string   `json:"name" validate:"required,max=64"`
+	Description string   `json:"description" validate:"max=255"`
+	Permissions []string `json:"permissions" validate:"dive,required"`
+}
@@ -0,0 +1,126 @@
+package managerole
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+type CreateCommandHandler struct {
+	repo         Repository
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+	uowFactory   contract.UnitOfWorkFactory
+	l            logger.Logger
+}
+
+func NewCreateCommandHandler(
+	repo Repository,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+	uowFactory contract.UnitOfWorkFactory,
+	l logger.Logger,
+) *CreateCommandHandler {
+	return &CreateCommandHandler{
+		repo:         repo,
+		validator:    validator,
+		authProvider: authProvider,
+		uowFactory:   uowFactory,
+		l:            l,
+	}
+}
+
+// Handle creates a role with the given permissions. Super admin roles are
+// only created by seeding, so roles created here are never super admin.
+func (h *CreateCommandHandler) Handle(ctx context.Context, command *CreateCommand) (*RoleResponse, error) {
+	if command == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(command); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	can, err := h.authProvider.Can(ctx, constant.PermissionRoleManageAny)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to check permission for creating role")
+	}
+
+	if !can {
+		return nil, customerror.NewNoPermissionError(constant.PermissionRoleManageAny)
+	}
+
+	currentUser, err := h.authProvider.MustGetUser(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get current user")
+	}
+
+	name := strings.TrimSpace(command.Name)
+	description := strings.TrimSpace(command.Description)
+	permissionNames := normalizePermissions(command.Permissions)
+
+	uow := h.uowFactory.New()
+	return uowhelper.DoWithResult(ctx, uow, h.l, func(ctx context.Context) (*RoleResponse, error) {
+		exists, err := h.repo.ExistsRoleName(ctx, name, uuid.Nil)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to check role name")
+		}
+
+		if exists {
+			return nil, errors.WithStack(ErrRoleNameAlreadyExists)
+		}
+
+		permissions, err := h.repo.GetPermissionsByNames(ctx, permissionNames)
+		if err != nil {
+			return nil, err
+		}
+
+		roleID, err := uuid.NewV7()
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to generate role ID")
+		}
+
+		if err := h.repo.CreateRole(ctx, database.Role{
+			RoleID:      roleID,
+			Name:        name,
+			Description: description,
+			Permissions: &permissions,
+			CreatedAt:   time.Now(),
+		}); err != nil {
+			return nil, errors.WrapIf(err, "failed to create role")
+		}
+
+		entry, err := newAuditLogEntry(currentUser.UserID, auditActionRoleCreate, roleID, auditRole{
+			Name:        name,
+			Description: description,
+			Permissions: permissionNames,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := h.repo.CreateAuditLogEntry(ctx, entry); err != nil {
+			return nil, errors.WrapIf(err, "failed to audit role creation")
+		}
+
+		role, err := h.repo.GetRole(ctx, roleID)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to fetch created role")
+		}
+
+		return &RoleResponse{
+			Role: *role,
+		}, nil
+	})
+}
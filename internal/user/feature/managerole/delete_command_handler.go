@@ -0,0 +1,126 @@
+package managerole
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+type DeleteCommandHandler struct {
+	repo         Repository
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+	uowFactory   contract.UnitOfWorkFactory
+	revoker      contract.RoomAccessRevoker
+	l            logger.Logger
+}
+
+func NewDeleteCommandHandler(
+	repo Repository,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+	uowFactory contract.UnitOfWorkFactory,
+	revoker contract.RoomAccessRevoker,
+	l logger.Logger,
+) *DeleteCommandHandler {
+	return &DeleteCommandHandler{
+		repo:         repo,
+		validator:    validator,
+		authProvider: authProvider,
+		uowFactory:   uowFactory,
+		revoker:      revoker,
+		l:            l,
+	}
+}
+
+// Handle deletes a role that is no longer assigned to any user. The last
+// super admin role can never be deleted.
+func (h *DeleteCommandHandler) Handle(ctx context.Context, command *DeleteCommand) error {
+	if command == nil {
+		return errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(command); err != nil {
+		return errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	can, err := h.authProvider.Can(ctx, constant.PermissionRoleManageAny)
+	if err != nil {
+		return errors.WrapIf(err, "failed to check permission for deleting role")
+	}
+
+	if !can {
+		return customerror.NewNoPermissionError(constant.PermissionRoleManageAny)
+	}
+
+	currentUser, err := h.authProvider.MustGetUser(ctx)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get current user")
+	}
+
+	var userIDs []uuid.UUID
+
+	uow := h.uowFactory.New()
+	err = uowhelper.Do(ctx, uow, h.l, func(ctx context.Context) error {
+		role, err := h.repo.GetRole(ctx, command.RoleID)
+		if err != nil {
+			return errors.WrapIf(err, "failed to fetch role")
+		}
+
+		if role.IsSuperAdmin {
+			count, err := h.repo.CountSuperAdminRoles(ctx)
+			if err != nil {
+				return errors.WrapIf(err, "failed to count super admin roles")
+			}
+
+			if count <= 1 {
+				return errors.WithStack(ErrCannotDeleteLastSuperAdmin)
+			}
+		}
+
+		if role.UserCount > 0 {
+			return errors.WithStack(ErrRoleInUse)
+		}
+
+		userIDs, err = h.repo.GetRoleUserIDs(ctx, command.RoleID)
+		if err != nil {
+			return errors.WrapIf(err, "failed to get role users")
+		}
+
+		if err := h.repo.DeleteRole(ctx, command.RoleID); err != nil {
+			return errors.WrapIf(err, "failed to delete role")
+		}
+
+		entry, err := newAuditLogEntry(currentUser.UserID, auditActionRoleDelete, command.RoleID, auditRole{
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: role.Permissions,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := h.repo.CreateAuditLogEntry(ctx, entry); err != nil {
+			return errors.WrapIf(err, "failed to audit role deletion")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		h.revoker.RecheckUserRooms(userID)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package managerole
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/contracttest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger/defaultlogger"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+func TestDelete(t *testing.T) {
+	errCommit := errors.New("commit failed")
+	userIDs := []uuid.UUID{uuid.New()}
+
+	tests := []struct {
+		name            string
+		permissions     []string
+		role            ResponseRole
+		superAdminCount int64
+		commitErr       error
+		wantErr         error
+		wantDeleted     bool
+		wantUsers       []uuid.UUID
+	}{
+		{
+			name:        "deleted",
+			permissions: []string{constant.PermissionRoleManageAny},
+			role:        ResponseRole{Name: "unused"},
+			wantDeleted: true,
+			wantUsers:   userIDs,
+		},
+		{name: "no permission", role: ResponseRole{Name: "unused"}, wantErr: customerror.ErrBaseNoPermission},
+		{
+			name:        "in use",
+			permissions: []string{constant.PermissionRoleManageAny},
+			role:        ResponseRole{Name: "setter", UserCount: 1},
+			wantErr:     ErrRoleInUse,
+		},
+		{
+			name:            "last super admin",
+			permissions:     []string{constant.PermissionRoleManageAny},
+			role:            ResponseRole{Name: "super_admin", IsSuperAdmin: true},
+			superAdminCount: 1,
+			wantErr:         ErrCannotDeleteLastSuperAdmin,
+		},
+		{
+			name:            "another super admin left",
+			permissions:     []string{constant.PermissionRoleManageAny},
+			role:            ResponseRole{Name: "root", IsSuperAdmin: true},
+			superAdminCount: 2,
+			wantDeleted:     true,
+			wantUsers:       userIDs,
+		},
+		{
+			name:        "commit failed",
+			permissions: []string{constant.PermissionRoleManageAny},
+			role:        ResponseRole{Name: "unused"},
+			commitErr:   errCommit,
+			wantErr:     errCommit,
+			wantDeleted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role := tt.role
+			role.RoleID = uuid.New()
+			repo := &fakeRepository{role: role, superAdminCount: tt.superAdminCount, userIDs: userIDs}
+			revoker := &fakeRevoker{}
+
+			handler := NewDeleteCommandHandler(
+				repo,
+				validator.New(),
+				contracttest.NewAuthProvider(uuid.New(), &contract.AuthUserDetails{Permissions: tt.permissions}),
+				&fakeUnitOfWork{commitErr: tt.commitErr},
+				revoker,
+				defaultlogger.GetLogger(),
+			)
+
+			err := handler.Handle(context.Background(), &DeleteCommand{RoleID: role.RoleID})
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			} else if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Handle() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if repo.deleted != tt.wantDeleted {
+				t.Errorf("deleted = %v, want %v", repo.deleted, tt.wantDeleted)
+			}
+
+			if tt.wantDeleted && repo.auditAction != auditActionRoleDelete {
+				t.Errorf("audit action = %q, want %q", repo.auditAction, auditActionRoleDelete)
+			}
+
+			if !slices.Equal(revoker.users, tt.wantUsers) {
+				t.Errorf("rechecked users = %v, want %v", revoker.users, tt.wantUsers)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package managerole
+
+import (
+	"net/http"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/http/httperror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/user"
+
+	"emperror.dev/errors"
+	"github.com/labstack/echo/v4"
+)
+
+type Endpoint struct {
+	*user.EndpointParams
+	listHandler            *ListQueryHandler
+	getHandler             *GetQueryHandler
+	createHandler          *CreateCommandHandler
+	updateHandler          *UpdateCommandHandler
+	deleteHandler          *DeleteCommandHandler
+	listPermissionsHandler *ListPermissionsQueryHandler
+}
+
+func NewEndpoint(
+	params *user.EndpointParams,
+	listHandler *ListQueryHandler,
+	getHandler *GetQueryHandler,
+	createHandler *CreateCommandHandler,
+	updateHandler *UpdateCommandHandler,
+	deleteHandler *DeleteCommandHandler,
+	listPermissionsHandler *ListPermissionsQueryHandler,
+) *Endpoint {
+	return &Endpoint{
+		EndpointParams:         params,
+		listHandler:            listHandler,
+		getHandler:             getHandler,
+		createHandler:          createHandler,
+		updateHandler:          updateHandler,
+		deleteHandler:          deleteHandler,
+		listPermissionsHandler: listPermissionsHandler,
+	}
+}
+
+func (e *Endpoint) MapEndpoint() {
+	e.RolesGroup.GET("", e.handleList())
+	e.RolesGroup.POST("", e.handleCreate())
+	e.RolesGroup.GET("/:role_id", e.handleGet())
+	e.RolesGroup.PUT("/:role_id", e.handleUpdate())
+	e.RolesGroup.DELETE("/:role_id", e.handleDelete())
+	e.PermissionsGroup.GET("", e.handleListPermissions())
+}
+
+func (e *Endpoint) handleList() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		response, err := e.listHandler.Handle(ctx.Request().Context())
+		if err != nil {
+			if errors.Is(err, customerror.ErrBaseNoPermission) {
+				return err
+			}
+			return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
+		}
+
+		return ctx.JSON(http.StatusOK, response)
+	}
+}
+
+func (e *Endpoint) handleGet() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		query := &GetQuery{}
+		if err := ctx.Bind(query); err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid request")
+		}
+
+		if err := ctx.Validate(query); err != nil {
+			return err
+		}
+
+		response, err := e.getHandler.Handle(ctx.Request().Context(), query)
+		if err != nil {
+			if errors.Is(err, customerror.ErrBaseNoPermission) ||
+				errors.Is(err, customerror.ErrCommandNil) ||
+				errors.Is(err, customerror.ErrValidationFailed) {
+				return err
+			}
+
+			switch {
+			case errors.Is(err, ErrRoleNotFound):
+				return httperror.New(http.StatusNotFound, "Role not found").WithInternal(err)
+			default:
+				return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
+			}
+		}
+
+		return ctx.JSON(http.StatusOK, response)
+	}
+}
+
+func (e *Endpoint) handleCreate() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		command := &CreateCommand{}
+		if err := ctx.Bind(command); err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid request body")
+		}
+
+		if err := ctx.Validate(command); err != nil {
+			return err
+		}
+
+		response, err := e.createHandler.Handle(ctx.Request().Context(), command)
+		if err != nil {
+			if errors.Is(err, customerror.ErrBaseNoPermission) ||
+				errors.Is(err, customerror.ErrCommandNil) ||
+				errors.Is(err, customerror.ErrValidationFailed) {
+				return err
+			}
+
+			switch {
+			case errors.Is(err, ErrRoleNameAlreadyExists):
+				return httperror.New(http.StatusConflict, "Role name already exists").WithInternal(err)
+			case errors.Is(err, ErrPermissionNotFound):
+				return httperror.New(http.StatusUnprocessableEntity, err.Error()).WithInternal(err)
+			default:
+				return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
+			}
+		}
+
+		return ctx.JSON(http.StatusCreated, response)
+	}
+}
+
+func (e *Endpoint) handleUpdate() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		command := &UpdateCommand{}
+		if err := ctx.Bind(command); err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid request body")
+		}
+
+		if err := ctx.Validate(command); err != nil {
+			return err
+		}
+
+		response, err := e.updateHandler.Handle(ctx.Request().Context(), command)
+		if err != nil {
+			if errors.Is(err, customerror.ErrBaseNoPermission) ||
+				errors.Is(err, customerror.ErrCommandNil) ||
+				errors.Is(err, customerror.ErrValidationFailed) {
+				return err
+			}
+
+			switch {
+			case errors.Is(err, ErrRoleNotFound):
+				return httperror.New(http.StatusNotFound, "Role not found").WithInternal(err)
+			case errors.Is(err, ErrRoleNameAlreadyExists):
+				return httperror.New(http.StatusConflict, "Role name already exists").WithInternal(err)
+			case errors.Is(err, ErrPermissionNotFound):
+				return httperror.New(http.StatusUnprocessableEntity, err.Error()).WithInternal(err)
+			case errors.Is(err, ErrCannotRenameSuperAdmin):
+				return httperror.New(http.StatusUnprocessableEntity, "Super admin roles cannot be renamed").WithInternal(err)
+			default:
+				return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
+			}
+		}
+
+		return ctx.JSON(http.StatusOK, response)
+	}
+}
+
+func (e *Endpoint) handleDelete() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		command := &DeleteCommand{}
+		if err := ctx.Bind(command); err != nil {
+			return httperror.New(http.StatusBadRequest, "Invalid request")
+		}
+
+		if err := ctx.Validate(command); err != nil {
+			return err
+		}
+
+		if err := e.deleteHandler.Handle(ctx.Request().Context(), command); err != nil {
+			if errors.Is(err, customerror.ErrBaseNoPermission) ||
+				errors.Is(err, customerror.ErrCommandNil) ||
+				errors.Is(err, customerror.ErrValidationFailed) {
+				return err
+			}
+
+			switch {
+			case errors.Is(err, ErrRoleNotFound):
+				return httperror.New(http.StatusNotFound, "Role not found").WithInternal(err)
+			case errors.Is(err, ErrRoleInUse):
+				return httperror.New(http.StatusConflict, "Role is still assigned to users").WithInternal(err)
+			case errors.Is(err, ErrCannotDeleteLastSuperAdmin):
+				return httperror.New(http.StatusUnprocessableEntity, "System must retain at least one super admin role").WithInternal(err)
+			default:
+				return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
+			}
+		}
+
+		return ctx.NoContent(http.StatusNoContent)
+	}
+}
+
+func (e *Endpoint) handleListPermissions() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		response, err := e.listPermissionsHandler.Handle(ctx.Request().Context())
+		if err != nil {
+			if errors.Is(err, customerror.ErrBaseNoPermission) {
+				return err
+			}
+			return httperror.New(http.StatusInternalServerError, err.Error()).WithInternal(err)
+		}
+
+		return ctx.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,12 @@
+package managerole
+
+import "emperror.dev/errors"
+
+var (
+	ErrRoleNotFound               = errors.New("role not found")
+	ErrPermissionNotFound         = errors.New("permission not found")
+	ErrRoleNameAlreadyExists      = errors.New("role name already exists")
+	ErrRoleInUse                  = errors.New("role is still assigned to users")
+	ErrCannotRenameSuperAdmin     = errors.New("cannot rename a super admin role")
+	ErrCannotDeleteLastSuperAdmin = errors.New("cannot delete the last super admin role")
+)
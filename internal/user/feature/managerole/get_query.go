@@ -0,0 +1,7 @@
+package managerole
+
+import "github.com/google/uuid"
+
+type GetQuery struct {
+	RoleID uuid.UUID `param:"role_id" validate:"required"`
+}
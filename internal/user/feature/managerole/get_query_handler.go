@@ -0,0 +1,58 @@
+package managerole
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+)
+
+type GetQueryHandler struct {
+	repo         Repository
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+}
+
+func NewGetQueryHandler(
+	repo Repository,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+) *GetQueryHandler {
+	return &GetQueryHandler{
+		repo:         repo,
+		validator:    validator,
+		authProvider: authProvider,
+	}
+}
+
+func (h *GetQueryHandler) Handle(ctx context.Context, query *GetQuery) (*RoleResponse, error) {
+	if query == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(query); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	can, err := h.authProvider.Can(ctx, constant.PermissionRoleListAll)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to check permission for reading role")
+	}
+
+	if !can {
+		return nil, customerror.NewNoPermissionError(constant.PermissionRoleListAll)
+	}
+
+	role, err := h.repo.GetRole(ctx, query.RoleID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to fetch role")
+	}
+
+	return &RoleResponse{
+		Role: *role,
+	}, nil
+}
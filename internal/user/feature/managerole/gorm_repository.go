@@ -0,0 +1,257 @@
+package managerole
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) ListRoles(ctx context.Context) ([]ResponseRole, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var roles []database.Role
+	if err := db.WithContext(ctx).
+		Preload("Permissions").
+		Order("created_at ASC").
+		Find(&roles).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to list roles")
+	}
+
+	userCounts, err := r.countUsers(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]ResponseRole, len(roles))
+	for i, role := range roles {
+		responses[i] = toResponseRole(role, userCounts[role.RoleID])
+	}
+
+	return responses, nil
+}
+
+func (r *GormRepository) GetRole(ctx context.Context, roleID uuid.UUID) (*ResponseRole, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var role database.Role
+	if err := db.WithContext(ctx).
+		Preload("Permissions").
+		Where("role_id = ?", roleID).
+		First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithStack(ErrRoleNotFound)
+		}
+
+		return nil, errors.WrapIf(err, "failed to get role")
+	}
+
+	var userCount int64
+	if err := db.WithContext(ctx).
+		Table("user_roles").
+		Where("role_role_id = ?", roleID).
+		Count(&userCount).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to count role users")
+	}
+
+	response := toResponseRole(role, userCount)
+	return &response, nil
+}
+
+func (r *GormRepository) ExistsRoleName(ctx context.Context, name string, excludeRoleID uuid.UUID) (bool, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var count int64
+	if err := db.WithContext(ctx).
+		Model(&database.Role{}).
+		Where("LOWER(name) = LOWER(?) AND role_id <> ?", name, excludeRoleID).
+		Count(&count).Error; err != nil {
+		return false, errors.WrapIf(err, "failed to check duplicate role name")
+	}
+
+	return count > 0, nil
+}
+
+func (r *GormRepository) GetPermissionsByNames(ctx context.Context, names []string) ([]database.Permission, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	if len(names) == 0 {
+		return []database.Permission{}, nil
+	}
+
+	var permissions []database.Permission
+	if err := db.WithContext(ctx).
+		Where("name IN ?", names).
+		Find(&permissions).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to fetch permissions")
+	}
+
+	found := make(map[string]struct{}, len(permissions))
+	for _, permission := range permissions {
+		found[permission.Name] = struct{}{}
+	}
+
+	missing := make([]string, 0)
+	for _, name := range names {
+		if _, ok := found[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, errors.Wrapf(ErrPermissionNotFound, "unknown permissions: %s", strings.Join(missing, ", "))
+	}
+
+	return permissions, nil
+}
+
+func (r *GormRepository) CreateRole(ctx context.Context, role database.Role) error {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	if err := db.WithContext(ctx).Create(&role).Error; err != nil {
+		return errors.WrapIf(err, "failed to create role")
+	}
+
+	return nil
+}
+
+func (r *GormRepository) UpdateRole(
+	ctx context.Context,
+	roleID uuid.UUID,
+	name string,
+	description string,
+	permissions []database.Permission,
+) error {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	role := database.Role{RoleID: roleID, Permissions: &[]database.Permission{}}
+	if err := db.WithContext(ctx).
+		Model(&role).
+		Updates(map[string]any{
+			"name":        name,
+			"description": description,
+		}).Error; err != nil {
+		return errors.WrapIf(err, "failed to update role")
+	}
+
+	if err := db.WithContext(ctx).
+		Model(&role).
+		Association("Permissions").
+		Replace(permissions); err != nil {
+		return errors.WrapIf(err, "failed to replace role permissions")
+	}
+
+	return nil
+}
+
+func (r *GormRepository) DeleteRole(ctx context.Context, roleID uuid.UUID) error {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	role := database.Role{RoleID: roleID}
+	if err := db.WithContext(ctx).
+		Model(&role).
+		Association("Permissions").
+		Clear(); err != nil {
+		return errors.WrapIf(err, "failed to clear role permissions")
+	}
+
+	if err := db.WithContext(ctx).Delete(&role).Error; err != nil {
+		return errors.WrapIf(err, "failed to delete role")
+	}
+
+	return nil
+}
+
+func (r *GormRepository) CountSuperAdminRoles(ctx context.Context) (int64, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var count int64
+	if err := db.WithContext(ctx).
+		Model(&database.Role{}).
+		Where("is_super_admin = ?", true).
+		Count(&count).Error; err != nil {
+		return 0, errors.WrapIf(err, "failed to count super admin roles")
+	}
+
+	return count, nil
+}
+
+func (r *GormRepository) GetRoleUserIDs(ctx context.Context, roleID uuid.UUID) ([]uuid.UUID, error) {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	var userIDs []uuid.UUID
+	if err := db.WithContext(ctx).
+		Table("user_roles").
+		Where("role_role_id = ?", roleID).
+		Pluck("user_user_id", &userIDs).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to get role users")
+	}
+
+	return userIDs, nil
+}
+
+func (r *GormRepository) CreateAuditLogEntry(ctx context.Context, entry database.AuditLogEntry) error {
+	db := database.GetDBFromContext(ctx, r.db)
+
+	if err := db.WithContext(ctx).Create(&entry).Error; err != nil {
+		return errors.WrapIf(err, "failed to create audit log entry")
+	}
+
+	return nil
+}
+
+func (r *GormRepository) countUsers(ctx context.Context, db *gorm.DB) (map[uuid.UUID]int64, error) {
+	var rows []struct {
+		RoleRoleID uuid.UUID
+		UserCount  int64
+	}
+
+	if err := db.WithContext(ctx).
+		Table("user_roles").
+		Select("role_role_id, COUNT(*) AS user_count").
+		Group("role_role_id").
+		Scan(&rows).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to count role users")
+	}
+
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.RoleRoleID] = row.UserCount
+	}
+
+	return counts, nil
+}
+
+func toResponseRole(role database.Role, userCount int64) ResponseRole {
+	permissions := make([]string, 0)
+	if role.Permissions != nil {
+		for _, permission := range *role.Permissions {
+			permissions = append(permissions, permission.Name)
+		}
+	}
+
+	sort.Strings(permissions)
+
+	return ResponseRole{
+		RoleID:       role.RoleID,
+		Name:         role.Name,
+		Description:  role.Description,
+		IsSuperAdmin: role.IsSuperAdmin,
+		Permissions:  permissions,
+		UserCount:    userCount,
+		CreatedAt:    role.CreatedAt,
+	}
+}
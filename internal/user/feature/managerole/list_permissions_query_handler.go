@@ -0,0 +1,44 @@
+package managerole
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+
+	"emperror.dev/errors"
+)
+
+type ListPermissionsQueryHandler struct {
+	authProvider contract.AuthProvider
+}
+
+func NewListPermissionsQueryHandler(authProvider contract.AuthProvider) *ListPermissionsQueryHandler {
+	return &ListPermissionsQueryHandler{
+		authProvider: authProvider,
+	}
+}
+
+func (h *ListPermissionsQueryHandler) Handle(ctx context.Context) (*ListPermissionsResponse, error) {
+	can, err := h.authProvider.Can(ctx, constant.PermissionRoleListAll)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to check permission for listing permissions")
+	}
+
+	if !can {
+		return nil, customerror.NewNoPermissionError(constant.PermissionRoleListAll)
+	}
+
+	permissions := make([]ResponsePermission, len(constant.Permissions))
+	for i, p := range constant.Permissions {
+		permissions[i] = ResponsePermission{
+			Name:        p.Name,
+			Description: p.Description,
+		}
+	}
+
+	return &ListPermissionsResponse{
+		Permissions: permissions,
+	}, nil
+}
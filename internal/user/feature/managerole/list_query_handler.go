@@ -0,0 +1,43 @@
+package managerole
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+
+	"emperror.dev/errors"
+)
+
+type ListQueryHandler struct {
+	repo         Repository
+	authProvider contract.AuthProvider
+}
+
+func NewListQueryHandler(repo Repository, authProvider contract.AuthProvider) *ListQueryHandler {
+	return &ListQueryHandler{
+		repo:         repo,
+		authProvider: authProvider,
+	}
+}
+
+func (h *ListQueryHandler) Handle(ctx context.Context) (*ListResponse, error) {
+	can, err := h.authProvider.Can(ctx, constant.PermissionRoleListAll)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to check permission for listing roles")
+	}
+
+	if !can {
+		return nil, customerror.NewNoPermissionError(constant.PermissionRoleListAll)
+	}
+
+	roles, err := h.repo.ListRoles(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to fetch roles")
+	}
+
+	return &ListResponse{
+		Roles: roles,
+	}, nil
+}
@@ -0,0 +1,28 @@
+package managerole
+
+import (
+	"context"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	ListRoles(ctx context.Context) ([]ResponseRole, error)
+	GetRole(ctx context.Context, roleID uuid.UUID) (*ResponseRole, error)
+	ExistsRoleName(ctx context.Context, name string, excludeRoleID uuid.UUID) (bool, error)
+	GetPermissionsByNames(ctx context.Context, names []string) ([]database.Permission, error)
+	CreateRole(ctx context.Context, role database.Role) error
+	UpdateRole(
+		ctx context.Context,
+		roleID uuid.UUID,
+		name string,
+		description string,
+		permissions []database.Permission,
+	) error
+	DeleteRole(ctx context.Context, roleID uuid.UUID) error
+	CountSuperAdminRoles(ctx context.Context) (int64, error)
+	GetRoleUserIDs(ctx context.Context, roleID uuid.UUID) ([]uuid.UUID, error)
+	CreateAuditLogEntry(ctx context.Context, entry database.AuditLogEntry) error
+}
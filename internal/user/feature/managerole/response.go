@@ -0,0 +1,34 @@
+package managerole
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ResponseRole struct {
+	RoleID       uuid.UUID `json:"role_id"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	IsSuperAdmin bool      `json:"is_super_admin"`
+	Permissions  []string  `json:"permissions"`
+	UserCount    int64     `json:"user_count"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type ResponsePermission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ListResponse struct {
+	Roles []ResponseRole `json:"roles"`
+}
+
+type RoleResponse struct {
+	Role ResponseRole `json:"role"`
+}
+
+type ListPermissionsResponse struct {
+	Permissions []ResponsePermission `json:"permissions"`
+}
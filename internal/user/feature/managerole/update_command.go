@@ -0,0 +1,10 @@
+package managerole
+
+import "github.com/google/uuid"
+
+type UpdateCommand struct {
+	RoleID      uuid.UUID `param:"role_id" validate:"required"`
+	Name        string    `json:"name" validate:"required,max=64"`
+	Description string    `json:"description" validate:"max=255"`
+	Permissions []string  `json:"permissions" validate:"dive,required"`
+}
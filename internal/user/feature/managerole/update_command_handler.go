@@ -0,0 +1,151 @@
+package managerole
+
+import (
+	"context"
+	"strings"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/uowhelper"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+type UpdateCommandHandler struct {
+	repo         Repository
+	validator    *validator.Validate
+	authProvider contract.AuthProvider
+	uowFactory   contract.UnitOfWorkFactory
+	revoker      contract.RoomAccessRevoker
+	l            logger.Logger
+}
+
+func NewUpdateCommandHandler(
+	repo Repository,
+	validator *validator.Validate,
+	authProvider contract.AuthProvider,
+	uowFactory contract.UnitOfWorkFactory,
+	revoker contract.RoomAccessRevoker,
+	l logger.Logger,
+) *UpdateCommandHandler {
+	return &UpdateCommandHandler{
+		repo:         repo,
+		validator:    validator,
+		authProvider: authProvider,
+		uowFactory:   uowFactory,
+		revoker:      revoker,
+		l:            l,
+	}
+}
+
+// Handle replaces the name, description and permissions of a role. Super
+// admin roles are granted every permission regardless of the ones assigned
+// to them, and cannot be renamed since they are looked up by name.
+func (h *UpdateCommandHandler) Handle(ctx context.Context, command *UpdateCommand) (*RoleResponse, error) {
+	if command == nil {
+		return nil, errors.WithStack(customerror.ErrCommandNil)
+	}
+
+	if err := h.validator.Struct(command); err != nil {
+		return nil, errors.WithStack(errors.Append(err, customerror.ErrValidationFailed))
+	}
+
+	can, err := h.authProvider.Can(ctx, constant.PermissionRoleManageAny)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to check permission for updating role")
+	}
+
+	if !can {
+		return nil, customerror.NewNoPermissionError(constant.PermissionRoleManageAny)
+	}
+
+	currentUser, err := h.authProvider.MustGetUser(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get current user")
+	}
+
+	name := strings.TrimSpace(command.Name)
+	description := strings.TrimSpace(command.Description)
+	permissionNames := normalizePermissions(command.Permissions)
+
+	var userIDs []uuid.UUID
+
+	uow := h.uowFactory.New()
+	response, err := uowhelper.DoWithResult(ctx, uow, h.l, func(ctx context.Context) (*RoleResponse, error) {
+		before, err := h.repo.GetRole(ctx, command.RoleID)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to fetch role")
+		}
+
+		if before.IsSuperAdmin && name != before.Name {
+			return nil, errors.WithStack(ErrCannotRenameSuperAdmin)
+		}
+
+		exists, err := h.repo.ExistsRoleName(ctx, name, command.RoleID)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to check role name")
+		}
+
+		if exists {
+			return nil, errors.WithStack(ErrRoleNameAlreadyExists)
+		}
+
+		permissions, err := h.repo.GetPermissionsByNames(ctx, permissionNames)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := h.repo.UpdateRole(ctx, command.RoleID, name, description, permissions); err != nil {
+			return nil, errors.WrapIf(err, "failed to update role")
+		}
+
+		userIDs, err = h.repo.GetRoleUserIDs(ctx, command.RoleID)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to get role users")
+		}
+
+		entry, err := newAuditLogEntry(currentUser.UserID, auditActionRoleUpdate, command.RoleID, auditRoleUpdate{
+			Before: auditRole{
+				Name:        before.Name,
+				Description: before.Description,
+				Permissions: before.Permissions,
+			},
+			After: auditRole{
+				Name:        name,
+				Description: description,
+				Permissions: permissionNames,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := h.repo.CreateAuditLogEntry(ctx, entry); err != nil {
+			return nil, errors.WrapIf(err, "failed to audit role update")
+		}
+
+		role, err := h.repo.GetRole(ctx, command.RoleID)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to fetch updated role")
+		}
+
+		return &RoleResponse{
+			Role: *role,
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Fewer permissions may no longer let the users read the problems they
+	// follow.
+	for _, userID := range userIDs {
+		h.revoker.RecheckUserRooms(userID)
+	}
+
+	return response, nil
+}
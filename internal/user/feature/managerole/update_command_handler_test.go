@@ -0,0 +1,204 @@
+package managerole
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/constant"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/contract/contracttest"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/customerror"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/database"
+	"github.com/THUSAAC-PSD/algorithmia-backend/internal/pkg/logger/defaultlogger"
+
+	"emperror.dev/errors"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+type fakeRepository struct {
+	role            ResponseRole
+	superAdminCount int64
+	userIDs         []uuid.UUID
+	nameExists      bool
+	updatedName     string
+	deleted         bool
+	auditAction     string
+}
+
+func (r *fakeRepository) ListRoles(context.Context) ([]ResponseRole, error) {
+	return []ResponseRole{r.role}, nil
+}
+
+func (r *fakeRepository) GetRole(context.Context, uuid.UUID) (*ResponseRole, error) {
+	role := r.role
+	return &role, nil
+}
+
+func (r *fakeRepository) ExistsRoleName(context.Context, string, uuid.UUID) (bool, error) {
+	return r.nameExists, nil
+}
+
+func (r *fakeRepository) GetPermissionsByNames(_ context.Context, names []string) ([]database.Permission, error) {
+	permissions := make([]database.Permission, 0, len(names))
+	for _, name := range names {
+		permissions = append(permissions, database.Permission{Name: name})
+	}
+
+	return permissions, nil
+}
+
+func (r *fakeRepository) CreateRole(context.Context, database.Role) error {
+	return nil
+}
+
+func (r *fakeRepository) UpdateRole(_ context.Context, _ uuid.UUID, name string, _ string, _ []database.Permission) error {
+	r.updatedName = name
+	return nil
+}
+
+func (r *fakeRepository) DeleteRole(context.Context, uuid.UUID) error {
+	r.deleted = true
+	return nil
+}
+
+func (r *fakeRepository) CountSuperAdminRoles(context.Context) (int64, error) {
+	return r.superAdminCount, nil
+}
+
+func (r *fakeRepository) GetRoleUserIDs(context.Context, uuid.UUID) ([]uuid.UUID, error) {
+	return r.userIDs, nil
+}
+
+func (r *fakeRepository) CreateAuditLogEntry(_ context.Context, entry database.AuditLogEntry) error {
+	r.auditAction = entry.Action
+	return nil
+}
+
+type fakeUnitOfWork struct {
+	commitErr error
+}
+
+func (u *fakeUnitOfWork) Begin(ctx context.Context) (context.Context, error) { return ctx, nil }
+
+func (u *fakeUnitOfWork) Commit() error { return u.commitErr }
+
+func (u *fakeUnitOfWork) Rollback() error { return nil }
+
+func (u *fakeUnitOfWork) New() contract.UnitOfWork { return u }
+
+type fakeRevoker struct {
+	users []uuid.UUID
+}
+
+func (r *fakeRevoker) RecheckProblemRoom(uuid.UUID) {}
+
+func (r *fakeRevoker) RecheckUserRooms(userID uuid.UUID) {
+	r.users = append(r.users, userID)
+}
+
+func TestUpdate(t *testing.T) {
+	errCommit := errors.New("commit failed")
+	userIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	tests := []struct {
+		name        string
+		permissions []string
+		role        ResponseRole
+		newName     string
+		nameExists  bool
+		commitErr   error
+		wantErr     error
+		wantAudited bool
+		wantUsers   []uuid.UUID
+	}{
+		{
+			name:        "updated",
+			permissions: []string{constant.PermissionRoleManageAny},
+			role:        ResponseRole{Name: "reviewer"},
+			newName:     " senior reviewer ",
+			wantAudited: true,
+			wantUsers:   userIDs,
+		},
+		{name: "no permission", role: ResponseRole{Name: "reviewer"}, newName: "reviewer", wantErr: customerror.ErrBaseNoPermission},
+		{
+			name:        "name taken",
+			permissions: []string{constant.PermissionRoleManageAny},
+			role:        ResponseRole{Name: "reviewer"},
+			newName:     "tester",
+			nameExists:  true,
+			wantErr:     ErrRoleNameAlreadyExists,
+		},
+		{
+			name:        "super admin renamed",
+			permissions: []string{constant.PermissionRoleManageAny},
+			role:        ResponseRole{Name: "super_admin", IsSuperAdmin: true},
+			newName:     "admin",
+			wantErr:     ErrCannotRenameSuperAdmin,
+		},
+		{
+			name:        "super admin described",
+			permissions: []string{constant.PermissionRoleManageAny},
+			role:        ResponseRole{Name: "super_admin", IsSuperAdmin: true},
+			newName:     "super_admin",
+			wantAudited: true,
+			wantUsers:   userIDs,
+		},
+		{
+			name:        "commit failed",
+			permissions: []string{constant.PermissionRoleManageAny},
+			role:        ResponseRole{Name: "reviewer"},
+			newName:     "reviewer",
+			commitErr:   errCommit,
+			wantErr:     errCommit,
+			wantAudited: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role := tt.role
+			role.RoleID = uuid.New()
+			repo := &fakeRepository{role: role, nameExists: tt.nameExists, userIDs: userIDs}
+			revoker := &fakeRevoker{}
+
+			handler := NewUpdateCommandHandler(
+				repo,
+				validator.New(),
+				contracttest.NewAuthProvider(uuid.New(), &contract.AuthUserDetails{Permissions: tt.permissions}),
+				&fakeUnitOfWork{commitErr: tt.commitErr},
+				revoker,
+				defaultlogger.GetLogger(),
+			)
+
+			_, err := handler.Handle(context.Background(), &UpdateCommand{
+				RoleID:      role.RoleID,
+				Name:        tt.newName,
+				Permissions: []string{constant.PermissionProblemReviewAny},
+			})
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			} else if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Handle() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantAudited {
+				if want := strings.TrimSpace(tt.newName); repo.updatedName != want {
+					t.Errorf("updated name = %q, want %q", repo.updatedName, want)
+				}
+
+				if repo.auditAction != auditActionRoleUpdate {
+					t.Errorf("audit action = %q, want %q", repo.auditAction, auditActionRoleUpdate)
+				}
+			} else if repo.auditAction != "" {
+				t.Errorf("audit action = %q, want none", repo.auditAction)
+			}
+
+			if !slices.Equal(revoker.users, tt.wantUsers) {
+				t.Errorf("rechecked users = %v, want %v", revoker.users, tt.wantUsers)
+			}
+		})
+	}
+}
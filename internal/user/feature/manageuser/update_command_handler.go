@@ -18,17 +18,20 @@ type UpdateCommandHandler struct {
 	repo         Repository
 	validator    *validator.Validate
 	authProvider contract.AuthProvider
+	revoker      contract.RoomAccessRevoker
 }
 
 func NewUpdateCommandHandler(
 	repo Repository,
 	validator *validator.Validate,
 	authProvider contract.AuthProvider,
+	revoker contract.RoomAccessRevoker,
 ) *UpdateCommandHandler {
 	return &UpdateCommandHandler{
 		repo:         repo,
 		validator:    validator,
 		authProvider: authProvider,
+		revoker:      revoker,
 	}
 }
 
@@ -118,6 +121,9 @@ func (h *UpdateCommandHandler) Handle(
 		return nil, errors.WrapIf(err, "failed to update user")
 	}
 
+	// Fewer roles may no longer let the user read the problems they follow.
+	h.revoker.RecheckUserRooms(command.UserID)
+
 	return &UpdateResponse{User: *updatedUser}, nil
 }
 